@@ -11,15 +11,20 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/invowk/invowk/internal/app/commandadapters"
 	"github.com/invowk/invowk/internal/app/commandsvc"
 	"github.com/invowk/invowk/internal/app/deps"
+	appexec "github.com/invowk/invowk/internal/app/execute"
 	"github.com/invowk/invowk/internal/config"
 	"github.com/invowk/invowk/internal/discovery"
 	"github.com/invowk/invowk/internal/issue"
+	"github.com/invowk/invowk/internal/tui"
+	"github.com/invowk/invowk/internal/tuiclient"
 	"github.com/invowk/invowk/pkg/invowkfile"
 	"github.com/invowk/invowk/pkg/types"
+	"golang.org/x/term"
 )
 
 const serviceErrorLabel = "Error:"
@@ -36,6 +41,7 @@ type (
 		Commands    CommandService
 		Watchers    WatchRunnerCreator
 		Diagnostics DiagnosticRenderer
+		Prompts     InputPrompter
 		stdout      io.Writer
 		stderr      io.Writer
 	}
@@ -49,6 +55,7 @@ type (
 		Commands    CommandService
 		Watchers    WatchRunnerCreator
 		Diagnostics DiagnosticRenderer
+		Prompts     InputPrompter
 		Stdout      io.Writer
 		Stderr      io.Writer
 	}
@@ -58,6 +65,14 @@ type (
 	// semantics so the data contract has one source of truth.
 	ExecuteRequest = commandsvc.Request
 
+	// CompletionRequest is the CLI-facing alias for the command service
+	// completion request of a flag or argument.
+	CompletionRequest = commandsvc.CompletionRequest
+
+	// CompletionCandidate is one shell completion candidate computed by a
+	// completion script.
+	CompletionCandidate = appexec.CompletionCandidate
+
 	//goplint:validate-all
 	//
 	// ExecuteResult contains command execution outcomes.
@@ -73,6 +88,7 @@ type (
 		ResolveCommand(ctx context.Context, req ExecuteRequest) (*discovery.CommandInfo, ExecuteRequest, []discovery.Diagnostic, error)
 		ResolveWatchPlan(ctx context.Context, req ExecuteRequest) (*discovery.CommandInfo, ExecuteRequest, commandsvc.WatchPlan, []discovery.Diagnostic, error)
 		ResolveFromSource(ctx context.Context, req ExecuteRequest) (*discovery.CommandInfo, ExecuteRequest, []discovery.Diagnostic, error)
+		Complete(ctx context.Context, req CompletionRequest) ([]CompletionCandidate, error)
 	}
 
 	// DiscoveryService discovers invowk commands and diagnostics.
@@ -103,6 +119,25 @@ type (
 
 	cliExecutionObserver struct {
 		stdout io.Writer
+		stderr io.Writer
+		// spinners are the spinners shown while commands wait for their locks.
+		// Prerequisites and matrix combinations run concurrently, so several
+		// waits can be in progress at once.
+		spinnersMu sync.Mutex
+		spinners   map[lockSpinnerKey]*lockSpinner
+	}
+
+	// lockSpinnerKey identifies the lock wait a spinner is shown for.
+	lockSpinnerKey struct {
+		command invowkfile.CommandName
+		lock    invowkfile.LockName
+	}
+
+	// lockSpinner is a spinner shared by the concurrent runs waiting for the
+	// same lock of the same command; it stops when the last of them ends.
+	lockSpinner struct {
+		stop    func()
+		waiters int
 	}
 )
 
@@ -140,6 +175,21 @@ func NewApp(d Dependencies) (*App, error) {
 		if err != nil {
 			return nil, err
 		}
+		// Without a user cache directory, commands that declare sources always run.
+		var fingerprints commandsvc.FingerprintStore
+		if dir, dirErr := commandadapters.DefaultFingerprintDir(); dirErr == nil {
+			fingerprints = commandadapters.NewFingerprintStore(dir)
+		}
+		// Without a user cache directory, runs of commands that declare a lock may overlap.
+		var runLocker commandsvc.RunLocker
+		if dir, dirErr := commandadapters.DefaultRunLockDir(); dirErr == nil {
+			runLocker = commandadapters.NewRunLocker(dir)
+		}
+		// Without a user cache directory, completion scripts run on every completion.
+		var completions commandsvc.CompletionCache
+		if dir, dirErr := commandadapters.DefaultCompletionCacheDir(); dirErr == nil {
+			completions = commandadapters.NewCompletionCache(dir, commandadapters.CurrentCompletionSession(), commandadapters.CompletionCacheTTL)
+		}
 		svc := commandsvc.New(
 			d.Config,
 			d.Discovery,
@@ -149,12 +199,16 @@ func NewApp(d Dependencies) (*App, error) {
 				hostAccess,
 				registryFactory,
 				interactiveExecutor,
-				&cliExecutionObserver{stdout: d.Stdout},
+				&cliExecutionObserver{stdout: d.Stdout, stderr: d.Stderr},
 				requestScope.Begin,
 				commandadapters.NewDependencyCapabilityChecker(),
 				commandadapters.NewDependencyHostProbe(),
 				commandadapters.NewDependencyLockProvider(),
 				commandadapters.NewDependencyScriptFileReader(),
+				fingerprints,
+				completions,
+				terminalInputPrompter{},
+				runLocker,
 			),
 		)
 		d.Commands = &cliCommandAdapter{svc: svc, stdout: d.Stdout}
@@ -162,6 +216,9 @@ func NewApp(d Dependencies) (*App, error) {
 	if d.Watchers == nil {
 		d.Watchers = productionWatchRunnerCreator{}
 	}
+	if d.Prompts == nil {
+		d.Prompts = terminalInputPrompter{}
+	}
 
 	return &App{
 		Config:      d.Config,
@@ -169,6 +226,7 @@ func NewApp(d Dependencies) (*App, error) {
 		Commands:    d.Commands,
 		Watchers:    d.Watchers,
 		Diagnostics: d.Diagnostics,
+		Prompts:     d.Prompts,
 		stdout:      d.Stdout,
 		stderr:      d.Stderr,
 	}, nil
@@ -182,6 +240,130 @@ func (o *cliExecutionObserver) InteractiveFallback(runtimeName invowkfile.Runtim
 	fmt.Fprintf(o.stdout, "! Runtime '%s' does not support interactive mode, using standard execution\n", runtimeName)
 }
 
+func (o *cliExecutionObserver) StepStarting(event commandsvc.StepEvent) {
+	fmt.Fprintf(o.stdout, "-> Step %d/%d of '%s': %s\n", event.Index+1, event.Total, event.CommandName, event.Label)
+}
+
+func (o *cliExecutionObserver) StepFailed(event commandsvc.StepEvent) {
+	if event.Err != nil {
+		fmt.Fprintf(o.stdout, "! Step %d/%d of '%s' (%s) failed, continuing: %v\n", event.Index+1, event.Total, event.CommandName, event.Label, event.Err)
+		return
+	}
+	fmt.Fprintf(o.stdout, "! Step %d/%d of '%s' (%s) exited with code %d, continuing\n", event.Index+1, event.Total, event.CommandName, event.Label, event.ExitCode)
+}
+
+func (o *cliExecutionObserver) PrerequisiteStarting(event commandsvc.PrerequisiteEvent) {
+	fmt.Fprintf(o.stdout, "-> Prerequisite of '%s': %s\n", event.CommandName, event.Prerequisite)
+}
+
+func (o *cliExecutionObserver) CommandUpToDate(name invowkfile.CommandName) {
+	fmt.Fprintf(o.stdout, "-> '%s' is up to date, skipping (use --ivk-force to run anyway)\n", name)
+}
+
+func (o *cliExecutionObserver) HookStarting(event commandsvc.HookEvent) {
+	fmt.Fprintf(o.stdout, "-> %s hook %d/%d of '%s'\n", event.Phase, event.Index+1, event.Total, event.CommandName)
+}
+
+func (o *cliExecutionObserver) HookFailed(event commandsvc.HookEvent) {
+	if event.Err != nil {
+		fmt.Fprintf(o.stdout, "! %s hook %d/%d of '%s' failed: %v\n", event.Phase, event.Index+1, event.Total, event.CommandName, event.Err)
+		return
+	}
+	fmt.Fprintf(o.stdout, "! %s hook %d/%d of '%s' exited with code %d\n", event.Phase, event.Index+1, event.Total, event.CommandName, event.ExitCode)
+}
+
+func (o *cliExecutionObserver) AttemptStarting(event commandsvc.AttemptEvent) {
+	if event.Attempt == 1 {
+		fmt.Fprintf(o.stdout, "-> Attempt 1/%d of '%s'\n", event.Attempts, event.CommandName)
+		return
+	}
+	fmt.Fprintf(o.stdout, "! '%s' exited with code %d, retrying after %s (attempt %d/%d)\n", event.CommandName, event.ExitCode, event.Delay, event.Attempt, event.Attempts)
+}
+
+func (o *cliExecutionObserver) ImplementationSelected(event commandsvc.ImplementationEvent) {
+	fmt.Fprintf(o.stdout, "-> '%s' runs with %s: %s\n", event.CommandName, event.Runtime, event.Reason)
+}
+
+// CommandDeprecated warns about an invoked deprecated command. Verbose runs
+// also show the issue catalog entry on deprecations.
+func (o *cliExecutionObserver) CommandDeprecated(event commandsvc.DeprecationEvent) {
+	fmt.Fprint(o.stderr, RenderDeprecationWarning(event))
+	if !event.Verbose {
+		return
+	}
+	rendered, renderErr := renderIssueCatalogEntry(issue.Get(issue.CommandDeprecatedId), "dark")
+	if renderErr != nil {
+		slog.Warn("failed to render issue catalog entry", "issue_id", issue.CommandDeprecatedId, "error", renderErr)
+		return
+	}
+	fmt.Fprint(o.stderr, rendered)
+}
+
+// RunLockWaiting shows a spinner while a command waits for its lock, or a
+// progress line when stdout is not a terminal.
+func (o *cliExecutionObserver) RunLockWaiting(event commandsvc.RunLockEvent) {
+	title := fmt.Sprintf("Waiting for lock '%s' of '%s', held by %s", event.Lock, event.CommandName, event.Holder)
+	out, isFile := o.stdout.(*os.File)
+	if !isFile || !term.IsTerminal(int(out.Fd())) || tuiclient.NewClientFromEnv() != nil {
+		fmt.Fprintf(o.stdout, "-> %s...\n", title)
+		return
+	}
+
+	key := lockSpinnerKey{command: event.CommandName, lock: event.Lock}
+	o.spinnersMu.Lock()
+	defer o.spinnersMu.Unlock()
+	if spinner, ok := o.spinners[key]; ok {
+		spinner.waiters++
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := tui.NewSpin().Title(title).Context(ctx).Run(); err != nil {
+			slog.Debug("lock wait spinner failed", "error", err)
+		}
+	}()
+	if o.spinners == nil {
+		o.spinners = make(map[lockSpinnerKey]*lockSpinner)
+	}
+	o.spinners[key] = &lockSpinner{
+		stop: func() {
+			cancel()
+			<-done
+		},
+		waiters: 1,
+	}
+}
+
+// RunLockWaitEnded stops the spinner started by RunLockWaiting once no other
+// run waits for the same lock.
+func (o *cliExecutionObserver) RunLockWaitEnded(event commandsvc.RunLockEvent) {
+	key := lockSpinnerKey{command: event.CommandName, lock: event.Lock}
+	o.spinnersMu.Lock()
+	spinner, ok := o.spinners[key]
+	if ok {
+		spinner.waiters--
+		ok = spinner.waiters == 0
+		if ok {
+			delete(o.spinners, key)
+		}
+	}
+	o.spinnersMu.Unlock()
+	if ok {
+		spinner.stop()
+	}
+}
+
+func (o *cliExecutionObserver) MatrixRunStarting(event commandsvc.MatrixEvent) {
+	fmt.Fprintf(o.stdout, "-> Matrix %d/%d of '%s': %s\n", event.Index+1, event.Total, event.CommandName, event.Combination.Label())
+}
+
+func (o *cliExecutionObserver) MatrixFinished(summary commandsvc.MatrixSummary) {
+	fmt.Fprintln(o.stdout)
+	fmt.Fprint(o.stdout, RenderMatrixSummary(summary))
+}
+
 // Execute translates an ExecuteRequest into a commandsvc.Request, delegates
 // to the underlying service, and wraps raw domain errors into styled
 // ServiceErrors for CLI rendering. Dry-run results are rendered here.
@@ -219,6 +401,11 @@ func (a *cliCommandAdapter) ResolveWatchPlan(ctx context.Context, req ExecuteReq
 	return cmdInfo, resolvedReq, plan, convertCommandDiagnostics(commandDiags), err
 }
 
+// Complete delegates completion script runs to the command service.
+func (a *cliCommandAdapter) Complete(ctx context.Context, req CompletionRequest) ([]CompletionCandidate, error) {
+	return a.svc.Complete(ctx, req)
+}
+
 func (a *cliCommandAdapter) executeValidated(ctx context.Context, req ExecuteRequest) (ExecuteResult, []discovery.Diagnostic, error) {
 	result, commandDiags, err := a.svc.Execute(ctx, req)
 	diags := convertCommandDiagnostics(commandDiags)
@@ -274,6 +461,18 @@ func renderAndWrapServiceError(err error, req ExecuteRequest) error {
 		return newServiceError(err, issue.InvalidArgumentId, RenderArgumentValidationError(argErr))
 	}
 
+	if confirmErr, ok := errors.AsType[*commandsvc.ConfirmationRequiredError](err); ok {
+		return newServiceError(err, issue.ConfirmationRequiredId, RenderConfirmationRequiredError(confirmErr))
+	}
+
+	if lockedErr, ok := errors.AsType[*commandsvc.CommandLockedError](err); ok {
+		return newServiceError(err, 0, RenderCommandLockedError(lockedErr))
+	}
+
+	if privateErr, ok := errors.AsType[*commandsvc.PrivateCommandError](err); ok {
+		return newServiceError(err, 0, RenderPrivateCommandError(privateErr))
+	}
+
 	if ambigErr, ok := errors.AsType[*commandsvc.AmbiguousCommandError](err); ok {
 		styledMsg := RenderAmbiguousCommandError(&AmbiguousCommandError{
 			CommandName: ambigErr.CommandName,
@@ -29,12 +29,16 @@ type (
 		fromSource string
 		// forceRebuild forces container image rebuilds, bypassing cache.
 		forceRebuild bool
+		// force runs commands that declare sources even when they are up to date.
+		force bool
 		// containerName overrides the persistent container target name.
 		containerName string //goplint:ignore -- Cobra flag binding converted to typed request at adapter boundary.
 		// dryRun enables dry-run mode: prints what would be executed without executing.
 		dryRun bool
 		// watch enables watch mode: re-execute command on file changes.
 		watch bool
+		// yes answers the confirmation question of commands that declare one.
+		yes bool
 	}
 
 	//goplint:validate-all
@@ -134,9 +138,11 @@ Examples:
 	cmdCmd.PersistentFlags().StringVarP(&cmdFlags.runtimeOverride, "ivk-runtime", "r", "", "override the runtime (must be allowed by the command)")
 	cmdCmd.PersistentFlags().StringVarP(&cmdFlags.fromSource, "ivk-from", "f", "", "source to run command from (e.g., 'invowkfile' or module name)")
 	cmdCmd.PersistentFlags().BoolVar(&cmdFlags.forceRebuild, "ivk-force-rebuild", false, "force rebuild of container images (container runtime only)")
+	cmdCmd.PersistentFlags().BoolVar(&cmdFlags.force, "ivk-force", false, "run commands that declare sources even when they are up to date")
 	cmdCmd.PersistentFlags().StringVar(&cmdFlags.containerName, "ivk-container-name", "", "override persistent container target name (container runtime only)")
 	cmdCmd.PersistentFlags().BoolVar(&cmdFlags.dryRun, "ivk-dry-run", false, "print what would be executed without executing")
 	cmdCmd.PersistentFlags().BoolVarP(&cmdFlags.watch, "ivk-watch", "W", false, "watch files for changes and re-execute")
+	cmdCmd.PersistentFlags().BoolVar(&cmdFlags.yes, "ivk-yes", false, "confirm commands that ask for confirmation without prompting")
 
 	// Dynamic command leaves are only needed for `invowk cmd ...` flows.
 	// Skipping registration for unrelated invocations (e.g., --version, init)
@@ -330,6 +336,12 @@ func executeRequest(cmd *cobra.Command, app *App, req ExecuteRequest) error {
 	reqCtx := contextWithConfigPath(cmd.Context(), string(req.ConfigPath))
 	cmd.SetContext(reqCtx)
 
+	// Missing required inputs with a prompt block are asked for before execution.
+	req, err := resolveInputPrompts(reqCtx, cmd, app.Prompts, req)
+	if err != nil {
+		return err
+	}
+
 	// Cobra adapters always render service diagnostics in the CLI layer.
 	result, diags, err := app.Commands.Execute(reqCtx, req)
 	app.Diagnostics.Render(reqCtx, diags, app.stderr)
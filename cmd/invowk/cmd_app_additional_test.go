@@ -402,3 +402,22 @@ func testCommandInfo(t *testing.T, name, script string) *discovery.CommandInfo {
 		SourceID:    discovery.SourceIDInvowkfile,
 	}
 }
+
+func TestCLIExecutionObserverCommandDeprecated(t *testing.T) {
+	t.Parallel()
+
+	event := commandsvc.DeprecationEvent{CommandName: "old", Message: "going away"}
+	var stderr bytes.Buffer
+	observer := &cliExecutionObserver{stdout: &bytes.Buffer{}, stderr: &stderr}
+	observer.CommandDeprecated(event)
+	if got, want := stderr.String(), RenderDeprecationWarning(event); got != want {
+		t.Fatalf("CommandDeprecated() output = %q, want only the warning %q", got, want)
+	}
+
+	stderr.Reset()
+	event.Verbose = true
+	observer.CommandDeprecated(event)
+	if got := stderr.String(); !strings.HasPrefix(got, RenderDeprecationWarning(event)) || len(got) == len(RenderDeprecationWarning(event)) {
+		t.Fatalf("CommandDeprecated(verbose) output = %q, want the warning followed by the catalog entry", got)
+	}
+}
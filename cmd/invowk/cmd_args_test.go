@@ -202,6 +202,13 @@ func TestBuildArgsDocumentation(t *testing.T) {
 			},
 			shouldHave: []string{"services", "(variadic)", "Services to deploy"},
 		},
+		{
+			name: "argument with choices",
+			args: []invowkfile.Argument{
+				{Name: "env", Description: "Target environment", Choices: []string{"dev", "staging", "prod"}},
+			},
+			shouldHave: []string{"env", "[choices: dev|staging|prod]", "Target environment"},
+		},
 		{
 			name: "string type not shown",
 			args: []invowkfile.Argument{
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -29,6 +30,9 @@ type (
 		lastExecuteRequest        ExecuteRequest
 		resolvedFromSource        *discovery.CommandInfo
 		resolvedRequest           ExecuteRequest
+		completionRequests        []CompletionRequest
+		completions               []CompletionCandidate
+		completionErr             error
 	}
 
 	recordingDiscoveryService struct {
@@ -88,6 +92,12 @@ func (s *recordingCommandService) ResolveFromSource(ctx context.Context, req Exe
 	return cmdInfo, resolvedReq, nil, nil
 }
 
+func (s *recordingCommandService) Complete(ctx context.Context, req CompletionRequest) ([]CompletionCandidate, error) {
+	s.lastConfigPath = configPathFromContext(ctx)
+	s.completionRequests = append(s.completionRequests, req)
+	return s.completions, s.completionErr
+}
+
 func (s *recordingDiscoveryService) DiscoverCommandSet(ctx context.Context) (discovery.CommandSetResult, error) {
 	s.lastConfigPath = configPathFromContext(ctx)
 	return s.result, nil
@@ -432,7 +442,7 @@ func TestDiscoverCommand_DoesNotDuplicateConfigDiagnostics(t *testing.T) {
 		},
 		func() map[string]string { return nil },
 		testConfigFallback,
-		commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil),
+		commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 	)
 
 	customCuePath2 := filepath.Join(t.TempDir(), "custom.cue")
@@ -470,7 +480,7 @@ func TestDiscoverCommand_ResolvedCommandSkipsLookup(t *testing.T) {
 		disc,
 		func() map[string]string { return nil },
 		testConfigFallback,
-		commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil),
+		commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 	)
 
 	resolved := &discovery.CommandInfo{
@@ -522,3 +532,81 @@ func testMustDiagnostic(t *testing.T, severity discovery.Severity, code discover
 	}
 	return d
 }
+
+func TestBuildLeafCommand_ScriptCompletionUsesCommandService(t *testing.T) {
+	t.Parallel()
+
+	complete := &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: "kubectl get ns -o name"}}
+	cmdInfo := &discovery.CommandInfo{
+		Name:       "deploy",
+		SimpleName: "deploy",
+		SourceID:   discovery.SourceIDInvowkfile,
+		FilePath:   types.FilesystemPath("invowkfile.cue"),
+		Command: &invowkfile.Command{
+			Name: "deploy",
+			Flags: []invowkfile.Flag{
+				{Name: "namespace", Description: "Target namespace", Complete: complete},
+			},
+			Args: []invowkfile.Argument{
+				{Name: "service", Description: "Service to deploy", Complete: complete},
+			},
+		},
+	}
+	commands := &recordingCommandService{
+		completions: []CompletionCandidate{
+			{Value: "prod", Description: "Production"},
+			{Value: "staging"},
+			{Value: "preview"},
+		},
+	}
+	rootFlags := &rootFlagValues{configPath: filepath.Join(t.TempDir(), "custom.cue")}
+	app := &App{
+		Config:      &fixedConfigProvider{cfg: config.DefaultConfig()},
+		Commands:    commands,
+		Diagnostics: &defaultDiagnosticRenderer{},
+		stderr:      io.Discard,
+	}
+	leaf := buildLeafCommand(app, rootFlags, &cmdFlagValues{}, cmdInfo, "deploy")
+	leaf.SetContext(t.Context())
+	if err := leaf.Flags().Set("namespace", "prod"); err != nil {
+		t.Fatalf("set namespace flag: %v", err)
+	}
+
+	completeFlag, ok := leaf.GetFlagCompletionFunc("namespace")
+	if !ok {
+		t.Fatal("namespace flag has no completion function")
+	}
+	got, directive := completeFlag(leaf, []string{"api"}, "p")
+	if want := []string{"prod\tProduction", "preview"}; !slices.Equal(got, want) {
+		t.Errorf("namespace completions = %q, want %q", got, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("namespace directive = %v, want NoFileComp", directive)
+	}
+
+	leaf.ValidArgsFunction(leaf, nil, "s")
+	if len(commands.completionRequests) != 2 {
+		t.Fatalf("completion requests = %d, want 2", len(commands.completionRequests))
+	}
+	flagReq, argReq := commands.completionRequests[0], commands.completionRequests[1]
+	if flagReq.Flag != "namespace" || flagReq.Arg != "" {
+		t.Errorf("flag completion target = %q/%q, want namespace flag", flagReq.Flag, flagReq.Arg)
+	}
+	if flagReq.Request.Name != "deploy" || !slices.Equal(flagReq.Request.Args, []string{"api"}) {
+		t.Errorf("flag completion request = %q %v, want deploy [api]", flagReq.Request.Name, flagReq.Request.Args)
+	}
+	if flagReq.Request.FlagValues["namespace"] != "prod" {
+		t.Errorf("flag completion FlagValues = %v, want namespace=prod", flagReq.Request.FlagValues)
+	}
+	if argReq.Arg != "service" || argReq.Flag != "" {
+		t.Errorf("arg completion target = %q/%q, want service argument", argReq.Flag, argReq.Arg)
+	}
+	if commands.lastConfigPath != rootFlags.configPath {
+		t.Errorf("completion config path = %q, want %q", commands.lastConfigPath, rootFlags.configPath)
+	}
+
+	commands.completionErr = errors.New("script failed")
+	if got, _ := completeFlag(leaf, nil, ""); len(got) != 0 {
+		t.Errorf("completions on script failure = %q, want none", got)
+	}
+}
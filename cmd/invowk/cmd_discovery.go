@@ -19,11 +19,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// commandGroup holds commands grouped by category for list rendering.
-type commandGroup struct {
-	category invowkfile.CommandCategory
-	commands []*discovery.CommandInfo
-}
+type (
+	// commandGroup holds commands grouped by category for list rendering.
+	commandGroup struct {
+		category invowkfile.CommandCategory
+		commands []*discovery.CommandInfo
+	}
+
+	// scriptCompletionFunc completes the flag or argument named by target
+	// from its completion script.
+	scriptCompletionFunc func(cmd *cobra.Command, args []string, toComplete string, target CompletionRequest) ([]string, cobra.ShellCompDirective)
+)
 
 // registerDiscoveredCommands adds discovered commands as Cobra subcommands under `cmd`.
 // Unambiguous commands are registered under their SimpleName for transparent access
@@ -58,65 +64,97 @@ func registerDiscoveredCommands(ctx context.Context, app *App, rootFlags *rootFl
 		if commandSet.AmbiguousNames[cmdInfo.SimpleName] {
 			continue
 		}
+		registerCommandPath(app, rootFlags, cmdFlags, cmdCmd, commandMap, ambiguousPrefixes, cmdInfo, string(cmdInfo.SimpleName), false)
+	}
+
+	// Aliases are registered after every command name so names always win
+	// over aliases. Alias leaves are hidden from help listings; the aliased
+	// command's help lists them instead.
+	for _, cmdInfo := range commandSet.Commands {
+		for _, alias := range cmdInfo.Command.Aliases {
+			if commandSet.AmbiguousNames[alias] {
+				continue
+			}
+			registerCommandPath(app, rootFlags, cmdFlags, cmdCmd, commandMap, ambiguousPrefixes, cmdInfo, string(alias), true)
+		}
+	}
+}
+
+// registerCommandPath registers the space-separated path of a command name or
+// alias under cmdCmd, creating namespace parents as needed. commandMap tracks
+// the nodes created so far; a path whose leaf is already registered is skipped.
+// Hidden leaves (aliases and private or hidden commands) and the parents
+// created only for them are hidden; a listed leaf unhides its parents.
+//
+//nolint:contextcheck // Cobra child commands carry cancellation through cmd.Context() at execution time.
+func registerCommandPath(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValues, cmdCmd *cobra.Command, commandMap map[string]*cobra.Command, ambiguousPrefixes map[string]bool, cmdInfo *discovery.CommandInfo, registrationName string, isAlias bool) {
+	parts := strings.Fields(registrationName)
+	parent := cmdCmd
+	hidden := isAlias || !cmdInfo.Command.Visibility.IsListed()
 
-		registrationName := string(cmdInfo.SimpleName)
-		parts := strings.Fields(registrationName)
-		parent := cmdCmd
+	for i, part := range parts {
+		prefix := strings.Join(parts[:i+1], " ")
 
-		for i, part := range parts {
-			prefix := strings.Join(parts[:i+1], " ")
+		if existing, ok := commandMap[prefix]; ok {
+			if !hidden {
+				existing.Hidden = false
+			}
+			parent = existing
+			continue
+		}
 
-			if existing, ok := commandMap[prefix]; ok {
-				parent = existing
-				continue
+		isLeaf := i == len(parts)-1
+		var newCmd *cobra.Command
+		if isLeaf {
+			newCmd = buildLeafCommand(app, rootFlags, cmdFlags, cmdInfo, part)
+			if isAlias {
+				newCmd.Short = fmt.Sprintf("Alias for '%s'", cmdInfo.SimpleName)
 			}
+		} else {
+			newCmd = buildNamespaceCommand(app, rootFlags, cmdFlags, prefix, part, ambiguousPrefixes[prefix])
+		}
+		newCmd.Hidden = hidden
 
-			isLeaf := i == len(parts)-1
-			var newCmd *cobra.Command
-			if isLeaf {
-				newCmd = buildLeafCommand(app, rootFlags, cmdFlags, cmdInfo, part)
-			} else {
-				// Parent nodes exist to support nested command trees and ambiguity
-				// handling for intermediate prefixes.
-				parentPrefix := prefix
-				isAmbiguous := ambiguousPrefixes[parentPrefix]
-				newCmd = &cobra.Command{
-					Use:   part,
-					Short: fmt.Sprintf("Commands under '%s'", prefix),
-					RunE: func(cmd *cobra.Command, args []string) error {
-						ctx := contextWithConfigPath(cmd.Context(), rootFlags.configPath)
-						cmd.SetContext(ctx)
-
-						fromFlag, _ := cmd.Flags().GetString("ivk-from")
-						if fromFlag != "" {
-							// Preserve full path for longest-match disambiguation.
-							fullArgs := append(strings.Fields(parentPrefix), args...)
-							filter := &SourceFilter{SourceID: normalizeSourceName(fromFlag)}
-							return runDisambiguatedCommand(cmd, app, rootFlags, cmdFlags, filter, fullArgs)
-						}
-
-						if isAmbiguous {
-							cmdArgs := append(strings.Fields(parentPrefix), args...)
-							if err := checkAmbiguousCommand(ctx, app, cmdArgs); err != nil {
-								if ambigErr, ok := errors.AsType[*AmbiguousCommandError](err); ok {
-									fmt.Fprint(app.stderr, RenderAmbiguousCommandError(ambigErr))
-									cmd.SilenceErrors = true
-									cmd.SilenceUsage = true
-								}
-								return err
-							}
-						}
-
-						// Non-ambiguous parents behave as help-only namespaces.
-						return cmd.Help()
-					},
+		parent.AddCommand(newCmd)
+		commandMap[prefix] = newCmd
+		parent = newCmd
+	}
+}
+
+// buildNamespaceCommand creates a parent node of the command tree. Parent
+// nodes exist to support nested command trees and ambiguity handling for
+// intermediate prefixes.
+func buildNamespaceCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValues, parentPrefix, part string, isAmbiguous bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   part,
+		Short: fmt.Sprintf("Commands under '%s'", parentPrefix),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := contextWithConfigPath(cmd.Context(), rootFlags.configPath)
+			cmd.SetContext(ctx)
+
+			fromFlag, _ := cmd.Flags().GetString("ivk-from")
+			if fromFlag != "" {
+				// Preserve full path for longest-match disambiguation.
+				fullArgs := append(strings.Fields(parentPrefix), args...)
+				filter := &SourceFilter{SourceID: normalizeSourceName(fromFlag)}
+				return runDisambiguatedCommand(cmd, app, rootFlags, cmdFlags, filter, fullArgs)
+			}
+
+			if isAmbiguous {
+				cmdArgs := append(strings.Fields(parentPrefix), args...)
+				if err := checkAmbiguousCommand(ctx, app, cmdArgs); err != nil {
+					if ambigErr, ok := errors.AsType[*AmbiguousCommandError](err); ok {
+						fmt.Fprint(app.stderr, RenderAmbiguousCommandError(ambigErr))
+						cmd.SilenceErrors = true
+						cmd.SilenceUsage = true
+					}
+					return err
 				}
 			}
 
-			parent.AddCommand(newCmd)
-			commandMap[prefix] = newCmd
-			parent = newCmd
-		}
+			// Non-ambiguous parents behave as help-only namespaces.
+			return cmd.Help()
+		},
 	}
 }
 
@@ -133,6 +171,7 @@ func buildLeafCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValu
 	cmdSourceID := cmdInfo.SourceID
 	cmdRuntimeFlags := cmdInfo.Command.Flags
 	cmdArgs := cmdInfo.Command.Args
+	completeScript := scriptCompleter(app, rootFlags, cmdFlags, cmdInfo)
 
 	useStr := buildCommandUsageString(cmdPart, cmdArgs)
 
@@ -186,6 +225,12 @@ func buildLeafCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValu
 	newCmd.Flags().StringArray("ivk-env-inherit-deny", nil, "denylist for host environment inheritance (repeatable)")
 	newCmd.Flags().StringP("ivk-workdir", "w", "", "override the working directory for this command")
 
+	if dep := cmdInfo.Command.Deprecated; dep != nil {
+		newCmd.Long += "\n\nDeprecated: " + string(dep.Message)
+	}
+	if len(cmdInfo.Command.Aliases) > 0 {
+		newCmd.Long += "\n\nAliases: " + buildAliasesDocumentation(cmdInfo.Command.Aliases)
+	}
 	if len(cmdArgs) > 0 {
 		newCmd.Long += "\n\nArguments:\n" + buildArgsDocumentation(cmdArgs)
 	}
@@ -194,13 +239,14 @@ func buildLeafCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValu
 		// Project invowkfile flag definitions into Cobra flags with matching types.
 		name := string(flag.Name)
 		short := string(flag.Short)
+		usage := buildFlagUsage(flag)
 		switch flag.GetType() {
 		case invowkfile.FlagTypeBool:
 			defaultVal := flag.DefaultValue == "true"
 			if short != "" {
-				newCmd.Flags().BoolP(name, short, defaultVal, string(flag.Description))
+				newCmd.Flags().BoolP(name, short, defaultVal, usage)
 			} else {
-				newCmd.Flags().Bool(name, defaultVal, string(flag.Description))
+				newCmd.Flags().Bool(name, defaultVal, usage)
 			}
 		case invowkfile.FlagTypeInt:
 			defaultVal := 0
@@ -208,9 +254,9 @@ func buildLeafCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValu
 				_, _ = fmt.Sscanf(flag.DefaultValue, "%d", &defaultVal)
 			}
 			if short != "" {
-				newCmd.Flags().IntP(name, short, defaultVal, string(flag.Description))
+				newCmd.Flags().IntP(name, short, defaultVal, usage)
 			} else {
-				newCmd.Flags().Int(name, defaultVal, string(flag.Description))
+				newCmd.Flags().Int(name, defaultVal, usage)
 			}
 		case invowkfile.FlagTypeFloat:
 			defaultVal := 0.0
@@ -218,26 +264,161 @@ func buildLeafCommand(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValu
 				_, _ = fmt.Sscanf(flag.DefaultValue, "%f", &defaultVal)
 			}
 			if short != "" {
-				newCmd.Flags().Float64P(name, short, defaultVal, string(flag.Description))
+				newCmd.Flags().Float64P(name, short, defaultVal, usage)
 			} else {
-				newCmd.Flags().Float64(name, defaultVal, string(flag.Description))
+				newCmd.Flags().Float64(name, defaultVal, usage)
 			}
-		case invowkfile.FlagTypeString:
+		case invowkfile.FlagTypeList:
+			// Defaults were validated as JSON string arrays at parse time.
+			defaultVal, _ := invowkfile.ParseListValue(flag.DefaultValue)
 			if short != "" {
-				newCmd.Flags().StringP(name, short, flag.DefaultValue, string(flag.Description))
+				newCmd.Flags().StringArrayP(name, short, defaultVal, usage)
 			} else {
-				newCmd.Flags().String(name, flag.DefaultValue, string(flag.Description))
+				newCmd.Flags().StringArray(name, defaultVal, usage)
+			}
+		case invowkfile.FlagTypeString, invowkfile.FlagTypeDuration, invowkfile.FlagTypePath, invowkfile.FlagTypeJSON:
+			// Typed string values are validated by the command service before execution.
+			if short != "" {
+				newCmd.Flags().StringP(name, short, flag.DefaultValue, usage)
+			} else {
+				newCmd.Flags().String(name, flag.DefaultValue, usage)
 			}
 		}
-		if flag.Required {
+		if flag.Required && flag.Prompt == nil {
 			// Required markers are applied at Cobra level for immediate feedback.
+			// Prompted flags are checked after prompting (see resolveInputPrompts).
 			_ = newCmd.MarkFlagRequired(name)
 		}
+		switch {
+		case len(flag.Choices) > 0:
+			_ = newCmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(flag.Choices, cobra.ShellCompDirectiveNoFileComp))
+		case flag.Complete != nil:
+			flagName := flag.Name
+			_ = newCmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return completeScript(cmd, args, toComplete, CompletionRequest{Flag: flagName})
+			})
+		case flag.GetType() == invowkfile.FlagTypePath && flag.Kind == invowkfile.PathKindDir:
+			_ = newCmd.MarkFlagDirname(name)
+		}
+	}
+
+	if groups := cmdInfo.Command.FlagGroups; groups != nil {
+		// Group members were checked against the declared flags at parse time,
+		// so the Cobra markers cannot panic on unknown flags.
+		for _, group := range groups.MutuallyExclusive {
+			newCmd.MarkFlagsMutuallyExclusive(group.Strings()...)
+		}
+		for _, group := range groups.RequiredTogether {
+			newCmd.MarkFlagsRequiredTogether(group.Strings()...)
+		}
+		for _, group := range groups.OneRequired {
+			newCmd.MarkFlagsOneRequired(group.Strings()...)
+		}
+		newCmd.Long += "\n\nFlag groups:\n" + buildFlagGroupsDocumentation(groups)
+		// Cobra checks flag groups after PreRunE. Checking them here first
+		// renders violations through the issue catalog instead of a bare error.
+		newCmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+			if fromFlag, _ := cmd.Flags().GetString("ivk-from"); fromFlag != "" && normalizeSourceName(fromFlag) != cmdSourceID {
+				return nil // RunE re-routes to the leaf of the requested source
+			}
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return renderServiceErrorIfPresent(app, newServiceError(err, issue.FlagGroupViolatedId, RenderFlagGroupError(cmdName, err)))
+			}
+			return nil
+		}
+	}
+
+	if slices.ContainsFunc(cmdArgs, func(arg invowkfile.Argument) bool { return len(arg.Choices) > 0 || arg.Complete != nil }) {
+		newCmd.ValidArgsFunction = completeArgs(cmdArgs, completeScript)
 	}
 
 	return newCmd
 }
 
+// buildFlagUsage builds the help text of a flag: its description followed by
+// the allowed choices, if any.
+//
+//plint:render
+func buildFlagUsage(flag invowkfile.Flag) string {
+	usage := string(flag.Description)
+	if len(flag.Choices) > 0 {
+		usage += fmt.Sprintf(" (one of: %s)", strings.Join(flag.Choices, ", "))
+	}
+	return usage
+}
+
+// completeArgs completes positional arguments that declare choices or a
+// completion script. The argument being completed is selected by position; a
+// trailing variadic argument covers every remaining position.
+func completeArgs(argDefs []invowkfile.Argument, completeScript scriptCompletionFunc) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var def *invowkfile.Argument
+		switch {
+		case len(args) < len(argDefs):
+			def = &argDefs[len(args)]
+		case len(argDefs) > 0 && argDefs[len(argDefs)-1].Variadic:
+			def = &argDefs[len(argDefs)-1]
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if def.Complete != nil {
+			return completeScript(cmd, args, toComplete, CompletionRequest{Arg: def.Name})
+		}
+		if len(def.Choices) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		completions := make([]string, 0, len(def.Choices))
+		for _, choice := range def.Choices {
+			if strings.HasPrefix(choice, toComplete) {
+				completions = append(completions, choice)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// scriptCompleter returns the function that completes a flag or argument by
+// running its completion script through the command service. The script sees
+// the flags and args typed so far. A failing script offers no completions:
+// errors only reach the Cobra completion debug log, never the shell prompt.
+func scriptCompleter(app *App, rootFlags *rootFlagValues, cmdFlags *cmdFlagValues, cmdInfo *discovery.CommandInfo) scriptCompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string, target CompletionRequest) ([]string, cobra.ShellCompDirective) {
+		req, err := buildCommandExecuteRequest(cmd, rootFlags, cmdFlags, executeRequestOptions{
+			Name:            string(cmdInfo.Name),
+			Args:            args,
+			FromSource:      discovery.SourceID(cmdFlags.fromSource), //goplint:ignore -- CLI flag value, validated downstream
+			ResolvedCommand: cmdInfo,
+			FlagDefs:        cmdInfo.Command.Flags,
+			ArgDefs:         cmdInfo.Command.Args,
+		})
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		target.Request = req
+
+		ctx := contextWithConfigPath(cmd.Context(), rootFlags.configPath)
+		candidates, err := app.Commands.Complete(ctx, target)
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			if !strings.HasPrefix(candidate.Value, toComplete) {
+				continue
+			}
+			if candidate.Description != "" {
+				completions = append(completions, cobra.CompletionWithDesc(candidate.Value, candidate.Description))
+			} else {
+				completions = append(completions, candidate.Value)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 // buildCommandUsageString builds the Cobra Use string including argument placeholders.
 //
 //plint:render
@@ -265,6 +446,38 @@ func buildCommandUsageString(cmdPart string, args []invowkfile.Argument) string
 	return strings.Join(parts, " ")
 }
 
+// buildAliasesDocumentation lists the aliases of a command for its help text.
+//
+//plint:render
+func buildAliasesDocumentation(aliases []invowkfile.CommandName) string {
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, string(alias))
+	}
+	return strings.Join(names, ", ")
+}
+
+// buildFlagGroupsDocumentation lists the flag group constraints of a command
+// for its help text.
+//
+//plint:render
+func buildFlagGroupsDocumentation(groups *invowkfile.FlagGroups) string {
+	var lines []string
+	for _, kind := range []struct {
+		label  string
+		groups []invowkfile.FlagGroup
+	}{
+		{"mutually exclusive", groups.MutuallyExclusive},
+		{"required together", groups.RequiredTogether},
+		{"at least one required", groups.OneRequired},
+	} {
+		for _, group := range kind.groups {
+			lines = append(lines, fmt.Sprintf("  %-22s --%s", kind.label, strings.Join(group.Strings(), ", --")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // buildArgsDocumentation builds the documentation string for arguments.
 //
 //plint:render
@@ -289,7 +502,12 @@ func buildArgsDocumentation(args []invowkfile.Argument) string {
 			variadicInfo = " (variadic)"
 		}
 
-		lines = append(lines, fmt.Sprintf("  %-20s %s%s%s - %s", arg.Name, status, typeInfo, variadicInfo, arg.Description))
+		choicesInfo := ""
+		if len(arg.Choices) > 0 {
+			choicesInfo = fmt.Sprintf(" [choices: %s]", strings.Join(arg.Choices, "|"))
+		}
+
+		lines = append(lines, fmt.Sprintf("  %-20s %s%s%s%s - %s", arg.Name, status, typeInfo, variadicInfo, choicesInfo, arg.Description))
 	}
 
 	return strings.Join(lines, "\n")
@@ -355,6 +573,7 @@ func completeCommands(app *App, rootFlags *rootFlagValues) func(*cobra.Command,
 // listCommands displays all available commands grouped by source. Each command
 // shows its name, description, available runtimes (with default marked by *),
 // and supported platforms. Ambiguous commands are annotated with their source ID.
+// Private and hidden commands are left out.
 //
 // Diagnostic rendering follows a verbose/non-verbose split:
 //   - Verbose: full inline diagnostics before the listing
@@ -423,7 +642,7 @@ func listCommands(cmd *cobra.Command, app *App, rootFlags *rootFlagValues) error
 	fmt.Fprintln(w)
 
 	for _, sourceID := range commandSet.SourceOrder {
-		cmds := commandSet.BySource[sourceID]
+		cmds := listedCommands(commandSet.BySource[sourceID])
 		if len(cmds) == 0 {
 			continue
 		}
@@ -477,6 +696,14 @@ func listCommands(cmd *cobra.Command, app *App, rootFlags *rootFlagValues) error
 	return nil
 }
 
+// listedCommands returns the commands of cmds that appear in listings, leaving
+// out private and hidden ones.
+func listedCommands(cmds []*discovery.CommandInfo) []*discovery.CommandInfo {
+	return slices.DeleteFunc(slices.Clone(cmds), func(cmd *discovery.CommandInfo) bool {
+		return !cmd.Command.Visibility.IsListed()
+	})
+}
+
 // groupByCategory groups commands by their Category field.
 // Commands without a category come first, followed by categorized groups
 // in alphabetical order.
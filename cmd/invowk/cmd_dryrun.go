@@ -12,6 +12,7 @@ import (
 
 	"github.com/invowk/invowk/internal/app/commandsvc"
 	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
 )
 
 const dryRunFieldFmt = "  %s %s\n"
@@ -19,7 +20,8 @@ const dryRunFieldFmt = "  %s %s\n"
 // renderDryRun prints the resolved execution context without executing.
 // It shows the command name, source, runtime, platform, working directory,
 // script content, and environment variables — everything a user needs to
-// understand what invowk would do.
+// understand what invowk would do. Multi-step commands render their full
+// step plan instead of a single script.
 func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 	fmt.Fprintln(w, TitleStyle.Render("Dry Run"))
 	fmt.Fprintln(w)
@@ -27,13 +29,60 @@ func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 	// Command metadata.
 	fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Command:"), plan.CommandName)
 	fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Source:"), plan.SourceID)
-	fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Runtime:"), string(plan.Runtime))
+	runtimeLabel := string(plan.Runtime)
+	if len(plan.Steps) > 0 {
+		runtimeLabel = "per step"
+	}
+	fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Runtime:"), runtimeLabel)
 	fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Platform:"), string(plan.Platform))
+	if plan.Selection != "" {
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Selected:"), plan.Selection)
+	}
 
 	if plan.WorkDir != "" {
 		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("WorkDir:"), plan.WorkDir)
 	}
 
+	// Prerequisites run (in this order, or in parallel where independent)
+	// before the command itself.
+	if len(plan.Prerequisites) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, VerboseHighlightStyle.Render("  Prerequisites (not executed):"))
+		for i, name := range plan.Prerequisites {
+			fmt.Fprintf(w, "    %d. %s\n", i+1, name)
+		}
+	}
+
+	// Matrix commands run once per combination; the rest of the plan shows
+	// the first one.
+	if len(plan.Matrix) > 0 {
+		mode := "one after another"
+		if plan.MatrixParallel {
+			mode = "in parallel"
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, VerboseHighlightStyle.Render(fmt.Sprintf("  Matrix (%d combinations, %s; plan shows the first):", len(plan.Matrix), mode)))
+		for i, combination := range plan.Matrix {
+			fmt.Fprintf(w, "    %d. %s\n", i+1, combination.Label())
+		}
+	}
+
+	if len(plan.Hooks) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, VerboseHighlightStyle.Render("  Hooks (not executed):"))
+		renderDryRunHooks(w, plan.Hooks, "    ")
+	}
+
+	if len(plan.Steps) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, VerboseHighlightStyle.Render("  Steps:"))
+		renderDryRunSteps(w, plan.Steps, "    ")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, SubtitleStyle.Render("  Note: dependency validation (tools, cmds, filepaths, capabilities, custom checks, env vars) is not performed in dry-run mode."))
+		fmt.Fprintln(w)
+		return
+	}
+
 	if err := plan.Script.Validate(); err != nil {
 		fmt.Fprintln(w)
 		return
@@ -42,6 +91,15 @@ func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 	if plan.Timeout != "" {
 		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Timeout:"), plan.Timeout)
 	}
+	if plan.Retry != nil {
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Retry:"), dryRunRetry(plan.Retry))
+	}
+	if len(plan.SuccessExitCodes) > 0 || len(plan.ExitCodeMap) > 0 {
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("ExitCodes:"), dryRunExitCodes(plan.SuccessExitCodes, plan.ExitCodeMap))
+	}
+	if plan.Freshness != "" {
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Incremental:"), plan.Freshness)
+	}
 	if plan.Runtime == invowkfile.RuntimeContainer && plan.PersistentContainerMode != "" {
 		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Container:"), plan.PersistentContainerMode)
 	}
@@ -50,6 +108,13 @@ func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("ContainerNameSource:"), plan.PersistentContainerNameSource)
 		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("CreateIfMissing:"), strconv.FormatBool(plan.PersistentContainerCreateIfMissing))
 	}
+	if plan.Runtime == invowkfile.RuntimeRemoteSSH && plan.RemoteHost != "" {
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Remote:"), dryRunRemoteTarget(plan))
+	}
+	if plan.Runtime == invowkfile.RuntimeNative && plan.Sandbox != nil {
+		sandbox := fmt.Sprintf("network %s, filesystem %s", plan.Sandbox.Network, plan.VirtualFilesystemAccess.Effective())
+		fmt.Fprintf(w, dryRunFieldFmt, VerboseHighlightStyle.Render("Sandbox:"), sandbox)
+	}
 	renderDryRunVirtualSafety(w, plan)
 
 	// Script content.
@@ -88,6 +153,13 @@ func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 			fmt.Fprintf(w, "    %s=%s\n", k, userVars[k])
 		}
 	}
+	if len(plan.Secrets) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, VerboseHighlightStyle.Render("  Secrets (resolved at execution time):"))
+		for _, name := range slices.Sorted(maps.Keys(plan.Secrets)) {
+			fmt.Fprintf(w, "    %s (from %s)\n", name, plan.Secrets[name])
+		}
+	}
 
 	if plan.DependencyValidationSkipped {
 		fmt.Fprintln(w, SubtitleStyle.Render("  Note: dependency validation (tools, cmds, filepaths, capabilities, custom checks, env vars) is not performed in dry-run mode."))
@@ -95,8 +167,114 @@ func renderDryRun(w io.Writer, plan commandsvc.DryRunPlan) {
 	fmt.Fprintln(w)
 }
 
+// renderDryRunSteps prints the step plan of a multi-step command. Nested
+// multi-step commands are rendered recursively with deeper indentation.
+func renderDryRunSteps(w io.Writer, steps []commandsvc.DryRunStepPlan, indent string) {
+	for i, step := range steps {
+		header := fmt.Sprintf("%d. %s", i+1, step.Label)
+		if step.Cmd != "" && string(step.Cmd) != step.Label {
+			header += fmt.Sprintf(" (cmd: %s)", step.Cmd)
+		}
+		if step.ContinueOnError {
+			header += " [continue on error]"
+		}
+		fmt.Fprintf(w, "%s%s\n", indent, header)
+
+		detail := indent + "   "
+		if len(step.Plan.Steps) > 0 {
+			renderDryRunSteps(w, step.Plan.Steps, detail)
+			continue
+		}
+		if step.Cmd != "" {
+			fmt.Fprintf(w, "%sCommand: %s (source: %s)\n", detail, step.Plan.CommandName, step.Plan.SourceID)
+		}
+		fmt.Fprintf(w, "%sRuntime: %s\n", detail, step.Plan.Runtime)
+		if step.Plan.Timeout != "" {
+			fmt.Fprintf(w, "%sTimeout: %s\n", detail, step.Plan.Timeout)
+		}
+		if step.Plan.Script.IsFile() {
+			fmt.Fprintf(w, "%sScript: (file: %s)\n", detail, *step.Plan.Script.File)
+			continue
+		}
+		fmt.Fprintf(w, "%sScript:\n", detail)
+		for line := range strings.SplitSeq(string(step.Plan.Script.Content), "\n") {
+			fmt.Fprintf(w, "%s  %s\n", detail, line)
+		}
+	}
+}
+
+// dryRunRetry summarizes a retry policy on one line, e.g.
+// "3 attempts, backoff 1s (max 30s), on exit codes 75, 124".
+func dryRunRetry(retry *invowkfile.RetryPolicy) string {
+	backoff := invowkfile.DefaultRetryBackoff.String()
+	if retry.Backoff != "" {
+		backoff = string(retry.Backoff)
+	}
+	summary := fmt.Sprintf("%d attempts, backoff %s", retry.Attempts, backoff)
+	if retry.MaxBackoff != "" {
+		summary += fmt.Sprintf(" (max %s)", retry.MaxBackoff)
+	}
+	if len(retry.OnExitCodes) > 0 {
+		codes := make([]string, len(retry.OnExitCodes))
+		for i, code := range retry.OnExitCodes {
+			codes[i] = code.String()
+		}
+		summary += ", on exit codes " + strings.Join(codes, ", ")
+	}
+	return summary
+}
+
+// dryRunExitCodes summarizes an implementation's exit code policy, e.g.
+// "success 1; 2 -> 10, 3 -> 10".
+func dryRunExitCodes(successCodes []types.ExitCode, exitCodeMap invowkfile.ExitCodeMap) string {
+	var parts []string
+	if len(successCodes) > 0 {
+		codes := make([]string, len(successCodes))
+		for i, code := range successCodes {
+			codes[i] = code.String()
+		}
+		parts = append(parts, "success "+strings.Join(codes, ", "))
+	}
+	if len(exitCodeMap) > 0 {
+		mappings := make([]string, 0, len(exitCodeMap))
+		for _, from := range slices.Sorted(maps.Keys(exitCodeMap)) {
+			mappings = append(mappings, fmt.Sprintf("%s -> %s", from, exitCodeMap[from]))
+		}
+		parts = append(parts, strings.Join(mappings, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// dryRunRemoteTarget formats the remote-ssh target as [user@]host[:port].
+func dryRunRemoteTarget(plan commandsvc.DryRunPlan) string {
+	target := plan.RemoteHost.String()
+	if plan.RemoteUser != "" {
+		target = plan.RemoteUser.String() + "@" + target
+	}
+	if plan.RemotePort != 0 {
+		target += ":" + plan.RemotePort.String()
+	}
+	return target
+}
+
+// renderDryRunHooks prints the merged lifecycle hooks of a command in
+// execution order, labelled by phase.
+func renderDryRunHooks(w io.Writer, hooks []commandsvc.DryRunHookPlan, indent string) {
+	for i, hook := range hooks {
+		fmt.Fprintf(w, "%s%d. %s (runtime: %s)\n", indent, i+1, hook.Phase, hook.Runtime)
+		detail := indent + "   "
+		if hook.Script.IsFile() {
+			fmt.Fprintf(w, "%sScript: (file: %s)\n", detail, *hook.Script.File)
+			continue
+		}
+		for line := range strings.SplitSeq(string(hook.Script.Content), "\n") {
+			fmt.Fprintf(w, "%s%s\n", detail, line)
+		}
+	}
+}
+
 func renderDryRunVirtualSafety(w io.Writer, plan commandsvc.DryRunPlan) {
-	if plan.Runtime != invowkfile.RuntimeVirtualSh && plan.Runtime != invowkfile.RuntimeVirtualLua {
+	if !plan.Runtime.IsVirtual() {
 		return
 	}
 	fmt.Fprintln(w)
@@ -124,6 +302,9 @@ func renderDryRunVirtualSafety(w io.Writer, plan commandsvc.DryRunPlan) {
 			fmt.Fprintf(w, "    LuaMemoryLimit: %s\n", plan.LuaMemoryLimit)
 		}
 	}
+	if plan.Runtime == invowkfile.RuntimeVirtualStarlark && plan.StarlarkMaxSteps != 0 {
+		fmt.Fprintf(w, "    StarlarkMaxSteps: %d\n", plan.StarlarkMaxSteps)
+	}
 }
 
 func dryRunAllowedBinaries(allowed []invowkfile.AllowedBinary) string {
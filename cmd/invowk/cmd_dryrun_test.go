@@ -9,6 +9,7 @@ import (
 
 	"github.com/invowk/invowk/internal/app/commandsvc"
 	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
 )
 
 func TestIsArgEnvVar(t *testing.T) {
@@ -50,13 +51,18 @@ func TestRenderDryRun_AllSections(t *testing.T) {
 
 	var buf bytes.Buffer
 	plan := commandsvc.DryRunPlan{
-		CommandName: "deploy",
-		SourceID:    "my-module.invowkmod",
-		Runtime:     invowkfile.RuntimeVirtualSh,
-		Platform:    invowkfile.PlatformLinux,
-		WorkDir:     "/app",
-		Timeout:     "30s",
-		Script:      invowkfile.ImplementationScript{Content: "echo deploying"},
+		CommandName:      "deploy",
+		SourceID:         "my-module.invowkmod",
+		Runtime:          invowkfile.RuntimeVirtualSh,
+		Platform:         invowkfile.PlatformLinux,
+		WorkDir:          "/app",
+		Selection:        `implementation #1 selected: env CI="true"`,
+		Timeout:          "30s",
+		Retry:            &invowkfile.RetryPolicy{Attempts: 3, MaxBackoff: "10s", OnExitCodes: []types.ExitCode{75, 124}},
+		SuccessExitCodes: []types.ExitCode{1},
+		ExitCodeMap:      invowkfile.ExitCodeMap{3: 10, 2: 10},
+		Freshness:        "stale because source 'main.go' changed",
+		Script:           invowkfile.ImplementationScript{Content: "echo deploying"},
 		Env: map[string]string{
 			"INVOWK_CMD_NAME": "deploy",
 			"ARG1":            "production",
@@ -75,7 +81,11 @@ func TestRenderDryRun_AllSections(t *testing.T) {
 		"Source:", "my-module.invowkmod",
 		"Runtime:", "virtual-sh",
 		"WorkDir:", "/app",
+		"Selected:", `implementation #1 selected: env CI="true"`,
 		"Timeout:", "30s",
+		"Retry:", "3 attempts, backoff 1s (max 10s), on exit codes 75, 124",
+		"ExitCodes:", "success 1; 2 -> 10, 3 -> 10",
+		"Incremental:", "stale because source 'main.go' changed",
 		"Script:",
 		"echo deploying",
 		"INVOWK_CMD_NAME=deploy",
@@ -89,6 +99,42 @@ func TestRenderDryRun_AllSections(t *testing.T) {
 	}
 }
 
+func TestRenderDryRun_RemoteSSHTarget(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	renderDryRun(&buf, commandsvc.DryRunPlan{
+		CommandName: "deploy",
+		Runtime:     invowkfile.RuntimeRemoteSSH,
+		Platform:    invowkfile.PlatformLinux,
+		RemoteHost:  "app-01.example.com",
+		RemoteUser:  "deploy",
+		RemotePort:  2222,
+		Script:      invowkfile.ImplementationScript{Content: "./restart.sh"},
+	})
+
+	if out := buf.String(); !strings.Contains(out, "deploy@app-01.example.com:2222") {
+		t.Errorf("renderDryRun output missing remote target:\n%s", out)
+	}
+}
+
+func TestRenderDryRun_NativeSandbox(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	renderDryRun(&buf, commandsvc.DryRunPlan{
+		CommandName: "lint",
+		Runtime:     invowkfile.RuntimeNative,
+		Platform:    invowkfile.PlatformLinux,
+		Sandbox:     &invowkfile.RuntimeSandboxConfig{},
+		Script:      invowkfile.ImplementationScript{Content: "golangci-lint run"},
+	})
+
+	if out := buf.String(); !strings.Contains(out, "network none, filesystem restricted") {
+		t.Errorf("renderDryRun output missing sandbox summary:\n%s", out)
+	}
+}
+
 func TestRenderDryRun_VirtualFilesystemAndHostBinaryPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -136,6 +182,21 @@ func TestRenderDryRun_VirtualFilesystemAndHostBinaryPolicy(t *testing.T) {
 				"DATA=./data",
 			},
 		},
+		{
+			name: "starlark step limit",
+			plan: commandsvc.DryRunPlan{
+				CommandName:      "hermetic",
+				SourceID:         "invowkfile",
+				Runtime:          invowkfile.RuntimeVirtualStarlark,
+				Platform:         invowkfile.PlatformLinux,
+				Script:           invowkfile.ImplementationScript{Content: "print('hermetic')"},
+				StarlarkMaxSteps: 100000,
+			},
+			want: []string{
+				"HostBinaries: deny-all",
+				"StarlarkMaxSteps: 100000",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -175,6 +236,9 @@ func TestRenderDryRun_NoImpl(t *testing.T) {
 	if strings.Contains(out, "Timeout:") {
 		t.Error("Timeout should not appear when impl is nil")
 	}
+	if strings.Contains(out, "Incremental:") {
+		t.Error("Incremental should not appear without declared sources")
+	}
 	if strings.Contains(out, "Script: invowkfile.ImplementationScript{Content: ") {
 		t.Error("}Script should not appear when impl is nil")
 	}
@@ -325,3 +389,205 @@ func TestRenderDryRun_EphemeralContainer(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderDryRun_Steps(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	plan := commandsvc.DryRunPlan{
+		CommandName: "release",
+		SourceID:    "invowkfile",
+		Platform:    invowkfile.PlatformLinux,
+		Steps: []commandsvc.DryRunStepPlan{
+			{
+				Label: "build",
+				Cmd:   "build",
+				Plan: commandsvc.DryRunPlan{
+					CommandName: "build",
+					SourceID:    "invowkfile",
+					Runtime:     invowkfile.RuntimeNative,
+					Timeout:     "5m",
+					Script:      invowkfile.ImplementationScript{Content: "go build ./..."},
+				},
+			},
+			{
+				Label: "ship",
+				Cmd:   "@tools publish",
+				Plan: commandsvc.DryRunPlan{
+					CommandName: "tools publish",
+					Steps: []commandsvc.DryRunStepPlan{{
+						Label: "script",
+						Plan: commandsvc.DryRunPlan{
+							Runtime: invowkfile.RuntimeVirtualSh,
+							Script:  invowkfile.ImplementationScript{Content: "echo upload"},
+						},
+					}},
+				},
+			},
+			{
+				Label:           "notify",
+				ContinueOnError: true,
+				Plan: commandsvc.DryRunPlan{
+					Runtime: invowkfile.RuntimeVirtualSh,
+					Script:  invowkfile.ImplementationScript{Content: "echo done"},
+				},
+			},
+		},
+		DependencyValidationSkipped: true,
+	}
+
+	renderDryRun(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"per step",
+		"Steps:",
+		"1. build\n",
+		"Command: build (source: invowkfile)",
+		"Timeout: 5m",
+		"go build ./...",
+		"2. ship (cmd: @tools publish)",
+		"       1. script",
+		"echo upload",
+		"3. notify [continue on error]",
+		"Runtime: virtual-sh",
+		"dependency validation",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderDryRun() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Environment") {
+		t.Errorf("step plan should not render a top-level environment section:\n%s", out)
+	}
+}
+
+func TestRenderDryRun_Prerequisites(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	plan := commandsvc.DryRunPlan{
+		CommandName:                 "deploy",
+		SourceID:                    "invowkfile",
+		Runtime:                     invowkfile.RuntimeNative,
+		Platform:                    invowkfile.PlatformLinux,
+		Script:                      invowkfile.ImplementationScript{Content: "./deploy.sh"},
+		Prerequisites:               []invowkfile.CommandName{"build", "test"},
+		DependencyValidationSkipped: true,
+	}
+
+	renderDryRun(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Prerequisites (not executed):",
+		"    1. build\n",
+		"    2. test\n",
+		"./deploy.sh",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderDryRun() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Prerequisites") > strings.Index(out, "Script:") {
+		t.Errorf("prerequisites should render before the script:\n%s", out)
+	}
+}
+
+func TestRenderDryRun_Secrets(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	plan := commandsvc.DryRunPlan{
+		CommandName: "deploy",
+		SourceID:    "invowkfile",
+		Runtime:     invowkfile.RuntimeNative,
+		Platform:    invowkfile.PlatformLinux,
+		Script:      invowkfile.ImplementationScript{Content: "./deploy.sh"},
+		Secrets: map[invowkfile.EnvVarName]invowkfile.SecretProviderType{
+			"DB_PASS": invowkfile.SecretProviderFile,
+			"TOKEN":   invowkfile.SecretProviderCommand,
+		},
+	}
+
+	renderDryRun(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Secrets (resolved at execution time):",
+		"    DB_PASS (from file)\n    TOKEN (from command)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderDryRun() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderDryRun_Matrix(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	plan := commandsvc.DryRunPlan{
+		CommandName: "test",
+		SourceID:    "invowkfile",
+		Runtime:     invowkfile.RuntimeVirtualSh,
+		Platform:    invowkfile.PlatformLinux,
+		Script:      invowkfile.ImplementationScript{Content: "go test ./..."},
+		Env:         map[string]string{"INVOWK_MATRIX_GO": "1.22"},
+		Matrix: []invowkfile.MatrixCombination{
+			{"go": "1.22"},
+			{"go": "1.23"},
+		},
+		MatrixParallel: true,
+	}
+
+	renderDryRun(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Matrix (2 combinations, in parallel; plan shows the first):",
+		"    1. go=1.22\n",
+		"    2. go=1.23\n",
+		"INVOWK_MATRIX_GO=1.22",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderDryRun() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderDryRun_Hooks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	plan := commandsvc.DryRunPlan{
+		CommandName: "deploy",
+		SourceID:    "invowkfile",
+		Runtime:     invowkfile.RuntimeNative,
+		Platform:    invowkfile.PlatformLinux,
+		Script:      invowkfile.ImplementationScript{Content: "./deploy.sh"},
+		Hooks: []commandsvc.DryRunHookPlan{
+			{Phase: invowkfile.HookPhaseBefore, Runtime: invowkfile.RuntimeNative, Script: invowkfile.ImplementationScript{Content: "echo start"}},
+			{Phase: invowkfile.HookPhaseFinally, Runtime: invowkfile.RuntimeVirtualSh, Script: invowkfile.ImplementationScript{Content: "rm -rf tmp"}},
+		},
+		DependencyValidationSkipped: true,
+	}
+
+	renderDryRun(&buf, plan)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Hooks (not executed):",
+		"    1. before (runtime: native)\n",
+		"       echo start\n",
+		"    2. finally (runtime: virtual-sh)\n",
+		"       rm -rf tmp\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderDryRun() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Hooks") > strings.Index(out, "Script:") {
+		t.Errorf("hooks should render before the script:\n%s", out)
+	}
+}
@@ -133,6 +133,7 @@ func buildCommandExecuteRequest(cmd *cobra.Command, rootFlags *rootFlagValues, c
 		VerboseSet:      verboseSet,
 		FromSource:      opts.FromSource,
 		ForceRebuild:    cmdFlags.forceRebuild,
+		Force:           cmdFlags.force,
 		ContainerName:   invowkfile.ContainerName(cmdFlags.containerName),
 		Workdir:         invowkfile.WorkDir(stringFlagValue(cmd, "ivk-workdir")),
 		EnvFiles:        toDotenvFilePaths(stringArrayFlagValue(cmd, "ivk-env-file")),
@@ -145,6 +146,7 @@ func buildCommandExecuteRequest(cmd *cobra.Command, rootFlags *rootFlagValues, c
 		EnvInheritAllow: toEnvVarNames(stringArrayFlagValue(cmd, "ivk-env-inherit-allow")),
 		EnvInheritDeny:  toEnvVarNames(stringArrayFlagValue(cmd, "ivk-env-inherit-deny")),
 		DryRun:          cmdFlags.dryRun,
+		Yes:             cmdFlags.yes,
 		ResolvedCommand: opts.ResolvedCommand,
 	}, nil
 }
@@ -189,7 +191,14 @@ func flagValueFromCobra(cmd *cobra.Command, flag invowkfile.Flag) (string, bool)
 			return "", false
 		}
 		return fmt.Sprintf("%g", floatVal), true
-	case invowkfile.FlagTypeString:
+	case invowkfile.FlagTypeList:
+		// List values cross into the command service as JSON string arrays.
+		listVal, err := cmd.Flags().GetStringArray(name)
+		if err != nil {
+			return "", false
+		}
+		return invowkfile.EncodeListValue(listVal), true
+	case invowkfile.FlagTypeString, invowkfile.FlagTypeDuration, invowkfile.FlagTypePath, invowkfile.FlagTypeJSON:
 		stringVal, err := cmd.Flags().GetString(name)
 		if err != nil {
 			return "", false
@@ -42,6 +42,10 @@ func (s *fakeAmbiguityCommandService) ResolveFromSource(_ context.Context, req E
 	return &discovery.CommandInfo{Name: "build", SimpleName: "build"}, req, nil, nil
 }
 
+func (*fakeAmbiguityCommandService) Complete(context.Context, CompletionRequest) ([]CompletionCandidate, error) {
+	return nil, nil
+}
+
 // TestCreateRuntimeSession_ContainerInitializationIsScoped verifies that
 // runtime setup returns independent sessions so cleanup and provisioning state
 // stay scoped to each execution.
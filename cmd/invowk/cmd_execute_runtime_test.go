@@ -180,7 +180,7 @@ func TestResolveRuntime(t *testing.T) {
 				&lookupDiscoveryService{lookup: discovery.LookupResult{Command: tt.command()}},
 				func() map[string]string { return nil },
 				testConfigFallback,
-				commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil),
+				commandsvc.NewPorts(nil, testRuntimeRegistryFactory(t), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 			)
 			result, _, err := svc.Execute(t.Context(), tt.request)
 			if err != nil {
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/invowk/invowk/internal/tui"
+	"github.com/invowk/invowk/internal/tuiclient"
+	"github.com/invowk/invowk/internal/tuiwire"
+	"github.com/invowk/invowk/pkg/invowkfile"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var errEmptyPromptOptions = errors.New("prompt has no options to choose from")
+
+type (
+	// InputPrompter asks for the values of missing required arguments and flags
+	// that declare a prompt block.
+	InputPrompter interface {
+		// Available reports whether prompts can be shown. When it returns false,
+		// missing values are reported by the usual input validation errors.
+		Available() bool
+		// Prompt shows the prompt and returns the raw answer.
+		Prompt(ctx context.Context, req InputPrompt) (string, error)
+	}
+
+	// InputPrompt describes one prompt for a missing argument or flag value.
+	InputPrompt struct {
+		Config *invowkfile.PromptConfig
+		// Options holds the values offered by "choose" and "filter" prompts.
+		Options []string
+		// DirsOnly makes "file" prompts pick a directory (path values of kind "dir").
+		DirsOnly bool
+	}
+
+	// terminalInputPrompter renders prompts and command confirmation questions
+	// with the TUI components, delegating to the parent TUI server when invowk
+	// runs inside an interactive session.
+	terminalInputPrompter struct{}
+)
+
+// Available reports whether a TUI server is reachable or both stdin and
+// stdout are terminals, so prompts never block CI or piped input.
+func (terminalInputPrompter) Available() bool {
+	if tuiclient.NewClientFromEnv() != nil {
+		return true
+	}
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Prompt renders the component selected by the prompt type.
+func (terminalInputPrompter) Prompt(ctx context.Context, req InputPrompt) (string, error) {
+	cfg := req.Config
+	if (cfg.Type == invowkfile.PromptTypeChoose || cfg.Type == invowkfile.PromptTypeFilter) && len(req.Options) == 0 {
+		return "", errEmptyPromptOptions
+	}
+	if client := tuiclient.NewClientFromEnv(); client != nil {
+		return promptWithClient(ctx, client, req)
+	}
+	return promptDirect(req)
+}
+
+// Confirm asks the confirmation question of a command with the confirm
+// component, delegating to the parent TUI server when one is reachable.
+func (terminalInputPrompter) Confirm(ctx context.Context, message string, defaultYes bool) (bool, error) {
+	if client := tuiclient.NewClientFromEnv(); client != nil {
+		return client.ConfirmContext(ctx, tuiwire.ConfirmRequest{Title: message, Default: defaultYes})
+	}
+	return tui.Confirm(tui.ConfirmOptions{Title: message, Default: defaultYes})
+}
+
+func promptWithClient(ctx context.Context, client *tuiclient.Client, req InputPrompt) (string, error) {
+	cfg := req.Config
+	switch cfg.Type {
+	case invowkfile.PromptTypeChoose:
+		return client.ChooseSingleContext(ctx, tuiwire.ChooseRequest{Title: cfg.Message, Options: req.Options})
+	case invowkfile.PromptTypeConfirm:
+		confirmed, err := client.ConfirmContext(ctx, tuiwire.ConfirmRequest{Title: cfg.Message})
+		return strconv.FormatBool(confirmed), err
+	case invowkfile.PromptTypeWrite:
+		return client.TextAreaContext(ctx, tuiwire.TextAreaRequest{Title: cfg.Message, Placeholder: cfg.Placeholder})
+	case invowkfile.PromptTypeFilter:
+		results, err := client.FilterContext(ctx, tuiwire.FilterRequest{Title: cfg.Message, Options: req.Options, Limit: 1})
+		return firstPromptResult(results), err
+	case invowkfile.PromptTypeFile:
+		startDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		return client.FileContext(ctx, tuiwire.FileRequest{
+			Title:       cfg.Message,
+			Path:        startDir,
+			ShowFiles:   !req.DirsOnly,
+			ShowDirs:    req.DirsOnly,
+			AllowedExts: cfg.Extensions,
+		})
+	case invowkfile.PromptTypeInput:
+		return client.InputContext(ctx, tuiwire.InputRequest{Title: cfg.Message, Placeholder: cfg.Placeholder, CharLimit: cfg.CharLimit})
+	default:
+		return "", cfg.Type.Validate()
+	}
+}
+
+func promptDirect(req InputPrompt) (string, error) {
+	cfg := req.Config
+	switch cfg.Type {
+	case invowkfile.PromptTypeChoose:
+		return tui.ChooseStrings(cfg.Message, req.Options, tui.DefaultConfig())
+	case invowkfile.PromptTypeConfirm:
+		confirmed, err := tui.Confirm(tui.ConfirmOptions{Title: cfg.Message})
+		return strconv.FormatBool(confirmed), err
+	case invowkfile.PromptTypeWrite:
+		return tui.Write(tui.WriteOptions{Title: cfg.Message, Placeholder: cfg.Placeholder})
+	case invowkfile.PromptTypeFilter:
+		results, err := tui.Filter(tui.FilterOptions{Title: cfg.Message, Options: req.Options, Limit: 1})
+		return firstPromptResult(results), err
+	case invowkfile.PromptTypeFile:
+		// Start from an absolute directory so the answer does not get resolved
+		// against the invowkfile directory like a relative CLI value would.
+		startDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		return tui.File(tui.FileOptions{
+			Title:             cfg.Message,
+			CurrentDirectory:  startDir,
+			FileAllowed:       !req.DirsOnly,
+			DirAllowed:        req.DirsOnly,
+			AllowedExtensions: cfg.Extensions,
+		})
+	case invowkfile.PromptTypeInput:
+		return tui.Input(tui.InputOptions{Title: cfg.Message, Placeholder: cfg.Placeholder, CharLimit: cfg.CharLimit})
+	default:
+		return "", cfg.Type.Validate()
+	}
+}
+
+//goplint:ignore -- TUI components return raw selections.
+func firstPromptResult(results []string) string {
+	if len(results) == 0 {
+		return ""
+	}
+	return results[0]
+}
+
+// resolveInputPrompts asks for required flags and arguments that were not
+// given on the command line and declare a prompt block. Values passed on the
+// command line always win. Answers are stored like CLI values, so the command
+// service validates and injects them through INVOWK_FLAG_*/INVOWK_ARG_*.
+//
+// Prompted flags are not required at the Cobra level, so their zero values
+// are dropped first: without a prompter (non-TTY), the command service then
+// reports the usual missing-flag and missing-argument errors.
+func resolveInputPrompts(ctx context.Context, cmd *cobra.Command, prompter InputPrompter, req ExecuteRequest) (ExecuteRequest, error) {
+	if !hasInputPrompts(req.FlagDefs, req.ArgDefs) {
+		return req, nil
+	}
+	available := prompter != nil && prompter.Available()
+	req.FlagValues = maps.Clone(req.FlagValues)
+
+	for i := range req.FlagDefs {
+		flag := &req.FlagDefs[i]
+		if flag.Prompt == nil || !flag.Required || cmd.Flags().Changed(string(flag.Name)) {
+			continue
+		}
+		delete(req.FlagValues, flag.Name)
+		if !available {
+			continue
+		}
+		answer, err := prompter.Prompt(ctx, InputPrompt{
+			Config:   flag.Prompt,
+			Options:  flag.Prompt.EffectiveOptions(flag.Choices),
+			DirsOnly: flag.Kind == invowkfile.PathKindDir,
+		})
+		if err != nil {
+			return req, fmt.Errorf("prompt for flag '--%s': %w", flag.Name, err)
+		}
+		if flag.GetType() == invowkfile.FlagTypeList {
+			answer = invowkfile.EncodeListValue([]string{answer})
+		}
+		if req.FlagValues == nil {
+			req.FlagValues = make(map[invowkfile.FlagName]string)
+		}
+		req.FlagValues[flag.Name] = answer
+	}
+
+	if !available {
+		return req, nil
+	}
+	// Required arguments precede optional ones, so missing values are filled
+	// positionally until the first argument that cannot be prompted for.
+	for i := len(req.Args); i < len(req.ArgDefs); i++ {
+		arg := &req.ArgDefs[i]
+		if arg.Prompt == nil || !arg.Required {
+			break
+		}
+		answer, err := prompter.Prompt(ctx, InputPrompt{
+			Config:   arg.Prompt,
+			Options:  arg.Prompt.EffectiveOptions(arg.Choices),
+			DirsOnly: arg.Kind == invowkfile.PathKindDir,
+		})
+		if err != nil {
+			return req, fmt.Errorf("prompt for argument '%s': %w", arg.Name, err)
+		}
+		req.Args = append(slices.Clip(req.Args), answer)
+	}
+	return req, nil
+}
+
+func hasInputPrompts(flagDefs []invowkfile.Flag, argDefs []invowkfile.Argument) bool {
+	return slices.ContainsFunc(flagDefs, func(flag invowkfile.Flag) bool { return flag.Prompt != nil }) ||
+		slices.ContainsFunc(argDefs, func(arg invowkfile.Argument) bool { return arg.Prompt != nil })
+}
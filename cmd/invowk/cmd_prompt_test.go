@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+
+	"github.com/spf13/cobra"
+)
+
+type fakeInputPrompter struct {
+	available bool
+	answers   map[string]string
+	err       error
+	asked     []string
+}
+
+func (p *fakeInputPrompter) Available() bool { return p.available }
+
+func (p *fakeInputPrompter) Prompt(_ context.Context, req InputPrompt) (string, error) {
+	p.asked = append(p.asked, req.Config.Message)
+	if p.err != nil {
+		return "", p.err
+	}
+	return p.answers[req.Config.Message], nil
+}
+
+func TestResolveInputPrompts(t *testing.T) {
+	t.Parallel()
+
+	flagDefs := []invowkfile.Flag{
+		{Name: "force", Description: "Force", Type: invowkfile.FlagTypeBool, Required: true, Prompt: &invowkfile.PromptConfig{Type: invowkfile.PromptTypeConfirm, Message: "Force?"}},
+		{Name: "tag", Description: "Tags", Type: invowkfile.FlagTypeList, Required: true, Choices: []string{"a", "b"}, Prompt: &invowkfile.PromptConfig{Type: invowkfile.PromptTypeFilter, Message: "Tag?"}},
+		{Name: "verbose", Description: "Verbose", Type: invowkfile.FlagTypeBool},
+	}
+	argDefs := []invowkfile.Argument{
+		{Name: "env", Description: "Env", Required: true, Prompt: &invowkfile.PromptConfig{Type: invowkfile.PromptTypeInput, Message: "Env?"}},
+		{Name: "region", Description: "Region", Required: true, Prompt: &invowkfile.PromptConfig{Type: invowkfile.PromptTypeInput, Message: "Region?"}},
+		{Name: "extra", Description: "Extra"},
+	}
+	answers := map[string]string{"Force?": "true", "Tag?": "b", "Env?": "prod", "Region?": "eu"}
+
+	tests := []struct {
+		name       string
+		cliFlags   []string
+		cliArgs    []string
+		available  bool
+		wantAsked  []string
+		wantFlags  map[invowkfile.FlagName]string
+		wantArgs   []string
+		promptErr  error
+		wantErrMsg string
+	}{
+		{
+			name:      "prompts for every missing required input",
+			available: true,
+			wantAsked: []string{"Force?", "Tag?", "Env?", "Region?"},
+			wantFlags: map[invowkfile.FlagName]string{"force": "true", "tag": `["b"]`, "verbose": "false"},
+			wantArgs:  []string{"prod", "eu"},
+		},
+		{
+			name:      "command line values win",
+			cliFlags:  []string{"--force=false", "--tag", "a"},
+			cliArgs:   []string{"staging"},
+			available: true,
+			wantAsked: []string{"Region?"},
+			wantFlags: map[invowkfile.FlagName]string{"force": "false", "tag": `["a"]`, "verbose": "false"},
+			wantArgs:  []string{"staging", "eu"},
+		},
+		{
+			name:      "no terminal leaves values missing",
+			cliArgs:   []string{"staging"},
+			wantFlags: map[invowkfile.FlagName]string{"verbose": "false"},
+			wantArgs:  []string{"staging"},
+		},
+		{
+			name:       "prompt failure aborts",
+			available:  true,
+			promptErr:  errors.New("cancelled"),
+			wantAsked:  []string{"Force?"},
+			wantErrMsg: "prompt for flag '--force': cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			leaf := buildLeafCommand(nil, nil, nil, &discovery.CommandInfo{
+				Name:        "deploy",
+				SimpleName:  "deploy",
+				SourceID:    discovery.SourceIDInvowkfile,
+				FilePath:    types.FilesystemPath("invowkfile.cue"),
+				Description: "Deploy",
+				Command:     &invowkfile.Command{Name: "deploy", Flags: flagDefs, Args: argDefs},
+			}, "deploy")
+			if err := leaf.ParseFlags(tt.cliFlags); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			req := ExecuteRequest{
+				Name:       "deploy",
+				Args:       tt.cliArgs,
+				FlagValues: flagValuesFromCobra(leaf, flagDefs),
+				FlagDefs:   flagDefs,
+				ArgDefs:    argDefs,
+			}
+			prompter := &fakeInputPrompter{available: tt.available, answers: answers, err: tt.promptErr}
+
+			got, err := resolveInputPrompts(t.Context(), leaf, prompter, req)
+			if tt.wantErrMsg != "" {
+				if err == nil || err.Error() != tt.wantErrMsg {
+					t.Fatalf("resolveInputPrompts() error = %v, want %q", err, tt.wantErrMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("resolveInputPrompts() error = %v", err)
+			}
+			if !slices.Equal(prompter.asked, tt.wantAsked) {
+				t.Fatalf("asked %q, want %q", prompter.asked, tt.wantAsked)
+			}
+			if tt.wantErrMsg != "" {
+				return
+			}
+			if !maps.Equal(got.FlagValues, tt.wantFlags) {
+				t.Fatalf("FlagValues = %v, want %v", got.FlagValues, tt.wantFlags)
+			}
+			if !slices.Equal(got.Args, tt.wantArgs) {
+				t.Fatalf("Args = %q, want %q", got.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildLeafCommandPromptedFlagsAreNotCobraRequired(t *testing.T) {
+	t.Parallel()
+
+	flagDefs := []invowkfile.Flag{
+		{Name: "force", Description: "Force", Type: invowkfile.FlagTypeBool, Required: true, Prompt: &invowkfile.PromptConfig{Type: invowkfile.PromptTypeConfirm, Message: "Force?"}},
+		{Name: "env", Description: "Env", Required: true},
+	}
+	leaf := buildLeafCommand(nil, nil, nil, &discovery.CommandInfo{
+		Name:        "deploy",
+		SimpleName:  "deploy",
+		SourceID:    discovery.SourceIDInvowkfile,
+		FilePath:    types.FilesystemPath("invowkfile.cue"),
+		Description: "Deploy",
+		Command:     &invowkfile.Command{Name: "deploy", Flags: flagDefs},
+	}, "deploy")
+
+	if _, ok := leaf.Flags().Lookup("force").Annotations[cobra.BashCompOneRequiredFlag]; ok {
+		t.Fatal("prompted flag 'force' is marked required at the Cobra level")
+	}
+	if _, ok := leaf.Flags().Lookup("env").Annotations[cobra.BashCompOneRequiredFlag]; !ok {
+		t.Fatal("flag 'env' is not marked required at the Cobra level")
+	}
+}
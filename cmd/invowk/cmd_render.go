@@ -5,9 +5,13 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/invowk/invowk/internal/app/commandsvc"
 	"github.com/invowk/invowk/internal/app/deps"
 	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/tui"
+	"github.com/invowk/invowk/pkg/invowkfile"
 
 	"charm.land/lipgloss/v2"
 )
@@ -34,6 +38,83 @@ func RenderFlagValidationError(err *deps.FlagValidationError) string {
 	return sb.String()
 }
 
+// RenderFlagGroupError creates a styled error message when the flags passed to
+// a command violate one of its flag groups.
+//
+//plint:render
+func RenderFlagGroupError(cmdName invowkfile.CommandName, err error) string {
+	var sb strings.Builder
+
+	sb.WriteString(renderHeaderStyle.Render("✗ Invalid flag combination!"))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Command %s received flags its flag groups do not allow.\n\n", renderCommandStyle.Render("'"+string(cmdName)+"'"))
+	sb.WriteString(renderLabelStyle.Render("Flag group errors:"))
+	sb.WriteString("\n")
+	sb.WriteString(renderValueStyle.Render("  • " + err.Error()))
+	sb.WriteString("\n\n")
+	sb.WriteString(renderHintStyle.Render("Run the command with --help for its flag groups."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderConfirmationRequiredError creates a styled error message when a
+// command asks for confirmation and no terminal is available to answer it.
+//
+//plint:render
+func RenderConfirmationRequiredError(err *commandsvc.ConfirmationRequiredError) string {
+	var sb strings.Builder
+
+	sb.WriteString(renderHeaderStyle.Render("✗ Confirmation required!"))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Command %s asks for confirmation before it runs:\n\n", renderCommandStyle.Render("'"+string(err.CommandName)+"'"))
+	sb.WriteString(renderValueStyle.Render("  " + err.Message))
+	sb.WriteString("\n\n")
+	sb.WriteString(renderHintStyle.Render("No terminal is available to answer. Pass --ivk-yes to confirm."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderPrivateCommandError creates a styled error message when a private
+// command is run from outside its module.
+//
+//plint:render
+func RenderPrivateCommandError(err *commandsvc.PrivateCommandError) string {
+	var sb strings.Builder
+
+	sb.WriteString(renderHeaderStyle.Render("✗ Private command!"))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Command %s is private to %s.\n\n", renderCommandStyle.Render("'"+string(err.CommandName)+"'"), renderValueStyle.Render(string(err.SourceID)))
+	sb.WriteString(renderHintStyle.Render("Private commands run only as dependencies, steps, or nested invocations of commands from the same module."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderCommandLockedError creates a styled error message when the lock of a
+// command is held by another process.
+//
+//plint:render
+func RenderCommandLockedError(err *commandsvc.CommandLockedError) string {
+	var sb strings.Builder
+
+	sb.WriteString(renderHeaderStyle.Render("✗ Command locked!"))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Command %s did not run: lock %s is held by %s",
+		renderCommandStyle.Render("'"+string(err.CommandName)+"'"),
+		renderValueStyle.Render("'"+string(err.Lock)+"'"),
+		renderValueStyle.Render(err.Holder.String()))
+	if err.Waited > 0 {
+		fmt.Fprintf(&sb, " (waited %s)", err.Waited)
+	}
+	sb.WriteString(".\n\n")
+	sb.WriteString(renderHintStyle.Render("Run the command again once the other run has finished, or declare lock.wait to wait for it."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
 // RenderArgumentValidationError creates a styled error message for argument validation failures
 //
 //plint:render
@@ -231,6 +312,77 @@ func RenderRuntimeNotAllowedError(cmdName, selectedRuntime, allowedRuntimes stri
 	return sb.String()
 }
 
+// RenderDeprecationWarning creates a styled warning for an invoked deprecated command
+//
+//plint:render
+func RenderDeprecationWarning(event commandsvc.DeprecationEvent) string {
+	var sb strings.Builder
+
+	sb.WriteString(renderWarningHeaderStyle.Render("! Deprecated command!"))
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "The command %s is deprecated: %s\n", renderCommandStyle.Render("'"+string(event.CommandName)+"'"), event.Message)
+	if event.Replacement != "" {
+		sb.WriteString("\n")
+		sb.WriteString(renderLabelStyle.Render("Replacement: "))
+		sb.WriteString(renderCommandStyle.Render(string(event.Replacement)))
+		sb.WriteString("\n")
+	}
+	if event.Forwarded {
+		sb.WriteString(renderHintStyle.Render(fmt.Sprintf("Running '%s' instead.", event.Replacement)))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// RenderMatrixSummary creates the pass/fail table printed after the last
+// combination of a matrix command, followed by the errors of the combinations
+// that could not run to completion.
+//
+//plint:render
+func RenderMatrixSummary(summary commandsvc.MatrixSummary) string {
+	var sb strings.Builder
+
+	failures := 0
+	rows := make([][]string, 0, len(summary.Outcomes))
+	for _, outcome := range summary.Outcomes {
+		result := "pass"
+		switch {
+		case outcome.Err != nil:
+			result = "error"
+		case outcome.ExitCode != 0:
+			result = fmt.Sprintf("fail (exit %d)", outcome.ExitCode)
+		}
+		if outcome.Failed() {
+			failures++
+		}
+		duration := "-"
+		if outcome.Duration > 0 {
+			duration = outcome.Duration.Round(time.Millisecond).String()
+		}
+		rows = append(rows, []string{outcome.Combination.Label(), result, duration})
+	}
+
+	command := renderCommandStyle.Render("'" + string(summary.CommandName) + "'")
+	if failures > 0 {
+		fmt.Fprintf(&sb, "%s %d of %d combinations of %s failed\n", ErrorStyle.Render("✗"), failures, len(summary.Outcomes), command)
+	} else {
+		fmt.Fprintf(&sb, "%s All %d combinations of %s passed\n", SuccessStyle.Render("✓"), len(summary.Outcomes), command)
+	}
+	sb.WriteString(tui.RenderTable(tui.TableOptions{
+		Columns: []tui.TableColumn{{Title: "Combination"}, {Title: "Result"}, {Title: "Duration"}},
+		Rows:    rows,
+	}))
+	sb.WriteString("\n")
+	for _, outcome := range summary.Outcomes {
+		if outcome.Err != nil {
+			fmt.Fprintf(&sb, "%s %s: %v\n", ErrorStyle.Render("✗"), outcome.Combination.Label(), outcome.Err)
+		}
+	}
+
+	return sb.String()
+}
+
 // RenderSourceNotFoundError creates a styled error message when a specified source doesn't exist.
 //
 //plint:render
@@ -3,10 +3,19 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/invowk/invowk/internal/app/commandsvc"
+	appexec "github.com/invowk/invowk/internal/app/execute"
 	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/issue"
 	"github.com/invowk/invowk/pkg/invowkfile"
 	"github.com/invowk/invowk/pkg/types"
 )
@@ -159,6 +168,61 @@ func TestRenderAmbiguousCommandError_ShowsExampleWithFirstSource(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// RenderDeprecationWarning tests
+// ---------------------------------------------------------------------------
+
+func TestRenderDeprecationWarning(t *testing.T) {
+	t.Parallel()
+
+	output := RenderDeprecationWarning(commandsvc.DeprecationEvent{
+		CommandName: "ship",
+		Message:     "renamed to deploy",
+		Replacement: "deploy",
+		Forwarded:   true,
+	})
+	for _, want := range []string{"Deprecated command", "ship", "renamed to deploy", "Replacement", "Running 'deploy' instead."} {
+		if !strings.Contains(output, want) {
+			t.Errorf("RenderDeprecationWarning() = %q, want it to contain %q", output, want)
+		}
+	}
+
+	output = RenderDeprecationWarning(commandsvc.DeprecationEvent{CommandName: "old", Message: "going away"})
+	if strings.Contains(output, "Replacement") || strings.Contains(output, "instead") {
+		t.Errorf("RenderDeprecationWarning() = %q, want no replacement without one", output)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RenderMatrixSummary tests
+// ---------------------------------------------------------------------------
+
+func TestRenderMatrixSummary(t *testing.T) {
+	t.Parallel()
+
+	output := RenderMatrixSummary(commandsvc.MatrixSummary{
+		CommandName: "test",
+		Outcomes: []appexec.MatrixOutcome{
+			{Combination: invowkfile.MatrixCombination{"go": "1.22"}, Duration: 1500 * time.Millisecond},
+			{Combination: invowkfile.MatrixCombination{"go": "1.23"}, ExitCode: 2, Duration: time.Second},
+			{Combination: invowkfile.MatrixCombination{"go": "1.24"}, ExitCode: 1, Err: errors.New("runtime unavailable")},
+		},
+	})
+	for _, want := range []string{"2 of 3 combinations", "'test'", "Combination", "go=1.22", "pass", "1.5s", "fail (exit 2)", "error", "go=1.24: runtime unavailable"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("RenderMatrixSummary() = %q, want it to contain %q", output, want)
+		}
+	}
+
+	output = RenderMatrixSummary(commandsvc.MatrixSummary{
+		CommandName: "test",
+		Outcomes:    []appexec.MatrixOutcome{{Combination: invowkfile.MatrixCombination{"go": "1.22"}}},
+	})
+	if !strings.Contains(output, "All 1 combinations") {
+		t.Errorf("RenderMatrixSummary() = %q, want a passing headline", output)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // formatSourceDisplayName tests
 // ---------------------------------------------------------------------------
@@ -236,3 +300,179 @@ func TestBuildLeafCommandAcceptsArbitraryArgs(t *testing.T) {
 		t.Errorf("leaf command should accept arbitrary args for service validation, got: %v", err)
 	}
 }
+
+func TestBuildLeafCommandChoicesCompletion(t *testing.T) {
+	t.Parallel()
+
+	cmdInfo := &discovery.CommandInfo{
+		Name:        "deploy",
+		SimpleName:  "deploy",
+		SourceID:    discovery.SourceIDInvowkfile,
+		FilePath:    types.FilesystemPath("invowkfile.cue"),
+		Description: "Deploy",
+		Command: &invowkfile.Command{
+			Name: "deploy",
+			Flags: []invowkfile.Flag{
+				{Name: "region", Description: "Target region", Choices: []string{"eu", "us"}},
+			},
+			Args: []invowkfile.Argument{
+				{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}},
+				{Name: "services", Description: "Services", Variadic: true, Choices: []string{"api", "web"}},
+			},
+		},
+	}
+	leaf := buildLeafCommand(nil, nil, nil, cmdInfo, "deploy")
+
+	if got := leaf.Flags().Lookup("region").Usage; got != "Target region (one of: eu, us)" {
+		t.Errorf("region flag usage = %q", got)
+	}
+	completeFlag, ok := leaf.GetFlagCompletionFunc("region")
+	if !ok {
+		t.Fatal("region flag has no completion function")
+	}
+	if got, _ := completeFlag(leaf, nil, ""); !slices.Equal(got, []string{"eu", "us"}) {
+		t.Errorf("region completions = %v, want [eu us]", got)
+	}
+
+	if leaf.ValidArgsFunction == nil {
+		t.Fatal("leaf command has no ValidArgsFunction")
+	}
+	tests := []struct {
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{args: nil, toComplete: "", want: []string{"dev", "prod"}},
+		{args: nil, toComplete: "p", want: []string{"prod"}},
+		{args: []string{"dev"}, toComplete: "", want: []string{"api", "web"}},
+		{args: []string{"dev", "api"}, toComplete: "w", want: []string{"web"}},
+	}
+	for _, tt := range tests {
+		got, directive := leaf.ValidArgsFunction(leaf, tt.args, tt.toComplete)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ValidArgsFunction(%v, %q) = %v, want %v", tt.args, tt.toComplete, got, tt.want)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("ValidArgsFunction(%v, %q) directive = %v, want NoFileComp", tt.args, tt.toComplete, directive)
+		}
+	}
+}
+
+func TestBuildLeafCommandTypedFlags(t *testing.T) {
+	t.Parallel()
+
+	flagDefs := []invowkfile.Flag{
+		{Name: "tag", Description: "Tags", Type: invowkfile.FlagTypeList, DefaultValue: `["latest"]`},
+		{Name: "host", Description: "Hosts", Type: invowkfile.FlagTypeList},
+		{Name: "wait", Description: "Wait time", Type: invowkfile.FlagTypeDuration, DefaultValue: "30s"},
+		{Name: "out", Description: "Output dir", Type: invowkfile.FlagTypePath},
+	}
+	cmdInfo := &discovery.CommandInfo{
+		Name:        "deploy",
+		SimpleName:  "deploy",
+		SourceID:    discovery.SourceIDInvowkfile,
+		FilePath:    types.FilesystemPath("invowkfile.cue"),
+		Description: "Deploy",
+		Command:     &invowkfile.Command{Name: "deploy", Flags: flagDefs},
+	}
+	leaf := buildLeafCommand(nil, nil, nil, cmdInfo, "deploy")
+
+	if got := flagValuesFromCobra(leaf, flagDefs); got["tag"] != `["latest"]` || got["host"] != "" || got["wait"] != "30s" {
+		t.Fatalf("default flag values = %v", got)
+	}
+	if err := leaf.ParseFlags([]string{"--host", "a", "--host", "b,c", "--out", "dist"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	got := flagValuesFromCobra(leaf, flagDefs)
+	if got["host"] != `["a","b,c"]` || got["out"] != "dist" {
+		t.Fatalf("parsed flag values = %v", got)
+	}
+}
+
+func TestBuildLeafCommandDocumentsAliasesAndDeprecation(t *testing.T) {
+	t.Parallel()
+
+	cmdInfo := &discovery.CommandInfo{
+		Name:        "ship",
+		SimpleName:  "ship",
+		SourceID:    discovery.SourceIDInvowkfile,
+		FilePath:    types.FilesystemPath("invowkfile.cue"),
+		Description: "Ship",
+		Command: &invowkfile.Command{
+			Name:       "ship",
+			Aliases:    []invowkfile.CommandName{"sh", "s"},
+			Deprecated: &invowkfile.Deprecation{Message: "renamed to deploy", Replacement: "deploy"},
+		},
+	}
+	leaf := buildLeafCommand(nil, nil, nil, cmdInfo, "ship")
+
+	for _, want := range []string{"Deprecated: renamed to deploy", "Aliases: sh, s"} {
+		if !strings.Contains(leaf.Long, want) {
+			t.Errorf("Long = %q, want it to contain %q", leaf.Long, want)
+		}
+	}
+}
+
+func TestBuildLeafCommandEnforcesFlagGroups(t *testing.T) {
+	t.Parallel()
+
+	cmdInfo := &discovery.CommandInfo{
+		Name:       "push",
+		SimpleName: "push",
+		SourceID:   discovery.SourceIDInvowkfile,
+		FilePath:   types.FilesystemPath("invowkfile.cue"),
+		Command: &invowkfile.Command{
+			Name: "push",
+			Flags: []invowkfile.Flag{
+				{Name: "tag", Description: "Image tag"},
+				{Name: "latest", Description: "Push latest", Type: invowkfile.FlagTypeBool},
+			},
+			FlagGroups: &invowkfile.FlagGroups{
+				MutuallyExclusive: []invowkfile.FlagGroup{{"tag", "latest"}},
+				OneRequired:       []invowkfile.FlagGroup{{"tag", "latest"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		set     map[string]string
+		wantErr string
+	}{
+		{name: "one flag", set: map[string]string{"tag": "v1"}},
+		{name: "exclusive flags", set: map[string]string{"tag": "v1", "latest": "true"}, wantErr: "none of the others can be"},
+		{name: "no flag", set: nil, wantErr: "at least one of the flags in the group [tag latest] is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stderr bytes.Buffer
+			leaf := buildLeafCommand(&App{stderr: &stderr}, nil, nil, cmdInfo, "push")
+			if !strings.Contains(leaf.Long, "mutually exclusive     --tag, --latest") {
+				t.Errorf("Long = %q, want the flag groups documented", leaf.Long)
+			}
+			for name, value := range tt.set {
+				if err := leaf.Flags().Set(name, value); err != nil {
+					t.Fatalf("Set(%s) error = %v", name, err)
+				}
+			}
+
+			err := leaf.PreRunE(leaf, nil)
+			if tt.wantErr == "" {
+				if err != nil || stderr.Len() != 0 {
+					t.Fatalf("PreRunE() error = %v, stderr = %q; want success", err, stderr.String())
+				}
+				return
+			}
+			svcErr, ok := errors.AsType[*ServiceError](err)
+			if !ok || svcErr.IssueID != issue.FlagGroupViolatedId || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("PreRunE() error = %v, want a flag group ServiceError containing %q", err, tt.wantErr)
+			}
+			if !strings.Contains(stderr.String(), "Invalid flag combination") {
+				t.Errorf("stderr = %q, want the rendered flag group error", stderr.String())
+			}
+		})
+	}
+}
@@ -194,6 +194,10 @@ func (s *recordingDynamicCommandService) ResolveFromSource(_ context.Context, re
 	return nil, req, nil, nil
 }
 
+func (*recordingDynamicCommandService) Complete(context.Context, CompletionRequest) ([]CompletionCandidate, error) {
+	return nil, nil
+}
+
 func dynamicConfigCommandSet(t *testing.T, tmpDir string) *discovery.DiscoveredCommandSet {
 	t.Helper()
 
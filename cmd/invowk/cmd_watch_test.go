@@ -117,6 +117,10 @@ func (s *fakeWatchCommandService) ResolveFromSource(_ context.Context, req Execu
 	return s.cmdInfo, req, nil, nil
 }
 
+func (*fakeWatchCommandService) Complete(context.Context, CompletionRequest) ([]CompletionCandidate, error) {
+	return nil, nil
+}
+
 func newResolvedWatchCommand(t *testing.T) *discovery.CommandInfo {
 	t.Helper()
 
@@ -18,6 +18,7 @@ func newInternalCommand(app *App, rootFlags *rootFlagValues) *cobra.Command {
 
 	cmd.AddCommand(newInternalExecShCommand())
 	cmd.AddCommand(newInternalExecLuaCommand())
+	cmd.AddCommand(newInternalExecSandboxedCommand())
 	cmd.AddCommand(newInternalCheckCmdCommand(app, rootFlags))
 
 	return cmd
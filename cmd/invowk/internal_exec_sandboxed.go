@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/invowk/invowk/internal/sandbox"
+
+	"github.com/spf13/cobra"
+)
+
+// newInternalExecSandboxedCommand creates the `invowk internal exec-sandboxed` command.
+// The native runtime starts it in new Linux namespaces for implementations with
+// a sandbox block; it finishes the sandbox setup and runs the wrapped command.
+func newInternalExecSandboxedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "exec-sandboxed -- <command> [args...]",
+		Short:  "Run a command inside the native runtime sandbox (internal use only)",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		RunE:   runInternalExecSandboxed,
+	}
+
+	cmd.Flags().String(sandbox.SpecJSONFlag, "{}", "sandbox spec as JSON object")
+
+	return cmd
+}
+
+// runInternalExecSandboxed applies the sandbox spec and runs the command,
+// exiting with the command's exit code.
+//
+//goplint:ignore -- Cobra adapter receives raw argv/flag strings and validates before delegating to sandbox.
+func runInternalExecSandboxed(cmd *cobra.Command, args []string) error {
+	specJSON, _ := cmd.Flags().GetString(sandbox.SpecJSONFlag)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	var spec sandbox.Spec
+	if err := json.Unmarshal([]byte(specJSON), &spec); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing sandbox spec: %v\n", err)
+		return &ExitError{Code: 1}
+	}
+
+	exitCode, err := sandbox.Init(spec, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running sandboxed command: %v\n", err)
+	}
+	if exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+	return nil
+}
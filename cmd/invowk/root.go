@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"syscall"
 
 	"github.com/charmbracelet/fang"
 	"github.com/spf13/cobra"
@@ -81,7 +83,9 @@ be organized hierarchically with support for dependencies.
 }
 
 // Execute runs the invowk CLI. It creates the App with default dependencies,
-// builds the Cobra command tree, and uses fang for graceful signal handling.
+// builds the Cobra command tree, and runs it through fang. SIGINT and SIGTERM
+// cancel the root command context instead of killing the process, so running
+// commands are stopped and their finally hooks still run.
 // Non-zero exit codes from ExitError are propagated to os.Exit.
 func Execute() {
 	app, err := NewApp(Dependencies{})
@@ -92,12 +96,10 @@ func Execute() {
 
 	rootCmd := NewRootCommand(app)
 
-	if err := fang.Execute(
-		context.Background(),
-		rootCmd,
-		fang.WithVersion(getVersionString(Version, Commit, BuildDate)),
-		fang.WithNotifySignal(os.Interrupt),
-	); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = fang.Execute(ctx, rootCmd, fang.WithVersion(getVersionString(Version, Commit, BuildDate)))
+	stop()
+	if err != nil {
 		if exitErr, ok := errors.AsType[*ExitError](err); ok {
 			os.Exit(int(exitErr.Code))
 		}
@@ -53,6 +53,11 @@ var (
 				Bold(true).
 				Foreground(ColorError).
 				MarginBottom(1)
+	// renderWarningHeaderStyle is for warning card headers (bold amber).
+	renderWarningHeaderStyle = lipgloss.NewStyle().
+					Bold(true).
+					Foreground(ColorWarning).
+					MarginBottom(1)
 	// renderCommandStyle is for command names in error cards (bold blue).
 	renderCommandStyle = lipgloss.NewStyle().
 				Bold(true).
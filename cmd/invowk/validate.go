@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/invowk/invowk/internal/discovery"
 	"github.com/invowk/invowk/pkg/invowkfile"
 	"github.com/invowk/invowk/pkg/invowkmod"
 	"github.com/invowk/invowk/pkg/types"
@@ -110,7 +111,14 @@ Examples:
 				return runWorkspaceValidation(cmd, app)
 			}
 
-			return runPathValidation(cmd, args[0])
+			// Module-qualified templates resolve against the modules discovery
+			// finds from the current directory, as they do at run time.
+			modules, err := app.Discovery.DiscoverModules(cmd.Context())
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s Module discovery failed, module templates are unavailable: %s\n", moduleWarningIcon, err)
+			}
+
+			return runPathValidation(cmd, args[0], modules.Modules)
 		},
 	}
 }
@@ -198,8 +206,9 @@ func runWorkspaceValidation(cmd *cobra.Command, app *App) error {
 	return nil
 }
 
-// runPathValidation validates a single path, auto-detecting its type.
-func runPathValidation(cmd *cobra.Command, targetPath string) error {
+// runPathValidation validates a single path, auto-detecting its type. modules
+// are the discovered modules whose templates the target may extend.
+func runPathValidation(cmd *cobra.Command, targetPath string, modules []*discovery.DiscoveredFile) error {
 	absPath, err := filepath.Abs(targetPath)
 	if err != nil {
 		return fmt.Errorf(failedResolvePathFmt, err)
@@ -209,9 +218,9 @@ func runPathValidation(cmd *cobra.Command, targetPath string) error {
 
 	switch pt {
 	case pathTypeModule:
-		return runModulePathValidation(cmd, resolvedPath)
+		return runModulePathValidation(cmd, resolvedPath, modules)
 	case pathTypeInvowkfile:
-		return runInvowkfilePathValidation(cmd, resolvedPath)
+		return runInvowkfilePathValidation(cmd, resolvedPath, modules)
 	case pathTypeUnknown:
 		return fmt.Errorf("cannot determine file type for %s: expected invowkfile.cue, *.invowkmod directory, or directory containing invowkfile.cue", targetPath)
 	default:
@@ -258,7 +267,7 @@ func detectPathType(absPath string) (detected pathType, resolvedPath string) {
 }
 
 // runInvowkfilePathValidation validates a single invowkfile and renders styled output.
-func runInvowkfilePathValidation(cmd *cobra.Command, invowkfilePath string) error {
+func runInvowkfilePathValidation(cmd *cobra.Command, invowkfilePath string, modules []*discovery.DiscoveredFile) error {
 	stdout := cmd.OutOrStdout()
 	stderr := cmd.ErrOrStderr()
 
@@ -272,7 +281,7 @@ func runInvowkfilePathValidation(cmd *cobra.Command, invowkfilePath string) erro
 	fmt.Fprintln(stdout)
 
 	// Parse the invowkfile (CUE schema + structural validation).
-	inv, err := invowkfile.Parse(types.FilesystemPath(invowkfilePath))
+	inv, err := invowkfile.Parse(types.FilesystemPath(invowkfilePath), invowkfile.WithTemplateModules(discovery.TemplateModules(nil, modules)))
 	if err != nil {
 		fmt.Fprintf(stderr, "%s CUE schema validation failed\n", moduleErrorIcon)
 		fmt.Fprintln(stderr)
@@ -319,7 +328,7 @@ func runInvowkfilePathValidation(cmd *cobra.Command, invowkfilePath string) erro
 // runModulePathValidation validates a module directory and renders styled output.
 // It calls invowkmod.Validate() for structural checks, then performs deep validation
 // by parsing the module's invowkfile (if present) and validating the command tree.
-func runModulePathValidation(cmd *cobra.Command, modulePath string) error {
+func runModulePathValidation(cmd *cobra.Command, modulePath string, modules []*discovery.DiscoveredFile) error {
 	stdout := cmd.OutOrStdout()
 	stderr := cmd.ErrOrStderr()
 
@@ -347,7 +356,7 @@ func runModulePathValidation(cmd *cobra.Command, modulePath string) error {
 		if loadErr != nil {
 			result.AddIssue(invowkmod.IssueTypeInvowkmod, loadErr.Error(), invowkmodCueFileName)
 		} else {
-			inv, invErr := invowkfile.ParseLoadedModuleInvowkfile(loadedModule)
+			inv, invErr := invowkfile.ParseLoadedModuleInvowkfile(loadedModule, invowkfile.WithTemplateModules(discovery.TemplateModules(loadedModule, modules)))
 			if invErr != nil {
 				result.AddIssue(invowkmod.IssueTypeInvowkfile, invErr.Error(), "invowkfile.cue")
 			} else if inv != nil {
@@ -26,6 +26,7 @@ require (
 	github.com/sahilm/fuzzy v0.1.3
 	github.com/spf13/cobra v1.10.2
 	github.com/u-root/u-root v0.16.0
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
 	golang.org/x/sys v0.46.0
 	golang.org/x/term v0.44.0
 	mvdan.cc/sh/v3 v3.13.1
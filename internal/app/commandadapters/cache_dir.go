@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// invowkCacheDir returns the directory elem beneath the per-user invowk cache
+// directory: $XDG_CACHE_HOME/invowk (default ~/.cache/invowk) on Linux,
+// ~/Library/Caches/invowk on macOS, and %LocalAppData%\invowk on Windows. The
+// caches of the command adapters share it.
+func invowkCacheDir(elem ...string) (types.FilesystemPath, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user cache directory: %w", err)
+	}
+	return types.FilesystemPath(filepath.Join(append([]string{base, "invowk"}, elem...)...)), nil //goplint:ignore -- derived from the OS-reported cache directory.
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestDefaultCacheDirsHonourXDGCacheHome(t *testing.T) {
+	if goruntime.GOOS == "windows" || goruntime.GOOS == "darwin" || goruntime.GOOS == "ios" || goruntime.GOOS == "plan9" {
+		t.Skip("XDG_CACHE_HOME only applies to Unix-like systems")
+	}
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+
+	for name, resolve := range map[string]func() (types.FilesystemPath, error){
+		"fingerprints": DefaultFingerprintDir,
+		"locks":        DefaultRunLockDir,
+		"completions":  DefaultCompletionCacheDir,
+	} {
+		got, err := resolve()
+		if err != nil {
+			t.Fatalf("%s: error = %v", name, err)
+		}
+		if want := filepath.Join(cacheHome, "invowk", name); string(got) != want {
+			t.Errorf("%s dir = %q, want %q", name, got, want)
+		}
+	}
+}
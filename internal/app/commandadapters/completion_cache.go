@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// CompletionCacheTTL is how long cached completion candidates stay fresh.
+// Entries are scoped to a shell session already; the TTL bounds staleness in
+// long-lived shells and guards against a reused session process ID.
+const CompletionCacheTTL = 5 * time.Minute
+
+// CompletionCache caches completion candidates as one JSON file per
+// completion key under a directory per shell session. Entries older than the
+// TTL are treated as missing; saving prunes them, along with the directories
+// of sessions that saved nothing within the TTL.
+type CompletionCache struct {
+	dir     types.FilesystemPath
+	session string
+	ttl     time.Duration
+}
+
+// NewCompletionCache creates a completion cache rooted at dir for the shell
+// session whose entries stay fresh for ttl. The session directory is created
+// on the first save.
+//
+//goplint:ignore -- session is a directory name checked by Validate.
+func NewCompletionCache(dir types.FilesystemPath, session string, ttl time.Duration) *CompletionCache {
+	return &CompletionCache{dir: dir, session: session, ttl: ttl}
+}
+
+// DefaultCompletionCacheDir returns the completions directory beneath the
+// user cache directory (see invowkCacheDir).
+func DefaultCompletionCacheDir() (types.FilesystemPath, error) {
+	return invowkCacheDir("completions")
+}
+
+// CurrentCompletionSession identifies the shell session of the process.
+// Shells run completion as a child process, so the parent process ID
+// identifies the session.
+func CurrentCompletionSession() string {
+	return strconv.Itoa(os.Getppid())
+}
+
+// Validate returns an error when the cache has no directory or no TTL.
+func (c *CompletionCache) Validate() error {
+	if c == nil || c.dir == "" {
+		return errors.New("completion cache directory is required")
+	}
+	if c.ttl <= 0 {
+		return errors.New("completion cache TTL must be positive")
+	}
+	if c.session == "" || c.session != filepath.Base(c.session) || c.session == "." || c.session == ".." {
+		return fmt.Errorf("invalid completion cache session %q", c.session)
+	}
+	return c.dir.Validate()
+}
+
+// Load returns the candidates cached for key. A missing, expired, or
+// undecodable entry is reported as not found so the script runs again and
+// overwrites it.
+func (c *CompletionCache) Load(key appexec.CompletionKey) ([]appexec.CompletionCandidate, bool, error) {
+	path, err := c.entryPath(key)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading completions %s: %w", path, err)
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		return nil, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading completions %s: %w", path, err)
+	}
+	var candidates []appexec.CompletionCandidate
+	if err := json.Unmarshal(data, &candidates); err != nil {
+		return nil, false, nil
+	}
+	return candidates, true, nil
+}
+
+// Save caches candidates under key, replacing any previous entry.
+func (c *CompletionCache) Save(key appexec.CompletionKey, candidates []appexec.CompletionCandidate) error {
+	path, err := c.entryPath(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		return fmt.Errorf("encoding completions: %w", err)
+	}
+	if err := os.MkdirAll(c.sessionDir(), 0o755); err != nil {
+		return fmt.Errorf("creating completion cache directory: %w", err)
+	}
+	if err := writeFileAtomically(path, data); err != nil {
+		return fmt.Errorf("writing completions: %w", err)
+	}
+	c.prune()
+	return nil
+}
+
+// prune removes the expired entries of the session and the directories of
+// other sessions that saved nothing within the TTL: every entry in them has
+// expired, and their shell has usually exited. Pruning is best effort; a
+// failure leaves files that the next save retries.
+func (c *CompletionCache) prune() {
+	now := time.Now()
+	expired := func(entry fs.DirEntry) bool {
+		info, err := entry.Info()
+		return err == nil && now.Sub(info.ModTime()) > c.ttl
+	}
+
+	sessions, err := os.ReadDir(string(c.dir))
+	if err != nil {
+		slog.Debug("listing completion cache sessions failed", "dir", c.dir, "error", err)
+		return
+	}
+	for _, session := range sessions {
+		if !session.IsDir() || session.Name() == c.session || !expired(session) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(string(c.dir), session.Name())); err != nil {
+			slog.Debug("removing stale completion cache session failed", "session", session.Name(), "error", err)
+		}
+	}
+
+	entries, err := os.ReadDir(c.sessionDir())
+	if err != nil {
+		slog.Debug("listing completion cache entries failed", "dir", c.sessionDir(), "error", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && expired(entry) {
+			if err := os.Remove(filepath.Join(c.sessionDir(), entry.Name())); err != nil {
+				slog.Debug("removing expired completion cache entry failed", "entry", entry.Name(), "error", err)
+			}
+		}
+	}
+}
+
+func (c *CompletionCache) sessionDir() string {
+	return filepath.Join(string(c.dir), c.session)
+}
+
+func (c *CompletionCache) entryPath(key appexec.CompletionKey) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	// Keys are hex digests, which keeps the entry name inside the cache directory.
+	if err := key.Validate(); err != nil {
+		return "", err
+	}
+	return filepath.Join(c.sessionDir(), string(key)+".json"), nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestCompletionCacheRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "completions")
+	dir := filepath.Join(root, "4242")
+	cache := NewCompletionCache(types.FilesystemPath(root), "4242", time.Minute)
+	complete := &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: "ls envs"}}
+	key := appexec.NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", complete, nil)
+
+	if got, found, err := cache.Load(key); err != nil || found || got != nil {
+		t.Fatalf("Load() before Save = (%v, %v, %v), want (nil, false, nil)", got, found, err)
+	}
+
+	want := []appexec.CompletionCandidate{{Value: "staging", Description: "Staging cluster"}, {Value: "production"}}
+	if err := cache.Save(key, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, found, err := cache.Load(key)
+	if err != nil || !found || !slices.Equal(got, want) {
+		t.Fatalf("Load() = (%v, %v, %v), want (%v, true, nil)", got, found, err, want)
+	}
+
+	// An expired entry is treated as missing so the script runs again.
+	entry := filepath.Join(dir, key.String()+".json")
+	expired := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(entry, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := cache.Load(key); err != nil || found {
+		t.Fatalf("Load() of expired entry = (found %v, %v), want (false, nil)", found, err)
+	}
+
+	// A corrupt entry is treated as missing.
+	if err := os.WriteFile(entry, []byte("["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := cache.Load(key); err != nil || found {
+		t.Fatalf("Load() of corrupt entry = (found %v, %v), want (false, nil)", found, err)
+	}
+
+	if err := cache.Save("../escape", want); !errors.Is(err, appexec.ErrInvalidCompletionKey) {
+		t.Fatalf("Save() with invalid key error = %v, want ErrInvalidCompletionKey", err)
+	}
+}
+
+func TestCompletionCacheSavePrunesStaleEntries(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cache := NewCompletionCache(types.FilesystemPath(root), "4242", time.Minute)
+	expired := time.Now().Add(-2 * time.Minute)
+	write := func(path string, mtime time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ownExpired := filepath.Join(root, "4242", "old.json")
+	write(ownExpired, expired)
+	deadSession := filepath.Join(root, "1111")
+	write(filepath.Join(deadSession, "old.json"), expired)
+	if err := os.Chtimes(deadSession, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	liveSession := filepath.Join(root, "2222")
+	write(filepath.Join(liveSession, "fresh.json"), time.Now())
+
+	complete := &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: "ls envs"}}
+	key := appexec.NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", complete, nil)
+	if err := cache.Save(key, []appexec.CompletionCandidate{{Value: "staging"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := os.Stat(ownExpired); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expired entry of the session stat error = %v, want it removed", err)
+	}
+	if _, err := os.Stat(deadSession); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale session directory stat error = %v, want it removed", err)
+	}
+	if _, err := os.Stat(filepath.Join(liveSession, "fresh.json")); err != nil {
+		t.Errorf("fresh entry of another session stat error = %v, want it kept", err)
+	}
+	if _, found, err := cache.Load(key); err != nil || !found {
+		t.Errorf("Load() after Save = (found %v, %v), want the saved entry", found, err)
+	}
+}
+
+func TestCompletionCacheValidateSession(t *testing.T) {
+	t.Parallel()
+
+	for _, session := range []string{"", ".", "..", "a/b"} {
+		cache := NewCompletionCache(types.FilesystemPath(t.TempDir()), session, time.Minute)
+		if err := cache.Validate(); err == nil {
+			t.Errorf("Validate() with session %q succeeded, want an error", session)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// FingerprintStore persists incremental-execution fingerprints as one JSON
+// file per fingerprint key under a cache directory.
+type FingerprintStore struct {
+	dir types.FilesystemPath
+}
+
+// NewFingerprintStore creates a fingerprint store rooted at dir. The directory
+// is created on the first save.
+func NewFingerprintStore(dir types.FilesystemPath) *FingerprintStore {
+	return &FingerprintStore{dir: dir}
+}
+
+// DefaultFingerprintDir returns the default fingerprint cache directory,
+// "fingerprints" in the user's invowk cache directory (see invowkCacheDir).
+func DefaultFingerprintDir() (types.FilesystemPath, error) {
+	dir, err := invowkCacheDir("fingerprints")
+	if err != nil {
+		return "", fmt.Errorf("resolving fingerprint cache directory: %w", err)
+	}
+	return dir, nil
+}
+
+// Validate returns an error when the store has no directory.
+func (s *FingerprintStore) Validate() error {
+	if s == nil || s.dir == "" {
+		return errors.New("fingerprint store directory is required")
+	}
+	return s.dir.Validate()
+}
+
+// Load returns the fingerprint recorded for key, or nil when none exists.
+// A record that cannot be decoded is treated as missing so the command runs
+// and overwrites it.
+//
+//nolint:nilnil // A nil fingerprint with nil error is the "no record" value.
+func (s *FingerprintStore) Load(key appexec.FingerprintKey) (*appexec.Fingerprint, error) {
+	path, err := s.recordPath(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fingerprint %s: %w", path, err)
+	}
+	var fingerprint appexec.Fingerprint
+	if err := json.Unmarshal(data, &fingerprint); err != nil {
+		return nil, nil
+	}
+	return &fingerprint, nil
+}
+
+// Save records fingerprint under key, replacing any previous record. The
+// record is written to a temporary file and renamed into place so concurrent
+// readers never observe a partial record.
+func (s *FingerprintStore) Save(key appexec.FingerprintKey, fingerprint appexec.Fingerprint) error {
+	path, err := s.recordPath(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return fmt.Errorf("encoding fingerprint: %w", err)
+	}
+	if err := os.MkdirAll(string(s.dir), 0o755); err != nil {
+		return fmt.Errorf("creating fingerprint cache directory: %w", err)
+	}
+	if err := writeFileAtomically(path, data); err != nil {
+		return fmt.Errorf("writing fingerprint: %w", err)
+	}
+	return nil
+}
+
+func (s *FingerprintStore) recordPath(key appexec.FingerprintKey) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	// Keys are hex digests, which keeps the record name inside the store directory.
+	if err := key.Validate(); err != nil {
+		return "", err
+	}
+	return filepath.Join(string(s.dir), string(key)+".json"), nil
+}
+
+// writeFileAtomically writes data to a temporary file next to path and renames
+// it into place, so concurrent readers never observe a partial file.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".record-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestFingerprintStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "fingerprints")
+	store := NewFingerprintStore(types.FilesystemPath(dir))
+	key := appexec.NewFingerprintKey("build", invowkfile.RuntimeNative, "/work", nil)
+
+	previous, err := store.Load(key)
+	if err != nil || previous != nil {
+		t.Fatalf("Load() before Save = (%v, %v), want (nil, nil)", previous, err)
+	}
+
+	want := appexec.Fingerprint{
+		Sources:   map[string]string{"src/main.go": "aa"},
+		Generates: map[string]string{"bin/app": "bb"},
+	}
+	if err := store.Save(key, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := store.Load(key)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got == nil || !maps.Equal(got.Sources, want.Sources) || !maps.Equal(got.Generates, want.Generates) {
+		t.Fatalf("Load() = %#v, want %#v", got, want)
+	}
+
+	// A corrupt record is treated as missing so the command runs and rewrites it.
+	if err := os.WriteFile(filepath.Join(dir, key.String()+".json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Load(key); err != nil || got != nil {
+		t.Fatalf("Load() of corrupt record = (%v, %v), want (nil, nil)", got, err)
+	}
+
+	if err := store.Save("../escape", want); !errors.Is(err, appexec.ErrInvalidFingerprintKey) {
+		t.Fatalf("Save() with invalid key error = %v, want ErrInvalidFingerprintKey", err)
+	}
+}
@@ -82,6 +82,12 @@ func (InteractiveExecutor) Execute(ctx *runtime.ExecutionContext, cmdName invowk
 		defer prepared.Cleanup()
 	}
 
+	// Secrets are resolved while the command is prepared, so the redactor is
+	// complete before the PTY produces output.
+	var secrets *runtime.SecretRedactor
+	if ctx.DeclaresSecrets() {
+		secrets = ctx.Secrets
+	}
 	interactiveResult, err := runInteractiveCmd(
 		goCtx,
 		tui.InteractiveOptions{
@@ -92,6 +98,7 @@ func (InteractiveExecutor) Execute(ctx *runtime.ExecutionContext, cmdName invowk
 			},
 		},
 		prepared.Cmd,
+		secrets,
 	)
 	if err != nil {
 		return &runtime.Result{ExitCode: types.ExitCode(1), Error: fmt.Errorf("interactive execution failed: %w", err)} //goplint:ignore -- literal exit code for interactive failure
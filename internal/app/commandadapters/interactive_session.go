@@ -3,19 +3,63 @@
 package commandadapters
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os/exec"
 	"time"
 
+	"github.com/invowk/invowk/internal/runtime"
 	"github.com/invowk/invowk/internal/tui"
 	"github.com/invowk/invowk/pkg/types"
 
 	"github.com/charmbracelet/x/xpty"
 )
 
-func runInteractiveCmd(ctx context.Context, opts tui.InteractiveOptions, cmd *exec.Cmd) (result *tui.InteractiveResult, err error) {
+type (
+	// redactingTerminal masks secret values in the output read from an
+	// interactive terminal before the TUI renders it. Input written to the
+	// terminal is forwarded unchanged.
+	redactingTerminal struct {
+		tui.InteractiveTerminal
+		writer  *runtime.RedactingWriter
+		masked  bytes.Buffer
+		readErr error
+	}
+)
+
+// newRedactingTerminal wraps terminal so that values registered with secrets
+// are masked in its output.
+func newRedactingTerminal(terminal tui.InteractiveTerminal, secrets *runtime.SecretRedactor) *redactingTerminal {
+	t := &redactingTerminal{InteractiveTerminal: terminal}
+	t.writer = secrets.Writer(&t.masked)
+	return t
+}
+
+// Read returns masked terminal output. Output that could be the start of a
+// secret is held back until the next read completes or rules it out, and is
+// flushed when the terminal reports an error such as EOF.
+func (t *redactingTerminal) Read(p []byte) (int, error) {
+	for t.masked.Len() == 0 && t.readErr == nil {
+		n, err := t.InteractiveTerminal.Read(p)
+		if n > 0 {
+			_, _ = t.writer.Write(p[:n]) // Writes to a bytes.Buffer do not fail.
+		}
+		if err != nil {
+			_ = t.writer.Flush() // Writes to a bytes.Buffer do not fail.
+			t.readErr = err
+		}
+	}
+	if t.masked.Len() > 0 {
+		return t.masked.Read(p)
+	}
+	return 0, t.readErr
+}
+
+// runInteractiveCmd runs cmd on a PTY inside the interactive TUI session. When
+// secrets is non-nil, their values are masked in the rendered output.
+func runInteractiveCmd(ctx context.Context, opts tui.InteractiveOptions, cmd *exec.Cmd, secrets *runtime.SecretRedactor) (result *tui.InteractiveResult, err error) {
 	width, height := 80, 24
 	if w, h, termErr := tui.TerminalSize(); termErr == nil {
 		width, height = int(w), int(h)
@@ -35,7 +79,11 @@ func runInteractiveCmd(ctx context.Context, opts tui.InteractiveOptions, cmd *ex
 		return nil, fmt.Errorf("failed to start command on PTY: %w", err)
 	}
 
-	return tui.RunInteractiveSession(ctx, opts, pty, func(waitCtx context.Context) tui.InteractiveResult {
+	var terminal tui.InteractiveTerminal = pty
+	if secrets != nil {
+		terminal = newRedactingTerminal(pty, secrets)
+	}
+	return tui.RunInteractiveSession(ctx, opts, terminal, func(waitCtx context.Context) tui.InteractiveResult {
 		startTime := time.Now()
 		waitErr := xpty.WaitProcess(waitCtx, cmd)
 		result := tui.InteractiveResult{Duration: time.Since(startTime)}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/invowk/invowk/internal/runtime"
+)
+
+type chunkedTerminal struct {
+	chunks  []string
+	written []string
+}
+
+func (t *chunkedTerminal) Read(p []byte) (int, error) {
+	if len(t.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, t.chunks[0])
+	t.chunks[0] = t.chunks[0][n:]
+	if t.chunks[0] == "" {
+		t.chunks = t.chunks[1:]
+	}
+	return n, nil
+}
+
+func (t *chunkedTerminal) Write(p []byte) (int, error) {
+	t.written = append(t.written, string(p))
+	return len(p), nil
+}
+
+func (*chunkedTerminal) Resize(int, int) error { return nil }
+
+func TestRedactingTerminalMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	secrets := runtime.NewSecretRedactor()
+	secrets.Add("s3cr3t-value")
+	inner := &chunkedTerminal{chunks: []string{"token=s3c", "r3t-value\n", "prompt> s3cr"}}
+	terminal := newRedactingTerminal(inner, secrets)
+
+	var out []byte
+	buf := make([]byte, 4)
+	for {
+		n, err := terminal.Read(buf)
+		out = append(out, buf[:n]...)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+	}
+	if got, want := string(out), "token=********\nprompt> s3cr"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	if _, err := terminal.Write([]byte("s3cr3t-value\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(inner.written) != 1 || inner.written[0] != "s3cr3t-value\n" {
+		t.Errorf("terminal input = %q, want it forwarded unchanged", inner.written)
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// runLockPollInterval is how often a waiting run retries a held lock.
+const runLockPollInterval = 250 * time.Millisecond
+
+// RunLocker takes command run locks as exclusive file locks, one lock file per
+// lock key. User-scoped locks live in the locker's directory, and
+// project-scoped locks in ProjectRunLockDir beside the invowkfile that
+// declares them. The holder records its process ID and start time in a file
+// next to the lock so that contenders can name it. The zero-byte lock file is
+// harmless if orphaned; the operating system releases the file lock when the
+// holder exits, including on process crash. Lock directories and files are
+// created with the permissions the umask allows, and lock files are opened
+// read-only, so users sharing a project directory can lock each other out.
+type RunLocker struct {
+	dir types.FilesystemPath
+}
+
+// NewRunLocker creates a run locker rooted at dir. The directory is created
+// on the first lock.
+func NewRunLocker(dir types.FilesystemPath) *RunLocker {
+	return &RunLocker{dir: dir}
+}
+
+// DefaultRunLockDir returns the default run lock directory, "locks" in the
+// user's invowk cache directory next to the fingerprint cache.
+func DefaultRunLockDir() (types.FilesystemPath, error) {
+	dir, err := invowkCacheDir("locks")
+	if err != nil {
+		return "", fmt.Errorf("resolving run lock directory: %w", err)
+	}
+	return dir, nil
+}
+
+// ProjectRunLockDir returns the directory of the project-scoped locks declared
+// by the invowkfile in projectDir: ".invowk/locks" beside it.
+func ProjectRunLockDir(projectDir types.FilesystemPath) types.FilesystemPath {
+	return types.FilesystemPath(filepath.Join(string(projectDir), ".invowk", "locks")) //goplint:ignore -- fixed subdirectory of a validated project directory.
+}
+
+// Validate returns an error when the locker has no directory.
+func (l *RunLocker) Validate() error {
+	if l == nil || l.dir == "" {
+		return errors.New("run lock directory is required")
+	}
+	return l.dir.Validate()
+}
+
+// TryLock takes lock without waiting. When another process holds it, TryLock
+// returns a nil release func and the holder it recorded (zero when the record
+// is missing or unreadable).
+func (l *RunLocker) TryLock(lock appexec.RunLock) (func(), appexec.RunLockHolder, error) {
+	dir, lockPath, holderPath, err := l.paths(lock)
+	if err != nil {
+		return nil, appexec.RunLockHolder{}, err
+	}
+	//nolint:gosec // G301: project lock directories are shared by the users of the project
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, appexec.RunLockHolder{}, fmt.Errorf("creating run lock directory: %w", err)
+	}
+	// Locking does not need write access, so lock files created by another
+	// user of the project can be locked too.
+	//nolint:gosec // G302: lock files are empty and shared by the users of the project
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDONLY, 0o666)
+	if err != nil {
+		return nil, appexec.RunLockHolder{}, fmt.Errorf("open lock file %s: %w", lockPath, err)
+	}
+	locked, err := tryLockFile(f)
+	if err != nil || !locked {
+		_ = f.Close()
+		if err != nil {
+			return nil, appexec.RunLockHolder{}, fmt.Errorf("lock %s: %w", lockPath, err)
+		}
+		return nil, readRunLockHolder(holderPath), nil
+	}
+
+	holder, err := json.Marshal(appexec.RunLockHolder{PID: os.Getpid(), Started: time.Now()})
+	if err == nil {
+		err = writeFileAtomically(holderPath, holder)
+	}
+	if err == nil {
+		// Contenders of other users read the record to name the holder.
+		err = os.Chmod(holderPath, 0o644) //nolint:gosec // G302: the record only holds a PID and a start time
+	}
+	if err != nil {
+		// Without a record, contenders report an unknown holder.
+		slog.Debug("failed to record run lock holder", "path", holderPath, "error", err)
+	}
+
+	release := sync.OnceFunc(func() {
+		// Remove the record before unlocking so it never names a former holder
+		// of a lock taken by the next run.
+		if err := os.Remove(holderPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Debug("failed to remove run lock holder", "path", holderPath, "error", err)
+		}
+		if err := unlockFile(f); err != nil {
+			slog.Warn("run lock unlock failed", "path", lockPath, "error", err)
+		}
+		if err := f.Close(); err != nil {
+			slog.Warn("run lock file close failed", "path", lockPath, "error", err)
+		}
+	})
+	return release, appexec.RunLockHolder{}, nil
+}
+
+// Lock waits until lock is free and takes it. It returns ctx's error when ctx
+// is done first.
+func (l *RunLocker) Lock(ctx context.Context, lock appexec.RunLock) (func(), error) {
+	ticker := time.NewTicker(runLockPollInterval)
+	defer ticker.Stop()
+	for {
+		release, _, err := l.TryLock(lock)
+		if err != nil || release != nil {
+			return release, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for run lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *RunLocker) paths(lock appexec.RunLock) (dir, lockPath, holderPath string, err error) {
+	// Keys are hex digests, which keeps the lock files inside the lock directory.
+	if err := lock.Validate(); err != nil {
+		return "", "", "", err
+	}
+	if lock.ProjectDir != "" {
+		dir = string(ProjectRunLockDir(lock.ProjectDir))
+	} else {
+		if err := l.Validate(); err != nil {
+			return "", "", "", err
+		}
+		dir = string(l.dir)
+	}
+	base := filepath.Join(dir, string(lock.Key))
+	return dir, base + ".lock", base + ".holder.json", nil
+}
+
+// readRunLockHolder returns the holder recorded at path, or the zero holder
+// when the record is missing or cannot be decoded.
+func readRunLockHolder(path string) appexec.RunLockHolder {
+	var holder appexec.RunLockHolder
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return holder
+	}
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return appexec.RunLockHolder{}
+	}
+	return holder
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandadapters
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestRunLockerExcludesOtherHolders(t *testing.T) {
+	t.Parallel()
+
+	locker := NewRunLocker(types.FilesystemPath(filepath.Join(t.TempDir(), "locks")))
+	lock := appexec.NewRunLock("db", invowkfile.LockScopeUser, "")
+
+	release, _, err := locker.TryLock(lock)
+	if err != nil || release == nil {
+		t.Fatalf("TryLock() = (%v, %v), want the lock", release != nil, err)
+	}
+
+	// Every TryLock opens its own file description, so a second attempt in
+	// the same process contends like another process would.
+	again, holder, err := locker.TryLock(lock)
+	if err != nil || again != nil {
+		t.Fatalf("TryLock() of held lock = (%v, %v), want busy", again != nil, err)
+	}
+	if holder.PID != os.Getpid() || time.Since(holder.Started) > time.Minute {
+		t.Errorf("holder = %+v, want this process, started just now", holder)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Lock(ctx, lock); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Lock() of held lock = %v, want context.DeadlineExceeded", err)
+	}
+
+	release()
+	release() // Releasing twice is a no-op.
+	next, err := locker.Lock(t.Context(), lock)
+	if err != nil {
+		t.Fatalf("Lock() after release error = %v", err)
+	}
+	next()
+}
+
+func TestRunLockerUnknownHolder(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "locks")
+	locker := NewRunLocker(types.FilesystemPath(dir))
+	lock := appexec.NewRunLock("db", invowkfile.LockScopeUser, "")
+	release, _, err := locker.TryLock(lock)
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+	defer release()
+
+	// A missing holder record is reported as an unknown holder.
+	if err := os.Remove(filepath.Join(dir, lock.Key.String()+".holder.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, holder, err := locker.TryLock(lock); err != nil || holder.Known() {
+		t.Fatalf("TryLock() holder = (%+v, %v), want unknown holder", holder, err)
+	}
+}
+
+func TestRunLockerValidate(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := NewRunLocker("").TryLock(appexec.NewRunLock("db", invowkfile.LockScopeUser, "")); err == nil {
+		t.Error("TryLock() without directory error = nil, want error")
+	}
+	locker := NewRunLocker(types.FilesystemPath(t.TempDir()))
+	if _, _, err := locker.TryLock(appexec.RunLock{Key: "../escape"}); !errors.Is(err, appexec.ErrInvalidRunLockKey) {
+		t.Errorf("TryLock() with invalid key error = %v, want ErrInvalidRunLockKey", err)
+	}
+}
+
+func TestRunLockerProjectLocksLiveInTheProject(t *testing.T) {
+	t.Parallel()
+
+	project := types.FilesystemPath(t.TempDir())
+	// Lockers of different users share nothing but the project directory.
+	alice := NewRunLocker(types.FilesystemPath(filepath.Join(t.TempDir(), "locks")))
+	bob := NewRunLocker(types.FilesystemPath(filepath.Join(t.TempDir(), "locks")))
+	lock := appexec.NewRunLock("db", invowkfile.LockScopeProject, project)
+
+	release, _, err := alice.TryLock(lock)
+	if err != nil || release == nil {
+		t.Fatalf("TryLock() = (%v, %v), want the lock", release != nil, err)
+	}
+	defer release()
+	if _, err := os.Stat(filepath.Join(string(ProjectRunLockDir(project)), lock.Key.String()+".lock")); err != nil {
+		t.Errorf("project lock file: %v", err)
+	}
+	if again, holder, err := bob.TryLock(lock); err != nil || again != nil || holder.PID != os.Getpid() {
+		t.Fatalf("TryLock() by another user = (%v, %+v, %v), want busy", again != nil, holder, err)
+	}
+	// Project locks do not need the user lock directory.
+	if again, _, err := NewRunLocker("").TryLock(lock); err != nil || again != nil {
+		t.Errorf("TryLock() without user directory = (%v, %v), want busy", again != nil, err)
+	}
+}
@@ -74,7 +74,7 @@ func (f RuntimeRegistryFactory) Create(cfg *config.Config, hostAccess commandsvc
 	session := &runtimeSession{
 		registry: runtime.NewRegistry(),
 		cleanup: func() {
-			// Native and virtual runtimes do not allocate registry resources.
+			// Native, virtual, and remote-ssh runtimes do not allocate registry resources.
 		},
 		dependencyProbeFactory: NewDependencyRuntimeProbeFactory(),
 	}
@@ -87,6 +87,8 @@ func (f RuntimeRegistryFactory) Create(cfg *config.Config, hostAccess commandsvc
 		cfg.Virtual.Utilities.Enabled,
 		runtime.WithLuaInteractiveCommandFactory(luaInteractiveCommand),
 	))
+	session.registry.Register(runtime.RuntimeTypeVirtualStarlark, runtime.NewStarlarkRuntime(cfg.Virtual.Utilities.Enabled))
+	session.registry.Register(runtime.RuntimeTypeRemoteSSH, runtime.NewRemoteSSHRuntime())
 
 	if !shouldInitializeContainerRuntime(selectedRuntime) {
 		return session
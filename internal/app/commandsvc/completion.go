@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidCompletionRequest is the sentinel error wrapped by InvalidCompletionRequestError.
+	ErrInvalidCompletionRequest = errors.New("invalid completion request")
+
+	// ErrNoCompletionScript is returned when the completed flag or argument
+	// does not exist or declares no complete block.
+	ErrNoCompletionScript = errors.New("no completion script")
+
+	// ErrCompletionScriptFailed is returned when a completion script exits
+	// with a non-zero code.
+	ErrCompletionScriptFailed = errors.New("completion script failed")
+)
+
+type (
+	//goplint:validate-all
+	//
+	// CompletionRequest asks for the completion candidates of one flag or
+	// positional argument of a command. Request carries the command and the
+	// flags and args typed so far; exactly one of Flag and Arg names the
+	// value being completed.
+	CompletionRequest struct {
+		Request Request
+		Flag    invowkfile.FlagName
+		Arg     invowkfile.ArgumentName
+	}
+
+	// InvalidCompletionRequestError is returned when a CompletionRequest has
+	// invalid fields. It wraps ErrInvalidCompletionRequest for errors.Is()
+	// compatibility and collects field-level validation errors.
+	InvalidCompletionRequestError struct {
+		FieldErrors []error
+	}
+)
+
+// Validate returns nil if the request is valid and names exactly one valid
+// flag or argument, or an error collecting all field-level validation failures.
+func (r CompletionRequest) Validate() error {
+	var errs []error
+	if err := r.Request.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	switch {
+	case (r.Flag == "") == (r.Arg == ""):
+		errs = append(errs, errors.New("exactly one of flag and arg must be set"))
+	case r.Flag != "":
+		if err := r.Flag.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		if err := r.Arg.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &InvalidCompletionRequestError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidCompletionRequestError.
+func (e *InvalidCompletionRequestError) Error() string {
+	return types.FormatFieldErrors("completion request", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidCompletionRequest for errors.Is() compatibility.
+func (e *InvalidCompletionRequestError) Unwrap() error { return ErrInvalidCompletionRequest }
+
+// Complete runs the completion script of a flag or argument and returns its
+// candidates. The script runs like a lifecycle hook, in the context of the
+// command with the flags and args typed so far, but with captured output and
+// the complete block's timeout. Candidates are served from the completion
+// cache when the same script already ran for the same values; a cache that
+// cannot be read or written only costs a script run.
+func (s *Service) Complete(ctx context.Context, req CompletionRequest) ([]appexec.CompletionCandidate, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = s.beginRequest(ctx, req.Request.ConfigPath)
+	cmdReq := req.Request
+	if cmdReq.UserEnv == nil && s.userEnvFunc != nil {
+		cmdReq.UserEnv = s.userEnvFunc()
+	}
+	if cmdReq.Platform == "" {
+		cmdReq.Platform = invowkfile.CurrentPlatform()
+	}
+
+	cfg, cmdInfo, cmdReq, _, err := s.discoverCommand(ctx, cmdReq)
+	if err != nil {
+		return nil, err
+	}
+	complete, target := req.completionConfig(cmdInfo.Command)
+	if complete == nil {
+		return nil, fmt.Errorf("%w for %s of command '%s'", ErrNoCompletionScript, target, cmdInfo.Name)
+	}
+	timeout, err := complete.EffectiveTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := appexec.NewRuntimeSelection(complete.EffectiveRuntime(), requestPlatform(cmdReq), complete.ScriptImplementation())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	execCtx, err := s.buildExecContext(ctx, cmdReq, cmdInfo, s.resolveDefinitions(cmdReq, cmdInfo), selection)
+	if err != nil {
+		return nil, err
+	}
+
+	key := appexec.NewCompletionKey(cmdInfo.FilePath, cmdInfo.Name, target, complete, dryRunEnv(execCtx))
+	cached, found, err := s.completionCache().Load(key)
+	if err != nil {
+		slog.Debug("failed to load cached completions", "command", cmdInfo.Name, "target", target, "error", err)
+	}
+	if found {
+		return cached, nil
+	}
+
+	ioCtx, stdout, stderr := runtime.CaptureIO()
+	execCtx.IO = ioCtx
+	session := s.registryFactory.Create(cfg, s.hostAccess, execCtx.SelectedRuntime)
+	defer session.Close()
+	execCtx.ExecutionID = session.NewExecutionID()
+
+	result := session.Execute(execCtx) //nolint:contextcheck // execCtx carries the timeout context.
+	if result.Error != nil {
+		return nil, fmt.Errorf("completion script for %s of command '%s': %w", target, cmdInfo.Name, result.Error)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("%w: %s of command '%s' exited with code %d: %s", ErrCompletionScriptFailed, target, cmdInfo.Name, result.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	candidates := appexec.ParseCompletionCandidates(stdout.String())
+	if err := s.completionCache().Save(key, candidates); err != nil {
+		slog.Debug("failed to cache completions", "command", cmdInfo.Name, "target", target, "error", err)
+	}
+	return candidates, nil
+}
+
+// completionConfig returns the complete block of the requested flag or
+// argument of cmd (nil when it declares none) and a label naming it.
+func (r CompletionRequest) completionConfig(cmd *invowkfile.Command) (complete *invowkfile.CompletionConfig, target string) {
+	if r.Flag != "" {
+		if i := slices.IndexFunc(cmd.Flags, func(flag invowkfile.Flag) bool { return flag.Name == r.Flag }); i >= 0 {
+			complete = cmd.Flags[i].Complete
+		}
+		return complete, fmt.Sprintf("flag '%s'", r.Flag)
+	}
+	if i := slices.IndexFunc(cmd.Args, func(arg invowkfile.Argument) bool { return arg.Name == r.Arg }); i >= 0 {
+		complete = cmd.Args[i].Complete
+	}
+	return complete, fmt.Sprintf("argument '%s'", r.Arg)
+}
+
+func (s *Service) completionCache() CompletionCache {
+	if s.completions == nil {
+		return noopCompletionCache{}
+	}
+	return s.completions
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	// completionScriptRuntime prints the script content as completion output
+	// and exits with the code registered for the script.
+	completionScriptRuntime struct {
+		exitCodes map[invowkfile.ScriptContent]types.ExitCode
+		runs      int
+	}
+
+	memoryCompletionCache struct {
+		entries map[appexec.CompletionKey][]appexec.CompletionCandidate
+	}
+)
+
+func (*completionScriptRuntime) Name() string { return string(invowkfile.RuntimeNative) }
+
+func (r *completionScriptRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	r.runs++
+	script := execCtx.SelectedImpl.Script.Content
+	if code := r.exitCodes[script]; code != 0 {
+		_, _ = fmt.Fprint(execCtx.IO.Stderr, "cluster unreachable")
+		return &runtimepkg.Result{ExitCode: code}
+	}
+	_, _ = fmt.Fprint(execCtx.IO.Stdout, script)
+	return &runtimepkg.Result{}
+}
+
+func (*completionScriptRuntime) Available() bool { return true }
+
+func (*completionScriptRuntime) Validate(*runtimepkg.ExecutionContext) error { return nil }
+
+func (c *memoryCompletionCache) Load(key appexec.CompletionKey) ([]appexec.CompletionCandidate, bool, error) {
+	candidates, found := c.entries[key]
+	return candidates, found, nil
+}
+
+func (c *memoryCompletionCache) Save(key appexec.CompletionKey, candidates []appexec.CompletionCandidate) error {
+	c.entries[key] = candidates
+	return nil
+}
+
+func TestServiceComplete(t *testing.T) {
+	t.Parallel()
+
+	service, rt := newCompletionTestService(t)
+
+	want := []appexec.CompletionCandidate{{Value: "prod", Description: "Production"}, {Value: "staging"}}
+	for range 2 {
+		got, err := service.Complete(t.Context(), CompletionRequest{Request: Request{Name: "deploy"}, Flag: "namespace"})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Complete() = %v, want %v", got, want)
+		}
+	}
+	if rt.runs != 1 {
+		t.Errorf("completion script runs = %d, want 1 (second call served from cache)", rt.runs)
+	}
+
+	got, err := service.Complete(t.Context(), CompletionRequest{Request: Request{Name: "deploy"}, Arg: "service"})
+	if err != nil {
+		t.Fatalf("Complete(arg) error = %v", err)
+	}
+	if want := []appexec.CompletionCandidate{{Value: "api"}, {Value: "web"}}; !slices.Equal(got, want) {
+		t.Errorf("Complete(arg) = %v, want %v", got, want)
+	}
+}
+
+func TestServiceCompleteErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     CompletionRequest
+		wantErr error
+	}{
+		{
+			name:    "neither flag nor arg",
+			req:     CompletionRequest{Request: Request{Name: "deploy"}},
+			wantErr: ErrInvalidCompletionRequest,
+		},
+		{
+			name:    "both flag and arg",
+			req:     CompletionRequest{Request: Request{Name: "deploy"}, Flag: "namespace", Arg: "service"},
+			wantErr: ErrInvalidCompletionRequest,
+		},
+		{
+			name:    "flag without complete block",
+			req:     CompletionRequest{Request: Request{Name: "deploy"}, Flag: "verbose"},
+			wantErr: ErrNoCompletionScript,
+		},
+		{
+			name:    "unknown argument",
+			req:     CompletionRequest{Request: Request{Name: "deploy"}, Arg: "missing"},
+			wantErr: ErrNoCompletionScript,
+		},
+		{
+			name:    "script exits non-zero",
+			req:     CompletionRequest{Request: Request{Name: "deploy"}, Flag: "cluster"},
+			wantErr: ErrCompletionScriptFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, _ := newCompletionTestService(t)
+			if _, err := service.Complete(t.Context(), tt.req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Complete() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newCompletionTestService(t *testing.T) (*Service, *completionScriptRuntime) {
+	t.Helper()
+
+	service, _, _ := newHookTestService(t, nil, nil, "deploy", "")
+	rt := &completionScriptRuntime{exitCodes: map[invowkfile.ScriptContent]types.ExitCode{"clusters": 2}}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeNative, rt)
+	service.registryFactory = staticRuntimeRegistryFactory{registry: registry}
+	service.completions = &memoryCompletionCache{entries: map[appexec.CompletionKey][]appexec.CompletionCandidate{}}
+
+	stub, ok := service.discovery.(*stubCommandDiscovery)
+	if !ok {
+		t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+	}
+	deploy := stub.commandSet.Set.Commands[0].Command
+	deploy.Flags = []invowkfile.Flag{
+		{Name: "namespace", Description: "Namespace", Complete: completionScript("prod\tProduction\nstaging\n")},
+		{Name: "cluster", Description: "Cluster", Complete: completionScript("clusters")},
+		{Name: "verbose", Description: "Verbose output", Type: invowkfile.FlagTypeBool},
+	}
+	deploy.Args = []invowkfile.Argument{
+		{Name: "service", Description: "Service", Complete: completionScript("api\nweb\n")},
+	}
+	return service, rt
+}
+
+func completionScript(content invowkfile.ScriptContent) *invowkfile.CompletionConfig {
+	return &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: content}}
+}
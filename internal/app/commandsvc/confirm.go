@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+var (
+	// ErrConfirmationRequired is returned when a command declares a confirm
+	// block, no question can be asked, and the request does not answer yes.
+	ErrConfirmationRequired = errors.New("confirmation required")
+
+	// ErrConfirmationDeclined is returned when the user answers no to the
+	// confirmation question of a command.
+	ErrConfirmationDeclined = errors.New("confirmation declined")
+)
+
+// ConfirmationRequiredError is returned when a command that declares a
+// confirm block runs without a terminal and without Request.Yes. It wraps
+// ErrConfirmationRequired for errors.Is() compatibility.
+type ConfirmationRequiredError struct {
+	CommandName invowkfile.CommandName
+	// Message is the confirmation question with its placeholders rendered.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ConfirmationRequiredError) Error() string {
+	return fmt.Sprintf("command '%s' requires confirmation (%s) but no terminal is available; pass --ivk-yes to confirm", e.CommandName, e.Message)
+}
+
+// Unwrap returns ErrConfirmationRequired for errors.Is() compatibility.
+func (e *ConfirmationRequiredError) Unwrap() error { return ErrConfirmationRequired }
+
+// confirmExecution asks the confirmation question of a command that declares
+// a confirm block, before any prerequisite, dependency check, or script runs.
+// Requests that answer yes up front (--ivk-yes) and dry runs, which execute
+// nothing, are not asked.
+func (s *Service) confirmExecution(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo) error {
+	confirm := cmdInfo.Command.Confirm
+	if confirm == nil || req.Yes || req.DryRun {
+		return nil
+	}
+	facts := whenContext(req, cmdInfo)
+	message := confirm.RenderMessage(facts.Flags, facts.Args)
+	confirmer := s.confirmationPrompter()
+	if !confirmer.Available() {
+		return &ConfirmationRequiredError{CommandName: cmdInfo.Name, Message: message}
+	}
+	confirmed, err := confirmer.Confirm(ctx, message, confirm.Default)
+	if err != nil {
+		return fmt.Errorf("confirm command '%s': %w", cmdInfo.Name, err)
+	}
+	if !confirmed {
+		return fmt.Errorf("%w: command '%s' was not run", ErrConfirmationDeclined, cmdInfo.Name)
+	}
+	return nil
+}
+
+func (s *Service) confirmationPrompter() Confirmer {
+	if s.confirmer == nil {
+		return unavailableConfirmer{}
+	}
+	return s.confirmer
+}
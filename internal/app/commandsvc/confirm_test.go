@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+type recordingConfirmer struct {
+	available bool
+	answer    bool
+	messages  []string
+	defaults  []bool
+}
+
+func (c *recordingConfirmer) Available() bool { return c.available }
+
+func (c *recordingConfirmer) Confirm(_ context.Context, message string, defaultYes bool) (bool, error) {
+	c.messages = append(c.messages, message)
+	c.defaults = append(c.defaults, defaultYes)
+	return c.answer, nil
+}
+
+func TestServiceExecuteConfirm(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		confirmer    *recordingConfirmer
+		req          Request
+		wantAsked    bool
+		wantRan      bool
+		wantErr      error
+		wantRequired bool
+	}{
+		{
+			name:      "confirmed",
+			confirmer: &recordingConfirmer{available: true, answer: true},
+			req:       Request{Name: "deploy", FlagValues: map[invowkfile.FlagName]string{"env": "prod"}},
+			wantAsked: true,
+			wantRan:   true,
+		},
+		{
+			name:      "declined",
+			confirmer: &recordingConfirmer{available: true},
+			req:       Request{Name: "deploy", FlagValues: map[invowkfile.FlagName]string{"env": "prod"}},
+			wantAsked: true,
+			wantErr:   ErrConfirmationDeclined,
+		},
+		{
+			name:      "yes skips the question",
+			confirmer: &recordingConfirmer{available: true},
+			req:       Request{Name: "deploy", Yes: true},
+			wantRan:   true,
+		},
+		{
+			name:         "no terminal",
+			confirmer:    &recordingConfirmer{},
+			req:          Request{Name: "deploy", FlagValues: map[invowkfile.FlagName]string{"env": "prod"}},
+			wantErr:      ErrConfirmationRequired,
+			wantRequired: true,
+		},
+		{
+			name:      "dry run is not asked",
+			confirmer: &recordingConfirmer{},
+			req:       Request{Name: "deploy", DryRun: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, rt, _ := newHookTestService(t, nil, nil, "deploy", "")
+			service.confirmer = tt.confirmer
+			stub, ok := service.discovery.(*stubCommandDiscovery)
+			if !ok {
+				t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+			}
+			deploy := stub.commandSet.Set.Commands[0].Command
+			deploy.Flags = []invowkfile.Flag{{Name: "env", Description: "Environment", DefaultValue: "staging"}}
+			deploy.Confirm = &invowkfile.ConfirmConfig{Message: "Deploy to {{flag.env}}?", Default: true}
+
+			_, _, err := service.Execute(t.Context(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if required, ok := errors.AsType[*ConfirmationRequiredError](err); ok != tt.wantRequired {
+				t.Fatalf("Execute() error = %v, want ConfirmationRequiredError: %v", err, tt.wantRequired)
+			} else if ok && required.Message != "Deploy to prod?" {
+				t.Errorf("ConfirmationRequiredError.Message = %q, want %q", required.Message, "Deploy to prod?")
+			}
+
+			if asked := len(tt.confirmer.messages) > 0; asked != tt.wantAsked {
+				t.Fatalf("confirmation asked = %v, want %v", asked, tt.wantAsked)
+			}
+			if tt.wantAsked && (tt.confirmer.messages[0] != "Deploy to prod?" || !tt.confirmer.defaults[0]) {
+				t.Errorf("Confirm(%q, %v), want (\"Deploy to prod?\", true)", tt.confirmer.messages[0], tt.confirmer.defaults[0])
+			}
+			if ran := slices.Contains(rt.ran, "deploy"); ran != tt.wantRan {
+				t.Errorf("main script ran = %v, want %v", ran, tt.wantRan)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// handleDeprecation reports an invoked deprecated command to the observer.
+// When the deprecation forwards to its replacement, it returns the request
+// that runs the replacement in place of the command. A forwarded request is
+// never forwarded again, so replacements that are themselves deprecated only
+// warn.
+func (s *Service) handleDeprecation(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config) (forwarded bool, fwdReq Request, diags []Diagnostic, err error) {
+	dep := cmdInfo.Command.Deprecated
+	forward := dep.Forward && dep.Replacement != "" && !req.deprecationForwarded
+	s.observer.CommandDeprecated(DeprecationEvent{
+		CommandName: cmdInfo.Name,
+		Message:     dep.Message,
+		Replacement: dep.Replacement,
+		Forwarded:   forward,
+		Verbose:     req.Verbose,
+	})
+	if !forward {
+		return false, Request{}, nil, nil
+	}
+
+	// The replacement is declared in the same invowkfile, so it resolves in
+	// the deprecated command's source.
+	_, target, _, diags, err := s.discoverCommandFromSource(ctx, cfg, Request{
+		Name:       string(dep.Replacement),
+		FromSource: cmdInfo.SourceID,
+	})
+	if err != nil {
+		return false, Request{}, diags, err
+	}
+
+	fwdReq = req
+	fwdReq.caller = cmdInfo
+	fwdReq.Name = string(target.Name)
+	fwdReq.ResolvedCommand = target
+	fwdReq.FlagDefs = target.Command.Flags
+	fwdReq.ArgDefs = target.Command.Args
+	fwdReq.FlagValues = forwardedFlagValues(target, req.FlagValues)
+	fwdReq.deprecationForwarded = true
+	return true, fwdReq, diags, nil
+}
+
+// forwardedFlagValues passes the flag values of a deprecated command on to its
+// replacement. Only flags the replacement declares carry over; the others
+// start from the replacement's defaults.
+func forwardedFlagValues(target *discovery.CommandInfo, values map[invowkfile.FlagName]string) map[invowkfile.FlagName]string {
+	result := defaultFlagValues(target)
+	for _, flag := range target.Command.Flags {
+		if value, ok := values[flag.Name]; ok {
+			result[flag.Name] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type recordingDeprecationObserver struct {
+	noopExecutionObserver
+	events []DeprecationEvent
+}
+
+func (o *recordingDeprecationObserver) CommandDeprecated(event DeprecationEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestServiceExecuteDeprecatedCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		req        Request
+		wantScript invowkfile.ScriptContent
+		wantEvents []DeprecationEvent
+	}{
+		{
+			name:       "forwards to replacement",
+			req:        Request{Name: "deploy", Args: []string{"v1"}, FlagValues: map[invowkfile.FlagName]string{"target": "prod", "legacy": "x"}},
+			wantScript: "echo ship",
+			wantEvents: []DeprecationEvent{{CommandName: "deploy", Message: "use ship", Replacement: "ship", Forwarded: true}},
+		},
+		{
+			name:       "resolves aliases",
+			req:        Request{Name: "dp", Args: []string{"v1"}, FlagValues: map[invowkfile.FlagName]string{"target": "prod"}},
+			wantScript: "echo ship",
+			wantEvents: []DeprecationEvent{{CommandName: "deploy", Message: "use ship", Replacement: "ship", Forwarded: true}},
+		},
+		{
+			name:       "warns without forwarding",
+			req:        Request{Name: "old"},
+			wantScript: "echo old",
+			wantEvents: []DeprecationEvent{{CommandName: "old", Message: "going away"}},
+		},
+		{
+			name:       "verbose run",
+			req:        Request{Name: "old", Verbose: true, VerboseSet: true},
+			wantScript: "echo old",
+			wantEvents: []DeprecationEvent{{CommandName: "old", Message: "going away", Verbose: true}},
+		},
+		{
+			name:       "does not chain forwards",
+			req:        Request{Name: "older"},
+			wantScript: "echo old",
+			wantEvents: []DeprecationEvent{
+				{CommandName: "older", Message: "use old", Replacement: "old", Forwarded: true},
+				{CommandName: "old", Message: "going away"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, rt, observer := newDeprecationTestService(t)
+			result, _, err := service.Execute(t.Context(), tt.req)
+			if err != nil || result.ExitCode != 0 {
+				t.Fatalf("Execute() = %d, %v; want success", result.ExitCode, err)
+			}
+			if len(rt.ran) != 1 || rt.ran[0].script != tt.wantScript {
+				t.Fatalf("ran = %#v, want only %q", rt.ran, tt.wantScript)
+			}
+			if len(observer.events) != len(tt.wantEvents) {
+				t.Fatalf("events = %#v, want %#v", observer.events, tt.wantEvents)
+			}
+			for i, want := range tt.wantEvents {
+				if observer.events[i] != want {
+					t.Errorf("events[%d] = %#v, want %#v", i, observer.events[i], want)
+				}
+			}
+			if tt.wantScript != "echo ship" {
+				return
+			}
+			env := rt.ran[0].env
+			if env["INVOWK_FLAG_TARGET"] != "prod" || env["INVOWK_FLAG_MODE"] != "fast" || env["ARG1"] != "v1" {
+				t.Errorf("replacement env = %v, want forwarded target flag, default mode, and args", env)
+			}
+		})
+	}
+}
+
+func newDeprecationTestService(t *testing.T) (*Service, *stepRecordingRuntime, *recordingDeprecationObserver) {
+	t.Helper()
+
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue"))}
+	newCommand := func(name invowkfile.CommandName, script string) *invowkfile.Command {
+		return invowkfiletest.NewTestCommand(string(name),
+			invowkfiletest.WithScript(script),
+			invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+			invowkfiletest.WithAllPlatforms(),
+		)
+	}
+	ship := newCommand("ship", "echo ship")
+	ship.Flags = []invowkfile.Flag{{Name: "target"}, {Name: "mode", DefaultValue: "fast"}}
+	deploy := newCommand("deploy", "echo deploy")
+	deploy.Aliases = []invowkfile.CommandName{"dp"}
+	deploy.Flags = []invowkfile.Flag{{Name: "target"}, {Name: "legacy"}}
+	deploy.Deprecated = &invowkfile.Deprecation{Message: "use ship", Replacement: "ship", Forward: true}
+	old := newCommand("old", "echo old")
+	old.Deprecated = &invowkfile.Deprecation{Message: "going away"}
+	older := newCommand("older", "echo older")
+	older.Deprecated = &invowkfile.Deprecation{Message: "use old", Replacement: "old", Forward: true}
+
+	set := discovery.NewDiscoveredCommandSet()
+	for _, cmd := range []*invowkfile.Command{ship, deploy, old, older} {
+		set.Add(&discovery.CommandInfo{
+			Name:       cmd.Name,
+			SimpleName: cmd.Name,
+			SourceID:   discovery.SourceIDInvowkfile,
+			Command:    cmd,
+			Invowkfile: inv,
+		})
+	}
+	set.Analyze()
+
+	rt := &stepRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	observer := &recordingDeprecationObserver{}
+	cfg := config.DefaultConfig()
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, rt, observer
+}
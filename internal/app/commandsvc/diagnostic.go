@@ -23,6 +23,8 @@ const (
 	DiagnosticCodeContainerRuntimeInitFailed DiagnosticCode = "container_runtime_init_failed"
 	// DiagnosticCodeScriptInterpreterShebangOverride indicates script.interpreter overrides a shebang.
 	DiagnosticCodeScriptInterpreterShebangOverride DiagnosticCode = "script_interpreter_shebang_override"
+	// DiagnosticCodeFingerprintStoreFailed indicates an incremental-execution fingerprint could not be loaded or saved.
+	DiagnosticCodeFingerprintStoreFailed DiagnosticCode = "fingerprint_store_failed"
 )
 
 type (
@@ -18,7 +18,9 @@ import (
 //  2. Validates timeout string (fail-fast on invalid values).
 //  3. Wraps context with timeout.
 //  4. Validates dependencies (tools, cmds, filepaths, capabilities, custom checks, env vars).
-//  5. Dispatches to interactive mode through an adapter port or standard execution.
+//  5. Dispatches to interactive mode through an adapter port or standard execution,
+//     re-running failed attempts when the implementation declares a retry policy.
+//  6. Records the declared outputs of the run for the commands running after it.
 //
 // It returns ClassifiedError for runtime failures and raw typed errors for
 // dependency validation. The CLI adapter handles rendering.
@@ -58,7 +60,7 @@ func (s *Service) dispatchExecution(req Request, execCtx *runtime.ExecutionConte
 		s.observer.CommandStarting(cmdName)
 	}
 
-	result, interactiveFallback, err := s.executeWithRequestedMode(req, execCtx, session)
+	result, interactiveFallback, err := s.executeWithRetry(req, execCtx, cmdInfo, session)
 	if err != nil {
 		return Result{}, diags, err
 	}
@@ -70,6 +72,7 @@ func (s *Service) dispatchExecution(req Request, execCtx *runtime.ExecutionConte
 	if result.Error != nil {
 		return Result{}, diags, newClassifiedExecutionError(result.Error)
 	}
+	req.outputs.record(cmdInfo.Command.Name, result.Outputs)
 
 	return Result{ExitCode: result.ExitCode}, diags, nil
 }
@@ -125,7 +128,13 @@ func (s *Service) executeWithRequestedMode(req Request, execCtx *runtime.Executi
 		if err := cmdName.Validate(); err != nil {
 			return nil, "", fmt.Errorf("resolved interactive command name: %w", err)
 		}
-		return s.interactive.Execute(execCtx, cmdName, interactiveRT), "", nil
+		result := s.interactive.Execute(execCtx, cmdName, interactiveRT)
+		if execCtx.DeclaresSecrets() {
+			// The interactive executor masks the PTY output; the result
+			// bypasses Registry.Execute, so mask its error here.
+			result = execCtx.RedactResult(result)
+		}
+		return result, "", nil
 	}
 
 	return session.Execute(execCtx), invowkfile.RuntimeMode(rt.Name()), nil //goplint:ignore -- runtime names are registered from runtime mode constants.
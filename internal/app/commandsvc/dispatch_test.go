@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -342,6 +344,50 @@ func TestExecuteWithRequestedMode(t *testing.T) {
 	}
 }
 
+// secretInteractiveExecutor builds the command environment, as runtimes do
+// while preparing an interactive command, and fails with the resolved secret.
+type secretInteractiveExecutor struct{}
+
+func (secretInteractiveExecutor) Execute(execCtx *runtimepkg.ExecutionContext, _ invowkfile.CommandName, _ RuntimeInteractiveCommand) *runtimepkg.Result {
+	env, err := runtimepkg.NewDefaultEnvBuilder().Build(execCtx, invowkfile.EnvInheritNone)
+	if err != nil {
+		return &runtimepkg.Result{ExitCode: 1, Error: err}
+	}
+	return &runtimepkg.Result{ExitCode: 1, Error: fmt.Errorf("login rejected %s", env["TOKEN"])}
+}
+
+func TestExecuteWithRequestedModeRedactsInteractiveSecrets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(dir, "invowkfile.cue"))}
+	cmd := invowkfiletest.NewTestCommand("deploy",
+		invowkfiletest.WithScript("deploy"),
+		invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+		invowkfiletest.WithAllPlatforms(),
+	)
+	cmd.Env = &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+		"TOKEN": {Provider: invowkfile.SecretProviderFile, Path: "token"},
+	}}
+	execCtx := runtimepkg.NewExecutionContext(t.Context(), cmd, inv)
+	execCtx.SelectedRuntime = invowkfile.RuntimeVirtualSh
+	execCtx.SelectedImpl = &cmd.Implementations[0]
+
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, &stubInteractiveRuntime{stubRuntime: stubRuntime{name: "interactive"}, supports: true})
+	svc := &Service{interactive: secretInteractiveExecutor{}}
+	result, _, err := svc.executeWithRequestedMode(Request{Name: "deploy", Interactive: true}, execCtx, &testRuntimeSession{registry: registry})
+	if err != nil {
+		t.Fatalf("executeWithRequestedMode() error = %v", err)
+	}
+	if result.Error == nil || result.Error.Error() != "login rejected ********" {
+		t.Fatalf("result.Error = %v, want the secret masked", result.Error)
+	}
+}
+
 func TestNewClassifiedExecutionError(t *testing.T) {
 	t.Parallel()
 
@@ -11,6 +11,10 @@
 //  6. Dependency validation (tools, cmds, filepaths, capabilities, custom checks, env vars)
 //  7. Execution dispatch (runtime registry → timeout → deps → runtime)
 //
+// Commands that declare steps run each step in order instead of dispatching a
+// single implementation: cmd steps re-enter the pipeline for the referenced
+// command and script steps dispatch through the declaring command's context.
+//
 // The service returns raw typed errors instead of styled ServiceErrors. The CLI
 // adapter in cmd/ wraps errors with rendering (lipgloss styles, issue catalog
 // rendering). This keeps the service free of presentation concerns and avoids
@@ -25,6 +29,7 @@
 //   - service.go: Service struct, New(), Execute(), discoverCommand(), resolveDefinitions(), loadConfig()
 //   - inputs.go: validateInputs(), resolveRuntime(), buildExecContext()
 //   - dispatch.go: dispatchExecution(), runtime diagnostics, execution error classification
+//   - steps.go: executeSteps() — sequential multi-step command execution and planning
 //   - ports.go: host-access, runtime registry, and interactive-execution ports
 //   - errors.go: classifyExecutionError() — plain text error classification
 package commandsvc
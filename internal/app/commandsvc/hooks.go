@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// defaultFinallyHookTimeout bounds the finally phase, which runs detached from
+// the command's cancellation and would otherwise have no deadline at all.
+const defaultFinallyHookTimeout = time.Minute
+
+type (
+	// hookRun carries the command context shared by every lifecycle hook of
+	// one Execute call.
+	hookRun struct {
+		req     Request
+		cmdInfo *discovery.CommandInfo
+		cfg     *config.Config
+		defs    resolvedDefinitions
+		hooks   *invowkfile.Hooks
+	}
+
+	// mainRunFunc executes the command's main script (or steps) with the
+	// diagnostics collected so far.
+	mainRunFunc func([]Diagnostic) (Result, []Diagnostic, error)
+)
+
+// commandHooks returns the merged root and command lifecycle hooks of
+// cmdInfo, or nil when neither level declares a hook.
+func commandHooks(cmdInfo *discovery.CommandInfo) *invowkfile.Hooks {
+	var rootHooks *invowkfile.Hooks
+	if cmdInfo.Invowkfile != nil {
+		rootHooks = cmdInfo.Invowkfile.Hooks
+	}
+	return invowkfile.MergeHooks(rootHooks, cmdInfo.Command.Hooks)
+}
+
+// runWithHooks wraps runMain in the command's lifecycle hooks:
+//  1. before hooks run in order; the first failure skips the main run.
+//  2. runMain executes the command.
+//  3. after hooks run in order when the main run succeeded.
+//  4. finally hooks always run, on a context detached from cancellation so
+//     they still run after a timeout or an interrupt (SIGINT). The phase
+//     has its own deadline (defaultFinallyHookTimeout unless overridden).
+//
+// The first failure of phases 1-3 determines the result. A finally failure
+// determines the result only when everything before it succeeded.
+func (s *Service) runWithHooks(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, diags []Diagnostic, runMain mainRunFunc) (Result, []Diagnostic, error) {
+	hooks := commandHooks(cmdInfo)
+	if hooks == nil {
+		return runMain(diags)
+	}
+	run := hookRun{req: req, cmdInfo: cmdInfo, cfg: cfg, defs: defs, hooks: hooks}
+
+	result, diags, err := s.runHookPhase(ctx, run, invowkfile.HookPhaseBefore, diags)
+	if succeeded(result, err) {
+		result, diags, err = runMain(diags)
+		if succeeded(result, err) {
+			result, diags, err = s.runHookPhase(ctx, run, invowkfile.HookPhaseAfter, diags)
+		}
+	}
+
+	finallyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.finallyHookTimeout())
+	finallyResult, diags, finallyErr := s.runHookPhase(finallyCtx, run, invowkfile.HookPhaseFinally, diags)
+	cancel()
+	if succeeded(result, err) {
+		return finallyResult, diags, finallyErr
+	}
+	return result, diags, err
+}
+
+// finallyHookTimeout returns the deadline of the finally phase.
+func (s *Service) finallyHookTimeout() time.Duration {
+	if s.finallyTimeout > 0 {
+		return s.finallyTimeout
+	}
+	return defaultFinallyHookTimeout
+}
+
+// runHookPhase runs the hooks of one phase in declaration order. Before and
+// after phases stop at the first failing hook; the finally phase runs every
+// hook and reports the first failure.
+func (s *Service) runHookPhase(ctx context.Context, run hookRun, phase invowkfile.HookPhase, diags []Diagnostic) (Result, []Diagnostic, error) {
+	hooks := run.hooks.Phase(phase)
+	var (
+		firstResult Result
+		firstErr    error
+	)
+	for i := range hooks {
+		event := HookEvent{CommandName: run.cmdInfo.Name, Phase: phase, Index: i, Total: len(hooks)}
+		if run.req.Verbose {
+			s.observer.HookStarting(event)
+		}
+
+		result, hookDiags, err := s.executeHook(ctx, run, &hooks[i])
+		diags = append(diags, hookDiags...)
+		if succeeded(result, err) {
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("%s hook #%d of command '%s': %w", phase, i+1, run.cmdInfo.Name, err)
+		}
+		event.ExitCode = result.ExitCode
+		event.Err = err
+		s.observer.HookFailed(event)
+		if phase != invowkfile.HookPhaseFinally {
+			return result, diags, err
+		}
+		if succeeded(firstResult, firstErr) {
+			firstResult, firstErr = result, err
+		}
+	}
+	return firstResult, diags, firstErr
+}
+
+// executeHook runs a single hook in the context of the command: the hook
+// sees the command's flags, args, env, and working directory.
+func (s *Service) executeHook(ctx context.Context, run hookRun, hook *invowkfile.Hook) (Result, []Diagnostic, error) {
+	impl := hook.ScriptImplementation()
+	selection, err := appexec.NewRuntimeSelection(hook.EffectiveRuntime(), requestPlatform(run.req), impl)
+	if err != nil {
+		return Result{}, nil, err
+	}
+	execCtx, err := s.buildExecContext(ctx, run.req, run.cmdInfo, run.defs, selection)
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	var diags []Diagnostic
+	if scriptAnalysis, ok := analyzeSelectedImplementationScript(execCtx); ok {
+		diags = appendScriptInterpreterDiagnostics(diags, scriptAnalysis)
+	}
+	return s.dispatchExecution(run.req, execCtx, run.cmdInfo, run.cfg, diags) //nolint:contextcheck // execCtx carries ctx through the runtime/dependency pipeline.
+}
+
+// dryRunHooks lists the merged lifecycle hooks of cmdInfo for the dry-run plan.
+func dryRunHooks(cmdInfo *discovery.CommandInfo) []DryRunHookPlan {
+	hooks := commandHooks(cmdInfo)
+	if hooks == nil {
+		return nil
+	}
+	var plans []DryRunHookPlan
+	for _, phase := range []invowkfile.HookPhase{invowkfile.HookPhaseBefore, invowkfile.HookPhaseAfter, invowkfile.HookPhaseFinally} {
+		phaseHooks := hooks.Phase(phase)
+		for i := range phaseHooks {
+			plans = append(plans, DryRunHookPlan{
+				Phase:   phase,
+				Runtime: phaseHooks[i].EffectiveRuntime(),
+				Script:  phaseHooks[i].Script,
+			})
+		}
+	}
+	return plans
+}
+
+func succeeded(result Result, err error) bool {
+	return err == nil && result.ExitCode == 0
+}
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	// hookRecordingRuntime records executed scripts and honors context
+	// cancellation: the "wait" script blocks until its context is done, and
+	// reports that it started on waiting when set.
+	hookRecordingRuntime struct {
+		mu        sync.Mutex
+		exitCodes map[invowkfile.ScriptContent]types.ExitCode
+		ran       []invowkfile.ScriptContent
+		waiting   chan struct{}
+	}
+
+	recordingHookObserver struct {
+		noopExecutionObserver
+		failed []HookEvent
+	}
+)
+
+func (*hookRecordingRuntime) Name() string { return string(invowkfile.RuntimeVirtualSh) }
+
+func (r *hookRecordingRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	script := execCtx.SelectedImpl.Script.Content
+	r.mu.Lock()
+	r.ran = append(r.ran, script)
+	r.mu.Unlock()
+	if script == "wait" {
+		if r.waiting != nil {
+			close(r.waiting)
+			r.waiting = nil
+		}
+		<-execCtx.Context.Done()
+	}
+	if err := execCtx.Context.Err(); err != nil {
+		return &runtimepkg.Result{ExitCode: 1, Error: err}
+	}
+	return &runtimepkg.Result{ExitCode: r.exitCodes[script]}
+}
+
+func (*hookRecordingRuntime) Available() bool { return true }
+
+func (*hookRecordingRuntime) Validate(*runtimepkg.ExecutionContext) error { return nil }
+
+func (o *recordingHookObserver) HookFailed(event HookEvent) { o.failed = append(o.failed, event) }
+
+func TestServiceExecuteHooks(t *testing.T) {
+	t.Parallel()
+
+	rootHooks := &invowkfile.Hooks{
+		Before:  []invowkfile.Hook{hookScript("root before")},
+		Finally: []invowkfile.Hook{hookScript("root finally")},
+	}
+	cmdHooks := &invowkfile.Hooks{
+		Before:  []invowkfile.Hook{hookScript("cmd before")},
+		After:   []invowkfile.Hook{hookScript("cmd after")},
+		Finally: []invowkfile.Hook{hookScript("cmd finally")},
+	}
+
+	tests := []struct {
+		name         string
+		mainScript   invowkfile.ScriptContent
+		timeout      invowkfile.DurationString
+		exitCodes    map[invowkfile.ScriptContent]types.ExitCode
+		cancel       bool
+		cancelMain   bool
+		wantRan      []invowkfile.ScriptContent
+		wantExitCode types.ExitCode
+		wantErr      error
+		wantFailed   []invowkfile.HookPhase
+	}{
+		{
+			name:    "success runs every phase in root then command order",
+			wantRan: []invowkfile.ScriptContent{"root before", "cmd before", "main", "cmd after", "root finally", "cmd finally"},
+		},
+		{
+			name:         "failing before hook skips main and after",
+			exitCodes:    map[invowkfile.ScriptContent]types.ExitCode{"root before": 2},
+			wantRan:      []invowkfile.ScriptContent{"root before", "root finally", "cmd finally"},
+			wantExitCode: 2,
+			wantFailed:   []invowkfile.HookPhase{invowkfile.HookPhaseBefore},
+		},
+		{
+			name:         "failing main skips after but runs finally",
+			exitCodes:    map[invowkfile.ScriptContent]types.ExitCode{"main": 3},
+			wantRan:      []invowkfile.ScriptContent{"root before", "cmd before", "main", "root finally", "cmd finally"},
+			wantExitCode: 3,
+		},
+		{
+			name:         "every finally hook runs and an earlier failure wins",
+			exitCodes:    map[invowkfile.ScriptContent]types.ExitCode{"main": 3, "root finally": 4},
+			wantRan:      []invowkfile.ScriptContent{"root before", "cmd before", "main", "root finally", "cmd finally"},
+			wantExitCode: 3,
+			wantFailed:   []invowkfile.HookPhase{invowkfile.HookPhaseFinally},
+		},
+		{
+			name:         "finally failure fails a successful run",
+			exitCodes:    map[invowkfile.ScriptContent]types.ExitCode{"cmd finally": 5},
+			wantRan:      []invowkfile.ScriptContent{"root before", "cmd before", "main", "cmd after", "root finally", "cmd finally"},
+			wantExitCode: 5,
+			wantFailed:   []invowkfile.HookPhase{invowkfile.HookPhaseFinally},
+		},
+		{
+			name:       "finally runs after the implementation timeout",
+			mainScript: "wait",
+			timeout:    "10ms",
+			wantRan:    []invowkfile.ScriptContent{"root before", "cmd before", "wait", "root finally", "cmd finally"},
+			wantErr:    context.DeadlineExceeded,
+		},
+		{
+			name:       "finally runs after an interrupt during main",
+			mainScript: "wait",
+			cancelMain: true,
+			wantRan:    []invowkfile.ScriptContent{"root before", "cmd before", "wait", "root finally", "cmd finally"},
+			wantErr:    context.Canceled,
+		},
+		{
+			name:       "finally runs after cancellation",
+			cancel:     true,
+			wantRan:    []invowkfile.ScriptContent{"root before", "root finally", "cmd finally"},
+			wantErr:    context.Canceled,
+			wantFailed: []invowkfile.HookPhase{invowkfile.HookPhaseBefore},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mainScript := tt.mainScript
+			if mainScript == "" {
+				mainScript = "main"
+			}
+			service, rt, observer := newHookTestService(t, rootHooks, cmdHooks, mainScript, tt.timeout)
+			rt.exitCodes = tt.exitCodes
+
+			ctx := t.Context()
+			if tt.cancel {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			if tt.cancelMain {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				rt.waiting = make(chan struct{})
+				go func(waiting <-chan struct{}) {
+					<-waiting
+					cancel()
+				}(rt.waiting)
+			}
+
+			result, _, err := service.Execute(ctx, Request{Name: "deploy"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && result.ExitCode != tt.wantExitCode {
+				t.Fatalf("ExitCode = %d, want %d", result.ExitCode, tt.wantExitCode)
+			}
+			if !slices.Equal(rt.ran, tt.wantRan) {
+				t.Fatalf("ran %q, want %q", rt.ran, tt.wantRan)
+			}
+			var failedPhases []invowkfile.HookPhase
+			for _, event := range observer.failed {
+				failedPhases = append(failedPhases, event.Phase)
+			}
+			if !slices.Equal(failedPhases, tt.wantFailed) {
+				t.Fatalf("HookFailed phases = %v, want %v", failedPhases, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestServiceExecuteFinallyHookTimeout(t *testing.T) {
+	t.Parallel()
+
+	cmdHooks := &invowkfile.Hooks{Finally: []invowkfile.Hook{hookScript("wait"), hookScript("cmd finally")}}
+	service, rt, observer := newHookTestService(t, nil, cmdHooks, "main", "")
+	service.finallyTimeout = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, _, err := service.Execute(ctx, Request{Name: "deploy"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute() error = %v, want context.Canceled", err)
+	}
+	if want := []invowkfile.ScriptContent{"main", "wait", "cmd finally"}; !slices.Equal(rt.ran, want) {
+		t.Fatalf("ran %q, want %q", rt.ran, want)
+	}
+	if len(observer.failed) != 2 || !errors.Is(observer.failed[0].Err, context.DeadlineExceeded) {
+		t.Fatalf("HookFailed events = %+v, want the hung finally hook to hit the finally deadline", observer.failed)
+	}
+}
+
+func TestServiceExecuteHooksDryRun(t *testing.T) {
+	t.Parallel()
+
+	service, rt, _ := newHookTestService(t, nil, &invowkfile.Hooks{
+		Before:  []invowkfile.Hook{hookScript("cmd before")},
+		Finally: []invowkfile.Hook{{Script: invowkfile.ImplementationScript{Content: "cmd finally"}, Runtime: invowkfile.RuntimeVirtualSh}},
+	}, "main", "")
+
+	result, _, err := service.Execute(t.Context(), Request{Name: "deploy", DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(rt.ran) != 0 {
+		t.Fatalf("dry-run executed %q", rt.ran)
+	}
+	want := []DryRunHookPlan{
+		{Phase: invowkfile.HookPhaseBefore, Runtime: invowkfile.RuntimeNative, Script: invowkfile.ImplementationScript{Content: "cmd before"}},
+		{Phase: invowkfile.HookPhaseFinally, Runtime: invowkfile.RuntimeVirtualSh, Script: invowkfile.ImplementationScript{Content: "cmd finally"}},
+	}
+	if got := result.DryRunData.Plan.Hooks; !slices.Equal(got, want) {
+		t.Fatalf("Plan.Hooks = %#v, want %#v", got, want)
+	}
+	if err := result.DryRunData.Plan.Validate(); err != nil {
+		t.Fatalf("Plan.Validate() error = %v", err)
+	}
+}
+
+func hookScript(content invowkfile.ScriptContent) invowkfile.Hook {
+	return invowkfile.Hook{Script: invowkfile.ImplementationScript{Content: content}}
+}
+
+func newHookTestService(t *testing.T, rootHooks, cmdHooks *invowkfile.Hooks, mainScript invowkfile.ScriptContent, timeout invowkfile.DurationString) (*Service, *hookRecordingRuntime, *recordingHookObserver) {
+	t.Helper()
+
+	inv := &invowkfile.Invowkfile{
+		FilePath: types.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue")),
+		Hooks:    rootHooks,
+	}
+	deploy := invowkfiletest.NewTestCommand("deploy",
+		invowkfiletest.WithScript(string(mainScript)),
+		invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+		invowkfiletest.WithAllPlatforms(),
+	)
+	deploy.Hooks = cmdHooks
+	deploy.Implementations[0].Timeout = timeout
+
+	set := discovery.NewDiscoveredCommandSet()
+	set.Add(&discovery.CommandInfo{
+		Name:       deploy.Name,
+		SimpleName: deploy.Name,
+		FilePath:   inv.FilePath,
+		SourceID:   discovery.SourceIDInvowkfile,
+		Command:    deploy,
+		Invowkfile: inv,
+	})
+	set.Analyze()
+
+	rt := &hookRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	registry.Register(runtimepkg.RuntimeTypeNative, rt)
+	observer := &recordingHookObserver{}
+	cfg := config.DefaultConfig()
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, rt, observer
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// incrementalRun carries the fingerprint state of a command that declares
+// sources from the pre-run freshness check to the post-run record.
+type incrementalRun struct {
+	baseDir   types.FilesystemPath
+	generates []invowkfile.GlobPattern
+	freshness appexec.Freshness
+}
+
+// checkIncremental fingerprints the script, sources and generated outputs of the
+// selected implementation and compares them with the last successful run.
+// It returns nil for commands that declare no sources, which always run.
+// A fingerprint that cannot be loaded is reported as a diagnostic and treated
+// as missing, so the command runs.
+func (s *Service) checkIncremental(req Request, cmdInfo *discovery.CommandInfo, execCtx *runtime.ExecutionContext) (*incrementalRun, []Diagnostic, error) {
+	sources, generates := cmdInfo.Command.IncrementalPatterns(execCtx.SelectedImpl)
+	if len(sources) == 0 {
+		return nil, nil, nil
+	}
+
+	baseDir := watchBaseDir(cmdInfo, watchPlanOptions{
+		workdirOverride: req.Workdir,
+		selectedRuntime: &execCtx.SelectedRuntime,
+		selectedImpl:    execCtx.SelectedImpl,
+	})
+	current, err := appexec.ComputeFingerprint(baseDir, sources, generates)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fingerprinting command '%s': %w", cmdInfo.Name, err)
+	}
+	if execCtx.SelectedImpl != nil {
+		script, scriptErr := execCtx.ResolveSelectedScript()
+		if scriptErr != nil {
+			return nil, nil, fmt.Errorf("fingerprinting command '%s': %w", cmdInfo.Name, scriptErr)
+		}
+		current.Script = appexec.HashScript(script, execCtx.SelectedImpl.Script.Interpreter)
+	}
+
+	key := appexec.NewFingerprintKey(cmdInfo.Name, execCtx.SelectedRuntime, baseDir, fingerprintEnv(cmdInfo, execCtx))
+	var diags []Diagnostic
+	previous, err := s.fingerprintStore().Load(key)
+	if err != nil {
+		diags = appendFingerprintStoreDiagnostic(diags, "load", cmdInfo.Name, err)
+		previous = nil
+	}
+
+	freshness := appexec.CheckFreshness(key, current, previous, generates)
+	if req.Force && freshness.UpToDate() {
+		freshness = freshness.Forced()
+	}
+	return &incrementalRun{baseDir: baseDir, generates: generates, freshness: freshness}, diags, nil
+}
+
+// recordIncremental stores the fingerprint of a successful run: the source
+// hashes taken before the run (so edits made while it ran still count as
+// changes) and the output hashes taken after it.
+func (s *Service) recordIncremental(run *incrementalRun, name invowkfile.CommandName) []Diagnostic {
+	outputs, err := appexec.ComputeFingerprint(run.baseDir, nil, run.generates)
+	if err != nil {
+		return appendFingerprintStoreDiagnostic(nil, "save", name, err)
+	}
+	recorded := appexec.Fingerprint{
+		Sources:   run.freshness.Current().Sources,
+		Generates: outputs.Generates,
+		Script:    run.freshness.Current().Script,
+	}
+	if err := s.fingerprintStore().Save(run.freshness.Key(), recorded); err != nil {
+		return appendFingerprintStoreDiagnostic(nil, "save", name, err)
+	}
+	return nil
+}
+
+func (s *Service) fingerprintStore() FingerprintStore {
+	if s.fingerprints == nil {
+		return noopFingerprintStore{}
+	}
+	return s.fingerprints
+}
+
+// fingerprintEnv returns the environment that identifies an incremental run:
+// the env vars declared by the invowkfile, command, and implementation,
+// overlaid by the projected INVOWK_* variables and --ivk-env-var overrides.
+//
+//goplint:ignore -- environment maps are stringly typed by os/exec and container APIs.
+func fingerprintEnv(cmdInfo *discovery.CommandInfo, execCtx *runtime.ExecutionContext) map[string]string {
+	env := make(map[string]string)
+	if cmdInfo.Invowkfile != nil {
+		maps.Copy(env, cmdInfo.Invowkfile.Env.GetVars())
+	}
+	maps.Copy(env, cmdInfo.Command.Env.GetVars())
+	if execCtx.SelectedImpl != nil {
+		maps.Copy(env, execCtx.SelectedImpl.Env.GetVars())
+	}
+	maps.Copy(env, dryRunEnv(execCtx))
+	return env
+}
+
+func appendFingerprintStoreDiagnostic(diags []Diagnostic, action string, name invowkfile.CommandName, err error) []Diagnostic {
+	diag, diagErr := NewDiagnosticWithCause(
+		DiagnosticSeverityWarning,
+		DiagnosticCodeFingerprintStoreFailed,
+		fmt.Sprintf("failed to %s the fingerprint of command '%s': %v", action, name, err),
+		"",
+		err,
+	)
+	if diagErr != nil {
+		slog.Error("BUG: failed to build fingerprint store diagnostic", "command", name, "error", diagErr)
+		return diags
+	}
+	return append(diags, diag)
+}
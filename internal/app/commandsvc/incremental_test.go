@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	memoryFingerprintStore struct {
+		records map[appexec.FingerprintKey]appexec.Fingerprint
+		loadErr error
+	}
+
+	recordingUpToDateObserver struct {
+		noopExecutionObserver
+		upToDate []invowkfile.CommandName
+	}
+)
+
+//nolint:nilnil // Mirrors the FingerprintStore "no record" contract.
+func (s *memoryFingerprintStore) Load(key appexec.FingerprintKey) (*appexec.Fingerprint, error) {
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	fingerprint, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &fingerprint, nil
+}
+
+func (s *memoryFingerprintStore) Save(key appexec.FingerprintKey, fingerprint appexec.Fingerprint) error {
+	s.records[key] = fingerprint
+	return nil
+}
+
+func (o *recordingUpToDateObserver) CommandUpToDate(name invowkfile.CommandName) {
+	o.upToDate = append(o.upToDate, name)
+}
+
+func TestServiceExecuteIncremental(t *testing.T) {
+	t.Parallel()
+
+	service, rt, observer, dir := newIncrementalTestService(t)
+	store := service.fingerprints.(*memoryFingerprintStore)
+	writeIncrementalFile(t, dir, "src/main.go", "package main")
+	writeIncrementalFile(t, dir, "bin/app", "binary")
+
+	execute := func(req Request) []Diagnostic {
+		t.Helper()
+		req.Name = "build"
+		_, diags, err := service.Execute(t.Context(), req)
+		if err != nil {
+			t.Fatalf("Execute(%+v) error = %v", req, err)
+		}
+		return diags
+	}
+
+	execute(Request{})
+	if len(rt.ran) != 1 || len(store.records) != 1 {
+		t.Fatalf("first run: ran %d times with %d records, want 1 and 1", len(rt.ran), len(store.records))
+	}
+
+	execute(Request{})
+	if len(rt.ran) != 1 {
+		t.Fatalf("unchanged run executed the command (ran %d times)", len(rt.ran))
+	}
+	if len(observer.upToDate) != 1 || observer.upToDate[0] != "build" {
+		t.Fatalf("CommandUpToDate events = %v, want [build]", observer.upToDate)
+	}
+
+	execute(Request{Force: true})
+	if len(rt.ran) != 2 {
+		t.Fatalf("forced run did not execute the command (ran %d times)", len(rt.ran))
+	}
+
+	writeIncrementalFile(t, dir, "src/main.go", "package main // edited")
+	result, _, err := service.Execute(t.Context(), Request{Name: "build", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry-run Execute() error = %v", err)
+	}
+	if got, want := result.DryRunData.Plan.Freshness, "stale because source 'src/main.go' changed"; got != want {
+		t.Fatalf("dry-run Freshness = %q, want %q", got, want)
+	}
+
+	execute(Request{})
+	if len(rt.ran) != 3 {
+		t.Fatalf("run after a source change did not execute the command (ran %d times)", len(rt.ran))
+	}
+
+	store.loadErr = errors.New("disk unavailable")
+	diags := execute(Request{})
+	if len(rt.ran) != 4 {
+		t.Fatalf("run with an unreadable store did not execute the command (ran %d times)", len(rt.ran))
+	}
+	if len(diags) != 1 || diags[0].Code() != DiagnosticCodeFingerprintStoreFailed {
+		t.Fatalf("diagnostics = %v, want one %s warning", diags, DiagnosticCodeFingerprintStoreFailed)
+	}
+}
+
+func TestServiceExecuteIncrementalScriptEdit(t *testing.T) {
+	t.Parallel()
+
+	service, rt, observer, dir := newIncrementalTestService(t)
+	writeIncrementalFile(t, dir, "src/main.go", "package main")
+	writeIncrementalFile(t, dir, "bin/app", "binary")
+	writeIncrementalFile(t, dir, "build.sh", "go build")
+	// script.file is only valid in modules, so the invowkfile is module-backed.
+	scriptFile := invowkfile.ScriptFilePath("build.sh")
+	info := service.discovery.(*stubCommandDiscovery).commandSet.Set.ByName["build"]
+	info.Invowkfile.ModulePath = types.FilesystemPath(dir)
+	build := info.Command
+	build.Implementations[0].Script = invowkfile.ImplementationScript{File: &scriptFile}
+
+	execute := func() {
+		t.Helper()
+		if _, _, err := service.Execute(t.Context(), Request{Name: "build"}); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+
+	execute()
+	execute()
+	if len(rt.ran) != 1 || len(observer.upToDate) != 1 {
+		t.Fatalf("ran %d times with %d up-to-date events, want 1 and 1", len(rt.ran), len(observer.upToDate))
+	}
+
+	writeIncrementalFile(t, dir, "build.sh", "go build -trimpath")
+	result, _, err := service.Execute(t.Context(), Request{Name: "build", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry-run Execute() error = %v", err)
+	}
+	if got, want := result.DryRunData.Plan.Freshness, "stale because script changed"; got != want {
+		t.Fatalf("dry-run Freshness = %q, want %q", got, want)
+	}
+	execute()
+	if len(rt.ran) != 2 {
+		t.Fatalf("run after a script file edit did not execute the command (ran %d times)", len(rt.ran))
+	}
+
+	build.Implementations[0].Script = invowkfile.ImplementationScript{Content: "go build"}
+	execute()
+	if len(rt.ran) != 3 {
+		t.Fatalf("run after switching to inline content did not execute the command (ran %d times)", len(rt.ran))
+	}
+	execute()
+	if len(rt.ran) != 3 {
+		t.Fatalf("unchanged inline script executed the command again (ran %d times)", len(rt.ran))
+	}
+	build.Implementations[0].Script.Content = "go build -race"
+	execute()
+	if len(rt.ran) != 4 {
+		t.Fatalf("run after an inline script edit did not execute the command (ran %d times)", len(rt.ran))
+	}
+}
+
+func TestServiceExecuteIncrementalFailedRunNotRecorded(t *testing.T) {
+	t.Parallel()
+
+	service, rt, _, dir := newIncrementalTestService(t)
+	store := service.fingerprints.(*memoryFingerprintStore)
+	writeIncrementalFile(t, dir, "src/main.go", "package main")
+	rt.exitCodes = map[invowkfile.ScriptContent]types.ExitCode{"go build": 1}
+
+	result, _, err := service.Execute(t.Context(), Request{Name: "build"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.ExitCode != 1 {
+		t.Fatalf("ExitCode = %d, want 1", result.ExitCode)
+	}
+	if len(store.records) != 0 {
+		t.Fatalf("failed run recorded %d fingerprints, want 0", len(store.records))
+	}
+}
+
+func newIncrementalTestService(t *testing.T) (*Service, *stepRecordingRuntime, *recordingUpToDateObserver, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(dir, "invowkfile.cue"))}
+	build := invowkfiletest.NewTestCommand("build",
+		invowkfiletest.WithScript("go build"),
+		invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+		invowkfiletest.WithAllPlatforms(),
+	)
+	build.Sources = []invowkfile.GlobPattern{"src/**/*.go"}
+	build.Generates = []invowkfile.GlobPattern{"bin/app"}
+
+	set := discovery.NewDiscoveredCommandSet()
+	set.Add(&discovery.CommandInfo{
+		Name:       build.Name,
+		SimpleName: build.Name,
+		FilePath:   inv.FilePath,
+		SourceID:   discovery.SourceIDInvowkfile,
+		Command:    build,
+		Invowkfile: inv,
+	})
+	set.Analyze()
+
+	rt := &stepRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	observer := &recordingUpToDateObserver{}
+	cfg := config.DefaultConfig()
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		fingerprints:    &memoryFingerprintStore{records: make(map[appexec.FingerprintKey]appexec.Fingerprint)},
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, rt, observer, dir
+}
+
+func writeIncrementalFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/invowk/invowk/internal/app/deps"
 	appexec "github.com/invowk/invowk/internal/app/execute"
@@ -27,6 +29,16 @@ func (s *Service) validateInputs(req Request, cmdInfo *discovery.CommandInfo, de
 		return err
 	}
 
+	if cmdInfo.Invowkfile != nil {
+		baseDir := cmdInfo.Invowkfile.GetScriptBasePath()
+		if err := deps.ValidateFlagPaths(req.Name, baseDir, defs.flagValues, defs.flagDefs); err != nil {
+			return err
+		}
+		if err := deps.ValidateArgumentPaths(req.Name, baseDir, req.Args, defs.argDefs); err != nil {
+			return err
+		}
+	}
+
 	platform := requestPlatform(req)
 	if !cmdInfo.Command.CanRunOnPlatform(platform) {
 		return &UnsupportedPlatformError{
@@ -45,11 +57,16 @@ func (s *Service) validateInputs(req Request, cmdInfo *discovery.CommandInfo, de
 //  2. Config default runtime (cfg.DefaultRuntime) — soft, silently falls back if incompatible.
 //  3. Per-command default — first runtime of the first matching implementation.
 //
+// Implementations whose when conditions fail for the request are skipped.
+//
 // It returns service-owned typed errors (RuntimeNotAllowedError, etc.) — no ServiceError wrapping.
 func (s *Service) resolveRuntime(req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config) (appexec.RuntimeSelection, error) {
 	cmdName := invowkfile.CommandName(req.Name) //goplint:ignore -- CLI boundary, validated by discovery lookup
-	selection, err := appexec.ResolveRuntime(cmdInfo.Command, cmdName, req.Runtime, cfg, requestPlatform(req))
+	conditions := whenContext(req, cmdInfo)
+	secrets := conditionSecrets(cmdInfo, conditions)
+	selection, err := appexec.ResolveRuntime(cmdInfo.Command, cmdName, req.Runtime, cfg, requestPlatform(req), conditions)
 	if err != nil {
+		err = secrets.RedactError(err)
 		if notAllowed, ok := errors.AsType[*appexec.RuntimeNotAllowedError](err); ok {
 			return appexec.RuntimeSelection{}, &RuntimeNotAllowedError{
 				CommandName: notAllowed.CommandName,
@@ -61,7 +78,79 @@ func (s *Service) resolveRuntime(req Request, cmdInfo *discovery.CommandInfo, cf
 		return appexec.RuntimeSelection{}, fmt.Errorf("%w: resolve runtime for '%s': %w", ErrRuntimeResolution, req.Name, err)
 	}
 
-	return selection, nil
+	return selection.WithRedactedReason(secrets.Redact), nil
+}
+
+// conditionSecrets returns a redactor for the host environment values that
+// env secrets of the command read. Env when conditions quote the values they
+// compared, so the selection explanation printed by dry-run and verbose runs
+// would otherwise show such a secret before the runtime resolves it.
+func conditionSecrets(cmdInfo *discovery.CommandInfo, conditions invowkfile.WhenContext) *runtime.SecretRedactor {
+	envs := []*invowkfile.EnvConfig{cmdInfo.Command.Env}
+	if cmdInfo.Invowkfile != nil {
+		envs = append(envs, cmdInfo.Invowkfile.Env)
+	}
+	for i := range cmdInfo.Command.Implementations {
+		envs = append(envs, cmdInfo.Command.Implementations[i].Env)
+	}
+
+	secrets := runtime.NewSecretRedactor()
+	for _, env := range envs {
+		for _, source := range env.GetSecrets() {
+			if source.Provider != invowkfile.SecretProviderEnv || conditions.LookupEnv == nil {
+				continue
+			}
+			if value, ok := conditions.LookupEnv(string(source.Var)); ok {
+				secrets.Add(value)
+			}
+		}
+	}
+	return secrets
+}
+
+// whenContext returns the facts implementation when conditions are evaluated
+// against: the captured host environment, the effective flag and argument
+// values, the invowkfile directory, and the host CPU architecture.
+func whenContext(req Request, cmdInfo *discovery.CommandInfo) invowkfile.WhenContext {
+	flagValues := req.FlagValues
+	if flagValues == nil {
+		flagValues = defaultFlagValues(cmdInfo)
+	}
+	argDefs := req.ArgDefs
+	if argDefs == nil {
+		argDefs = cmdInfo.Command.Args
+	}
+	args := make(map[invowkfile.ArgumentName]string, len(argDefs))
+	for i, argDef := range argDefs {
+		switch {
+		case argDef.Variadic && i < len(req.Args):
+			args[argDef.Name] = strings.Join(req.Args[i:], " ")
+		case i < len(req.Args):
+			args[argDef.Name] = req.Args[i]
+		case argDef.DefaultValue != "":
+			args[argDef.Name] = argDef.DefaultValue
+		}
+	}
+
+	lookupEnv := os.LookupEnv
+	if req.UserEnv != nil {
+		lookupEnv = func(name string) (string, bool) {
+			value, ok := req.UserEnv[name]
+			return value, ok
+		}
+	}
+	var baseDir invowkfile.FilesystemPath
+	if cmdInfo.Invowkfile != nil {
+		baseDir = cmdInfo.Invowkfile.GetScriptBasePath()
+	}
+
+	return invowkfile.WhenContext{
+		LookupEnv: lookupEnv,
+		Flags:     flagValues,
+		Args:      args,
+		BaseDir:   baseDir,
+		Arch:      invowkfile.CurrentArchitecture(),
+	}
 }
 
 // ensureSSHIfNeeded conditionally starts the SSH server when the selected runtime
@@ -80,29 +169,44 @@ func (s *Service) ensureSSHIfNeeded(ctx context.Context, resolved appexec.Runtim
 
 // buildExecContext constructs the runtime execution context from the request,
 // discovered command info, resolved definitions, and selected runtime. It projects
-// flags and arguments into environment variables following the INVOWK_FLAG_*,
-// INVOWK_ARG_*, ARGn, and ARGC conventions.
+// flags, arguments, matrix values, and the outputs of the commands that ran
+// earlier in the invocation into environment variables following the
+// INVOWK_FLAG_*, INVOWK_ARG_*, ARGn, ARGC, INVOWK_MATRIX_*, and INVOWK_DEP_*
+// conventions.
 func (s *Service) buildExecContext(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, defs resolvedDefinitions, resolved appexec.RuntimeSelection) (*runtime.ExecutionContext, error) {
-	return appexec.BuildExecutionContext(ctx, appexec.BuildExecutionContextOptions{
-		Command:         cmdInfo.Command,
-		CommandFullName: cmdInfo.Name,
-		Invowkfile:      cmdInfo.Invowkfile,
-		Selection:       resolved,
-		Args:            req.Args,
-		Verbose:         req.Verbose,
-		Workdir:         req.Workdir,
-		ForceRebuild:    req.ForceRebuild,
-		ContainerName:   req.ContainerName,
-		EnvFiles:        req.EnvFiles,
-		EnvVars:         req.EnvVars,
-		FlagValues:      defs.flagValues,
-		ArgDefs:         defs.argDefs,
-		EnvInheritMode:  req.EnvInheritMode,
-		EnvInheritAllow: req.EnvInheritAllow,
-		EnvInheritDeny:  req.EnvInheritDeny,
-		SourceID:        cmdInfo.SourceID,
-		Platform:        requestPlatform(req),
+	execCtx, err := appexec.BuildExecutionContext(ctx, appexec.BuildExecutionContextOptions{
+		Command:           cmdInfo.Command,
+		CommandFullName:   cmdInfo.Name,
+		Invowkfile:        cmdInfo.Invowkfile,
+		Selection:         resolved,
+		Args:              req.Args,
+		Verbose:           req.Verbose,
+		Workdir:           req.Workdir,
+		ForceRebuild:      req.ForceRebuild,
+		ContainerName:     req.ContainerName,
+		EnvFiles:          req.EnvFiles,
+		EnvVars:           req.EnvVars,
+		FlagValues:        defs.flagValues,
+		FlagDefs:          defs.flagDefs,
+		ArgDefs:           defs.argDefs,
+		EnvInheritMode:    req.EnvInheritMode,
+		EnvInheritAllow:   req.EnvInheritAllow,
+		EnvInheritDeny:    req.EnvInheritDeny,
+		Matrix:            req.matrix,
+		DependencyOutputs: req.outputs.snapshot(),
+		SourceID:          cmdInfo.SourceID,
+		Platform:          requestPlatform(req),
 	})
+	if err != nil {
+		return nil, err
+	}
+	if req.stdout != nil {
+		execCtx.IO.Stdout = req.stdout
+	}
+	if req.stderr != nil {
+		execCtx.IO.Stderr = req.stderr
+	}
+	return execCtx, nil
 }
 
 func requestPlatform(req Request) invowkfile.Platform {
@@ -144,6 +144,105 @@ func TestResolveRuntime(t *testing.T) {
 	}
 }
 
+func TestResolveRuntimeWhenConditions(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{}
+	cmdInfo := commandsvcTestCommandInfo(t, "build")
+	cmdInfo.Command.Flags = []invowkfile.Flag{{Name: "target", Description: "Build target", DefaultValue: "dev"}}
+	conditional := cmdInfo.Command.Implementations[0]
+	conditional.When = &invowkfile.When{
+		Env:   map[invowkfile.EnvVarName]string{"CI": "true"},
+		Flags: map[invowkfile.FlagName]string{"target": "prod"},
+	}
+	cmdInfo.Command.Implementations = append([]invowkfile.Implementation{conditional}, cmdInfo.Command.Implementations...)
+
+	selection, err := service.resolveRuntime(Request{
+		Name:       "build",
+		FlagValues: map[invowkfile.FlagName]string{"target": "prod"},
+		UserEnv:    map[string]string{"CI": "true"},
+	}, cmdInfo, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("resolveRuntime(CI) = %v", err)
+	}
+	if selection.Impl() != &cmdInfo.Command.Implementations[0] {
+		t.Fatalf("resolveRuntime(CI) selected %p, want the conditional implementation", selection.Impl())
+	}
+	if want := `implementation #1 selected: env CI="true", flag --target="prod"`; selection.Reason() != want {
+		t.Fatalf("selection.Reason() = %q, want %q", selection.Reason(), want)
+	}
+
+	// Without parsed flag values the declared default ("dev") applies.
+	selection, err = service.resolveRuntime(Request{Name: "build", UserEnv: map[string]string{"CI": "true"}}, cmdInfo, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("resolveRuntime(default flag) = %v", err)
+	}
+	if selection.Impl() != &cmdInfo.Command.Implementations[1] || !strings.Contains(selection.Reason(), `flag --target is "dev", want "prod"`) {
+		t.Fatalf("resolveRuntime(default flag) = %p, %q; want the fallback implementation", selection.Impl(), selection.Reason())
+	}
+}
+
+func TestResolveRuntimeRedactsEnvSecretConditions(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{}
+	cmdInfo := commandsvcTestCommandInfo(t, "deploy")
+	cmdInfo.Command.Env = &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+		"API_TOKEN": {Provider: invowkfile.SecretProviderEnv, Var: "DEPLOY_TOKEN"},
+	}}
+	conditional := cmdInfo.Command.Implementations[0]
+	conditional.When = &invowkfile.When{Env: map[invowkfile.EnvVarName]string{"DEPLOY_TOKEN": "expected"}}
+	cmdInfo.Command.Implementations = append([]invowkfile.Implementation{conditional}, cmdInfo.Command.Implementations...)
+
+	selection, err := service.resolveRuntime(Request{
+		Name:    "deploy",
+		UserEnv: map[string]string{"DEPLOY_TOKEN": "s3cr3t-value"},
+	}, cmdInfo, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("resolveRuntime() = %v", err)
+	}
+	if strings.Contains(selection.Reason(), "s3cr3t-value") || !strings.Contains(selection.Reason(), runtimepkg.RedactedSecret) {
+		t.Fatalf("selection.Reason() = %q, want the secret value masked", selection.Reason())
+	}
+
+	// Without a fallback implementation the explanation is part of the error.
+	cmdInfo.Command.Implementations = cmdInfo.Command.Implementations[:1]
+	_, err = service.resolveRuntime(Request{
+		Name:    "deploy",
+		UserEnv: map[string]string{"DEPLOY_TOKEN": "s3cr3t-value"},
+	}, cmdInfo, config.DefaultConfig())
+	if err == nil || strings.Contains(err.Error(), "s3cr3t-value") {
+		t.Fatalf("resolveRuntime(no match) error = %v, want the secret value masked", err)
+	}
+}
+
+func TestWhenContextArgs(t *testing.T) {
+	t.Parallel()
+
+	cmdInfo := commandsvcTestCommandInfo(t, "build")
+	cmdInfo.Command.Args = []invowkfile.Argument{
+		{Name: "service"},
+		{Name: "region", DefaultValue: "eu"},
+		{Name: "extra", Variadic: true},
+	}
+
+	wc := whenContext(Request{Name: "build", Args: []string{"api"}}, cmdInfo)
+	if wc.Args["service"] != "api" || wc.Args["region"] != "eu" {
+		t.Fatalf("whenContext().Args = %v, want provided and default values", wc.Args)
+	}
+	if _, ok := wc.Args["extra"]; ok {
+		t.Fatalf("whenContext().Args = %v, want an empty variadic argument unset", wc.Args)
+	}
+
+	wc = whenContext(Request{Name: "build", Args: []string{"api", "us", "a", "b"}}, cmdInfo)
+	if wc.Args["region"] != "us" || wc.Args["extra"] != "a b" {
+		t.Fatalf("whenContext().Args = %v, want variadic values space-joined", wc.Args)
+	}
+	if wc.Arch != invowkfile.CurrentArchitecture() || wc.BaseDir != cmdInfo.Invowkfile.GetScriptBasePath() {
+		t.Fatalf("whenContext() = arch %q, base dir %q", wc.Arch, wc.BaseDir)
+	}
+}
+
 func TestEnsureSSHIfNeeded(t *testing.T) {
 	t.Parallel()
 
@@ -323,7 +422,7 @@ func unsupportedPlatform() invowkfile.PlatformType {
 func mustResolveRuntime(t *testing.T, cmd *invowkfile.Command) appexec.RuntimeSelection {
 	t.Helper()
 
-	selection, err := appexec.ResolveRuntime(cmd, cmd.Name, "", config.DefaultConfig(), invowkfile.CurrentPlatform())
+	selection, err := appexec.ResolveRuntime(cmd, cmd.Name, "", config.DefaultConfig(), invowkfile.CurrentPlatform(), invowkfile.WhenContext{})
 	if err != nil {
 		t.Fatalf("ResolveRuntime() = %v", err)
 	}
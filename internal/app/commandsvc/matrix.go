@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// executeMatrix runs a matrix command once per combination, each with the
+// combination's values injected as INVOWK_MATRIX_* env vars. Prerequisites
+// run once before the first combination; every combination then runs with
+// its own hooks. A failing combination does not stop the others, and the
+// outcome of every combination is reported to the observer at the end. The
+// exit code is that of the first failed combination.
+//
+// Parallel matrices run at most execution.max_parallel combinations at once,
+// non-interactively, with every output line prefixed by its combination. In
+// dry-run mode the first combination is planned and the plan lists them all.
+func (s *Service) executeMatrix(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, diags []Diagnostic) (Result, []Diagnostic, error) {
+	matrix := cmdInfo.Command.Matrix
+	combinations := matrix.Combinations()
+
+	if req.DryRun {
+		child := req
+		child.matrix = combinations[0]
+		result, childDiags, err := s.executeCommand(ctx, child, cmdInfo, cfg, defs, diags)
+		if result.DryRunData != nil {
+			result.DryRunData.Plan.Matrix = combinations
+			result.DryRunData.Plan.MatrixParallel = matrix.Parallel
+		}
+		return result, childDiags, err
+	}
+
+	var impl *invowkfile.Implementation
+	if !cmdInfo.Command.HasSteps() {
+		resolved, err := s.resolveRuntime(req, cmdInfo, cfg)
+		if err != nil {
+			return Result{}, diags, err
+		}
+		impl = resolved.Impl()
+	}
+	_, prereqDiags, err := s.runPrerequisites(ctx, req, cmdInfo, cfg, impl)
+	diags = append(diags, prereqDiags...)
+	if err != nil {
+		result, resultErr := prerequisiteFailure(err)
+		return result, diags, resultErr
+	}
+
+	limit := 1
+	if matrix.Parallel {
+		limit = cfg.Execution.MaxParallel.Limit()
+	}
+	var (
+		// mu guards diags and serializes observer events.
+		mu sync.Mutex
+		// stdoutMu and stderrMu are shared by the prefixing writers of each stream.
+		stdoutMu, stderrMu sync.Mutex
+	)
+	run := func(ctx context.Context, index int, combination invowkfile.MatrixCombination) (types.ExitCode, error) {
+		child := req
+		child.matrix = combination
+		child.prerequisitesScheduled = true
+		var flush func()
+		if matrix.Parallel {
+			child.Interactive = false
+			child, flush = prefixedMatrixRequest(child, combination, &stdoutMu, &stderrMu)
+			defer flush()
+		}
+
+		mu.Lock()
+		s.observer.MatrixRunStarting(MatrixEvent{CommandName: cmdInfo.Name, Index: index, Total: len(combinations), Combination: combination})
+		mu.Unlock()
+		result, childDiags, err := s.executeCommand(ctx, child, cmdInfo, cfg, defs, nil)
+		mu.Lock()
+		diags = append(diags, childDiags...)
+		mu.Unlock()
+		return result.ExitCode, err
+	}
+
+	matrixResult := appexec.RunMatrix(ctx, combinations, limit, run)
+	s.observer.MatrixFinished(MatrixSummary{CommandName: cmdInfo.Name, Outcomes: matrixResult.Outcomes})
+	return Result{ExitCode: matrixResult.ExitCode()}, diags, nil
+}
+
+// prefixedMatrixRequest routes the output of a parallel matrix combination
+// through writers that prefix every line with the combination, wrapping the
+// request's own streams (the standard streams when unset). The returned func
+// writes any unterminated last line and must be called when the run ends.
+func prefixedMatrixRequest(req Request, combination invowkfile.MatrixCombination, stdoutMu, stderrMu *sync.Mutex) (Request, func()) {
+	prefix := appexec.MatrixOutputPrefix(combination)
+	std := runtime.DefaultIO()
+	if req.stdout != nil {
+		std.Stdout = req.stdout
+	}
+	if req.stderr != nil {
+		std.Stderr = req.stderr
+	}
+	stdout := appexec.NewLinePrefixWriter(std.Stdout, prefix, stdoutMu)
+	stderr := appexec.NewLinePrefixWriter(std.Stderr, prefix, stderrMu)
+	req.stdout, req.stderr = stdout, stderr
+	return req, func() {
+		for _, w := range []*appexec.LinePrefixWriter{stdout, stderr} {
+			if err := w.Flush(); err != nil {
+				slog.Debug("failed to flush matrix output", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	// matrixRecordingRuntime records the INVOWK_MATRIX_GO value of every run
+	// and whether its output was prefixed, and exits with the code mapped to
+	// the value.
+	matrixRecordingRuntime struct {
+		mu        sync.Mutex
+		exitCodes map[string]types.ExitCode
+		values    []string
+		prefixed  int
+	}
+
+	recordingMatrixObserver struct {
+		noopExecutionObserver
+		started []MatrixEvent
+		summary *MatrixSummary
+	}
+)
+
+func (*matrixRecordingRuntime) Name() string { return string(invowkfile.RuntimeVirtualSh) }
+
+func (r *matrixRecordingRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	value := execCtx.Env.ExtraEnv["INVOWK_MATRIX_GO"]
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, value)
+	if _, ok := execCtx.IO.Stdout.(*appexec.LinePrefixWriter); ok {
+		r.prefixed++
+	}
+	return &runtimepkg.Result{ExitCode: r.exitCodes[value]}
+}
+
+func (*matrixRecordingRuntime) Available() bool { return true }
+
+func (*matrixRecordingRuntime) Validate(*runtimepkg.ExecutionContext) error { return nil }
+
+func (o *recordingMatrixObserver) MatrixRunStarting(event MatrixEvent) {
+	o.started = append(o.started, event)
+}
+
+func (o *recordingMatrixObserver) MatrixFinished(summary MatrixSummary) { o.summary = &summary }
+
+func TestServiceExecuteMatrix(t *testing.T) {
+	t.Parallel()
+
+	for _, parallel := range []bool{false, true} {
+		name := "sequential"
+		if parallel {
+			name = "parallel"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			service, rt, observer := newMatrixTestService(t, parallel)
+			rt.exitCodes = map[string]types.ExitCode{"1.23": 2}
+
+			result, _, err := service.Execute(t.Context(), Request{Name: "deploy"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.ExitCode != 2 {
+				t.Errorf("Execute() exit code = %d, want 2 (the failed combination's)", result.ExitCode)
+			}
+
+			values := slices.Clone(rt.values)
+			if parallel {
+				slices.Sort(values)
+			}
+			if want := []string{"1.22", "1.23", "1.24"}; !slices.Equal(values, want) {
+				t.Errorf("runs saw INVOWK_MATRIX_GO = %q, want %q (every combination runs)", values, want)
+			}
+			if wantPrefixed := map[bool]int{false: 0, true: 3}[parallel]; rt.prefixed != wantPrefixed {
+				t.Errorf("%d runs had prefixed output, want %d", rt.prefixed, wantPrefixed)
+			}
+			if len(observer.started) != 3 || observer.started[0].Total != 3 {
+				t.Errorf("MatrixRunStarting events = %+v, want 3 of 3", observer.started)
+			}
+			if observer.summary == nil {
+				t.Fatal("MatrixFinished was not reported")
+			}
+			outcomes := observer.summary.Outcomes
+			if len(outcomes) != 3 || outcomes[0].Failed() || outcomes[1].ExitCode != 2 || outcomes[2].Failed() {
+				t.Errorf("summary outcomes = %+v, want only go=1.23 to fail", outcomes)
+			}
+		})
+	}
+}
+
+func TestServiceExecuteMatrixDryRun(t *testing.T) {
+	t.Parallel()
+
+	service, rt, observer := newMatrixTestService(t, true)
+	result, _, err := service.Execute(t.Context(), Request{Name: "deploy", DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(rt.values) > 0 || observer.summary != nil {
+		t.Fatalf("dry run executed %d combinations, want none", len(rt.values))
+	}
+	if result.DryRunData == nil {
+		t.Fatal("Execute() returned no dry-run data")
+	}
+	plan := result.DryRunData.Plan
+	if len(plan.Matrix) != 3 || !plan.MatrixParallel {
+		t.Errorf("plan matrix = %v (parallel: %v), want 3 parallel combinations", plan.Matrix, plan.MatrixParallel)
+	}
+	if got := plan.Env["INVOWK_MATRIX_GO"]; got != "1.22" {
+		t.Errorf("plan INVOWK_MATRIX_GO = %q, want the first combination's value", got)
+	}
+}
+
+func TestPrefixedMatrixRequestWrapsRequestStreams(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	var stdoutMu, stderrMu sync.Mutex
+	child, flush := prefixedMatrixRequest(Request{Name: "deploy", stdout: &stdout, stderr: &stderr},
+		invowkfile.MatrixCombination{"go": "1.23"}, &stdoutMu, &stderrMu)
+	fmt.Fprintln(child.stdout, "built")
+	fmt.Fprint(child.stderr, "warning")
+	flush()
+
+	prefix := appexec.MatrixOutputPrefix(invowkfile.MatrixCombination{"go": "1.23"})
+	if want := prefix + "built\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if want := prefix + "warning"; !bytes.HasPrefix(stderr.Bytes(), []byte(want)) {
+		t.Errorf("stderr = %q, want it to start with %q", stderr.String(), want)
+	}
+}
+
+func newMatrixTestService(t *testing.T, parallel bool) (*Service, *matrixRecordingRuntime, *recordingMatrixObserver) {
+	t.Helper()
+
+	service, _, _ := newHookTestService(t, nil, nil, "deploy", "")
+	stub, ok := service.discovery.(*stubCommandDiscovery)
+	if !ok {
+		t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+	}
+	stub.commandSet.Set.Commands[0].Command.Matrix = &invowkfile.CommandMatrix{
+		Vars:     map[invowkfile.MatrixVarName][]string{"go": {"1.22", "1.23", "1.24"}},
+		Parallel: parallel,
+	}
+
+	rt := &matrixRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	service.registryFactory = staticRuntimeRegistryFactory{registry: registry}
+	observer := &recordingMatrixObserver{}
+	service.observer = observer
+	return service, rt, observer
+}
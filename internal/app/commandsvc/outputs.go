@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// invocationOutputs collects the outputs written by the commands of one
+// invocation, so the commands running after them read the values as
+// INVOWK_DEP_<CMD>_<NAME> env vars. Parallel prerequisites and matrix
+// combinations record concurrently.
+type invocationOutputs struct {
+	mu     sync.Mutex
+	byName map[invowkfile.CommandName]invowkfile.CommandOutputs
+}
+
+// record stores the outputs of a run of producer. Values written by an
+// earlier run of the same command (e.g. by one of its hooks) are kept unless
+// overwritten. record is a no-op on a nil receiver.
+func (o *invocationOutputs) record(producer invowkfile.CommandName, outputs invowkfile.CommandOutputs) {
+	if o == nil || len(outputs) == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.byName == nil {
+		o.byName = make(map[invowkfile.CommandName]invowkfile.CommandOutputs)
+	}
+	if o.byName[producer] == nil {
+		o.byName[producer] = make(invowkfile.CommandOutputs, len(outputs))
+	}
+	maps.Copy(o.byName[producer], outputs)
+}
+
+// snapshot returns a copy of the outputs recorded so far, keyed by producer.
+func (o *invocationOutputs) snapshot() map[invowkfile.CommandName]invowkfile.CommandOutputs {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	snapshot := make(map[invowkfile.CommandName]invowkfile.CommandOutputs, len(o.byName))
+	for producer, outputs := range o.byName {
+		snapshot[producer] = maps.Clone(outputs)
+	}
+	return snapshot
+}
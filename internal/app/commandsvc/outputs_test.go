@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// outputWritingRuntime writes the file content mapped to a script to the
+// run's output file before recording the run.
+type outputWritingRuntime struct {
+	stepRecordingRuntime
+	writes map[invowkfile.ScriptContent]string
+}
+
+func (r *outputWritingRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	if content, ok := r.writes[execCtx.SelectedImpl.Script.Content]; ok {
+		if err := os.WriteFile(execCtx.Env.ExtraEnv[runtimepkg.EnvVarOutputFile], []byte(content), 0o600); err != nil {
+			return runtimepkg.NewErrorResult(1, err)
+		}
+	}
+	return r.stepRecordingRuntime.Execute(execCtx)
+}
+
+func TestServiceExecuteOutputs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("prerequisite outputs reach the commands after it", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt := newOutputTestService(t, "version=1.2.3\nnotes<<EOF\nline 1\nline 2\nEOF\n")
+		result, _, err := service.Execute(t.Context(), Request{Name: "deploy"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 0 {
+			t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+		}
+
+		runs := rt.ran
+		if len(runs) != 3 {
+			t.Fatalf("ran %d commands, want build, test, deploy", len(runs))
+		}
+		if _, ok := runs[0].env["INVOWK_DEP_BUILD_VERSION"]; ok {
+			t.Error("build saw its own output")
+		}
+		if runs[0].env[runtimepkg.EnvVarOutputFile] == "" {
+			t.Error("build ran without INVOWK_OUTPUT_FILE")
+		}
+		for _, run := range runs[1:] {
+			if got := run.env["INVOWK_DEP_BUILD_VERSION"]; got != "1.2.3" {
+				t.Errorf("%s INVOWK_DEP_BUILD_VERSION = %q, want 1.2.3", run.script, got)
+			}
+			if got := run.env["INVOWK_DEP_BUILD_NOTES"]; got != "line 1\nline 2" {
+				t.Errorf("%s INVOWK_DEP_BUILD_NOTES = %q, want the multi-line value", run.script, got)
+			}
+			if _, ok := run.env[runtimepkg.EnvVarOutputFile]; ok {
+				t.Errorf("%s declares no outputs but got INVOWK_OUTPUT_FILE", run.script)
+			}
+		}
+		if _, err := os.Stat(runs[0].env[runtimepkg.EnvVarOutputFile]); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("output file was not removed: %v", err)
+		}
+	})
+
+	t.Run("undeclared output fails the producer", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt := newOutputTestService(t, "digest=sha256:abc\n")
+		_, _, err := service.Execute(t.Context(), Request{Name: "deploy"})
+		if !errors.Is(err, runtimepkg.ErrInvalidOutputFile) || !errors.Is(err, invowkfile.ErrUndeclaredOutput) {
+			t.Fatalf("Execute() error = %v, want ErrInvalidOutputFile wrapping ErrUndeclaredOutput", err)
+		}
+		if len(rt.ran) != 1 {
+			t.Fatalf("ran %d commands, want only build", len(rt.ran))
+		}
+	})
+}
+
+// newOutputTestService builds the prerequisite test service, in which "build"
+// declares the outputs "version" and "notes" and writes buildOutputs to its
+// output file.
+func newOutputTestService(t *testing.T, buildOutputs string) (*Service, *outputWritingRuntime) {
+	t.Helper()
+
+	service, _, _ := newPrerequisiteTestService(t, func(build *invowkfile.Command) {
+		build.Outputs = []invowkfile.CommandOutput{{Name: "version"}, {Name: "notes"}}
+	})
+	rt := &outputWritingRuntime{writes: map[invowkfile.ScriptContent]string{"echo build": buildOutputs}}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	service.registryFactory = staticRuntimeRegistryFactory{registry: registry}
+	return service, rt
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/invowk/invowk/internal/app/deps"
+	appexec "github.com/invowk/invowk/internal/app/execute"
 	"github.com/invowk/invowk/internal/config"
 	"github.com/invowk/invowk/internal/runtime"
 	"github.com/invowk/invowk/pkg/invowkfile"
@@ -63,12 +64,99 @@ type (
 	ExecutionObserver interface {
 		CommandStarting(invowkfile.CommandName)
 		InteractiveFallback(invowkfile.RuntimeMode)
+		// StepStarting reports that a step of a multi-step command is about to run.
+		StepStarting(StepEvent)
+		// StepFailed reports a failed step whose continue_on_error setting let
+		// the remaining steps run.
+		StepFailed(StepEvent)
+		// PrerequisiteStarting reports that a prerequisite command (a
+		// depends_on.cmds entry with run: true) is about to run. Prerequisites
+		// may run in parallel, so events can arrive from several goroutines.
+		PrerequisiteStarting(PrerequisiteEvent)
+		// CommandUpToDate reports that a command declaring sources was skipped
+		// because its fingerprint matches its last successful run.
+		CommandUpToDate(invowkfile.CommandName)
+		// HookStarting reports that a lifecycle hook is about to run (verbose mode only).
+		HookStarting(HookEvent)
+		// HookFailed reports a lifecycle hook that failed. A failing before or
+		// after hook stops the run; a failing finally hook does not stop the
+		// remaining finally hooks.
+		HookFailed(HookEvent)
+		// AttemptStarting reports that a run of an implementation with a retry
+		// policy is about to start. Retries are always reported; the first
+		// attempt is reported in verbose mode only.
+		AttemptStarting(AttemptEvent)
+		// ImplementationSelected reports the implementation chosen for a
+		// command whose candidates declare when conditions (verbose mode only).
+		ImplementationSelected(ImplementationEvent)
+		// CommandDeprecated reports that an invoked command is deprecated,
+		// before it (or its replacement, when forwarded) runs.
+		CommandDeprecated(DeprecationEvent)
+		// RunLockWaiting reports that a command waits for its lock, held by
+		// another process. RunLockWaitEnded always follows, whether the lock
+		// was taken or the wait failed.
+		RunLockWaiting(RunLockEvent)
+		// RunLockWaitEnded reports that a command stopped waiting for its lock.
+		RunLockWaitEnded(RunLockEvent)
+		// MatrixRunStarting reports that a combination of a matrix command is
+		// about to run. Parallel combinations report from several goroutines.
+		MatrixRunStarting(MatrixEvent)
+		// MatrixFinished reports the outcome of every combination of a matrix
+		// command once the last one has finished.
+		MatrixFinished(MatrixSummary)
+	}
+
+	// FingerprintStore persists the fingerprints recorded after successful runs
+	// of commands that declare sources.
+	FingerprintStore interface {
+		// Load returns the fingerprint recorded for key, or nil when none exists.
+		Load(appexec.FingerprintKey) (*appexec.Fingerprint, error)
+		// Save records the fingerprint of a successful run under key.
+		Save(appexec.FingerprintKey, appexec.Fingerprint) error
+	}
+
+	// CompletionCache keeps the candidates of completion script runs for the
+	// rest of a shell session, so repeated completions do not rerun scripts.
+	CompletionCache interface {
+		// Load returns the candidates cached for key and whether an entry exists.
+		Load(appexec.CompletionKey) ([]appexec.CompletionCandidate, bool, error)
+		// Save caches the candidates of a successful script run under key.
+		Save(appexec.CompletionKey, []appexec.CompletionCandidate) error
+	}
+
+	// Confirmer asks the confirmation question of commands that declare a
+	// confirm block.
+	Confirmer interface {
+		// Available reports whether the question can be asked. When it returns
+		// false, confirmed commands fail unless the request answers yes.
+		Available() bool
+		// Confirm asks the question and returns the answer. defaultYes
+		// preselects the affirmative answer.
+		Confirm(ctx context.Context, message string, defaultYes bool) (bool, error)
+	}
+
+	// RunLocker takes the locks that keep overlapping runs of commands that
+	// declare a lock from executing at the same time, across processes.
+	RunLocker interface {
+		// TryLock takes the lock without waiting. When another process holds
+		// it, TryLock returns a nil release func and the holder.
+		TryLock(appexec.RunLock) (release func(), holder appexec.RunLockHolder, err error)
+		// Lock waits until the lock is free or ctx is done.
+		Lock(ctx context.Context, lock appexec.RunLock) (release func(), err error)
 	}
 
 	noopHostAccess struct{}
 
 	noopExecutionObserver struct{}
 
+	noopFingerprintStore struct{}
+
+	noopCompletionCache struct{}
+
+	unavailableConfirmer struct{}
+
+	noopRunLocker struct{}
+
 	missingRuntimeRegistryFactory struct{}
 
 	emptyRuntimeSession struct {
@@ -94,6 +182,91 @@ func (noopExecutionObserver) InteractiveFallback(invowkfile.RuntimeMode) {
 	// Interactive fallback events are optional for service-only callers.
 }
 
+func (noopExecutionObserver) StepStarting(StepEvent) {
+	// Step progress events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) StepFailed(StepEvent) {
+	// Step failure events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) PrerequisiteStarting(PrerequisiteEvent) {
+	// Prerequisite progress events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) CommandUpToDate(invowkfile.CommandName) {
+	// Incremental skip events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) HookStarting(HookEvent) {
+	// Hook progress events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) HookFailed(HookEvent) {
+	// Hook failure events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) AttemptStarting(AttemptEvent) {
+	// Retry attempt events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) ImplementationSelected(ImplementationEvent) {
+	// Implementation selection events are optional for service-only callers.
+}
+
+func (noopExecutionObserver) CommandDeprecated(DeprecationEvent) {
+	// Deprecation warnings are optional for service-only callers.
+}
+
+func (noopExecutionObserver) RunLockWaiting(RunLockEvent) {
+	// Lock wait progress is optional for service-only callers.
+}
+
+func (noopExecutionObserver) RunLockWaitEnded(RunLockEvent) {
+	// Lock wait progress is optional for service-only callers.
+}
+
+func (noopExecutionObserver) MatrixRunStarting(MatrixEvent) {
+	// Matrix progress is optional for service-only callers.
+}
+
+func (noopExecutionObserver) MatrixFinished(MatrixSummary) {
+	// Matrix summaries are optional for service-only callers.
+}
+
+// Load reports no record: without a store every incremental command is
+// treated as never run.
+//
+//nolint:nilnil // A nil fingerprint with nil error is the "no record" value.
+func (noopFingerprintStore) Load(appexec.FingerprintKey) (*appexec.Fingerprint, error) {
+	return nil, nil
+}
+
+func (noopFingerprintStore) Save(appexec.FingerprintKey, appexec.Fingerprint) error { return nil }
+
+// Load reports no entry: without a cache every completion runs its script.
+func (noopCompletionCache) Load(appexec.CompletionKey) ([]appexec.CompletionCandidate, bool, error) {
+	return nil, false, nil
+}
+
+func (noopCompletionCache) Save(appexec.CompletionKey, []appexec.CompletionCandidate) error {
+	return nil
+}
+
+// Available reports false: without a confirmer no question can be asked, so
+// confirmed commands require Request.Yes.
+func (unavailableConfirmer) Available() bool { return false }
+
+func (unavailableConfirmer) Confirm(context.Context, string, bool) (bool, error) { return false, nil }
+
+// TryLock always succeeds: without a locker, runs of commands that declare a
+// lock are not serialized.
+func (noopRunLocker) TryLock(appexec.RunLock) (func(), appexec.RunLockHolder, error) {
+	return func() {}, appexec.RunLockHolder{}, nil
+}
+
+func (noopRunLocker) Lock(context.Context, appexec.RunLock) (func(), error) { return func() {}, nil }
+
 func (missingRuntimeRegistryFactory) Create(*config.Config, HostAccess, invowkfile.RuntimeMode) RuntimeSession {
 	return &emptyRuntimeSession{registry: runtime.NewRegistry()}
 }
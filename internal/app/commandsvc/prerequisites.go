@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/invowk/invowk/internal/app/deps"
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// runPrerequisites executes the prerequisite commands of cmdInfo: the targets
+// of depends_on.cmds entries with run: true, collected transitively from root,
+// command, and implementation depends_on. impl is the implementation selected
+// for cmdInfo (nil for multi-step commands); prerequisites resolve their own
+// implementation from their default runtime, which is what they execute with.
+//
+// Shared prerequisites run once, independent ones run in parallel (bounded by
+// execution.max_parallel), and the first failure fails the command. In dry-run
+// mode nothing runs and the prerequisites are only returned, in a valid
+// execution order, for the plan.
+func (s *Service) runPrerequisites(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, impl *invowkfile.Implementation) ([]invowkfile.CommandName, []Diagnostic, error) {
+	if req.prerequisitesScheduled {
+		return nil, nil, nil
+	}
+
+	// Commands from different sources can share a name, so prerequisites are
+	// tracked by their source-qualified ID.
+	root := prerequisiteID(cmdInfo)
+	targets := map[appexec.PrerequisiteID]*discovery.CommandInfo{root: cmdInfo}
+	// callers records the command that first declared each prerequisite.
+	callers := map[appexec.PrerequisiteID]*discovery.CommandInfo{}
+	resolve := func(id appexec.PrerequisiteID) ([]appexec.PrerequisiteID, error) {
+		info := targets[id]
+		var implDeps *invowkfile.DependsOn
+		switch {
+		case info != cmdInfo:
+			implDeps = prerequisiteImplementationDeps(req, info, cfg)
+		case impl != nil:
+			implDeps = impl.DependsOn
+		}
+		merged := invowkfile.MergeDependsOnAll(info.Invowkfile.DependsOn, info.Command.DependsOn, implDeps)
+		found, err := deps.ResolvePrerequisites(s.discovery, merged, info, deps.ExecutionContext{Context: ctx, CommandName: info.Name}, s.lockProvider)
+		if err != nil {
+			return nil, err
+		}
+		needs := make([]appexec.PrerequisiteID, 0, len(found))
+		for _, target := range found {
+			need := prerequisiteID(target)
+			if _, known := targets[need]; !known {
+				targets[need] = target
+				callers[need] = info
+			}
+			needs = append(needs, need)
+		}
+		return needs, nil
+	}
+
+	graph, err := appexec.BuildPrerequisiteGraph(root, resolve)
+	if err != nil {
+		return nil, nil, err
+	}
+	order := make([]invowkfile.CommandName, 0, len(graph.Order()))
+	for _, id := range graph.Order() {
+		order = append(order, id.Name)
+	}
+	if len(order) == 0 || req.DryRun {
+		return order, nil, nil
+	}
+
+	var (
+		mu    sync.Mutex
+		diags []Diagnostic
+	)
+	run := func(ctx context.Context, id appexec.PrerequisiteID) (types.ExitCode, error) {
+		target := targets[id]
+		if req.Verbose {
+			mu.Lock()
+			s.observer.PrerequisiteStarting(PrerequisiteEvent{CommandName: cmdInfo.Name, Prerequisite: id.Name})
+			mu.Unlock()
+		}
+
+		childReq := inheritedRequest(req, target)
+		childReq.prerequisitesScheduled = true
+		childReq.caller = callers[id]
+		result, childDiags, err := s.Execute(ctx, childReq)
+
+		mu.Lock()
+		diags = append(diags, childDiags...)
+		mu.Unlock()
+		return result.ExitCode, err
+	}
+
+	err = appexec.RunPrerequisites(ctx, graph, cfg.Execution.MaxParallel.Limit(), run)
+	return order, diags, err
+}
+
+// prerequisiteID identifies info in the prerequisite graph.
+func prerequisiteID(info *discovery.CommandInfo) appexec.PrerequisiteID {
+	return appexec.PrerequisiteID{SourceID: info.SourceID, Name: info.Name}
+}
+
+// prerequisiteImplementationDeps returns the implementation-level depends_on of
+// the implementation a prerequisite command would run with, or nil when none
+// can be selected (multi-step commands, or commands unavailable on the platform,
+// whose own execution reports the problem).
+func prerequisiteImplementationDeps(req Request, info *discovery.CommandInfo, cfg *config.Config) *invowkfile.DependsOn {
+	if info.Command.HasSteps() {
+		return nil
+	}
+	selection, err := appexec.ResolveRuntime(info.Command, info.Name, "", cfg, requestPlatform(req), whenContext(inheritedRequest(req, info), info))
+	if err != nil || selection.Impl() == nil {
+		return nil
+	}
+	return selection.Impl().DependsOn
+}
+
+// prerequisiteFailure converts a prerequisite run error into the command's
+// result. Like failing steps, a prerequisite that merely exited non-zero
+// propagates its exit code without an additional error.
+func prerequisiteFailure(err error) (Result, error) {
+	failed, ok := errors.AsType[*appexec.PrerequisiteFailedError](err)
+	if !ok {
+		return Result{}, err
+	}
+	if failed.Err == nil {
+		return Result{ExitCode: failed.ExitCode}, nil
+	}
+	return Result{ExitCode: failed.ExitCode}, err
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type recordingPrerequisiteObserver struct {
+	noopExecutionObserver
+	started []PrerequisiteEvent
+}
+
+func (o *recordingPrerequisiteObserver) PrerequisiteStarting(event PrerequisiteEvent) {
+	o.started = append(o.started, event)
+}
+
+func TestServiceExecutePrerequisites(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs shared prerequisites once before the command", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, observer := newPrerequisiteTestService(t, nil)
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "deploy", Verbose: true, VerboseSet: true})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 0 {
+			t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+		}
+		if got := ranScripts(rt); !slices.Equal(got, []invowkfile.ScriptContent{"echo build", "echo test", "echo deploy"}) {
+			t.Fatalf("ran = %v, want build, test, deploy", got)
+		}
+		if len(observer.started) != 2 || observer.started[0].CommandName != "deploy" {
+			t.Errorf("prerequisite events = %#v", observer.started)
+		}
+	})
+
+	t.Run("failing prerequisite fails the command", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newPrerequisiteTestService(t, nil)
+		rt.exitCodes = map[invowkfile.ScriptContent]types.ExitCode{"echo build": 2}
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "deploy"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 2 {
+			t.Fatalf("ExitCode = %d, want 2", result.ExitCode)
+		}
+		if got := ranScripts(rt); !slices.Equal(got, []invowkfile.ScriptContent{"echo build"}) {
+			t.Fatalf("ran = %v, want only build", got)
+		}
+	})
+
+	t.Run("dry run lists prerequisites", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newPrerequisiteTestService(t, nil)
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "deploy", DryRun: true})
+		if err != nil {
+			t.Fatalf("Execute(dry-run) error = %v", err)
+		}
+		if len(rt.ran) != 0 {
+			t.Fatalf("dry-run executed %d commands", len(rt.ran))
+		}
+		if result.DryRunData == nil {
+			t.Fatal("Execute(dry-run) did not return DryRunData")
+		}
+		plan := result.DryRunData.Plan
+		if !slices.Equal(plan.Prerequisites, []invowkfile.CommandName{"build", "test"}) {
+			t.Fatalf("plan.Prerequisites = %v, want [build test]", plan.Prerequisites)
+		}
+		if err := plan.Validate(); err != nil {
+			t.Fatalf("plan.Validate() = %v", err)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newPrerequisiteTestService(t, func(build *invowkfile.Command) {
+			build.DependsOn = &invowkfile.DependsOn{Commands: []invowkfile.CommandDependency{
+				{Alternatives: []invowkfile.CommandDependencyRef{"deploy"}, Run: true},
+			}}
+		})
+
+		_, _, err := service.Execute(t.Context(), Request{Name: "deploy"})
+		if !errors.Is(err, appexec.ErrPrerequisiteCycle) {
+			t.Fatalf("Execute() error = %v, want ErrPrerequisiteCycle", err)
+		}
+		if len(rt.ran) != 0 {
+			t.Fatalf("ran %d commands, want 0", len(rt.ran))
+		}
+	})
+}
+
+func ranScripts(rt *stepRecordingRuntime) []invowkfile.ScriptContent {
+	scripts := make([]invowkfile.ScriptContent, 0, len(rt.ran))
+	for _, run := range rt.ran {
+		scripts = append(scripts, run.script)
+	}
+	return scripts
+}
+
+// newPrerequisiteTestService builds a service whose "deploy" command runs
+// "test" and "build" as prerequisites, and "test" in turn runs "build".
+// "lint" is a discoverability-only dependency and must never run.
+func newPrerequisiteTestService(t *testing.T, mutateBuild func(*invowkfile.Command)) (*Service, *stepRecordingRuntime, *recordingPrerequisiteObserver) {
+	t.Helper()
+
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue"))}
+	newCommand := func(name string, prereqs ...invowkfile.CommandDependencyRef) *invowkfile.Command {
+		cmd := invowkfiletest.NewTestCommand(name,
+			invowkfiletest.WithScript("echo "+name),
+			invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+			invowkfiletest.WithAllPlatforms(),
+		)
+		if len(prereqs) > 0 {
+			cmd.DependsOn = &invowkfile.DependsOn{}
+			for _, ref := range prereqs {
+				cmd.DependsOn.Commands = append(cmd.DependsOn.Commands, invowkfile.CommandDependency{
+					Alternatives: []invowkfile.CommandDependencyRef{ref},
+					Run:          true,
+				})
+			}
+		}
+		return cmd
+	}
+
+	build := newCommand("build")
+	if mutateBuild != nil {
+		mutateBuild(build)
+	}
+	deploy := newCommand("deploy", "test", "build")
+	deploy.DependsOn.Commands = append(deploy.DependsOn.Commands, invowkfile.CommandDependency{
+		Alternatives: []invowkfile.CommandDependencyRef{"lint"},
+	})
+
+	set := discovery.NewDiscoveredCommandSet()
+	for _, cmd := range []*invowkfile.Command{build, newCommand("test", "build"), newCommand("lint"), deploy} {
+		set.Add(&discovery.CommandInfo{
+			Name:       cmd.Name,
+			SimpleName: cmd.Name,
+			SourceID:   discovery.SourceIDInvowkfile,
+			Command:    cmd,
+			Invowkfile: inv,
+		})
+	}
+	set.Analyze()
+
+	rt := &stepRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	observer := &recordingPrerequisiteObserver{}
+	cfg := config.DefaultConfig()
+	cfg.Execution.MaxParallel = 2
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, rt, observer
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// executeWithRetry runs the selected implementation through executeAttempt.
+// When the implementation declares a retry policy, a failed run whose exit
+// code, as the script returned it, is retryable is repeated after an
+// exponential backoff until it succeeds or the attempts are exhausted. Only
+// the result of the final run is reported with its translated exit code.
+// Runtime errors (including timeouts and cancellation) are never retried.
+// Every run sees its 1-based attempt number in INVOWK_ATTEMPT, and the
+// implementation timeout bounds all attempts together.
+func (s *Service) executeWithRetry(req Request, execCtx *runtime.ExecutionContext, cmdInfo *discovery.CommandInfo, session RuntimeSession) (*runtime.Result, invowkfile.RuntimeMode, error) {
+	var policy *invowkfile.RetryPolicy
+	if execCtx.SelectedImpl != nil {
+		policy = execCtx.SelectedImpl.Retry
+	}
+	if policy == nil || policy.Attempts <= 1 {
+		result, _, interactiveFallback, err := s.executeAttempt(req, execCtx, session)
+		return result, interactiveFallback, err
+	}
+
+	event := AttemptEvent{CommandName: cmdInfo.Name, Attempts: int(policy.Attempts)}
+	for attempt := 1; ; attempt++ {
+		event.Attempt = attempt
+		if attempt > 1 || req.Verbose {
+			s.observer.AttemptStarting(event)
+		}
+		execCtx.Env.ExtraEnv[runtime.EnvVarAttempt] = strconv.Itoa(attempt)
+
+		result, rawExitCode, interactiveFallback, err := s.executeAttempt(req, execCtx, session)
+		if err != nil || result.Error != nil || attempt == event.Attempts ||
+			result.ExitCode.IsSuccess() || !policy.ShouldRetry(rawExitCode) {
+			return result, interactiveFallback, err
+		}
+
+		delay, err := policy.Delay(attempt)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := sleepUntilRetry(execCtx, delay); err != nil {
+			return &runtime.Result{ExitCode: result.ExitCode, Error: err}, interactiveFallback, nil
+		}
+		event.ExitCode = rawExitCode
+		event.Delay = delay
+	}
+}
+
+// executeAttempt runs one attempt through executeWithRequestedMode. The
+// result's exit code is translated by the implementation's success_exit_codes
+// and exit_code_map; rawExitCode is the exit code before translation, which
+// retry policies match against. Commands that declare outputs get a fresh
+// output file for the attempt, and the outputs written to it are returned in
+// the result.
+func (s *Service) executeAttempt(req Request, execCtx *runtime.ExecutionContext, session RuntimeSession) (result *runtime.Result, rawExitCode types.ExitCode, interactiveFallback invowkfile.RuntimeMode, err error) {
+	outputFile, err := runtime.NewOutputFile(execCtx)
+	if err != nil {
+		return nil, 0, "", newClassifiedExecutionError(err)
+	}
+	result, interactiveFallback, err = s.executeWithRequestedMode(req, execCtx, session)
+	if result != nil {
+		rawExitCode = result.ExitCode
+	}
+	return outputFile.Collect(runtime.TranslateExitCode(execCtx, result)), rawExitCode, interactiveFallback, err
+}
+
+// sleepUntilRetry waits for the retry backoff, returning early with an error
+// when the execution context is cancelled or times out.
+func sleepUntilRetry(execCtx *runtime.ExecutionContext, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-execCtx.Context.Done():
+		return fmt.Errorf("retry aborted: %w", execCtx.Context.Err())
+	case <-timer.C:
+		return nil
+	}
+}
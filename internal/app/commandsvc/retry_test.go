@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var errRetryTestRuntime = errors.New("runtime failure")
+
+type (
+	// attemptRecordingRuntime returns exitCodes in order (the last one
+	// repeats) and records the INVOWK_ATTEMPT value of every run.
+	attemptRecordingRuntime struct {
+		exitCodes []types.ExitCode
+		err       error
+		attempts  []string
+	}
+
+	recordingAttemptObserver struct {
+		noopExecutionObserver
+		events []AttemptEvent
+	}
+)
+
+func (*attemptRecordingRuntime) Name() string { return string(invowkfile.RuntimeVirtualSh) }
+
+func (r *attemptRecordingRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	r.attempts = append(r.attempts, execCtx.Env.ExtraEnv[runtimepkg.EnvVarAttempt])
+	if r.err != nil {
+		return &runtimepkg.Result{ExitCode: 1, Error: r.err}
+	}
+	return &runtimepkg.Result{ExitCode: r.exitCodes[min(len(r.attempts), len(r.exitCodes))-1]}
+}
+
+func (*attemptRecordingRuntime) Available() bool { return true }
+
+func (*attemptRecordingRuntime) Validate(*runtimepkg.ExecutionContext) error { return nil }
+
+func (o *recordingAttemptObserver) AttemptStarting(event AttemptEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestServiceExecuteRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		retry        invowkfile.RetryPolicy
+		exitCodes    []types.ExitCode
+		runtimeErr   error
+		verbose      bool
+		wantAttempts []string
+		wantExitCode types.ExitCode
+		wantErr      error
+		wantEvents   []AttemptEvent
+	}{
+		{
+			name:         "retries until success",
+			retry:        invowkfile.RetryPolicy{Attempts: 4, Backoff: "1ms"},
+			exitCodes:    []types.ExitCode{7, 7, 0},
+			wantAttempts: []string{"1", "2", "3"},
+			wantEvents: []AttemptEvent{
+				{CommandName: "fetch", Attempt: 2, Attempts: 4, ExitCode: 7, Delay: time.Millisecond},
+				{CommandName: "fetch", Attempt: 3, Attempts: 4, ExitCode: 7, Delay: 2 * time.Millisecond},
+			},
+		},
+		{
+			name:         "exhausted attempts keep the last exit code",
+			retry:        invowkfile.RetryPolicy{Attempts: 2, Backoff: "1ms"},
+			exitCodes:    []types.ExitCode{7, 9},
+			wantAttempts: []string{"1", "2"},
+			wantExitCode: 9,
+			wantEvents:   []AttemptEvent{{CommandName: "fetch", Attempt: 2, Attempts: 2, ExitCode: 7, Delay: time.Millisecond}},
+		},
+		{
+			name:         "unlisted exit code is not retried",
+			retry:        invowkfile.RetryPolicy{Attempts: 3, Backoff: "1ms", OnExitCodes: []types.ExitCode{75}},
+			exitCodes:    []types.ExitCode{1},
+			wantAttempts: []string{"1"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "runtime error is not retried",
+			retry:        invowkfile.RetryPolicy{Attempts: 3, Backoff: "1ms"},
+			runtimeErr:   errRetryTestRuntime,
+			wantAttempts: []string{"1"},
+			wantErr:      errRetryTestRuntime,
+		},
+		{
+			name:         "verbose reports the first attempt",
+			retry:        invowkfile.RetryPolicy{Attempts: 3},
+			exitCodes:    []types.ExitCode{0},
+			verbose:      true,
+			wantAttempts: []string{"1"},
+			wantEvents:   []AttemptEvent{{CommandName: "fetch", Attempt: 1, Attempts: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := &attemptRecordingRuntime{exitCodes: tt.exitCodes, err: tt.runtimeErr}
+			service, observer := newRetryTestService(t, rt, tt.retry)
+
+			result, _, err := service.Execute(t.Context(), Request{Name: "fetch", Verbose: tt.verbose, VerboseSet: tt.verbose})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && result.ExitCode != tt.wantExitCode {
+				t.Fatalf("ExitCode = %d, want %d", result.ExitCode, tt.wantExitCode)
+			}
+			if !slices.Equal(rt.attempts, tt.wantAttempts) {
+				t.Fatalf("INVOWK_ATTEMPT values = %q, want %q", rt.attempts, tt.wantAttempts)
+			}
+			if !slices.Equal(observer.events, tt.wantEvents) {
+				t.Fatalf("AttemptStarting events = %+v, want %+v", observer.events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestServiceExecuteRetryBackoffHonorsCancellation(t *testing.T) {
+	t.Parallel()
+
+	rt := &attemptRecordingRuntime{exitCodes: []types.ExitCode{7}}
+	service, _ := newRetryTestService(t, rt, invowkfile.RetryPolicy{Attempts: 3, Backoff: "1h"})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err := service.Execute(ctx, Request{Name: "fetch"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Execute() error = %v, want context.DeadlineExceeded", err)
+	}
+	if len(rt.attempts) != 1 {
+		t.Fatalf("ran %d attempts, want 1 before the backoff was interrupted", len(rt.attempts))
+	}
+}
+
+func TestServiceExecuteExitCodePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		retry        invowkfile.RetryPolicy
+		exitCodes    []types.ExitCode
+		runtimeErr   error
+		wantAttempts []string
+		wantExitCode types.ExitCode
+		wantErr      error
+	}{
+		{
+			name:         "success exit code is neither retried nor a failure",
+			retry:        invowkfile.RetryPolicy{Attempts: 3, Backoff: "1ms"},
+			exitCodes:    []types.ExitCode{1},
+			wantAttempts: []string{"1"},
+		},
+		{
+			name:         "retries match the script exit code and report the translated one",
+			retry:        invowkfile.RetryPolicy{Attempts: 3, Backoff: "1ms", OnExitCodes: []types.ExitCode{2, 3}},
+			exitCodes:    []types.ExitCode{2, 3, 3},
+			wantAttempts: []string{"1", "2", "3"},
+			wantExitCode: 10,
+		},
+		{
+			name:         "translated exit code does not trigger a retry",
+			retry:        invowkfile.RetryPolicy{Attempts: 3, Backoff: "1ms", OnExitCodes: []types.ExitCode{75}},
+			exitCodes:    []types.ExitCode{2, 0},
+			wantAttempts: []string{"1"},
+			wantExitCode: 75,
+		},
+		{
+			name:         "mapped exit code is reported",
+			retry:        invowkfile.RetryPolicy{Attempts: 1},
+			exitCodes:    []types.ExitCode{3},
+			wantAttempts: []string{"1"},
+			wantExitCode: 10,
+		},
+		{
+			name:         "runtime error is not translated",
+			retry:        invowkfile.RetryPolicy{Attempts: 1},
+			runtimeErr:   errRetryTestRuntime,
+			wantAttempts: []string{"1"},
+			wantErr:      errRetryTestRuntime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := &attemptRecordingRuntime{exitCodes: tt.exitCodes, err: tt.runtimeErr}
+			service, _ := newRetryTestService(t, rt, tt.retry, func(impl *invowkfile.Implementation) {
+				impl.SuccessExitCodes = []types.ExitCode{1}
+				impl.ExitCodeMap = invowkfile.ExitCodeMap{2: 75, 3: 10}
+			})
+
+			result, _, err := service.Execute(t.Context(), Request{Name: "fetch"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && result.ExitCode != tt.wantExitCode {
+				t.Fatalf("ExitCode = %d, want %d", result.ExitCode, tt.wantExitCode)
+			}
+			if !slices.Equal(rt.attempts, tt.wantAttempts) {
+				t.Fatalf("INVOWK_ATTEMPT values = %q, want %q", rt.attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// newRetryTestService builds a service running "fetch" with the retry policy
+// on rt; configure adjusts the implementation further.
+func newRetryTestService(t *testing.T, rt *attemptRecordingRuntime, retry invowkfile.RetryPolicy, configure ...func(*invowkfile.Implementation)) (*Service, *recordingAttemptObserver) {
+	t.Helper()
+
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue"))}
+	fetch := invowkfiletest.NewTestCommand("fetch",
+		invowkfiletest.WithScript("curl -fsSL https://example.com"),
+		invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+		invowkfiletest.WithAllPlatforms(),
+	)
+	fetch.Implementations[0].Retry = &retry
+	for _, fn := range configure {
+		fn(&fetch.Implementations[0])
+	}
+
+	set := discovery.NewDiscoveredCommandSet()
+	set.Add(&discovery.CommandInfo{
+		Name:       fetch.Name,
+		SimpleName: fetch.Name,
+		FilePath:   inv.FilePath,
+		SourceID:   discovery.SourceIDInvowkfile,
+		Command:    fetch,
+		Invowkfile: inv,
+	})
+	set.Analyze()
+
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	observer := &recordingAttemptObserver{}
+	cfg := config.DefaultConfig()
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, observer
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// ErrCommandLocked is returned when the lock of a command is held by another
+// process and the run does not wait for it, or gave up waiting.
+var ErrCommandLocked = errors.New("command locked")
+
+// CommandLockedError is returned when the lock of a command is held by
+// another process. It wraps ErrCommandLocked for errors.Is() compatibility.
+type CommandLockedError struct {
+	CommandName invowkfile.CommandName
+	Lock        invowkfile.LockName
+	// Holder is the process holding the lock (zero when unknown).
+	Holder appexec.RunLockHolder
+	// Waited is how long the run waited before giving up (zero when the
+	// lock does not wait).
+	Waited time.Duration
+}
+
+// Error implements the error interface.
+func (e *CommandLockedError) Error() string {
+	if e.Waited > 0 {
+		return fmt.Sprintf("command '%s' is locked: lock '%s' is still held by %s after waiting %s", e.CommandName, e.Lock, e.Holder, e.Waited)
+	}
+	return fmt.Sprintf("command '%s' is locked: lock '%s' is held by %s", e.CommandName, e.Lock, e.Holder)
+}
+
+// Unwrap returns ErrCommandLocked for errors.Is() compatibility.
+func (e *CommandLockedError) Unwrap() error { return ErrCommandLocked }
+
+// acquireRunLock takes the lock of a command that declares one, after the
+// confirmation question and before any prerequisite, hook, or script runs.
+// A held lock fails the run right away unless the lock waits for it. The
+// returned context records the lock so nested runs that declare the same
+// lock do not wait for their parent; the release func must be called when
+// the run ends. Dry runs execute nothing and take no lock.
+func (s *Service) acquireRunLock(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo) (context.Context, func(), error) {
+	lock := cmdInfo.Command.Lock
+	if lock == nil || req.DryRun {
+		return ctx, func() {}, nil
+	}
+	name := lock.EffectiveName(cmdInfo.Name)
+	projectDir := types.FilesystemPath(filepath.Dir(string(cmdInfo.FilePath))) //goplint:ignore -- directory of a discovered invowkfile path.
+	runLock := appexec.NewRunLock(name, lock.EffectiveScope(), projectDir)
+	if appexec.HoldsRunLock(ctx, runLock.Key) {
+		return ctx, func() {}, nil
+	}
+
+	locker := s.commandRunLocker()
+	release, holder, err := locker.TryLock(runLock)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("lock command '%s': %w", cmdInfo.Name, err)
+	}
+	if release == nil {
+		if !lock.Wait {
+			return ctx, nil, &CommandLockedError{CommandName: cmdInfo.Name, Lock: name, Holder: holder}
+		}
+		event := RunLockEvent{CommandName: cmdInfo.Name, Lock: name, Holder: holder}
+		if release, err = s.waitForRunLock(ctx, lock, runLock, event); err != nil {
+			return ctx, nil, err
+		}
+	}
+	return appexec.WithRunLock(ctx, runLock.Key), release, nil
+}
+
+// waitForRunLock waits for a lock held by another process, up to the lock
+// timeout. Giving up after the timeout reports a CommandLockedError.
+func (s *Service) waitForRunLock(ctx context.Context, lock *invowkfile.CommandLock, runLock appexec.RunLock, event RunLockEvent) (func(), error) {
+	timeout, err := lock.EffectiveTimeout()
+	if err != nil {
+		return nil, err
+	}
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	s.observer.RunLockWaiting(event)
+	release, err := s.commandRunLocker().Lock(waitCtx, runLock)
+	s.observer.RunLockWaitEnded(event)
+	if err == nil {
+		return release, nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, &CommandLockedError{CommandName: event.CommandName, Lock: event.Lock, Holder: event.Holder, Waited: timeout}
+	}
+	return nil, fmt.Errorf("wait for lock of command '%s': %w", event.CommandName, err)
+}
+
+func (s *Service) commandRunLocker() RunLocker {
+	if s.runLocker == nil {
+		return noopRunLocker{}
+	}
+	return s.runLocker
+}
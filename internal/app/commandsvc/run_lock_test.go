@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// fakeRunLocker reports its lock as held by holder when busy. Lock succeeds
+// when frees is set and otherwise waits for ctx.
+type fakeRunLocker struct {
+	busy     bool
+	frees    bool
+	holder   appexec.RunLockHolder
+	locks    []appexec.RunLock
+	waits    int
+	releases int
+}
+
+func (l *fakeRunLocker) TryLock(lock appexec.RunLock) (func(), appexec.RunLockHolder, error) {
+	l.locks = append(l.locks, lock)
+	if l.busy {
+		return nil, l.holder, nil
+	}
+	return func() { l.releases++ }, appexec.RunLockHolder{}, nil
+}
+
+func (l *fakeRunLocker) Lock(ctx context.Context, _ appexec.RunLock) (func(), error) {
+	l.waits++
+	if l.frees {
+		return func() { l.releases++ }, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestServiceExecuteRunLock(t *testing.T) {
+	t.Parallel()
+
+	holder := appexec.RunLockHolder{PID: 4242, Started: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)}
+	tests := []struct {
+		name       string
+		lock       invowkfile.CommandLock
+		locker     *fakeRunLocker
+		dryRun     bool
+		wantRan    bool
+		wantWaited time.Duration
+		wantLocked bool
+	}{
+		{name: "free lock", locker: &fakeRunLocker{}, wantRan: true},
+		{name: "held lock fails", locker: &fakeRunLocker{busy: true, holder: holder}, wantLocked: true},
+		{name: "held lock waits", lock: invowkfile.CommandLock{Wait: true}, locker: &fakeRunLocker{busy: true, frees: true}, wantRan: true},
+		{
+			name:       "wait times out",
+			lock:       invowkfile.CommandLock{Wait: true, Timeout: "10ms"},
+			locker:     &fakeRunLocker{busy: true, holder: holder},
+			wantLocked: true,
+			wantWaited: 10 * time.Millisecond,
+		},
+		{name: "dry run takes no lock", locker: &fakeRunLocker{busy: true}, dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, rt, _ := newHookTestService(t, nil, nil, "deploy", "")
+			service.runLocker = tt.locker
+			stub, ok := service.discovery.(*stubCommandDiscovery)
+			if !ok {
+				t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+			}
+			stub.commandSet.Set.Commands[0].Command.Lock = &tt.lock
+
+			_, _, err := service.Execute(t.Context(), Request{Name: "deploy", DryRun: tt.dryRun})
+			locked, isLocked := errors.AsType[*CommandLockedError](err)
+			if isLocked != tt.wantLocked || (!isLocked && err != nil) {
+				t.Fatalf("Execute() error = %v, want CommandLockedError: %v", err, tt.wantLocked)
+			}
+			if isLocked {
+				if locked.Lock != "deploy" || locked.Holder != holder || locked.Waited != tt.wantWaited {
+					t.Errorf("CommandLockedError = %+v, want lock 'deploy' held by %v, waited %s", locked, holder, tt.wantWaited)
+				}
+				if !errors.Is(err, ErrCommandLocked) {
+					t.Errorf("Execute() error = %v, want ErrCommandLocked", err)
+				}
+			}
+			if ran := slices.Contains(rt.ran, "deploy"); ran != tt.wantRan {
+				t.Errorf("script ran = %v, want %v", ran, tt.wantRan)
+			}
+			if tt.dryRun && len(tt.locker.locks) > 0 {
+				t.Errorf("dry run tried %d locks, want none", len(tt.locker.locks))
+			}
+			if tt.wantRan && tt.locker.releases != 1 {
+				t.Errorf("lock released %d times, want 1", tt.locker.releases)
+			}
+		})
+	}
+}
+
+func TestServiceAcquireRunLockNested(t *testing.T) {
+	t.Parallel()
+
+	service, _, _ := newHookTestService(t, nil, nil, "deploy", "")
+	locker := &fakeRunLocker{}
+	service.runLocker = locker
+	stub, ok := service.discovery.(*stubCommandDiscovery)
+	if !ok {
+		t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+	}
+	cmdInfo := stub.commandSet.Set.Commands[0]
+	cmdInfo.Command.Lock = &invowkfile.CommandLock{Name: "db"}
+
+	ctx, release, err := service.acquireRunLock(t.Context(), Request{Name: "deploy"}, cmdInfo)
+	if err != nil {
+		t.Fatalf("acquireRunLock() error = %v", err)
+	}
+	defer release()
+	if want := types.FilesystemPath(filepath.Dir(string(cmdInfo.FilePath))); locker.locks[0].ProjectDir != want {
+		t.Errorf("lock ProjectDir = %q, want the invowkfile directory %q", locker.locks[0].ProjectDir, want)
+	}
+
+	// A nested run of a command with the same lock, e.g. a step, must not
+	// wait for its parent.
+	locker.busy = true
+	_, nestedRelease, err := service.acquireRunLock(ctx, Request{Name: "deploy"}, cmdInfo)
+	if err != nil {
+		t.Fatalf("nested acquireRunLock() error = %v", err)
+	}
+	nestedRelease()
+	if len(locker.locks) != 1 {
+		t.Errorf("TryLock() called %d times, want 1 (nested run reuses the held lock)", len(locker.locks))
+	}
+}
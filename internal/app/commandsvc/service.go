@@ -9,6 +9,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/invowk/invowk/internal/app/deps"
 	"github.com/invowk/invowk/internal/config"
@@ -36,8 +37,14 @@ type (
 		hostProbe         deps.HostProbe
 		lockProvider      deps.CommandScopeLockProvider
 		scriptFileReader  deps.ScriptFileReader
+		fingerprints      FingerprintStore
+		completions       CompletionCache
+		confirmer         Confirmer
+		runLocker         RunLocker
 		userEnvFunc       UserEnvFunc
 		configFallback    ConfigFallbackFunc
+		// finallyTimeout overrides defaultFinallyHookTimeout when positive.
+		finallyTimeout time.Duration
 	}
 
 	ports struct {
@@ -50,6 +57,10 @@ type (
 		hostProbe         deps.HostProbe
 		lockProvider      deps.CommandScopeLockProvider
 		scriptFileReader  deps.ScriptFileReader
+		fingerprints      FingerprintStore
+		completions       CompletionCache
+		confirmer         Confirmer
+		runLocker         RunLocker
 	}
 
 	// ConfigFallbackFunc loads configuration with fallback to defaults on failure.
@@ -68,6 +79,10 @@ func NewPorts(
 	hostProbe deps.HostProbe,
 	lockProvider deps.CommandScopeLockProvider,
 	scriptFileReader deps.ScriptFileReader,
+	fingerprints FingerprintStore,
+	completions CompletionCache,
+	confirmer Confirmer,
+	runLocker RunLocker,
 ) ports {
 	return ports{
 		hostAccess:        hostAccess,
@@ -79,6 +94,10 @@ func NewPorts(
 		hostProbe:         hostProbe,
 		lockProvider:      lockProvider,
 		scriptFileReader:  scriptFileReader,
+		fingerprints:      fingerprints,
+		completions:       completions,
+		confirmer:         confirmer,
+		runLocker:         runLocker,
 	}
 }
 
@@ -106,6 +125,10 @@ func New(
 		interactive:     defaultInteractiveExecutor{},
 		observer:        noopExecutionObserver{},
 		requestScope:    beginNoopRequestScope,
+		fingerprints:    noopFingerprintStore{},
+		completions:     noopCompletionCache{},
+		confirmer:       unavailableConfirmer{},
+		runLocker:       noopRunLocker{},
 		userEnvFunc:     userEnvFunc,
 		configFallback:  configFallback,
 	}
@@ -136,18 +159,40 @@ func New(
 	if servicePorts.scriptFileReader != nil {
 		svc.scriptFileReader = servicePorts.scriptFileReader
 	}
+	if servicePorts.fingerprints != nil {
+		svc.fingerprints = servicePorts.fingerprints
+	}
+	if servicePorts.completions != nil {
+		svc.completions = servicePorts.completions
+	}
+	if servicePorts.confirmer != nil {
+		svc.confirmer = servicePorts.confirmer
+	}
+	if servicePorts.runLocker != nil {
+		svc.runLocker = servicePorts.runLocker
+	}
 	return svc
 }
 
 // Execute executes an invowk command through the full orchestration pipeline:
 //  1. Validates the request struct fields.
 //  2. Loads config and discovers the target command by name.
-//  3. Validates inputs: flags, arguments, platform compatibility, and runtime compatibility.
+//  3. Validates inputs: flags, arguments, platform compatibility, and runtime compatibility,
+//     then asks the confirmation question of commands that declare one (see confirmExecution)
+//     and takes the command's lock, held until the run ends (see acquireRunLock).
 //  4. Manages host-access lifecycle when the container runtime needs host callbacks.
 //  5. Builds execution context with env var projection (INVOWK_FLAG_*, INVOWK_ARG_*, ARGn).
 //  6. Propagates incoming context for timeout and cancellation signals.
 //  7. Dry-run intercept: if DryRun is set, returns structured data for rendering.
-//  8. Dispatches execution (timeout → dep validation → runtime).
+//  8. Skips commands that declare sources when their fingerprint is up to date.
+//  9. Dispatches execution (timeout → dep validation → runtime) wrapped in the
+//     merged root and command lifecycle hooks (see runWithHooks), and records
+//     the fingerprint of successful incremental runs.
+//
+// Commands that declare steps branch off after input validation and run each
+// step in order (see executeSteps). Deprecated commands are reported to the
+// observer right after discovery and may forward to their replacement (see
+// handleDeprecation).
 func (s *Service) Execute(ctx context.Context, req Request) (Result, []Diagnostic, error) {
 	// Validate typed fields before any downstream work to catch programmatic misuse early.
 	if err := req.Validate(); err != nil {
@@ -164,28 +209,112 @@ func (s *Service) Execute(ctx context.Context, req Request) (Result, []Diagnosti
 	if req.Platform == "" {
 		req.Platform = invowkfile.CurrentPlatform()
 	}
+	if req.outputs == nil {
+		req.outputs = &invocationOutputs{}
+	}
 
 	cfg, cmdInfo, req, diags, err := s.discoverCommand(ctx, req)
 	if err != nil {
 		return Result{}, diags, err
 	}
 
+	if visibilityErr := s.checkVisibility(ctx, cfg, req, cmdInfo); visibilityErr != nil {
+		return Result{}, diags, visibilityErr
+	}
+
+	if cmdInfo.Command.Deprecated != nil {
+		forwarded, fwdReq, fwdDiags, fwdErr := s.handleDeprecation(ctx, req, cmdInfo, cfg)
+		diags = append(diags, fwdDiags...)
+		if fwdErr != nil {
+			return Result{}, diags, fwdErr
+		}
+		if forwarded {
+			result, childDiags, childErr := s.Execute(ctx, fwdReq)
+			return result, append(diags, childDiags...), childErr
+		}
+	}
+
 	defs := s.resolveDefinitions(req, cmdInfo)
 
 	if validErr := s.validateInputs(req, cmdInfo, defs); validErr != nil {
 		return Result{}, diags, validErr
 	}
 
+	if confirmErr := s.confirmExecution(ctx, req, cmdInfo); confirmErr != nil {
+		return Result{}, diags, confirmErr
+	}
+
+	ctx, release, lockErr := s.acquireRunLock(ctx, req, cmdInfo)
+	if lockErr != nil {
+		return Result{}, diags, lockErr
+	}
+	defer release()
+
+	if cmdInfo.Command.Matrix != nil {
+		return s.executeMatrix(ctx, req, cmdInfo, cfg, defs, diags)
+	}
+	return s.executeCommand(ctx, req, cmdInfo, cfg, defs, diags)
+}
+
+// executeCommand runs a discovered, confirmed, and locked command: its
+// prerequisites, hooks, and steps or selected implementation. Each
+// combination of a matrix command runs through it.
+func (s *Service) executeCommand(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, diags []Diagnostic) (Result, []Diagnostic, error) {
+	// Step commands have no implementation of their own; each step selects
+	// its runtime independently.
+	if cmdInfo.Command.HasSteps() {
+		prereqs, prereqDiags, prereqErr := s.runPrerequisites(ctx, req, cmdInfo, cfg, nil)
+		diags = append(diags, prereqDiags...)
+		if prereqErr != nil {
+			result, resultErr := prerequisiteFailure(prereqErr)
+			return result, diags, resultErr
+		}
+		runSteps := func(diags []Diagnostic) (Result, []Diagnostic, error) {
+			return s.executeSteps(ctx, req, cmdInfo, cfg, defs, diags)
+		}
+		if !req.DryRun {
+			return s.runWithHooks(ctx, req, cmdInfo, cfg, defs, diags, runSteps)
+		}
+		result, stepDiags, stepErr := runSteps(diags)
+		if result.DryRunData != nil {
+			result.DryRunData.Plan.Prerequisites = prereqs
+			result.DryRunData.Plan.Hooks = dryRunHooks(cmdInfo)
+		}
+		return result, stepDiags, stepErr
+	}
+
 	resolved, err := s.resolveRuntime(req, cmdInfo, cfg)
 	if err != nil {
 		return Result{}, diags, err
 	}
+	if req.Verbose && !req.DryRun && resolved.Reason() != "" {
+		s.observer.ImplementationSelected(ImplementationEvent{
+			CommandName: cmdInfo.Name,
+			Runtime:     resolved.Mode(),
+			Reason:      resolved.Reason(),
+		})
+	}
+
+	// Prerequisites (depends_on.cmds with run: true) run before the command's
+	// own context is built; in dry-run mode they are only listed in the plan.
+	prereqs, prereqDiags, err := s.runPrerequisites(ctx, req, cmdInfo, cfg, resolved.Impl())
+	diags = append(diags, prereqDiags...)
+	if err != nil {
+		result, resultErr := prerequisiteFailure(err)
+		return result, diags, resultErr
+	}
 
 	execCtx, err := s.buildExecContext(ctx, req, cmdInfo, defs, resolved)
 	if err != nil {
 		return Result{}, diags, err
 	}
 
+	incremental, incrementalDiags, err := s.checkIncremental(req, cmdInfo, execCtx)
+	diags = append(diags, incrementalDiags...)
+	if err != nil {
+		return Result{}, diags, err
+	}
+
 	scriptAnalysis, hasScriptAnalysis := analyzeSelectedImplementationScript(execCtx)
 
 	// Dry-run mode returns structured data for the CLI adapter to render.
@@ -196,6 +325,12 @@ func (s *Service) Execute(ctx context.Context, req Request) (Result, []Diagnosti
 		if planErr != nil {
 			return Result{}, diags, planErr
 		}
+		plan.Selection = resolved.Reason()
+		plan.Prerequisites = prereqs
+		plan.Hooks = dryRunHooks(cmdInfo)
+		if incremental != nil {
+			plan.Freshness = incremental.freshness.String()
+		}
 		return Result{
 			ExitCode: 0,
 			DryRunData: &DryRunData{
@@ -203,6 +338,10 @@ func (s *Service) Execute(ctx context.Context, req Request) (Result, []Diagnosti
 			},
 		}, diags, nil
 	}
+	if incremental != nil && incremental.freshness.UpToDate() {
+		s.observer.CommandUpToDate(cmdInfo.Name)
+		return Result{}, diags, nil
+	}
 	if hasScriptAnalysis {
 		diags = appendScriptInterpreterDiagnostics(diags, scriptAnalysis)
 	}
@@ -218,7 +357,13 @@ func (s *Service) Execute(ctx context.Context, req Request) (Result, []Diagnosti
 		defer s.hostAccess.Stop()
 	}
 
-	return s.dispatchExecution(req, execCtx, cmdInfo, cfg, diags) //nolint:contextcheck // execCtx carries ctx through the runtime/dependency pipeline.
+	result, diags, err := s.runWithHooks(ctx, req, cmdInfo, cfg, defs, diags, func(diags []Diagnostic) (Result, []Diagnostic, error) {
+		return s.dispatchExecution(req, execCtx, cmdInfo, cfg, diags) //nolint:contextcheck // execCtx carries ctx through the runtime/dependency pipeline.
+	})
+	if succeeded(result, err) && incremental != nil {
+		diags = append(diags, s.recordIncremental(incremental, cmdInfo.Name)...)
+	}
+	return result, diags, err
 }
 
 func newDryRunPlan(
@@ -235,13 +380,17 @@ func newDryRunPlan(
 		Runtime:                     execCtx.SelectedRuntime,
 		Platform:                    execCtx.SelectedPlatform,
 		WorkDir:                     execCtx.WorkDir,
-		Env:                         dryRunEnv(execCtx),
+		Secrets:                     dryRunSecrets(execCtx, impl),
 		DependencyValidationSkipped: true,
 	}
+	plan.Env = redactDryRunEnv(dryRunEnv(execCtx), plan.Secrets)
 	if impl != nil {
 		plan.Timeout = impl.Timeout
+		plan.Retry = impl.Retry
+		plan.SuccessExitCodes = impl.SuccessExitCodes
+		plan.ExitCodeMap = impl.ExitCodeMap
 		plan.Script = impl.Script
-		filesystem := impl.VirtualFilesystemForPlatform(execCtx.SelectedPlatform)
+		filesystem := impl.VirtualFilesystemForPlatform(execCtx.SelectedPlatform, execCtx.SelectedArch)
 		plan.VirtualFilesystemAccess = filesystem.EffectiveAccess()
 		plan.VirtualFilesystemPaths = filesystem.Paths
 		if rtConfig := impl.GetRuntimeConfig(execCtx.SelectedRuntime); rtConfig != nil {
@@ -249,6 +398,11 @@ func newDryRunPlan(
 			plan.BinaryLookupMode = rtConfig.BinaryLookupMode
 			plan.LuaCPULimit = rtConfig.CPULimit
 			plan.LuaMemoryLimit = rtConfig.MemoryLimit
+			plan.StarlarkMaxSteps = rtConfig.MaxSteps
+			plan.RemoteHost = rtConfig.Host
+			plan.RemoteUser = rtConfig.User
+			plan.RemotePort = rtConfig.Port
+			plan.Sandbox = rtConfig.Sandbox
 		}
 	}
 	if hasScriptAnalysis {
@@ -297,6 +451,44 @@ func dryRunEnv(execCtx *runtime.ExecutionContext) map[string]string {
 	return env
 }
 
+// dryRunSecrets returns the provider of every declared env secret. A secret
+// redeclared at a more specific level reports that level's provider.
+func dryRunSecrets(execCtx *runtime.ExecutionContext, impl *invowkfile.Implementation) map[invowkfile.EnvVarName]invowkfile.SecretProviderType {
+	levels := make([]*invowkfile.EnvConfig, 0, 3)
+	if execCtx.Invowkfile != nil {
+		levels = append(levels, execCtx.Invowkfile.Env)
+	}
+	if execCtx.Command != nil {
+		levels = append(levels, execCtx.Command.Env)
+	}
+	if impl != nil {
+		levels = append(levels, impl.Env)
+	}
+	var secrets map[invowkfile.EnvVarName]invowkfile.SecretProviderType
+	for _, env := range levels {
+		for name, source := range env.GetSecrets() {
+			if secrets == nil {
+				secrets = make(map[invowkfile.EnvVarName]invowkfile.SecretProviderType)
+			}
+			secrets[name] = source.Provider
+		}
+	}
+	return secrets
+}
+
+// redactDryRunEnv masks projected env values whose name is a declared secret:
+// an --ivk-env-file or --ivk-env-var value for it is just as sensitive.
+//
+//goplint:ignore -- environment maps are stringly typed by os/exec and container APIs.
+func redactDryRunEnv(env map[string]string, secrets map[invowkfile.EnvVarName]invowkfile.SecretProviderType) map[string]string {
+	for name := range secrets {
+		if _, ok := env[string(name)]; ok {
+			env[string(name)] = runtime.RedactedSecret
+		}
+	}
+	return env
+}
+
 //goplint:ignore -- environment maps are stringly typed by os/exec and container APIs.
 func copyStringMap(src map[string]string) map[string]string {
 	if src == nil {
@@ -533,9 +725,9 @@ func (s *Service) discoverCommandFromSource(ctx context.Context, cfg *config.Con
 	var target *discovery.CommandInfo
 	matchLen := 0
 	for i := len(tokens); i > 0; i-- {
-		candidate := strings.Join(tokens[:i], " ")
+		candidate := invowkfile.CommandName(strings.Join(tokens[:i], " ")) //goplint:ignore -- only compared against discovered command names.
 		for _, cmd := range result.Set.BySource[req.FromSource] {
-			if string(cmd.SimpleName) == candidate || string(cmd.Name) == candidate {
+			if cmd.SimpleName == candidate || cmd.Name == candidate || cmd.HasAlias(candidate) {
 				target = cmd
 				matchLen = i
 				break
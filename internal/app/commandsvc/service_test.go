@@ -386,6 +386,43 @@ func TestNewDryRunPlanRejectsInvalidPersistentPlan(t *testing.T) {
 	}
 }
 
+func TestNewDryRunPlanListsSecretsWithoutResolving(t *testing.T) {
+	t.Parallel()
+
+	inv := &invowkfile.Invowkfile{
+		FilePath: invowkfile.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue")),
+		Env: &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+			"TOKEN": {Provider: invowkfile.SecretProviderFile, Path: "missing.secret"},
+		}},
+	}
+	cmd := &invowkfile.Command{
+		Name: "deploy",
+		Implementations: []invowkfile.Implementation{{
+			Script:    invowkfile.ImplementationScript{Content: "echo deploy"},
+			Runtimes:  []invowkfile.RuntimeConfig{{Name: invowkfile.RuntimeNative}},
+			Platforms: invowkfile.AllPlatformConfigs(),
+			Env: &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+				"TOKEN": {Provider: invowkfile.SecretProviderCommand, Command: []string{"pass", "show", "token"}},
+			}},
+		}},
+	}
+	execCtx := runtimepkg.NewExecutionContext(t.Context(), cmd, inv)
+	execCtx.SelectedRuntime = invowkfile.RuntimeNative
+	execCtx.SelectedImpl = &cmd.Implementations[0]
+	execCtx.Env.RuntimeEnvVars = map[string]string{"TOKEN": "from-cli", "REGION": "eu"}
+
+	plan, err := newDryRunPlan(Request{Name: "deploy"}, &discovery.CommandInfo{}, execCtx, &cmd.Implementations[0], invowkfile.ScriptInterpreterAnalysis{}, false)
+	if err != nil {
+		t.Fatalf("newDryRunPlan() error = %v", err)
+	}
+	if got := plan.Secrets["TOKEN"]; got != invowkfile.SecretProviderCommand {
+		t.Errorf("Secrets[TOKEN] = %q, want the implementation-level provider", got)
+	}
+	if plan.Env["TOKEN"] != runtimepkg.RedactedSecret || plan.Env["REGION"] != "eu" {
+		t.Errorf("Env = %v, want TOKEN masked and REGION kept", plan.Env)
+	}
+}
+
 func (h *recordingHostAccess) Ensure(context.Context) error {
 	h.ensureCalls++
 	h.running = true
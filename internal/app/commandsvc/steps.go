@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// ErrUnknownStepFlag is returned when a cmd step binds a flag that the invoked
+// command does not declare.
+var ErrUnknownStepFlag = errors.New("unknown flag bound by command step")
+
+// executeSteps runs a sequential multi-step command. Each step either
+// re-enters Execute for the referenced command (cmd steps) or dispatches the
+// inline script through the declaring command's execution context (script
+// steps). In dry-run mode every step is planned instead of executed and the
+// combined step plan is returned in DryRunData.
+func (s *Service) executeSteps(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, diags []Diagnostic) (Result, []Diagnostic, error) {
+	ctx, err := appexec.EnterStepCommand(ctx, cmdInfo.Name)
+	if err != nil {
+		return Result{}, diags, err
+	}
+
+	steps := cmdInfo.Command.Steps
+	var stepPlans []DryRunStepPlan
+	run := func(ctx context.Context, index int, step *invowkfile.CommandStep) (types.ExitCode, error) {
+		event := StepEvent{CommandName: cmdInfo.Name, Index: index, Total: len(steps), Label: step.Label()}
+		if req.Verbose && !req.DryRun {
+			s.observer.StepStarting(event)
+		}
+
+		result, stepDiags, stepErr := s.executeStep(ctx, req, cmdInfo, cfg, defs, step)
+		diags = append(diags, stepDiags...)
+		if req.DryRun && stepErr == nil && result.DryRunData != nil {
+			stepPlans = append(stepPlans, DryRunStepPlan{
+				Label:           step.Label(),
+				Cmd:             step.Cmd,
+				ContinueOnError: step.ContinueOnError,
+				Plan:            result.DryRunData.Plan,
+			})
+		}
+
+		if step.ContinueOnError && (stepErr != nil || result.ExitCode != 0) {
+			event.ExitCode = result.ExitCode
+			event.Err = stepErr
+			s.observer.StepFailed(event)
+		}
+		return result.ExitCode, stepErr
+	}
+
+	stepsResult := appexec.RunSteps(ctx, cmdInfo.Name, steps, run)
+	if stepsResult.Failure != nil {
+		if stepsResult.Failure.Err == nil {
+			// Non-zero exit codes propagate like any other command exit code.
+			return Result{ExitCode: stepsResult.ExitCode()}, diags, nil
+		}
+		return Result{ExitCode: stepsResult.ExitCode()}, diags, stepsResult.Failure
+	}
+
+	if req.DryRun {
+		return Result{
+			DryRunData: &DryRunData{Plan: DryRunPlan{
+				CommandName:                 cmdInfo.Name,
+				SourceID:                    cmdInfo.SourceID,
+				Platform:                    requestPlatform(req),
+				WorkDir:                     req.Workdir,
+				Steps:                       stepPlans,
+				DependencyValidationSkipped: true,
+			}},
+		}, diags, nil
+	}
+	return Result{}, diags, nil
+}
+
+// executeStep runs (or plans, in dry-run mode) a single command step.
+func (s *Service) executeStep(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, step *invowkfile.CommandStep) (Result, []Diagnostic, error) {
+	if step.IsCommand() {
+		childReq, diags, err := s.stepCommandRequest(ctx, req, cmdInfo, cfg, step)
+		if err != nil {
+			return Result{}, diags, err
+		}
+		result, childDiags, err := s.Execute(ctx, childReq)
+		return result, append(diags, childDiags...), err
+	}
+	return s.executeScriptStep(ctx, req, cmdInfo, cfg, defs, step)
+}
+
+// stepCommandRequest resolves the command referenced by a cmd step and builds
+// the request that invokes it. Bare refs resolve in the declaring command's
+// source; "@source command" refs resolve in the named source. Flags and args
+// come from the step bindings; everything else is inherited from req.
+func (s *Service) stepCommandRequest(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, step *invowkfile.CommandStep) (Request, []Diagnostic, error) {
+	parts, err := step.Cmd.Parse()
+	if err != nil {
+		return Request{}, nil, err
+	}
+	source := cmdInfo.SourceID
+	if parts.Qualified {
+		source = discovery.SourceID(parts.SourceID)
+	}
+
+	_, target, _, diags, err := s.discoverCommandFromSource(ctx, cfg, Request{
+		Name:       string(parts.Command),
+		FromSource: source,
+	})
+	if err != nil {
+		return Request{}, diags, err
+	}
+
+	flagValues, err := stepFlagValues(target, step)
+	if err != nil {
+		return Request{}, diags, err
+	}
+
+	childReq := inheritedRequest(req, target)
+	childReq.caller = cmdInfo
+	childReq.Args = slices.Clone(step.Args)
+	childReq.Runtime = step.Runtime
+	childReq.FlagValues = flagValues
+	return childReq, diags, nil
+}
+
+// inheritedRequest builds the request that runs target on behalf of req.
+// Invocation-wide settings (env overrides, verbosity, dry-run, force, yes, platform,
+// output streams) carry over from req; flags start from target's defaults and no args are passed.
+func inheritedRequest(req Request, target *discovery.CommandInfo) Request {
+	return Request{
+		Name:            string(target.Name),
+		Platform:        req.Platform,
+		Interactive:     req.Interactive,
+		InteractiveSet:  true,
+		Verbose:         req.Verbose,
+		VerboseSet:      true,
+		ForceRebuild:    req.ForceRebuild,
+		Force:           req.Force,
+		EnvFiles:        req.EnvFiles,
+		EnvVars:         req.EnvVars,
+		ConfigPath:      req.ConfigPath,
+		FlagValues:      defaultFlagValues(target),
+		FlagDefs:        target.Command.Flags,
+		ArgDefs:         target.Command.Args,
+		EnvInheritMode:  req.EnvInheritMode,
+		EnvInheritAllow: req.EnvInheritAllow,
+		EnvInheritDeny:  req.EnvInheritDeny,
+		DryRun:          req.DryRun,
+		Yes:             req.Yes,
+		ResolvedCommand: target,
+		UserEnv:         req.UserEnv,
+		stdout:          req.stdout,
+		stderr:          req.stderr,
+		outputs:         req.outputs,
+	}
+}
+
+// defaultFlagValues returns the declared flag defaults of target.
+func defaultFlagValues(target *discovery.CommandInfo) map[invowkfile.FlagName]string {
+	values := make(map[invowkfile.FlagName]string, len(target.Command.Flags))
+	for _, flag := range target.Command.Flags {
+		if flag.DefaultValue != "" {
+			values[flag.Name] = flag.DefaultValue
+		}
+	}
+	return values
+}
+
+// stepFlagValues merges a cmd step's flag bindings over the invoked command's
+// flag defaults. Binding a flag the invoked command does not declare is an error.
+func stepFlagValues(target *discovery.CommandInfo, step *invowkfile.CommandStep) (map[invowkfile.FlagName]string, error) {
+	values := defaultFlagValues(target)
+	for _, name := range slices.Sorted(maps.Keys(step.Flags)) {
+		if !slices.ContainsFunc(target.Command.Flags, func(flag invowkfile.Flag) bool { return flag.Name == name }) {
+			return nil, fmt.Errorf("%w: command '%s' has no flag '--%s'", ErrUnknownStepFlag, target.Name, name)
+		}
+		values[name] = step.Flags[name]
+	}
+	return values, nil
+}
+
+// executeScriptStep runs an inline-script step in the context of the declaring
+// command: the script sees the declaring command's flags, args, and env.
+func (s *Service) executeScriptStep(ctx context.Context, req Request, cmdInfo *discovery.CommandInfo, cfg *config.Config, defs resolvedDefinitions, step *invowkfile.CommandStep) (Result, []Diagnostic, error) {
+	impl := step.ScriptImplementation()
+	selection, err := appexec.NewRuntimeSelection(step.EffectiveRuntime(), requestPlatform(req), impl)
+	if err != nil {
+		return Result{}, nil, err
+	}
+	execCtx, err := s.buildExecContext(ctx, req, cmdInfo, defs, selection)
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	scriptAnalysis, hasScriptAnalysis := analyzeSelectedImplementationScript(execCtx)
+	if req.DryRun {
+		plan, planErr := newDryRunPlan(req, cmdInfo, execCtx, impl, scriptAnalysis, hasScriptAnalysis)
+		if planErr != nil {
+			return Result{}, nil, planErr
+		}
+		return Result{DryRunData: &DryRunData{Plan: plan}}, nil, nil
+	}
+
+	var diags []Diagnostic
+	if hasScriptAnalysis {
+		diags = appendScriptInterpreterDiagnostics(diags, scriptAnalysis)
+	}
+	return s.dispatchExecution(req, execCtx, cmdInfo, cfg, diags) //nolint:contextcheck // execCtx carries ctx through the runtime/dependency pipeline.
+}
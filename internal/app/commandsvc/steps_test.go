@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	appexec "github.com/invowk/invowk/internal/app/execute"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	stepRecordingRuntime struct {
+		mu        sync.Mutex
+		exitCodes map[invowkfile.ScriptContent]types.ExitCode
+		ran       []stepRun
+	}
+
+	stepRun struct {
+		script invowkfile.ScriptContent
+		env    map[string]string
+	}
+
+	recordingStepObserver struct {
+		noopExecutionObserver
+		started []StepEvent
+		failed  []StepEvent
+	}
+)
+
+func (*stepRecordingRuntime) Name() string { return string(invowkfile.RuntimeVirtualSh) }
+
+func (r *stepRecordingRuntime) Execute(execCtx *runtimepkg.ExecutionContext) *runtimepkg.Result {
+	script := execCtx.SelectedImpl.Script.Content
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ran = append(r.ran, stepRun{script: script, env: execCtx.Env.ExtraEnv})
+	return &runtimepkg.Result{ExitCode: r.exitCodes[script]}
+}
+
+func (*stepRecordingRuntime) Available() bool { return true }
+
+func (*stepRecordingRuntime) Validate(*runtimepkg.ExecutionContext) error { return nil }
+
+func (o *recordingStepObserver) StepStarting(event StepEvent) { o.started = append(o.started, event) }
+
+func (o *recordingStepObserver) StepFailed(event StepEvent) { o.failed = append(o.failed, event) }
+
+func TestServiceExecuteSteps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs steps in order with bindings", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, observer := newStepTestService(t, []invowkfile.CommandStep{
+			{Cmd: "build", Flags: map[invowkfile.FlagName]string{"target": "prod"}, Args: []string{"v1"}},
+			{Name: "announce", Script: &invowkfile.ImplementationScript{Content: "echo announce"}, Runtime: invowkfile.RuntimeVirtualSh},
+		})
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "release", Verbose: true, VerboseSet: true})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 0 {
+			t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+		}
+		if len(rt.ran) != 2 || rt.ran[0].script != "echo build" || rt.ran[1].script != "echo announce" {
+			t.Fatalf("ran = %#v, want build then announce", rt.ran)
+		}
+		if got := rt.ran[0].env["INVOWK_FLAG_TARGET"]; got != "prod" {
+			t.Errorf("build INVOWK_FLAG_TARGET = %q, want prod", got)
+		}
+		if got := rt.ran[0].env["INVOWK_FLAG_MODE"]; got != "fast" {
+			t.Errorf("build INVOWK_FLAG_MODE = %q, want default fast", got)
+		}
+		if got := rt.ran[0].env["ARG1"]; got != "v1" {
+			t.Errorf("build ARG1 = %q, want v1", got)
+		}
+		if got := rt.ran[1].env["INVOWK_CMD_NAME"]; got != "release" {
+			t.Errorf("script step INVOWK_CMD_NAME = %q, want release", got)
+		}
+		if len(observer.started) != 2 || observer.started[1].Label != "announce" || observer.started[1].Total != 2 {
+			t.Errorf("started events = %#v", observer.started)
+		}
+	})
+
+	t.Run("fails fast on non-zero exit", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newStepTestService(t, []invowkfile.CommandStep{
+			{Script: &invowkfile.ImplementationScript{Content: "exit 3"}, Runtime: invowkfile.RuntimeVirtualSh},
+			{Cmd: "build"},
+		})
+		rt.exitCodes = map[invowkfile.ScriptContent]types.ExitCode{"exit 3": 3}
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "release"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 3 {
+			t.Fatalf("ExitCode = %d, want 3", result.ExitCode)
+		}
+		if len(rt.ran) != 1 {
+			t.Fatalf("ran %d steps, want 1", len(rt.ran))
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, observer := newStepTestService(t, []invowkfile.CommandStep{
+			{Script: &invowkfile.ImplementationScript{Content: "exit 3"}, Runtime: invowkfile.RuntimeVirtualSh, ContinueOnError: true},
+			{Cmd: "build"},
+		})
+		rt.exitCodes = map[invowkfile.ScriptContent]types.ExitCode{"exit 3": 3}
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "release"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.ExitCode != 0 || len(rt.ran) != 2 {
+			t.Fatalf("ExitCode = %d, ran = %d; want 0 and 2", result.ExitCode, len(rt.ran))
+		}
+		if len(observer.failed) != 1 || observer.failed[0].ExitCode != 3 {
+			t.Fatalf("failed events = %#v", observer.failed)
+		}
+	})
+
+	t.Run("unknown flag binding", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newStepTestService(t, []invowkfile.CommandStep{
+			{Cmd: "build", Flags: map[invowkfile.FlagName]string{"missing": "x"}},
+		})
+
+		_, _, err := service.Execute(t.Context(), Request{Name: "release"})
+		if !errors.Is(err, ErrUnknownStepFlag) || !errors.Is(err, appexec.ErrStepFailed) {
+			t.Fatalf("Execute() error = %v, want ErrUnknownStepFlag wrapped in ErrStepFailed", err)
+		}
+		if len(rt.ran) != 0 {
+			t.Fatalf("ran %d steps, want 0", len(rt.ran))
+		}
+	})
+
+	t.Run("dry run plans every step", func(t *testing.T) {
+		t.Parallel()
+
+		service, rt, _ := newStepTestService(t, []invowkfile.CommandStep{
+			{Cmd: "build", ContinueOnError: true},
+			{Name: "announce", Script: &invowkfile.ImplementationScript{Content: "echo announce"}},
+		})
+
+		result, _, err := service.Execute(t.Context(), Request{Name: "release", DryRun: true})
+		if err != nil {
+			t.Fatalf("Execute(dry-run) error = %v", err)
+		}
+		if len(rt.ran) != 0 {
+			t.Fatalf("dry-run executed %d steps", len(rt.ran))
+		}
+		if result.DryRunData == nil {
+			t.Fatal("Execute(dry-run) did not return DryRunData")
+		}
+		plan := result.DryRunData.Plan
+		if err := plan.Validate(); err != nil {
+			t.Fatalf("plan.Validate() = %v", err)
+		}
+		if len(plan.Steps) != 2 {
+			t.Fatalf("plan.Steps = %#v, want 2 steps", plan.Steps)
+		}
+		if step := plan.Steps[0]; step.Cmd != "build" || !step.ContinueOnError || step.Plan.Runtime != invowkfile.RuntimeVirtualSh {
+			t.Errorf("cmd step plan = %#v", step)
+		}
+		if step := plan.Steps[1]; step.Label != "announce" || step.Plan.Runtime != invowkfile.RuntimeNative || step.Plan.Script.Content != "echo announce" {
+			t.Errorf("script step plan = %#v", step)
+		}
+	})
+}
+
+func TestServiceExecuteStepsDetectsCycles(t *testing.T) {
+	t.Parallel()
+
+	service, rt, _ := newStepTestService(t, []invowkfile.CommandStep{{Cmd: "loop"}})
+	set := service.discovery.(*stubCommandDiscovery).commandSet.Set
+	loop := &discovery.CommandInfo{
+		Name:       "loop",
+		SimpleName: "loop",
+		SourceID:   discovery.SourceIDInvowkfile,
+		Command:    &invowkfile.Command{Name: "loop", Steps: []invowkfile.CommandStep{{Cmd: "release"}}},
+		Invowkfile: set.Commands[0].Invowkfile,
+	}
+	set.Add(loop)
+	set.Analyze()
+
+	_, _, err := service.Execute(t.Context(), Request{Name: "release"})
+	if !errors.Is(err, appexec.ErrStepCycle) {
+		t.Fatalf("Execute() error = %v, want ErrStepCycle", err)
+	}
+	if len(rt.ran) != 0 {
+		t.Fatalf("ran %d steps, want 0", len(rt.ran))
+	}
+}
+
+func newStepTestService(t *testing.T, steps []invowkfile.CommandStep) (*Service, *stepRecordingRuntime, *recordingStepObserver) {
+	t.Helper()
+
+	inv := &invowkfile.Invowkfile{FilePath: types.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue"))}
+	build := invowkfiletest.NewTestCommand("build",
+		invowkfiletest.WithScript("echo build"),
+		invowkfiletest.WithRuntime(invowkfile.RuntimeVirtualSh),
+		invowkfiletest.WithAllPlatforms(),
+	)
+	build.Flags = []invowkfile.Flag{{Name: "target"}, {Name: "mode", DefaultValue: "fast"}}
+	release := &invowkfile.Command{Name: "release", Steps: steps}
+
+	set := discovery.NewDiscoveredCommandSet()
+	for _, cmd := range []*invowkfile.Command{build, release} {
+		set.Add(&discovery.CommandInfo{
+			Name:       cmd.Name,
+			SimpleName: cmd.Name,
+			SourceID:   discovery.SourceIDInvowkfile,
+			Command:    cmd,
+			Invowkfile: inv,
+		})
+	}
+	set.Analyze()
+
+	rt := &stepRecordingRuntime{}
+	registry := runtimepkg.NewRegistry()
+	registry.Register(runtimepkg.RuntimeTypeVirtualSh, rt)
+	registry.Register(runtimepkg.RuntimeTypeNative, rt)
+	observer := &recordingStepObserver{}
+	cfg := config.DefaultConfig()
+	return &Service{
+		config:          &staticCommandsvcConfigProvider{cfg: cfg},
+		discovery:       &stubCommandDiscovery{commandSet: discovery.CommandSetResult{Set: set}},
+		hostAccess:      noopHostAccess{},
+		registryFactory: staticRuntimeRegistryFactory{registry: registry},
+		interactive:     defaultInteractiveExecutor{},
+		observer:        observer,
+		userEnvFunc:     func() map[string]string { return map[string]string{} },
+		configFallback: func(context.Context, config.Loader, string) (*config.Config, []Diagnostic) {
+			return cfg, nil
+		},
+	}, rt, observer
+}
@@ -6,8 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	appexec "github.com/invowk/invowk/internal/app/execute"
 	"github.com/invowk/invowk/internal/config"
 	"github.com/invowk/invowk/internal/discovery"
 	"github.com/invowk/invowk/pkg/invowkfile"
@@ -60,6 +63,9 @@ type (
 		FromSource discovery.SourceID
 		// ForceRebuild forces container image rebuilds, bypassing cache.
 		ForceRebuild bool
+		// Force runs commands that declare sources even when their fingerprint
+		// shows they are up to date.
+		Force bool
 		// ContainerName overrides the persistent container target name for
 		// container runtime execution. Zero value means no CLI override.
 		ContainerName invowkfile.ContainerName
@@ -86,6 +92,9 @@ type (
 		EnvInheritDeny []invowkfile.EnvVarName
 		// DryRun enables dry-run mode: returns execution plan without executing.
 		DryRun bool
+		// Yes answers the confirmation question of commands that declare a
+		// confirm block without asking (--ivk-yes).
+		Yes bool
 		// ResolvedCommand carries a pre-resolved command when the caller already
 		// performed discovery (for example, dynamic Cobra leaf execution). When set,
 		// Execute() can skip GetCommand discovery.
@@ -94,6 +103,36 @@ type (
 		// injects command-level env vars. When nil, Execute() populates it eagerly
 		// via the UserEnvProvider callback. Tests can set this to inject a controlled env.
 		UserEnv map[string]string
+
+		// prerequisitesScheduled is set on requests that execute a node of an
+		// already scheduled prerequisite graph, so the node's own prerequisites
+		// are not run a second time.
+		prerequisitesScheduled bool
+
+		// deprecationForwarded is set on requests forwarded from a deprecated
+		// command to its replacement, so forwarding never chains or loops.
+		deprecationForwarded bool
+
+		// matrix is the combination run by a request for one combination of a
+		// matrix command; nil otherwise.
+		matrix invowkfile.MatrixCombination //goplint:ignore -- expanded from the command's validated matrix.
+
+		// stdout and stderr replace the standard output streams of the run when
+		// set, e.g. with the prefixing writers of a parallel matrix combination.
+		// Commands run by cmd steps inherit them.
+		stdout io.Writer
+		stderr io.Writer
+
+		// caller is the command on whose behalf the request runs (the command
+		// declaring a prerequisite or cmd step, or a forwarding deprecated
+		// command); nil for direct invocations. Private commands check it.
+		caller *discovery.CommandInfo
+
+		// outputs collects the outputs of the commands run by one invocation.
+		// Execute creates it for direct invocations; the requests it derives
+		// (prerequisites, cmd steps, matrix combinations, and forwarding)
+		// share it.
+		outputs *invocationOutputs
 	}
 
 	//goplint:validate-all
@@ -138,6 +177,15 @@ type (
 		WorkDir invowkfile.WorkDir
 		// Timeout is the selected implementation timeout, if any.
 		Timeout invowkfile.DurationString
+		// Retry is the selected implementation retry policy, if any.
+		Retry *invowkfile.RetryPolicy
+		// SuccessExitCodes and ExitCodeMap are the selected implementation's
+		// exit code policy, if any.
+		SuccessExitCodes []types.ExitCode
+		ExitCodeMap      invowkfile.ExitCodeMap
+		// Selection explains which when conditions skipped or selected
+		// implementations. Empty when no candidate declares when conditions.
+		Selection string //goplint:ignore -- dry-run render DTO, not a domain value
 		// PersistentContainerMode reports whether the container runtime would
 		// use an ephemeral or persistent target.
 		PersistentContainerMode string //goplint:ignore -- dry-run render DTO, not a domain value
@@ -164,11 +212,178 @@ type (
 		LuaCPULimit invowkfile.LuaCPULimit
 		// LuaMemoryLimit is the selected virtual-lua memory quota, if any.
 		LuaMemoryLimit invowkfile.MemoryLimit
+		// StarlarkMaxSteps is the selected virtual-starlark step limit, if any.
+		StarlarkMaxSteps invowkfile.StarlarkMaxSteps
+		// RemoteHost, RemoteUser, and RemotePort select the remote-ssh target.
+		// RemoteHost is empty for other runtimes.
+		RemoteHost invowkfile.RemoteHost
+		RemoteUser invowkfile.RemoteUser
+		RemotePort invowkfile.RemotePort
+		// Sandbox is the selected native runtime's sandbox config, if any.
+		Sandbox *invowkfile.RuntimeSandboxConfig
 		// Env contains projected execution environment variables.
 		Env map[string]string //goplint:ignore -- environment maps are stringly typed by os/exec and container APIs.
+		// Secrets maps the env secrets declared at the root, command, and
+		// implementation levels to their providers. Dry-run never resolves them.
+		Secrets map[invowkfile.EnvVarName]invowkfile.SecretProviderType
 		// DependencyValidationSkipped is true because dry-run mode does not
 		// execute dependency checks.
 		DependencyValidationSkipped bool
+		// Steps holds the per-step plans of a multi-step command, in execution
+		// order. When set, the implementation-specific fields above are unused.
+		Steps []DryRunStepPlan
+		// Prerequisites lists the commands that depends_on.cmds entries with
+		// run: true would execute before this command, in a valid execution order.
+		Prerequisites []invowkfile.CommandName
+		// Freshness reports, for commands that declare sources, whether the
+		// command is "up to date" or "stale because <reason>". Empty for
+		// commands that always run.
+		Freshness string //goplint:ignore -- dry-run render DTO, not a domain value
+		// Hooks lists the merged root and command lifecycle hooks that would
+		// run around the command, grouped by phase in execution order.
+		Hooks []DryRunHookPlan
+		// Matrix lists the combinations of a matrix command, in run order. The
+		// rest of the plan describes the first combination.
+		Matrix []invowkfile.MatrixCombination
+		// MatrixParallel reports whether the combinations would run in parallel.
+		MatrixParallel bool
+	}
+
+	//goplint:validate-all
+	//
+	// DryRunHookPlan describes one lifecycle hook of a command plan.
+	DryRunHookPlan struct {
+		// Phase is when the hook runs: before, after, or finally.
+		Phase invowkfile.HookPhase
+		// Runtime is the runtime that would execute the hook.
+		Runtime invowkfile.RuntimeMode
+		// Script is the hook script.
+		Script invowkfile.ImplementationScript
+	}
+
+	//goplint:validate-all
+	//
+	// DryRunStepPlan describes one step of a multi-step command plan.
+	DryRunStepPlan struct {
+		// Label is the step's display label (its name or target).
+		Label string //goplint:ignore -- dry-run render DTO, not a domain value
+		// Cmd is the referenced command for cmd steps; empty for script steps.
+		Cmd invowkfile.CommandDependencyRef
+		// ContinueOnError reports whether a failure of this step is tolerated.
+		ContinueOnError bool
+		// Plan is the resolved execution plan of the step.
+		Plan DryRunPlan
+	}
+
+	// StepEvent describes a step of a multi-step command for execution observers.
+	StepEvent struct {
+		// CommandName is the multi-step command that owns the step.
+		CommandName invowkfile.CommandName
+		// Index is the zero-based position of the step.
+		Index int
+		// Total is the number of steps declared by the command.
+		Total int
+		// Label is the step's display label (its name or target).
+		Label string //goplint:ignore -- observer render DTO, not a domain value
+		// ExitCode is the step exit code (failure events only).
+		ExitCode types.ExitCode
+		// Err is the step execution error (failure events only).
+		Err error
+	}
+
+	// AttemptEvent describes one run of an implementation that declares a
+	// retry policy, for execution observers.
+	AttemptEvent struct {
+		// CommandName is the command being run.
+		CommandName invowkfile.CommandName
+		// Attempt is the 1-based number of the run about to start.
+		Attempt int
+		// Attempts is the total number of runs allowed by the retry policy.
+		Attempts int
+		// ExitCode is the exit code of the previous run (retries only).
+		ExitCode types.ExitCode
+		// Delay is the backoff waited before this run (retries only).
+		Delay time.Duration
+	}
+
+	// HookEvent describes a lifecycle hook for execution observers.
+	HookEvent struct {
+		// CommandName is the command the hook runs around.
+		CommandName invowkfile.CommandName
+		// Phase is the hook's lifecycle phase.
+		Phase invowkfile.HookPhase
+		// Index is the zero-based position of the hook within its phase.
+		Index int
+		// Total is the number of hooks in the phase.
+		Total int
+		// ExitCode is the hook exit code (failure events only).
+		ExitCode types.ExitCode
+		// Err is the hook execution error (failure events only).
+		Err error
+	}
+
+	// ImplementationEvent describes an implementation chosen through when
+	// conditions for execution observers.
+	ImplementationEvent struct {
+		// CommandName is the command being run.
+		CommandName invowkfile.CommandName
+		// Runtime is the selected runtime mode.
+		Runtime invowkfile.RuntimeMode
+		// Reason explains which when conditions skipped or selected implementations.
+		Reason string //goplint:ignore -- rendered selection explanation for verbose output.
+	}
+
+	// DeprecationEvent describes an invoked deprecated command for execution observers.
+	DeprecationEvent struct {
+		// CommandName is the deprecated command.
+		CommandName invowkfile.CommandName
+		// Message explains the deprecation.
+		Message invowkfile.DescriptionText
+		// Replacement is the command that supersedes it (may be empty).
+		Replacement invowkfile.CommandName
+		// Forwarded is true when the replacement runs instead of the command.
+		Forwarded bool
+		// Verbose is true for verbose runs, which explain deprecations in detail.
+		Verbose bool
+	}
+
+	// RunLockEvent describes a command waiting for its lock for execution observers.
+	RunLockEvent struct {
+		// CommandName is the command that waits.
+		CommandName invowkfile.CommandName
+		// Lock is the name of the lock.
+		Lock invowkfile.LockName
+		// Holder is the process holding the lock when the wait started.
+		Holder appexec.RunLockHolder
+	}
+
+	// MatrixEvent describes a combination of a matrix command for execution observers.
+	MatrixEvent struct {
+		// CommandName is the matrix command.
+		CommandName invowkfile.CommandName
+		// Index is the zero-based position of the combination.
+		Index int
+		// Total is the number of combinations of the matrix.
+		Total int
+		// Combination is the variable values of the run.
+		Combination invowkfile.MatrixCombination
+	}
+
+	// MatrixSummary reports the outcome of every combination of a matrix
+	// command for execution observers.
+	MatrixSummary struct {
+		// CommandName is the matrix command.
+		CommandName invowkfile.CommandName
+		// Outcomes lists the outcome of every combination, in run order.
+		Outcomes []appexec.MatrixOutcome
+	}
+
+	// PrerequisiteEvent describes a prerequisite command for execution observers.
+	PrerequisiteEvent struct {
+		// CommandName is the command whose prerequisites are running.
+		CommandName invowkfile.CommandName
+		// Prerequisite is the prerequisite command about to run.
+		Prerequisite invowkfile.CommandName
 	}
 
 	// ClassifiedError is a typed error that carries a service-owned error kind
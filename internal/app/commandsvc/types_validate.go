@@ -178,7 +178,12 @@ func (d DryRunData) Validate() error {
 }
 
 // Validate returns nil if the DryRunPlan has valid fields, or a validation error if not.
+// Multi-step plans carry no runtime or script of their own; each step plan is
+// validated instead.
 func (p DryRunPlan) Validate() error {
+	if len(p.Steps) > 0 {
+		return p.validateStepPlan()
+	}
 	var errs []error
 	if p.Runtime == "" {
 		errs = append(errs, errors.New("runtime is required"))
@@ -211,6 +216,19 @@ func (p DryRunPlan) Validate() error {
 	if err := p.Timeout.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if p.Retry != nil {
+		if err := p.Retry.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, code := range p.SuccessExitCodes {
+		if err := code.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := p.ExitCodeMap.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if p.PersistentContainerName != "" {
 		if err := p.PersistentContainerName.Validate(); err != nil {
 			errs = append(errs, err)
@@ -242,6 +260,136 @@ func (p DryRunPlan) Validate() error {
 	if err := p.LuaMemoryLimit.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := p.StarlarkMaxSteps.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if p.RemoteHost != "" {
+		if err := p.RemoteHost.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := p.RemoteUser.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.RemotePort.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if p.Sandbox != nil {
+		if err := p.Sandbox.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for name, provider := range p.Secrets {
+		if err := name.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := provider.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = p.appendPrerequisiteErrors(errs)
+	errs = p.appendHookErrors(errs)
+	errs = p.appendMatrixErrors(errs)
+	if len(errs) > 0 {
+		return &InvalidDryRunDataError{FieldErrors: errs}
+	}
+	return nil
+}
+
+func (p DryRunPlan) validateStepPlan() error {
+	var errs []error
+	if p.CommandName != "" {
+		if err := p.CommandName.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.SourceID != "" {
+		if err := p.SourceID.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.Platform != "" {
+		if err := p.Platform.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.WorkDir != "" {
+		if err := p.WorkDir.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, step := range p.Steps {
+		if err := step.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = p.appendPrerequisiteErrors(errs)
+	errs = p.appendHookErrors(errs)
+	errs = p.appendMatrixErrors(errs)
+	if len(errs) > 0 {
+		return &InvalidDryRunDataError{FieldErrors: errs}
+	}
+	return nil
+}
+
+func (p DryRunPlan) appendPrerequisiteErrors(errs []error) []error {
+	for _, name := range p.Prerequisites {
+		if err := name.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (p DryRunPlan) appendHookErrors(errs []error) []error {
+	for _, hook := range p.Hooks {
+		if err := hook.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (p DryRunPlan) appendMatrixErrors(errs []error) []error {
+	for _, combination := range p.Matrix {
+		if err := combination.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Validate returns nil if the DryRunStepPlan has valid fields, or a validation error if not.
+// It validates Cmd (when non-empty) and the nested Plan.
+func (p DryRunStepPlan) Validate() error {
+	var errs []error
+	if p.Cmd != "" {
+		if err := p.Cmd.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := p.Plan.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return &InvalidDryRunDataError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Validate returns nil if the DryRunHookPlan has valid fields, or a validation error if not.
+// It validates Phase, Runtime, and Script.
+func (p DryRunHookPlan) Validate() error {
+	var errs []error
+	if err := p.Phase.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.Runtime.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.Script.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if len(errs) > 0 {
 		return &InvalidDryRunDataError{FieldErrors: errs}
 	}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/invowk/invowk/internal/app/deps"
+	"github.com/invowk/invowk/internal/config"
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// ErrPrivateCommand is returned when a private command is run from outside
+// its module.
+var ErrPrivateCommand = errors.New("private command")
+
+// PrivateCommandError is returned when a command with visibility "private" is
+// run directly, or by a command of another module. It wraps ErrPrivateCommand
+// for errors.Is() compatibility.
+type PrivateCommandError struct {
+	CommandName invowkfile.CommandName
+	SourceID    discovery.SourceID
+}
+
+// Error implements the error interface.
+func (e *PrivateCommandError) Error() string {
+	return fmt.Sprintf("command '%s' is private to '%s' and can only be run by commands of the same module", e.CommandName, e.SourceID)
+}
+
+// Unwrap returns ErrPrivateCommand for errors.Is() compatibility.
+func (e *PrivateCommandError) Unwrap() error { return ErrPrivateCommand }
+
+// checkVisibility rejects runs of a private command that do not come from a
+// command of its own module. The calling command is req.caller for
+// prerequisites, cmd steps, and deprecation forwards; for a nested invowk
+// invocation it is the command whose script runs invowk, identified by the
+// INVOWK_SOURCE and INVOWK_CMD_NAME variables injected into every script.
+// Like depends_on.cmds scope, this organizes commands rather than sandboxing
+// them: a script can set those variables itself.
+func (s *Service) checkVisibility(ctx context.Context, cfg *config.Config, req Request, cmdInfo *discovery.CommandInfo) error {
+	if !cmdInfo.Command.Visibility.IsPrivate() {
+		return nil
+	}
+	caller := req.caller
+	if caller == nil {
+		caller = s.nestedInvocationCaller(ctx, cfg, req)
+	}
+	if caller != nil && deps.PrivateCommandDecision(caller, cmdInfo).Allowed {
+		return nil
+	}
+	return &PrivateCommandError{CommandName: cmdInfo.Name, SourceID: cmdInfo.SourceID}
+}
+
+// nestedInvocationCaller returns the command whose script started this invowk
+// process, or nil when invowk was not started by a command script.
+func (s *Service) nestedInvocationCaller(ctx context.Context, cfg *config.Config, req Request) *discovery.CommandInfo {
+	source, name := req.UserEnv[runtime.EnvVarSource], req.UserEnv[runtime.EnvVarCmdName]
+	if source == "" || name == "" {
+		return nil
+	}
+	_, caller, _, _, err := s.discoverCommandFromSource(ctx, cfg, Request{
+		Name:       name,
+		FromSource: discovery.SourceID(source), //goplint:ignore -- injected by invowk, only compared against discovered sources.
+	})
+	if err != nil {
+		return nil
+	}
+	return caller
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package commandsvc
+
+import (
+	"errors"
+	"testing"
+
+	runtimepkg "github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestServiceExecuteVisibility(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		visibility invowkfile.CommandVisibility
+		req        Request
+		wantErr    error
+		wantRan    int
+	}{
+		{
+			name:       "private command run directly",
+			visibility: invowkfile.VisibilityPrivate,
+			req:        Request{Name: "build"},
+			wantErr:    ErrPrivateCommand,
+		},
+		{
+			name:       "private command run by a step of the same invowkfile",
+			visibility: invowkfile.VisibilityPrivate,
+			req:        Request{Name: "release"},
+			wantRan:    1,
+		},
+		{
+			name:       "private command run by a nested invocation from the same invowkfile",
+			visibility: invowkfile.VisibilityPrivate,
+			req: Request{Name: "build", UserEnv: map[string]string{
+				runtimepkg.EnvVarSource:  "invowkfile",
+				runtimepkg.EnvVarCmdName: "release",
+			}},
+			wantRan: 1,
+		},
+		{
+			name:       "private command run by a nested invocation from an unknown source",
+			visibility: invowkfile.VisibilityPrivate,
+			req: Request{Name: "build", UserEnv: map[string]string{
+				runtimepkg.EnvVarSource:  "tools",
+				runtimepkg.EnvVarCmdName: "release",
+			}},
+			wantErr: ErrPrivateCommand,
+		},
+		{
+			name:       "hidden command run directly",
+			visibility: invowkfile.VisibilityHidden,
+			req:        Request{Name: "build"},
+			wantRan:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, rt, _ := newStepTestService(t, []invowkfile.CommandStep{{Cmd: "build"}})
+			stub, ok := service.discovery.(*stubCommandDiscovery)
+			if !ok {
+				t.Fatalf("discovery = %T, want *stubCommandDiscovery", service.discovery)
+			}
+			for _, cmdInfo := range stub.commandSet.Set.Commands {
+				if cmdInfo.Name == "build" {
+					cmdInfo.Command.Visibility = tt.visibility
+				}
+			}
+
+			_, _, err := service.Execute(t.Context(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(rt.ran) != tt.wantRan {
+				t.Errorf("ran %d scripts, want %d", len(rt.ran), tt.wantRan)
+			}
+		})
+	}
+}
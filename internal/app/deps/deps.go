@@ -23,10 +23,11 @@ const missingCommandDependencyDetailFormat = "%s - %s"
 // config's depends_on (if any) is validated inside the container environment.
 // Runtime-level depends_on is only supported for container runtime.
 //
-// Note: depends_on.cmds is a discoverability check only. For host-level deps, Invowk validates
+// Note: depends_on.cmds is a discoverability check here. For host-level deps, Invowk validates
 // that commands are discoverable via the standard discovery pipeline. For container runtime deps,
 // it runs 'invowk internal check-cmd' inside the container. Neither phase executes the
-// referenced commands.
+// referenced commands; entries with run: true are executed earlier by the command service
+// (see ResolvePrerequisites).
 func ValidateDependencies(disc CommandSetProvider, cmdInfo *discovery.CommandInfo, parentCtx ExecutionContext, userEnv map[string]string) error {
 	return ValidateDependenciesWithCapabilityChecker(disc, cmdInfo, nil, parentCtx, userEnv, nil)
 }
@@ -219,7 +220,7 @@ func resolveCommandDependenciesWithLockProvider(disc CommandSetProvider, deps *i
 			continue
 		}
 
-		matchedCmd, forbidden, found := findAccessibleCommand(available, currentSource, alternatives, scope)
+		matchedCmd, forbidden, found := findAccessibleCommand(available, currentSource, alternatives, cmdInfo, scope)
 		if matchedCmd != nil {
 			matchedName := matchedCmd.Name
 			resolved = append(resolved, resolvedCommandDependency{
@@ -252,6 +253,21 @@ func resolveCommandDependenciesWithLockProvider(disc CommandSetProvider, deps *i
 	return resolved, nil
 }
 
+// PrivateCommandDecision reports whether caller may run target with respect to
+// target's visibility: a private command is only visible from commands of its
+// own module, or of the root invowkfile for root commands. Commands that are
+// not private are always allowed here; module scope rules for depends_on.cmds
+// are enforced separately.
+func PrivateCommandDecision(caller, target *discovery.CommandInfo) invowkmod.CommandScopeDecision {
+	scopeTarget := commandScopeTarget(target)
+	if !scopeTarget.Private {
+		return invowkmod.CommandScopeDecision{Allowed: true, TargetCommand: scopeTarget.Reference}
+	}
+	scope := invowkmod.NewCommandScope(commandScopeModuleID(caller))
+	scope.ModuleSourceID = invowkmod.ModuleSourceID(caller.SourceID) //goplint:ignore -- SourceID validated by discovery
+	return scope.CanCallTarget(scopeTarget)
+}
+
 // buildCommandScope constructs a CommandScope for scope enforcement.
 // Returns nil for root invowkfile commands (no scope restrictions).
 func buildCommandScope(cmdInfo *discovery.CommandInfo, available map[invowkfile.CommandName]*discovery.CommandInfo, lock *invowkmod.LockFile) *invowkmod.CommandScope {
@@ -259,11 +275,7 @@ func buildCommandScope(cmdInfo *discovery.CommandInfo, available map[invowkfile.
 		return nil // Root invowkfile — no scope restrictions.
 	}
 
-	moduleID := cmdInfo.Invowkfile.Metadata.Module()
-	if cmdInfo.ModuleID != nil {
-		moduleID = *cmdInfo.ModuleID
-	}
-
+	moduleID := commandScopeModuleID(cmdInfo)
 	requirements := cmdInfo.Invowkfile.Metadata.Requires()
 
 	// Wire direct dependencies from declarations resolved through lock-file
@@ -292,6 +304,18 @@ func buildCommandScope(cmdInfo *discovery.CommandInfo, available map[invowkfile.
 	return scope
 }
 
+// commandScopeModuleID returns the module that owns cmdInfo's scope (empty for
+// root invowkfile commands).
+func commandScopeModuleID(cmdInfo *discovery.CommandInfo) invowkmod.ModuleID {
+	if cmdInfo.ModuleID != nil {
+		return *cmdInfo.ModuleID
+	}
+	if cmdInfo.Invowkfile.Metadata != nil {
+		return cmdInfo.Invowkfile.Metadata.Module()
+	}
+	return ""
+}
+
 func commandScopeLock(provider CommandScopeLockProvider, inv *invowkfile.Invowkfile) (*invowkmod.LockFile, error) {
 	if provider == nil || inv == nil || inv.ModulePath == "" {
 		return &invowkmod.LockFile{}, nil
@@ -300,6 +324,11 @@ func commandScopeLock(provider CommandScopeLockProvider, inv *invowkfile.Invowkf
 }
 
 func commandScopeDenialDetail(scope *invowkmod.CommandScope, decision invowkmod.CommandScopeDecision) DependencyMessage {
+	if decision.Reason == invowkmod.CommandScopeDenyPrivate {
+		return dependencyMessageFromDetail(fmt.Sprintf(
+			"%s - command '%s' is private to '%s' and can only be called from commands of the same module",
+			decision.TargetCommand, decision.TargetCommand, decision.TargetSource))
+	}
 	return dependencyMessageFromDetail(fmt.Sprintf(
 		"%s - command from module '%s' cannot call '%s': module '%s' is not accessible\n"+
 			"Commands can only call commands from the same module (%s), commands from globally installed user command modules (~/.invowk/cmds/), or commands from direct dependencies declared in invowkmod.cue:requires and resolved in invowkmod.lock.cue. "+
@@ -307,11 +336,11 @@ func commandScopeDenialDetail(scope *invowkmod.CommandScope, decision invowkmod.
 		decision.TargetCommand, scope.ModuleID, decision.TargetCommand, decision.TargetSource, scope.ModuleID))
 }
 
-func findAccessibleCommand(available map[invowkfile.CommandName]*discovery.CommandInfo, currentSource invowkmod.ModuleSourceID, alternatives []commandDependencyAlternative, scope *invowkmod.CommandScope) (*discovery.CommandInfo, []DependencyMessage, bool) {
+func findAccessibleCommand(available map[invowkfile.CommandName]*discovery.CommandInfo, currentSource invowkmod.ModuleSourceID, alternatives []commandDependencyAlternative, caller *discovery.CommandInfo, scope *invowkmod.CommandScope) (*discovery.CommandInfo, []DependencyMessage, bool) {
 	var forbidden []DependencyMessage
 	for _, alt := range alternatives {
 		for _, candidate := range matchingCommandCandidates(available, currentSource, alt) {
-			decision := commandScopeDecision(scope, candidate)
+			decision := commandScopeDecision(scope, caller, candidate)
 			if decision.Allowed {
 				return candidate, nil, true
 			}
@@ -321,19 +350,26 @@ func findAccessibleCommand(available map[invowkfile.CommandName]*discovery.Comma
 	return nil, forbidden, len(forbidden) > 0
 }
 
-func commandScopeDecision(scope *invowkmod.CommandScope, cmd *discovery.CommandInfo) invowkmod.CommandScopeDecision {
+func commandScopeDecision(scope *invowkmod.CommandScope, caller, cmd *discovery.CommandInfo) invowkmod.CommandScopeDecision {
 	if scope == nil {
-		return invowkmod.CommandScopeDecision{Allowed: true, TargetCommand: invowkmod.CommandReference(cmd.Name)}
+		// Root invowkfile commands have no module scope, but private module
+		// commands stay out of their reach.
+		return PrivateCommandDecision(caller, cmd)
 	}
+	return scope.CanCallTarget(commandScopeTarget(cmd))
+}
+
+func commandScopeTarget(cmd *discovery.CommandInfo) invowkmod.CommandTarget {
 	moduleID := invowkmod.ModuleID("")
 	if cmd.ModuleID != nil {
 		moduleID = *cmd.ModuleID
 	}
-	return scope.CanCallTarget(invowkmod.CommandTarget{
+	return invowkmod.CommandTarget{
 		Reference: invowkmod.CommandReference(cmd.Name),
 		SourceID:  invowkmod.ModuleSourceID(cmd.SourceID), //goplint:ignore -- SourceID validated by discovery
 		ModuleID:  moduleID,
-	})
+		Private:   cmd.Command != nil && cmd.Command.Visibility.IsPrivate(),
+	}
 }
 
 func discoverAvailableCommands(disc CommandSetProvider, ctx ExecutionContext) (map[invowkfile.CommandName]*discovery.CommandInfo, error) {
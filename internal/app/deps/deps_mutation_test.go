@@ -416,7 +416,7 @@ func testCommandScopeAccessibleCommandDecisions(t *testing.T) {
 	scope.AddDirectDependency(depsMutationModuleID, invowkmod.ModuleSourceID(depsMutationSource))
 
 	requireAccessibleCommandDecision(t, available, scope, "@tools lint", allowed, 0)
-	matched, forbidden, found := findAccessibleCommand(available, "", commandDependencyAlternativesForTest(t, "@blocked lint"), scope)
+	matched, forbidden, found := findAccessibleCommand(available, "", commandDependencyAlternativesForTest(t, "@blocked lint"), nil, scope)
 	if !found || matched != nil || len(forbidden) != 1 {
 		t.Fatalf("blocked lookup matched=%v forbidden=%v found=%v, want one forbidden", matched, forbidden, found)
 	}
@@ -424,7 +424,7 @@ func testCommandScopeAccessibleCommandDecisions(t *testing.T) {
 		t.Fatalf("forbidden detail = %q, want inaccessible blocked module", forbidden[0])
 	}
 
-	decision := commandScopeDecision(nil, &discovery.CommandInfo{Name: depsMutationCommand})
+	decision := commandScopeDecision(nil, nil, &discovery.CommandInfo{Name: depsMutationCommand})
 	if !decision.Allowed || decision.TargetCommand != invowkmod.CommandReference(depsMutationCommand) {
 		t.Fatalf("root decision = %+v, want allowed target command", decision)
 	}
@@ -541,7 +541,7 @@ func requireAccessibleCommandDecision(
 ) {
 	t.Helper()
 
-	matched, forbidden, found := findAccessibleCommand(available, "", commandDependencyAlternativesForTest(t, ref), scope)
+	matched, forbidden, found := findAccessibleCommand(available, "", commandDependencyAlternativesForTest(t, ref), nil, scope)
 	if !found || matched != want || len(forbidden) != wantForbidden {
 		t.Fatalf("lookup %q matched=%v forbidden=%v found=%v, want matched=%v forbidden=%d", ref, matched, forbidden, found, want, wantForbidden)
 	}
@@ -137,3 +137,55 @@ func TestCheckCommandDependenciesExistRejectsSameModuleIDWithDifferentSource(t *
 		t.Fatalf("ForbiddenCommands[0] = %q", depErr.ForbiddenCommands[0])
 	}
 }
+
+func TestCheckCommandDependenciesExistRejectsPrivateCommandOfOtherModule(t *testing.T) {
+	t.Parallel()
+
+	toolsID := invowkmod.ModuleID("io.example.tools")
+	private := &invowkfile.Command{Name: "helper", Visibility: invowkfile.VisibilityPrivate}
+	callerInfo := &discovery.CommandInfo{
+		Name:       invowkfile.CommandName("build"),
+		SourceID:   discovery.SourceIDInvowkfile,
+		Command:    &invowkfile.Command{Name: "build"},
+		Invowkfile: &invowkfile.Invowkfile{},
+	}
+	disc := &stubCommandSetProvider{
+		result: discovery.CommandSetResult{Set: &discovery.DiscoveredCommandSet{
+			Commands: []*discovery.CommandInfo{
+				{
+					Name:       invowkfile.CommandName("tools helper"),
+					SimpleName: "helper",
+					SourceID:   discovery.SourceID("tools"),
+					ModuleID:   &toolsID,
+					Command:    private,
+				},
+				{
+					Name:       invowkfile.CommandName("setup"),
+					SimpleName: "setup",
+					SourceID:   discovery.SourceIDInvowkfile,
+					Command:    &invowkfile.Command{Name: "setup", Visibility: invowkfile.VisibilityPrivate},
+				},
+			},
+		}},
+	}
+	ctx := testDependencyExecutionContext(t, callerInfo.Command, "")
+
+	sameFile := &invowkfile.DependsOn{Commands: []invowkfile.CommandDependency{
+		{Alternatives: []invowkfile.CommandDependencyRef{"setup"}},
+	}}
+	if err := CheckCommandDependenciesExist(disc, sameFile, callerInfo, ctx); err != nil {
+		t.Fatalf("CheckCommandDependenciesExist(private root command) error = %v", err)
+	}
+
+	otherModule := &invowkfile.DependsOn{Commands: []invowkfile.CommandDependency{
+		{Alternatives: []invowkfile.CommandDependencyRef{"@tools helper"}},
+	}}
+	err := CheckCommandDependenciesExist(disc, otherModule, callerInfo, ctx)
+	var depErr *DependencyError
+	if !errors.As(err, &depErr) {
+		t.Fatalf("CheckCommandDependenciesExist(private module command) error = %v, want *DependencyError", err)
+	}
+	if len(depErr.ForbiddenCommands) != 1 || !strings.Contains(depErr.ForbiddenCommands[0].String(), "is private to 'tools'") {
+		t.Fatalf("ForbiddenCommands = %v, want private command denial", depErr.ForbiddenCommands)
+	}
+}
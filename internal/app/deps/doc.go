@@ -18,9 +18,11 @@
 //   - types.go: Exported types, sentinels, constants, CommandSetProvider interface
 //   - deps.go: Top-level ValidateDependencies, ValidateHostDependencies,
 //     ValidateRuntimeDependencies, CheckCommandDependenciesExist
+//   - prerequisites.go: ResolvePrerequisites for depends_on.cmds entries with run: true
 //   - tools.go: Tool validation (host PATH and container)
 //   - filepaths.go: Filepath validation (host filesystem and container)
 //   - checks.go: Custom check scripts, env vars, capabilities
 //   - helpers.go: Shared helpers (EvaluateAlternatives, NewContainerValidationContext, etc.)
-//   - input.go: Flag and argument validation (ValidateFlagValues, ValidateArguments)
+//   - input.go: Flag and argument validation (ValidateFlagValues, ValidateArguments,
+//     ValidateFlagPaths, ValidateArgumentPaths)
 package deps
@@ -44,6 +44,48 @@ func ValidateFlagValues(cmdName string, flagValues map[invowkfile.FlagName]strin
 	return nil
 }
 
+// ValidateFlagPaths checks "path" flag values against their must_exist and kind
+// options. Relative values are resolved against baseDir (the invowkfile directory).
+// It runs after ValidateFlagValues, so values are already type-checked.
+func ValidateFlagPaths(cmdName string, baseDir invowkfile.FilesystemPath, flagValues map[invowkfile.FlagName]string, flagDefs []invowkfile.Flag) error {
+	var validationErrs []DependencyMessage
+	for _, flag := range flagDefs {
+		if err := flag.ValidatePathValue(baseDir, flagValues[flag.Name]); err != nil {
+			validationErrs = append(validationErrs, dependencyMessageFromDetail(err.Error()))
+		}
+	}
+
+	if len(validationErrs) > 0 {
+		return &FlagValidationError{
+			CommandName: invowkfile.CommandName(cmdName), //goplint:ignore -- display value in validation error type
+			Failures:    validationErrs,
+		}
+	}
+
+	return nil
+}
+
+// ValidateArgumentPaths checks "path" argument values against their must_exist
+// and kind options. Relative values are resolved against baseDir (the invowkfile
+// directory). It runs after ValidateArguments, so the argument count is valid.
+//
+//goplint:ignore -- argument-validation helpers intentionally operate on raw argv slices.
+func ValidateArgumentPaths(cmdName string, baseDir invowkfile.FilesystemPath, providedArgs []string, argDefs []invowkfile.Argument) error {
+	for i, argValue := range providedArgs {
+		if len(argDefs) == 0 {
+			return nil
+		}
+		argDef := argDefs[min(i, len(argDefs)-1)]
+		if i >= len(argDefs) && !argDef.Variadic {
+			return nil
+		}
+		if err := argDef.ValidatePathValue(baseDir, argValue); err != nil {
+			return newArgumentValueError(cmdName, providedArgs, argDefs, argDef.Name, argValue, err)
+		}
+	}
+	return nil
+}
+
 // ValidateArguments validates provided arguments against their definitions.
 // It returns an *ArgumentValidationError if validation fails.
 func ValidateArguments(cmdName string, providedArgs []string, argDefs []invowkfile.Argument) error {
@@ -4,6 +4,9 @@ package deps
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
@@ -110,6 +113,35 @@ func TestValidateArguments(t *testing.T) {
 	}
 }
 
+func TestValidateInputPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	baseDir := invowkfile.FilesystemPath(dir)
+	fileFlag := invowkfile.Flag{Name: "config", Type: invowkfile.FlagTypePath, MustExist: true, Kind: invowkfile.PathKindFile}
+	dirArg := invowkfile.Argument{Name: "dirs", Type: invowkfile.ArgumentTypePath, Kind: invowkfile.PathKindDir, Variadic: true}
+
+	if err := ValidateFlagPaths("build", baseDir, map[invowkfile.FlagName]string{"config": "app.yaml"}, []invowkfile.Flag{fileFlag}); err != nil {
+		t.Fatalf("ValidateFlagPaths(existing file) = %v, want nil", err)
+	}
+	err := ValidateFlagPaths("build", baseDir, map[invowkfile.FlagName]string{"config": "missing.yaml"}, []invowkfile.Flag{fileFlag})
+	if !errors.Is(err, ErrFlagValidationFailed) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("ValidateFlagPaths(missing file) = %v, want does not exist", err)
+	}
+
+	if err := ValidateArgumentPaths("build", baseDir, []string{".", "missing"}, []invowkfile.Argument{dirArg}); err != nil {
+		t.Fatalf("ValidateArgumentPaths(dir, missing) = %v, want nil", err)
+	}
+	err = ValidateArgumentPaths("build", baseDir, []string{".", "app.yaml"}, []invowkfile.Argument{dirArg})
+	var argErr *ArgumentValidationError
+	if !errors.As(err, &argErr) || argErr.Type != ArgErrInvalidValue || argErr.InvalidArg != "dirs" {
+		t.Fatalf("ValidateArgumentPaths(file for dir) = %v, want invalid value for dirs", err)
+	}
+}
+
 func TestSummarizeArgDefs(t *testing.T) {
 	t.Parallel()
 
@@ -298,7 +330,7 @@ func testArgumentValidationCountErrorPreservesPayload(t *testing.T) {
 	if argErr.CommandName != "copy" {
 		t.Fatalf("CommandName = %q, want copy", argErr.CommandName)
 	}
-	if !slices.Equal(argErr.ArgDefs, defs) {
+	if !reflect.DeepEqual(argErr.ArgDefs, defs) {
 		t.Fatalf("ArgDefs = %+v, want %+v", argErr.ArgDefs, defs)
 	}
 	if !slices.Equal(argErr.ProvidedArgs, provided) {
@@ -326,7 +358,7 @@ func testArgumentValidationValueErrorPreservesPayload(t *testing.T) {
 	if argErr.CommandName != "serve" {
 		t.Fatalf("CommandName = %q, want serve", argErr.CommandName)
 	}
-	if !slices.Equal(argErr.ArgDefs, defs) {
+	if !reflect.DeepEqual(argErr.ArgDefs, defs) {
 		t.Fatalf("ArgDefs = %+v, want %+v", argErr.ArgDefs, defs)
 	}
 	if !slices.Equal(argErr.ProvidedArgs, provided) {
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package deps
+
+import (
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// ResolvePrerequisites resolves the depends_on.cmds entries that set run: true
+// to the discovered commands that satisfy them. Each entry resolves to its
+// first discoverable and accessible alternative, using the same discoverability
+// and CommandScope rules as CheckCommandDependenciesExist. Results follow
+// declaration order; entries without run: true are ignored.
+func ResolvePrerequisites(disc CommandSetProvider, deps *invowkfile.DependsOn, cmdInfo *discovery.CommandInfo, ctx ExecutionContext, lockProvider CommandScopeLockProvider) ([]*discovery.CommandInfo, error) {
+	prereqs := deps.Prerequisites()
+	if len(prereqs) == 0 {
+		return nil, nil
+	}
+
+	resolved, err := resolveCommandDependenciesWithLockProvider(disc, &invowkfile.DependsOn{Commands: prereqs}, cmdInfo, ctx, lockProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := discoverAvailableCommands(disc, ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]*discovery.CommandInfo, 0, len(resolved))
+	for _, dep := range resolved {
+		if dep.Command == nil {
+			continue
+		}
+		if target, ok := available[*dep.Command]; ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
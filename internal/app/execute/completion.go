@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidCompletionKey is the sentinel error wrapped by InvalidCompletionKeyError.
+	ErrInvalidCompletionKey = errors.New("invalid completion key")
+
+	// ErrInvalidCompletionCandidate is returned when a CompletionCandidate has
+	// an empty value or contains a tab or a line break.
+	ErrInvalidCompletionCandidate = errors.New("invalid completion candidate")
+
+	completionKeyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+type (
+	// CompletionKey identifies the output of one completion script run: the
+	// invowkfile, command, completed flag or argument, script, runtime, and the
+	// environment projected from the values typed so far. Format: 64 lowercase
+	// hex characters (a SHA-256 digest), which keeps keys safe to use as file names.
+	CompletionKey string
+
+	// InvalidCompletionKeyError is returned when a CompletionKey is not a
+	// 64-character lowercase hex digest.
+	InvalidCompletionKeyError struct {
+		Value CompletionKey
+	}
+
+	// CompletionCandidate is one shell completion candidate produced by a
+	// completion script, with an optional description shown by shells that
+	// support it.
+	CompletionCandidate struct {
+		Value       string `json:"value"`
+		Description string `json:"description,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidCompletionKeyError) Error() string {
+	return fmt.Sprintf("invalid completion key %q (must be 64 lowercase hex chars)", e.Value)
+}
+
+// Unwrap returns ErrInvalidCompletionKey for errors.Is compatibility.
+func (e *InvalidCompletionKeyError) Unwrap() error { return ErrInvalidCompletionKey }
+
+// Validate returns nil if the CompletionKey is a SHA-256 hex digest.
+//
+//goplint:nonzero
+func (k CompletionKey) Validate() error {
+	if !completionKeyPattern.MatchString(string(k)) {
+		return &InvalidCompletionKeyError{Value: k}
+	}
+	return nil
+}
+
+// String returns the string representation of the CompletionKey.
+func (k CompletionKey) String() string { return string(k) }
+
+// Validate returns nil if the candidate has a single-line value and
+// description without tabs.
+func (c CompletionCandidate) Validate() error {
+	if c.Value == "" || strings.ContainsAny(c.Value, "\t\r\n") || strings.ContainsAny(c.Description, "\t\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidCompletionCandidate, c.Value)
+	}
+	return nil
+}
+
+// NewCompletionKey derives the cache key of a completion script run. target
+// names the completed flag or argument; env is the environment projected from
+// the flags and args typed so far, so scripts that depend on them are cached
+// per combination of values.
+//
+//goplint:ignore -- environment maps are stringly typed by the runtime execution context.
+func NewCompletionKey(file types.FilesystemPath, name invowkfile.CommandName, target string, complete *invowkfile.CompletionConfig, env map[string]string) CompletionKey {
+	hasher := sha256.New()
+	writeField := func(value string) {
+		// Length-prefix every field so adjacent values cannot run together.
+		fmt.Fprintf(hasher, "%d:%s\n", len(value), value)
+	}
+	writeField(string(file))
+	writeField(string(name))
+	writeField(target)
+	writeField(string(complete.EffectiveRuntime()))
+	writeField(string(complete.Script.Content))
+	if complete.Script.File != nil {
+		writeField(string(*complete.Script.File))
+	}
+	writeField(string(complete.Script.Interpreter))
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		writeField(k)
+		writeField(env[k])
+	}
+	return CompletionKey(hex.EncodeToString(hasher.Sum(nil)))
+}
+
+// ParseCompletionCandidates parses the stdout of a completion script. Every
+// non-blank line is one candidate: the value, optionally followed by a tab
+// and a description. Surrounding whitespace is trimmed and repeated values
+// keep their first occurrence.
+func ParseCompletionCandidates(output string) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	seen := make(map[string]bool)
+	for line := range strings.Lines(output) {
+		value, description, _ := strings.Cut(strings.TrimRight(line, "\r\n"), "\t")
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		candidates = append(candidates, CompletionCandidate{Value: value, Description: strings.TrimSpace(description)})
+	}
+	return candidates
+}
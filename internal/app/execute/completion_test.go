@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestNewCompletionKey(t *testing.T) {
+	t.Parallel()
+
+	complete := &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: "ls envs"}}
+	env := map[string]string{"INVOWK_FLAG_REGION": "eu"}
+	key := NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", complete, env)
+	if err := key.Validate(); err != nil {
+		t.Fatalf("NewCompletionKey() = %q, Validate() = %v", key, err)
+	}
+
+	variants := map[string]CompletionKey{
+		"file":    NewCompletionKey("/other/invowkfile.cue", "deploy", "argument 'env'", complete, env),
+		"name":    NewCompletionKey("/work/invowkfile.cue", "rollback", "argument 'env'", complete, env),
+		"target":  NewCompletionKey("/work/invowkfile.cue", "deploy", "flag 'env'", complete, env),
+		"runtime": NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", &invowkfile.CompletionConfig{Script: complete.Script, Runtime: invowkfile.RuntimeVirtualSh}, env),
+		"script":  NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", &invowkfile.CompletionConfig{Script: invowkfile.ImplementationScript{Content: "ls"}}, env),
+		"env":     NewCompletionKey("/work/invowkfile.cue", "deploy", "argument 'env'", complete, map[string]string{"INVOWK_FLAG_REGION": "us"}),
+	}
+	for field, variant := range variants {
+		if variant == key {
+			t.Errorf("changing %s did not change the key", field)
+		}
+	}
+
+	if err := CompletionKey("../escape").Validate(); !errors.Is(err, ErrInvalidCompletionKey) {
+		t.Errorf("Validate() = %v, want ErrInvalidCompletionKey", err)
+	}
+}
+
+func TestParseCompletionCandidates(t *testing.T) {
+	t.Parallel()
+
+	output := "staging\tStaging cluster\r\n\n  production \t Production cluster \nstaging\tduplicate\ndev"
+	want := []CompletionCandidate{
+		{Value: "staging", Description: "Staging cluster"},
+		{Value: "production", Description: "Production cluster"},
+		{Value: "dev"},
+	}
+	got := ParseCompletionCandidates(output)
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseCompletionCandidates() = %#v, want %#v", got, want)
+	}
+	for _, candidate := range got {
+		if err := candidate.Validate(); err != nil {
+			t.Errorf("candidate %q Validate() = %v", candidate.Value, err)
+		}
+	}
+	if err := (CompletionCandidate{Value: "a\tb"}).Validate(); !errors.Is(err, ErrInvalidCompletionCandidate) {
+		t.Errorf("Validate() = %v, want ErrInvalidCompletionCandidate", err)
+	}
+}
@@ -1,7 +1,8 @@
 // SPDX-License-Identifier: MPL-2.0
 
-// Package execute provides runtime resolution and execution context
-// construction for the invowk command pipeline. It decouples
-// CLI-layer orchestration from runtime selection logic and env var
+// Package execute provides runtime resolution, execution context
+// construction, sequential step execution, prerequisite scheduling, and
+// incremental-execution fingerprints for the invowk command pipeline. It
+// decouples CLI-layer orchestration from runtime selection logic and env var
 // projection.
 package execute
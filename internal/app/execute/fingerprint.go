@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// reasonNoPreviousRun is the stale reason for commands that never completed successfully.
+	reasonNoPreviousRun = "no previous successful run"
+	// reasonForced is the stale reason when the caller bypasses fingerprint checks.
+	reasonForced = "execution was forced"
+	// reasonScriptChanged is the stale reason when the selected implementation's script was edited.
+	reasonScriptChanged = "script changed"
+)
+
+var (
+	// ErrInvalidFingerprintKey is the sentinel error wrapped by InvalidFingerprintKeyError.
+	ErrInvalidFingerprintKey = errors.New("invalid fingerprint key")
+
+	fingerprintKeyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+type (
+	// FingerprintKey identifies one incremental execution configuration: the
+	// command's full name, runtime, working directory, and resolved environment.
+	// A change to any of them makes the command stale. Format: 64 lowercase hex
+	// characters (a SHA-256 digest), which keeps keys safe to use as file names.
+	FingerprintKey string
+
+	// InvalidFingerprintKeyError is returned when a FingerprintKey is not a
+	// 64-character lowercase hex digest.
+	InvalidFingerprintKeyError struct {
+		Value FingerprintKey
+	}
+
+	//goplint:mutable
+	//
+	// Fingerprint records the content hashes of the files matched by a
+	// command's sources and generates patterns, keyed by slash-separated path
+	// relative to the command's working directory, and the hash of the
+	// selected implementation's script (see HashScript).
+	Fingerprint struct {
+		Sources   map[string]string `json:"sources"`
+		Generates map[string]string `json:"generates"`
+		Script    string            `json:"script,omitempty"`
+	}
+
+	// Freshness is the outcome of comparing a command's current fingerprint
+	// with the fingerprint recorded after its last successful run.
+	Freshness struct {
+		key         FingerprintKey
+		current     Fingerprint
+		staleReason string
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidFingerprintKeyError) Error() string {
+	return fmt.Sprintf("invalid fingerprint key %q (must be 64 lowercase hex chars)", e.Value)
+}
+
+// Unwrap returns ErrInvalidFingerprintKey for errors.Is compatibility.
+func (e *InvalidFingerprintKeyError) Unwrap() error { return ErrInvalidFingerprintKey }
+
+// Validate returns nil if the FingerprintKey is a SHA-256 hex digest.
+//
+//goplint:nonzero
+func (k FingerprintKey) Validate() error {
+	if !fingerprintKeyPattern.MatchString(string(k)) {
+		return &InvalidFingerprintKeyError{Value: k}
+	}
+	return nil
+}
+
+// String returns the string representation of the FingerprintKey.
+func (k FingerprintKey) String() string { return string(k) }
+
+// NewFingerprintKey derives the fingerprint key of a command execution from
+// its full name, runtime, working directory, and resolved environment.
+//
+//goplint:ignore -- environment maps are stringly typed by the runtime execution context.
+func NewFingerprintKey(name invowkfile.CommandName, runtime invowkfile.RuntimeMode, workDir types.FilesystemPath, env map[string]string) FingerprintKey {
+	hasher := sha256.New()
+	writeField := func(value string) {
+		// Length-prefix every field so adjacent values cannot run together.
+		fmt.Fprintf(hasher, "%d:%s\n", len(value), value)
+	}
+	writeField(string(name))
+	writeField(string(runtime))
+	writeField(string(workDir))
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		writeField(k)
+		writeField(env[k])
+	}
+	return FingerprintKey(hex.EncodeToString(hasher.Sum(nil)))
+}
+
+// ComputeFingerprint hashes the regular files under baseDir matched by the
+// sources and generates glob patterns. Patterns are slash-separated and
+// relative to baseDir; patterns that match nothing contribute no entries.
+func ComputeFingerprint(baseDir types.FilesystemPath, sources, generates []invowkfile.GlobPattern) (Fingerprint, error) {
+	sourceHashes, err := hashMatchedFiles(baseDir, sources)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	outputHashes, err := hashMatchedFiles(baseDir, generates)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	return Fingerprint{Sources: sourceHashes, Generates: outputHashes}, nil
+}
+
+// HashScript hashes a resolved script (inline content or the contents of its
+// script file) together with its interpreter, so editing either makes the
+// command stale.
+func HashScript(content string, interpreter invowkfile.InterpreterSpec) string {
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%d:%s\n%s", len(interpreter), interpreter, content)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// CheckFreshness compares current with previous, the fingerprint recorded for
+// key after the last successful run (nil when there is none). generates are
+// the command's output patterns: a pattern that matches no file leaves the
+// command stale even when previous agrees, because the output is missing.
+func CheckFreshness(key FingerprintKey, current Fingerprint, previous *Fingerprint, generates []invowkfile.GlobPattern) Freshness {
+	freshness := Freshness{key: key, current: current}
+	if previous == nil {
+		freshness.staleReason = reasonNoPreviousRun
+		return freshness
+	}
+	freshness.staleReason = firstNonEmpty(
+		scriptChangedReason(previous.Script, current.Script),
+		diffFileHashes("source", previous.Sources, current.Sources),
+		missingOutputReason(current.Generates, generates),
+		diffFileHashes("output", previous.Generates, current.Generates),
+	)
+	return freshness
+}
+
+// Forced returns a copy of f that is stale regardless of its fingerprints,
+// used when the caller bypasses incremental checks.
+func (f Freshness) Forced() Freshness {
+	f.staleReason = reasonForced
+	return f
+}
+
+// UpToDate reports whether the command can be skipped.
+func (f Freshness) UpToDate() bool { return f.staleReason == "" }
+
+// StaleReason describes why the command must run; empty when it is up to date.
+func (f Freshness) StaleReason() string { return f.staleReason }
+
+// Key returns the fingerprint key of the checked execution.
+func (f Freshness) Key() FingerprintKey { return f.key }
+
+// Current returns the fingerprint computed for the check.
+func (f Freshness) Current() Fingerprint { return f.current }
+
+// String renders the freshness for dry-run output: "up to date" or
+// "stale because <reason>".
+func (f Freshness) String() string {
+	if f.UpToDate() {
+		return "up to date"
+	}
+	return "stale because " + f.staleReason
+}
+
+func hashMatchedFiles(baseDir types.FilesystemPath, patterns []invowkfile.GlobPattern) (map[string]string, error) {
+	hashes := make(map[string]string)
+	if baseDir == "" {
+		baseDir = "."
+	}
+	fsys := os.DirFS(string(baseDir))
+	for _, pattern := range patterns {
+		matches, err := doublestar.Glob(fsys, string(pattern), doublestar.WithFilesOnly())
+		if err != nil {
+			return nil, fmt.Errorf("matching %q in %s: %w", pattern, baseDir, err)
+		}
+		for _, rel := range matches {
+			if _, seen := hashes[rel]; seen {
+				continue
+			}
+			sum, err := hashFile(filepath.Join(string(baseDir), filepath.FromSlash(rel)))
+			if err != nil {
+				return nil, err
+			}
+			hashes[rel] = sum
+		}
+	}
+	return hashes, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// diffFileHashes returns a reason naming the first (in path order) file that
+// was added, removed, or changed between previous and current; empty when
+// both agree.
+func diffFileHashes(kind string, previous, current map[string]string) string {
+	paths := slices.Sorted(maps.Keys(previous))
+	for path := range current {
+		if _, ok := previous[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		before, hadBefore := previous[path]
+		after, hasNow := current[path]
+		switch {
+		case !hadBefore:
+			return fmt.Sprintf("%s '%s' was added", kind, path)
+		case !hasNow:
+			return fmt.Sprintf("%s '%s' was removed", kind, path)
+		case before != after:
+			return fmt.Sprintf("%s '%s' changed", kind, path)
+		}
+	}
+	return ""
+}
+
+func scriptChangedReason(previous, current string) string {
+	if previous != current {
+		return reasonScriptChanged
+	}
+	return ""
+}
+
+func missingOutputReason(outputs map[string]string, generates []invowkfile.GlobPattern) string {
+	for _, pattern := range generates {
+		matched := false
+		for path := range outputs {
+			if ok, _ := doublestar.Match(string(pattern), path); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("no output matches '%s'", pattern)
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
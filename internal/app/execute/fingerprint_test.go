@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestNewFingerprintKey(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"INVOWK_FLAG_TARGET": "prod", "ARG1": "v1"}
+	key := NewFingerprintKey("build", invowkfile.RuntimeNative, "/work", env)
+	if err := key.Validate(); err != nil {
+		t.Fatalf("NewFingerprintKey() = %q, Validate() = %v", key, err)
+	}
+	if again := NewFingerprintKey("build", invowkfile.RuntimeNative, "/work", map[string]string{"ARG1": "v1", "INVOWK_FLAG_TARGET": "prod"}); again != key {
+		t.Errorf("key depends on env map order: %q != %q", again, key)
+	}
+
+	variants := map[string]FingerprintKey{
+		"name":    NewFingerprintKey("test", invowkfile.RuntimeNative, "/work", env),
+		"runtime": NewFingerprintKey("build", invowkfile.RuntimeVirtualSh, "/work", env),
+		"workdir": NewFingerprintKey("build", invowkfile.RuntimeNative, "/other", env),
+		"env":     NewFingerprintKey("build", invowkfile.RuntimeNative, "/work", map[string]string{"INVOWK_FLAG_TARGET": "dev", "ARG1": "v1"}),
+	}
+	for field, variant := range variants {
+		if variant == key {
+			t.Errorf("changing %s did not change the key", field)
+		}
+	}
+
+	if err := FingerprintKey("../escape").Validate(); !errors.Is(err, ErrInvalidFingerprintKey) {
+		t.Errorf("Validate() = %v, want ErrInvalidFingerprintKey", err)
+	}
+}
+
+func TestCheckFreshness(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFingerprintFile(t, dir, "src/main.go", "package main")
+	writeFingerprintFile(t, dir, "src/util.go", "package main // util")
+	writeFingerprintFile(t, dir, "bin/app", "binary")
+
+	sources := []invowkfile.GlobPattern{"src/**/*.go"}
+	generates := []invowkfile.GlobPattern{"bin/app"}
+	baseDir := types.FilesystemPath(dir)
+	key := NewFingerprintKey("build", invowkfile.RuntimeNative, baseDir, nil)
+
+	previous := computeTestFingerprint(t, baseDir, sources, generates)
+	if len(previous.Sources) != 2 || len(previous.Generates) != 1 {
+		t.Fatalf("ComputeFingerprint() = %#v, want 2 sources and 1 output", previous)
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(t *testing.T)
+		previous   *Fingerprint
+		wantReason string
+	}{
+		{
+			name:       "no previous run",
+			wantReason: "no previous successful run",
+		},
+		{
+			name:     "unchanged",
+			previous: &previous,
+		},
+		{
+			name:       "source changed",
+			mutate:     func(t *testing.T) { writeFingerprintFile(t, dir, "src/util.go", "package main // edited") },
+			previous:   &previous,
+			wantReason: "source 'src/util.go' changed",
+		},
+		{
+			name:       "source added",
+			mutate:     func(t *testing.T) { writeFingerprintFile(t, dir, "src/new.go", "package main") },
+			previous:   &previous,
+			wantReason: "source 'src/new.go' was added",
+		},
+		{
+			name:       "output missing",
+			mutate:     func(t *testing.T) { removeFingerprintFile(t, dir, "bin/app") },
+			previous:   &previous,
+			wantReason: "no output matches 'bin/app'",
+		},
+		{
+			name:       "output modified",
+			mutate:     func(t *testing.T) { writeFingerprintFile(t, dir, "bin/app", "tampered") },
+			previous:   &previous,
+			wantReason: "output 'bin/app' changed",
+		},
+	}
+
+	for _, tt := range tests {
+		// Subtests share the fixture directory, so they run sequentially and
+		// restore the original files afterwards.
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mutate != nil {
+				tt.mutate(t)
+				t.Cleanup(func() {
+					removeFingerprintFile(t, dir, "src/new.go")
+					writeFingerprintFile(t, dir, "src/util.go", "package main // util")
+					writeFingerprintFile(t, dir, "bin/app", "binary")
+				})
+			}
+
+			freshness := CheckFreshness(key, computeTestFingerprint(t, baseDir, sources, generates), tt.previous, generates)
+			if got := freshness.StaleReason(); got != tt.wantReason {
+				t.Fatalf("StaleReason() = %q, want %q", got, tt.wantReason)
+			}
+			if freshness.UpToDate() != (tt.wantReason == "") {
+				t.Errorf("UpToDate() = %v with reason %q", freshness.UpToDate(), tt.wantReason)
+			}
+		})
+	}
+
+	edited := computeTestFingerprint(t, baseDir, sources, generates)
+	edited.Script = HashScript("go build -v", "")
+	withScript := previous
+	withScript.Script = HashScript("go build", "")
+	if got := CheckFreshness(key, edited, &withScript, generates).StaleReason(); got != "script changed" {
+		t.Errorf("StaleReason() after a script edit = %q, want script changed", got)
+	}
+
+	current := computeTestFingerprint(t, baseDir, sources, generates)
+	fresh := CheckFreshness(key, current, &previous, generates)
+	if got := fresh.String(); got != "up to date" {
+		t.Errorf("String() = %q, want up to date", got)
+	}
+	if got := fresh.Forced().String(); got != "stale because execution was forced" {
+		t.Errorf("Forced().String() = %q", got)
+	}
+}
+
+func TestHashScript(t *testing.T) {
+	t.Parallel()
+
+	base := HashScript("go build", "")
+	if again := HashScript("go build", ""); again != base {
+		t.Errorf("HashScript() is not deterministic: %q != %q", again, base)
+	}
+	for name, variant := range map[string]string{
+		"content":     HashScript("go build ./...", ""),
+		"interpreter": HashScript("go build", "bash"),
+		"boundary":    HashScript("o build", "g"),
+	} {
+		if variant == base {
+			t.Errorf("changing the %s did not change the hash", name)
+		}
+	}
+}
+
+func computeTestFingerprint(t *testing.T, baseDir types.FilesystemPath, sources, generates []invowkfile.GlobPattern) Fingerprint {
+	t.Helper()
+
+	fingerprint, err := ComputeFingerprint(baseDir, sources, generates)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	return fingerprint
+}
+
+func writeFingerprintFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func removeFingerprintFile(t *testing.T, dir, rel string) {
+	t.Helper()
+
+	if err := os.Remove(filepath.Join(dir, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+type (
+	// MatrixRunFunc executes one combination of a matrix command and reports
+	// its exit code. A non-nil error or a non-zero exit code marks the
+	// combination as failed.
+	MatrixRunFunc func(ctx context.Context, index int, combination invowkfile.MatrixCombination) (types.ExitCode, error)
+
+	// MatrixOutcome records how a single matrix combination finished.
+	MatrixOutcome struct {
+		// Combination is the variable values of the run.
+		Combination invowkfile.MatrixCombination
+		// ExitCode is the exit code reported by the run.
+		ExitCode types.ExitCode
+		// Err is the execution error reported by the run, if any.
+		Err error
+		// Duration is how long the run took.
+		Duration time.Duration
+	}
+
+	// MatrixResult aggregates the outcomes of a matrix run.
+	MatrixResult struct {
+		// Outcomes lists the outcome of every combination, in combination order.
+		Outcomes []MatrixOutcome
+	}
+
+	// LinePrefixWriter prefixes every line written through it, so that the
+	// output of runs sharing a terminal stays attributable. Complete lines are
+	// written at once under a mutex shared by all writers of the same
+	// destination, so lines of concurrent runs never interleave mid-line.
+	LinePrefixWriter struct {
+		out     io.Writer
+		prefix  []byte
+		mu      *sync.Mutex
+		pending []byte
+	}
+)
+
+// Failed returns true when the run reported an error or a non-zero exit code.
+func (o MatrixOutcome) Failed() bool {
+	return o.Err != nil || o.ExitCode != 0
+}
+
+// Failures returns the number of combinations that failed.
+func (r MatrixResult) Failures() int {
+	failures := 0
+	for _, outcome := range r.Outcomes {
+		if outcome.Failed() {
+			failures++
+		}
+	}
+	return failures
+}
+
+// ExitCode returns the exit code of the first failed combination (1 when it
+// failed with an error and no exit code), or 0 when every combination passed.
+func (r MatrixResult) ExitCode() types.ExitCode {
+	for _, outcome := range r.Outcomes {
+		if outcome.Failed() {
+			return max(outcome.ExitCode, 1)
+		}
+	}
+	return 0
+}
+
+// RunMatrix executes every combination of a matrix command before returning.
+//
+// At most limit combinations run at the same time (values below 1 mean 1, so
+// combinations run one after another in order). A failing combination does
+// not stop the others: the matrix reports every outcome. Context
+// cancellation stops scheduling, and combinations that never started are
+// reported as failed with the context error.
+func RunMatrix(ctx context.Context, combinations []invowkfile.MatrixCombination, limit int, run MatrixRunFunc) MatrixResult {
+	result := MatrixResult{Outcomes: make([]MatrixOutcome, len(combinations))}
+	limit = max(limit, 1)
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, combination := range combinations {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			result.Outcomes[i] = MatrixOutcome{Combination: combination, ExitCode: 1, Err: err}
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-slots }()
+			started := time.Now()
+			exitCode, err := run(ctx, i, combination)
+			result.Outcomes[i] = MatrixOutcome{Combination: combination, ExitCode: exitCode, Err: err, Duration: time.Since(started)}
+		})
+	}
+	wg.Wait()
+	return result
+}
+
+// NewLinePrefixWriter creates a writer that prefixes every line written to
+// out with prefix. mu must be shared by all writers of out.
+func NewLinePrefixWriter(out io.Writer, prefix string, mu *sync.Mutex) *LinePrefixWriter {
+	return &LinePrefixWriter{out: out, prefix: []byte(prefix), mu: mu}
+}
+
+// MatrixOutputPrefix returns the prefix of the output lines of a combination
+// run in parallel, e.g. "[db=postgres, go=1.22] ".
+func MatrixOutputPrefix(combination invowkfile.MatrixCombination) string {
+	return fmt.Sprintf("[%s] ", combination.Label())
+}
+
+// Write buffers p and writes every line it completes, prefixed.
+func (w *LinePrefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		end := bytes.IndexByte(w.pending, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.pending[:end+1]); err != nil {
+			return len(p), err
+		}
+		w.pending = w.pending[end+1:]
+	}
+}
+
+// Flush writes a trailing line that was not terminated by a newline.
+func (w *LinePrefixWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := append(w.pending, '\n')
+	w.pending = nil
+	return w.writeLine(line)
+}
+
+func (w *LinePrefixWriter) writeLine(line []byte) error {
+	buf := make([]byte, 0, len(w.prefix)+len(line))
+	buf = append(buf, w.prefix...)
+	buf = append(buf, line...)
+	_, err := w.out.Write(buf)
+	return err
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestRunMatrix(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	combinations := []invowkfile.MatrixCombination{{"go": "1.22"}, {"go": "1.23"}, {"go": "1.24"}}
+	for _, limit := range []int{0, 1, 3} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			t.Parallel()
+
+			var running, peak atomic.Int32
+			result := RunMatrix(t.Context(), combinations, limit, func(_ context.Context, index int, combination invowkfile.MatrixCombination) (types.ExitCode, error) {
+				peak.Store(max(peak.Load(), running.Add(1)))
+				defer running.Add(-1)
+				switch combination["go"] {
+				case "1.22":
+					return 3, nil
+				case "1.23":
+					return 0, errBoom
+				}
+				return 0, nil
+			})
+
+			if got := int(peak.Load()); got > max(limit, 1) {
+				t.Errorf("%d combinations ran at once, want at most %d", got, max(limit, 1))
+			}
+			if len(result.Outcomes) != len(combinations) {
+				t.Fatalf("RunMatrix() reported %d outcomes, want %d", len(result.Outcomes), len(combinations))
+			}
+			for i, outcome := range result.Outcomes {
+				if outcome.Combination["go"] != combinations[i]["go"] {
+					t.Errorf("outcome %d is %v, want %v (combination order)", i, outcome.Combination, combinations[i])
+				}
+			}
+			if !errors.Is(result.Outcomes[1].Err, errBoom) || result.Outcomes[2].Failed() {
+				t.Errorf("outcomes = %+v, want the second to fail with errBoom and the third to pass", result.Outcomes)
+			}
+			if got := result.Failures(); got != 2 {
+				t.Errorf("Failures() = %d, want 2", got)
+			}
+			if got := result.ExitCode(); got != 3 {
+				t.Errorf("ExitCode() = %d, want 3 (the first failure's)", got)
+			}
+		})
+	}
+}
+
+func TestRunMatrixCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	combinations := []invowkfile.MatrixCombination{{"go": "1.22"}, {"go": "1.23"}}
+	result := RunMatrix(ctx, combinations, 1, func(context.Context, int, invowkfile.MatrixCombination) (types.ExitCode, error) {
+		cancel()
+		return 0, nil
+	})
+
+	if result.Outcomes[0].Failed() {
+		t.Errorf("first outcome = %+v, want it to pass", result.Outcomes[0])
+	}
+	if !errors.Is(result.Outcomes[1].Err, context.Canceled) || result.ExitCode() != 1 {
+		t.Errorf("second outcome = %+v, want it to fail with context.Canceled", result.Outcomes[1])
+	}
+}
+
+func TestLinePrefixWriter(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	var mu sync.Mutex
+	first := NewLinePrefixWriter(&out, MatrixOutputPrefix(invowkfile.MatrixCombination{"go": "1.22"}), &mu)
+	second := NewLinePrefixWriter(&out, "[b] ", &mu)
+
+	fmt.Fprint(first, "building")
+	fmt.Fprint(second, "one\ntwo\n")
+	fmt.Fprint(first, " done\npartial")
+	if err := first.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if err := second.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "[b] one\n[b] two\n[go=1.22] building done\n[go=1.22] partial\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -26,6 +28,7 @@ type (
 		mode     invowkfile.RuntimeMode
 		platform invowkfile.Platform
 		impl     *invowkfile.Implementation
+		reason   string //goplint:ignore -- human-readable explanation rendered by dry-run and verbose output.
 	}
 
 	// InvalidRuntimeSelectionError is returned when a RuntimeSelection has invalid fields.
@@ -66,12 +69,25 @@ type (
 		EnvVars  map[string]string
 
 		FlagValues map[invowkfile.FlagName]string
-		ArgDefs    []invowkfile.Argument
+		// FlagDefs and ArgDefs select how typed values are projected: "path"
+		// values are resolved against the invowkfile directory and "list"
+		// flags are rendered in their list_format.
+		FlagDefs []invowkfile.Flag
+		ArgDefs  []invowkfile.Argument
 
 		EnvInheritMode  invowkfile.EnvInheritMode
 		EnvInheritAllow []invowkfile.EnvVarName
 		EnvInheritDeny  []invowkfile.EnvVarName
 
+		// Matrix is the combination run by a matrix command (nil otherwise).
+		// Each variable is injected as INVOWK_MATRIX_<NAME>.
+		Matrix invowkfile.MatrixCombination
+
+		// DependencyOutputs holds the outputs of the commands that ran earlier
+		// in the same invocation, keyed by producer. Each output is injected as
+		// INVOWK_DEP_<CMD>_<NAME>.
+		DependencyOutputs map[invowkfile.CommandName]invowkfile.CommandOutputs
+
 		// SourceID identifies the origin of the command (invowkfile path or module ID).
 		// Injected as INVOWK_SOURCE so scripts can identify which source they belong to.
 		SourceID discovery.SourceID
@@ -116,6 +132,17 @@ func (r RuntimeSelection) Platform() invowkfile.Platform { return r.platform }
 // Impl returns the resolved implementation.
 func (r RuntimeSelection) Impl() *invowkfile.Implementation { return r.impl }
 
+// Reason explains which when conditions selected the implementation. It is
+// empty when no candidate implementation declares when conditions.
+func (r RuntimeSelection) Reason() string { return r.reason }
+
+// WithRedactedReason returns a copy of r whose reason is passed through
+// redact. Env conditions quote host environment values, which may be secrets.
+func (r RuntimeSelection) WithRedactedReason(redact func(string) string) RuntimeSelection {
+	r.reason = redact(r.reason)
+	return r
+}
+
 // Validate returns nil if the RuntimeSelection has valid fields, or an error if not.
 // Mode must be a recognized RuntimeMode and Impl must not be nil.
 // A selection created via NewRuntimeSelection always passes Validate();
@@ -166,11 +193,15 @@ func (e *RuntimeNotAllowedError) Error() string {
 //  2. Config default runtime (soft fallback)
 //  3. Command default runtime
 //
+// Within each tier, the first implementation for the platform and runtime
+// whose when conditions hold in conditions is selected; the returned
+// selection's Reason explains skipped and selected implementations.
+//
 // The platform parameter makes this function pure — callers pass the resolved
 // platform rather than relying on the host OS at call time. Production code
 // passes invowkfile.CurrentPlatform(); tests pass a fixed platform for
 // deterministic behavior across CI environments.
-func ResolveRuntime(command *invowkfile.Command, commandName invowkfile.CommandName, runtimeOverride invowkfile.RuntimeMode, cfg *config.Config, platform invowkfile.Platform) (RuntimeSelection, error) {
+func ResolveRuntime(command *invowkfile.Command, commandName invowkfile.CommandName, runtimeOverride invowkfile.RuntimeMode, cfg *config.Config, platform invowkfile.Platform, conditions invowkfile.WhenContext) (RuntimeSelection, error) {
 	if runtimeOverride != "" {
 		// Defense-in-depth: the CLI boundary should have already validated the mode
 		// via ParseRuntimeMode, but verify here to catch programmatic misuse.
@@ -187,17 +218,12 @@ func ResolveRuntime(command *invowkfile.Command, commandName invowkfile.CommandN
 			}
 		}
 
-		impl := command.GetImplForPlatformRuntime(platform, runtimeOverride)
+		impl, reason := command.SelectImplementation(platform, runtimeOverride, conditions)
 		if impl == nil {
-			return RuntimeSelection{}, fmt.Errorf(
-				"no implementation found for command '%s' on platform '%s' with runtime '%s'",
-				commandName,
-				platform,
-				runtimeOverride,
-			)
+			return RuntimeSelection{}, noImplementationError(commandName, platform, runtimeOverride, reason)
 		}
 		// Mode is validated above; constructor re-validates (defense-in-depth).
-		return NewRuntimeSelection(runtimeOverride, platform, impl)
+		return newConditionalSelection(runtimeOverride, platform, impl, reason)
 	}
 
 	if cfg != nil && cfg.DefaultRuntime != "" {
@@ -208,26 +234,21 @@ func ResolveRuntime(command *invowkfile.Command, commandName invowkfile.CommandN
 			return RuntimeSelection{}, fmt.Errorf("invalid default_runtime in config: %w", err)
 		}
 		if command.IsRuntimeAllowedForPlatform(platform, configRuntime) {
-			impl := command.GetImplForPlatformRuntime(platform, configRuntime)
+			impl, reason := command.SelectImplementation(platform, configRuntime, conditions)
 			if impl != nil {
 				// Mode is validated above; constructor re-validates (defense-in-depth).
-				return NewRuntimeSelection(configRuntime, platform, impl)
+				return newConditionalSelection(configRuntime, platform, impl, reason)
 			}
 		}
 	}
 
-	defaultRuntime := command.GetDefaultRuntimeForPlatform(platform)
-	defaultImpl := command.GetImplForPlatformRuntime(platform, defaultRuntime)
+	defaultRuntime := command.GetDefaultRuntimeForPlatformWhen(platform, conditions)
+	defaultImpl, reason := command.SelectImplementation(platform, defaultRuntime, conditions)
 	if defaultImpl == nil {
-		return RuntimeSelection{}, fmt.Errorf(
-			"no implementation found for command '%s' on platform '%s' with runtime '%s'",
-			commandName,
-			platform,
-			defaultRuntime,
-		)
+		return RuntimeSelection{}, noImplementationError(commandName, platform, defaultRuntime, reason)
 	}
 
-	return NewRuntimeSelection(defaultRuntime, platform, defaultImpl)
+	return newConditionalSelection(defaultRuntime, platform, defaultImpl, reason)
 }
 
 // BuildExecutionContext converts options into a runtime.ExecutionContext.
@@ -299,12 +320,15 @@ func projectEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.Executio
 	projectCommandEnvVars(opts, execCtx, selectedPlatform)
 	projectArgEnvVars(opts, execCtx)
 	projectFlagEnvVars(opts, execCtx)
+	projectMatrixEnvVars(opts, execCtx)
+	projectDependencyOutputEnvVars(opts, execCtx)
 }
 
 func projectCommandEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext, selectedPlatform invowkfile.Platform) {
 	execCtx.Env.ExtraEnv[runtime.EnvVarCmdName] = string(opts.Command.Name)
 	execCtx.Env.ExtraEnv[runtime.EnvVarRuntime] = string(opts.Selection.Mode())
-	// EnvVarSource and EnvVarPlatform are conditionally injected (only when
+	execCtx.Env.ExtraEnv[runtime.EnvVarAttempt] = "1"
+	// EnvVarSource, EnvVarPlatform, and EnvVarArch are conditionally injected (only when
 	// non-empty), but unconditionally filtered in shouldFilterEnvVar. The
 	// asymmetry is intentional: filtering prevents leakage even if future
 	// code paths inject these vars unconditionally.
@@ -314,6 +338,21 @@ func projectCommandEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.E
 	if selectedPlatform != "" {
 		execCtx.Env.ExtraEnv[runtime.EnvVarPlatform] = string(selectedPlatform)
 	}
+	if execCtx.SelectedArch != "" {
+		execCtx.Env.ExtraEnv[runtime.EnvVarArch] = string(execCtx.SelectedArch)
+	}
+}
+
+func projectMatrixEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext) {
+	for name, value := range opts.Matrix {
+		execCtx.Env.ExtraEnv[name.EnvVar()] = value
+	}
+}
+
+func projectDependencyOutputEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext) {
+	for producer, outputs := range opts.DependencyOutputs {
+		maps.Copy(execCtx.Env.ExtraEnv, outputs.DependencyEnv(producer))
+	}
 }
 
 func projectArgEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext) {
@@ -332,17 +371,22 @@ func projectArgDefEnvVar(opts BuildExecutionContextOptions, execCtx *runtime.Exe
 	envName := invowkfile.ArgNameToEnvVar(argDef.Name)
 	switch {
 	case argDef.Variadic:
-		projectVariadicArgEnvVars(opts, execCtx, index, envName)
+		projectVariadicArgEnvVars(opts, execCtx, index, argDef)
 	case index < len(opts.Args):
-		execCtx.Env.ExtraEnv[envName] = opts.Args[index]
+		execCtx.Env.ExtraEnv[envName] = argDef.EnvValue(opts.Invowkfile.GetScriptBasePath(), opts.Args[index])
 	case argDef.DefaultValue != "":
-		execCtx.Env.ExtraEnv[envName] = argDef.DefaultValue
+		execCtx.Env.ExtraEnv[envName] = argDef.EnvValue(opts.Invowkfile.GetScriptBasePath(), argDef.DefaultValue)
 	}
 }
 
-//goplint:ignore -- argument index and envName are derived adapter values for exported process environment keys.
-func projectVariadicArgEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext, index int, envName string) {
-	values := opts.Args[min(index, len(opts.Args)):]
+//goplint:ignore -- argument index maps user argv positions into exported process environment keys.
+func projectVariadicArgEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext, index int, argDef invowkfile.Argument) {
+	envName := invowkfile.ArgNameToEnvVar(argDef.Name)
+	baseDir := opts.Invowkfile.GetScriptBasePath()
+	values := make([]string, 0, max(len(opts.Args)-index, 0))
+	for _, value := range opts.Args[min(index, len(opts.Args)):] {
+		values = append(values, argDef.EnvValue(baseDir, value))
+	}
 	execCtx.Env.ExtraEnv[envName+"_COUNT"] = strconv.Itoa(len(values))
 	for i, value := range values {
 		execCtx.Env.ExtraEnv[fmt.Sprintf("%s_%d", envName, i+1)] = value
@@ -351,7 +395,33 @@ func projectVariadicArgEnvVars(opts BuildExecutionContextOptions, execCtx *runti
 }
 
 func projectFlagEnvVars(opts BuildExecutionContextOptions, execCtx *runtime.ExecutionContext) {
+	baseDir := opts.Invowkfile.GetScriptBasePath()
 	for name, value := range opts.FlagValues {
-		execCtx.Env.ExtraEnv[invowkfile.FlagNameToEnvVar(name)] = value
+		envValue := value
+		if i := slices.IndexFunc(opts.FlagDefs, func(f invowkfile.Flag) bool { return f.Name == name }); i >= 0 {
+			envValue = opts.FlagDefs[i].EnvValue(baseDir, value)
+		}
+		execCtx.Env.ExtraEnv[invowkfile.FlagNameToEnvVar(name)] = envValue
+	}
+}
+
+// newConditionalSelection creates a validated RuntimeSelection carrying the
+// when-condition explanation of the implementation choice.
+func newConditionalSelection(mode invowkfile.RuntimeMode, platform invowkfile.Platform, impl *invowkfile.Implementation, reason string) (RuntimeSelection, error) {
+	selection, err := NewRuntimeSelection(mode, platform, impl)
+	if err != nil {
+		return RuntimeSelection{}, err
+	}
+	selection.reason = reason
+	return selection, nil
+}
+
+// noImplementationError reports that no implementation matched, including
+// the skipped conditional implementations when there were any.
+func noImplementationError(commandName invowkfile.CommandName, platform invowkfile.Platform, mode invowkfile.RuntimeMode, reason string) error {
+	err := fmt.Errorf("no implementation found for command '%s' on platform '%s' with runtime '%s'", commandName, platform, mode)
+	if reason != "" {
+		return fmt.Errorf("%w (%s)", err, reason)
 	}
+	return err
 }
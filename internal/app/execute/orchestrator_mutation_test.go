@@ -4,6 +4,7 @@ package execute
 
 import (
 	"errors"
+	"path/filepath"
 	"slices"
 	"testing"
 
@@ -61,7 +62,7 @@ func TestResolveRuntimeMutationErrorContracts(t *testing.T) {
 
 	cmd := executeMutationCommand()
 
-	_, err := ResolveRuntime(cmd, "deploy", invowkfile.RuntimeMode("bogus"), nil, invowkfile.PlatformLinux)
+	_, err := ResolveRuntime(cmd, "deploy", invowkfile.RuntimeMode("bogus"), nil, invowkfile.PlatformLinux, invowkfile.WhenContext{})
 	if err == nil {
 		t.Fatal("ResolveRuntime(invalid override) error = nil, want ErrInvalidRuntimeMode")
 	}
@@ -69,7 +70,7 @@ func TestResolveRuntimeMutationErrorContracts(t *testing.T) {
 		t.Fatalf("ResolveRuntime(invalid override) error = %v, want ErrInvalidRuntimeMode", err)
 	}
 
-	_, err = ResolveRuntime(cmd, "deploy", "", &config.Config{DefaultRuntime: config.RuntimeMode("magical")}, invowkfile.PlatformLinux)
+	_, err = ResolveRuntime(cmd, "deploy", "", &config.Config{DefaultRuntime: config.RuntimeMode("magical")}, invowkfile.PlatformLinux, invowkfile.WhenContext{})
 	if err == nil {
 		t.Fatal("ResolveRuntime(invalid config default) error = nil, want wrapped ErrInvalidRuntimeMode")
 	}
@@ -77,7 +78,7 @@ func TestResolveRuntimeMutationErrorContracts(t *testing.T) {
 		t.Fatalf("ResolveRuntime(invalid config default) error = %v, want wrapped ErrInvalidRuntimeMode", err)
 	}
 
-	got, err := ResolveRuntime(cmd, "deploy", "", &config.Config{}, invowkfile.PlatformLinux)
+	got, err := ResolveRuntime(cmd, "deploy", "", &config.Config{}, invowkfile.PlatformLinux, invowkfile.WhenContext{})
 	if err != nil {
 		t.Fatalf("ResolveRuntime(empty config default) error = %v", err)
 	}
@@ -86,7 +87,7 @@ func TestResolveRuntimeMutationErrorContracts(t *testing.T) {
 	}
 
 	virtualOnly := executeMutationCommandWithRuntimes(invowkfile.RuntimeVirtualSh)
-	_, err = ResolveRuntime(virtualOnly, "deploy", invowkfile.RuntimeNative, nil, invowkfile.PlatformLinux)
+	_, err = ResolveRuntime(virtualOnly, "deploy", invowkfile.RuntimeNative, nil, invowkfile.PlatformLinux, invowkfile.WhenContext{})
 	if err == nil {
 		t.Fatal("ResolveRuntime(disallowed override) error = nil")
 	}
@@ -274,6 +275,54 @@ func TestBuildExecutionContextMutationPlatformAndArgumentBoundaries(t *testing.T
 	}
 }
 
+func TestBuildExecutionContextProjectsTypedInputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(dir, "invowkfile.cue"))}
+
+	got, err := BuildExecutionContext(t.Context(), BuildExecutionContextOptions{
+		Command:    &invowkfile.Command{Name: "deploy"},
+		Invowkfile: inv,
+		Selection:  RuntimeSelectionOf(invowkfile.RuntimeNative, &invowkfile.Implementation{}),
+		Args:       []string{"conf/app.yaml", "a.txt", "b.txt"},
+		ArgDefs: []invowkfile.Argument{
+			{Name: "config", Type: invowkfile.ArgumentTypePath},
+			{Name: "files", Type: invowkfile.ArgumentTypePath, Variadic: true},
+		},
+		FlagValues: map[invowkfile.FlagName]string{
+			"tag":  `["v1","latest"]`,
+			"host": `["a.example"]`,
+			"out":  "dist",
+			"name": `["raw"]`,
+		},
+		FlagDefs: []invowkfile.Flag{
+			{Name: "tag", Type: invowkfile.FlagTypeList},
+			{Name: "host", Type: invowkfile.FlagTypeList, ListFormat: invowkfile.ListFormatJSON},
+			{Name: "out", Type: invowkfile.FlagTypePath},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildExecutionContext() error = %v", err)
+	}
+
+	want := map[string]string{
+		"INVOWK_ARG_CONFIG":  filepath.Join(dir, "conf", "app.yaml"),
+		"INVOWK_ARG_FILES_1": filepath.Join(dir, "a.txt"),
+		"INVOWK_ARG_FILES":   filepath.Join(dir, "a.txt") + " " + filepath.Join(dir, "b.txt"),
+		"ARG1":               "conf/app.yaml",
+		"INVOWK_FLAG_TAG":    "v1\nlatest",
+		"INVOWK_FLAG_HOST":   `["a.example"]`,
+		"INVOWK_FLAG_OUT":    filepath.Join(dir, "dist"),
+		"INVOWK_FLAG_NAME":   `["raw"]`,
+	}
+	for name, value := range want {
+		if got.Env.ExtraEnv[name] != value {
+			t.Errorf("%s = %q, want %q", name, got.Env.ExtraEnv[name], value)
+		}
+	}
+}
+
 func TestApplyEnvInheritOverridesMutationContracts(t *testing.T) {
 	t.Parallel()
 
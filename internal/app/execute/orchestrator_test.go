@@ -109,7 +109,7 @@ func TestResolveRuntime(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := ResolveRuntime(tt.cmd, "test", tt.override, tt.cfg, invowkfile.PlatformLinux)
+			got, err := ResolveRuntime(tt.cmd, "test", tt.override, tt.cfg, invowkfile.PlatformLinux, invowkfile.WhenContext{})
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -139,6 +139,62 @@ func TestResolveRuntime(t *testing.T) {
 	}
 }
 
+func TestResolveRuntime_WhenConditions(t *testing.T) {
+	t.Parallel()
+
+	linux := []invowkfile.PlatformConfig{{Name: invowkfile.PlatformLinux}}
+	cmd := &invowkfile.Command{
+		Name: "deploy",
+		Implementations: []invowkfile.Implementation{
+			{
+				Script:    invowkfile.ImplementationScript{Content: "make deploy-ci"},
+				Runtimes:  []invowkfile.RuntimeConfig{{Name: invowkfile.RuntimeVirtualSh}},
+				Platforms: linux,
+				When:      &invowkfile.When{Env: map[invowkfile.EnvVarName]string{"CI": "true"}},
+			},
+			{
+				Script:    invowkfile.ImplementationScript{Content: "make deploy"},
+				Runtimes:  []invowkfile.RuntimeConfig{{Name: invowkfile.RuntimeNative}, {Name: invowkfile.RuntimeVirtualSh}},
+				Platforms: linux,
+			},
+		},
+	}
+	inCI := invowkfile.WhenContext{LookupEnv: func(name string) (string, bool) { return "true", name == "CI" }}
+
+	got, err := ResolveRuntime(cmd, "deploy", "", nil, invowkfile.PlatformLinux, inCI)
+	if err != nil {
+		t.Fatalf("ResolveRuntime(CI) error = %v", err)
+	}
+	if got.Mode() != invowkfile.RuntimeVirtualSh || got.Impl() != &cmd.Implementations[0] {
+		t.Errorf("ResolveRuntime(CI) = %s %p, want the conditional virtual implementation", got.Mode(), got.Impl())
+	}
+	if got.Reason() != `implementation #1 selected: env CI="true"` {
+		t.Errorf("ResolveRuntime(CI) reason = %q", got.Reason())
+	}
+
+	got, err = ResolveRuntime(cmd, "deploy", invowkfile.RuntimeVirtualSh, nil, invowkfile.PlatformLinux, invowkfile.WhenContext{})
+	if err != nil {
+		t.Fatalf("ResolveRuntime(local override) error = %v", err)
+	}
+	if got.Impl() != &cmd.Implementations[1] || !strings.Contains(got.Reason(), "implementation #1 skipped") {
+		t.Errorf("ResolveRuntime(local override) = %p, %q; want the fallback implementation", got.Impl(), got.Reason())
+	}
+
+	got, err = ResolveRuntime(cmd, "deploy", "", nil, invowkfile.PlatformLinux, invowkfile.WhenContext{})
+	if err != nil {
+		t.Fatalf("ResolveRuntime(local) error = %v", err)
+	}
+	if got.Mode() != invowkfile.RuntimeNative || got.Reason() != "" {
+		t.Errorf("ResolveRuntime(local) = %s, %q; want native without reason", got.Mode(), got.Reason())
+	}
+
+	cmd.Implementations = cmd.Implementations[:1]
+	_, err = ResolveRuntime(cmd, "deploy", "", nil, invowkfile.PlatformLinux, invowkfile.WhenContext{})
+	if err == nil || !strings.Contains(err.Error(), `implementation #1 skipped: env CI is unset, want "true"`) {
+		t.Fatalf("ResolveRuntime(no match) error = %v, want the skipped conditions", err)
+	}
+}
+
 func TestBuildExecutionContext(t *testing.T) {
 	t.Parallel()
 
@@ -216,6 +272,19 @@ func TestBuildExecutionContext(t *testing.T) {
 				"INVOWK_FLAG_VERBOSE":     "true",
 			},
 		},
+		{
+			name: "Matrix projection",
+			opts: BuildExecutionContextOptions{
+				Command:    cmd,
+				Invowkfile: inv,
+				Selection:  sel,
+				Matrix:     invowkfile.MatrixCombination{"go": "1.22", "db_engine": "postgres"},
+			},
+			want: map[string]string{
+				"INVOWK_MATRIX_GO":        "1.22",
+				"INVOWK_MATRIX_DB_ENGINE": "postgres",
+			},
+		},
 		{
 			name: "Metadata env vars injected",
 			opts: BuildExecutionContextOptions{
@@ -228,8 +297,10 @@ func TestBuildExecutionContext(t *testing.T) {
 			want: map[string]string{
 				"INVOWK_CMD_NAME": "test",
 				"INVOWK_RUNTIME":  "native",
+				"INVOWK_ATTEMPT":  "1",
 				"INVOWK_SOURCE":   "my-module",
 				"INVOWK_PLATFORM": "linux",
+				"INVOWK_ARCH":     string(invowkfile.CurrentArchitecture()),
 			},
 		},
 		{
@@ -242,6 +313,7 @@ func TestBuildExecutionContext(t *testing.T) {
 			want: map[string]string{
 				"INVOWK_CMD_NAME": "test",
 				"INVOWK_RUNTIME":  "native",
+				"INVOWK_ATTEMPT":  "1",
 			},
 		},
 	}
@@ -374,13 +446,16 @@ func TestBuildExecutionContext_MetadataOmittedWhenEmpty(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// INVOWK_CMD_NAME and INVOWK_RUNTIME are always set.
+	// INVOWK_CMD_NAME, INVOWK_RUNTIME, and INVOWK_ATTEMPT are always set.
 	if got := gotCtx.Env.ExtraEnv["INVOWK_CMD_NAME"]; got != "test" {
 		t.Errorf("INVOWK_CMD_NAME = %q, want %q", got, "test")
 	}
 	if got := gotCtx.Env.ExtraEnv["INVOWK_RUNTIME"]; got != "native" {
 		t.Errorf("INVOWK_RUNTIME = %q, want %q", got, "native")
 	}
+	if got := gotCtx.Env.ExtraEnv["INVOWK_ATTEMPT"]; got != "1" {
+		t.Errorf("INVOWK_ATTEMPT = %q, want %q", got, "1")
+	}
 
 	// INVOWK_SOURCE and INVOWK_PLATFORM should NOT be present when SourceID/Platform are empty.
 	if _, ok := gotCtx.Env.ExtraEnv["INVOWK_SOURCE"]; ok {
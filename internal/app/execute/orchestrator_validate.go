@@ -83,6 +83,19 @@ func (o BuildExecutionContextOptions) appendEnvValidationErrors(errs *[]error) {
 			*errs = append(*errs, err)
 		}
 	}
+	if o.Matrix != nil {
+		if err := o.Matrix.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+	for producer, outputs := range o.DependencyOutputs {
+		if err := producer.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+		if err := outputs.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
 }
 
 func (o BuildExecutionContextOptions) appendMetadataValidationErrors(errs *[]error) {
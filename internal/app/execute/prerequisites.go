@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/internal/discovery"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrPrerequisiteFailed is the sentinel error wrapped by PrerequisiteFailedError.
+	ErrPrerequisiteFailed = errors.New("prerequisite command failed")
+
+	// ErrPrerequisiteCycle is the sentinel error wrapped by PrerequisiteCycleError.
+	ErrPrerequisiteCycle = errors.New("prerequisite command cycle")
+)
+
+type (
+	//goplint:validate-all
+	//
+	// PrerequisiteID identifies a command in a prerequisite graph. Commands
+	// from different sources can share a name, so the name is qualified by
+	// the source declaring the command.
+	PrerequisiteID struct {
+		SourceID discovery.SourceID
+		Name     invowkfile.CommandName
+	}
+
+	// PrerequisiteResolveFunc returns the direct prerequisites of a command,
+	// i.e. the commands its depends_on.cmds entries with run: true resolve to.
+	PrerequisiteResolveFunc func(id PrerequisiteID) ([]PrerequisiteID, error)
+
+	// PrerequisiteRunFunc executes one prerequisite command and reports its exit code.
+	// A non-nil error or a non-zero exit code marks the prerequisite as failed.
+	PrerequisiteRunFunc func(ctx context.Context, id PrerequisiteID) (types.ExitCode, error)
+
+	// PrerequisiteGraph is the deduplicated DAG of prerequisite commands
+	// reachable from a root command. A prerequisite shared by several commands
+	// appears (and runs) once.
+	PrerequisiteGraph struct {
+		root  PrerequisiteID
+		needs map[PrerequisiteID][]PrerequisiteID
+		order []PrerequisiteID
+	}
+
+	// PrerequisiteFailedError is returned when a prerequisite command fails.
+	// It wraps both ErrPrerequisiteFailed and the prerequisite's own error so
+	// callers can still match typed runtime and dependency errors with errors.As.
+	PrerequisiteFailedError struct {
+		CommandName  invowkfile.CommandName
+		Prerequisite invowkfile.CommandName
+		ExitCode     types.ExitCode
+		Err          error
+	}
+
+	// PrerequisiteCycleError is returned when prerequisites (directly or
+	// indirectly) depend on themselves.
+	PrerequisiteCycleError struct {
+		Chain []invowkfile.CommandName
+	}
+
+	prerequisiteOutcome struct {
+		id       PrerequisiteID
+		exitCode types.ExitCode
+		err      error
+	}
+)
+
+// Validate returns nil if the PrerequisiteID has a valid command name and, when
+// set, a valid source ID.
+func (id PrerequisiteID) Validate() error {
+	var errs []error
+	if id.SourceID != "" {
+		if err := id.SourceID.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := id.Name.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Error implements the error interface.
+func (e *PrerequisiteFailedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("prerequisite '%s' of command '%s' failed: %v", e.Prerequisite, e.CommandName, e.Err)
+	}
+	return fmt.Sprintf("prerequisite '%s' of command '%s' failed with exit code %d", e.Prerequisite, e.CommandName, e.ExitCode)
+}
+
+// Unwrap returns ErrPrerequisiteFailed and the underlying error for errors.Is/As chains.
+func (e *PrerequisiteFailedError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrPrerequisiteFailed}
+	}
+	return []error{ErrPrerequisiteFailed, e.Err}
+}
+
+// Error implements the error interface.
+func (e *PrerequisiteCycleError) Error() string {
+	names := make([]string, len(e.Chain))
+	for i, name := range e.Chain {
+		names[i] = string(name)
+	}
+	return "prerequisite commands form a cycle: " + strings.Join(names, " -> ")
+}
+
+// Unwrap returns ErrPrerequisiteCycle for errors.Is compatibility.
+func (e *PrerequisiteCycleError) Unwrap() error { return ErrPrerequisiteCycle }
+
+// BuildPrerequisiteGraph walks the prerequisites reachable from root and
+// returns the deduplicated graph. It returns a PrerequisiteCycleError when a
+// command is reachable from itself, and any error reported by resolve.
+func BuildPrerequisiteGraph(root PrerequisiteID, resolve PrerequisiteResolveFunc) (*PrerequisiteGraph, error) {
+	graph := &PrerequisiteGraph{
+		root:  root,
+		needs: make(map[PrerequisiteID][]PrerequisiteID),
+	}
+
+	var stack []PrerequisiteID
+	var visit func(id PrerequisiteID) error
+	visit = func(id PrerequisiteID) error {
+		if idx := slices.Index(stack, id); idx >= 0 {
+			chain := make([]invowkfile.CommandName, 0, len(stack)-idx+1)
+			for _, entry := range stack[idx:] {
+				chain = append(chain, entry.Name)
+			}
+			return &PrerequisiteCycleError{Chain: append(chain, id.Name)}
+		}
+		if _, done := graph.needs[id]; done {
+			return nil
+		}
+
+		needs, err := resolve(id)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, id)
+		var unique []PrerequisiteID
+		for _, need := range needs {
+			if slices.Contains(unique, need) {
+				continue
+			}
+			unique = append(unique, need)
+			if err := visit(need); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+
+		graph.needs[id] = unique
+		if id != root {
+			graph.order = append(graph.order, id)
+		}
+		return nil
+	}
+
+	if err := visit(root); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// Order returns the prerequisites in a valid sequential execution order: every
+// command appears after the commands it depends on. The root is not included.
+func (g *PrerequisiteGraph) Order() []PrerequisiteID {
+	return slices.Clone(g.order)
+}
+
+// Needs returns the direct prerequisites of the identified command.
+func (g *PrerequisiteGraph) Needs(id PrerequisiteID) []PrerequisiteID {
+	return slices.Clone(g.needs[id])
+}
+
+// RunPrerequisites executes every prerequisite in the graph before returning.
+//
+// A prerequisite starts once all of its own prerequisites succeeded, and at
+// most limit prerequisites run at the same time (values below 1 mean 1), so
+// independent branches of the graph run in parallel. The first failure stops
+// scheduling: prerequisites that are already running finish, nothing new starts,
+// and the failure is returned as a PrerequisiteFailedError. Context cancellation
+// likewise stops scheduling.
+func RunPrerequisites(ctx context.Context, graph *PrerequisiteGraph, limit int, run PrerequisiteRunFunc) error {
+	if len(graph.order) == 0 {
+		return nil
+	}
+	limit = max(limit, 1)
+
+	pending := make(map[PrerequisiteID]int, len(graph.order))
+	dependents := make(map[PrerequisiteID][]PrerequisiteID)
+	var ready []PrerequisiteID
+	for _, id := range graph.order {
+		pending[id] = len(graph.needs[id])
+		for _, need := range graph.needs[id] {
+			dependents[need] = append(dependents[need], id)
+		}
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	results := make(chan prerequisiteOutcome)
+	running := 0
+	var failure *PrerequisiteFailedError
+	for {
+		for failure == nil && running < limit && len(ready) > 0 {
+			id := ready[0]
+			if err := ctx.Err(); err != nil {
+				failure = &PrerequisiteFailedError{CommandName: graph.root.Name, Prerequisite: id.Name, ExitCode: 1, Err: err}
+				break
+			}
+			ready = ready[1:]
+			running++
+			go func() {
+				exitCode, err := run(ctx, id)
+				results <- prerequisiteOutcome{id: id, exitCode: exitCode, err: err}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		outcome := <-results
+		running--
+		if outcome.err != nil || outcome.exitCode != 0 {
+			if failure == nil {
+				exitCode := outcome.exitCode
+				if exitCode == 0 {
+					exitCode = 1
+				}
+				failure = &PrerequisiteFailedError{CommandName: graph.root.Name, Prerequisite: outcome.id.Name, ExitCode: exitCode, Err: outcome.err}
+			}
+			continue
+		}
+		for _, dependent := range dependents[outcome.id] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if failure != nil {
+		return failure
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// prerequisiteResolver resolves the edges between commands of one source.
+func prerequisiteResolver(edges map[invowkfile.CommandName][]invowkfile.CommandName) PrerequisiteResolveFunc {
+	return func(id PrerequisiteID) ([]PrerequisiteID, error) {
+		return prerequisiteIDs(edges[id.Name]...), nil
+	}
+}
+
+func prerequisiteIDs(names ...invowkfile.CommandName) []PrerequisiteID {
+	ids := make([]PrerequisiteID, 0, len(names))
+	for _, name := range names {
+		ids = append(ids, PrerequisiteID{Name: name})
+	}
+	return ids
+}
+
+func TestBuildPrerequisiteGraph(t *testing.T) {
+	t.Parallel()
+
+	graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(map[invowkfile.CommandName][]invowkfile.CommandName{
+		"deploy": {"build", "test", "build"},
+		"test":   {"build", "lint"},
+	}))
+	if err != nil {
+		t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+	}
+
+	want := prerequisiteIDs("build", "lint", "test")
+	if got := graph.Order(); !slices.Equal(got, want) {
+		t.Fatalf("Order() = %v, want %v", got, want)
+	}
+	if got := graph.Needs(PrerequisiteID{Name: "deploy"}); !slices.Equal(got, prerequisiteIDs("build", "test")) {
+		t.Errorf("Needs(deploy) = %v, want deduplicated [build test]", got)
+	}
+}
+
+func TestBuildPrerequisiteGraphKeepsSameNamedCommandsApart(t *testing.T) {
+	t.Parallel()
+
+	root := PrerequisiteID{SourceID: "invowkfile", Name: "deploy"}
+	local := PrerequisiteID{SourceID: "invowkfile", Name: "build"}
+	shared := PrerequisiteID{SourceID: "tools", Name: "build"}
+	graph, err := BuildPrerequisiteGraph(root, func(id PrerequisiteID) ([]PrerequisiteID, error) {
+		if id == root {
+			return []PrerequisiteID{local, shared}, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+	}
+	if got := graph.Order(); !slices.Equal(got, []PrerequisiteID{local, shared}) {
+		t.Fatalf("Order() = %v, want both build commands", got)
+	}
+}
+
+func TestBuildPrerequisiteGraphDetectsCycles(t *testing.T) {
+	t.Parallel()
+
+	_, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(map[invowkfile.CommandName][]invowkfile.CommandName{
+		"deploy": {"build"},
+		"build":  {"gen"},
+		"gen":    {"build"},
+	}))
+	if !errors.Is(err, ErrPrerequisiteCycle) {
+		t.Fatalf("BuildPrerequisiteGraph() error = %v, want ErrPrerequisiteCycle", err)
+	}
+	if want := "prerequisite commands form a cycle: build -> gen -> build"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRunPrerequisites(t *testing.T) {
+	t.Parallel()
+
+	edges := map[invowkfile.CommandName][]invowkfile.CommandName{
+		"deploy":  {"test", "package"},
+		"test":    {"build"},
+		"package": {"build"},
+	}
+
+	t.Run("runs each prerequisite once after its needs", func(t *testing.T) {
+		t.Parallel()
+
+		graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(edges))
+		if err != nil {
+			t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+		}
+
+		var mu sync.Mutex
+		var ran []invowkfile.CommandName
+		err = RunPrerequisites(t.Context(), graph, 4, func(_ context.Context, id PrerequisiteID) (types.ExitCode, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, id.Name)
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("RunPrerequisites() error = %v", err)
+		}
+		if len(ran) != 3 || ran[0] != "build" {
+			t.Fatalf("ran = %v, want build first and 3 prerequisites", ran)
+		}
+	})
+
+	t.Run("runs independent branches in parallel", func(t *testing.T) {
+		t.Parallel()
+
+		graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(map[invowkfile.CommandName][]invowkfile.CommandName{
+			"deploy": {"a", "b"},
+		}))
+		if err != nil {
+			t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+		}
+
+		// Each prerequisite waits for the other to start, so the run only
+		// completes when both execute concurrently.
+		var started sync.WaitGroup
+		started.Add(2)
+		err = RunPrerequisites(t.Context(), graph, 2, func(context.Context, PrerequisiteID) (types.ExitCode, error) {
+			started.Done()
+			started.Wait()
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("RunPrerequisites() error = %v", err)
+		}
+	})
+
+	t.Run("failure stops dependents", func(t *testing.T) {
+		t.Parallel()
+
+		graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(edges))
+		if err != nil {
+			t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+		}
+
+		var ran []invowkfile.CommandName
+		err = RunPrerequisites(t.Context(), graph, 1, func(_ context.Context, id PrerequisiteID) (types.ExitCode, error) {
+			ran = append(ran, id.Name)
+			if id.Name == "build" {
+				return 2, nil
+			}
+			return 0, nil
+		})
+		var failed *PrerequisiteFailedError
+		if !errors.As(err, &failed) || !errors.Is(err, ErrPrerequisiteFailed) {
+			t.Fatalf("RunPrerequisites() error = %v, want PrerequisiteFailedError", err)
+		}
+		if failed.Prerequisite != "build" || failed.CommandName != "deploy" || failed.ExitCode != 2 {
+			t.Errorf("failure = %#v", failed)
+		}
+		if !slices.Equal(ran, []invowkfile.CommandName{"build"}) {
+			t.Errorf("ran = %v, want only build", ran)
+		}
+	})
+
+	t.Run("error keeps typed cause", func(t *testing.T) {
+		t.Parallel()
+
+		errBoom := errors.New("boom")
+		graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(map[invowkfile.CommandName][]invowkfile.CommandName{
+			"deploy": {"build"},
+		}))
+		if err != nil {
+			t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+		}
+		err = RunPrerequisites(t.Context(), graph, 1, func(context.Context, PrerequisiteID) (types.ExitCode, error) {
+			return 0, errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("RunPrerequisites() error = %v, want wrapped errBoom", err)
+		}
+		if want := "prerequisite 'build' of command 'deploy' failed: boom"; err.Error() != want {
+			t.Errorf("error = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("cancellation stops scheduling", func(t *testing.T) {
+		t.Parallel()
+
+		graph, err := BuildPrerequisiteGraph(PrerequisiteID{Name: "deploy"}, prerequisiteResolver(edges))
+		if err != nil {
+			t.Fatalf("BuildPrerequisiteGraph() error = %v", err)
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		var ran []invowkfile.CommandName
+		err = RunPrerequisites(ctx, graph, 1, func(_ context.Context, id PrerequisiteID) (types.ExitCode, error) {
+			ran = append(ran, id.Name)
+			cancel()
+			return 0, nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("RunPrerequisites() error = %v, want context.Canceled", err)
+		}
+		if len(ran) != 1 {
+			t.Errorf("ran = %v, want 1 prerequisite", ran)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidRunLockKey is the sentinel error wrapped by InvalidRunLockKeyError.
+	ErrInvalidRunLockKey = errors.New("invalid run lock key")
+
+	runLockKeyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+type (
+	// RunLockKey identifies the lock a command run takes: the lock scope and
+	// name and, for project-scoped locks, the directory of the invowkfile that
+	// declares the command. Format: 64 lowercase hex characters (a SHA-256
+	// digest), which keeps keys safe to use as file names.
+	RunLockKey string
+
+	// InvalidRunLockKeyError is returned when a RunLockKey is not a
+	// 64-character lowercase hex digest.
+	InvalidRunLockKeyError struct {
+		Value RunLockKey
+	}
+
+	//goplint:validate-all
+	//
+	// RunLock identifies the lock a command run takes and where its lock file
+	// lives.
+	RunLock struct {
+		Key RunLockKey
+		// ProjectDir is the directory of the invowkfile declaring a
+		// project-scoped lock. Its lock file lives beside the invowkfile, so
+		// every user working in the project contends for it. ProjectDir is
+		// empty for user-scoped locks, which live in the user's cache.
+		ProjectDir types.FilesystemPath
+	}
+
+	// RunLockHolder describes the process holding a run lock. The zero value
+	// means the holder is unknown, e.g. because it has not recorded itself yet.
+	RunLockHolder struct {
+		PID     int       `json:"pid"`
+		Started time.Time `json:"started"`
+	}
+
+	// heldRunLocksKey is the context key of the run locks held by the
+	// enclosing runs of a command.
+	heldRunLocksKey struct{}
+)
+
+// Error implements the error interface.
+func (e *InvalidRunLockKeyError) Error() string {
+	return fmt.Sprintf("invalid run lock key %q (must be 64 lowercase hex chars)", e.Value)
+}
+
+// Unwrap returns ErrInvalidRunLockKey for errors.Is compatibility.
+func (e *InvalidRunLockKeyError) Unwrap() error { return ErrInvalidRunLockKey }
+
+// Validate returns nil if the RunLockKey is a SHA-256 hex digest.
+//
+//goplint:nonzero
+func (k RunLockKey) Validate() error {
+	if !runLockKeyPattern.MatchString(string(k)) {
+		return &InvalidRunLockKeyError{Value: k}
+	}
+	return nil
+}
+
+// String returns the string representation of the RunLockKey.
+func (k RunLockKey) String() string { return string(k) }
+
+// Known reports whether the holder recorded its process ID.
+func (h RunLockHolder) Known() bool { return h.PID > 0 }
+
+// String names the holder for messages, e.g. "PID 4242 (started 2026-01-02
+// 15:04:05)", or "another process" when the holder is unknown.
+func (h RunLockHolder) String() string {
+	if !h.Known() {
+		return "another process"
+	}
+	return fmt.Sprintf("PID %d (started %s)", h.PID, h.Started.Local().Format(time.DateTime))
+}
+
+// NewRunLockKey derives the key of a command lock. projectDir is the directory
+// of the invowkfile that declares the command; it only contributes to
+// project-scoped locks, so user-scoped locks of the same name are shared
+// across projects.
+func NewRunLockKey(name invowkfile.LockName, scope invowkfile.LockScope, projectDir types.FilesystemPath) RunLockKey {
+	hasher := sha256.New()
+	writeField := func(value string) {
+		// Length-prefix every field so adjacent values cannot run together.
+		fmt.Fprintf(hasher, "%d:%s\n", len(value), value)
+	}
+	writeField(string(scope))
+	writeField(string(name))
+	if scope == invowkfile.LockScopeProject {
+		writeField(string(projectDir))
+	}
+	return RunLockKey(hex.EncodeToString(hasher.Sum(nil)))
+}
+
+// NewRunLock returns the lock of a command declaring a lock named name with
+// the given scope. projectDir is the directory of the invowkfile that
+// declares the command.
+func NewRunLock(name invowkfile.LockName, scope invowkfile.LockScope, projectDir types.FilesystemPath) RunLock {
+	lock := RunLock{Key: NewRunLockKey(name, scope, projectDir)}
+	if scope == invowkfile.LockScopeProject {
+		lock.ProjectDir = projectDir
+	}
+	return lock
+}
+
+// Validate returns nil if the key is valid and the project directory, when
+// set, is a valid path.
+func (l RunLock) Validate() error {
+	if err := l.Key.Validate(); err != nil {
+		return err
+	}
+	if l.ProjectDir == "" {
+		return nil
+	}
+	return l.ProjectDir.Validate()
+}
+
+// HoldsRunLock reports whether an enclosing run recorded in ctx holds the lock
+// identified by key.
+func HoldsRunLock(ctx context.Context, key RunLockKey) bool {
+	held, _ := ctx.Value(heldRunLocksKey{}).([]RunLockKey)
+	return slices.Contains(held, key)
+}
+
+// WithRunLock returns a derived context recording that the run holds the lock
+// identified by key, so nested runs of commands that declare the same lock
+// (steps and prerequisites) do not wait for themselves.
+func WithRunLock(ctx context.Context, key RunLockKey) context.Context {
+	held, _ := ctx.Value(heldRunLocksKey{}).([]RunLockKey)
+	return context.WithValue(ctx, heldRunLocksKey{}, append(slices.Clone(held), key))
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestNewRunLockKey(t *testing.T) {
+	t.Parallel()
+
+	key := NewRunLockKey("db", invowkfile.LockScopeProject, "/work/api")
+	if err := key.Validate(); err != nil {
+		t.Fatalf("NewRunLockKey() = %q, Validate() = %v", key, err)
+	}
+
+	variants := map[string]RunLockKey{
+		"name":    NewRunLockKey("cache", invowkfile.LockScopeProject, "/work/api"),
+		"scope":   NewRunLockKey("db", invowkfile.LockScopeUser, "/work/api"),
+		"project": NewRunLockKey("db", invowkfile.LockScopeProject, "/work/web"),
+	}
+	for field, variant := range variants {
+		if variant == key {
+			t.Errorf("changing %s did not change the key", field)
+		}
+	}
+
+	userKey := NewRunLockKey("db", invowkfile.LockScopeUser, "/work/api")
+	if other := NewRunLockKey("db", invowkfile.LockScopeUser, "/work/web"); other != userKey {
+		t.Errorf("user-scoped keys differ across projects: %q != %q", other, userKey)
+	}
+
+	if err := RunLockKey("../escape").Validate(); !errors.Is(err, ErrInvalidRunLockKey) {
+		t.Errorf("Validate() = %v, want ErrInvalidRunLockKey", err)
+	}
+}
+
+func TestNewRunLock(t *testing.T) {
+	t.Parallel()
+
+	project := NewRunLock("db", invowkfile.LockScopeProject, "/work/api")
+	if project.ProjectDir != "/work/api" || project.Key != NewRunLockKey("db", invowkfile.LockScopeProject, "/work/api") {
+		t.Errorf("project lock = %+v, want the project key beside /work/api", project)
+	}
+	if user := NewRunLock("db", invowkfile.LockScopeUser, "/work/api"); user.ProjectDir != "" {
+		t.Errorf("user lock ProjectDir = %q, want empty", user.ProjectDir)
+	}
+	if err := project.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	if err := (RunLock{Key: "../escape"}).Validate(); !errors.Is(err, ErrInvalidRunLockKey) {
+		t.Errorf("Validate() = %v, want ErrInvalidRunLockKey", err)
+	}
+}
+
+func TestWithRunLock(t *testing.T) {
+	t.Parallel()
+
+	db := NewRunLockKey("db", invowkfile.LockScopeUser, "")
+	cache := NewRunLockKey("cache", invowkfile.LockScopeUser, "")
+	ctx := WithRunLock(t.Context(), db)
+	if !HoldsRunLock(ctx, db) {
+		t.Error("HoldsRunLock(db) = false after WithRunLock(db)")
+	}
+	if HoldsRunLock(ctx, cache) || HoldsRunLock(t.Context(), db) {
+		t.Error("HoldsRunLock() = true for a lock the run does not hold")
+	}
+	if nested := WithRunLock(ctx, cache); !HoldsRunLock(nested, db) || !HoldsRunLock(nested, cache) {
+		t.Error("nested context lost a held lock")
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrStepFailed is the sentinel error wrapped by StepFailedError.
+	ErrStepFailed = errors.New("command step failed")
+
+	// ErrStepCycle is the sentinel error wrapped by StepCycleError.
+	ErrStepCycle = errors.New("command step cycle")
+)
+
+type (
+	// StepRunFunc executes one command step and reports its exit code.
+	// A non-nil error or a non-zero exit code marks the step as failed.
+	StepRunFunc func(ctx context.Context, index int, step *invowkfile.CommandStep) (types.ExitCode, error)
+
+	// StepOutcome records how a single command step finished.
+	StepOutcome struct {
+		// Index is the zero-based position of the step in the command's steps list.
+		Index int
+		// Step is the executed step definition.
+		Step *invowkfile.CommandStep
+		// ExitCode is the exit code reported by the step.
+		ExitCode types.ExitCode
+		// Err is the execution error reported by the step, if any.
+		Err error
+	}
+
+	// StepsResult aggregates the outcomes of a sequential step run.
+	StepsResult struct {
+		// Outcomes lists every step that ran, in execution order.
+		Outcomes []StepOutcome
+		// Failure is the step failure that stopped the run, or nil when every
+		// step either succeeded or was allowed to fail via continue_on_error.
+		Failure *StepFailedError
+	}
+
+	// StepFailedError is returned when a step without continue_on_error fails.
+	// It wraps both ErrStepFailed and the step's own error so callers can still
+	// match typed runtime and dependency errors with errors.As.
+	StepFailedError struct {
+		CommandName invowkfile.CommandName
+		Index       int
+		Label       string //goplint:ignore -- rendered step label for diagnostics.
+		ExitCode    types.ExitCode
+		Err         error
+	}
+
+	// StepCycleError is returned when a step command (directly or indirectly)
+	// invokes itself through its own steps.
+	StepCycleError struct {
+		Chain []invowkfile.CommandName
+	}
+
+	stepChainKey struct{}
+)
+
+// Failed returns true when the step reported an error or a non-zero exit code.
+func (o StepOutcome) Failed() bool {
+	return o.Err != nil || o.ExitCode != 0
+}
+
+// ExitCode returns the exit code of the failing step, or 0 when the run succeeded.
+func (r StepsResult) ExitCode() types.ExitCode {
+	if r.Failure == nil {
+		return 0
+	}
+	return r.Failure.ExitCode
+}
+
+// Error implements the error interface.
+func (e *StepFailedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("step %d (%s) of command '%s' failed: %v", e.Index+1, e.Label, e.CommandName, e.Err)
+	}
+	return fmt.Sprintf("step %d (%s) of command '%s' failed with exit code %d", e.Index+1, e.Label, e.CommandName, e.ExitCode)
+}
+
+// Unwrap returns ErrStepFailed and the underlying step error for errors.Is/As chains.
+func (e *StepFailedError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrStepFailed}
+	}
+	return []error{ErrStepFailed, e.Err}
+}
+
+// Error implements the error interface.
+func (e *StepCycleError) Error() string {
+	names := make([]string, len(e.Chain))
+	for i, name := range e.Chain {
+		names[i] = string(name)
+	}
+	return "command steps form a cycle: " + strings.Join(names, " -> ")
+}
+
+// Unwrap returns ErrStepCycle for errors.Is compatibility.
+func (e *StepCycleError) Unwrap() error { return ErrStepCycle }
+
+// EnterStepCommand records that the named step command is running and returns
+// a derived context carrying the active step chain. It returns a StepCycleError
+// when the command is already part of the chain, which happens when steps
+// reference each other in a loop.
+func EnterStepCommand(ctx context.Context, name invowkfile.CommandName) (context.Context, error) {
+	chain, _ := ctx.Value(stepChainKey{}).([]invowkfile.CommandName)
+	if slices.Contains(chain, name) {
+		return ctx, &StepCycleError{Chain: append(slices.Clone(chain), name)}
+	}
+	return context.WithValue(ctx, stepChainKey{}, append(slices.Clone(chain), name)), nil
+}
+
+// RunSteps executes the steps of a command in declaration order.
+//
+// Execution is fail-fast: the first failing step without ContinueOnError stops
+// the run and is reported in StepsResult.Failure. Failures of steps that set
+// ContinueOnError are recorded in Outcomes and execution moves on. Context
+// cancellation stops the run before the next step starts.
+func RunSteps(ctx context.Context, commandName invowkfile.CommandName, steps []invowkfile.CommandStep, run StepRunFunc) StepsResult {
+	var result StepsResult
+	for i := range steps {
+		step := &steps[i]
+		if err := ctx.Err(); err != nil {
+			result.Failure = newStepFailedError(commandName, i, step, 1, err)
+			return result
+		}
+
+		exitCode, err := run(ctx, i, step)
+		outcome := StepOutcome{Index: i, Step: step, ExitCode: exitCode, Err: err}
+		result.Outcomes = append(result.Outcomes, outcome)
+		if !outcome.Failed() || step.ContinueOnError {
+			continue
+		}
+
+		if exitCode == 0 {
+			exitCode = 1
+		}
+		result.Failure = newStepFailedError(commandName, i, step, exitCode, err)
+		return result
+	}
+	return result
+}
+
+func newStepFailedError(commandName invowkfile.CommandName, index int, step *invowkfile.CommandStep, exitCode types.ExitCode, err error) *StepFailedError {
+	return &StepFailedError{
+		CommandName: commandName,
+		Index:       index,
+		Label:       step.Label(),
+		ExitCode:    exitCode,
+		Err:         err,
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package execute
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestRunSteps(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name         string
+		steps        []invowkfile.CommandStep
+		results      map[int]types.ExitCode
+		errs         map[int]error
+		wantRan      []int
+		wantExitCode types.ExitCode
+		wantErr      error
+	}{
+		{
+			name:    "all steps succeed",
+			steps:   []invowkfile.CommandStep{{Cmd: "a"}, {Cmd: "b"}, {Cmd: "c"}},
+			wantRan: []int{0, 1, 2},
+		},
+		{
+			name:         "fail fast on exit code",
+			steps:        []invowkfile.CommandStep{{Cmd: "a"}, {Cmd: "b"}, {Cmd: "c"}},
+			results:      map[int]types.ExitCode{1: 3},
+			wantRan:      []int{0, 1},
+			wantExitCode: 3,
+			wantErr:      ErrStepFailed,
+		},
+		{
+			name:         "fail fast on error",
+			steps:        []invowkfile.CommandStep{{Cmd: "a"}, {Cmd: "b"}},
+			errs:         map[int]error{0: errBoom},
+			wantRan:      []int{0},
+			wantExitCode: 1,
+			wantErr:      errBoom,
+		},
+		{
+			name:    "continue on error",
+			steps:   []invowkfile.CommandStep{{Cmd: "a", ContinueOnError: true}, {Cmd: "b"}},
+			results: map[int]types.ExitCode{0: 2},
+			wantRan: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ran []int
+			result := RunSteps(t.Context(), "release", tt.steps, func(_ context.Context, index int, _ *invowkfile.CommandStep) (types.ExitCode, error) {
+				ran = append(ran, index)
+				return tt.results[index], tt.errs[index]
+			})
+
+			if len(ran) != len(tt.wantRan) {
+				t.Fatalf("ran steps %v, want %v", ran, tt.wantRan)
+			}
+			for i := range ran {
+				if ran[i] != tt.wantRan[i] {
+					t.Fatalf("ran steps %v, want %v", ran, tt.wantRan)
+				}
+			}
+			if got := result.ExitCode(); got != tt.wantExitCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantExitCode)
+			}
+			if tt.wantErr == nil {
+				if result.Failure != nil {
+					t.Fatalf("Failure = %v, want nil", result.Failure)
+				}
+				return
+			}
+			if !errors.Is(result.Failure, tt.wantErr) {
+				t.Fatalf("Failure = %v, want wrapped %v", result.Failure, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunStepsStopsOnCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	steps := []invowkfile.CommandStep{{Cmd: "a"}, {Cmd: "b"}}
+	result := RunSteps(ctx, "release", steps, func(context.Context, int, *invowkfile.CommandStep) (types.ExitCode, error) {
+		cancel()
+		return 0, nil
+	})
+
+	if len(result.Outcomes) != 1 {
+		t.Fatalf("Outcomes = %d, want 1", len(result.Outcomes))
+	}
+	if !errors.Is(result.Failure, context.Canceled) {
+		t.Fatalf("Failure = %v, want context.Canceled", result.Failure)
+	}
+	if result.Failure.Index != 1 {
+		t.Fatalf("Failure.Index = %d, want 1", result.Failure.Index)
+	}
+}
+
+func TestEnterStepCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := EnterStepCommand(t.Context(), "release")
+	if err != nil {
+		t.Fatalf("EnterStepCommand(release) error = %v", err)
+	}
+	ctx, err = EnterStepCommand(ctx, "build")
+	if err != nil {
+		t.Fatalf("EnterStepCommand(build) error = %v", err)
+	}
+	if _, err := EnterStepCommand(ctx, "test"); err != nil {
+		t.Fatalf("EnterStepCommand(test) error = %v", err)
+	}
+
+	_, err = EnterStepCommand(ctx, "release")
+	if !errors.Is(err, ErrStepCycle) {
+		t.Fatalf("EnterStepCommand(release) error = %v, want ErrStepCycle", err)
+	}
+	if want := "command steps form a cycle: release -> build -> release"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"sync"
 
 	"github.com/invowk/invowk/internal/llm"
+	"github.com/invowk/invowk/internal/runtime"
+	"github.com/invowk/invowk/pkg/invowkfile"
 )
 
 const (
@@ -75,6 +77,7 @@ func (c *LLMChecker) Check(ctx context.Context, sc *ScanContext) ([]Finding, err
 	}
 
 	batches := batchScripts(prepared)
+	secrets := scanSecretRedactor(ctx, sc)
 
 	type batchResult struct {
 		findings []Finding
@@ -107,7 +110,7 @@ func (c *LLMChecker) Check(ctx context.Context, sc *ScanContext) ([]Finding, err
 				return
 			}
 
-			findings, err := c.analyzeBatch(ctx, batchRefs)
+			findings, err := c.analyzeBatch(ctx, batchRefs, secrets)
 			results[idx] = batchResult{findings: findings, err: err}
 		}(i, batch)
 	}
@@ -136,8 +139,9 @@ func (c *LLMChecker) Check(ctx context.Context, sc *ScanContext) ([]Finding, err
 }
 
 // analyzeBatch sends a single batch of scripts to the LLM and parses findings.
-func (c *LLMChecker) analyzeBatch(ctx context.Context, batch []ScriptRef) ([]Finding, error) {
-	userPrompt := buildUserPrompt(batch)
+// Known secret values are masked before the prompt leaves the process.
+func (c *LLMChecker) analyzeBatch(ctx context.Context, batch []ScriptRef, secrets *runtime.SecretRedactor) ([]Finding, error) {
+	userPrompt := secrets.Redact(buildUserPrompt(batch))
 
 	raw, err := c.completer.Complete(ctx, systemPrompt, userPrompt)
 	if err != nil {
@@ -152,6 +156,52 @@ func (c *LLMChecker) analyzeBatch(ctx context.Context, batch []ScriptRef) ([]Fin
 	return convertBatchFindings(parsed, batch)
 }
 
+// scanSecretRedactor collects the values of the env secrets declared by the
+// scanned invowkfiles and modules so they can be masked in LLM prompts. Only
+// the side-effect-free file and env providers are resolved: auditing must
+// never run secret commands or keyring helpers. Unresolvable secrets are
+// skipped, since there is no value to leak.
+func scanSecretRedactor(ctx context.Context, sc *ScanContext) *runtime.SecretRedactor {
+	var files []*invowkfile.Invowkfile
+	for _, file := range sc.Invowkfiles() {
+		files = append(files, file.Invowkfile)
+	}
+	for _, module := range sc.Modules() {
+		files = append(files, module.Invowkfile)
+	}
+
+	secrets := runtime.NewSecretRedactor()
+	resolve := func(inv *invowkfile.Invowkfile, env *invowkfile.EnvConfig) {
+		for _, source := range env.GetSecrets() {
+			var provider runtime.SecretProvider
+			switch source.Provider {
+			case invowkfile.SecretProviderFile:
+				provider = runtime.FileSecretProvider{}
+			case invowkfile.SecretProviderEnv:
+				provider = runtime.EnvSecretProvider{}
+			default:
+				continue
+			}
+			if value, err := provider.Resolve(ctx, source, inv.GetScriptBasePath()); err == nil {
+				secrets.Add(value)
+			}
+		}
+	}
+	for _, inv := range files {
+		if inv == nil {
+			continue
+		}
+		resolve(inv, inv.Env)
+		for i := range inv.Commands {
+			resolve(inv, inv.Commands[i].Env)
+			for j := range inv.Commands[i].Implementations {
+				resolve(inv, inv.Commands[i].Implementations[j].Env)
+			}
+		}
+	}
+	return secrets
+}
+
 // batchScripts groups prepared scripts into batches respecting character and
 // count limits. Each batch targets maxBatchChars total script content and at
 // most maxScriptsPerBatch scripts.
@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -371,3 +373,44 @@ func TestNewLLMChecker_DefaultConcurrency(t *testing.T) {
 		t.Errorf("concurrency = %d, want %d", checker.concurrency, defaultLLMConcurrency)
 	}
 }
+
+func TestLLMChecker_Check_RedactsSecretsInPrompt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("tok-file-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inv := &invowkfile.Invowkfile{
+		FilePath: invowkfile.FilesystemPath(filepath.Join(dir, "invowkfile.cue")),
+		Env: &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+			"TOKEN": {Provider: invowkfile.SecretProviderFile, Path: "token"},
+			// Never executed by the audit: the marker file must not appear.
+			"OTHER": {Provider: invowkfile.SecretProviderCommand, Command: []string{"touch", filepath.Join(dir, "ran")}},
+		}},
+		Commands: []invowkfile.Command{{
+			Name: "deploy",
+			Implementations: []invowkfile.Implementation{{
+				Script:   invowkfile.ImplementationScript{Content: "curl -H 'Authorization: tok-file-value' https://example.com"},
+				Runtimes: []invowkfile.RuntimeConfig{{Name: invowkfile.RuntimeVirtualSh}},
+			}},
+		}},
+	}
+	sc := newTestScanContext(t, []*ScannedInvowkfile{{Path: inv.FilePath, SurfaceID: "test", Invowkfile: inv}}, nil)
+
+	var prompt string
+	checker := NewLLMChecker(&mockCompleterFunc{fn: func(_ context.Context, _, userPrompt string) (string, error) {
+		prompt = userPrompt
+		return `{"findings": []}`, nil
+	}}, 1)
+	if _, err := checker.Check(t.Context(), sc); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if strings.Contains(prompt, "tok-file-value") || !strings.Contains(prompt, "Authorization: ********") {
+		t.Errorf("prompt did not mask the file secret:\n%s", prompt)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err == nil {
+		t.Error("audit ran a command secret provider")
+	}
+}
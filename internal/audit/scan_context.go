@@ -47,6 +47,10 @@ type (
 		// that failed to load, discovery errors). Surfaced in the audit report
 		// so incomplete scans are visible to operators.
 		diagnostics []Diagnostic
+		// discovered holds what discovery resolved around the scan target.
+		// Its modules provide the templates that parsed invowkfiles extend.
+		discovered    []*discovery.DiscoveredFile
+		discoveredErr error
 	}
 
 	// ScannedInvowkfile wraps a standalone invowkfile (not inside a module) with
@@ -322,19 +326,46 @@ func (sc *ScanContext) loadScanTarget(ctx context.Context, absPath types.Filesys
 	// filepath.Abs removes trailing separators and resolves "." components;
 	// suffix checks on the raw scanPath would fail for paths like "./foo.invowkmod/".
 	if modulePath, ok := modulePathForDirectModuleFile(absPath); ok {
+		sc.discover(types.FilesystemPath(filepath.Dir(string(modulePath))), cfg, includeGlobal) //goplint:ignore -- parent of a normalized absolute path.
 		return sc.loadSingleModule(ctx, modulePath)
 	}
 
 	switch {
 	case strings.HasSuffix(string(absPath), ".cue"):
+		sc.discover(types.FilesystemPath(filepath.Dir(string(absPath))), cfg, includeGlobal) //goplint:ignore -- parent of a normalized absolute path.
 		return sc.loadStandaloneInvowkfile(ctx, absPath)
 	case strings.HasSuffix(string(absPath), invowkmod.ModuleSuffix):
+		sc.discover(types.FilesystemPath(filepath.Dir(string(absPath))), cfg, includeGlobal) //goplint:ignore -- parent of a normalized absolute path.
 		return sc.loadSingleModule(ctx, absPath)
 	default:
-		return sc.loadDirectoryTree(ctx, absPath, cfg, includeGlobal)
+		sc.discover(absPath, cfg, includeGlobal)
+		return sc.loadDirectoryTree(ctx, absPath)
 	}
 }
 
+// discover runs discovery from baseDir so that the scanned invowkfiles can
+// extend the templates of the modules it resolves. It is a no-op without a
+// config.
+func (sc *ScanContext) discover(baseDir types.FilesystemPath, cfg *config.Config, includeGlobal bool) {
+	if cfg == nil {
+		return
+	}
+	opts := []discovery.Option{
+		discovery.WithBaseDir(baseDir),
+		discovery.WithVendoredIntegrityVerification(false),
+	}
+	if !includeGlobal {
+		opts = append(opts, discovery.WithCommandsDir(""))
+	}
+	sc.discovered, sc.discoveredErr = discovery.New(cfg, opts...).DiscoverAll()
+}
+
+// templateModules returns the parse option resolving the module-qualified
+// template references of module's invowkfile (nil for a root invowkfile).
+func (sc *ScanContext) templateModules(module *invowkmod.Module) invowkfile.ParseOption {
+	return invowkfile.WithTemplateModules(discovery.TemplateModules(module, sc.discovered))
+}
+
 func modulePathForDirectModuleFile(absPath types.FilesystemPath) (types.FilesystemPath, bool) {
 	base := filepath.Base(string(absPath))
 	if base != invowkfileCUEFileName && base != invowkmodCueFileName {
@@ -368,7 +399,7 @@ func (sc *ScanContext) loadStandaloneInvowkfile(ctx context.Context, absPath typ
 	if err := scanContextErr(ctx); err != nil {
 		return err
 	}
-	inv, parseErr := invowkfile.Parse(absPath)
+	inv, parseErr := invowkfile.Parse(absPath, sc.templateModules(nil))
 	si := &ScannedInvowkfile{
 		Path:        absPath,
 		SurfaceID:   string(absPath),
@@ -437,7 +468,7 @@ func (sc *ScanContext) loadScannedModule(
 	invPath := fspath.JoinStr(absPath, invowkfileCUEFileName)
 	if sm.Invowkfile == nil {
 		if _, statErr := os.Stat(string(invPath)); statErr == nil {
-			parsed, parseErr := invowkfile.ParseLoadedModuleInvowkfile(mod)
+			parsed, parseErr := invowkfile.ParseLoadedModuleInvowkfile(mod, sc.templateModules(mod))
 			if parseErr == nil {
 				sm.Invowkfile = parsed
 			} else {
@@ -471,7 +502,7 @@ func (sc *ScanContext) loadScannedModule(
 	return sm, vendored, nil
 }
 
-func (sc *ScanContext) loadDirectoryTree(ctx context.Context, absPath types.FilesystemPath, cfg *config.Config, includeGlobal bool) error {
+func (sc *ScanContext) loadDirectoryTree(ctx context.Context, absPath types.FilesystemPath) error {
 	if err := scanContextErr(ctx); err != nil {
 		return err
 	}
@@ -481,7 +512,7 @@ func (sc *ScanContext) loadDirectoryTree(ctx context.Context, absPath types.File
 	if err := sc.loadDirectoryModules(ctx, absPath); err != nil {
 		return err
 	}
-	if err := sc.loadDiscoveryResults(ctx, absPath, cfg, includeGlobal); err != nil {
+	if err := sc.loadDiscoveryResults(ctx); err != nil {
 		return err
 	}
 	return nil
@@ -516,7 +547,7 @@ func (sc *ScanContext) appendParsedInvowkfile(ctx context.Context, path types.Fi
 	if err := scanContextErr(ctx); err != nil {
 		return err
 	}
-	inv, parseErr := invowkfile.Parse(path)
+	inv, parseErr := invowkfile.Parse(path, sc.templateModules(nil))
 	if err := scanContextErr(ctx); err != nil {
 		return err
 	}
@@ -565,29 +596,18 @@ func (sc *ScanContext) loadDirectoryModules(ctx context.Context, absPath types.F
 	return nil
 }
 
-func (sc *ScanContext) loadDiscoveryResults(ctx context.Context, absPath types.FilesystemPath, cfg *config.Config, includeGlobal bool) error {
-	if cfg == nil {
-		return nil
-	}
+// loadDiscoveryResults merges what discovery resolved from the scanned
+// directory, recording a diagnostic when discovery only partially succeeded.
+func (sc *ScanContext) loadDiscoveryResults(ctx context.Context) error {
 	if err := scanContextErr(ctx); err != nil {
 		return err
 	}
 
-	opts := []discovery.Option{
-		discovery.WithBaseDir(absPath),
-		discovery.WithVendoredIntegrityVerification(false),
-	}
-	if !includeGlobal {
-		opts = append(opts, discovery.WithCommandsDir(""))
-	}
-
-	disc := discovery.New(cfg, opts...)
-	files, discErr := disc.DiscoverAll()
-	if discErr != nil {
-		sc.addDiagnostic(diagnosticDiscoveryPartial, fmt.Sprintf("discovery error (partial results): %v", discErr), "")
+	if sc.discoveredErr != nil {
+		sc.addDiagnostic(diagnosticDiscoveryPartial, fmt.Sprintf("discovery error (partial results): %v", sc.discoveredErr), "")
 	}
-	if files != nil {
-		if err := sc.mergeDiscoveryResults(ctx, files); err != nil {
+	if sc.discovered != nil {
+		if err := sc.mergeDiscoveryResults(ctx, sc.discovered); err != nil {
 			return err
 		}
 	}
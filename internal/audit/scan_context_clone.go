@@ -4,6 +4,7 @@ package audit
 
 import (
 	"maps"
+	"slices"
 
 	"github.com/invowk/invowk/pkg/invowkfile"
 	"github.com/invowk/invowk/pkg/invowkmod"
@@ -114,6 +115,7 @@ func clonePlatformConfigs(platforms []invowkfile.PlatformConfig) []invowkfile.Pl
 	cloned := make([]invowkfile.PlatformConfig, len(platforms))
 	for i := range platforms {
 		cloned[i] = platforms[i]
+		cloned[i].Arch = slices.Clone(platforms[i].Arch)
 		if platforms[i].Virtual != nil {
 			virtual := *platforms[i].Virtual
 			if virtual.Filesystem != nil {
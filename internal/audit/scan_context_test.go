@@ -498,6 +498,44 @@ func TestScanContextModulesReturnsInvowkfileDeepCopies(t *testing.T) {
 	}
 }
 
+func TestBuildScanContextStandaloneFileExtendsIncludedModuleTemplates(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	includeDir := filepath.Join(t.TempDir(), "io.example.tools.invowkmod")
+	createAuditTestModule(t, includeDir, "io.example.tools", "noop")
+	if err := os.WriteFile(filepath.Join(includeDir, "invowkfile.cue"), []byte(`
+templates: container: {
+	runtimes: [{name: "container", image: "golang:1.26"}]
+	platforms: [{name: "linux"}]
+}
+cmds: [{name: "noop", implementations: [{script: {content: "true"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]
+`), 0o644); err != nil {
+		t.Fatalf("WriteFile(module invowkfile) error = %v", err)
+	}
+	invowkfilePath := filepath.Join(root, "invowkfile.cue")
+	if err := os.WriteFile(invowkfilePath, []byte(`cmds: [{name: "build", implementations: [{extends: "@io.example.tools container", script: {content: "go build"}}]}]`), 0o644); err != nil {
+		t.Fatalf("WriteFile(invowkfile) error = %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Includes = []config.IncludeEntry{{Path: config.ModuleIncludePath(includeDir)}}
+	sc, err := BuildScanContext(t.Context(), types.FilesystemPath(invowkfilePath), cfg, false)
+	if err != nil {
+		t.Fatalf("BuildScanContext() error = %v", err)
+	}
+	if len(sc.invowkfiles) != 1 {
+		t.Fatalf("sc.invowkfiles = %d, want 1", len(sc.invowkfiles))
+	}
+	if parseErr := sc.invowkfiles[0].ParseErr; parseErr != nil {
+		t.Fatalf("ParseErr = %v, want the included module template to resolve", parseErr)
+	}
+	impl := sc.invowkfiles[0].Invowkfile.Commands[0].Implementations[0]
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Image != "golang:1.26" {
+		t.Fatalf("runtimes = %+v, want the included module template runtime", impl.Runtimes)
+	}
+}
+
 func TestBuildScanContextIncludedModuleKeepsLockAndVendoredArtifacts(t *testing.T) {
 	t.Parallel()
 
@@ -476,10 +476,17 @@ func generateCUE(cfg *Config, forceLLMAPIBlock bool) string {
 	writeConfigCUEUI(&sb, cfg.UI)
 	writeConfigCUELLM(&sb, cfg.LLM, forceLLMAPIBlock)
 	writeConfigCUEContainer(&sb, cfg.Container)
+	writeConfigCUEExecution(&sb, cfg.Execution)
 
 	return sb.String()
 }
 
+func writeConfigCUEExecution(sb *strings.Builder, execution ExecutionConfig) {
+	sb.WriteString("\nexecution: {\n")
+	fmt.Fprintf(sb, "\tmax_parallel: %d\n", execution.MaxParallel)
+	sb.WriteString("}\n")
+}
+
 func writeConfigCUEIncludes(sb *strings.Builder, includes []IncludeEntry) {
 	sb.WriteString("\nincludes: [\n")
 	for _, entry := range includes {
@@ -108,6 +108,7 @@ func TestGenerateCUE_Roundtrip(t *testing.T) {
 				InheritIncludes: false,
 			},
 		},
+		Execution: ExecutionConfig{MaxParallel: 4},
 	}
 
 	cueContent := GenerateCUE(cfg)
@@ -145,6 +146,9 @@ func TestGenerateCUE_Roundtrip(t *testing.T) {
 	if loaded.UI.Interactive != cfg.UI.Interactive {
 		t.Errorf("roundtrip UI.Interactive = %v, want %v", loaded.UI.Interactive, cfg.UI.Interactive)
 	}
+	if loaded.Execution.MaxParallel != cfg.Execution.MaxParallel {
+		t.Errorf("roundtrip Execution.MaxParallel = %d, want %d", loaded.Execution.MaxParallel, cfg.Execution.MaxParallel)
+	}
 	if loaded.Container.AutoProvision.Enabled != cfg.Container.AutoProvision.Enabled {
 		t.Errorf("roundtrip AutoProvision.Enabled = %v, want %v", loaded.Container.AutoProvision.Enabled, cfg.Container.AutoProvision.Enabled)
 	}
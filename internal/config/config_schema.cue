@@ -9,7 +9,7 @@ import "strings"
 #ContainerEngineType: "podman" | "docker"
 
 // ConfigRuntimeType defines valid default runtime types
-#ConfigRuntimeType: "native" | "virtual-sh" | "virtual-lua" | "container"
+#ConfigRuntimeType: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark" | "container" | "remote-ssh"
 
 // ColorSchemeType defines valid color scheme types
 #ColorSchemeType: "auto" | "dark" | "light"
@@ -35,7 +35,7 @@ import "strings"
 	includes: *([]) | [...#IncludeEntry]
 
 	// default_runtime sets the global default runtime mode
-	// Valid values: "native", "virtual-sh", "virtual-lua", "container"
+	// Valid values: "native", "virtual-sh", "virtual-lua", "virtual-starlark", "container", "remote-ssh"
 	default_runtime: *"native" | #ConfigRuntimeType
 
 	// virtual configures the virtual runtime family.
@@ -49,6 +49,9 @@ import "strings"
 
 	// llm configures common LLM defaults and the default backend for LLM-aware commands
 	llm: *#LLMDefaultsConfig | #LLMConfig
+
+	// execution configures how invowk schedules command executions
+	execution: *#ExecutionConfig | #ExecutionConfig
 })
 
 // IncludeEntry specifies a module to include in command discovery.
@@ -110,6 +113,14 @@ import "strings"
 	enabled: *true | bool
 })
 
+// ExecutionConfig configures how invowk schedules command executions.
+#ExecutionConfig: close({
+	// max_parallel limits how many independent prerequisite commands
+	// (depends_on.cmds entries with run: true) execute at the same time.
+	// Zero means use the number of available CPUs.
+	max_parallel: *0 | (int & >=0)
+})
+
 // UIConfig configures the user interface
 #UIConfig: close({
 	// color_scheme sets the color scheme
@@ -81,6 +81,17 @@ func TestVirtualUtilitiesConfigSchemaSync(t *testing.T) {
 	schematest.AssertFieldsSync(t, "VirtualUtilitiesConfig", cueFields, goFields)
 }
 
+// TestExecutionConfigSchemaSync verifies ExecutionConfig Go struct matches #ExecutionConfig CUE definition.
+func TestExecutionConfigSchemaSync(t *testing.T) {
+	t.Parallel()
+
+	schema, _ := getCUESchema(t)
+	cueFields := schematest.ExtractCUEFields(t, schematest.LookupDefinition(t, schema, "#ExecutionConfig"))
+	goFields := schematest.ExtractGoJSONTags(t, reflect.TypeFor[ExecutionConfig]())
+
+	schematest.AssertFieldsSync(t, "ExecutionConfig", cueFields, goFields)
+}
+
 // TestUIConfigSchemaSync verifies UIConfig Go struct matches #UIConfig CUE definition.
 func TestUIConfigSchemaSync(t *testing.T) {
 	t.Parallel()
@@ -826,6 +837,7 @@ func TestBehavioralSync_ConfigRuntimeMode(t *testing.T) {
 			{"native", true, true, ""},
 			{"virtual-sh", true, true, ""},
 			{"virtual-lua", true, true, ""},
+			{"virtual-starlark", true, true, ""},
 			{"virtual", false, false, ""},
 			{"container", true, true, ""},
 			{"invalid", false, false, ""},
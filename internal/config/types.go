@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -28,8 +29,12 @@ const (
 	RuntimeVirtualSh RuntimeMode = types.RuntimeVirtualSh
 	// RuntimeVirtualLua runs commands in the embedded Lua interpreter.
 	RuntimeVirtualLua RuntimeMode = types.RuntimeVirtualLua
+	// RuntimeVirtualStarlark runs commands in the embedded Starlark interpreter.
+	RuntimeVirtualStarlark RuntimeMode = types.RuntimeVirtualStarlark
 	// RuntimeContainer runs commands inside a container (Docker/Podman).
 	RuntimeContainer RuntimeMode = types.RuntimeContainer
+	// RuntimeRemoteSSH runs commands on a remote host over SSH.
+	RuntimeRemoteSSH RuntimeMode = types.RuntimeRemoteSSH
 
 	// ColorSchemeAuto detects the terminal color scheme automatically.
 	ColorSchemeAuto ColorScheme = "auto"
@@ -78,6 +83,8 @@ var (
 	ErrInvalidLLMTimeout = errors.New("invalid LLM timeout")
 	// ErrInvalidLLMConcurrency is returned when an LLM concurrency value is invalid.
 	ErrInvalidLLMConcurrency = errors.New("invalid LLM concurrency")
+	// ErrInvalidMaxParallel is returned when an execution max_parallel value is invalid.
+	ErrInvalidMaxParallel = errors.New("invalid max parallel")
 	// ErrInvalidLLMAPIConfig is the sentinel error wrapped by InvalidLLMAPIConfigError.
 	ErrInvalidLLMAPIConfig = errors.New("invalid LLM API config")
 	// ErrInvalidLLMConfig is the sentinel error wrapped by InvalidLLMConfigError.
@@ -186,6 +193,15 @@ type (
 		Value LLMConcurrency
 	}
 
+	// MaxParallel is the max number of command executions invowk runs concurrently.
+	// Zero means use the number of available CPUs.
+	MaxParallel int
+
+	// InvalidMaxParallelError is returned when a MaxParallel is negative.
+	InvalidMaxParallelError struct {
+		Value MaxParallel
+	}
+
 	// ModuleIncludePath represents an absolute filesystem path to a *.invowkmod directory.
 	// A valid path must be non-empty, absolute, and end with .invowkmod.
 	ModuleIncludePath string
@@ -303,6 +319,16 @@ type (
 		LLM LLMConfig `json:"llm,omitzero" mapstructure:"llm"`
 		// Container configures container runtime behavior
 		Container ContainerConfig `json:"container" mapstructure:"container"`
+		// Execution configures how command executions are scheduled.
+		Execution ExecutionConfig `json:"execution" mapstructure:"execution"`
+	}
+
+	//goplint:validate-all
+	//
+	// ExecutionConfig configures how invowk schedules command executions.
+	ExecutionConfig struct {
+		// MaxParallel limits concurrently running prerequisite commands.
+		MaxParallel MaxParallel `json:"max_parallel" mapstructure:"max_parallel"`
 	}
 
 	//goplint:validate-all
@@ -637,6 +663,39 @@ func (e *InvalidLLMConcurrencyError) Error() string {
 // Unwrap returns ErrInvalidLLMConcurrency for errors.Is() compatibility.
 func (e *InvalidLLMConcurrencyError) Unwrap() error { return ErrInvalidLLMConcurrency }
 
+// String returns the string representation of the MaxParallel.
+func (m MaxParallel) String() string { return fmt.Sprintf("%d", m) }
+
+// Validate returns an error if MaxParallel is negative.
+func (m MaxParallel) Validate() error {
+	if m < 0 {
+		return &InvalidMaxParallelError{Value: m}
+	}
+	return nil
+}
+
+// Limit returns the effective concurrency limit: the configured value, or the
+// number of available CPUs when MaxParallel is zero.
+func (m MaxParallel) Limit() int {
+	if m <= 0 {
+		return runtime.NumCPU()
+	}
+	return int(m)
+}
+
+// Error implements the error interface for InvalidMaxParallelError.
+func (e *InvalidMaxParallelError) Error() string {
+	return fmt.Sprintf("invalid max parallel %d: must be zero or greater", e.Value)
+}
+
+// Unwrap returns ErrInvalidMaxParallel for errors.Is() compatibility.
+func (e *InvalidMaxParallelError) Unwrap() error { return ErrInvalidMaxParallel }
+
+// Validate returns an error if the execution config has invalid fields.
+func (c ExecutionConfig) Validate() error {
+	return c.MaxParallel.Validate()
+}
+
 // HasConfig reports whether any API backend setting is configured.
 func (c LLMAPIConfig) HasConfig() bool {
 	return c.BaseURL != "" || c.Model != "" || c.CredentialEnv != ""
@@ -828,6 +887,9 @@ func (c Config) Validate() error {
 	if err := c.Container.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.Execution.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if len(errs) > 0 {
 		return &InvalidConfigError{FieldErrors: errs}
 	}
@@ -288,6 +288,31 @@ func TestLLMConcurrencyMutationErrorPayload(t *testing.T) {
 	}
 }
 
+func TestMaxParallelMutationErrorPayload(t *testing.T) {
+	t.Parallel()
+
+	err := MaxParallel(-1).Validate()
+	if !errors.Is(err, ErrInvalidMaxParallel) {
+		t.Fatalf("MaxParallel(-1).Validate() error = %v, want ErrInvalidMaxParallel", err)
+	}
+	var maxErr *InvalidMaxParallelError
+	if !errors.As(err, &maxErr) || maxErr.Value != -1 {
+		t.Fatalf("MaxParallel(-1).Validate() error = %#v, want value -1", err)
+	}
+	if got, want := err.Error(), "invalid max parallel -1: must be zero or greater"; got != want {
+		t.Fatalf("InvalidMaxParallelError.Error() = %q, want %q", got, want)
+	}
+	if cfgErr := (Config{Execution: ExecutionConfig{MaxParallel: -1}}).Validate(); !errors.Is(cfgErr, ErrInvalidMaxParallel) {
+		t.Fatalf("Config.Validate() error = %v, want ErrInvalidMaxParallel", cfgErr)
+	}
+	if got := MaxParallel(3).Limit(); got != 3 {
+		t.Errorf("MaxParallel(3).Limit() = %d, want 3", got)
+	}
+	if got := MaxParallel(0).Limit(); got < 1 {
+		t.Errorf("MaxParallel(0).Limit() = %d, want at least 1", got)
+	}
+}
+
 func TestLLMAPIConfigMutationPresence(t *testing.T) {
 	t.Parallel()
 
@@ -59,7 +59,9 @@ func TestConfigRuntimeMode_Validate(t *testing.T) {
 		{RuntimeNative, true, false},
 		{RuntimeVirtualSh, true, false},
 		{RuntimeVirtualLua, true, false},
+		{RuntimeVirtualStarlark, true, false},
 		{RuntimeContainer, true, false},
+		{RuntimeRemoteSSH, true, false},
 		{"virtual", false, true},
 		{"", false, true},
 		{"invalid", false, true},
@@ -56,6 +56,8 @@ var (
 	ErrInvalidContainerName = containerargs.ErrInvalidContainerName
 	// ErrInvalidHostMapping is the sentinel error wrapped by InvalidHostMappingError.
 	ErrInvalidHostMapping = errors.New("invalid host mapping")
+	// ErrInvalidImagePlatform is the sentinel error wrapped by InvalidImagePlatformError.
+	ErrInvalidImagePlatform = errors.New("invalid image platform")
 	// ErrInvalidBuildOptions is the sentinel error wrapped by InvalidBuildOptionsError.
 	ErrInvalidBuildOptions = errors.New("invalid build options")
 	// ErrInvalidRunOptions is the sentinel error wrapped by InvalidRunOptionsError.
@@ -89,6 +91,11 @@ type (
 	// HostMapping is a host-to-IP mapping entry for --add-host (e.g., "host.docker.internal:host-gateway").
 	HostMapping string
 
+	// ImagePlatform selects the image variant for --platform in "os/arch"
+	// or "os/arch/variant" form (e.g., "linux/arm64").
+	// The zero value ("") means "engine default".
+	ImagePlatform string
+
 	// InvalidEngineTypeError is returned when an EngineType value is not recognized.
 	// It wraps ErrInvalidEngineType for errors.Is() compatibility.
 	InvalidEngineTypeError struct {
@@ -116,6 +123,12 @@ type (
 		Value HostMapping
 	}
 
+	// InvalidImagePlatformError is returned when an ImagePlatform value is invalid.
+	// DDD Value Type error struct — wraps ErrInvalidImagePlatform for errors.Is().
+	InvalidImagePlatformError struct {
+		Value ImagePlatform
+	}
+
 	// InvalidBuildOptionsError is returned when BuildOptions has one or more invalid fields.
 	// It wraps ErrInvalidBuildOptions for errors.Is() compatibility.
 	InvalidBuildOptionsError struct {
@@ -212,6 +225,8 @@ type (
 		Tag ImageTag
 		// BuildArgs are build-time variables
 		BuildArgs map[string]string
+		// Platform is the target platform of the built image (empty means engine default)
+		Platform ImagePlatform
 		// NoCache disables the build cache
 		NoCache bool
 		// Stdout is where to write build output
@@ -252,6 +267,8 @@ type (
 		TTY bool
 		// ExtraHosts are additional host-to-IP mappings (e.g., "host.docker.internal:host-gateway")
 		ExtraHosts []HostMapping
+		// Platform selects the image variant to run (empty means engine default)
+		Platform ImagePlatform
 	}
 
 	//goplint:validate-all
@@ -276,6 +293,8 @@ type (
 		Name ContainerName
 		// ExtraHosts are additional host-to-IP mappings.
 		ExtraHosts []HostMapping
+		// Platform selects the image variant to create the container from (empty means engine default).
+		Platform ImagePlatform
 	}
 
 	// CreateResult contains the result of creating a container.
@@ -434,6 +453,41 @@ func (e *InvalidHostMappingError) Error() string {
 // Unwrap returns ErrInvalidHostMapping for errors.Is() compatibility.
 func (e *InvalidHostMappingError) Unwrap() error { return ErrInvalidHostMapping }
 
+// ImagePlatformForArch returns the Linux image platform for a CPU architecture
+// spelled like Go's GOARCH, which matches the OCI architecture names.
+func ImagePlatformForArch(arch string) ImagePlatform {
+	return ImagePlatform("linux/" + arch)
+}
+
+// String returns the string representation of the ImagePlatform.
+func (p ImagePlatform) String() string { return string(p) }
+
+// Validate returns an error if the ImagePlatform is invalid.
+// A valid ImagePlatform has two or three non-empty "/"-separated parts
+// without whitespace or control characters.
+//
+//goplint:nonzero
+func (p ImagePlatform) Validate() error {
+	parts := strings.Split(string(p), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return &InvalidImagePlatformError{Value: p}
+	}
+	for _, part := range parts {
+		if part == "" || strings.IndexFunc(part, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+			return &InvalidImagePlatformError{Value: p}
+		}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidImagePlatformError.
+func (e *InvalidImagePlatformError) Error() string {
+	return fmt.Sprintf("invalid image platform %q: must be os/arch or os/arch/variant", e.Value)
+}
+
+// Unwrap returns ErrInvalidImagePlatform for errors.Is() compatibility.
+func (e *InvalidImagePlatformError) Unwrap() error { return ErrInvalidImagePlatform }
+
 // Error implements the error interface for InvalidBuildOptionsError.
 func (e *InvalidBuildOptionsError) Error() string {
 	return types.FormatFieldErrors("build options", e.FieldErrors)
@@ -443,8 +497,8 @@ func (e *InvalidBuildOptionsError) Error() string {
 func (e *InvalidBuildOptionsError) Unwrap() error { return ErrInvalidBuildOptions }
 
 // Validate returns an error if any typed field of the BuildOptions is invalid.
-// Validates ContextDir, Dockerfile, and Tag.
-// Dockerfile and Tag use zero-value-is-valid semantics: empty means "use default".
+// Validates ContextDir, Dockerfile, Tag, and Platform.
+// Dockerfile, Tag, and Platform use zero-value-is-valid semantics: empty means "use default".
 func (o BuildOptions) Validate() error {
 	var errs []error
 	if err := o.ContextDir.Validate(); err != nil {
@@ -460,6 +514,11 @@ func (o BuildOptions) Validate() error {
 			errs = append(errs, err)
 		}
 	}
+	if o.Platform != "" {
+		if err := o.Platform.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if len(errs) > 0 {
 		return &InvalidBuildOptionsError{FieldErrors: errs}
 	}
@@ -499,7 +558,7 @@ func (e *ContainerNameConflictError) Error() string {
 func (e *ContainerNameConflictError) Unwrap() error { return ErrContainerNameConflict }
 
 // Validate returns an error if any typed field of the RunOptions is invalid.
-// Validates Image, WorkDir, Name, ExtraHosts, Volumes, and Ports.
+// Validates Image, Platform, WorkDir, Name, ExtraHosts, Volumes, and Ports.
 func (o RunOptions) Validate() error {
 	var errs []error
 	o.appendImageValidationErrors(&errs)
@@ -515,6 +574,11 @@ func (o RunOptions) appendImageValidationErrors(errs *[]error) {
 	if err := o.Image.Validate(); err != nil {
 		*errs = append(*errs, err)
 	}
+	if o.Platform != "" {
+		if err := o.Platform.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
 }
 
 func (o RunOptions) appendRunLocationValidationErrors(errs *[]error) {
@@ -566,6 +630,11 @@ func (o CreateOptions) appendCreateImageValidationErrors(errs *[]error) {
 	if len(o.Command) == 0 {
 		*errs = append(*errs, errors.New("container command is required"))
 	}
+	if o.Platform != "" {
+		if err := o.Platform.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
 }
 
 func (o CreateOptions) appendCreateNameValidationErrors(errs *[]error) {
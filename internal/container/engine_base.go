@@ -18,11 +18,12 @@ import (
 )
 
 const (
-	commandFailedFmt    = "command %s %v failed: %w"
-	containerArgAddHost = "--add-host"
-	containerArgLabel   = "--label"
-	containerArgName    = "--name"
-	containerArgFormat  = "--format"
+	commandFailedFmt     = "command %s %v failed: %w"
+	containerArgAddHost  = "--add-host"
+	containerArgLabel    = "--label"
+	containerArgName     = "--name"
+	containerArgFormat   = "--format"
+	containerArgPlatform = "--platform"
 
 	containerCommandCreate  = "create"
 	containerCommandRun     = "run"
@@ -262,6 +263,10 @@ func (e *BaseCLIEngine) BuildArgs(opts BuildOptions) []string {
 		args = append(args, "--no-cache")
 	}
 
+	if opts.Platform != "" {
+		args = append(args, containerArgPlatform, string(opts.Platform))
+	}
+
 	for k, v := range opts.BuildArgs {
 		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, v))
 	}
@@ -286,6 +291,10 @@ func (e *BaseCLIEngine) RunArgs(opts RunOptions) []string {
 		args = append(args, containerArgName, string(opts.Name))
 	}
 
+	if opts.Platform != "" {
+		args = append(args, containerArgPlatform, string(opts.Platform))
+	}
+
 	if opts.WorkDir != "" {
 		args = append(args, "-w", string(opts.WorkDir))
 	}
@@ -365,6 +374,9 @@ func (e *BaseCLIEngine) CreateArgs(opts CreateOptions) []string {
 	if opts.Name != "" {
 		args = append(args, containerArgName, string(opts.Name))
 	}
+	if opts.Platform != "" {
+		args = append(args, containerArgPlatform, string(opts.Platform))
+	}
 	if opts.WorkDir != "" {
 		args = append(args, "-w", string(opts.WorkDir))
 	}
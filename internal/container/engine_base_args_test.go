@@ -61,6 +61,14 @@ func TestBaseCLIEngine_BuildArgs(t *testing.T) {
 			},
 			expected: []string{"build", "--no-cache", "."},
 		},
+		{
+			name: "build with platform",
+			opts: BuildOptions{
+				ContextDir: ".",
+				Platform:   "linux/arm64",
+			},
+			expected: []string{"build", "--platform", "linux/arm64", "."},
+		},
 		{
 			name: "build with all options",
 			opts: BuildOptions{
@@ -172,6 +180,14 @@ func TestBaseCLIEngine_RunArgs(t *testing.T) {
 			},
 			contains: []string{"--add-host", "host.docker.internal:host-gateway"},
 		},
+		{
+			name: "run with platform",
+			opts: RunOptions{
+				Image:    "debian:stable-slim",
+				Platform: "linux/arm64",
+			},
+			contains: []string{"--platform", "linux/arm64"},
+		},
 		{
 			name: "run with command",
 			opts: RunOptions{
@@ -290,6 +306,7 @@ func TestBaseCLIEngine_PersistentLifecycleArgs(t *testing.T) {
 		Ports:      []PortMappingSpec{"8080:80"},
 		Name:       "my-dev",
 		ExtraHosts: []HostMapping{"host.docker.internal:host-gateway"},
+		Platform:   "linux/amd64",
 	})
 
 	for _, want := range []string{
@@ -300,6 +317,7 @@ func TestBaseCLIEngine_PersistentLifecycleArgs(t *testing.T) {
 		"-v", "/host:/workspace",
 		"-p", "8080:80",
 		"--add-host", "host.docker.internal:host-gateway",
+		"--platform", "linux/amd64",
 		"debian:stable-slim",
 		"/bin/sh", "-c", "sleep infinity",
 	} {
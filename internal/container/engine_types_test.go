@@ -212,6 +212,45 @@ func TestHostMapping_String(t *testing.T) {
 	}
 }
 
+func TestImagePlatform_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		platform ImagePlatform
+		wantErr  bool
+	}{
+		{"os and arch", ImagePlatform("linux/arm64"), false},
+		{"with variant", ImagePlatform("linux/arm/v7"), false},
+		{"from arch", ImagePlatformForArch("amd64"), false},
+		{"empty is invalid", ImagePlatform(""), true},
+		{"arch only is invalid", ImagePlatform("arm64"), true},
+		{"empty part is invalid", ImagePlatform("linux/"), true},
+		{"too many parts is invalid", ImagePlatform("linux/arm/v7/x"), true},
+		{"whitespace is invalid", ImagePlatform("linux/arm 64"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.platform.Validate()
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("ImagePlatform(%q).Validate() returned unexpected error: %v", tt.platform, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidImagePlatform) {
+				t.Errorf("ImagePlatform(%q).Validate() error = %v, want ErrInvalidImagePlatform", tt.platform, err)
+			}
+			var ipErr *InvalidImagePlatformError
+			if !errors.As(err, &ipErr) {
+				t.Errorf("error should be *InvalidImagePlatformError, got: %T", err)
+			}
+		})
+	}
+}
+
 func TestNetworkPort_Validate(t *testing.T) {
 	t.Parallel()
 
@@ -61,6 +61,9 @@ const (
 	CodeContainerRuntimeInitFailed DiagnosticCode = "container_runtime_init_failed"
 	// CodeScriptInterpreterShebangOverride indicates script.interpreter overrides a script shebang.
 	CodeScriptInterpreterShebangOverride DiagnosticCode = "script_interpreter_shebang_override"
+	// CodeFingerprintStoreFailed indicates an incremental-execution fingerprint
+	// could not be loaded or saved. Bridged from the command service.
+	CodeFingerprintStoreFailed DiagnosticCode = "fingerprint_store_failed"
 	// CodeModuleShadowsGlobal indicates a local module has the same ID as a
 	// globally installed module, causing the local to take precedence. This is
 	// safe (local doesn't gain global trust) but may indicate typosquatting or
@@ -76,6 +79,9 @@ const (
 	// CodeProvisionedModuleManifestInvalid indicates a provisioned-module
 	// manifest env var was present but malformed.
 	CodeProvisionedModuleManifestInvalid DiagnosticCode = "provisioned_module_manifest_invalid"
+	// CodeInvowkfileImportCollision indicates a command or env var of an
+	// imported invowkfile fragment was ignored because its name was taken.
+	CodeInvowkfileImportCollision DiagnosticCode = "invowkfile_import_collision"
 
 	// invalidDiagnosticPanicFmt is used for impossible invalid Diagnostic states.
 	invalidDiagnosticPanicFmt = "BUG: invalid diagnostic: %v"
@@ -232,10 +238,11 @@ func (dc DiagnosticCode) Validate() error {
 		CodeIncludeNotModule, CodeIncludeReservedSkipped, CodeIncludeModuleLoadFailed,
 		CodeVendoredScanFailed, CodeVendoredReservedSkipped, CodeVendoredModuleLoadSkipped,
 		CodeVendoredNestedIgnored, CodeContainerRuntimeInitFailed,
-		CodeScriptInterpreterShebangOverride,
+		CodeScriptInterpreterShebangOverride, CodeFingerprintStoreFailed,
 		CodeModuleShadowsGlobal, CodeModuleSymlinkSkipped, CodeVendoredSymlinkSkipped,
 		CodeVendoredUndeclaredSkipped, CodeVendoredAmbiguousLockSkipped,
-		CodeVendoredTransitiveSkipped, CodeProvisionedModuleManifestInvalid:
+		CodeVendoredTransitiveSkipped, CodeProvisionedModuleManifestInvalid,
+		CodeInvowkfileImportCollision:
 		return nil
 	default:
 		return &InvalidDiagnosticCodeError{Value: dc}
@@ -304,9 +304,9 @@ func (d *Discovery) LoadFirst() (*DiscoveredFile, error) {
 			return file, nil
 		}
 
-		inv, parseErr = invowkfile.ParseLoadedModuleInvowkfile(file.Module)
+		inv, parseErr = invowkfile.ParseLoadedModuleInvowkfile(file.Module, invowkfile.WithTemplateModules(TemplateModules(file.Module, files)))
 	} else {
-		inv, parseErr = invowkfile.Parse(file.Path)
+		inv, parseErr = invowkfile.Parse(file.Path, invowkfile.WithTemplateModules(TemplateModules(nil, files)))
 	}
 
 	if parseErr != nil {
@@ -439,6 +439,41 @@ func (d *Discovery) commandSourceFor(file *DiscoveredFile) (commandSourceIdentit
 	return source, true
 }
 
+// TemplateModules maps the IDs of the discovered command-bearing modules whose
+// templates the invowkfile of module may extend to their directories. A root
+// invowkfile (nil module) may extend any discovered module; a module, like its
+// command scope, only reaches the global modules and its declared, locked
+// dependencies. When several modules share an ID, the one with the highest
+// discovery precedence wins.
+func TemplateModules(module *invowkmod.Module, files []*DiscoveredFile) map[invowkmod.ModuleID]types.FilesystemPath {
+	allowed := func(*DiscoveredFile) bool { return true }
+	if module != nil {
+		var requirements []invowkmod.ModuleRequirement
+		if module.Metadata != nil {
+			requirements = module.Metadata.Requires
+		}
+		lock, err := invowkmod.LoadLockFile(filepath.Join(string(module.Path), invowkmod.LockFileName))
+		if err != nil {
+			// An unreadable lock file vouches for no dependency.
+			lock = nil
+		}
+		allowed = func(file *DiscoveredFile) bool {
+			return file.IsGlobalModule || invowkmod.IsDeclaredLockedModule(requirements, lock, file.Module.Name())
+		}
+	}
+
+	modules := make(map[invowkmod.ModuleID]types.FilesystemPath)
+	for _, file := range files {
+		if file.Module == nil || file.Path == "" || !allowed(file) {
+			continue
+		}
+		if _, exists := modules[file.Module.Name()]; !exists {
+			modules[file.Module.Name()] = file.Module.Path
+		}
+	}
+	return modules
+}
+
 // getAliasForModulePath looks up an alias for the given module directory path
 // from the includes config. Paths are normalized with filepath.Clean before
 // comparison to handle trailing slashes and redundant separators. Returns the
@@ -481,9 +516,9 @@ func (d *Discovery) loadAllWithDiagnostics() ([]*DiscoveredFile, []Diagnostic, e
 
 			// Parse module invowkfile.cue and reattach module metadata so downstream
 			// logic (scope/dependency checks) can treat it as module-backed input.
-			inv, parseErr = invowkfile.ParseLoadedModuleInvowkfile(file.Module)
+			inv, parseErr = invowkfile.ParseLoadedModuleInvowkfile(file.Module, invowkfile.WithTemplateModules(TemplateModules(file.Module, files)))
 		} else {
-			inv, parseErr = invowkfile.Parse(file.Path)
+			inv, parseErr = invowkfile.Parse(file.Path, invowkfile.WithTemplateModules(TemplateModules(nil, files)))
 		}
 
 		if parseErr != nil {
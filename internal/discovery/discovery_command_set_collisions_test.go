@@ -239,6 +239,49 @@ func TestDiscoveredCommandSet_HierarchicalAmbiguity(t *testing.T) {
 	})
 }
 
+func TestDiscoveredCommandSet_Aliases(t *testing.T) {
+	t.Parallel()
+
+	set := NewDiscoveredCommandSet()
+	build := &CommandInfo{
+		Name: "build", SimpleName: "build", SourceID: "invowkfile",
+		Command: &invowkfile.Command{Name: "build", Aliases: []invowkfile.CommandName{"b", "deploy"}},
+	}
+	ship := &CommandInfo{
+		Name: "foo ship", SimpleName: "ship", SourceID: "foo",
+		Command: &invowkfile.Command{Name: "ship", Aliases: []invowkfile.CommandName{"s"}},
+	}
+	addCommandInfos(set, []*CommandInfo{build, ship, {
+		Name: "bar deploy", SimpleName: "deploy", SourceID: "bar",
+		Command: &invowkfile.Command{Name: "deploy"},
+	}})
+
+	set.Analyze()
+
+	if set.ByName["b"] != build || set.ByName["foo s"] != ship {
+		t.Errorf("ByName does not resolve aliases: b=%v, foo s=%v", set.ByName["b"], set.ByName["foo s"])
+	}
+	if got := set.BySimpleName["s"]; len(got) != 1 || got[0] != ship {
+		t.Errorf("BySimpleName[s] = %v, want the ship command", got)
+	}
+	if got := ship.AliasNames(); !slices.Equal(got, []invowkfile.CommandName{"foo s"}) {
+		t.Errorf("AliasNames() = %q, want [foo s]", got)
+	}
+	if !ship.HasAlias("s") || !ship.HasAlias("foo s") || ship.HasAlias("ship") {
+		t.Error("HasAlias() must match declared and qualified aliases only")
+	}
+	// The "deploy" alias collides with the "deploy" command from another
+	// source; only the command whose own name conflicts is marked ambiguous.
+	requireAmbiguityState(t, set, map[invowkfile.CommandName]bool{
+		"deploy": true,
+		"build":  false,
+		"ship":   false,
+		"b":      false,
+		"s":      false,
+	})
+	requireCommandCount(t, set, 3)
+}
+
 func addCommandInfos(set *DiscoveredCommandSet, commands []*CommandInfo) {
 	for _, command := range commands {
 		set.Add(command)
@@ -78,11 +78,12 @@ type (
 		// ByName indexes commands by their full name for O(1) lookup.
 		// Only the first command added with a given name is stored (respects
 		// discovery precedence order). Used by GetCommand for fast resolution.
+		// Command aliases are indexed by their fully qualified names too.
 		ByName map[invowkfile.CommandName]*CommandInfo
 
 		// BySimpleName indexes commands by their simple name for conflict detection.
 		// Key: simple command name (e.g., "deploy")
-		// Value: all commands with that name from different sources
+		// Value: all commands with that name (or alias) from different sources
 		BySimpleName map[invowkfile.CommandName][]*CommandInfo
 
 		// AmbiguousNames contains simple names that have conflicts (>1 source)
@@ -136,6 +137,16 @@ func (s *DiscoveredCommandSet) Add(cmd *CommandInfo) {
 	// Index by simple name
 	s.BySimpleName[cmd.SimpleName] = append(s.BySimpleName[cmd.SimpleName], cmd)
 
+	// Aliases share the command namespace: they resolve like names and take
+	// part in the same conflict analysis.
+	for i, alias := range cmd.AliasNames() {
+		if _, exists := s.ByName[alias]; !exists {
+			s.ByName[alias] = cmd
+		}
+		simpleAlias := cmd.Command.Aliases[i]
+		s.BySimpleName[simpleAlias] = append(s.BySimpleName[simpleAlias], cmd)
+	}
+
 	// Index by source
 	if _, exists := s.BySource[cmd.SourceID]; !exists {
 		// First command from this source, add to source order
@@ -146,7 +157,8 @@ func (s *DiscoveredCommandSet) Add(cmd *CommandInfo) {
 
 // Analyze detects conflicts and marks ambiguous commands.
 // Must be called after all commands have been added and before presenting results
-// to users. It marks SimpleName entries with commands from >1 source as IsAmbiguous,
+// to users. It marks SimpleName (and alias) entries with commands from >1 source
+// as ambiguous, flags commands whose own SimpleName conflicts as IsAmbiguous,
 // and sorts SourceOrder ("invowkfile" first, then modules alphabetically) for
 // consistent display ordering.
 func (s *DiscoveredCommandSet) Analyze() {
@@ -156,10 +168,13 @@ func (s *DiscoveredCommandSet) Analyze() {
 			continue
 		}
 
-		// Ambiguous: same name, different sources
+		// Ambiguous: same name (or alias), different sources. Only commands
+		// whose own name conflicts lose their transparent registration.
 		s.AmbiguousNames[simpleName] = true
 		for _, cmd := range cmds {
-			cmd.IsAmbiguous = true
+			if cmd.SimpleName == simpleName {
+				cmd.IsAmbiguous = true
+			}
 		}
 	}
 
@@ -167,6 +182,30 @@ func (s *DiscoveredCommandSet) Analyze() {
 	slices.SortFunc(s.SourceOrder, compareSourceIDs)
 }
 
+// AliasNames returns the fully qualified names of the command's aliases.
+// Module command aliases carry the same source prefix as Name (e.g., "foo b"
+// for alias "b" of "foo build").
+func (c *CommandInfo) AliasNames() []invowkfile.CommandName {
+	if c.Command == nil || len(c.Command.Aliases) == 0 {
+		return nil
+	}
+	prefix := strings.TrimSuffix(string(c.Name), string(c.SimpleName))
+	names := make([]invowkfile.CommandName, 0, len(c.Command.Aliases))
+	for _, alias := range c.Command.Aliases {
+		names = append(names, invowkfile.CommandName(prefix+string(alias))) //goplint:ignore -- alias validated by the invowkfile schema; prefix is the validated source namespace.
+	}
+	return names
+}
+
+// HasAlias reports whether name is one of the command's aliases, either as
+// declared or fully qualified.
+func (c *CommandInfo) HasAlias(name invowkfile.CommandName) bool {
+	if c.Command == nil {
+		return false
+	}
+	return slices.Contains(c.Command.Aliases, name) || slices.Contains(c.AliasNames(), name)
+}
+
 func commandsSpanMultipleSources(cmds []*CommandInfo) bool {
 	sources := make(map[SourceID]struct{}, len(cmds))
 	for _, cmd := range cmds {
@@ -229,6 +268,14 @@ func (d *Discovery) DiscoverCommandSet(ctx context.Context) (CommandSetResult, e
 		if file.Invowkfile == nil {
 			continue
 		}
+		for _, collision := range file.Invowkfile.ImportCollisions {
+			diagnostics = append(diagnostics, mustDiagnosticWithPath(
+				SeverityWarning,
+				CodeInvowkfileImportCollision,
+				collision.String(),
+				collision.Fragment,
+			))
+		}
 
 		// Determine source ID and module ID for this file
 		var sourceID SourceID
@@ -226,6 +226,30 @@ func TestDiscoverCommandSetMutationParseErrorsBecomeDiagnostics(t *testing.T) {
 	requireDiscoveryMutationDiagnostic(t, result.Diagnostics, CodeInvowkfileParseSkipped, "skipping invowkfile")
 }
 
+func TestDiscoverCommandSetMutationInvalidFlagGroupsBecomeDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	content := `cmds: [{
+	name: "push"
+	implementations: [{script: {content: "echo push"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]
+	flags: [{name: "tag", description: "Image tag"}]
+	flag_groups: {mutually_exclusive: [["tag", "latest"]]}
+}]`
+	if err := os.WriteFile(filepath.Join(tmpDir, "invowkfile.cue"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write invowkfile: %v", err)
+	}
+
+	result, err := newTestDiscovery(t, config.DefaultConfig(), tmpDir).DiscoverCommandSet(t.Context())
+	if err != nil {
+		t.Fatalf("DiscoverCommandSet() error = %v, want diagnostics-only parse skip", err)
+	}
+	if len(result.Set.Commands) != 0 {
+		t.Fatalf("commands length = %d, want 0 after parse skip", len(result.Set.Commands))
+	}
+	requireDiscoveryMutationDiagnostic(t, result.Diagnostics, CodeInvowkfileParseSkipped, "flag group lists undeclared flag 'latest'")
+}
+
 func TestGetCommandMutationRejectsInvalidCommandName(t *testing.T) {
 	t.Parallel()
 
@@ -432,6 +432,103 @@ cmds: [{name: "custom-cmd", implementations: [{script: {content: "echo custom"},
 	}
 }
 
+func TestLoadAll_ExtendsTemplatesFromIncludedModule(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	modulePath := filepath.Join(tmpDir, "shared", "io.example.tools.invowkmod")
+	createTestModule(t, modulePath, "io.example.tools", "noop")
+	templates := `
+templates: container: {
+	runtimes: [{name: "container", image: "golang:1.26"}]
+	platforms: [{name: "linux"}]
+}
+cmds: [{name: "noop", implementations: [{script: {content: "true"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]
+`
+	if err := os.WriteFile(filepath.Join(modulePath, "invowkfile.cue"), []byte(templates), 0o644); err != nil {
+		t.Fatalf("failed to write invowkfile.cue: %v", err)
+	}
+
+	projectDir := filepath.Join(tmpDir, "project")
+	testutil.MustMkdirAll(t, projectDir, 0o755)
+	rootInvowkfile := `cmds: [{name: "build", implementations: [{extends: "@io.example.tools container", script: {content: "go build"}}]}]`
+	if err := os.WriteFile(filepath.Join(projectDir, "invowkfile.cue"), []byte(rootInvowkfile), 0o644); err != nil {
+		t.Fatalf("failed to write invowkfile.cue: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Includes = []config.IncludeEntry{{Path: config.ModuleIncludePath(modulePath)}}
+	d := newTestDiscovery(t, cfg, tmpDir, WithBaseDir(types.FilesystemPath(projectDir)))
+
+	files, err := d.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	for _, f := range files {
+		if f.Source != SourceCurrentDir {
+			continue
+		}
+		if f.Error != nil {
+			t.Fatalf("root invowkfile error = %v, want the included module template to resolve", f.Error)
+		}
+		impl := f.Invowkfile.Commands[0].Implementations[0]
+		if len(impl.Runtimes) != 1 || impl.Runtimes[0].Image != "golang:1.26" {
+			t.Errorf("runtimes = %+v, want the included module template runtime", impl.Runtimes)
+		}
+		return
+	}
+	t.Fatal("LoadAll() did not load the root invowkfile")
+}
+
+func TestLoadAll_ModuleCannotExtendTemplatesOfUndeclaredModule(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	toolsPath := filepath.Join(tmpDir, "shared", "io.example.tools.invowkmod")
+	createTestModule(t, toolsPath, "io.example.tools", "noop")
+	templates := `
+templates: container: {
+	runtimes: [{name: "container", image: "golang:1.26"}]
+	platforms: [{name: "linux"}]
+}
+cmds: [{name: "noop", implementations: [{script: {content: "true"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]
+`
+	if err := os.WriteFile(filepath.Join(toolsPath, "invowkfile.cue"), []byte(templates), 0o644); err != nil {
+		t.Fatalf("failed to write invowkfile.cue: %v", err)
+	}
+
+	appPath := filepath.Join(tmpDir, "shared", "io.example.app.invowkmod")
+	createTestModule(t, appPath, "io.example.app", "build")
+	app := `cmds: [{name: "build", implementations: [{extends: "@io.example.tools container", script: {content: "go build"}}]}]`
+	if err := os.WriteFile(filepath.Join(appPath, "invowkfile.cue"), []byte(app), 0o644); err != nil {
+		t.Fatalf("failed to write invowkfile.cue: %v", err)
+	}
+
+	emptyDir := filepath.Join(tmpDir, "empty")
+	testutil.MustMkdirAll(t, emptyDir, 0o755)
+	cfg := config.DefaultConfig()
+	cfg.Includes = []config.IncludeEntry{
+		{Path: config.ModuleIncludePath(toolsPath)},
+		{Path: config.ModuleIncludePath(appPath)},
+	}
+	d := newTestDiscovery(t, cfg, tmpDir, WithBaseDir(types.FilesystemPath(emptyDir)))
+
+	files, err := d.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	for _, f := range files {
+		if f.Module == nil || f.Module.Name() != "io.example.app" {
+			continue
+		}
+		if f.Error == nil || !strings.Contains(f.Error.Error(), "not available to provide templates") {
+			t.Fatalf("module invowkfile error = %v, want the undeclared module's templates to be unavailable", f.Error)
+		}
+		return
+	}
+	t.Fatal("LoadAll() did not load the app module")
+}
+
 func TestLoadFirst_NoFiles(t *testing.T) {
 	t.Parallel()
 
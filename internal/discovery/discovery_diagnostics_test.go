@@ -92,6 +92,45 @@ func TestDiscoverCommandSet_DiagnosticsForInvalidIncludedModule(t *testing.T) {
 	}
 }
 
+func TestDiscoverCommandSet_DiagnosticsForImportCollisions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	root := `imports: ["ci/*.invowk.cue"]
+cmds: [{
+	name: "lint"
+	implementations: [{script: {content: "echo root"}, runtimes: [{name: "virtual-sh"}], platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]}]
+}]`
+	fragment := `cmds: [{
+	name: "lint"
+	implementations: [{script: {content: "echo fragment"}, runtimes: [{name: "virtual-sh"}], platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]}]
+}, {
+	name: "vet"
+	implementations: [{script: {content: "echo vet"}, runtimes: [{name: "virtual-sh"}], platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]}]
+}]`
+	fragmentPath := filepath.Join(tmpDir, "ci", "lint.invowk.cue")
+	if err := os.MkdirAll(filepath.Dir(fragmentPath), 0o755); err != nil {
+		t.Fatalf("create fragment dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "invowkfile.cue"), []byte(root), 0o644); err != nil {
+		t.Fatalf("write invowkfile: %v", err)
+	}
+	if err := os.WriteFile(fragmentPath, []byte(fragment), 0o644); err != nil {
+		t.Fatalf("write fragment: %v", err)
+	}
+
+	result, err := newTestDiscovery(t, config.DefaultConfig(), tmpDir).DiscoverCommandSet(t.Context())
+	if err != nil {
+		t.Fatalf("DiscoverCommandSet() returned error: %v", err)
+	}
+	if got := len(result.Set.Commands); got != 2 {
+		t.Fatalf("commands length = %d, want 2 (root lint and imported vet)", got)
+	}
+	if !containsDiagnostic(result.Diagnostics, CodeInvowkfileImportCollision, fragmentPath) {
+		t.Fatalf("expected %s diagnostic for %s, got %#v", CodeInvowkfileImportCollision, fragmentPath, result.Diagnostics)
+	}
+}
+
 func containsDiagnostic(diags []Diagnostic, code DiagnosticCode, path string) bool {
 	for _, diag := range diags {
 		if diag.code == code && string(diag.path) == path {
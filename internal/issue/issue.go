@@ -29,6 +29,9 @@ const (
 	DependenciesNotSatisfiedId
 	HostNotSupportedId
 	InvalidArgumentId
+	CommandDeprecatedId
+	FlagGroupViolatedId
+	ConfirmationRequiredId
 )
 
 var (
@@ -117,6 +120,21 @@ var (
 		mdMsg: loadTemplate("invalid_argument"),
 	}
 
+	commandDeprecatedIssue = &Issue{
+		id:    CommandDeprecatedId,
+		mdMsg: loadTemplate("command_deprecated"),
+	}
+
+	flagGroupViolatedIssue = &Issue{
+		id:    FlagGroupViolatedId,
+		mdMsg: loadTemplate("flag_group_violated"),
+	}
+
+	confirmationRequiredIssue = &Issue{
+		id:    ConfirmationRequiredId,
+		mdMsg: loadTemplate("confirmation_required"),
+	}
+
 	issues = map[Id]*Issue{
 		fileNotFoundIssue.Id():             fileNotFoundIssue,
 		invowkfileNotFoundIssue.Id():       invowkfileNotFoundIssue,
@@ -133,6 +151,9 @@ var (
 		dependenciesNotSatisfiedIssue.Id(): dependenciesNotSatisfiedIssue,
 		hostNotSupportedIssue.Id():         hostNotSupportedIssue,
 		invalidArgumentIssue.Id():          invalidArgumentIssue,
+		commandDeprecatedIssue.Id():        commandDeprecatedIssue,
+		flagGroupViolatedIssue.Id():        flagGroupViolatedIssue,
+		confirmationRequiredIssue.Id():     confirmationRequiredIssue,
 	}
 )
 
@@ -199,7 +220,8 @@ func (id Id) Validate() error {
 		CommandNotFoundId, RuntimeNotAvailableId, ContainerEngineNotFoundId,
 		DockerfileNotFoundId, ScriptExecutionFailedId, ConfigLoadFailedId,
 		InvalidRuntimeModeId, ShellNotFoundId, PermissionDeniedId,
-		DependenciesNotSatisfiedId, HostNotSupportedId, InvalidArgumentId:
+		DependenciesNotSatisfiedId, HostNotSupportedId, InvalidArgumentId,
+		CommandDeprecatedId, FlagGroupViolatedId, ConfirmationRequiredId:
 		return nil
 	default:
 		return &InvalidIdError{Value: id}
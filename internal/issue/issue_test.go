@@ -29,6 +29,9 @@ func TestId_Constants(t *testing.T) {
 		DependenciesNotSatisfiedId,
 		HostNotSupportedId,
 		InvalidArgumentId,
+		CommandDeprecatedId,
+		FlagGroupViolatedId,
+		ConfirmationRequiredId,
 	}
 
 	seen := make(map[Id]bool)
@@ -178,6 +181,9 @@ func TestGet(t *testing.T) {
 		{PermissionDeniedId, false, "Permission denied"},
 		{DependenciesNotSatisfiedId, false, "Dependencies not satisfied"},
 		{HostNotSupportedId, false, "Host not supported"},
+		{CommandDeprecatedId, false, "Command deprecated"},
+		{FlagGroupViolatedId, false, "Invalid flag combination"},
+		{ConfirmationRequiredId, false, "Confirmation required"},
 		{Id(9999), true, ""},
 	}
 
@@ -257,7 +263,7 @@ func TestValues(t *testing.T) {
 	}
 
 	// Count expected number of issues
-	expectedCount := 15 // Based on the number of predefined issues
+	expectedCount := 18 // Based on the number of predefined issues
 
 	if len(issues) != expectedCount {
 		t.Errorf("Values() returned %d issues, want %d", len(issues), expectedCount)
@@ -303,6 +309,9 @@ func TestIssuesMapCompleteness(t *testing.T) {
 		DependenciesNotSatisfiedId,
 		HostNotSupportedId,
 		InvalidArgumentId,
+		CommandDeprecatedId,
+		FlagGroupViolatedId,
+		ConfirmationRequiredId,
 	}
 
 	for _, id := range expectedIds {
@@ -373,6 +382,9 @@ func TestId_Validate(t *testing.T) {
 		{"DependenciesNotSatisfiedId", DependenciesNotSatisfiedId, true, false},
 		{"HostNotSupportedId", HostNotSupportedId, true, false},
 		{"InvalidArgumentId", InvalidArgumentId, true, false},
+		{"CommandDeprecatedId", CommandDeprecatedId, true, false},
+		{"FlagGroupViolatedId", FlagGroupViolatedId, true, false},
+		{"ConfirmationRequiredId", ConfirmationRequiredId, true, false},
 		{"zero value", Id(0), false, true},
 		{"out of range positive", Id(9999), false, true},
 		{"negative", Id(-1), false, true},
@@ -25,6 +25,7 @@ type (
 	// Execute and ExecuteCapture to avoid code duplication.
 	containerExecPrep struct {
 		image          container.ImageTag
+		platform       container.ImagePlatform
 		shellCmd       []string
 		workDir        container.MountTargetPath
 		env            map[string]string
@@ -93,6 +94,17 @@ func (r *ContainerRuntime) prepareContainerExecution(ctx *ExecutionContext, opts
 		}
 	}
 
+	outputFile := ctx.Env.ExtraEnv[EnvVarOutputFile]
+	if outputFile != "" {
+		persistent, persistentErr := persistentContainerRequested(ctx, containerCfg)
+		if persistentErr != nil {
+			return nil, NewErrorResult(1, persistentErr)
+		}
+		if persistent {
+			return nil, NewErrorResult(1, ErrOutputsInPersistentContainer)
+		}
+	}
+
 	skipImagePrep, existingExternalCLI, err := r.shouldSkipPersistentImagePreparation(ctx, containerCfg)
 	if err != nil {
 		return nil, NewErrorResult(1, err)
@@ -150,6 +162,12 @@ func (r *ContainerRuntime) prepareContainerExecution(ctx *ExecutionContext, opts
 	// so the volume-mount validator does not reject Windows backslashes; both
 	// Docker and Podman accept forward slashes in Windows host paths.
 	volumes = append(volumes, container.VolumeMountSpec(filepath.ToSlash(invowkDir)+":/workspace")) //goplint:ignore -- constructed from known-good directory + constant mount target
+	// Mount the output file of commands that declare outputs and point the
+	// script at the mounted path.
+	if outputFile != "" {
+		volumes = append(volumes, container.VolumeMountSpec(filepath.ToSlash(outputFile)+":"+containerOutputFilePath)) //goplint:ignore -- constructed from an invowk-created temp file + constant mount target
+		env[EnvVarOutputFile] = containerOutputFilePath
+	}
 
 	// Resolve interpreter (defaults to "auto" which parses shebang)
 	interpInfo := ctx.SelectedImpl.Script.ResolveInterpreterFromScript(script)
@@ -226,6 +244,7 @@ func (r *ContainerRuntime) prepareContainerExecution(ctx *ExecutionContext, opts
 
 	return &containerExecPrep{
 		image:          imageTag,
+		platform:       imagePlatform(ctx),
 		shellCmd:       shellCmd,
 		workDir:        mountTarget,
 		env:            env,
@@ -385,6 +404,7 @@ func (r *ContainerRuntime) Execute(ctx *ExecutionContext) *Result {
 	// Run the container
 	runOpts := container.RunOptions{
 		Image:       prep.image,
+		Platform:    prep.platform,
 		Command:     prep.shellCmd,
 		WorkDir:     prep.workDir,
 		Env:         prep.env,
@@ -449,6 +469,7 @@ func (r *ContainerRuntime) ExecuteCapture(ctx *ExecutionContext) *Result {
 	// Run the container with output capture
 	runOpts := container.RunOptions{
 		Image:       prep.image,
+		Platform:    prep.platform,
 		Command:     prep.shellCmd,
 		WorkDir:     prep.workDir,
 		Env:         prep.env,
@@ -488,6 +509,20 @@ func resultWithDiagnostics(result *Result, diagnostics []InitDiagnostic) *Result
 	return result
 }
 
+// imagePlatform returns the engine --platform for the selected implementation:
+// the Linux image variant of the host architecture when the selected platform
+// entry declares an arch restriction, and the engine default otherwise.
+func imagePlatform(ctx *ExecutionContext) container.ImagePlatform {
+	if ctx.SelectedImpl == nil || ctx.SelectedArch == "" {
+		return ""
+	}
+	platformCfg := ctx.SelectedImpl.GetPlatformConfig(ctx.SelectedPlatform, ctx.SelectedArch)
+	if platformCfg == nil || len(platformCfg.Arch) == 0 {
+		return ""
+	}
+	return container.ImagePlatformForArch(string(ctx.SelectedArch))
+}
+
 // setupSSHConnection sets up SSH connection for container host access
 func (r *ContainerRuntime) setupSSHConnection(ctx *ExecutionContext, env map[string]string) (*HostCallbackConnectionInfo, error) {
 	if r.hostCallbacks == nil {
@@ -4,6 +4,7 @@ package runtime
 
 import (
 	"github.com/invowk/invowk/internal/container"
+	"github.com/invowk/invowk/pkg/invowkfile"
 	"github.com/invowk/invowk/pkg/types"
 )
 
@@ -12,3 +13,9 @@ import (
 func IsTransientContainerEngineExitCode(code types.ExitCode) bool {
 	return container.IsTransientEngineExitCode(code)
 }
+
+// isContainerEngineFailure reports whether a run in the container runtime
+// exited with a code of the container engine rather than of the script.
+func isContainerEngineFailure(ctx *ExecutionContext, code types.ExitCode) bool {
+	return ctx.SelectedRuntime == invowkfile.RuntimeContainer && IsTransientContainerEngineExitCode(code)
+}
@@ -25,6 +25,7 @@ const (
 	persistentContainerLabelSpecHash    = "dev.invowk.container.spec"
 	persistentContainerManagedLabelTrue = "true"
 	defaultContainerShellPath           = "/bin/sh"
+	containerOutputFilePath             = "/invowk/output"
 )
 
 var persistentContainerIdleCommand = []string{
@@ -162,6 +163,7 @@ func (r *ContainerRuntime) persistentCreateOptions(ctx *ExecutionContext, prep *
 	labels := persistentContainerLabels(ctx, prep, target)
 	return container.CreateOptions{
 		Image:      prep.image,
+		Platform:   prep.platform,
 		Command:    slices.Clone(persistentContainerIdleCommand),
 		Labels:     labels,
 		Volumes:    slices.Clone(prep.volumes),
@@ -68,6 +68,7 @@ func (r *ContainerRuntime) PrepareCommand(ctx *ExecutionContext) (*PreparedComma
 
 	runOpts := container.RunOptions{
 		Image:       prep.image,
+		Platform:    prep.platform,
 		Command:     prep.shellCmd,
 		WorkDir:     prep.workDir,
 		Env:         prep.env,
@@ -145,6 +145,7 @@ func (r *ContainerRuntime) ensureImage(ctx *ExecutionContext, cfg invowkfileCont
 		Dockerfile: containerfile,
 		Tag:        container.ImageTag(imageTag),
 		NoCache:    ctx.ForceRebuild,
+		Platform:   imagePlatform(ctx),
 		Stdout:     ctx.IO.Stdout,
 		Stderr:     ctx.IO.Stderr,
 	}
@@ -772,3 +772,42 @@ func TestContainerConfigFromRuntime(t *testing.T) {
 		}
 	})
 }
+
+func TestImagePlatform(t *testing.T) {
+	t.Parallel()
+
+	impl := &invowkfile.Implementation{
+		Platforms: []invowkfile.PlatformConfig{
+			{Name: invowkfile.PlatformLinux, Arch: []invowkfile.Architecture{invowkfile.ArchARM64}},
+			{Name: invowkfile.PlatformMac},
+		},
+	}
+	tests := []struct {
+		name     string
+		ctx      *ExecutionContext
+		expected container.ImagePlatform
+	}{
+		{
+			name:     "arch-restricted platform",
+			ctx:      &ExecutionContext{SelectedImpl: impl, SelectedPlatform: invowkfile.PlatformLinux, SelectedArch: invowkfile.ArchARM64},
+			expected: "linux/arm64",
+		},
+		{
+			name: "unrestricted platform",
+			ctx:  &ExecutionContext{SelectedImpl: impl, SelectedPlatform: invowkfile.PlatformMac, SelectedArch: invowkfile.ArchARM64},
+		},
+		{
+			name: "no selected implementation",
+			ctx:  &ExecutionContext{SelectedPlatform: invowkfile.PlatformLinux, SelectedArch: invowkfile.ArchARM64},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := imagePlatform(tt.ctx); got != tt.expected {
+				t.Errorf("imagePlatform() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,11 +2,13 @@
 
 // Package runtime provides command execution runtimes for Invowk.
 //
-// Four runtime implementations are available:
+// Six runtime implementations are available:
 //   - native: executes commands using the host shell (bash/sh/PowerShell)
 //   - virtual-sh: executes commands using an embedded shell interpreter (mvdan/sh)
 //   - virtual-lua: executes commands using an embedded Lua runtime
+//   - virtual-starlark: executes commands using an embedded Starlark interpreter
 //   - container: executes commands inside a container (Docker/Podman)
+//   - remote-ssh: executes commands on another host through the system ssh client
 //
 // All runtimes implement the Runtime interface with Name(), Execute(), Available(), and Validate().
 // Runtimes supporting output capture implement CapturingRuntime, and those supporting interactive
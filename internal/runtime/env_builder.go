@@ -17,9 +17,9 @@ type (
 	//  2. Root-level env.files
 	//  3. Command-level env.files
 	//  4. Implementation-level env.files
-	//  5. Root-level env.vars
-	//  6. Command-level env.vars
-	//  7. Implementation-level env.vars
+	//  5. Root-level env.vars, then env.secrets
+	//  6. Command-level env.vars, then env.secrets
+	//  7. Implementation-level env.vars, then env.secrets
 	//  8. ExtraEnv (INVOWK_FLAG_*, INVOWK_ARG_*, ARGn, ARGC)
 	//  9. RuntimeEnvFiles (--ivk-env-file flag)
 	//  10. RuntimeEnvVars (--ivk-env-var flag) - HIGHEST priority
@@ -33,6 +33,9 @@ type (
 	// minimize the supply-chain attack surface.
 	// The container runtime defaults to EnvInheritNone (safe default).
 	//
+	// Secrets are resolved through SecretProviders at build time and their
+	// values are registered with ExecutionContext.Secrets for output masking.
+	//
 	// This interface enables:
 	//   - Testability: runtimes can be tested with mock env builders
 	//   - Flexibility: alternative env building strategies for specific use cases
@@ -48,6 +51,10 @@ type (
 		// Environ returns the host environment as "KEY=VALUE" strings.
 		// When nil, os.Environ() is used.
 		Environ func() []string
+		// SecretProviders overrides the built-in secret providers by type
+		// (optional). Missing types use CommandSecretProvider,
+		// FileSecretProvider, EnvSecretProvider, or KeyringSecretProvider.
+		SecretProviders map[invowkfile.SecretProviderType]SecretProvider
 	}
 
 	// MockEnvBuilder is a test helper that returns a fixed environment map.
@@ -96,14 +103,14 @@ func (b *DefaultEnvBuilder) Build(ctx *ExecutionContext, defaultMode invowkfile.
 		}
 	}
 
-	// 5. Root-level env.vars
-	maps.Copy(env, ctx.Invowkfile.Env.GetVars())
-
-	// 6. Command-level env.vars
-	maps.Copy(env, ctx.Command.Env.GetVars())
-
-	// 7. Implementation-level env.vars
-	maps.Copy(env, ctx.SelectedImpl.Env.GetVars())
+	// 5-7. Root-, command-, and implementation-level env.vars, each
+	// followed by the secrets declared at the same level
+	for _, levelEnv := range []*invowkfile.EnvConfig{ctx.Invowkfile.Env, ctx.Command.Env, ctx.SelectedImpl.Env} {
+		maps.Copy(env, levelEnv.GetVars())
+		if err := b.resolveSecrets(ctx, env, levelEnv.GetSecrets(), ctx.Invowkfile.GetScriptBasePath()); err != nil {
+			return nil, err
+		}
+	}
 
 	// 8. Extra env from context (flags, args)
 	maps.Copy(env, ctx.Env.ExtraEnv)
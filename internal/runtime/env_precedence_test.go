@@ -16,9 +16,9 @@ import (
 //  2. Root-level env.files
 //  3. Command-level env.files
 //  4. Implementation-level env.files
-//  5. Root-level env.vars
-//  6. Command-level env.vars
-//  7. Implementation-level env.vars
+//  5. Root-level env.vars, then env.secrets
+//  6. Command-level env.vars, then env.secrets
+//  7. Implementation-level env.vars, then env.secrets
 //  8. ExtraEnv (INVOWK_FLAG_*, INVOWK_ARG_*, ARGn, ARGC)
 //  9. RuntimeEnvFiles (--ivk-env-file flag)
 //  10. RuntimeEnvVars (--ivk-env-var flag) - HIGHEST priority
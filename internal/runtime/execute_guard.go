@@ -11,6 +11,8 @@ const (
 	virtualNoScriptErrMsg        = "script has no content to execute"
 	containerNoImplErrMsg        = "no implementation selected for execution"
 	containerNoScriptErrMsg      = "implementation has no script to execute"
+	remoteSSHNoImplErrMsg        = "no implementation selected for execution"
+	remoteSSHNoScriptErrMsg      = "implementation has no script to execute"
 	nilExecutionContextErrMsg    = "execution context is required"
 	noInvowkfileErrMsg           = "execution context has no invowkfile"
 	sshServerNotConfiguredErrMsg = "enable_host_ssh is enabled but SSH server is not configured"
@@ -26,6 +28,8 @@ var (
 	errVirtualNoScript        = errors.New(virtualNoScriptErrMsg)
 	errContainerNoImpl        = errors.New(containerNoImplErrMsg)
 	errContainerNoScript      = errors.New(containerNoScriptErrMsg)
+	errRemoteSSHNoImpl        = errors.New(remoteSSHNoImplErrMsg)
+	errRemoteSSHNoScript      = errors.New(remoteSSHNoScriptErrMsg)
 	errSSHServerNotConfigured = errors.New(sshServerNotConfiguredErrMsg)
 	errSSHServerNotRunning    = errors.New(sshServerNotRunningErrMsg)
 )
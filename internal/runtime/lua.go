@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -52,6 +53,7 @@ type (
 		script         string
 		runtimeCfg     *invowkfile.RuntimeConfig
 		env            map[string]string
+		envLists       map[string][]string
 		policy         *virtualHostBinaryPolicy
 		pathResolver   virtualPathResolver
 		pathValidator  virtualPathValidator
@@ -70,6 +72,7 @@ type (
 		pathResolver     virtualPathResolver
 		pathValidator    virtualPathValidator
 		env              map[string]string
+		envLists         map[string][]string
 		workDir          string
 		scriptBasePath   string
 		stdin            io.Reader
@@ -200,6 +203,7 @@ func (r *LuaRuntime) execute(ctx *ExecutionContext, stdout, stderr io.Writer) *R
 		script:         script,
 		runtimeCfg:     selectedRuntimeConfig(ctx),
 		env:            env,
+		envLists:       luaEnvLists(ctx, env),
 		policy:         hostBinaryPolicy(ctx, env),
 		pathResolver:   pathResolver,
 		pathValidator:  pathValidator,
@@ -236,6 +240,7 @@ func (r *LuaRuntime) executeScript(ctx context.Context, req luaExecutionRequest)
 			pathResolver:     req.pathResolver,
 			pathValidator:    req.pathValidator,
 			env:              req.env,
+			envLists:         req.envLists,
 			workDir:          req.workDir,
 			scriptBasePath:   req.scriptBasePath,
 			stdin:            req.stdin,
@@ -389,8 +394,9 @@ func installInvowkLuaBridge(
 	r.SetTable(state, luart.StringValue("bin_path"), luart.StringValue(req.env[EnvVarStateBinPath]))
 	stateProxy, stateLockFunc := luaReadOnlyProxyTable(r, state, "invowk.state")
 	r.SetTable(invowk, luart.StringValue("state"), luart.TableValue(stateProxy))
-	r.SetTable(invowk, luart.StringValue("env"), luart.TableValue(luaReadOnlyEnvTable(r, req.env)))
+	r.SetTable(invowk, luart.StringValue("env"), luart.TableValue(luaReadOnlyEnvTable(r, req.env, req.envLists)))
 	pathFunc := r.SetEnvGoFunc(invowk, "path", luaPathFunc(req.pathResolver, req.workDir), 1, false)
+	outputFunc := r.SetEnvGoFunc(invowk, "output", luaOutputFunc(req.env[EnvVarOutputFile]), 2, false)
 	commandConfig := luaCommandBridgeConfig{
 		policy:           req.policy,
 		registry:         req.registry,
@@ -413,7 +419,7 @@ func installInvowkLuaBridge(
 	invowkProxy, invowkLockFunc := luaReadOnlyProxyTable(r, invowk, "invowk")
 	r.SetEnv(r.GlobalEnv(), "invowk", luart.TableValue(invowkProxy))
 
-	funcs := append([]*luart.GoFunction{pathFunc, getenvFunc, requireFunc, stateLockFunc, invowkLockFunc}, ioFuncs...)
+	funcs := append([]*luart.GoFunction{pathFunc, outputFunc, getenvFunc, requireFunc, stateLockFunc, invowkLockFunc}, ioFuncs...)
 	funcs = append(funcs, cmdFuncs...)
 	funcs = append(funcs, captureFuncs...)
 	luart.SolemnlyDeclareCompliance(luart.ComplyCpuSafe|luart.ComplyMemSafe|luart.ComplyIoSafe, funcs...)
@@ -433,6 +439,47 @@ func luaPathFunc(resolver virtualPathResolver, workDir string) luart.GoFunctionF
 	}
 }
 
+// luaOutputFunc implements invowk.output(name, value), which sets a declared
+// output of the command by appending it to the output file at path.
+func luaOutputFunc(path string) luart.GoFunctionFunc {
+	return func(t *luart.Thread, c *luart.GoCont) (luart.Cont, error) {
+		name, err := c.StringArg(0)
+		if err != nil {
+			return nil, fmt.Errorf("read invowk.output name: %w", err)
+		}
+		value, err := c.StringArg(1)
+		if err != nil {
+			return nil, fmt.Errorf("read invowk.output value: %w", err)
+		}
+		if path == "" {
+			return nil, errors.New("invowk.output: the command declares no outputs")
+		}
+		outputName := invowkfile.OutputName(name)
+		if err := outputName.Validate(); err != nil {
+			return nil, fmt.Errorf("invowk.output: %w", err)
+		}
+		if err := appendOutput(path, outputName, value); err != nil {
+			return nil, fmt.Errorf("invowk.output: %w", err)
+		}
+		return c.Next(), nil
+	}
+}
+
+func appendOutput(path string, name invowkfile.OutputName, value string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("open output file: %w", err)
+	}
+	if _, err := f.WriteString(FormatOutput(name, value)); err != nil {
+		_ = f.Close() // The write error is the one to report.
+		return fmt.Errorf("write output file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write output file: %w", err)
+	}
+	return nil
+}
+
 func installLuaOSBridge(r *luart.Runtime, env map[string]string) *luart.GoFunction {
 	osTable := luart.NewTable()
 	getenvFunc := r.SetEnvGoFunc(osTable, "getenv", func(t *luart.Thread, c *luart.GoCont) (luart.Cont, error) {
@@ -570,7 +617,29 @@ func luaCommandArgsWithName(name string, values []luart.Value) ([]string, error)
 	return args, nil
 }
 
-func luaReadOnlyEnvTable(r *luart.Runtime, env map[string]string) *luart.Table {
+// luaEnvLists decodes the INVOWK_FLAG_* values of "list" flags so invowk.env
+// can expose them as Lua sequences instead of encoded strings.
+func luaEnvLists(ctx *ExecutionContext, env map[string]string) map[string][]string {
+	if ctx.Command == nil {
+		return nil
+	}
+	lists := make(map[string][]string)
+	for i := range ctx.Command.Flags {
+		flag := &ctx.Command.Flags[i]
+		if flag.GetType() != invowkfile.FlagTypeList {
+			continue
+		}
+		envName := invowkfile.FlagNameToEnvVar(flag.Name)
+		if value, ok := env[envName]; ok {
+			lists[envName] = invowkfile.SplitListEnvValue(value, flag.ListFormat)
+		}
+	}
+	return lists
+}
+
+// luaReadOnlyEnvTable exposes env as a read-only table. Keys present in lists
+// ("list" flags) read as fresh Lua sequences; every other key reads as a string.
+func luaReadOnlyEnvTable(r *luart.Runtime, env map[string]string, lists map[string][]string) *luart.Table {
 	table := luart.NewTable()
 	meta := luart.NewTable()
 	r.SetEnvGoFunc(meta, "__index", func(t *luart.Thread, c *luart.GoCont) (luart.Cont, error) {
@@ -578,6 +647,9 @@ func luaReadOnlyEnvTable(r *luart.Runtime, env map[string]string) *luart.Table {
 		if err != nil {
 			return nil, fmt.Errorf("read invowk.env key: %w", err)
 		}
+		if values, ok := lists[name]; ok {
+			return c.PushingNext1(t.Runtime, luaStringSequence(values)), nil
+		}
 		value, ok := env[name]
 		if !ok {
 			return c.PushingNext1(t.Runtime, luart.NilValue), nil
@@ -608,6 +680,14 @@ func luaSetReadOnlyNewIndex(r *luart.Runtime, meta *luart.Table, name string) *l
 	}, 3, false)
 }
 
+func luaStringSequence(values []string) luart.Value {
+	table := luart.NewTable()
+	for i, value := range values {
+		table.Set(luart.IntValue(int64(i+1)), luart.StringValue(value))
+	}
+	return luart.TableValue(table)
+}
+
 func luaArgsTable(args []string) luart.Value {
 	table := luart.NewTable()
 	for i, arg := range args {
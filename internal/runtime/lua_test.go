@@ -69,6 +69,76 @@ print(tostring(cmdWriteOK))
 	}
 }
 
+func TestLuaBridgeEnvExposesListFlagsAsSequences(t *testing.T) {
+	t.Parallel()
+
+	script := `
+local tags = invowk.env.INVOWK_FLAG_TAG
+print(type(tags), #tags, tags[1], tags[2])
+local hosts = invowk.env.INVOWK_FLAG_HOST
+print(type(hosts), #hosts, hosts[1])
+print(os.getenv("INVOWK_FLAG_TAG"))
+print(invowk.env.INVOWK_FLAG_NAME)
+`
+	ctx, stdout, _ := newLuaExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualLua}, nil)
+	ctx.Command.Flags = []invowkfile.Flag{
+		{Name: "tag", Type: invowkfile.FlagTypeList},
+		{Name: "host", Type: invowkfile.FlagTypeList, ListFormat: invowkfile.ListFormatJSON},
+		{Name: "name"},
+	}
+	ctx.Env.ExtraEnv["INVOWK_FLAG_TAG"] = "v1\nlatest"
+	ctx.Env.ExtraEnv["INVOWK_FLAG_HOST"] = `["a.example"]`
+	ctx.Env.ExtraEnv["INVOWK_FLAG_NAME"] = "plain"
+
+	result := NewLuaRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+
+	want := "table\t2\tv1\tlatest\ntable\t1\ta.example\nv1\nlatest\nplain"
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestLuaBridgeOutputWritesDeclaredOutputs(t *testing.T) {
+	t.Parallel()
+
+	script := `
+invowk.output("version", "1.2.3")
+invowk.output("notes", "line 1\nline 2")
+print(tostring(pcall(invowk.output, "bad-name", "x")))
+`
+	ctx, stdout, _ := newLuaExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualLua}, nil)
+	ctx.Command.Outputs = []invowkfile.CommandOutput{{Name: "version"}, {Name: "notes"}}
+	outputFile, err := NewOutputFile(ctx)
+	if err != nil {
+		t.Fatalf("NewOutputFile() error = %v", err)
+	}
+
+	result := outputFile.Collect(NewLuaRuntime(false).Execute(ctx))
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "false" {
+		t.Fatalf("invowk.output with an invalid name succeeded: %q", got)
+	}
+	want := invowkfile.CommandOutputs{"version": "1.2.3", "notes": "line 1\nline 2"}
+	if len(result.Outputs) != len(want) || result.Outputs["version"] != want["version"] || result.Outputs["notes"] != want["notes"] {
+		t.Fatalf("Outputs = %q, want %q", result.Outputs, want)
+	}
+}
+
+func TestLuaBridgeOutputWithoutDeclaredOutputsFails(t *testing.T) {
+	t.Parallel()
+
+	ctx, _, _ := newLuaExecutionContext(t, `invowk.output("version", "1.2.3")`, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualLua}, nil)
+	result := NewLuaRuntime(false).Execute(ctx)
+	if result.Success() || result.Error == nil || !strings.Contains(result.Error.Error(), "declares no outputs") {
+		t.Fatalf("Execute() result = %#v, want a declares-no-outputs error", result)
+	}
+}
+
 func TestLuaBridgeVirtualFilesystemPathsExposeResolvedPath(t *testing.T) {
 	t.Parallel()
 
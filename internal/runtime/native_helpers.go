@@ -103,8 +103,9 @@ func promoteContextError(ctx *ExecutionContext, result *Result) {
 }
 
 // configureCommandDirAndEnv validates the working directory, builds the
-// environment, and applies both to the exec.Cmd. Returns error if working
-// directory validation or environment building fails.
+// environment, and applies both to the exec.Cmd, wrapping it in the sandbox
+// when one is configured. Returns error if working directory validation,
+// environment building, or sandbox setup fails.
 func (r *NativeRuntime) configureCommandDirAndEnv(cmd *exec.Cmd, ctx *ExecutionContext) error {
 	workDir := ctx.EffectiveWorkDir()
 	if workDir != "" {
@@ -119,6 +120,9 @@ func (r *NativeRuntime) configureCommandDirAndEnv(cmd *exec.Cmd, ctx *ExecutionC
 		return fmt.Errorf(failedBuildEnvironmentFmt, err)
 	}
 	ctx.AddTUIEnv(env)
+	if err = configureNativeSandbox(cmd, ctx, env); err != nil {
+		return err
+	}
 	cmd.Env = EnvToSlice(env)
 
 	return nil
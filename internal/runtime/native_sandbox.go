@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/invowk/invowk/internal/sandbox"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// configureNativeSandbox wraps cmd in the Linux sandbox when the selected
+// native runtime config has a sandbox block. Writable paths are the roots the
+// virtual runtimes allow, and their anchors and named paths are added to env.
+func configureNativeSandbox(cmd *exec.Cmd, ctx *ExecutionContext, env map[string]string) error {
+	rtConfig := selectedRuntimeConfig(ctx)
+	if rtConfig == nil || rtConfig.Sandbox == nil {
+		return nil
+	}
+
+	resolver, err := newVirtualPathResolver(ctx)
+	if err != nil {
+		return fmt.Errorf("resolve sandbox paths: %w", err)
+	}
+	addVirtualPathEnv(env, resolver)
+
+	spec := sandbox.Spec{
+		UnrestrictedFilesystem: resolver.access == invowkfile.VirtualFilesystemAccessFull,
+		IsolateNetwork:         rtConfig.Sandbox.Network.Effective() == invowkfile.SandboxNetworkNone,
+	}
+	for _, root := range resolver.allowedRoots {
+		spec.WritablePaths = append(spec.WritablePaths, types.FilesystemPath(root))
+	}
+
+	invowkPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+	if err = sandbox.Wrap(cmd, []string{invowkPath, "internal", "exec-sandboxed"}, spec); err != nil {
+		return fmt.Errorf("native sandbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"slices"
+	"testing"
+
+	"github.com/invowk/invowk/internal/sandbox"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestConfigureNativeSandbox(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(dir, "invowkfile.cue"))}
+	ctx := NewExecutionContext(t.Context(), testCommandWithScript("lint", "make lint", invowkfile.RuntimeNative), inv)
+
+	cmd := exec.Command("/bin/sh", "-c", "make lint")
+	env := map[string]string{}
+	if err := configureNativeSandbox(cmd, ctx, env); err != nil {
+		t.Fatalf("configureNativeSandbox() without sandbox error = %v", err)
+	}
+	if cmd.Path != "/bin/sh" || len(env) != 0 {
+		t.Fatalf("command without sandbox was changed: %q %q, env %v", cmd.Path, cmd.Args, env)
+	}
+
+	ctx.SelectedImpl.Runtimes[0].Sandbox = &invowkfile.RuntimeSandboxConfig{Network: invowkfile.SandboxNetworkHost}
+	err := configureNativeSandbox(cmd, ctx, env)
+	switch {
+	case goruntime.GOOS != "linux":
+		if !errors.Is(err, sandbox.ErrUnsupported) {
+			t.Fatalf("configureNativeSandbox() error = %v, want ErrUnsupported", err)
+		}
+		return
+	case errors.Is(err, sandbox.ErrLandlockUnavailable):
+		t.Skip(err)
+	case err != nil:
+		t.Fatalf("configureNativeSandbox() error = %v", err)
+	}
+
+	specIndex := slices.Index(cmd.Args, "--"+sandbox.SpecJSONFlag)
+	if specIndex < 1 || cmd.Args[specIndex-1] != "exec-sandboxed" {
+		t.Fatalf("wrapped args = %q, want the exec-sandboxed helper", cmd.Args)
+	}
+	var spec sandbox.Spec
+	if err = json.Unmarshal([]byte(cmd.Args[specIndex+1]), &spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if spec.IsolateNetwork || spec.UnrestrictedFilesystem {
+		t.Errorf("spec = %+v, want host network and restricted filesystem", spec)
+	}
+	workDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(spec.WritablePaths, invowkfile.FilesystemPath(workDir)) {
+		t.Errorf("writable paths %v do not include the working directory %q", spec.WritablePaths, workDir)
+	}
+	if env["INVOWK_ANCHOR_WORK"] == "" {
+		t.Errorf("env = %v, want virtual path anchors", env)
+	}
+	if !slices.Equal(cmd.Args[len(cmd.Args)-3:], []string{"/bin/sh", "-c", "make lint"}) {
+		t.Errorf("wrapped args = %q, want the original command at the end", cmd.Args)
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+const (
+	// EnvVarOutputFile is injected with the path of the file a command that
+	// declares outputs writes them to, one name=value line per output, or
+	// name<<DELIMITER followed by the value lines and DELIMITER for
+	// multi-line values.
+	EnvVarOutputFile = "INVOWK_OUTPUT_FILE"
+
+	// maxOutputFileSize bounds how much of an output file is read.
+	maxOutputFileSize = 1 << 20
+)
+
+var (
+	// ErrInvalidOutputFile is returned when the output file of a command is
+	// malformed, too large, or names an output the command does not declare.
+	ErrInvalidOutputFile = errors.New("invalid output file")
+
+	// ErrOutputsInPersistentContainer is returned when a command that
+	// declares outputs runs in a persistent container, which cannot mount the
+	// per-execution output file.
+	ErrOutputsInPersistentContainer = errors.New("commands that declare outputs cannot run in a persistent container")
+)
+
+// OutputFile is the per-execution file a command writes its declared outputs to.
+type OutputFile struct {
+	path    string
+	command *invowkfile.Command
+}
+
+// NewOutputFile creates the output file of ctx's command and exposes its path
+// to the script as INVOWK_OUTPUT_FILE. It returns nil when the command
+// declares no outputs. Collect must be called once the script has run.
+func NewOutputFile(ctx *ExecutionContext) (*OutputFile, error) {
+	if ctx.Command == nil || len(ctx.Command.Outputs) == 0 {
+		return nil, nil
+	}
+	f, err := os.CreateTemp("", "invowk-output-*")
+	if err != nil {
+		return nil, fmt.Errorf("create output file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name()) // Best-effort cleanup of the unusable file.
+		return nil, fmt.Errorf("create output file: %w", err)
+	}
+	if ctx.Env.ExtraEnv == nil {
+		ctx.Env.ExtraEnv = make(map[string]string)
+	}
+	ctx.Env.ExtraEnv[EnvVarOutputFile] = f.Name()
+	return &OutputFile{path: f.Name(), command: ctx.Command}, nil
+}
+
+// Collect parses the outputs written by a successful run into result.Outputs
+// and removes the file. A malformed file fails the result. Collect is a no-op
+// on a nil OutputFile.
+func (f *OutputFile) Collect(result *Result) *Result {
+	if f == nil {
+		return result
+	}
+	defer func() { _ = os.Remove(f.path) }() // Best-effort cleanup; the file is in the temp dir.
+	if result == nil || result.Error != nil || result.ExitCode != 0 {
+		return result
+	}
+	outputs, err := f.read()
+	if err != nil {
+		result.ExitCode = 1
+		result.Error = err
+		return result
+	}
+	result.Outputs = outputs
+	return result
+}
+
+func (f *OutputFile) read() (invowkfile.CommandOutputs, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("read output file: %w", err)
+	}
+	defer func() { _ = file.Close() }() // Read-only handle; close error is non-actionable.
+	data, err := io.ReadAll(io.LimitReader(file, maxOutputFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read output file: %w", err)
+	}
+	if len(data) > maxOutputFileSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidOutputFile, maxOutputFileSize)
+	}
+	return ParseOutputs(string(data), f.command)
+}
+
+// ParseOutputs parses the contents of an output file written by cmd. Each
+// output is either a name=value line or a name<<DELIMITER line followed by the
+// value lines and a line holding only DELIMITER. Blank lines are ignored, and
+// an output written twice keeps its last value.
+func ParseOutputs(data string, cmd *invowkfile.Command) (invowkfile.CommandOutputs, error) {
+	outputs := make(invowkfile.CommandOutputs)
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var name, value string
+		if before, delimiter, ok := strings.Cut(line, "<<"); ok && !strings.Contains(before, "=") {
+			name = before
+			end := i + 1
+			for end < len(lines) && lines[end] != delimiter {
+				end++
+			}
+			if delimiter == "" || end == len(lines) {
+				return nil, fmt.Errorf("%w: line %d: output '%s' is missing its closing delimiter %q", ErrInvalidOutputFile, i+1, name, delimiter)
+			}
+			value = strings.Join(lines[i+1:end], "\n")
+			i = end
+		} else if name, value, ok = strings.Cut(line, "="); !ok {
+			return nil, fmt.Errorf("%w: line %d: expected name=value or name<<DELIMITER", ErrInvalidOutputFile, i+1)
+		}
+
+		outputName := invowkfile.OutputName(name)
+		if err := outputName.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidOutputFile, i+1, err)
+		}
+		if !cmd.DeclaresOutput(outputName) {
+			return nil, fmt.Errorf("%w: line %d: '%s': %w", ErrInvalidOutputFile, i+1, name, invowkfile.ErrUndeclaredOutput)
+		}
+		outputs[outputName] = value
+	}
+	return outputs, nil
+}
+
+// FormatOutput returns the output file entry that sets name to value, using
+// the delimiter form when value spans several lines.
+func FormatOutput(name invowkfile.OutputName, value string) string {
+	if !strings.ContainsAny(value, "\r\n") {
+		return fmt.Sprintf("%s=%s\n", name, value)
+	}
+	delimiter := "INVOWK_EOF"
+	for strings.Contains(value, delimiter) {
+		delimiter += "_"
+	}
+	return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
+}
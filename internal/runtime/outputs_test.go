@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestParseOutputs(t *testing.T) {
+	t.Parallel()
+
+	cmd := &invowkfile.Command{Outputs: []invowkfile.CommandOutput{{Name: "version"}, {Name: "notes"}, {Name: "url"}}}
+	tests := []struct {
+		name    string
+		data    string
+		want    invowkfile.CommandOutputs
+		wantErr error
+	}{
+		{name: "empty", data: "", want: invowkfile.CommandOutputs{}},
+		{
+			name: "lines",
+			data: "version=1.0\r\n\nurl=https://example.com/?a=b\nversion=1.1\n",
+			want: invowkfile.CommandOutputs{"version": "1.1", "url": "https://example.com/?a=b"},
+		},
+		{
+			name: "delimited",
+			data: "notes<<EOF\nfirst\n\nlast\nEOF\nversion=2\n",
+			want: invowkfile.CommandOutputs{"notes": "first\n\nlast", "version": "2"},
+		},
+		{name: "empty value", data: "version=\n", want: invowkfile.CommandOutputs{"version": ""}},
+		{name: "missing delimiter", data: "notes<<EOF\nfirst\n", wantErr: ErrInvalidOutputFile},
+		{name: "no separator", data: "version\n", wantErr: ErrInvalidOutputFile},
+		{name: "invalid name", data: "1version=1\n", wantErr: invowkfile.ErrInvalidOutputName},
+		{name: "undeclared", data: "digest=abc\n", wantErr: invowkfile.ErrUndeclaredOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseOutputs(tt.data, cmd)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidOutputFile) {
+					t.Fatalf("ParseOutputs() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOutputs() error = %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ParseOutputs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOutputRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cmd := &invowkfile.Command{Outputs: []invowkfile.CommandOutput{{Name: "value"}}}
+	for _, value := range []string{"", "plain", "a=b", "two\nlines", "has\nINVOWK_EOF\ninside"} {
+		got, err := ParseOutputs(FormatOutput("value", value), cmd)
+		if err != nil {
+			t.Fatalf("ParseOutputs(FormatOutput(%q)) error = %v", value, err)
+		}
+		if got["value"] != value {
+			t.Errorf("round trip of %q = %q", value, got["value"])
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// RedactedSecret replaces resolved secret values in command output.
+const RedactedSecret = "********"
+
+type (
+	// SecretRedactor masks registered secret values. It is safe for concurrent
+	// use: values are registered while the environment is built and masked by
+	// the output writers of the running command. A nil SecretRedactor masks
+	// nothing.
+	SecretRedactor struct {
+		mu       sync.RWMutex
+		values   []string
+		replacer *strings.Replacer
+	}
+
+	// RedactingWriter masks secret values in a byte stream before forwarding
+	// it. A secret split across two writes is still masked: a trailing chunk
+	// that could be the start of a secret is held back until the next write
+	// or Flush.
+	RedactingWriter struct {
+		mu       sync.Mutex
+		dst      io.Writer
+		redactor *SecretRedactor
+		pending  []byte
+	}
+
+	// redactedError carries a masked error message while keeping the original
+	// error chain available to errors.Is and errors.As.
+	redactedError struct {
+		msg string
+		err error
+	}
+)
+
+// NewSecretRedactor creates an empty SecretRedactor.
+func NewSecretRedactor() *SecretRedactor {
+	return &SecretRedactor{}
+}
+
+// Add registers secret values to mask. Empty and already registered values
+// are ignored.
+func (r *SecretRedactor) Add(values ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, value := range values {
+		if value == "" || slices.Contains(r.values, value) {
+			continue
+		}
+		r.values = append(r.values, value)
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	// Longer values first, so a secret containing another one is masked whole.
+	slices.SortFunc(r.values, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	pairs := make([]string, 0, 2*len(r.values))
+	for _, value := range r.values {
+		pairs = append(pairs, value, RedactedSecret)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact returns s with every registered secret value masked.
+func (r *SecretRedactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// RedactError returns err with every registered secret value masked in its
+// message. The original error chain stays available to errors.Is and errors.As.
+func (r *SecretRedactor) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := r.Redact(err.Error()); msg != err.Error() {
+		return &redactedError{msg: msg, err: err}
+	}
+	return err
+}
+
+// Writer returns a RedactingWriter forwarding to dst.
+func (r *SecretRedactor) Writer(dst io.Writer) *RedactingWriter {
+	return &RedactingWriter{dst: dst, redactor: r}
+}
+
+// Write masks secret values in p and forwards the result to the underlying
+// writer. It always consumes all of p unless the underlying writer fails.
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	out, held := w.redactor.split(string(w.pending))
+	w.pending = append(w.pending[:0], held...)
+	if out == "" {
+		return len(p), nil
+	}
+	if _, err := io.WriteString(w.dst, out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush forwards any held-back output. It must be called once the stream ends.
+func (w *RedactingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		return nil
+	}
+	out := w.redactor.Redact(string(w.pending))
+	w.pending = w.pending[:0]
+	_, err := io.WriteString(w.dst, out)
+	return err
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// DeclaresSecrets reports whether the invowkfile, command, or selected
+// implementation declares env secrets.
+func (ctx *ExecutionContext) DeclaresSecrets() bool {
+	var implEnv *invowkfile.EnvConfig
+	if ctx.SelectedImpl != nil {
+		implEnv = ctx.SelectedImpl.Env
+	}
+	var cmdEnv *invowkfile.EnvConfig
+	if ctx.Command != nil {
+		cmdEnv = ctx.Command.Env
+	}
+	var rootEnv *invowkfile.EnvConfig
+	if ctx.Invowkfile != nil {
+		rootEnv = ctx.Invowkfile.Env
+	}
+	return len(invowkfile.SecretNames(rootEnv, cmdEnv, implEnv)) > 0
+}
+
+// redactOutput routes stdout and stderr through redacting writers and returns
+// a function that flushes them and restores the original streams. Streams are
+// only wrapped for commands declaring secrets: the wrapper turns a terminal
+// into a pipe for the child process.
+func (ctx *ExecutionContext) redactOutput() (restore func()) {
+	if ctx.Secrets == nil {
+		ctx.Secrets = NewSecretRedactor()
+	}
+	original := ctx.IO
+	var writers []*RedactingWriter
+	wrap := func(w io.Writer) io.Writer {
+		if w == nil {
+			return nil
+		}
+		rw := ctx.Secrets.Writer(w)
+		writers = append(writers, rw)
+		return rw
+	}
+	ctx.IO.Stdout = wrap(original.Stdout)
+	ctx.IO.Stderr = wrap(original.Stderr)
+	return func() {
+		for _, rw := range writers {
+			_ = rw.Flush() // Best effort: the stream may already be closed.
+		}
+		ctx.IO = original
+	}
+}
+
+// RedactResult masks secret values in the result error and diagnostics,
+// which the CLI prints (with the full error chain in verbose mode). Registry
+// Execute applies it; interactive executors, which bypass the registry, must
+// apply it to their results too.
+func (ctx *ExecutionContext) RedactResult(result *Result) *Result {
+	if result == nil {
+		return nil
+	}
+	result.Error = ctx.Secrets.RedactError(result.Error)
+	for i := range result.Diagnostics {
+		result.Diagnostics[i].Message = ctx.Secrets.Redact(result.Diagnostics[i].Message)
+	}
+	return result
+}
+
+// split masks complete secret values in s and returns the part that can be
+// written now and the trailing part that could still grow into a secret.
+func (r *SecretRedactor) split(s string) (out, held string) {
+	if r == nil {
+		return s, ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.replacer == nil {
+		return s, ""
+	}
+	s = r.replacer.Replace(s)
+	// r.values is sorted longest first.
+	for n := min(len(s), len(r.values[0])-1); n > 0; n-- {
+		suffix := s[len(s)-n:]
+		for _, value := range r.values {
+			if len(value) > n && strings.HasPrefix(value, suffix) {
+				return s[:len(s)-n], suffix
+			}
+		}
+	}
+	return s, ""
+}
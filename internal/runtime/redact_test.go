@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+var errSecretEcho = errors.New("secret echo failed")
+
+// secretEchoRuntime builds the environment and echoes TOKEN to stdout and
+// stderr in small chunks, like a streaming child process.
+type secretEchoRuntime struct {
+	mockRuntime
+}
+
+func (r *secretEchoRuntime) Execute(ctx *ExecutionContext) *Result {
+	env, err := NewDefaultEnvBuilder().Build(ctx, invowkfile.EnvInheritNone)
+	if err != nil {
+		return NewErrorResult(1, err)
+	}
+	line := "token=" + env["TOKEN"] + "\n"
+	for i := 0; i < len(line); i += 3 {
+		_, _ = fmt.Fprint(ctx.IO.Stdout, line[i:min(i+3, len(line))])
+	}
+	_, _ = fmt.Fprint(ctx.IO.Stderr, "tail "+env["TOKEN"][:4])
+	return &Result{
+		ExitCode:    1,
+		Error:       fmt.Errorf("%w: rejected %s", errSecretEcho, env["TOKEN"]),
+		Diagnostics: []InitDiagnostic{{Message: "used " + env["TOKEN"]}},
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		secrets []string
+		chunks  []string
+		want    string
+	}{
+		{name: "no secrets", chunks: []string{"plain ", "output"}, want: "plain output"},
+		{name: "whole secret", secrets: []string{"hunter2"}, chunks: []string{"pw=hunter2\n"}, want: "pw=********\n"},
+		{name: "split secret", secrets: []string{"hunter2"}, chunks: []string{"pw=hun", "te", "r2 done"}, want: "pw=******** done"},
+		{name: "prefix that is not a secret", secrets: []string{"hunter2"}, chunks: []string{"hun", "gry"}, want: "hungry"},
+		{name: "partial secret at end of stream", secrets: []string{"hunter2"}, chunks: []string{"x hunt"}, want: "x hunt"},
+		{name: "overlapping secrets mask the longest", secrets: []string{"abc", "abcdef"}, chunks: []string{"abcdef abc"}, want: "******** ********"},
+		{name: "repeated prefix", secrets: []string{"abab"}, chunks: []string{"xab", "ab", "ab"}, want: "x********ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			redactor := NewSecretRedactor()
+			redactor.Add(tt.secrets...)
+			var out bytes.Buffer
+			w := redactor.Writer(&out)
+			for _, chunk := range tt.chunks {
+				if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+					t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Fatalf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretRedactor_NilAndEmptyValues(t *testing.T) {
+	t.Parallel()
+
+	var nilRedactor *SecretRedactor
+	nilRedactor.Add("ignored")
+	if got := nilRedactor.Redact("ignored"); got != "ignored" {
+		t.Errorf("nil Redact() = %q, want input unchanged", got)
+	}
+
+	redactor := NewSecretRedactor()
+	redactor.Add("", "x")
+	if got := redactor.Redact("a x"); got != "a ********" {
+		t.Errorf("Redact() = %q, want %q", got, "a ********")
+	}
+}
+
+func TestRegistry_ExecuteRedactsSecretOutput(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "token"), []byte("s3cr3t-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(tmpDir, "invowkfile.cue"))}
+	cmd := testCommandWithScript("deploy", "echo deploy", invowkfile.RuntimeNative)
+	cmd.Env = &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+		"TOKEN": {Provider: invowkfile.SecretProviderFile, Path: "token"},
+	}}
+
+	reg := NewRegistry()
+	reg.Register(RuntimeTypeNative, &secretEchoRuntime{mockRuntime{name: "native", available: true}})
+	ctx := NewExecutionContext(t.Context(), cmd, inv)
+	var stdout, stderr bytes.Buffer
+	ctx.IO.Stdout = &stdout
+	ctx.IO.Stderr = &stderr
+
+	result := reg.Execute(ctx)
+	if !errors.Is(result.Error, errSecretEcho) || result.Error.Error() != "secret echo failed: rejected ********" {
+		t.Errorf("Execute() error = %v, want a masked error wrapping errSecretEcho", result.Error)
+	}
+	if got := result.Diagnostics[0].Message; got != "used ********" {
+		t.Errorf("diagnostic = %q, want the secret masked", got)
+	}
+	if got := stdout.String(); got != "token=********\n" {
+		t.Errorf("stdout = %q, want the secret masked", got)
+	}
+	if got := stderr.String(); got != "tail s3cr" {
+		t.Errorf("stderr = %q, want held-back output flushed", got)
+	}
+	if ctx.IO.Stdout != &stdout || ctx.IO.Stderr != &stderr {
+		t.Error("Execute() did not restore the original output streams")
+	}
+}
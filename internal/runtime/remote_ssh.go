@@ -0,0 +1,362 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	defaultSSHClient = "ssh"
+
+	// sshClientFailureExitCode is the exit code of the ssh client when the
+	// connection, authentication, or an ssh protocol step fails.
+	sshClientFailureExitCode types.ExitCode = 255
+
+	// remoteSSHUploadScript creates a private temporary directory on the
+	// remote host, stores stdin as the script, and prints the directory.
+	remoteSSHUploadScript = `umask 077 && d=$(mktemp -d "${TMPDIR:-/tmp}/invowk-XXXXXX") && cat > "$d/script" && printf '%s' "$d"`
+
+	// remoteSSHUploadLauncher stores stdin as the launcher in the directory
+	// passed as $1.
+	remoteSSHUploadLauncher = `umask 077 && cat > "$1/launch.sh"`
+
+	// remoteSSHRemoveDir removes the directory passed as $1 after a failed upload.
+	remoteSSHRemoveDir = `rm -rf "$1"`
+)
+
+var (
+	// ErrRemoteSSHHostRequired is returned when the selected remote-ssh
+	// runtime config declares no host.
+	ErrRemoteSSHHostRequired = errors.New("remote-ssh runtime requires host")
+
+	// ErrOutputsInRemoteSSH is returned when a command that declares outputs
+	// runs in the remote-ssh runtime, which cannot reach the local
+	// per-execution output file.
+	ErrOutputsInRemoteSSH = errors.New("commands that declare outputs cannot run in the remote-ssh runtime")
+
+	// remoteEnvNamePattern matches the environment variable names the
+	// launcher can export with a POSIX shell.
+	remoteEnvNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+type (
+	// RemoteSSHRuntime executes commands on another host through the system
+	// ssh client. Each run uploads the script and a launcher to a private
+	// temporary directory on the host; the launcher exports the command
+	// environment, enters the remote workdir, runs the script with the
+	// positional arguments, and removes the directory when the script exits.
+	// The exit code of the script is the exit code of the run.
+	RemoteSSHRuntime struct {
+		// sshClient is the ssh client binary
+		sshClient string
+		// sshClientArgs are extra ssh client arguments placed before the destination
+		sshClientArgs []string
+		// envBuilder builds environment variables for execution
+		envBuilder EnvBuilder
+	}
+
+	// RemoteSSHRuntimeOption configures a RemoteSSHRuntime.
+	RemoteSSHRuntimeOption func(*RemoteSSHRuntime)
+
+	// remoteSSHTarget holds the ssh client arguments that select and
+	// authenticate against the remote host of a run.
+	remoteSSHTarget struct {
+		args        []string
+		destination string
+	}
+)
+
+// WithSSHClient sets the ssh client binary for the remote-ssh runtime.
+// If not set, "ssh" is resolved from PATH.
+func WithSSHClient(path types.FilesystemPath) RemoteSSHRuntimeOption {
+	return func(r *RemoteSSHRuntime) {
+		r.sshClient = string(path)
+	}
+}
+
+// WithSSHClientArgs sets extra ssh client arguments (e.g., "-o", "BatchMode=yes")
+// passed to every ssh invocation before the destination.
+func WithSSHClientArgs(args []string) RemoteSSHRuntimeOption {
+	return func(r *RemoteSSHRuntime) {
+		r.sshClientArgs = slices.Clone(args)
+	}
+}
+
+// WithRemoteSSHEnvBuilder sets the environment builder for the remote-ssh runtime.
+// If not set, NewDefaultEnvBuilder() is used.
+func WithRemoteSSHEnvBuilder(b EnvBuilder) RemoteSSHRuntimeOption {
+	return func(r *RemoteSSHRuntime) {
+		r.envBuilder = b
+	}
+}
+
+// NewRemoteSSHRuntime creates a new remote-ssh runtime with optional configuration.
+func NewRemoteSSHRuntime(opts ...RemoteSSHRuntimeOption) *RemoteSSHRuntime {
+	r := &RemoteSSHRuntime{
+		sshClient:  defaultSSHClient,
+		envBuilder: NewDefaultEnvBuilder(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Name returns the runtime name
+func (r *RemoteSSHRuntime) Name() string {
+	return string(RuntimeTypeRemoteSSH)
+}
+
+// Available returns whether the ssh client can be found
+func (r *RemoteSSHRuntime) Available() bool {
+	_, err := exec.LookPath(r.sshClient)
+	return err == nil
+}
+
+// Validate checks if a command can be executed on the remote host
+func (r *RemoteSSHRuntime) Validate(ctx *ExecutionContext) error {
+	if err := validateExecutionContextForRun(ctx, errRemoteSSHNoImpl, errRemoteSSHNoScript); err != nil {
+		return err
+	}
+	_, err := remoteSSHRuntimeConfig(ctx)
+	return err
+}
+
+// Execute uploads the script to the remote host and streams its output
+func (r *RemoteSSHRuntime) Execute(ctx *ExecutionContext) *Result {
+	output := newStreamingOutput(ctx.IO.Stdout, ctx.IO.Stderr)
+	return r.executeCommon(ctx, output, nil, ctx.IO.Stdin)
+}
+
+// ExecuteCapture uploads the script to the remote host and captures its output
+func (r *RemoteSSHRuntime) ExecuteCapture(ctx *ExecutionContext) *Result {
+	output, captured := newCapturingOutput()
+	return r.executeCommon(ctx, output, captured, nil)
+}
+
+// SupportsInteractive returns true as the ssh client can allocate a remote PTY.
+func (r *RemoteSSHRuntime) SupportsInteractive() bool {
+	return true
+}
+
+// PrepareInteractive uploads the script and returns an ssh client command that
+// requests a remote PTY. The launcher removes the uploaded files when the
+// script exits, so no local cleanup is needed.
+func (r *RemoteSSHRuntime) PrepareInteractive(ctx *ExecutionContext) (*PreparedCommand, error) {
+	cmd, err := r.prepareRun(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedCommand{Cmd: cmd, Cleanup: nil}, nil
+}
+
+func (r *RemoteSSHRuntime) executeCommon(ctx *ExecutionContext, output *executeOutput, captured *capturedOutput, stdin io.Reader) *Result {
+	cmd, err := r.prepareRun(ctx, false)
+	if err != nil {
+		return NewErrorResult(1, err)
+	}
+	cmd.Stdout = output.stdout
+	cmd.Stderr = output.stderr
+	cmd.Stdin = stdin
+
+	err = cmd.Run()
+	result := extractExitCode(err, captured)
+	promoteContextError(ctx, result)
+	return result
+}
+
+// prepareRun uploads the script and launcher and returns the ssh client
+// command that runs the launcher. tty requests a remote PTY.
+func (r *RemoteSSHRuntime) prepareRun(ctx *ExecutionContext, tty bool) (*exec.Cmd, error) {
+	if err := validateExecutionContextForRun(ctx, errRemoteSSHNoImpl, errRemoteSSHNoScript); err != nil {
+		return nil, err
+	}
+	rtConfig, err := remoteSSHRuntimeConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Env.ExtraEnv[EnvVarOutputFile] != "" {
+		return nil, ErrOutputsInRemoteSSH
+	}
+
+	script, err := ctx.ResolveSelectedScript()
+	if err != nil {
+		return nil, err
+	}
+	env, err := r.envBuilder.Build(ctx, invowkfile.EnvInheritNone)
+	if err != nil {
+		return nil, fmt.Errorf(failedBuildEnvironmentFmt, err)
+	}
+	interpInfo := ctx.SelectedImpl.Script.ResolveInterpreterFromScript(script)
+	launcher := remoteSSHLauncher(env, rtConfig.WorkDir, interpInfo)
+
+	target := newRemoteSSHTarget(ctx, rtConfig)
+	dir, err := r.upload(ctx, target, script, launcher)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteCmd := append([]string{"sh", dir + "/launch.sh"}, ctx.PositionalArgs...)
+	cmd := r.sshCommand(ctx, target, tty, remoteCmd)
+	return cmd, nil
+}
+
+// upload stores the script and launcher in a new private directory on the
+// remote host and returns the directory.
+func (r *RemoteSSHRuntime) upload(ctx *ExecutionContext, target remoteSSHTarget, script, launcher string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := r.sshCommand(ctx, target, false, []string{"sh", "-c", remoteSSHUploadScript})
+	cmd.Stdin = strings.NewReader(script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", remoteSSHError("upload script to", target, err, &stderr)
+	}
+	dir := strings.TrimSpace(stdout.String())
+	if !strings.HasPrefix(dir, "/") || strings.ContainsAny(dir, "\n'") {
+		return "", fmt.Errorf("upload script to %s: unexpected remote directory %q", target.destination, dir)
+	}
+
+	stderr.Reset()
+	cmd = r.sshCommand(ctx, target, false, []string{"sh", "-c", remoteSSHUploadLauncher, "invowk", dir})
+	cmd.Stdin = strings.NewReader(launcher)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		cleanup := r.sshCommand(ctx, target, false, []string{"sh", "-c", remoteSSHRemoveDir, "invowk", dir})
+		_ = cleanup.Run() // Best-effort removal of the partial upload
+		return "", remoteSSHError("upload launcher to", target, err, &stderr)
+	}
+	return dir, nil
+}
+
+// sshCommand builds an ssh client command that runs remoteArgs on the target.
+// The remote command is sent as one shell-quoted string: the remote sshd
+// hands it to the login shell, which splits it back into remoteArgs.
+func (r *RemoteSSHRuntime) sshCommand(ctx *ExecutionContext, target remoteSSHTarget, tty bool, remoteArgs []string) *exec.Cmd {
+	args := slices.Clone(r.sshClientArgs)
+	if tty {
+		args = append(args, "-tt")
+	} else {
+		args = append(args, "-T")
+	}
+	args = append(args, target.args...)
+	args = append(args, "--", target.destination, remoteShellCommand(remoteArgs))
+
+	cmd := exec.CommandContext(ctx.Context, r.sshClient, args...)
+	cmd.WaitDelay = cmdWaitDelay
+	return cmd
+}
+
+// newRemoteSSHTarget returns the ssh client arguments selecting the configured host.
+func newRemoteSSHTarget(ctx *ExecutionContext, rtConfig *invowkfile.RuntimeConfig) remoteSSHTarget {
+	var args []string
+	if rtConfig.Port != 0 {
+		args = append(args, "-p", rtConfig.Port.String())
+	}
+	if rtConfig.User != "" {
+		args = append(args, "-l", rtConfig.User.String())
+	}
+	if rtConfig.IdentityFile != "" {
+		args = append(args, "-i", remoteSSHIdentityFile(ctx, rtConfig.IdentityFile), "-o", "IdentitiesOnly=yes")
+	}
+	return remoteSSHTarget{args: args, destination: rtConfig.Host.String()}
+}
+
+// remoteSSHRuntimeConfig returns the selected remote-ssh runtime config.
+func remoteSSHRuntimeConfig(ctx *ExecutionContext) (*invowkfile.RuntimeConfig, error) {
+	rtConfig := ctx.SelectedImpl.GetRuntimeConfig(invowkfile.RuntimeRemoteSSH)
+	if rtConfig == nil || rtConfig.Host == "" {
+		return nil, ErrRemoteSSHHostRequired
+	}
+	if err := rtConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return rtConfig, nil
+}
+
+// remoteSSHIdentityFile resolves a relative identity file against the
+// invowkfile directory. Paths starting with "~" are left to the ssh client.
+func remoteSSHIdentityFile(ctx *ExecutionContext, identityFile invowkfile.FilesystemPath) string {
+	path := filepath.FromSlash(string(identityFile))
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return path
+	}
+	return filepath.Join(filepath.Dir(string(ctx.Invowkfile.FilePath)), path)
+}
+
+// remoteSSHLauncher builds the POSIX shell launcher that exports env, enters
+// workDir, runs the uploaded script with the positional arguments, and
+// removes its own directory when the script exits.
+//
+//goplint:ignore -- launcher text is generated shell source for the remote host.
+func remoteSSHLauncher(env map[string]string, workDir invowkfile.WorkDir, interp invowkfile.ShebangInfo) string {
+	var sb strings.Builder
+	sb.WriteString("d=$(dirname \"$0\")\n")
+	sb.WriteString("trap 'rm -rf \"$d\"' EXIT\n")
+	sb.WriteString("trap 'exit 129' HUP\ntrap 'exit 130' INT\ntrap 'exit 143' TERM\n")
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if !remoteEnvNamePattern.MatchString(name) {
+			continue
+		}
+		fmt.Fprintf(&sb, "export %s=%s\n", name, shellQuote(env[name]))
+	}
+	if workDir != "" {
+		fmt.Fprintf(&sb, "cd %s || exit 1\n", shellQuote(string(workDir)))
+	}
+	if interp.Found {
+		parts := make([]string, 0, len(interp.Args)+1)
+		for _, part := range append([]string{interp.Interpreter}, interp.Args...) {
+			parts = append(parts, shellQuote(part))
+		}
+		fmt.Fprintf(&sb, "%s \"$d/script\" \"$@\"\n", strings.Join(parts, " "))
+	} else {
+		sb.WriteString("sh \"$d/script\" \"$@\"\n")
+	}
+	return sb.String()
+}
+
+// remoteShellCommand joins args into one shell-quoted command string.
+//
+//goplint:ignore -- ssh sends the remote command as shell source text.
+func remoteShellCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+//
+//goplint:ignore -- shell quoting operates on arbitrary script text.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// remoteSSHError describes a failed ssh invocation, including the ssh
+// client's stderr when it reported one.
+func remoteSSHError(action string, target remoteSSHTarget, err error, stderr *bytes.Buffer) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("%s %s: %w: %s", action, target.destination, err, msg)
+	}
+	return fmt.Errorf("%s %s: %w", action, target.destination, err)
+}
+
+// isRemoteSSHFailure reports whether a run in the remote-ssh runtime exited
+// with the ssh client's failure code rather than a code of the script.
+func isRemoteSSHFailure(ctx *ExecutionContext, code types.ExitCode) bool {
+	return ctx.SelectedRuntime == invowkfile.RuntimeRemoteSSH && code == sshClientFailureExitCode
+}
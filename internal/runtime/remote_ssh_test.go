@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/invowk/invowk/internal/sshserver"
+	"github.com/invowk/invowk/internal/testutil/invowkfiletest"
+	"github.com/invowk/invowk/pkg/invowkfile"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestRemoteSSHLauncher(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"B_VAR": "it's", "A_VAR": "one two", "BASH_FUNC_x%%": "() { :; }"}
+	launcher := remoteSSHLauncher(env, "/srv/app", invowkfile.ShebangInfo{})
+
+	for _, want := range []string{
+		"trap 'rm -rf \"$d\"' EXIT\n",
+		"export A_VAR='one two'\nexport B_VAR='it'\\''s'\n",
+		"cd '/srv/app' || exit 1\n",
+		"sh \"$d/script\" \"$@\"\n",
+	} {
+		if !strings.Contains(launcher, want) {
+			t.Errorf("launcher missing %q:\n%s", want, launcher)
+		}
+	}
+	if strings.Contains(launcher, "BASH_FUNC") {
+		t.Errorf("launcher exports a non-POSIX variable name:\n%s", launcher)
+	}
+
+	interp := invowkfile.ShebangInfo{Interpreter: "python3", Args: []string{"-u"}, Found: true}
+	launcher = remoteSSHLauncher(nil, "", interp)
+	if !strings.Contains(launcher, "'python3' '-u' \"$d/script\" \"$@\"\n") {
+		t.Errorf("launcher does not run the interpreter:\n%s", launcher)
+	}
+	if strings.Contains(launcher, "cd ") {
+		t.Errorf("launcher changes directory without a workdir:\n%s", launcher)
+	}
+}
+
+func TestRemoteShellCommand(t *testing.T) {
+	t.Parallel()
+
+	if goruntime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	args := []string{"printf", "%s|", "plain", "it's", "$HOME", "a b"}
+	out, err := exec.Command("sh", "-c", remoteShellCommand(args)).Output()
+	if err != nil {
+		t.Fatalf("sh -c remoteShellCommand() error = %v", err)
+	}
+	if got, want := string(out), "plain|it's|$HOME|a b|"; got != want {
+		t.Errorf("quoted command output = %q, want %q", got, want)
+	}
+}
+
+func TestRemoteSSHRuntimeValidate(t *testing.T) {
+	t.Parallel()
+
+	inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(t.TempDir(), "invowkfile.cue"))}
+	cmd := testCommandWithScript("deploy", "echo hi", invowkfile.RuntimeRemoteSSH)
+	ctx := NewExecutionContext(t.Context(), cmd, inv)
+
+	rt := NewRemoteSSHRuntime()
+	if err := rt.Validate(ctx); !errors.Is(err, ErrRemoteSSHHostRequired) {
+		t.Errorf("Validate() without host error = %v, want ErrRemoteSSHHostRequired", err)
+	}
+
+	ctx.SelectedImpl.Runtimes[0].Host = "build-01"
+	if err := rt.Validate(ctx); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	ctx.Env.ExtraEnv[EnvVarOutputFile] = "/tmp/outputs"
+	if result := rt.Execute(ctx); !errors.Is(result.Error, ErrOutputsInRemoteSSH) {
+		t.Errorf("Execute() with outputs error = %v, want ErrOutputsInRemoteSSH", result.Error)
+	}
+}
+
+func TestRemoteSSHTargetArgs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := &ExecutionContext{Invowkfile: &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(dir, "invowkfile.cue"))}}
+	target := newRemoteSSHTarget(ctx, &invowkfile.RuntimeConfig{
+		Name:         invowkfile.RuntimeRemoteSSH,
+		Host:         "build-01",
+		Port:         2222,
+		User:         "ops",
+		IdentityFile: "keys/id_ed25519",
+	})
+
+	want := []string{"-p", "2222", "-l", "ops", "-i", filepath.Join(dir, "keys", "id_ed25519"), "-o", "IdentitiesOnly=yes"}
+	if strings.Join(target.args, " ") != strings.Join(want, " ") || target.destination != "build-01" {
+		t.Errorf("target = %v %q, want %v %q", target.args, target.destination, want, "build-01")
+	}
+}
+
+func TestTranslateExitCodeKeepsSSHClientFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := &ExecutionContext{
+		SelectedRuntime: invowkfile.RuntimeRemoteSSH,
+		SelectedImpl:    &invowkfile.Implementation{ExitCodeMap: invowkfile.ExitCodeMap{255: 0}},
+	}
+	if got := TranslateExitCode(ctx, NewExitCodeResult(255)).ExitCode; got != 255 {
+		t.Errorf("TranslateExitCode() = %d, want ssh client failure code 255 kept", got)
+	}
+}
+
+// TestRemoteSSHRuntime_WishServer runs scripts through the system ssh client
+// against the Wish-based SSH server, which executes them on this machine.
+func TestRemoteSSHRuntime_WishServer(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	if goruntime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell on the SSH server")
+	}
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh client not available")
+	}
+
+	rt, port := startRemoteSSHTestServer(t)
+	tmpDir := t.TempDir()
+	inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(tmpDir, "invowkfile.cue"))}
+	newCtx := func(script string) *ExecutionContext {
+		cmd := invowkfiletest.NewTestCommand("deploy",
+			invowkfiletest.WithScript(script),
+			invowkfiletest.WithRuntimeConfig(invowkfile.RuntimeConfig{
+				Name:    invowkfile.RuntimeRemoteSSH,
+				Host:    "127.0.0.1",
+				Port:    port,
+				User:    "invowk",
+				WorkDir: invowkfile.WorkDir(tmpDir),
+			}),
+			invowkfiletest.WithAllPlatforms(),
+			invowkfiletest.WithEnv("GREETING", "it's remote"),
+		)
+		return NewExecutionContext(t.Context(), cmd, inv)
+	}
+
+	t.Run("streams output and propagates exit code", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := newCtx(`echo "$GREETING $1 $2"; pwd; echo err >&2; exit 7`)
+		ctx.PositionalArgs = []string{"a b", "c"}
+		var stdout, stderr bytes.Buffer
+		ctx.IO = IOContext{Stdout: &stdout, Stderr: &stderr, Stdin: strings.NewReader("")}
+
+		result := rt.Execute(ctx)
+		if result.Error != nil || result.ExitCode != 7 {
+			t.Fatalf("Execute() = exit %d, error %v (stderr %q), want exit 7", result.ExitCode, result.Error, stderr.String())
+		}
+		if got, want := stdout.String(), "it's remote a b c\n"+tmpDir+"\n"; got != want {
+			t.Errorf("stdout = %q, want %q", got, want)
+		}
+		if got := stderr.String(); got != "err\n" {
+			t.Errorf("stderr = %q, want %q", got, "err\n")
+		}
+	})
+
+	t.Run("captures interpreter script output and removes upload", func(t *testing.T) {
+		t.Parallel()
+
+		result := rt.ExecuteCapture(newCtx("#!/bin/sh -e\nprintf '%s' \"$(dirname \"$0\")\""))
+		if result.Error != nil || result.ExitCode != 0 {
+			t.Fatalf("ExecuteCapture() = exit %d, error %v (stderr %q)", result.ExitCode, result.Error, result.ErrOutput)
+		}
+		if !strings.Contains(result.Output, "invowk-") {
+			t.Fatalf("output = %q, want the upload directory", result.Output)
+		}
+		if _, err := os.Stat(result.Output); !os.IsNotExist(err) {
+			t.Errorf("upload directory %q still exists after the run (stat error %v)", result.Output, err)
+		}
+	})
+}
+
+// startRemoteSSHTestServer starts a Wish SSH server and returns a remote-ssh
+// runtime whose ssh client authenticates with a server token.
+func startRemoteSSHTestServer(t *testing.T) (*RemoteSSHRuntime, invowkfile.RemotePort) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := sshserver.DefaultConfig()
+	cfg.HostKeyPath = types.FilesystemPath(filepath.Join(dir, "id_ed25519"))
+	srv, err := sshserver.New(cfg)
+	if err != nil {
+		t.Fatalf("sshserver.New() error = %v", err)
+	}
+	if err = srv.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	info, err := srv.GetConnectionInfo("remote-ssh-test")
+	if err != nil {
+		t.Fatalf("GetConnectionInfo() error = %v", err)
+	}
+
+	askpass := filepath.Join(dir, "askpass.sh")
+	if err = os.WriteFile(askpass, []byte("#!/bin/sh\necho "+string(info.Token)+"\n"), 0o700); err != nil {
+		t.Fatalf("write askpass: %v", err)
+	}
+	client := filepath.Join(dir, "ssh.sh")
+	wrapper := "#!/bin/sh\nSSH_ASKPASS=" + askpass + " SSH_ASKPASS_REQUIRE=force DISPLAY=:0 exec ssh \"$@\"\n"
+	if err = os.WriteFile(client, []byte(wrapper), 0o700); err != nil {
+		t.Fatalf("write ssh wrapper: %v", err)
+	}
+
+	rt := NewRemoteSSHRuntime(
+		WithSSHClient(types.FilesystemPath(client)),
+		WithSSHClientArgs([]string{
+			"-o", "StrictHostKeyChecking=no",
+			"-o", "UserKnownHostsFile=/dev/null",
+			"-o", "PubkeyAuthentication=no",
+			"-o", "LogLevel=ERROR",
+		}),
+	)
+	return rt, invowkfile.RemotePort(info.Port)
+}
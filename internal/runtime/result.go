@@ -33,3 +33,19 @@ func NewSuccessResult() *Result {
 func NewExitCodeResult(code ExitCode) *Result {
 	return &Result{ExitCode: code}
 }
+
+// TranslateExitCode applies the success_exit_codes and exit_code_map of the
+// selected implementation to the exit code of a run that terminated normally.
+// Results carrying an error, container engine failures, and ssh client
+// failures keep their exit code, so translation never hides an infrastructure
+// failure.
+func TranslateExitCode(ctx *ExecutionContext, result *Result) *Result {
+	if result == nil || result.Error != nil || ctx.SelectedImpl == nil {
+		return result
+	}
+	if isContainerEngineFailure(ctx, result.ExitCode) || isRemoteSSHFailure(ctx, result.ExitCode) {
+		return result
+	}
+	result.ExitCode = ctx.SelectedImpl.TranslateExitCode(result.ExitCode)
+	return result
+}
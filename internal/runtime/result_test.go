@@ -5,6 +5,8 @@ package runtime
 import (
 	"errors"
 	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
 )
 
 func TestNewErrorResult(t *testing.T) {
@@ -83,3 +85,42 @@ func TestNewExitCodeResult(t *testing.T) {
 		t.Errorf("expected empty ErrOutput, got %q", result.ErrOutput)
 	}
 }
+
+func TestTranslateExitCode(t *testing.T) {
+	t.Parallel()
+
+	impl := &invowkfile.Implementation{
+		SuccessExitCodes: []ExitCode{1},
+		ExitCodeMap:      invowkfile.ExitCodeMap{2: 10, 125: 0},
+	}
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		runtime invowkfile.RuntimeMode
+		impl    *invowkfile.Implementation
+		result  *Result
+		want    ExitCode
+	}{
+		{name: "success exit code", runtime: invowkfile.RuntimeNative, impl: impl, result: NewExitCodeResult(1), want: 0},
+		{name: "mapped exit code", runtime: invowkfile.RuntimeVirtualSh, impl: impl, result: NewExitCodeResult(2), want: 10},
+		{name: "unmapped exit code", runtime: invowkfile.RuntimeNative, impl: impl, result: NewExitCodeResult(3), want: 3},
+		{name: "runtime error", runtime: invowkfile.RuntimeNative, impl: impl, result: NewErrorResult(1, errFailed), want: 1},
+		{name: "container engine failure", runtime: invowkfile.RuntimeContainer, impl: impl, result: NewExitCodeResult(125), want: 125},
+		{name: "native exit code 125", runtime: invowkfile.RuntimeNative, impl: impl, result: NewExitCodeResult(125), want: 0},
+		{name: "no implementation", runtime: invowkfile.RuntimeNative, result: NewExitCodeResult(1), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &ExecutionContext{SelectedRuntime: tt.runtime, SelectedImpl: tt.impl}
+			if got := TranslateExitCode(ctx, tt.result).ExitCode; got != tt.want {
+				t.Errorf("TranslateExitCode().ExitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if TranslateExitCode(&ExecutionContext{SelectedImpl: impl}, nil) != nil {
+		t.Error("TranslateExitCode(nil) != nil")
+	}
+}
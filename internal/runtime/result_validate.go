@@ -22,7 +22,7 @@ var (
 type (
 	// InvalidResultError is returned when a Result has invalid fields.
 	// It wraps ErrInvalidResult for errors.Is() compatibility and collects
-	// field-level validation errors from ExitCode and Diagnostics.
+	// field-level validation errors from ExitCode, Diagnostics, and Outputs.
 	InvalidResultError struct {
 		FieldErrors []error
 	}
@@ -67,7 +67,7 @@ func (e *InvalidExecutionContextError) Error() string {
 func (e *InvalidExecutionContextError) Unwrap() error { return ErrInvalidExecutionContext }
 
 // Validate returns nil if the Result has valid fields, or a validation error if not.
-// It delegates to ExitCode.Validate().
+// It delegates to ExitCode.Validate(), each Diagnostic, and Outputs.
 func (r Result) Validate() error {
 	var errs []error
 	if err := r.ExitCode.Validate(); err != nil {
@@ -78,6 +78,9 @@ func (r Result) Validate() error {
 			errs = append(errs, err)
 		}
 	}
+	if err := r.Outputs.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if len(errs) > 0 {
 		return &InvalidResultError{FieldErrors: errs}
 	}
@@ -23,10 +23,12 @@ import (
 
 // Runtime type constants for different execution environments.
 const (
-	RuntimeTypeNative     RuntimeType = "native"
-	RuntimeTypeVirtualSh  RuntimeType = "virtual-sh"
-	RuntimeTypeVirtualLua RuntimeType = "virtual-lua"
-	RuntimeTypeContainer  RuntimeType = "container"
+	RuntimeTypeNative          RuntimeType = "native"
+	RuntimeTypeVirtualSh       RuntimeType = "virtual-sh"
+	RuntimeTypeVirtualLua      RuntimeType = "virtual-lua"
+	RuntimeTypeVirtualStarlark RuntimeType = "virtual-starlark"
+	RuntimeTypeContainer       RuntimeType = "container"
+	RuntimeTypeRemoteSSH       RuntimeType = "remote-ssh"
 
 	// EnvVarCmdName is injected with the command name being executed.
 	EnvVarCmdName = "INVOWK_CMD_NAME"
@@ -36,6 +38,11 @@ const (
 	EnvVarSource = "INVOWK_SOURCE"
 	// EnvVarPlatform is injected with the resolved platform (linux, macos, windows).
 	EnvVarPlatform = "INVOWK_PLATFORM"
+	// EnvVarArch is injected with the host CPU architecture (amd64, arm64, ...).
+	EnvVarArch = "INVOWK_ARCH"
+	// EnvVarAttempt is injected with the 1-based attempt number of the run
+	// (greater than 1 when an implementation retry policy re-runs the script).
+	EnvVarAttempt = "INVOWK_ATTEMPT"
 )
 
 var (
@@ -166,6 +173,10 @@ type (
 		SelectedRuntime invowkfile.RuntimeMode
 		// SelectedPlatform is the platform used to resolve the selected implementation.
 		SelectedPlatform invowkfile.Platform
+		// SelectedArch is the CPU architecture used to resolve the selected
+		// implementation. It is not validated: invowk may run on architectures
+		// the invowkfile schema does not name.
+		SelectedArch invowkfile.Architecture
 		// SelectedImpl is the implementation to execute (based on platform and runtime)
 		SelectedImpl *invowkfile.Implementation
 		// PositionalArgs contains command-line arguments to pass as shell positional parameters ($1, $2, etc.)
@@ -191,6 +202,10 @@ type (
 		Env EnvContext
 		// TUI holds TUI server connection details
 		TUI TUIContext
+		// Secrets collects the secret values resolved while building the
+		// environment. Registry.Execute masks them in the command output and
+		// in the returned error and diagnostics.
+		Secrets *SecretRedactor
 	}
 
 	//goplint:validate-all
@@ -208,6 +223,9 @@ type (
 		ErrOutput string
 		// Diagnostics contains non-fatal runtime diagnostics for callers to render.
 		Diagnostics []InitDiagnostic
+		// Outputs contains the declared outputs the command wrote to its
+		// output file (see OutputFile).
+		Outputs invowkfile.CommandOutputs
 	}
 
 	// Runtime defines the interface for command execution.
@@ -294,7 +312,7 @@ type (
 
 // Error implements the error interface for InvalidRuntimeTypeError.
 func (e *InvalidRuntimeTypeError) Error() string {
-	return fmt.Sprintf("invalid runtime type %q (valid: native, virtual-sh, virtual-lua, container)", e.Value)
+	return fmt.Sprintf("invalid runtime type %q (valid: native, virtual-sh, virtual-lua, virtual-starlark, container, remote-ssh)", e.Value)
 }
 
 // Unwrap returns the sentinel error for errors.Is() compatibility.
@@ -309,7 +327,7 @@ func (rt RuntimeType) String() string { return string(rt) }
 // or a validation error if it is not.
 func (rt RuntimeType) Validate() error {
 	switch rt {
-	case RuntimeTypeNative, RuntimeTypeVirtualSh, RuntimeTypeVirtualLua, RuntimeTypeContainer:
+	case RuntimeTypeNative, RuntimeTypeVirtualSh, RuntimeTypeVirtualLua, RuntimeTypeVirtualStarlark, RuntimeTypeContainer, RuntimeTypeRemoteSSH:
 		return nil
 	default:
 		return &InvalidRuntimeTypeError{Value: rt}
@@ -470,10 +488,12 @@ func NewExecutionContext(ctx context.Context, cmd *invowkfile.Command, inv *invo
 		Context:          ctx,
 		SelectedRuntime:  defaultRuntime,
 		SelectedPlatform: currentPlatform,
+		SelectedArch:     invowkfile.CurrentArchitecture(),
 		SelectedImpl:     defaultImpl,
 		IO:               DefaultIO(),
 		Env:              DefaultEnv(),
 		// TUI: zero value is fine (not configured by default)
+		Secrets: NewSecretRedactor(),
 	}
 }
 
@@ -626,7 +646,13 @@ func (r *Registry) Execute(ctx *ExecutionContext) *Result {
 		return NewErrorResult(1, err)
 	}
 
-	return rt.Execute(ctx)
+	if !ctx.DeclaresSecrets() {
+		return rt.Execute(ctx)
+	}
+	restore := ctx.redactOutput()
+	result := rt.Execute(ctx)
+	restore()
+	return ctx.RedactResult(result)
 }
 
 // EnvToSlice converts a map of environment variables to a slice
@@ -681,6 +707,17 @@ func shouldFilterEnvVar(name string) bool {
 	if strings.HasPrefix(name, "INVOWK_FLAG_") {
 		return true
 	}
+	// Filter INVOWK_MATRIX_* so a nested invocation never sees the matrix
+	// combination of its caller as its own.
+	if strings.HasPrefix(name, invowkfile.MatrixEnvVarPrefix) {
+		return true
+	}
+	// Filter INVOWK_DEP_* and INVOWK_OUTPUT_FILE: outputs flow between the
+	// commands of one invocation only, and a nested invocation must not write
+	// to its caller's output file.
+	if strings.HasPrefix(name, invowkfile.DependencyOutputEnvVarPrefix) || name == EnvVarOutputFile {
+		return true
+	}
 
 	// Filter SSH credentials to prevent token leakage from container env
 	// to child processes or nested invocations (SC-04 defense-in-depth).
@@ -698,12 +735,13 @@ func shouldFilterEnvVar(name string) bool {
 
 	// Filter metadata env vars to prevent leakage between nested invocations.
 	// Each invocation gets fresh metadata from its own execution context.
-	// All four vars are unconditionally filtered here, even though EnvVarSource
-	// and EnvVarPlatform are conditionally injected in the app execution layer.
+	// All six vars are unconditionally filtered here, even though EnvVarSource,
+	// EnvVarPlatform, and EnvVarArch are conditionally injected in the app
+	// execution layer.
 	// The unconditional filtering is by design: it prevents leakage even if
 	// future code paths inject these vars unconditionally.
 	switch name {
-	case EnvVarCmdName, EnvVarRuntime, EnvVarSource, EnvVarPlatform:
+	case EnvVarCmdName, EnvVarRuntime, EnvVarSource, EnvVarPlatform, EnvVarArch, EnvVarAttempt:
 		return true
 	}
 
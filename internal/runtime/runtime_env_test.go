@@ -297,11 +297,21 @@ func TestShouldFilterEnvVar(t *testing.T) {
 		{"INVOWK_FLAG_V", true},
 		{"INVOWK_FLAG_", true},
 
+		// INVOWK_MATRIX_* cases
+		{"INVOWK_MATRIX_GO", true},
+		{"INVOWK_MATRIX_", true},
+
+		// Output cases
+		{"INVOWK_DEP_BUILD_VERSION", true},
+		{"INVOWK_OUTPUT_FILE", true},
+
 		// Metadata env vars (injected by projectEnvVars, constants from pkg/platform)
 		{"INVOWK_CMD_NAME", true},
 		{"INVOWK_RUNTIME", true},
 		{"INVOWK_SOURCE", true},
 		{"INVOWK_PLATFORM", true},
+		{"INVOWK_ARCH", true},
+		{"INVOWK_ATTEMPT", true},
 
 		// ARGC case
 		{"ARGC", true},
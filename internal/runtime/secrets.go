@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+// ErrKeyringNotSupported is returned by the keyring secret provider on
+// platforms without a supported keyring helper binary.
+var ErrKeyringNotSupported = errors.New("keyring secrets are not supported on this platform")
+
+type (
+	// SecretProvider resolves the value of a secret source at execution time.
+	// baseDir is the directory relative secret paths are resolved against
+	// (the invowkfile directory, or the module root for modules).
+	SecretProvider interface {
+		Resolve(ctx context.Context, source invowkfile.SecretSource, baseDir invowkfile.FilesystemPath) (string, error)
+	}
+
+	// CommandSecretProvider runs the source command and returns its stdout
+	// with one trailing newline trimmed.
+	CommandSecretProvider struct{}
+
+	// FileSecretProvider reads the source file and returns its contents with
+	// one trailing newline trimmed.
+	FileSecretProvider struct{}
+
+	//goplint:mutable
+	//
+	// EnvSecretProvider reads the source variable from the host environment.
+	// The lookup ignores env_inherit_mode: naming the variable in a secret
+	// source is an explicit opt-in.
+	EnvSecretProvider struct {
+		// Environ returns the host environment as "KEY=VALUE" strings.
+		// When nil, os.Environ() is used.
+		Environ func() []string
+	}
+
+	// KeyringSecretProvider reads the secret from the OS keyring through the
+	// platform helper binary: `secret-tool lookup` on Linux and
+	// `security find-generic-password` on macOS.
+	KeyringSecretProvider struct{}
+)
+
+// Resolve runs the source command in baseDir.
+func (CommandSecretProvider) Resolve(ctx context.Context, source invowkfile.SecretSource, baseDir invowkfile.FilesystemPath) (string, error) {
+	return runSecretCommand(ctx, source.Command, baseDir)
+}
+
+// Resolve reads the source file. Relative paths are resolved against baseDir.
+func (FileSecretProvider) Resolve(_ context.Context, source invowkfile.SecretSource, baseDir invowkfile.FilesystemPath) (string, error) {
+	content, err := os.ReadFile(string(invowkfile.ResolveInputPath(baseDir, string(source.Path))))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file '%s': %w", source.Path, err)
+	}
+	return trimSecretNewline(string(content)), nil
+}
+
+// Resolve looks up the source variable in the host environment.
+func (p EnvSecretProvider) Resolve(_ context.Context, source invowkfile.SecretSource, _ invowkfile.FilesystemPath) (string, error) {
+	environ := p.Environ
+	if environ == nil {
+		environ = os.Environ
+	}
+	value, ok := SliceToEnv(environ())[string(source.Var)]
+	if !ok {
+		return "", fmt.Errorf("host environment variable %s is not set", source.Var)
+	}
+	return value, nil
+}
+
+// Resolve runs the keyring helper of the current platform.
+func (KeyringSecretProvider) Resolve(ctx context.Context, source invowkfile.SecretSource, baseDir invowkfile.FilesystemPath) (string, error) {
+	argv, err := keyringCommand(runtime.GOOS, source.Service, source.Account)
+	if err != nil {
+		return "", err
+	}
+	return runSecretCommand(ctx, argv, baseDir)
+}
+
+// keyringCommand returns the helper invocation printing the keyring secret
+// identified by service and (optionally) account on goos.
+func keyringCommand(goos, service, account string) ([]string, error) {
+	switch goos {
+	case "linux":
+		argv := []string{"secret-tool", "lookup", "service", service}
+		if account != "" {
+			argv = append(argv, "account", account)
+		}
+		return argv, nil
+	case "darwin":
+		argv := []string{"security", "find-generic-password", "-s", service}
+		if account != "" {
+			argv = append(argv, "-a", account)
+		}
+		return append(argv, "-w"), nil
+	default:
+		return nil, fmt.Errorf("%w (%s)", ErrKeyringNotSupported, goos)
+	}
+}
+
+// runSecretCommand runs argv and returns its stdout. Stderr is only used in
+// the error message, so helper diagnostics stay visible without the value.
+func runSecretCommand(ctx context.Context, argv []string, dir invowkfile.FilesystemPath) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = string(dir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("secret command '%s' failed: %w: %s", argv[0], err, detail)
+		}
+		return "", fmt.Errorf("secret command '%s' failed: %w", argv[0], err)
+	}
+	return trimSecretNewline(stdout.String()), nil
+}
+
+// trimSecretNewline removes one trailing "\n" or "\r\n", as printed by
+// `pass show` or left by editors, but keeps any other whitespace.
+func trimSecretNewline(value string) string {
+	if trimmed, ok := strings.CutSuffix(value, "\n"); ok {
+		return strings.TrimSuffix(trimmed, "\r")
+	}
+	return value
+}
+
+// secretProvider returns the provider registered for typ, falling back to
+// the built-in provider.
+func (b *DefaultEnvBuilder) secretProvider(typ invowkfile.SecretProviderType) (SecretProvider, error) {
+	if provider, ok := b.SecretProviders[typ]; ok && provider != nil {
+		return provider, nil
+	}
+	switch typ {
+	case invowkfile.SecretProviderCommand:
+		return CommandSecretProvider{}, nil
+	case invowkfile.SecretProviderFile:
+		return FileSecretProvider{}, nil
+	case invowkfile.SecretProviderEnv:
+		return EnvSecretProvider{Environ: b.Environ}, nil
+	case invowkfile.SecretProviderKeyring:
+		return KeyringSecretProvider{}, nil
+	default:
+		return nil, typ.Validate()
+	}
+}
+
+// resolveSecrets resolves secrets into env in name order and registers the
+// values with the context's redactor so they are masked in output.
+func (b *DefaultEnvBuilder) resolveSecrets(ctx *ExecutionContext, env map[string]string, secrets map[invowkfile.EnvVarName]invowkfile.SecretSource, baseDir invowkfile.FilesystemPath) error {
+	for _, name := range slices.Sorted(maps.Keys(secrets)) {
+		source := secrets[name]
+		provider, err := b.secretProvider(source.Provider)
+		if err != nil {
+			return fmt.Errorf("secret %s: %w", name, err)
+		}
+		value, err := provider.Resolve(ctx.Context, source, baseDir)
+		if err != nil {
+			return fmt.Errorf("secret %s: %w", name, err)
+		}
+		ctx.Secrets.Add(value)
+		env[string(name)] = value
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+type fakeSecretProvider struct {
+	values map[string]string
+}
+
+func (p fakeSecretProvider) Resolve(_ context.Context, source invowkfile.SecretSource, _ invowkfile.FilesystemPath) (string, error) {
+	value, ok := p.values[source.Service]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func TestDefaultEnvBuilder_ResolvesSecrets(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "db.secret"), []byte("file-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inv := &invowkfile.Invowkfile{
+		FilePath: invowkfile.FilesystemPath(filepath.Join(tmpDir, "invowkfile.cue")),
+		Env: &invowkfile.EnvConfig{
+			Vars: map[invowkfile.EnvVarName]string{"LEVEL": "root-var"},
+			Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+				"DB_PASS":  {Provider: invowkfile.SecretProviderFile, Path: "db.secret"},
+				"OVERRIDE": {Provider: invowkfile.SecretProviderEnv, Var: "HOST_TOKEN"},
+			},
+		},
+	}
+	cmd := testCommandWithScript("deploy", "echo deploy", invowkfile.RuntimeNative)
+	cmd.Env = &invowkfile.EnvConfig{
+		Vars: map[invowkfile.EnvVarName]string{"OVERRIDE": "command-var"},
+		Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{
+			"SIGN_KEY": {Provider: invowkfile.SecretProviderKeyring, Service: "sign"},
+		},
+	}
+	ctx := NewExecutionContext(t.Context(), cmd, inv)
+	ctx.Env.RuntimeEnvVars = map[string]string{"DB_PASS": "cli-value"}
+
+	builder := &DefaultEnvBuilder{
+		Environ:         func() []string { return []string{"HOST_TOKEN=host-value"} },
+		SecretProviders: map[invowkfile.SecretProviderType]SecretProvider{invowkfile.SecretProviderKeyring: fakeSecretProvider{values: map[string]string{"sign": "keyring-value"}}},
+	}
+	env, err := builder.Build(ctx, invowkfile.EnvInheritNone)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := map[string]string{
+		"LEVEL":    "root-var",
+		"DB_PASS":  "cli-value",   // --ivk-env-var beats secrets
+		"OVERRIDE": "command-var", // command-level vars beat root-level secrets
+		"SIGN_KEY": "keyring-value",
+	}
+	for key, value := range want {
+		if env[key] != value {
+			t.Errorf("env[%s] = %q, want %q", key, env[key], value)
+		}
+	}
+	if got := ctx.Secrets.Redact("file-value host-value keyring-value root-var"); got != "******** ******** ******** root-var" {
+		t.Errorf("Redact() = %q, want the three resolved secrets masked", got)
+	}
+}
+
+func TestDefaultEnvBuilder_SecretErrors(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	tests := []struct {
+		name    string
+		source  invowkfile.SecretSource
+		wantErr string
+	}{
+		{name: "missing file", source: invowkfile.SecretSource{Provider: invowkfile.SecretProviderFile, Path: "missing"}, wantErr: "secret TOKEN: failed to read secret file 'missing'"},
+		{name: "unset host var", source: invowkfile.SecretSource{Provider: invowkfile.SecretProviderEnv, Var: "UNSET_TOKEN"}, wantErr: "secret TOKEN: host environment variable UNSET_TOKEN is not set"},
+		{name: "unknown provider", source: invowkfile.SecretSource{Provider: "vault"}, wantErr: "secret TOKEN: invalid secret provider"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			inv := &invowkfile.Invowkfile{FilePath: invowkfile.FilesystemPath(filepath.Join(tmpDir, "invowkfile.cue"))}
+			cmd := testCommandWithScript("deploy", "echo deploy", invowkfile.RuntimeNative)
+			cmd.Env = &invowkfile.EnvConfig{Secrets: map[invowkfile.EnvVarName]invowkfile.SecretSource{"TOKEN": tt.source}}
+			ctx := NewExecutionContext(t.Context(), cmd, inv)
+
+			builder := &DefaultEnvBuilder{Environ: func() []string { return nil }}
+			_, err := builder.Build(ctx, invowkfile.EnvInheritNone)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Build() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandSecretProvider(t *testing.T) {
+	t.Parallel()
+
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+	provider := CommandSecretProvider{}
+	got, err := provider.Resolve(t.Context(), invowkfile.SecretSource{
+		Provider: invowkfile.SecretProviderCommand,
+		Command:  []string{"/bin/sh", "-c", "printf 's3cret\\n'"},
+	}, invowkfile.FilesystemPath(t.TempDir()))
+	if err != nil || got != "s3cret" {
+		t.Fatalf("Resolve() = %q, %v; want %q", got, err, "s3cret")
+	}
+
+	_, err = provider.Resolve(t.Context(), invowkfile.SecretSource{
+		Provider: invowkfile.SecretProviderCommand,
+		Command:  []string{"/bin/sh", "-c", "echo 'entry not found' >&2; exit 1"},
+	}, invowkfile.FilesystemPath(t.TempDir()))
+	if err == nil || !strings.Contains(err.Error(), "entry not found") {
+		t.Fatalf("Resolve() error = %v, want stderr detail", err)
+	}
+}
+
+func TestKeyringCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		goos    string
+		account string
+		want    []string
+		wantErr bool
+	}{
+		{goos: "linux", want: []string{"secret-tool", "lookup", "service", "invowk"}},
+		{goos: "linux", account: "me", want: []string{"secret-tool", "lookup", "service", "invowk", "account", "me"}},
+		{goos: "darwin", account: "me", want: []string{"security", "find-generic-password", "-s", "invowk", "-a", "me", "-w"}},
+		{goos: "windows", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := keyringCommand(tt.goos, "invowk", tt.account)
+		if tt.wantErr {
+			if !errors.Is(err, ErrKeyringNotSupported) {
+				t.Errorf("keyringCommand(%s) error = %v, want ErrKeyringNotSupported", tt.goos, err)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("keyringCommand(%s, %q) = %q, %v; want %q", tt.goos, tt.account, got, err, tt.want)
+		}
+	}
+}
+
+func TestTrimSecretNewline(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		"token":       "token",
+		"token\n":     "token",
+		"token\r\n":   "token",
+		"token\n\n":   "token\n",
+		" token \n":   " token ",
+		"multi\nline": "multi\nline",
+	} {
+		if got := trimSecretNewline(input); got != want {
+			t.Errorf("trimSecretNewline(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
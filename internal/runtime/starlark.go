@@ -0,0 +1,567 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/invowk/invowk/internal/uroot"
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+const (
+	starlarkScriptName  = "script"
+	starlarkModuleExt   = ".star"
+	starlarkBridgeName  = "invowk"
+	starlarkCommandType = "invowk_command"
+	starlarkStateType   = "invowk_state"
+)
+
+// starlarkFileOptions enables the dialect features top-level scripts need
+// (control flow, while loops, global reassignment, sets). Recursion stays
+// disabled, as in Bazel.
+var starlarkFileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+type (
+	// StarlarkRuntime executes commands using the embedded go.starlark.net interpreter.
+	StarlarkRuntime struct {
+		//plint:internal -- required constructor param; immutable after construction
+		utilitiesEnabled bool
+		//plint:internal -- registry is built by the constructor when utilities are enabled
+		urootRegistry *uroot.Registry
+		//plint:internal -- field has WithStarlarkEnvBuilder(); field name doesn't match pattern
+		envBuilder EnvBuilder
+	}
+
+	// StarlarkRuntimeOption configures a StarlarkRuntime.
+	StarlarkRuntimeOption func(*StarlarkRuntime)
+
+	//goplint:ignore -- internal Starlark execution DTO carries already-resolved script/env/argv values through the interpreter bridge.
+	starlarkExecutionRequest struct {
+		script   string
+		maxSteps invowkfile.StarlarkMaxSteps
+		bridge   *starlarkBridge
+		args     []string
+	}
+
+	//goplint:ignore -- internal Starlark bridge DTO groups execution dependencies for the builtins of the invowk module.
+	starlarkBridge struct {
+		ctx              context.Context
+		policy           *virtualHostBinaryPolicy
+		registry         *uroot.Registry
+		pathResolver     virtualPathResolver
+		pathValidator    virtualPathValidator
+		env              map[string]string
+		envLists         map[string][]string
+		workDir          string
+		scriptBasePath   string
+		stdin            io.Reader
+		stdout           io.Writer
+		stderr           io.Writer
+		utilitiesEnabled bool
+	}
+
+	// starlarkCommandHelper implements invowk.cmd and invowk.capture. It can be
+	// called as invowk.cmd("go", "version") or through an attribute named after
+	// the binary, as invowk.cmd.go("version").
+	starlarkCommandHelper struct {
+		bridge  *starlarkBridge
+		capture bool
+	}
+
+	// starlarkState implements invowk.state, whose fields reflect runtime state
+	// updated by the command helpers.
+	starlarkState struct {
+		env map[string]string
+	}
+
+	// starlarkLoader implements load() for files beneath the script base path.
+	// Loaded files share the predeclared invowk module, and run on the loading
+	// thread so they count against the same execution step limit.
+	starlarkLoader struct {
+		scriptBasePath string
+		predeclared    starlark.StringDict
+		modules        map[string]*starlarkLoadedModule
+	}
+
+	starlarkLoadedModule struct {
+		globals starlark.StringDict
+		err     error
+	}
+)
+
+// WithStarlarkEnvBuilder sets the environment builder for the Starlark runtime.
+func WithStarlarkEnvBuilder(b EnvBuilder) StarlarkRuntimeOption {
+	return func(r *StarlarkRuntime) {
+		r.envBuilder = b
+	}
+}
+
+// NewStarlarkRuntime creates a virtual-starlark runtime.
+func NewStarlarkRuntime(utilitiesEnabled bool, opts ...StarlarkRuntimeOption) *StarlarkRuntime {
+	r := &StarlarkRuntime{
+		utilitiesEnabled: utilitiesEnabled,
+		envBuilder:       NewDefaultEnvBuilder(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.utilitiesEnabled && r.urootRegistry == nil {
+		r.urootRegistry = uroot.BuildDefaultRegistry()
+	}
+	return r
+}
+
+// Name returns the runtime name.
+func (r *StarlarkRuntime) Name() string { return RuntimeTypeVirtualStarlark.String() }
+
+// Available returns whether this runtime is available.
+func (r *StarlarkRuntime) Available() bool { return true }
+
+// Validate checks whether the selected implementation can run under virtual-starlark.
+func (r *StarlarkRuntime) Validate(ctx *ExecutionContext) error {
+	if ctx.SelectedImpl == nil {
+		return errVirtualNoImpl
+	}
+	if err := ctx.SelectedImpl.Script.Validate(); err != nil {
+		return errVirtualNoScript
+	}
+	script, err := ctx.ResolveSelectedScript()
+	if err != nil {
+		return err
+	}
+	if interpErr := validateStarlarkInterpreter(ctx.SelectedImpl.Script, script); interpErr != nil {
+		return interpErr
+	}
+	return compileStarlarkScript(script)
+}
+
+// Execute runs a command using the Starlark runtime.
+func (r *StarlarkRuntime) Execute(ctx *ExecutionContext) *Result {
+	return r.execute(ctx, ctx.IO.Stdout, ctx.IO.Stderr)
+}
+
+// ExecuteCapture runs a command using the Starlark runtime and captures stdout.
+func (r *StarlarkRuntime) ExecuteCapture(ctx *ExecutionContext) *Result {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	result := r.execute(ctx, &stdout, &stderr)
+	result.Output = stdout.String()
+	result.ErrOutput = stderr.String()
+	return result
+}
+
+func (r *StarlarkRuntime) execute(ctx *ExecutionContext, stdout, stderr io.Writer) *Result {
+	if err := validateExecutionContextForRun(ctx, errVirtualNoImpl, errVirtualNoScript); err != nil {
+		return NewErrorResult(1, err)
+	}
+	script, err := ctx.ResolveSelectedScript()
+	if err != nil {
+		return NewErrorResult(1, err)
+	}
+	if interpErr := validateStarlarkInterpreter(ctx.SelectedImpl.Script, script); interpErr != nil {
+		return NewErrorResult(1, interpErr)
+	}
+	env, err := r.envBuilder.Build(ctx, invowkfile.EnvInheritAll)
+	if err != nil {
+		return NewErrorResult(1, fmt.Errorf(failedBuildEnvironmentFmt, err))
+	}
+	pathResolver, err := newVirtualPathResolver(ctx)
+	if err != nil {
+		return NewErrorResult(1, err)
+	}
+	addVirtualRuntimeEnv(env, pathResolver)
+	ctx.AddTUIEnv(env)
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	var maxSteps invowkfile.StarlarkMaxSteps
+	if cfg := selectedRuntimeConfig(ctx); cfg != nil {
+		maxSteps = cfg.MaxSteps
+	}
+	return executeStarlarkScript(starlarkExecutionRequest{
+		script:   script,
+		maxSteps: maxSteps,
+		args:     ctx.PositionalArgs,
+		bridge: &starlarkBridge{
+			ctx:              ctx.Context,
+			policy:           hostBinaryPolicy(ctx, env),
+			registry:         r.urootRegistry,
+			pathResolver:     pathResolver,
+			pathValidator:    virtualPathValidator{resolver: pathResolver},
+			env:              env,
+			envLists:         luaEnvLists(ctx, env),
+			workDir:          ctx.EffectiveWorkDir(),
+			scriptBasePath:   string(ctx.Invowkfile.GetScriptBasePath()),
+			stdin:            ctx.IO.Stdin,
+			stdout:           stdout,
+			stderr:           stderr,
+			utilitiesEnabled: r.utilitiesEnabled,
+		},
+	})
+}
+
+func executeStarlarkScript(req starlarkExecutionRequest) *Result {
+	execCtx := req.bridge.ctx
+	if execCtx == nil {
+		execCtx = context.Background()
+	}
+	select {
+	case <-execCtx.Done():
+		return NewErrorResult(1, execCtx.Err())
+	default:
+	}
+
+	predeclared := starlark.StringDict{starlarkBridgeName: req.bridge.module(req.args)}
+	loader := &starlarkLoader{
+		scriptBasePath: req.bridge.scriptBasePath,
+		predeclared:    predeclared,
+		modules:        make(map[string]*starlarkLoadedModule),
+	}
+	stdout := req.bridge.stdout
+	thread := &starlark.Thread{
+		Name: starlarkScriptName,
+		Print: func(_ *starlark.Thread, msg string) {
+			_, _ = fmt.Fprintln(stdout, msg) // print has no way to report write errors.
+		},
+		Load: loader.load,
+	}
+	if req.maxSteps != 0 {
+		thread.SetMaxExecutionSteps(uint64(req.maxSteps))
+		thread.OnMaxSteps = func(t *starlark.Thread) {
+			t.Cancel(fmt.Sprintf("exceeded max_steps (%s)", req.maxSteps))
+		}
+	}
+	stop := context.AfterFunc(execCtx, func() {
+		thread.Cancel(execCtx.Err().Error())
+	})
+	defer stop()
+
+	if _, err := starlark.ExecFileOptions(starlarkFileOptions, thread, starlarkScriptName, req.script, predeclared); err != nil {
+		if evalErr, ok := errors.AsType[*starlark.EvalError](err); ok {
+			return NewErrorResult(1, fmt.Errorf("run starlark script: %s", evalErr.Backtrace()))
+		}
+		return NewErrorResult(1, fmt.Errorf("run starlark script: %w", err))
+	}
+	return NewSuccessResult()
+}
+
+func compileStarlarkScript(script string) error {
+	_, _, err := starlark.SourceProgramOptions(starlarkFileOptions, starlarkScriptName, script, isStarlarkPredeclared)
+	if err != nil {
+		return fmt.Errorf("compile starlark script: %w", err)
+	}
+	return nil
+}
+
+func isStarlarkPredeclared(name string) bool {
+	return name == starlarkBridgeName
+}
+
+func validateStarlarkInterpreter(script invowkfile.ImplementationScript, scriptContent string) error {
+	interpInfo := script.ResolveInterpreterFromScript(scriptContent)
+	if !interpInfo.Found || invowkfile.IsStarlarkInterpreter(interpInfo.Interpreter) {
+		return nil
+	}
+	return fmt.Errorf("%w (got %q); virtual-starlark can execute only Starlark scripts", invowkfile.ErrInterpreterNotAllowed, interpInfo.Interpreter)
+}
+
+// module builds the frozen invowk module predeclared in every script and
+// loaded file.
+func (b *starlarkBridge) module(args []string) *starlarkstruct.Module {
+	argValues := make(starlark.Tuple, len(args))
+	for i, arg := range args {
+		argValues[i] = starlark.String(arg)
+	}
+	module := &starlarkstruct.Module{
+		Name: starlarkBridgeName,
+		Members: starlark.StringDict{
+			"args":    argValues,
+			"env":     starlarkEnvDict(b.env, b.envLists),
+			"state":   &starlarkState{env: b.env},
+			"path":    starlark.NewBuiltin("invowk.path", b.pathFunc),
+			"output":  starlark.NewBuiltin("invowk.output", b.outputFunc),
+			"cmd":     &starlarkCommandHelper{bridge: b},
+			"capture": &starlarkCommandHelper{bridge: b, capture: true},
+		},
+	}
+	module.Freeze()
+	return module
+}
+
+func (b *starlarkBridge) pathFunc(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &path); err != nil {
+		return nil, fmt.Errorf("read invowk.path argument: %w", err)
+	}
+	resolved, err := b.pathResolver.resolveBridgePath(path, b.workDir)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(resolved), nil
+}
+
+// outputFunc implements invowk.output(name, value), which sets a declared
+// output of the command by appending it to the output file.
+func (b *starlarkBridge) outputFunc(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, value string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &name, &value); err != nil {
+		return nil, fmt.Errorf("read invowk.output arguments: %w", err)
+	}
+	path := b.env[EnvVarOutputFile]
+	if path == "" {
+		return nil, errors.New("invowk.output: the command declares no outputs")
+	}
+	outputName := invowkfile.OutputName(name)
+	if err := outputName.Validate(); err != nil {
+		return nil, fmt.Errorf("invowk.output: %w", err)
+	}
+	if err := appendOutput(path, outputName, value); err != nil {
+		return nil, fmt.Errorf("invowk.output: %w", err)
+	}
+	return starlark.None, nil
+}
+
+// runCommand runs a u-root utility when utilities are enabled, or an allowed
+// host binary otherwise. Failures that are not a command exit status are
+// reported on stderr, because the helpers only return the exit code.
+func (b *starlarkBridge) runCommand(args []string, stdout, stderr io.Writer) int {
+	var exitCode int
+	var err error
+	if b.hasUtility(args[0]) {
+		exitCode, err = runVirtualUtility(b.ctx, b.registry, virtualUtilityRunRequest{
+			args:          args,
+			pathValidator: b.pathValidator,
+			env:           b.env,
+			workDir:       b.workDir,
+			stdin:         b.stdin,
+			stdout:        stdout,
+			stderr:        stderr,
+		})
+	} else {
+		exitCode, err = runAllowedHostBinary(b.ctx, b.policy, args, b.env, b.workDir, stdout, stderr)
+	}
+	if _, isExit := errors.AsType[*exec.ExitError](err); err != nil && !isExit {
+		_, _ = fmt.Fprintf(stderr, "%s: %v\n", args[0], err) // The exit code already reports the failure.
+	}
+	return exitCode
+}
+
+func (b *starlarkBridge) hasUtility(name string) bool {
+	if !b.utilitiesEnabled || b.registry == nil {
+		return false
+	}
+	_, found := b.registry.Lookup(name)
+	return found
+}
+
+// String implements starlark.Value.
+func (h *starlarkCommandHelper) String() string { return "<" + h.Name() + ">" }
+
+// Type implements starlark.Value.
+func (h *starlarkCommandHelper) Type() string { return starlarkCommandType }
+
+// Freeze implements starlark.Value. The helper has no mutable state.
+func (h *starlarkCommandHelper) Freeze() {}
+
+// Truth implements starlark.Value.
+func (h *starlarkCommandHelper) Truth() starlark.Bool { return starlark.True }
+
+// Hash implements starlark.Value.
+func (h *starlarkCommandHelper) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: %s", starlarkCommandType)
+}
+
+// Name implements starlark.Callable.
+func (h *starlarkCommandHelper) Name() string {
+	if h.capture {
+		return "invowk.capture"
+	}
+	return "invowk.cmd"
+}
+
+// CallInternal implements starlark.Callable for invowk.cmd(name, *args).
+func (h *starlarkCommandHelper) CallInternal(_ *starlark.Thread, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: unexpected keyword arguments", h.Name())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s: %s", h.Name(), luaBinaryRequiredMsg)
+	}
+	return h.run(args)
+}
+
+// Attr implements starlark.HasAttrs for invowk.cmd.<name>(*args).
+func (h *starlarkCommandHelper) Attr(name string) (starlark.Value, error) {
+	return starlark.NewBuiltin(h.Name()+"."+name, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if len(kwargs) > 0 {
+			return nil, fmt.Errorf("%s.%s: unexpected keyword arguments", h.Name(), name)
+		}
+		return h.run(append(starlark.Tuple{starlark.String(name)}, args...))
+	}), nil
+}
+
+// AttrNames implements starlark.HasAttrs. Any binary name is a valid attribute.
+func (h *starlarkCommandHelper) AttrNames() []string { return nil }
+
+// run executes the command and returns its exit code, or a
+// (stdout, stderr, exit_code) tuple for invowk.capture.
+func (h *starlarkCommandHelper) run(values starlark.Tuple) (starlark.Value, error) {
+	args := make([]string, len(values))
+	for i, value := range values {
+		arg, ok := starlark.AsString(value)
+		if !ok {
+			return nil, fmt.Errorf("%s: command arguments must be strings, got %s", h.Name(), value.Type())
+		}
+		args[i] = arg
+	}
+	if !h.capture {
+		return starlark.MakeInt(h.bridge.runCommand(args, h.bridge.stdout, h.bridge.stderr)), nil
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := h.bridge.runCommand(args, &stdout, &stderr)
+	return starlark.Tuple{
+		starlark.String(stdout.String()),
+		starlark.String(stderr.String()),
+		starlark.MakeInt(exitCode),
+	}, nil
+}
+
+// String implements starlark.Value.
+func (s *starlarkState) String() string { return "<invowk.state>" }
+
+// Type implements starlark.Value.
+func (s *starlarkState) Type() string { return starlarkStateType }
+
+// Freeze implements starlark.Value. Scripts cannot modify the state.
+func (s *starlarkState) Freeze() {}
+
+// Truth implements starlark.Value.
+func (s *starlarkState) Truth() starlark.Bool { return starlark.True }
+
+// Hash implements starlark.Value.
+func (s *starlarkState) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: %s", starlarkStateType)
+}
+
+// Attr implements starlark.HasAttrs.
+func (s *starlarkState) Attr(name string) (starlark.Value, error) {
+	if name == "bin_path" {
+		return starlark.String(s.env[EnvVarStateBinPath]), nil
+	}
+	return nil, nil //nolint:nilnil // starlark.HasAttrs reports missing attributes as (nil, nil).
+}
+
+// AttrNames implements starlark.HasAttrs.
+func (s *starlarkState) AttrNames() []string { return []string{"bin_path"} }
+
+// starlarkEnvDict exposes env as a frozen dict in sorted key order. Keys
+// present in lists ("list" flags) map to tuples; every other key maps to a string.
+func starlarkEnvDict(env map[string]string, lists map[string][]string) *starlark.Dict {
+	dict := starlark.NewDict(len(env))
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		var value starlark.Value = starlark.String(env[name])
+		if values, ok := lists[name]; ok {
+			items := make(starlark.Tuple, len(values))
+			for i, item := range values {
+				items[i] = starlark.String(item)
+			}
+			value = items
+		}
+		_ = dict.SetKey(starlark.String(name), value) // Only fails for frozen dicts or unhashable keys.
+	}
+	dict.Freeze()
+	return dict
+}
+
+func (l *starlarkLoader) load(thread *starlark.Thread, module string) (starlark.StringDict, error) {
+	path, err := l.resolveModule(module)
+	if err != nil {
+		return nil, err
+	}
+	if loaded, ok := l.modules[path]; ok {
+		if loaded == nil {
+			return nil, fmt.Errorf("starlark load cycle through %q", module)
+		}
+		return loaded.globals, loaded.err
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read starlark module %q: %w", module, err)
+	}
+
+	l.modules[path] = nil
+	_, program, err := starlark.SourceProgramOptions(starlarkFileOptions, path, source, isStarlarkPredeclared)
+	var globals starlark.StringDict
+	if err == nil {
+		globals, err = program.Init(thread, l.predeclared)
+		globals.Freeze()
+	}
+	if err != nil {
+		err = fmt.Errorf("starlark module %q: %w", module, err)
+	}
+	l.modules[path] = &starlarkLoadedModule{globals: globals, err: err}
+	return globals, err
+}
+
+// resolveModule maps a load() label such as "lib/helpers.star" to a file
+// beneath the script base path, rejecting labels that escape it.
+func (l *starlarkLoader) resolveModule(module string) (string, error) {
+	if validateErr := validateStarlarkModuleName(module); validateErr != nil {
+		return "", validateErr
+	}
+	if strings.TrimSpace(l.scriptBasePath) == "" {
+		return "", errors.New("starlark load needs a script base path")
+	}
+	base, err := normalizeExistingOrParent(l.scriptBasePath, "")
+	if err != nil {
+		return "", err
+	}
+	normalized, err := normalizeExistingOrParent(filepath.Join(base, filepath.FromSlash(module)), base)
+	if err != nil {
+		return "", err
+	}
+	if !pathWithin(base, normalized) {
+		return "", fmt.Errorf("starlark load %q escapes script base path", module)
+	}
+	return normalized, nil
+}
+
+func validateStarlarkModuleName(module string) error {
+	if !strings.HasSuffix(module, starlarkModuleExt) {
+		return fmt.Errorf("invalid starlark module %q: must be a %s file", module, starlarkModuleExt)
+	}
+	if strings.ContainsAny(module, `\:`) || strings.HasPrefix(module, "/") {
+		return fmt.Errorf("invalid starlark module %q: must be a relative slash-separated path", module)
+	}
+	for part := range strings.SplitSeq(module, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid starlark module %q", module)
+		}
+	}
+	return nil
+}
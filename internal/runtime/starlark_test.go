@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package runtime
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkfile"
+)
+
+func TestStarlarkRuntimeExecuteBasicOutput(t *testing.T) {
+	t.Parallel()
+
+	ctx, stdout, _ := newStarlarkExecutionContext(t, `print("hello", "starlark")`, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	if got := stdout.String(); got != "hello starlark\n" {
+		t.Fatalf("stdout = %q, want hello starlark newline", got)
+	}
+}
+
+func TestStarlarkBridgePathAndEnv(t *testing.T) {
+	t.Parallel()
+
+	script := `
+print(invowk.path("@work"))
+print(invowk.env["FOO"])
+print(invowk.env.get("MISSING", "none"))
+`
+	env := map[invowkfile.EnvVarName]string{"FOO": "bar"}
+	ctx, stdout, _ := newStarlarkExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, env)
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+
+	resolver := mustVirtualTestResolver(t, ctx)
+	workDir := mustResolveVirtualBridgeTestPath(t, resolver, ctx.EffectiveWorkDir(), "@work")
+	want := workDir + "\nbar\nnone\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestStarlarkBridgeIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "module member", script: `invowk.path = None`},
+		{name: "env entry", script: `invowk.env["FOO"] = "changed"`},
+		{name: "state field", script: `invowk.state.bin_path = "changed"`},
+		{name: "cmd attribute", script: `invowk.cmd.anything = None`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, _, _ := newStarlarkExecutionContext(t, tt.script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+			result := NewStarlarkRuntime(false).Execute(ctx)
+			if result.Success() {
+				t.Fatalf("Execute() succeeded, want an error assigning to the invowk module")
+			}
+		})
+	}
+}
+
+func TestStarlarkBridgeEnvExposesListFlagsAsTuples(t *testing.T) {
+	t.Parallel()
+
+	script := `
+tags = invowk.env["INVOWK_FLAG_TAG"]
+print(type(tags), len(tags), tags[0], tags[1])
+hosts = invowk.env["INVOWK_FLAG_HOST"]
+print(type(hosts), len(hosts), hosts[0])
+print(invowk.env["INVOWK_FLAG_NAME"])
+`
+	ctx, stdout, _ := newStarlarkExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+	ctx.Command.Flags = []invowkfile.Flag{
+		{Name: "tag", Type: invowkfile.FlagTypeList},
+		{Name: "host", Type: invowkfile.FlagTypeList, ListFormat: invowkfile.ListFormatJSON},
+		{Name: "name"},
+	}
+	ctx.Env.ExtraEnv["INVOWK_FLAG_TAG"] = "v1\nlatest"
+	ctx.Env.ExtraEnv["INVOWK_FLAG_HOST"] = `["a.example"]`
+	ctx.Env.ExtraEnv["INVOWK_FLAG_NAME"] = "plain"
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+
+	want := "tuple 2 v1 latest\ntuple 1 a.example\nplain"
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestStarlarkBridgeOutputWritesDeclaredOutputs(t *testing.T) {
+	t.Parallel()
+
+	script := `
+invowk.output("version", "1.2.3")
+invowk.output("notes", "line 1\nline 2")
+`
+	ctx, _, _ := newStarlarkExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+	ctx.Command.Outputs = []invowkfile.CommandOutput{{Name: "version"}, {Name: "notes"}}
+	outputFile, err := NewOutputFile(ctx)
+	if err != nil {
+		t.Fatalf("NewOutputFile() error = %v", err)
+	}
+
+	result := outputFile.Collect(NewStarlarkRuntime(false).Execute(ctx))
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	want := invowkfile.CommandOutputs{"version": "1.2.3", "notes": "line 1\nline 2"}
+	if len(result.Outputs) != len(want) || result.Outputs["version"] != want["version"] || result.Outputs["notes"] != want["notes"] {
+		t.Fatalf("Outputs = %q, want %q", result.Outputs, want)
+	}
+}
+
+func TestStarlarkLoadReadsModuleLocalFileAndBlocksTraversal(t *testing.T) {
+	t.Parallel()
+
+	ctx, stdout, _ := newStarlarkExecutionContext(t, `
+load("helpers/format.star", "upper")
+print(upper("ok"))
+`, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+	helpersDir := filepath.Join(string(ctx.Invowkfile.GetScriptBasePath()), "helpers")
+	if err := os.MkdirAll(helpersDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll(helpers) error = %v", err)
+	}
+	helper := "def upper(value):\n    return value.upper()\n"
+	if err := os.WriteFile(filepath.Join(helpersDir, "format.star"), []byte(helper), 0o644); err != nil {
+		t.Fatalf("WriteFile(format.star) error = %v", err)
+	}
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	if got := stdout.String(); got != "OK\n" {
+		t.Fatalf("stdout = %q, want OK newline", got)
+	}
+
+	for _, module := range []string{"../outside.star", "/etc/passwd.star", "helpers/format.lua"} {
+		loader := &starlarkLoader{scriptBasePath: string(ctx.Invowkfile.GetScriptBasePath())}
+		if _, err := loader.resolveModule(module); err == nil {
+			t.Errorf("resolveModule(%q) succeeded, want error", module)
+		}
+	}
+}
+
+func TestStarlarkArgsAvailableAsTuple(t *testing.T) {
+	t.Parallel()
+
+	ctx, stdout, _ := newStarlarkExecutionContext(t, `print(":".join(invowk.args))`, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+	ctx.PositionalArgs = []string{"one", "two"}
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	if got := stdout.String(); got != "one:two\n" {
+		t.Fatalf("stdout = %q, want joined args", got)
+	}
+}
+
+func TestStarlarkBridgeVirtualUtilityCommandAndCapture(t *testing.T) {
+	t.Parallel()
+
+	script := `
+code = invowk.cmd.basename("a/b")
+out, err, capture_code = invowk.capture("basename", "c/d")
+print("cmd-code=%d" % code)
+print("cap=%s:%s:%d" % (out.strip(), err.strip(), capture_code))
+`
+	ctx, stdout, _ := newStarlarkExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+
+	result := NewStarlarkRuntime(true).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	want := "b\ncmd-code=0\ncap=d::0\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestStarlarkBridgeUtilitiesDisabledDeniesBuiltinWithoutHostAllow(t *testing.T) {
+	t.Parallel()
+
+	script := `
+out, err, code = invowk.capture.basename("a/b")
+print(code)
+`
+	ctx, stdout, _ := newStarlarkExecutionContext(t, script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if !result.Success() {
+		t.Fatalf("Execute() result = %#v, want success", result)
+	}
+	if got := stdout.String(); got != "126\n" {
+		t.Fatalf("stdout = %q, want denied exit status 126", got)
+	}
+}
+
+func TestStarlarkMaxStepsStopsExecution(t *testing.T) {
+	t.Parallel()
+
+	script := `
+n = 0
+while True:
+    n += 1
+`
+	cfg := invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark, MaxSteps: 1000}
+	ctx, _, _ := newStarlarkExecutionContext(t, script, cfg, nil)
+
+	result := NewStarlarkRuntime(false).Execute(ctx)
+	if result.Success() || result.Error == nil || !strings.Contains(result.Error.Error(), "exceeded max_steps (1000)") {
+		t.Fatalf("Execute() result = %#v, want a max_steps error", result)
+	}
+}
+
+func TestStarlarkRuntimeValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr bool
+	}{
+		{name: "valid script", script: `print(invowk.args)`},
+		{name: "syntax error", script: `print(`, wantErr: true},
+		{name: "undefined global", script: `print(os)`, wantErr: true},
+		{name: "foreign interpreter", script: "#!/usr/bin/env python3\nprint(1)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, _, _ := newStarlarkExecutionContext(t, tt.script, invowkfile.RuntimeConfig{Name: invowkfile.RuntimeVirtualStarlark}, nil)
+			err := NewStarlarkRuntime(false).Validate(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newStarlarkExecutionContext(
+	t testing.TB,
+	script string,
+	cfg invowkfile.RuntimeConfig,
+	envVars map[invowkfile.EnvVarName]string,
+) (ctx *ExecutionContext, stdout, stderr *bytes.Buffer) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	inv := &invowkfile.Invowkfile{
+		FilePath: invowkfile.FilesystemPath(filepath.Join(tmpDir, "invowkfile.cue")),
+	}
+	cmd := testCommandWithScript("starlark-test", script, invowkfile.RuntimeVirtualStarlark)
+	cmd.Implementations[0].Runtimes = []invowkfile.RuntimeConfig{cfg}
+	if envVars != nil {
+		cmd.Env = &invowkfile.EnvConfig{Vars: envVars}
+	}
+
+	ctx = NewExecutionContext(t.Context(), cmd, inv)
+	ctx.SelectedRuntime = invowkfile.RuntimeVirtualStarlark
+	ctx.SelectedImpl = &cmd.Implementations[0]
+	stdout = &bytes.Buffer{}
+	stderr = &bytes.Buffer{}
+	ctx.IO.Stdout = stdout
+	ctx.IO.Stderr = stderr
+	return ctx, stdout, stderr
+}
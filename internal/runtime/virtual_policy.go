@@ -87,7 +87,7 @@ func selectedVirtualFilesystem(ctx *ExecutionContext) invowkfile.VirtualFilesyst
 	if ctx.SelectedPlatform != "" {
 		platform = ctx.SelectedPlatform
 	}
-	return ctx.SelectedImpl.VirtualFilesystemForPlatform(platform)
+	return ctx.SelectedImpl.VirtualFilesystemForPlatform(platform, ctx.SelectedArch)
 }
 
 func newVirtualPathResolverForFilesystem(
@@ -298,6 +298,12 @@ func expandVirtualAnchorPath(path string, anchors map[string]string) (string, er
 
 func addVirtualRuntimeEnv(env map[string]string, resolver virtualPathResolver) {
 	env[EnvVarStateBinPath] = ""
+	addVirtualPathEnv(env, resolver)
+}
+
+// addVirtualPathEnv exposes the resolver's anchors and named paths as
+// INVOWK_ANCHOR_<NAME> and INVOWK_PATH_<NAME>.
+func addVirtualPathEnv(env map[string]string, resolver virtualPathResolver) {
 	for name, path := range resolver.anchors {
 		key := "INVOWK_ANCHOR_" + strings.ToUpper(strings.TrimPrefix(name, "@"))
 		env[key] = path
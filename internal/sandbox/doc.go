@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sandbox isolates native runtime processes on Linux without a
+// container engine.
+//
+// Wrap rewrites an exec.Cmd to start a helper (the hidden
+// `invowk internal exec-sandboxed` command) in new user, mount, PID, and
+// optionally network namespaces. The helper runs Init as PID 1 of the new PID
+// namespace: it remounts the root filesystem read-only, re-attaches the
+// writable paths read-write, mounts a fresh /proc, restricts writes to the
+// same paths with Landlock, and then runs the original command.
+//
+// Killing the helper kills every process left in the sandbox.
+package sandbox
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// SpecJSONFlag is the helper command flag that carries the JSON-encoded Spec.
+const SpecJSONFlag = "spec-json"
+
+var (
+	// ErrUnsupported is returned when sandboxing is requested on a platform other than Linux.
+	ErrUnsupported = errors.New("sandbox is only supported on Linux")
+
+	// ErrLandlockUnavailable is returned when filesystem restrictions are requested
+	// but the kernel does not support Landlock (Linux 5.13+ with Landlock enabled).
+	ErrLandlockUnavailable = errors.New("landlock is not available on this kernel")
+
+	// ErrInvalidSpec is the sentinel error wrapped by InvalidSpecError.
+	ErrInvalidSpec = errors.New("invalid sandbox spec")
+)
+
+type (
+	//goplint:validate-all
+	//
+	// Spec describes the isolation applied to a sandboxed process.
+	Spec struct {
+		// WritablePaths are the absolute paths the process may write beneath.
+		// Every other path is read-only. Paths that do not exist are ignored.
+		WritablePaths []types.FilesystemPath `json:"writable_paths,omitempty"`
+		// UnrestrictedFilesystem keeps the host filesystem writable and skips
+		// Landlock, leaving only namespace isolation.
+		UnrestrictedFilesystem bool `json:"unrestricted_filesystem,omitempty"`
+		// IsolateNetwork runs the process in a new network namespace that only
+		// has a loopback interface.
+		IsolateNetwork bool `json:"isolate_network,omitempty"`
+	}
+
+	// InvalidSpecError is returned when a Spec has invalid fields.
+	// It wraps ErrInvalidSpec for errors.Is() compatibility.
+	InvalidSpecError struct {
+		FieldErrors []error
+	}
+)
+
+// Validate returns nil if every writable path is a valid absolute path.
+func (s Spec) Validate() error {
+	var errs []error
+	for _, path := range s.WritablePaths {
+		if err := path.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !filepath.IsAbs(string(path)) {
+			errs = append(errs, fmt.Errorf("writable path %q must be absolute", path))
+		}
+	}
+	if len(errs) > 0 {
+		return &InvalidSpecError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// restrictsFilesystem reports whether the sandbox limits writes to WritablePaths.
+func (s Spec) restrictsFilesystem() bool {
+	if s.UnrestrictedFilesystem {
+		return false
+	}
+	for _, path := range s.WritablePaths {
+		if filepath.Clean(string(path)) == string(filepath.Separator) {
+			return false
+		}
+	}
+	return true
+}
+
+// Error implements the error interface for InvalidSpecError.
+func (e *InvalidSpecError) Error() string {
+	return types.FormatFieldErrors("sandbox spec", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidSpec for errors.Is() compatibility.
+func (e *InvalidSpecError) Unwrap() error {
+	return errors.Join(ErrInvalidSpec, errors.Join(e.FieldErrors...))
+}
@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/invowk/invowk/pkg/types"
+	"golang.org/x/sys/unix"
+)
+
+// landlockWriteAccessV1 lists the Landlock ABI v1 rights that modify the filesystem.
+// Read and execute rights are not handled, so the whole filesystem stays readable.
+const landlockWriteAccessV1 = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+	unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// deviceAccess is granted beneath /dev so scripts can still write to
+// /dev/null, terminals, and other device nodes opened inside the sandbox.
+const deviceAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+	unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+
+// Wrap rewrites cmd to run through helper inside the sandbox described by spec.
+// helper is the argv prefix of the command that calls Init, for example
+// [invowk, internal, exec-sandboxed]. cmd.Dir, cmd.Env, and stdio are kept.
+//
+//goplint:ignore -- helper is a raw argv prefix built from os.Executable by the caller.
+func Wrap(cmd *exec.Cmd, helper []string, spec Spec) error {
+	if len(helper) == 0 {
+		return errors.New("sandbox helper command is required")
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	if spec.restrictsFilesystem() {
+		if _, err := landlockABI(); err != nil {
+			return err
+		}
+	}
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("encode sandbox spec: %w", err)
+	}
+
+	args := slices.Concat(helper, []string{"--" + SpecJSONFlag, string(encoded), "--", cmd.Path}, cmd.Args[1:])
+	cmd.Path = helper[0]
+	cmd.Args = args
+
+	cloneFlags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID)
+	if spec.IsolateNetwork {
+		cloneFlags |= syscall.CLONE_NEWNET
+	}
+	// Map the caller to root of the new user namespace: a non-root ID loses
+	// its capabilities on exec, and Init needs CAP_SYS_ADMIN and
+	// CAP_NET_ADMIN there to set up mounts and the loopback interface.
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  cloneFlags,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+	}
+	return nil
+}
+
+// Init sets up the sandbox from inside the namespaces created by Wrap and runs
+// argv with the process stdio and environment. It must run as PID 1 of the new
+// PID namespace and returns the exit code of argv; a command killed by a
+// signal reports 128 plus the signal number.
+//
+//goplint:ignore -- argv is the raw command line forwarded by the helper command.
+func Init(spec Spec, argv []string) (types.ExitCode, error) {
+	if len(argv) == 0 {
+		return 1, errors.New("sandbox command is required")
+	}
+	if os.Getpid() != 1 {
+		return 1, errors.New("sandbox init must run as PID 1 of a new PID namespace")
+	}
+	if err := spec.Validate(); err != nil {
+		return 1, err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return 1, fmt.Errorf("get working directory: %w", err)
+	}
+	writable := writableRoots(spec.WritablePaths)
+	if err = setupMounts(writable, spec.restrictsFilesystem()); err != nil {
+		return 1, err
+	}
+	// Re-resolve the working directory so it refers to the remounted tree.
+	if err = os.Chdir(workDir); err != nil {
+		return 1, fmt.Errorf("change to working directory: %w", err)
+	}
+	if spec.IsolateNetwork {
+		if err = loopbackUp(); err != nil {
+			return 1, err
+		}
+	}
+
+	// Landlock and no_new_privs apply to the calling thread and are inherited
+	// by children forked from it, so the command must start on this thread.
+	runtime.LockOSThread()
+	if err = unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return 1, fmt.Errorf("set no_new_privs: %w", err)
+	}
+	if spec.restrictsFilesystem() {
+		if err = restrictWrites(writable); err != nil {
+			return 1, err
+		}
+	}
+
+	return run(argv)
+}
+
+// writableRoots returns the existing writable paths, dropping paths nested in
+// another writable path, ordered so that parents come before children.
+func writableRoots(paths []types.FilesystemPath) []string {
+	var roots []string
+	for _, path := range paths {
+		cleaned := filepath.Clean(string(path))
+		if _, err := os.Stat(cleaned); err != nil {
+			continue
+		}
+		roots = append(roots, cleaned)
+	}
+	slices.SortFunc(roots, func(a, b string) int { return len(a) - len(b) })
+	var kept []string
+	for _, root := range roots {
+		if !slices.ContainsFunc(kept, func(parent string) bool { return within(parent, root) }) {
+			kept = append(kept, root)
+		}
+	}
+	return kept
+}
+
+func within(parent, path string) bool {
+	rel, err := filepath.Rel(parent, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// setupMounts detaches the mount tree from the host, makes it read-only
+// except for the writable roots when readOnly is set, and mounts a /proc that
+// only shows the sandbox's processes.
+func setupMounts(writable []string, readOnly bool) error {
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private: %w", err)
+	}
+	if readOnly {
+		// Clone the writable trees before the root becomes read-only; the
+		// clones keep their read-write mount flags when attached back.
+		clones := make([]int, 0, len(writable))
+		defer func() {
+			for _, fd := range clones {
+				_ = unix.Close(fd)
+			}
+		}()
+		for _, root := range writable {
+			fd, err := unix.OpenTree(unix.AT_FDCWD, root, unix.OPEN_TREE_CLONE|unix.OPEN_TREE_CLOEXEC|unix.AT_RECURSIVE)
+			if err != nil {
+				return fmt.Errorf("clone mount tree for %s: %w", root, err)
+			}
+			clones = append(clones, fd)
+		}
+		if err := unix.MountSetattr(-1, "/", unix.AT_RECURSIVE, &unix.MountAttr{Attr_set: unix.MOUNT_ATTR_RDONLY}); err != nil {
+			return fmt.Errorf("make root read-only: %w", err)
+		}
+		for i, root := range writable {
+			if err := unix.MoveMount(clones[i], "", unix.AT_FDCWD, root, unix.MOVE_MOUNT_F_EMPTY_PATH); err != nil {
+				return fmt.Errorf("attach writable path %s: %w", root, err)
+			}
+		}
+	}
+	if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("mount /proc: %w", err)
+	}
+	return nil
+}
+
+// loopbackUp brings up the loopback interface of a new network namespace.
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("open control socket: %w", err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return fmt.Errorf("loopback interface: %w", err)
+	}
+	if err = unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("read loopback flags: %w", err)
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	if err = unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("bring up loopback: %w", err)
+	}
+	return nil
+}
+
+// landlockABI returns the Landlock ABI version supported by the kernel.
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, fmt.Errorf("%w: %w", ErrLandlockUnavailable, errno)
+	}
+	if int(abi) < 1 {
+		return 0, ErrLandlockUnavailable
+	}
+	return int(abi), nil
+}
+
+// handledWriteAccess returns the write rights known to the given Landlock ABI.
+func handledWriteAccess(abi int) uint64 {
+	access := uint64(landlockWriteAccessV1)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// restrictWrites enforces a Landlock domain on the calling thread that only
+// allows filesystem modifications beneath the writable roots and /dev. The
+// thread must already have no_new_privs set.
+func restrictWrites(writable []string) error {
+	abi, err := landlockABI()
+	if err != nil {
+		return err
+	}
+	handled := handledWriteAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	//nolint:gosec // G103: unsafe.Pointer required to pass the ruleset attribute to the syscall
+	rulesetFD, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("create landlock ruleset: %w", errno)
+	}
+	defer func() { _ = unix.Close(int(rulesetFD)) }()
+
+	for _, root := range writable {
+		if err = addPathRule(int(rulesetFD), root, handled); err != nil {
+			return err
+		}
+	}
+	if err = addPathRule(int(rulesetFD), "/dev", handled&deviceAccess); err != nil {
+		return err
+	}
+
+	if _, _, errno = unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, rulesetFD, 0, 0); errno != 0 {
+		return fmt.Errorf("enforce landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// addPathRule grants access beneath path. Rights that only apply to
+// directories are dropped for regular files, which Landlock rejects.
+func addPathRule(rulesetFD int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("open %s for landlock rule: %w", path, err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	var stat unix.Stat_t
+	if err = unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("stat %s for landlock rule: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)} //nolint:gosec // G115: file descriptors fit in int32
+	//nolint:gosec // G103: unsafe.Pointer required to pass the rule attribute to the syscall
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFD), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("add landlock rule for %s: %w", path, errno)
+	}
+	return nil
+}
+
+// run starts argv and waits for it, forwarding termination signals. SIGINT is
+// not forwarded: terminals deliver it to the whole foreground process group,
+// which already includes the command.
+func run(argv []string) (types.ExitCode, error) {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, unix.SIGINT, unix.SIGTERM, unix.SIGHUP, unix.SIGQUIT, unix.SIGUSR1, unix.SIGUSR2)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return 127, err
+	}
+	go func() {
+		for sig := range signals {
+			if sig != unix.SIGINT {
+				_ = cmd.Process.Signal(sig)
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	if err == nil {
+		return 0, nil
+	}
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return types.ExitCode(128 + int(status.Signal())), nil
+		}
+		return types.ExitCode(exitErr.ExitCode()), nil
+	}
+	return 1, err
+}
@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// sandboxHelperEnv makes the test binary act as the sandbox helper command.
+	sandboxHelperEnv = "INVOWK_SANDBOX_TEST_HELPER"
+	// unprivilegedID is the user and group ID of the unprivileged test run.
+	unprivilegedID = 65534
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(sandboxHelperEnv) == "1" {
+		os.Exit(runSandboxHelper(os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// runSandboxHelper mirrors `invowk internal exec-sandboxed`:
+// --spec-json <json> -- <argv...>.
+func runSandboxHelper(args []string) int {
+	dash := slices.Index(args, "--")
+	if len(args) < 3 || args[0] != "--"+SpecJSONFlag || dash != 2 {
+		fmt.Fprintf(os.Stderr, "unexpected helper args: %q\n", args)
+		return 1
+	}
+	var spec Spec
+	if err := json.Unmarshal([]byte(args[1]), &spec); err != nil {
+		fmt.Fprintf(os.Stderr, "decode spec: %v\n", err)
+		return 1
+	}
+	code, err := Init(spec, args[dash+1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+	}
+	return int(code)
+}
+
+func TestWrapRewritesCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command("/bin/sh", "-c", "exit 0", "arg")
+	cmd.Dir = "/work"
+	spec := Spec{WritablePaths: []types.FilesystemPath{"/work"}, IsolateNetwork: true}
+	if err := Wrap(cmd, []string{"/usr/bin/invowk", "internal", "exec-sandboxed"}, spec); err != nil {
+		if errors.Is(err, ErrLandlockUnavailable) {
+			t.Skip(err)
+		}
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	want := []string{
+		"/usr/bin/invowk", "internal", "exec-sandboxed",
+		"--spec-json", `{"writable_paths":["/work"],"isolate_network":true}`,
+		"--", "/bin/sh", "-c", "exit 0", "arg",
+	}
+	if cmd.Path != "/usr/bin/invowk" || !slices.Equal(cmd.Args, want) {
+		t.Errorf("wrapped command = %q %q, want %q", cmd.Path, cmd.Args, want)
+	}
+	if cmd.Dir != "/work" {
+		t.Errorf("Dir = %q, want it kept", cmd.Dir)
+	}
+	if cmd.SysProcAttr == nil || cmd.SysProcAttr.Cloneflags == 0 || len(cmd.SysProcAttr.UidMappings) != 1 {
+		t.Errorf("SysProcAttr = %+v, want namespace clone flags and id mappings", cmd.SysProcAttr)
+	}
+}
+
+func TestWrapRejectsRelativeWritablePath(t *testing.T) {
+	t.Parallel()
+
+	err := Wrap(exec.Command("/bin/true"), []string{"invowk"}, Spec{WritablePaths: []types.FilesystemPath{"work"}})
+	if !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("Wrap() error = %v, want ErrInvalidSpec", err)
+	}
+}
+
+func TestWritableRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "nested")
+	if err := os.Mkdir(nested, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	got := writableRoots([]types.FilesystemPath{
+		types.FilesystemPath(nested),
+		types.FilesystemPath(filepath.Join(dir, "missing")),
+		types.FilesystemPath(dir + "/"),
+	})
+	if !slices.Equal(got, []string{dir}) {
+		t.Errorf("writableRoots() = %q, want only %q", got, dir)
+	}
+}
+
+func TestInitRequiresPIDNamespace(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Init(Spec{}, []string{"/bin/true"}); err == nil {
+		t.Error("Init() outside a PID namespace succeeded, want error")
+	}
+}
+
+func TestSandboxIsolation(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	if _, err := landlockABI(); err != nil {
+		t.Skip(err)
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	writable := t.TempDir()
+	readOnly := t.TempDir()
+	run := func(t *testing.T, spec Spec, script string) (string, int) {
+		t.Helper()
+		cmd := exec.Command("/bin/sh", "-c", script)
+		cmd.Dir = writable
+		cmd.Env = append(os.Environ(), sandboxHelperEnv+"=1", "W="+writable, "R="+readOnly)
+		if err := Wrap(cmd, []string{os.Args[0]}, spec); err != nil {
+			t.Fatalf("Wrap() error = %v", err)
+		}
+		out, err := cmd.CombinedOutput()
+		if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+			if strings.Contains(string(out), "sandbox:") {
+				t.Fatalf("sandbox setup failed: %s", out)
+			}
+			return string(out), exitErr.ExitCode()
+		}
+		if err != nil {
+			t.Skipf("cannot create namespaces in this environment: %v", err)
+		}
+		return string(out), 0
+	}
+
+	t.Run("restricts writes to writable paths", func(t *testing.T) {
+		out, code := run(t, Spec{WritablePaths: []types.FilesystemPath{types.FilesystemPath(writable)}}, `
+			echo ok > "$W/file" && cat "$W/file"
+			{ echo no > "$R/file"; } 2>/dev/null || echo denied
+			echo $PPID
+			exit 3`)
+		if code != 3 {
+			t.Fatalf("exit code = %d, want 3 (output %q)", code, out)
+		}
+		if want := "ok\ndenied\n1\n"; out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+		if _, err := os.Stat(filepath.Join(readOnly, "file")); !os.IsNotExist(err) {
+			t.Errorf("file outside writable paths was created (stat error %v)", err)
+		}
+	})
+
+	t.Run("unrestricted filesystem keeps host writable", func(t *testing.T) {
+		out, code := run(t, Spec{UnrestrictedFilesystem: true}, `echo ok > "$R/unrestricted"`)
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0 (output %q)", code, out)
+		}
+	})
+
+	t.Run("isolates network", func(t *testing.T) {
+		out, code := run(t, Spec{UnrestrictedFilesystem: true, IsolateNetwork: true}, `tail -n +3 /proc/net/dev | cut -d: -f1`)
+		if code != 0 {
+			t.Fatalf("exit code = %d, want 0 (output %q)", code, out)
+		}
+		if got := strings.Fields(out); !slices.Equal(got, []string{"lo"}) {
+			t.Errorf("network interfaces = %q, want only lo", got)
+		}
+	})
+}
+
+// TestSandboxIsolationUnprivileged reruns TestSandboxIsolation as a non-root
+// user when the tests run as root, so the user namespace setup is exercised
+// without host capabilities.
+func TestSandboxIsolationUnprivileged(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	if os.Geteuid() != 0 {
+		t.Skip("TestSandboxIsolation already runs unprivileged")
+	}
+	if _, err := landlockABI(); err != nil {
+		t.Skip(err)
+	}
+
+	// The unprivileged run needs its own copy of the test binary and a
+	// temporary directory it can write to; t.TempDir only grants the owner.
+	dir := t.TempDir()
+	//nolint:gosec // G302: the directories must be reachable by the unprivileged user
+	for _, chmod := range []struct {
+		path string
+		mode os.FileMode
+	}{{filepath.Dir(dir), 0o755}, {dir, 0o777}} {
+		if err := os.Chmod(chmod.path, chmod.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	binary, err := os.ReadFile(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBinary := filepath.Join(dir, "sandbox.test")
+	//nolint:gosec // G306: the test binary must be executable by the unprivileged user
+	if err = os.WriteFile(testBinary, binary, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testBinary, "-test.run=^TestSandboxIsolation$", "-test.v")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TMPDIR="+dir)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: unprivilegedID, Gid: unprivilegedID}}
+	out, err := cmd.CombinedOutput()
+	if errors.Is(err, fs.ErrPermission) {
+		t.Skipf("temporary directory is not reachable by an unprivileged user: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("unprivileged TestSandboxIsolation failed: %v\n%s", err, out)
+	}
+	if strings.Contains(string(out), "--- SKIP") {
+		t.Skipf("unprivileged TestSandboxIsolation skipped:\n%s", out)
+	}
+}
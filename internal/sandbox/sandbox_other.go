@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package sandbox
+
+import (
+	"os/exec"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// Wrap returns ErrUnsupported on non-Linux platforms.
+//
+//goplint:ignore -- helper is a raw argv prefix built from os.Executable by the caller.
+func Wrap(_ *exec.Cmd, _ []string, _ Spec) error {
+	return ErrUnsupported
+}
+
+// Init returns ErrUnsupported on non-Linux platforms.
+//
+//goplint:ignore -- argv is the raw command line forwarded by the helper command.
+func Init(_ Spec, _ []string) (types.ExitCode, error) {
+	return 1, ErrUnsupported
+}
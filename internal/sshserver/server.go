@@ -98,6 +98,9 @@ type (
 		ShutdownTimeout time.Duration
 		// DefaultShell is the shell to use (default: /bin/sh)
 		DefaultShell types.ShellPath
+		// HostKeyPath is where the host key is loaded from or generated
+		// (default: id_ed25519 in the working directory)
+		HostKeyPath types.FilesystemPath
 		// StartupTimeout is the max time to wait for server to be ready (default: 5s)
 		StartupTimeout time.Duration
 	}
@@ -141,6 +144,11 @@ func (c Config) Validate() error {
 	if err := c.DefaultShell.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if c.HostKeyPath != "" {
+		if err := c.HostKeyPath.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if len(errs) > 0 {
 		return &InvalidSSHConfigError{FieldErrors: errs}
 	}
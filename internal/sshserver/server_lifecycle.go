@@ -67,6 +67,9 @@ func (s *Server) Start(ctx context.Context) error {
 			s.commandMiddleware(), //nolint:contextcheck // Wish injects request context into sessions handled by middleware.
 		),
 	}
+	if s.cfg.HostKeyPath != "" {
+		wishOptions = append(wishOptions, wish.WithHostKeyPath(string(s.cfg.HostKeyPath)))
+	}
 	wishOptions = append(wishOptions, s.wishOptions...)
 	srv, err := wish.NewServer(wishOptions...)
 	if err != nil {
@@ -406,6 +406,16 @@ func TestSSHConfig_Validate(t *testing.T) {
 			},
 			false, true, 1,
 		},
+		{
+			"invalid host key path (whitespace-only)",
+			Config{
+				Host:         HostAddress("127.0.0.1"),
+				Port:         ListenPort(22),
+				DefaultShell: types.ShellPath("/bin/sh"),
+				HostKeyPath:  types.FilesystemPath(" "),
+			},
+			false, true, 1,
+		},
 		{
 			"multiple invalid fields",
 			Config{
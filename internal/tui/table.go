@@ -342,31 +342,7 @@ func newTableModelWithStyles(opts TableOptions, forModal bool) *tableModel {
 		}
 	}
 
-	// Build columns
-	columns := make([]table.Column, len(opts.Columns))
-	for i, col := range opts.Columns {
-		colWidth := int(col.Width)
-		if colWidth == 0 {
-			// Auto-calculate width based on content
-			colWidth = len(col.Title)
-			for _, row := range opts.Rows {
-				if i < len(row) && len(row[i]) > colWidth {
-					colWidth = len(row[i])
-				}
-			}
-			colWidth += 2 // Add padding
-		}
-		columns[i] = table.Column{
-			Title: col.Title,
-			Width: colWidth,
-		}
-	}
-
-	// Build rows
-	rows := make([]table.Row, len(opts.Rows))
-	for i, row := range opts.Rows {
-		rows[i] = row
-	}
+	columns, rows := tableColumnsAndRows(opts)
 
 	tableHeight := int(opts.Height)
 	if tableHeight == 0 {
@@ -408,16 +384,7 @@ func newTableModelWithStyles(opts TableOptions, forModal bool) *tableModel {
 		// Cell style - explicit background
 		s.Cell = base.Foreground(white)
 	} else {
-		// Default styles
-		s.Header = s.Header.
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			BorderBottom(true).
-			Bold(true)
-		s.Selected = s.Selected.
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57")).
-			Bold(false)
+		s = defaultTableStyles()
 	}
 
 	t.SetStyles(s)
@@ -433,3 +400,75 @@ func newTableModelWithStyles(opts TableOptions, forModal bool) *tableModel {
 		height: TerminalDimension(tableHeight),
 	}
 }
+
+// RenderTable renders every row of a table with the default table styles and
+// no row selection, for output that is printed once, such as a summary.
+// Height, Selectable, and SelectedIndex are ignored.
+func RenderTable(opts TableOptions) string {
+	if len(opts.Rows) == 0 {
+		return ""
+	}
+	columns, rows := tableColumnsAndRows(opts)
+	s := defaultTableStyles()
+	s.Selected = lipgloss.NewStyle()
+	width := 0
+	for _, col := range columns {
+		width += col.Width + s.Cell.GetHorizontalFrameSize()
+	}
+	if opts.Width > 0 {
+		width = min(width, int(opts.Width))
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithStyles(s),
+		table.WithWidth(width),
+	)
+	// The header and its bottom border take two lines above the rows.
+	t.SetHeight(len(rows) + 2)
+	return t.View()
+}
+
+// tableColumnsAndRows converts table options into bubbles table columns and
+// rows, sizing columns without a width to their widest cell plus padding.
+func tableColumnsAndRows(opts TableOptions) ([]table.Column, []table.Row) {
+	columns := make([]table.Column, len(opts.Columns))
+	for i, col := range opts.Columns {
+		colWidth := int(col.Width)
+		if colWidth == 0 {
+			// Auto-calculate width based on content
+			colWidth = len(col.Title)
+			for _, row := range opts.Rows {
+				if i < len(row) && len(row[i]) > colWidth {
+					colWidth = len(row[i])
+				}
+			}
+			colWidth += 2 // Add padding
+		}
+		columns[i] = table.Column{
+			Title: col.Title,
+			Width: colWidth,
+		}
+	}
+
+	rows := make([]table.Row, len(opts.Rows))
+	for i, row := range opts.Rows {
+		rows[i] = row
+	}
+	return columns, rows
+}
+
+// defaultTableStyles returns the table styles used outside modal overlays.
+func defaultTableStyles() table.Styles {
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(true)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	return s
+}
@@ -566,3 +566,31 @@ func TestTableModel_UnicodeAndLongInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderTable(t *testing.T) {
+	t.Parallel()
+
+	opts := TableOptions{
+		Columns: []TableColumn{{Title: "Combination"}, {Title: "Result"}},
+		Rows: [][]string{
+			{"go=1.22", "pass"},
+			{"go=1.23", "fail (exit 1)"},
+			{"go=1.24", "pass"},
+		},
+	}
+
+	out := RenderTable(opts)
+	for _, want := range []string{"Combination", "Result", "go=1.22", "fail (exit 1)", "go=1.24"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderTable() output missing %q:\n%s", want, out)
+		}
+	}
+	// Header, header border, and one line per row.
+	if lines := strings.Count(out, "\n") + 1; lines != len(opts.Rows)+2 {
+		t.Errorf("RenderTable() rendered %d lines, want %d:\n%s", lines, len(opts.Rows)+2, out)
+	}
+
+	if out := RenderTable(TableOptions{Columns: opts.Columns}); out != "" {
+		t.Errorf("RenderTable() without rows = %q, want empty", out)
+	}
+}
@@ -2,6 +2,8 @@
 
 package cueutil
 
+import "cuelang.org/go/cue/ast"
+
 // DefaultMaxFileSize is the default maximum file size for CUE parsing (5MB).
 // This limit prevents OOM attacks from maliciously large configuration files.
 const DefaultMaxFileSize int64 = 5 * 1024 * 1024
@@ -12,8 +14,13 @@ type (
 		maxFileSize int64
 		concrete    bool
 		filename    string
+		transform   ASTTransform
 	}
 
+	// ASTTransform rewrites the parsed user file before it is compiled and
+	// unified with the schema. A returned error aborts parsing unchanged.
+	ASTTransform func(*ast.File) error
+
 	// Option configures parsing behavior.
 	Option func(*parseOptions)
 )
@@ -53,3 +60,13 @@ func WithFilename(name string) Option {
 		o.filename = name
 	}
 }
+
+// WithASTTransform sets a transform applied to the parsed user file before
+// compilation. Transforms can expand syntax the schema does not model, such
+// as references to reusable fragments, while keeping the source positions of
+// the original nodes for error messages.
+func WithASTTransform(transform ASTTransform) Option {
+	return func(o *parseOptions) {
+		o.transform = transform
+	}
+}
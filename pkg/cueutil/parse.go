@@ -7,6 +7,7 @@ import (
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/parser"
 )
 
 // ParseResult contains the result of a successful CUE parse operation.
@@ -61,9 +62,9 @@ func ParseAndDecode[T any](schema, data []byte, schemaPath string, opts ...Optio
 	}
 
 	// Step 2: Compile the user data
-	userValue := ctx.CompileBytes(data, cue.Filename(filename))
-	if userValue.Err() != nil {
-		return nil, FormatError(userValue.Err(), filename)
+	userValue, err := compileUserData(ctx, data, filename, options.transform)
+	if err != nil {
+		return nil, err
 	}
 
 	// Look up the root definition in the schema
@@ -96,6 +97,31 @@ func ParseAndDecode[T any](schema, data []byte, schemaPath string, opts ...Optio
 	}, nil
 }
 
+// compileUserData compiles the user data, applying the AST transform first
+// when one is configured.
+func compileUserData(ctx *cue.Context, data []byte, filename string, transform ASTTransform) (cue.Value, error) {
+	if transform == nil {
+		userValue := ctx.CompileBytes(data, cue.Filename(filename))
+		if userValue.Err() != nil {
+			return cue.Value{}, FormatError(userValue.Err(), filename)
+		}
+		return userValue, nil
+	}
+
+	file, err := parser.ParseFile(filename, data, parser.ParseComments)
+	if err != nil {
+		return cue.Value{}, FormatError(err, filename)
+	}
+	if err := transform(file); err != nil {
+		return cue.Value{}, err
+	}
+	userValue := ctx.BuildFile(file)
+	if userValue.Err() != nil {
+		return cue.Value{}, FormatError(userValue.Err(), filename)
+	}
+	return userValue, nil
+}
+
 // ParseAndDecodeString is a convenience wrapper that accepts schema as string.
 // Useful when the schema is embedded as a string constant rather than bytes.
 func ParseAndDecodeString[T any](schema string, data []byte, schemaPath string, opts ...Option) (*ParseResult[T], error) {
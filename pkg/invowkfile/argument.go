@@ -18,6 +18,12 @@ const (
 	ArgumentTypeInt ArgumentType = "int"
 	// ArgumentTypeFloat is for floating-point arguments
 	ArgumentTypeFloat ArgumentType = "float"
+	// ArgumentTypeDuration is for positive Go duration arguments (e.g., "30s", "1h30m")
+	ArgumentTypeDuration ArgumentType = "duration"
+	// ArgumentTypePath is for filesystem path arguments, resolved relative to the invowkfile
+	ArgumentTypePath ArgumentType = "path"
+	// ArgumentTypeJSON is for JSON document arguments, optionally checked against a schema
+	ArgumentTypeJSON ArgumentType = "json"
 
 	invalidReasonMustNotBeEmpty = "must not be empty"
 )
@@ -82,10 +88,26 @@ type (
 		// DefaultValue is the default value if the argument is not provided (optional)
 		DefaultValue string `json:"default_value,omitempty"`
 		// Type specifies the data type of the argument (optional, defaults to "string")
-		// Supported types: "string", "int", "float"
+		// Supported types: "string", "int", "float", "duration", "path", "json"
 		Type ArgumentType `json:"type,omitempty"`
 		// Validation is a regex pattern to validate the argument value (optional)
 		Validation RegexPattern `json:"validation,omitempty"`
+		// Choices lists the allowed values for the argument (optional).
+		// Values outside the list are rejected, and shell completion offers the list.
+		// For variadic arguments every value must be one of the choices.
+		Choices []string `json:"choices,omitempty"`
+		// MustExist requires a "path" value to exist before the command runs (optional)
+		MustExist bool `json:"must_exist,omitempty"`
+		// Kind restricts a "path" value to a file or a directory (optional)
+		Kind PathKind `json:"kind,omitempty"`
+		// Schema is a CUE constraint that a "json" value must satisfy (optional)
+		Schema ValueSchema `json:"schema,omitempty"`
+		// Prompt asks for the value interactively when the required argument is
+		// missing and invowk runs in a terminal (optional)
+		Prompt *PromptConfig `json:"prompt,omitempty"`
+		// Complete computes the shell completion candidates of the argument
+		// with a script (optional)
+		Complete *CompletionConfig `json:"complete,omitempty"`
 		// Variadic indicates this argument accepts multiple values (optional, defaults to false)
 		// Only the last argument can be variadic
 		Variadic bool `json:"variadic,omitempty"`
@@ -94,7 +116,7 @@ type (
 
 // Error implements the error interface for InvalidArgumentTypeError.
 func (e *InvalidArgumentTypeError) Error() string {
-	return fmt.Sprintf("invalid argument type %q (valid: string, int, float)", e.Value)
+	return fmt.Sprintf("invalid argument type %q (valid: string, int, float, duration, path, json)", e.Value)
 }
 
 // Unwrap returns the sentinel error for errors.Is() compatibility.
@@ -110,7 +132,8 @@ func (at ArgumentType) String() string { return string(at) }
 // Note: the zero value ("") is valid — it is treated as "string" by GetType().
 func (at ArgumentType) Validate() error {
 	switch at {
-	case ArgumentTypeString, ArgumentTypeInt, ArgumentTypeFloat, "":
+	case ArgumentTypeString, ArgumentTypeInt, ArgumentTypeFloat,
+		ArgumentTypeDuration, ArgumentTypePath, ArgumentTypeJSON, "":
 		return nil
 	default:
 		return &InvalidArgumentTypeError{Value: at}
@@ -157,8 +180,9 @@ func (a *Argument) GetType() ArgumentType {
 // Validate returns nil if the Argument has valid fields,
 // or an error collecting all field-level validation failures.
 // Delegates to Name.Validate() (nonzero), Description.Validate() (non-empty),
-// Type.Validate() (zero-valid), Validation.Validate() (zero-valid), and
-// default-value compatibility.
+// Type.Validate() (zero-valid), Validation.Validate() (zero-valid),
+// default-value compatibility, choices, and the type-specific options
+// (must_exist, kind, schema).
 func (a Argument) Validate() error {
 	var errs []error
 	if err := a.Name.Validate(); err != nil {
@@ -174,6 +198,10 @@ func (a Argument) Validate() error {
 		errs = append(errs, err)
 	}
 	errs = append(errs, a.defaultValueValidationErrors()...)
+	errs = append(errs, a.choicesValidationErrors()...)
+	errs = append(errs, a.typeOptionsValidationErrors()...)
+	errs = append(errs, promptValidationErrors(a.Prompt, a.Required, FlagType(a.GetType()), a.Choices, a.Validation)...)
+	errs = append(errs, completionValidationErrors(a.Complete, FlagType(a.GetType()), a.Choices)...)
 	if len(errs) > 0 {
 		return &InvalidArgumentError{FieldErrors: errs}
 	}
@@ -188,12 +216,13 @@ func (e *InvalidArgumentError) Error() string {
 // Unwrap returns ErrInvalidArgument for errors.Is() compatibility.
 func (e *InvalidArgumentError) Unwrap() error { return ErrInvalidArgument }
 
-// ValidateArgumentValue validates an argument value at runtime against type and validation regex.
+// ValidateArgumentValue validates an argument value at runtime against type, validation regex,
+// choices, and (for "json" arguments) the schema.
 // Returns nil if the value is valid, or an error describing the issue.
 func (a *Argument) ValidateArgumentValue(value string) error {
 	argType := a.GetType()
 	// Validate the argument type itself before cross-casting to FlagType.
-	// ArgumentType values ("string", "int", "float") are a strict subset of
+	// ArgumentType values are a strict subset of
 	// FlagType values, so the cast is safe for all valid ArgumentType values.
 	if err := argType.Validate(); err != nil {
 		return fmt.Errorf("argument '%s': %w", a.Name, err)
@@ -201,12 +230,39 @@ func (a *Argument) ValidateArgumentValue(value string) error {
 	if err := validateValueType(value, FlagType(argType)); err != nil {
 		return fmt.Errorf("argument '%s' value '%s' is invalid: %s", a.Name, value, err.Error())
 	}
+	if err := validateValueInChoices("argument '"+a.Name.String()+"'", value, a.Choices); err != nil {
+		return err
+	}
 	if err := validateValueWithRegex("argument '"+a.Name.String()+"'", value, string(a.Validation)); err != nil {
 		return err
 	}
+	if argType == ArgumentTypeJSON {
+		if err := a.Schema.Check(value); err != nil {
+			return fmt.Errorf("argument '%s' value is invalid: %w", a.Name, err)
+		}
+	}
 	return nil
 }
 
+// ValidatePathValue checks a "path" argument value against must_exist and kind,
+// resolving relative values against baseDir (the invowkfile directory).
+// Returns nil for other argument types.
+func (a *Argument) ValidatePathValue(baseDir FilesystemPath, value string) error {
+	if a.GetType() != ArgumentTypePath || value == "" {
+		return nil
+	}
+	return checkPathValue("argument '"+a.Name.String()+"'", ResolveInputPath(baseDir, value), a.MustExist, a.Kind)
+}
+
+// EnvValue returns the INVOWK_ARG_* value for a validated argument value:
+// "path" values are resolved against baseDir, other values are returned unchanged.
+func (a *Argument) EnvValue(baseDir FilesystemPath, value string) string {
+	if a.GetType() == ArgumentTypePath {
+		return string(ResolveInputPath(baseDir, value))
+	}
+	return value
+}
+
 func (a Argument) defaultValueValidationErrors() []error {
 	if a.DefaultValue == "" {
 		return nil
@@ -216,11 +272,25 @@ func (a Argument) defaultValueValidationErrors() []error {
 		errs = append(errs, errors.New("cannot be both required and have a default_value"))
 	}
 	argType := a.GetType()
-	if err := validateValueType(a.DefaultValue, FlagType(argType)); err != nil {
-		errs = append(errs, fmt.Errorf("default_value %q is not compatible with type %q: %w", a.DefaultValue, argType, err))
+	typeErr := validateValueType(a.DefaultValue, FlagType(argType))
+	if typeErr != nil {
+		errs = append(errs, fmt.Errorf("default_value %q is not compatible with type %q: %w", a.DefaultValue, argType, typeErr))
 	}
 	if a.Validation.Validate() == nil && !matchesValidation(a.DefaultValue, string(a.Validation)) {
 		errs = append(errs, fmt.Errorf("default_value %q does not match validation pattern %q", a.DefaultValue, a.Validation))
 	}
+	if argType == ArgumentTypeJSON && typeErr == nil && a.Schema.Validate() == nil {
+		if err := a.Schema.Check(a.DefaultValue); err != nil {
+			errs = append(errs, fmt.Errorf("default_value %q is invalid: %w", a.DefaultValue, err))
+		}
+	}
 	return errs
 }
+
+func (a Argument) choicesValidationErrors() []error {
+	return choicesValidationErrors(a.Choices, FlagType(a.GetType()), a.Validation, a.DefaultValue)
+}
+
+func (a Argument) typeOptionsValidationErrors() []error {
+	return typeOptionsValidationErrors(FlagType(a.GetType()), a.MustExist, a.Kind, "", a.Schema)
+}
@@ -11,7 +11,7 @@ import (
 func TestArgumentTypeValidateReportsInvalidValue(t *testing.T) {
 	t.Parallel()
 
-	const value ArgumentType = "duration"
+	const value ArgumentType = "uuid"
 
 	err := value.Validate()
 	if err == nil {
@@ -27,7 +27,7 @@ func TestArgumentTypeValidateReportsInvalidValue(t *testing.T) {
 	if invalid.Value != value {
 		t.Fatalf("InvalidArgumentTypeError.Value = %q, want %q", invalid.Value, value)
 	}
-	want := `invalid argument type "duration" (valid: string, int, float)`
+	want := `invalid argument type "uuid" (valid: string, int, float, duration, path, json)`
 	if got := err.Error(); got != want {
 		t.Fatalf("InvalidArgumentTypeError.Error() = %q, want %q", got, want)
 	}
@@ -200,7 +200,7 @@ func TestArgumentValidateArgumentValueRejectsInvalidArgumentType(t *testing.T) {
 
 	arg := &Argument{
 		Name: "count",
-		Type: "duration",
+		Type: "uuid",
 	}
 
 	err := arg.ValidateArgumentValue("1")
@@ -4,6 +4,8 @@ package invowkfile
 
 import (
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -118,6 +120,124 @@ func assertArgumentValidateDefaultValue(t *testing.T, arg Argument, wantErr stri
 	}
 }
 
+func TestArgument_Validate_Choices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		arg     Argument
+		wantErr string
+	}{
+		{
+			name: "valid choices with default",
+			arg:  Argument{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}, DefaultValue: "dev"},
+		},
+		{
+			name:    "default not in choices",
+			arg:     Argument{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}, DefaultValue: "qa"},
+			wantErr: "is not one of the choices",
+		},
+		{
+			name:    "duplicate choice",
+			arg:     Argument{Name: "env", Description: "Target environment", Choices: []string{"dev", "dev"}},
+			wantErr: "duplicate choice",
+		},
+		{
+			name:    "choice incompatible with type",
+			arg:     Argument{Name: "replicas", Description: "Replica count", Type: ArgumentTypeInt, Choices: []string{"1", "many"}},
+			wantErr: "is not compatible with type",
+		},
+		{
+			name:    "choice does not match validation",
+			arg:     Argument{Name: "env", Description: "Target environment", Validation: "^[a-z]+$", Choices: []string{"dev", "PROD"}},
+			wantErr: "does not match validation pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertArgumentValidateDefaultValue(t, tt.arg, tt.wantErr)
+		})
+	}
+}
+
+func TestArgument_ValidateArgumentValue_Choices(t *testing.T) {
+	t.Parallel()
+
+	arg := Argument{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}}
+	if err := arg.ValidateArgumentValue("prod"); err != nil {
+		t.Fatalf("ValidateArgumentValue(prod) returned error: %v", err)
+	}
+	err := arg.ValidateArgumentValue("qa")
+	if err == nil || !strings.Contains(err.Error(), "is not one of the allowed choices: dev, prod") {
+		t.Fatalf("ValidateArgumentValue(qa) error = %v, want choices error", err)
+	}
+}
+
+func TestArgument_Validate_TypeOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		arg     Argument
+		wantErr string
+	}{
+		{
+			name: "duration default",
+			arg:  Argument{Name: "wait", Description: "Wait time", Type: ArgumentTypeDuration, DefaultValue: "30s"},
+		},
+		{
+			name: "path with kind",
+			arg:  Argument{Name: "src", Description: "Source file", Type: ArgumentTypePath, MustExist: true, Kind: PathKindFile},
+		},
+		{
+			name:    "must_exist without path type",
+			arg:     Argument{Name: "src", Description: "Source file", MustExist: true},
+			wantErr: `must_exist and kind require type "path"`,
+		},
+		{
+			name:    "list is not an argument type",
+			arg:     Argument{Name: "tags", Description: "Tags", Type: ArgumentType(FlagTypeList)},
+			wantErr: "invalid argument type",
+		},
+		{
+			name:    "json default violates schema",
+			arg:     Argument{Name: "cfg", Description: "Config", Type: ArgumentTypeJSON, Schema: "[...int]", DefaultValue: `["a"]`},
+			wantErr: "does not satisfy schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertArgumentValidateDefaultValue(t, tt.arg, tt.wantErr)
+		})
+	}
+}
+
+func TestArgument_PathAndEnvValue(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	baseDir := FilesystemPath(dir)
+	arg := Argument{Name: "src", Type: ArgumentTypePath, MustExist: true, Kind: PathKindDir}
+	if err := arg.ValidatePathValue(baseDir, "."); err != nil {
+		t.Fatalf("ValidatePathValue(.) = %v, want nil", err)
+	}
+	err := arg.ValidatePathValue(baseDir, "missing")
+	if err == nil || !strings.Contains(err.Error(), "argument 'src' path") {
+		t.Fatalf("ValidatePathValue(missing) = %v, want does not exist error", err)
+	}
+	if got := arg.EnvValue(baseDir, "sub/dir"); got != filepath.Join(dir, "sub", "dir") {
+		t.Fatalf("EnvValue(sub/dir) = %q", got)
+	}
+	plain := Argument{Name: "name"}
+	if got := plain.EnvValue(baseDir, "sub/dir"); got != "sub/dir" {
+		t.Fatalf("EnvValue(string) = %q, want unchanged", got)
+	}
+}
+
 func TestArgument_Validate_MissingDescription(t *testing.T) {
 	t.Parallel()
 	a := Argument{Name: "file"}
@@ -29,6 +29,14 @@ var (
 	// ErrInvalidCommandCategory is the sentinel error wrapped by InvalidCommandCategoryError.
 	ErrInvalidCommandCategory = errors.New("invalid command category")
 
+	// ErrStepCommandIncremental is returned when a command with steps declares
+	// sources or generates; each step decides on its own whether it is up to date.
+	ErrStepCommandIncremental = errors.New("commands with steps must not declare sources or generates")
+
+	// ErrGeneratesWithoutSources is returned when generates is declared without
+	// any sources, leaving nothing to decide whether the outputs are up to date.
+	ErrGeneratesWithoutSources = errors.New("generates requires sources")
+
 	// ErrInvalidCommand is the sentinel error wrapped by InvalidCommandError.
 	ErrInvalidCommand = errors.New("invalid command")
 )
@@ -69,12 +77,36 @@ type (
 	Command struct {
 		// Name is the command identifier (can include spaces for subcommand-like behavior, e.g., "test unit")
 		Name CommandName `json:"name"`
+		// Extends names the template merged into this command while parsing (optional).
+		Extends TemplateRef `json:"extends,omitempty"`
+		// Aliases lists alternative names the command can be invoked by (optional).
+		// Aliases share the command namespace: they must not collide with other
+		// command names or aliases in the same invowkfile.
+		Aliases []CommandName `json:"aliases,omitempty"`
+		// Deprecated marks the command as deprecated (optional). Invoking it prints
+		// a warning and may forward to a replacement command.
+		Deprecated *Deprecation `json:"deprecated,omitempty"`
+		// Confirm asks for an interactive yes before the command runs (optional).
+		Confirm *ConfirmConfig `json:"confirm,omitempty"`
+		// Lock keeps overlapping runs of the command from executing at the same time (optional).
+		Lock *CommandLock `json:"lock,omitempty"`
+		// Matrix runs the command once per combination of variable values (optional).
+		Matrix *CommandMatrix `json:"matrix,omitempty"`
+		// Outputs declares the named values the script writes to INVOWK_OUTPUT_FILE (optional).
+		Outputs []CommandOutput `json:"outputs,omitempty"`
 		// Description provides help text for the command
 		Description DescriptionText `json:"description,omitempty"`
 		// Category groups this command under a heading in 'invowk cmd' output (optional)
 		Category CommandCategory `json:"category,omitempty"`
-		// Implementations defines the executable implementations with platform/runtime constraints (required, at least one)
-		Implementations []Implementation `json:"implementations"`
+		// Visibility controls who can see and run the command (optional, default: public).
+		Visibility CommandVisibility `json:"visibility,omitempty"`
+		// Implementations defines the executable implementations with platform/runtime constraints.
+		// Exactly one of Implementations or Steps must be declared.
+		Implementations []Implementation `json:"implementations,omitempty"`
+		// Steps defines a sequential multi-step pipeline executed in declaration order.
+		// Each step invokes another command or runs an inline script.
+		// Exactly one of Implementations or Steps must be declared.
+		Steps []CommandStep `json:"steps,omitempty"`
 		// Env contains environment configuration for this command (optional)
 		// Environment from files is loaded first, then vars override.
 		// Command-level env is applied before implementation-level env.
@@ -90,6 +122,9 @@ type (
 		// Note: All flags starting with 'ivk-', 'invowk-', or 'i-' are reserved for system use.
 		// Additionally, 'help' and 'version' are reserved built-in flags.
 		Flags []Flag `json:"flags,omitempty"`
+		// FlagGroups declares mutually exclusive, required-together, and
+		// one-required constraints between Flags (optional).
+		FlagGroups *FlagGroups `json:"flag_groups,omitempty"`
 		// Args specifies positional arguments for this command
 		// Arguments are passed as environment variables: INVOWK_ARG_<NAME>
 		// For variadic arguments: INVOWK_ARG_<NAME>_COUNT and INVOWK_ARG_<NAME>_1, _2, etc.
@@ -97,6 +132,28 @@ type (
 		// Watch defines file-watching configuration for automatic re-execution (optional).
 		// When defined, the command can be activated in watch mode via --ivk-watch.
 		Watch *WatchConfig `json:"watch,omitempty"`
+		// Sources lists glob patterns for the command's input files (optional).
+		// Declaring sources makes the command incremental: it is skipped while
+		// its sources and generated outputs are unchanged since the last successful run.
+		Sources []GlobPattern `json:"sources,omitempty"`
+		// Generates lists glob patterns for the command's output files (optional).
+		// Requires sources on the command or implementation.
+		Generates []GlobPattern `json:"generates,omitempty"`
+		// Hooks declares lifecycle scripts that run around this command (optional).
+		// They run after the invowkfile's root-level hooks within each phase.
+		Hooks *Hooks `json:"hooks,omitempty"`
+
+		// ImportedFrom stores the path of the fragment that declared the command
+		// when it was merged through the invowkfile's imports (not in CUE).
+		// Empty for commands declared by the invowkfile itself.
+		ImportedFrom FilesystemPath `json:"-"`
+	}
+
+	// platformArchRuntimeKey narrows a platform+runtime combination to one CPU
+	// architecture. The zero arch stands for every architecture.
+	platformArchRuntimeKey struct {
+		key  PlatformRuntimeKey
+		arch Architecture
 	}
 )
 
@@ -156,21 +213,37 @@ func (c CommandCategory) String() string { return string(c) }
 
 // Validate returns nil if the Command has valid fields,
 // or an error collecting all field-level validation failures.
-// Delegates to Name.Validate() (nonzero), Description (non-empty),
-// Category (zero-valid), each Implementation, Env (non-nil), WorkDir (non-empty),
-// DependsOn (non-nil), each Flag, each Argument, and Watch (non-nil).
+// Delegates to Name.Validate() (nonzero), Extends (non-empty), each Alias,
+// Deprecated (non-nil), Confirm (non-nil), Lock (non-nil), Matrix (non-nil), each Output, Description (non-empty), Category (zero-valid),
+// Visibility (non-empty), each Implementation, each Step, Env (non-nil), WorkDir (non-empty), DependsOn
+// (non-nil), each Flag, FlagGroups (non-nil), each Argument, Watch (non-nil), each Sources and
+// Generates pattern, Hooks (non-nil), and ImportedFrom (non-empty).
 func (c Command) Validate() error {
 	var errs []error
 	appendFieldError(&errs, c.Name.Validate())
+	appendOptionalValidation(&errs, c.Extends, c.Extends != "")
+	appendEachValidation(&errs, c.Aliases)
+	appendOptionalValidation(&errs, c.Deprecated, c.Deprecated != nil)
+	appendOptionalValidation(&errs, c.Confirm, c.Confirm != nil)
+	appendOptionalValidation(&errs, c.Lock, c.Lock != nil)
+	appendOptionalValidation(&errs, c.Matrix, c.Matrix != nil)
+	appendEachValidation(&errs, c.Outputs)
 	appendOptionalValidation(&errs, c.Description, c.Description != "")
 	appendFieldError(&errs, c.Category.Validate())
+	appendOptionalValidation(&errs, c.Visibility, c.Visibility != "")
 	appendEachValidation(&errs, c.Implementations)
+	appendEachValidation(&errs, c.Steps)
 	appendOptionalValidation(&errs, c.Env, c.Env != nil)
 	appendOptionalValidation(&errs, c.WorkDir, c.WorkDir != "")
 	appendOptionalValidation(&errs, c.DependsOn, c.DependsOn != nil)
 	appendEachValidation(&errs, c.Flags)
+	appendOptionalValidation(&errs, c.FlagGroups, c.FlagGroups != nil)
 	appendEachValidation(&errs, c.Args)
 	appendOptionalValidation(&errs, c.Watch, c.Watch != nil)
+	appendEachValidation(&errs, c.Sources)
+	appendEachValidation(&errs, c.Generates)
+	appendOptionalValidation(&errs, c.Hooks, c.Hooks != nil)
+	appendOptionalValidation(&errs, c.ImportedFrom, c.ImportedFrom != "")
 	if len(errs) > 0 {
 		return &InvalidCommandError{FieldErrors: errs}
 	}
@@ -198,6 +271,59 @@ func (c *Command) GetImplForPlatformRuntime(platform Platform, runtime RuntimeMo
 	return nil
 }
 
+// SelectImplementation returns the first implementation for platform and
+// runtime that applies to the architecture of wc and whose when conditions
+// hold in wc, or nil when none does. The explanation lists the skipped and
+// selected implementations; it is empty when none of the candidates declares
+// when conditions or architecture restrictions.
+func (c *Command) SelectImplementation(platform Platform, runtime RuntimeMode, wc WhenContext) (selected *Implementation, explanation string) {
+	var steps []string
+	conditional := false
+	for i := range c.Implementations {
+		impl := &c.Implementations[i]
+		if !impl.MatchesPlatform(platform) || !impl.HasRuntime(runtime) {
+			continue
+		}
+		arches := impl.ArchesForPlatform(platform)
+		conditional = conditional || impl.When != nil || len(arches) > 0
+		if !impl.MatchesPlatformArch(platform, wc.Arch) {
+			steps = append(steps, fmt.Sprintf("implementation #%d skipped: arch is %s, want one of %s", i+1, orUnknown(string(wc.Arch)), joinArchitectures(arches)))
+			continue
+		}
+		ok, description := impl.When.Match(wc)
+		if !ok {
+			steps = append(steps, fmt.Sprintf("implementation #%d skipped: %s", i+1, description))
+			continue
+		}
+		if description == "" {
+			description = "no when conditions"
+		}
+		steps = append(steps, fmt.Sprintf("implementation #%d selected: %s", i+1, description))
+		selected = impl
+		break
+	}
+	if !conditional {
+		return selected, ""
+	}
+	return selected, strings.Join(steps, "; ")
+}
+
+// GetDefaultRuntimeForPlatformWhen returns the first runtime of the first
+// implementation for platform that applies to the architecture of wc and whose
+// when conditions hold in wc. It falls back to GetDefaultRuntimeForPlatform
+// when no such implementation exists.
+func (c *Command) GetDefaultRuntimeForPlatformWhen(platform Platform, wc WhenContext) RuntimeMode {
+	for _, impl := range c.GetImplsForPlatform(platform) {
+		if !impl.MatchesPlatformArch(platform, wc.Arch) {
+			continue
+		}
+		if ok, _ := impl.When.Match(wc); ok && len(impl.Runtimes) > 0 {
+			return impl.Runtimes[0].Name
+		}
+	}
+	return c.GetDefaultRuntimeForPlatform(platform)
+}
+
 // GetImplsForPlatform returns all implementations that can run on the given platform.
 func (c *Command) GetImplsForPlatform(platform Platform) []*Implementation {
 	var result []*Implementation
@@ -234,11 +360,33 @@ func (c *Command) CanRunOnCurrentHost() bool {
 }
 
 // CanRunOnPlatform returns true if the command has at least one implementation
-// for the given platform.
+// for the given platform. Step commands are platform-neutral at this level:
+// each step resolves its own platform compatibility when it runs.
 func (c *Command) CanRunOnPlatform(platform Platform) bool {
+	if c.HasSteps() {
+		return true
+	}
 	return len(c.GetImplsForPlatform(platform)) > 0
 }
 
+// IncrementalPatterns returns the source and output glob patterns that apply
+// when the command runs with impl: command-level patterns followed by the
+// implementation's own. impl may be nil.
+func (c *Command) IncrementalPatterns(impl *Implementation) (sources, generates []GlobPattern) {
+	sources = slices.Clone(c.Sources)
+	generates = slices.Clone(c.Generates)
+	if impl != nil {
+		sources = append(sources, impl.Sources...)
+		generates = append(generates, impl.Generates...)
+	}
+	return sources, generates
+}
+
+// HasSteps returns true if the command is a sequential multi-step command.
+func (c *Command) HasSteps() bool {
+	return len(c.Steps) > 0
+}
+
 // GetSupportedPlatforms returns all platforms that this command supports.
 // Platforms are mandatory on each implementation, so this aggregates the explicitly declared platforms.
 func (c *Command) GetSupportedPlatforms() []Platform {
@@ -321,22 +469,44 @@ func (c *Command) IsRuntimeAllowedForPlatform(platform Platform, runtime Runtime
 // ValidateImplementations checks that there are no duplicate platform+runtime combinations.
 // Returns an error with a descriptive message if duplicates are found.
 // Platforms are mandatory on each implementation, so this iterates the explicitly declared platforms.
+// Implementations with when conditions may share a combination with later
+// implementations, but nothing may follow an unconditional implementation
+// for the same combination, since it would never be selected. Platform
+// entries restricted to CPU architectures only claim the combination for
+// those architectures, so a later unrestricted entry serves the others.
 func (c *Command) ValidateImplementations() error {
-	seen := make(map[PlatformRuntimeKey]int) // key -> implementation index (1-based for error messages)
+	seen := make(map[platformArchRuntimeKey]int) // key -> unconditional implementation index (1-based for error messages)
 
 	for i := range c.Implementations {
 		impl := &c.Implementations[i]
 
 		for j := range impl.Platforms {
+			platform := &impl.Platforms[j]
 			for k := range impl.Runtimes {
-				key := PlatformRuntimeKey{Platform: impl.Platforms[j].Name, Runtime: impl.Runtimes[k].Name}
-				if existingIdx, exists := seen[key]; exists {
+				key := PlatformRuntimeKey{Platform: platform.Name, Runtime: impl.Runtimes[k].Name}
+				if existingIdx, exists := seen[platformArchRuntimeKey{key: key}]; exists {
 					return fmt.Errorf(
 						"command '%s' has duplicate platform+runtime combination: platform=%s, runtime=%s (implementations #%d and #%d)",
-						c.Name, impl.Platforms[j].Name, impl.Runtimes[k].Name, existingIdx, i+1,
+						c.Name, platform.Name, impl.Runtimes[k].Name, existingIdx, i+1,
 					)
 				}
-				seen[key] = i + 1
+				for _, arch := range platform.Arch {
+					if existingIdx, exists := seen[platformArchRuntimeKey{key: key, arch: arch}]; exists {
+						return fmt.Errorf(
+							"command '%s' has duplicate platform+runtime combination: platform=%s, arch=%s, runtime=%s (implementations #%d and #%d)",
+							c.Name, platform.Name, arch, impl.Runtimes[k].Name, existingIdx, i+1,
+						)
+					}
+				}
+				if impl.When != nil {
+					continue
+				}
+				if len(platform.Arch) == 0 {
+					seen[platformArchRuntimeKey{key: key}] = i + 1
+				}
+				for _, arch := range platform.Arch {
+					seen[platformArchRuntimeKey{key: key, arch: arch}] = i + 1
+				}
 			}
 		}
 	}
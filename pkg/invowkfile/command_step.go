@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidStepName is the sentinel error wrapped by InvalidStepNameError.
+	ErrInvalidStepName = errors.New("invalid step name")
+
+	// ErrInvalidCommandStep is the sentinel error wrapped by InvalidCommandStepError.
+	ErrInvalidCommandStep = errors.New("invalid command step")
+
+	// ErrMissingCommandStepTarget is returned when a step selects neither cmd nor script.
+	ErrMissingCommandStepTarget = errors.New("command step must set cmd or script")
+
+	// ErrMixedCommandStepTarget is returned when a step selects both cmd and script.
+	ErrMixedCommandStepTarget = errors.New("command step must not set both cmd and script")
+
+	// ErrCommandStepBindingsRequireCmd is returned when flag/arg bindings are
+	// declared on an inline-script step.
+	ErrCommandStepBindingsRequireCmd = errors.New("command step flags and args require cmd")
+
+	// ErrCommandStepScriptContainer is returned when an inline-script step selects
+	// the container or remote-ssh runtime, which need an image or host a step
+	// cannot declare.
+	ErrCommandStepScriptContainer = errors.New("command step scripts do not support the container or remote-ssh runtimes; invoke a command with such an implementation instead")
+)
+
+type (
+	// StepName is an optional display label for a command step.
+	// The zero value means "unnamed"; the step is then labelled by its target.
+	StepName string
+
+	// InvalidStepNameError is returned when a StepName is whitespace-only or too long.
+	InvalidStepNameError struct {
+		Value StepName
+	}
+
+	// InvalidCommandStepError is returned when a CommandStep has invalid fields.
+	// It wraps ErrInvalidCommandStep for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidCommandStepError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// CommandStep is one entry of a sequential multi-step command.
+	// A step either invokes another command (Cmd, with optional flag/arg bindings)
+	// or runs an inline script (Script). Steps run in declaration order and stop
+	// at the first failure unless ContinueOnError is set.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	CommandStep struct {
+		// Name is an optional display label used in progress and dry-run output.
+		Name StepName `json:"name,omitempty"`
+		// Cmd references the command to invoke. Bare refs resolve in the declaring
+		// command's source; "@source command" refs select an explicit source.
+		Cmd CommandDependencyRef `json:"cmd,omitempty"`
+		// Flags binds flag values on the invoked command (cmd steps only).
+		// Flags not bound here fall back to the invoked command's defaults.
+		Flags map[FlagName]string `json:"flags,omitempty"`
+		// Args are positional arguments passed to the invoked command (cmd steps only).
+		Args []string `json:"args,omitempty"`
+		// Script is an inline script run in the context of the declaring command
+		// (script steps only). It sees the declaring command's flags and args.
+		Script *ImplementationScript `json:"script,omitempty"`
+		// Runtime selects the runtime for this step. For cmd steps it acts as a
+		// runtime override for the invoked command; for script steps it selects
+		// the runtime that executes the script (default: native).
+		Runtime RuntimeMode `json:"runtime,omitempty"`
+		// ContinueOnError keeps executing later steps when this step fails.
+		// The failure is reported but does not fail the parent command.
+		ContinueOnError bool `json:"continue_on_error,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidStepNameError) Error() string {
+	return fmt.Sprintf("invalid step name %q (must not be whitespace-only, max %d chars)", e.Value, MaxNameLength)
+}
+
+// Unwrap returns ErrInvalidStepName so callers can use errors.Is for programmatic detection.
+func (e *InvalidStepNameError) Unwrap() error { return ErrInvalidStepName }
+
+// Validate returns nil if the StepName is valid, or a validation error if not.
+// The zero value ("") is valid — it means the step is unnamed.
+func (n StepName) Validate() error {
+	if n == "" {
+		return nil
+	}
+	if strings.TrimSpace(string(n)) == "" || utf8.RuneCountInString(string(n)) > MaxNameLength {
+		return &InvalidStepNameError{Value: n}
+	}
+	return nil
+}
+
+// String returns the string representation of the StepName.
+func (n StepName) String() string { return string(n) }
+
+// Validate returns nil if the CommandStep selects exactly one valid target,
+// or an error collecting all field-level validation failures.
+// Delegates to Name.Validate() (zero-valid), Cmd.Validate() (when set),
+// Script.Validate() (when set), each bound FlagName, and Runtime (when set).
+func (s CommandStep) Validate() error {
+	var errs []error
+	appendFieldError(&errs, s.Name.Validate())
+	hasCmd := s.Cmd != ""
+	hasScript := s.Script != nil
+	switch {
+	case hasCmd && hasScript:
+		errs = append(errs, ErrMixedCommandStepTarget)
+	case !hasCmd && !hasScript:
+		errs = append(errs, ErrMissingCommandStepTarget)
+	}
+	appendOptionalValidation(&errs, s.Cmd, hasCmd)
+	if hasScript {
+		appendFieldError(&errs, s.Script.Validate())
+		if len(s.Flags) > 0 || len(s.Args) > 0 {
+			errs = append(errs, ErrCommandStepBindingsRequireCmd)
+		}
+		if s.Runtime.RequiresConfig() {
+			errs = append(errs, ErrCommandStepScriptContainer)
+		}
+	}
+	for name := range s.Flags {
+		appendFieldError(&errs, name.Validate())
+	}
+	appendOptionalValidation(&errs, s.Runtime, s.Runtime != "")
+	if len(errs) > 0 {
+		return &InvalidCommandStepError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidCommandStepError.
+func (e *InvalidCommandStepError) Error() string {
+	return types.FormatFieldErrors("command step", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidCommandStep and field errors for errors.Is() compatibility.
+func (e *InvalidCommandStepError) Unwrap() error {
+	return errors.Join(ErrInvalidCommandStep, errors.Join(e.FieldErrors...))
+}
+
+// IsCommand returns true when this step invokes another command.
+func (s *CommandStep) IsCommand() bool { return s.Cmd != "" }
+
+// IsScript returns true when this step runs an inline script.
+func (s *CommandStep) IsScript() bool { return s.Script != nil }
+
+// EffectiveRuntime returns the runtime that executes an inline-script step.
+// Script steps default to the native runtime when no runtime is declared.
+func (s *CommandStep) EffectiveRuntime() RuntimeMode {
+	if s.Runtime != "" {
+		return s.Runtime
+	}
+	return RuntimeNative
+}
+
+// Label returns a human-readable identifier for the step: its Name when set,
+// otherwise the referenced command or "script".
+//
+//plint:render
+func (s *CommandStep) Label() string {
+	switch {
+	case s.Name != "":
+		return string(s.Name)
+	case s.IsCommand():
+		return string(s.Cmd)
+	default:
+		return "script"
+	}
+}
+
+// ScriptImplementation returns the implementation used to run an inline-script
+// step. The synthesized implementation supports every platform and only the
+// step's effective runtime. Returns nil for cmd steps.
+func (s *CommandStep) ScriptImplementation() *Implementation {
+	if s.Script == nil {
+		return nil
+	}
+	return &Implementation{
+		Script:    *s.Script,
+		Runtimes:  []RuntimeConfig{{Name: s.EffectiveRuntime()}},
+		Platforms: AllPlatformConfigs(),
+	}
+}
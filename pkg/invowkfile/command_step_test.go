@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommandStep_Validate(t *testing.T) {
+	t.Parallel()
+
+	script := &ImplementationScript{Content: "echo hi"}
+	tests := []struct {
+		name    string
+		step    CommandStep
+		wantErr error
+	}{
+		{"cmd step", CommandStep{Cmd: "build"}, nil},
+		{"qualified cmd step with bindings", CommandStep{Cmd: "@tools lint", Flags: map[FlagName]string{"fix": "true"}, Args: []string{"./..."}}, nil},
+		{"script step", CommandStep{Name: "announce", Script: script, Runtime: RuntimeVirtualSh}, nil},
+		{"neither target", CommandStep{Name: "empty"}, ErrMissingCommandStepTarget},
+		{"both targets", CommandStep{Cmd: "build", Script: script}, ErrMixedCommandStepTarget},
+		{"script with args", CommandStep{Script: script, Args: []string{"x"}}, ErrCommandStepBindingsRequireCmd},
+		{"container script", CommandStep{Script: script, Runtime: RuntimeContainer}, ErrCommandStepScriptContainer},
+		{"invalid ref", CommandStep{Cmd: "@ build"}, ErrInvalidCommandDependencyRef},
+		{"invalid flag name", CommandStep{Cmd: "build", Flags: map[FlagName]string{"-bad": "x"}}, ErrInvalidFlagName},
+		{"invalid runtime", CommandStep{Cmd: "build", Runtime: "bogus"}, ErrInvalidRuntimeMode},
+		{"whitespace name", CommandStep{Name: "  ", Cmd: "build"}, ErrInvalidStepName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.step.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidCommandStep) {
+				t.Fatalf("Validate() = %v, want ErrInvalidCommandStep", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() = %v, want wrapped %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandStep_ScriptImplementation(t *testing.T) {
+	t.Parallel()
+
+	cmdStep := CommandStep{Cmd: "build"}
+	if impl := cmdStep.ScriptImplementation(); impl != nil {
+		t.Fatalf("ScriptImplementation() for cmd step = %#v, want nil", impl)
+	}
+
+	step := CommandStep{Script: &ImplementationScript{Content: "echo hi"}}
+	impl := step.ScriptImplementation()
+	if impl == nil {
+		t.Fatal("ScriptImplementation() = nil, want implementation")
+	}
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Name != RuntimeNative {
+		t.Fatalf("Runtimes = %#v, want native default", impl.Runtimes)
+	}
+	for _, platform := range AllPlatformNames() {
+		if !impl.MatchesPlatform(platform) {
+			t.Errorf("MatchesPlatform(%s) = false, want true", platform)
+		}
+	}
+
+	step.Runtime = RuntimeVirtualSh
+	if got := step.ScriptImplementation().Runtimes[0].Name; got != RuntimeVirtualSh {
+		t.Fatalf("runtime = %s, want virtual-sh", got)
+	}
+}
+
+func TestCommandStep_Label(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		step CommandStep
+		want string
+	}{
+		{CommandStep{Name: "compile", Cmd: "build"}, "compile"},
+		{CommandStep{Cmd: "@tools lint"}, "@tools lint"},
+		{CommandStep{Script: &ImplementationScript{Content: "echo"}}, "script"},
+	}
+	for _, tt := range tests {
+		if got := tt.step.Label(); got != tt.want {
+			t.Errorf("Label() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseCommandSteps(t *testing.T) {
+	t.Parallel()
+
+	data := `
+cmds: [
+	{
+		name: "build"
+		implementations: [{script: {content: "echo build"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]
+	},
+	{
+		name: "release"
+		steps: [
+			{cmd: "build", flags: {target: "prod"}, args: ["v1"]},
+			{name: "notify", script: {content: "echo done"}, runtime: "virtual-sh", continue_on_error: true},
+		]
+	},
+]
+`
+	inv, err := ParseBytes([]byte(data), "/workspace/invowkfile.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+	release := inv.GetCommand("release")
+	if release == nil || !release.HasSteps() || len(release.Steps) != 2 {
+		t.Fatalf("release steps = %#v", release)
+	}
+	if release.Steps[0].Cmd != "build" || release.Steps[0].Flags["target"] != "prod" || release.Steps[0].Args[0] != "v1" {
+		t.Errorf("cmd step = %#v", release.Steps[0])
+	}
+	if !release.Steps[1].IsScript() || !release.Steps[1].ContinueOnError || release.Steps[1].Runtime != RuntimeVirtualSh {
+		t.Errorf("script step = %#v", release.Steps[1])
+	}
+	if !release.CanRunOnPlatform(PlatformWindows) {
+		t.Error("step command CanRunOnPlatform(windows) = false, want true")
+	}
+
+	regenerated, err := ParseBytes([]byte(GenerateCUE(inv)), "/workspace/invowkfile.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes(GenerateCUE()) error = %v", err)
+	}
+	if got := regenerated.GetCommand("release"); got == nil || len(got.Steps) != 2 || got.Steps[0].Flags["target"] != "prod" {
+		t.Fatalf("regenerated release = %#v", got)
+	}
+}
+
+func TestParseCommandStepsRejectsInvalidShapes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		command string
+		wantMsg string
+	}{
+		{
+			name:    "both implementations and steps",
+			command: `name: "release", steps: [{cmd: "other"}], implementations: [{script: {content: "echo"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]`,
+			wantMsg: "must not define both implementations and steps",
+		},
+		{
+			name:    "neither implementations nor steps",
+			command: `name: "release"`,
+			wantMsg: "must have at least one implementation or step",
+		},
+		{
+			name:    "self reference",
+			command: `name: "release", steps: [{cmd: "release"}]`,
+			wantMsg: "step must not invoke its own command",
+		},
+		{
+			name:    "script step with args",
+			command: `name: "release", steps: [{script: {content: "echo"}, args: ["x"]}]`,
+			wantMsg: "args",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseBytes([]byte("cmds: [{"+tt.command+"}]"), "/workspace/invowkfile.cue")
+			if err == nil {
+				t.Fatal("ParseBytes() returned nil error")
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("ParseBytes() error = %v, want message containing %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
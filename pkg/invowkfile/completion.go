@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// DefaultCompletionTimeout is the time a completion script may run when its
+// complete block declares no timeout. Completion runs while the user waits at
+// the prompt, so the default is short.
+const DefaultCompletionTimeout = 2 * time.Second
+
+var (
+	// ErrInvalidCompletionConfig is the sentinel error wrapped by InvalidCompletionConfigError.
+	ErrInvalidCompletionConfig = errors.New("invalid completion config")
+
+	// ErrCompletionContainerRuntime is returned when a completion script selects
+	// the container or remote-ssh runtime, which need an image or host a
+	// complete block cannot declare and are too slow for interactive completion.
+	ErrCompletionContainerRuntime = errors.New("completion scripts do not support the container or remote-ssh runtimes; use native, virtual-sh, virtual-lua, or virtual-starlark")
+)
+
+type (
+	// InvalidCompletionConfigError is returned when a CompletionConfig has
+	// invalid fields. It wraps ErrInvalidCompletionConfig for errors.Is()
+	// compatibility and collects field-level validation errors.
+	InvalidCompletionConfigError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// CompletionConfig declares a script that computes the shell completion
+	// candidates of an argument or flag. Every non-empty stdout line is one
+	// candidate, optionally followed by a tab and a description. The script
+	// runs in the context of the command: it sees the flags and args typed so
+	// far, the command env, and the working directory.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	CompletionConfig struct {
+		// Script is the inline (or module-contained) completion script.
+		Script ImplementationScript `json:"script"`
+		// Runtime selects the runtime that executes the script (default: native).
+		Runtime RuntimeMode `json:"runtime,omitempty"`
+		// Timeout limits how long the script may run (default: 2s).
+		Timeout DurationString `json:"timeout,omitempty"`
+	}
+)
+
+// Validate returns nil if the CompletionConfig has a valid script, a
+// completion-capable runtime, and a valid timeout, or an error collecting all
+// field-level validation failures.
+func (c CompletionConfig) Validate() error {
+	var errs []error
+	appendFieldError(&errs, c.Script.Validate())
+	appendOptionalValidation(&errs, c.Runtime, c.Runtime != "")
+	if c.Runtime.RequiresConfig() {
+		errs = append(errs, ErrCompletionContainerRuntime)
+	}
+	appendFieldError(&errs, c.Timeout.Validate())
+	if len(errs) > 0 {
+		return &InvalidCompletionConfigError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidCompletionConfigError.
+func (e *InvalidCompletionConfigError) Error() string {
+	return types.FormatFieldErrors("completion config", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidCompletionConfig and field errors for errors.Is() compatibility.
+func (e *InvalidCompletionConfigError) Unwrap() error {
+	return errors.Join(ErrInvalidCompletionConfig, errors.Join(e.FieldErrors...))
+}
+
+// EffectiveRuntime returns the runtime that executes the completion script.
+// Completion scripts default to the native runtime, like hooks.
+func (c *CompletionConfig) EffectiveRuntime() RuntimeMode {
+	if c.Runtime != "" {
+		return c.Runtime
+	}
+	return RuntimeNative
+}
+
+// EffectiveTimeout returns the time the completion script may run:
+// the declared timeout, or DefaultCompletionTimeout when none is declared.
+func (c *CompletionConfig) EffectiveTimeout() (time.Duration, error) {
+	timeout, err := parseDuration("timeout", c.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout == 0 {
+		return DefaultCompletionTimeout, nil
+	}
+	return timeout, nil
+}
+
+// ScriptImplementation returns the implementation used to run the completion
+// script. The synthesized implementation supports every platform and only the
+// script's effective runtime.
+func (c *CompletionConfig) ScriptImplementation() *Implementation {
+	return &Implementation{
+		Script:    c.Script,
+		Runtimes:  []RuntimeConfig{{Name: c.EffectiveRuntime()}},
+		Platforms: AllPlatformConfigs(),
+	}
+}
+
+// completionValidationErrors checks that a complete block fits the argument
+// or flag it belongs to: choices are completed already, and bool flags take
+// no value to complete. Shared by flags and arguments.
+func completionValidationErrors(complete *CompletionConfig, typeName FlagType, choices []string) []error {
+	if complete == nil {
+		return nil
+	}
+	var errs []error
+	if invalid, ok := errors.AsType[*InvalidCompletionConfigError](complete.Validate()); ok {
+		errs = append(errs, invalid.FieldErrors...)
+	}
+	if len(choices) > 0 {
+		errs = append(errs, errors.New("complete cannot be combined with choices (choices are offered as completions already)"))
+	}
+	if typeName == FlagTypeBool {
+		errs = append(errs, fmt.Errorf("complete does not fit type %q (bool flags take no value)", typeName))
+	}
+	return errs
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompletionConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	script := ImplementationScript{Content: "ls environments"}
+	tests := []struct {
+		name     string
+		complete CompletionConfig
+		wantErr  error
+	}{
+		{name: "defaults", complete: CompletionConfig{Script: script}},
+		{name: "virtual-sh with timeout", complete: CompletionConfig{Script: script, Runtime: RuntimeVirtualSh, Timeout: "500ms"}},
+		{name: "container runtime", complete: CompletionConfig{Script: script, Runtime: RuntimeContainer}, wantErr: ErrCompletionContainerRuntime},
+		{name: "unknown runtime", complete: CompletionConfig{Script: script, Runtime: "docker"}, wantErr: ErrInvalidRuntimeMode},
+		{name: "negative timeout", complete: CompletionConfig{Script: script, Timeout: "-1s"}, wantErr: ErrInvalidDurationString},
+		{name: "missing script", complete: CompletionConfig{}, wantErr: ErrInvalidCompletionConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.complete.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidCompletionConfig) {
+				t.Fatalf("Validate() error = %v, want %v wrapped in ErrInvalidCompletionConfig", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompletionConfigEffectiveValues(t *testing.T) {
+	t.Parallel()
+
+	defaults := &CompletionConfig{Script: ImplementationScript{Content: "ls"}}
+	if got := defaults.EffectiveRuntime(); got != RuntimeNative {
+		t.Errorf("EffectiveRuntime() = %q, want %q", got, RuntimeNative)
+	}
+	if got, err := defaults.EffectiveTimeout(); err != nil || got != DefaultCompletionTimeout {
+		t.Errorf("EffectiveTimeout() = %v, %v, want %v", got, err, DefaultCompletionTimeout)
+	}
+
+	custom := &CompletionConfig{Script: ImplementationScript{Content: "ls"}, Runtime: RuntimeVirtualSh, Timeout: "5s"}
+	if got, err := custom.EffectiveTimeout(); err != nil || got != 5*time.Second {
+		t.Errorf("EffectiveTimeout() = %v, %v, want 5s", got, err)
+	}
+	impl := custom.ScriptImplementation()
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Name != RuntimeVirtualSh || impl.Script.Content != "ls" {
+		t.Errorf("ScriptImplementation() = %#v, want the script on virtual-sh", impl)
+	}
+}
+
+func TestStructureValidatorValidateCompletions(t *testing.T) {
+	t.Parallel()
+
+	const suffix = " in invowkfile at flags.cue"
+	complete := &CompletionConfig{Script: ImplementationScript{Content: "ls environments"}}
+	scriptFile := ScriptFilePath("complete.sh")
+	tests := []struct {
+		name        string
+		flag        *Flag
+		arg         *Argument
+		wantField   string
+		wantMessage string
+	}{
+		{name: "valid flag", flag: &Flag{Name: "env", Description: "Environment", Complete: complete}},
+		{name: "valid arg", arg: &Argument{Name: "env", Description: "Environment", Complete: complete}},
+		{
+			name:        "flag with choices",
+			flag:        &Flag{Name: "env", Description: "Environment", Choices: []string{"dev"}, Complete: complete},
+			wantField:   "command 'deploy' flag 'env' complete",
+			wantMessage: "complete cannot be combined with choices",
+		},
+		{
+			name:        "bool flag",
+			flag:        &Flag{Name: "force", Description: "Force", Type: FlagTypeBool, Complete: complete},
+			wantField:   "command 'deploy' flag 'force' complete",
+			wantMessage: `complete does not fit type "bool"`,
+		},
+		{
+			name:        "container runtime",
+			arg:         &Argument{Name: "env", Description: "Environment", Complete: &CompletionConfig{Script: complete.Script, Runtime: RuntimeContainer}},
+			wantField:   "command 'deploy' argument 'env' complete",
+			wantMessage: ErrCompletionContainerRuntime.Error(),
+		},
+		{
+			name:        "script file outside a module",
+			arg:         &Argument{Name: "env", Description: "Environment", Complete: &CompletionConfig{Script: ImplementationScript{File: &scriptFile}}},
+			wantField:   "command 'deploy' argument 'env' complete script file",
+			wantMessage: ErrScriptFileRequiresModule.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := structureFlagMutationCommand()
+			if tt.flag != nil {
+				cmd.Flags = []Flag{*tt.flag}
+			}
+			if tt.arg != nil {
+				cmd.Args = []Argument{*tt.arg}
+			}
+
+			errs := NewStructureValidator().validateCompletions(structureFlagMutationContext(), &Invowkfile{}, &cmd)
+			if tt.wantMessage == "" {
+				if len(errs) != 0 {
+					t.Fatalf("validateCompletions() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("validateCompletions() returned %d errors, want 1: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField {
+				t.Fatalf("Field = %q, want %q", errs[0].Field, tt.wantField)
+			}
+			if !strings.Contains(errs[0].Message, tt.wantMessage) || !strings.HasSuffix(errs[0].Message, suffix) {
+				t.Fatalf("Message = %q, want %q ending in %q", errs[0].Message, tt.wantMessage, suffix)
+			}
+		})
+	}
+}
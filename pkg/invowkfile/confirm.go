@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	confirmPlaceholderFlag = "flag"
+	confirmPlaceholderArg  = "arg"
+)
+
+var (
+	// ErrInvalidConfirmConfig is the sentinel error wrapped by InvalidConfirmConfigError.
+	ErrInvalidConfirmConfig = errors.New("invalid confirm config")
+
+	// confirmPlaceholderPattern matches {{flag.<name>}} and {{arg.<name>}}
+	// placeholders in confirmation messages.
+	confirmPlaceholderPattern = regexp.MustCompile(`\{\{\s*(flag|arg)\.([^{}\s]+)\s*\}\}`)
+)
+
+type (
+	// InvalidConfirmConfigError is returned when a ConfirmConfig has invalid
+	// fields. It wraps ErrInvalidConfirmConfig for errors.Is() compatibility
+	// and collects field-level validation errors.
+	InvalidConfirmConfigError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// ConfirmConfig makes a command ask for an interactive yes before any
+	// dependency check or execution runs. The message may reference the
+	// effective flag and argument values with {{flag.<name>}} and
+	// {{arg.<name>}} placeholders, so the question can name what is about to
+	// be affected. --ivk-yes answers yes without asking.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	ConfirmConfig struct {
+		// Message is the question shown before the command runs.
+		Message string `json:"message"`
+		// Default preselects the answer of the question (default: no).
+		Default bool `json:"default,omitempty"`
+	}
+)
+
+// Validate returns nil if the ConfirmConfig has a non-empty message whose
+// placeholders name valid flags and arguments, or an error collecting all
+// field-level validation failures. Whether the placeholders name flags and
+// arguments the command declares is checked by the structure validator.
+func (c ConfirmConfig) Validate() error {
+	var errs []error
+	if strings.TrimSpace(c.Message) == "" {
+		errs = append(errs, errors.New("confirm message must not be empty"))
+	} else if utf8.RuneCountInString(c.Message) > MaxPromptMessageLength {
+		errs = append(errs, fmt.Errorf("confirm message exceeds maximum length of %d runes", MaxPromptMessageLength))
+	}
+	flags, args := c.Placeholders()
+	appendEachValidation(&errs, flags)
+	appendEachValidation(&errs, args)
+	if len(errs) > 0 {
+		return &InvalidConfirmConfigError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidConfirmConfigError.
+func (e *InvalidConfirmConfigError) Error() string {
+	return types.FormatFieldErrors("confirm config", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidConfirmConfig and field errors for errors.Is() compatibility.
+func (e *InvalidConfirmConfigError) Unwrap() error {
+	return errors.Join(ErrInvalidConfirmConfig, errors.Join(e.FieldErrors...))
+}
+
+// Placeholders returns the flag and argument names referenced by the
+// message placeholders, in order of appearance.
+func (c *ConfirmConfig) Placeholders() (flags []FlagName, args []ArgumentName) {
+	for _, match := range confirmPlaceholderPattern.FindAllStringSubmatch(c.Message, -1) {
+		if match[1] == confirmPlaceholderFlag {
+			flags = append(flags, FlagName(match[2]))
+		} else {
+			args = append(args, ArgumentName(match[2]))
+		}
+	}
+	return flags, args
+}
+
+// RenderMessage returns the message with every placeholder replaced by the
+// value of the flag or argument it names. Flags and arguments without a
+// value render as empty text.
+func (c *ConfirmConfig) RenderMessage(flags map[FlagName]string, args map[ArgumentName]string) string {
+	return confirmPlaceholderPattern.ReplaceAllStringFunc(c.Message, func(placeholder string) string {
+		match := confirmPlaceholderPattern.FindStringSubmatch(placeholder)
+		if match[1] == confirmPlaceholderArg {
+			return args[ArgumentName(match[2])]
+		}
+		return flags[FlagName(match[2])]
+	})
+}
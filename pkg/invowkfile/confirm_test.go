@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfirmConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		confirm ConfirmConfig
+		wantErr string
+	}{
+		{name: "plain message", confirm: ConfirmConfig{Message: "Reset the database?"}},
+		{name: "placeholders", confirm: ConfirmConfig{Message: "Wipe {{flag.env}} ({{ arg.target }})?", Default: true}},
+		{name: "empty message", confirm: ConfirmConfig{Message: "  "}, wantErr: "confirm message must not be empty"},
+		{name: "message too long", confirm: ConfirmConfig{Message: strings.Repeat("x", MaxPromptMessageLength+1)}, wantErr: "confirm message exceeds maximum length"},
+		{name: "invalid flag placeholder", confirm: ConfirmConfig{Message: "Wipe {{flag.-env}}?"}, wantErr: "invalid flag name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.confirm.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidConfirmConfig) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidConfirmConfig", err)
+			}
+			var confirmErr *InvalidConfirmConfigError
+			if !errors.As(err, &confirmErr) || !fieldErrorsContain(confirmErr.FieldErrors, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want a field error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfirmConfigRenderMessage(t *testing.T) {
+	t.Parallel()
+
+	confirm := &ConfirmConfig{Message: "Wipe the {{flag.env}} database of {{ arg.tenant }}? ({{flag.missing}}{{flag.env}})"}
+	flags, args := confirm.Placeholders()
+	if want := []FlagName{"env", "missing", "env"}; !slices.Equal(flags, want) {
+		t.Errorf("Placeholders() flags = %v, want %v", flags, want)
+	}
+	if want := []ArgumentName{"tenant"}; !slices.Equal(args, want) {
+		t.Errorf("Placeholders() args = %v, want %v", args, want)
+	}
+
+	got := confirm.RenderMessage(map[FlagName]string{"env": "prod"}, map[ArgumentName]string{"tenant": "acme"})
+	if want := "Wipe the prod database of acme? (prod)"; got != want {
+		t.Errorf("RenderMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestStructureValidatorValidateConfirm(t *testing.T) {
+	t.Parallel()
+
+	const suffix = " in invowkfile at flags.cue"
+	tests := []struct {
+		name        string
+		message     string
+		wantMessage string
+	}{
+		{name: "declared placeholders", message: "Wipe {{flag.env}} for {{arg.tenant}}?"},
+		{name: "undeclared flag", message: "Wipe {{flag.region}}?", wantMessage: "confirm message references undeclared flag 'region'" + suffix},
+		{name: "undeclared argument", message: "Wipe {{arg.env}} and {{arg.env}}?", wantMessage: "confirm message references undeclared argument 'env'" + suffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := structureFlagMutationCommand()
+			cmd.Flags = []Flag{{Name: "env", Description: "Environment"}}
+			cmd.Args = []Argument{{Name: "tenant", Description: "Tenant"}}
+			cmd.Confirm = &ConfirmConfig{Message: tt.message}
+
+			errs := NewStructureValidator().validateConfirm(structureFlagMutationContext(), &cmd, NewFieldPath().Command(cmd.Name))
+			if tt.wantMessage == "" {
+				if len(errs) != 0 {
+					t.Fatalf("validateConfirm() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("validateConfirm() returned %d errors, want 1: %v", len(errs), errs)
+			}
+			assertStructureFlagError(t, errs[0], "command 'deploy' confirm", []string{tt.wantMessage}, true)
+		})
+	}
+}
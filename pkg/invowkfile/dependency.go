@@ -57,6 +57,10 @@ var (
 	// ErrMixedCustomCheckDependency is returned when a custom check dependency
 	// combines direct check fields with an alternatives list.
 	ErrMixedCustomCheckDependency = errors.New("custom check dependency must use either direct fields or alternatives")
+	// ErrRuntimeRunDependency is returned when a runtime-level depends_on.cmds
+	// entry sets run: true. Prerequisites always execute on the host before the
+	// container starts, so they belong in root, command, or implementation depends_on.
+	ErrRuntimeRunDependency = errors.New("run: true is not supported in runtime-level depends_on.cmds; declare the prerequisite at root, command, or implementation level")
 )
 
 type (
@@ -197,6 +201,9 @@ type (
 		// Bare refs resolve only in the declaring command's source. Source-qualified refs
 		// use "@source command" syntax, e.g., "@tools lint".
 		Alternatives []CommandDependencyRef `json:"alternatives"`
+		// Run executes the first discoverable alternative as a prerequisite of the
+		// declaring command instead of only checking that it is discoverable.
+		Run bool `json:"run,omitempty"`
 	}
 
 	// CommandDependencyRef is a depends_on.cmds reference.
@@ -298,6 +305,21 @@ func (d *DependsOn) IsEmpty() bool {
 		len(d.Capabilities) == 0 && len(d.CustomChecks) == 0 && len(d.EnvVars) == 0
 }
 
+// Prerequisites returns the command dependencies that set run: true, in
+// declaration order. It returns nil for a nil DependsOn.
+func (d *DependsOn) Prerequisites() []CommandDependency {
+	if d == nil {
+		return nil
+	}
+	var prereqs []CommandDependency
+	for _, dep := range d.Commands {
+		if dep.Run {
+			prereqs = append(prereqs, dep)
+		}
+	}
+	return prereqs
+}
+
 // IsAlternatives returns true if this dependency uses the alternatives format
 func (c *CustomCheckDependency) IsAlternatives() bool {
 	return len(c.Alternatives) > 0
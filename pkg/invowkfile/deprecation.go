@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidDeprecation is the sentinel error wrapped by InvalidDeprecationError.
+	ErrInvalidDeprecation = errors.New("invalid deprecation")
+
+	// ErrForwardWithoutReplacement is returned when a deprecation asks to
+	// forward invocations without naming the command to forward to.
+	ErrForwardWithoutReplacement = errors.New("deprecation forward requires a replacement")
+)
+
+type (
+	// InvalidDeprecationError is returned when a Deprecation has invalid fields.
+	// It wraps ErrInvalidDeprecation for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidDeprecationError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// Deprecation marks a command as deprecated. Invoking the command prints a
+	// warning with the message and, when Forward is set, runs the replacement
+	// command instead.
+	Deprecation struct {
+		// Message explains why the command is deprecated (required).
+		Message DescriptionText `json:"message"`
+		// Replacement names the command that supersedes this one in the same
+		// invowkfile (optional).
+		Replacement CommandName `json:"replacement,omitempty"`
+		// Forward runs the replacement with the same args and the flags it
+		// declares instead of the deprecated command. Requires Replacement.
+		Forward bool `json:"forward,omitempty"`
+	}
+)
+
+// Validate returns nil if the Deprecation has a message, a valid optional
+// replacement, and a replacement when forwarding, or an error collecting all
+// field-level validation failures.
+func (d Deprecation) Validate() error {
+	var errs []error
+	appendFieldError(&errs, d.Message.Validate())
+	appendOptionalValidation(&errs, d.Replacement, d.Replacement != "")
+	if d.Forward && d.Replacement == "" {
+		errs = append(errs, ErrForwardWithoutReplacement)
+	}
+	if len(errs) > 0 {
+		return &InvalidDeprecationError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidDeprecationError.
+func (e *InvalidDeprecationError) Error() string {
+	return types.FormatFieldErrors("deprecation", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidDeprecation and field errors for errors.Is() compatibility.
+func (e *InvalidDeprecationError) Unwrap() error {
+	return errors.Join(ErrInvalidDeprecation, errors.Join(e.FieldErrors...))
+}
@@ -5,7 +5,9 @@ package invowkfile
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/invowk/invowk/pkg/types"
@@ -53,6 +55,10 @@ type (
 		// Vars contains environment variables as key-value pairs (optional)
 		// These override values loaded from Files.
 		Vars map[EnvVarName]string `json:"vars,omitempty"`
+		// Secrets maps env var names to sources resolved at execution time
+		// (optional). Secrets override Vars at the same level, and resolved
+		// values are masked in command output.
+		Secrets map[EnvVarName]SecretSource `json:"secrets,omitempty"`
 	}
 )
 
@@ -80,8 +86,9 @@ func (n EnvVarName) String() string { return string(n) }
 
 // Validate returns nil if the EnvConfig has valid fields,
 // or an error collecting all field-level validation failures.
-// Delegates to DotenvFilePath.Validate() for each file and EnvVarName.Validate()
-// for each variable key.
+// Delegates to DotenvFilePath.Validate() for each file, EnvVarName.Validate()
+// for each variable and secret key, and SecretSource.Validate() for each secret.
+// A name cannot be declared both as a var and as a secret.
 func (e EnvConfig) Validate() error {
 	var errs []error
 	for _, f := range e.Files {
@@ -94,6 +101,17 @@ func (e EnvConfig) Validate() error {
 			errs = append(errs, err)
 		}
 	}
+	for _, k := range slices.Sorted(maps.Keys(e.Secrets)) {
+		if err := k.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := e.Secrets[k].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", k, err))
+		}
+		if _, ok := e.Vars[k]; ok {
+			errs = append(errs, fmt.Errorf("%s is declared in both vars and secrets", k))
+		}
+	}
 	if len(errs) > 0 {
 		return &InvalidEnvConfigError{FieldErrors: errs}
 	}
@@ -130,6 +148,14 @@ func (e *EnvConfig) GetVars() map[string]string {
 	return result
 }
 
+// GetSecrets returns the declared secret sources, or nil if EnvConfig is nil.
+func (e *EnvConfig) GetSecrets() map[EnvVarName]SecretSource {
+	if e == nil {
+		return nil
+	}
+	return e.Secrets
+}
+
 // ValidateEnvVarName validates a single environment variable name.
 // [CUE-REDUNDANT] For invowkfile parsing, this is also validated in CUE schema:
 // env_inherit_allow?: [...string & =~"^[A-Za-z_][A-Za-z0-9_]*$"]
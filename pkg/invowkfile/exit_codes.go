@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrSuccessExitCodeZero is returned when success_exit_codes lists exit
+	// code 0, which always counts as success.
+	ErrSuccessExitCodeZero = errors.New("success_exit_codes must not contain 0 (exit code 0 always counts as success)")
+
+	// ErrExitCodeSuccessAndMapped is returned when an exit code is listed in
+	// success_exit_codes and also translated by exit_code_map.
+	ErrExitCodeSuccessAndMapped = errors.New("exit code must not be listed in both success_exit_codes and exit_code_map")
+)
+
+// ExitCodeMap translates the exit codes of an implementation's script into the
+// exit codes reported to invowk and its callers.
+type ExitCodeMap map[types.ExitCode]types.ExitCode
+
+// Validate returns nil if every mapped and translated exit code is valid.
+func (m ExitCodeMap) Validate() error {
+	return errors.Join(validateExitCodePolicy(nil, m)...)
+}
+
+// TranslateExitCode returns the exit code reported for a script run that
+// exited with code: 0 when code is listed in SuccessExitCodes, the translated
+// code when ExitCodeMap maps it, and code itself otherwise.
+func (s *Implementation) TranslateExitCode(code types.ExitCode) types.ExitCode {
+	if slices.Contains(s.SuccessExitCodes, code) {
+		return 0
+	}
+	if translated, ok := s.ExitCodeMap[code]; ok {
+		return translated
+	}
+	return code
+}
+
+// validateExitCodePolicy returns the errors of an implementation's
+// success_exit_codes and exit_code_map, in exit code order.
+func validateExitCodePolicy(successCodes []types.ExitCode, exitCodeMap ExitCodeMap) []error {
+	var errs []error
+	for _, code := range successCodes {
+		switch err := code.Validate(); {
+		case err != nil:
+			errs = append(errs, err)
+		case code.IsSuccess():
+			errs = append(errs, ErrSuccessExitCodeZero)
+		default:
+			if _, ok := exitCodeMap[code]; ok {
+				errs = append(errs, fmt.Errorf("exit code %s: %w", code, ErrExitCodeSuccessAndMapped))
+			}
+		}
+	}
+	for _, from := range slices.Sorted(maps.Keys(exitCodeMap)) {
+		appendFieldError(&errs, from.Validate())
+		appendFieldError(&errs, exitCodeMap[from].Validate())
+	}
+	return errs
+}
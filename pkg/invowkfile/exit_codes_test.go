@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestImplementationTranslateExitCode(t *testing.T) {
+	t.Parallel()
+
+	impl := &Implementation{
+		SuccessExitCodes: []types.ExitCode{1},
+		ExitCodeMap:      ExitCodeMap{0: 3, 2: 10},
+	}
+	tests := []struct {
+		code, want types.ExitCode
+	}{
+		{0, 3},
+		{1, 0},
+		{2, 10},
+		{4, 4},
+	}
+	for _, tt := range tests {
+		if got := impl.TranslateExitCode(tt.code); got != tt.want {
+			t.Errorf("TranslateExitCode(%d) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+	if got := (&Implementation{}).TranslateExitCode(2); got != 2 {
+		t.Errorf("TranslateExitCode(2) without a policy = %d, want 2", got)
+	}
+}
+
+func TestParseExitCodePolicy(t *testing.T) {
+	t.Parallel()
+
+	parse := func(policy string) (*Invowkfile, error) {
+		source := `cmds: [{
+	name: "lint"
+	implementations: [{
+		script: {content: "golangci-lint run"}
+		runtimes: [{name: "native"}]
+		platforms: [{name: "linux"}]
+		` + policy + `
+	}]
+}]`
+		return ParseBytes([]byte(source), "invowkfile.cue")
+	}
+
+	inv, err := parse(`success_exit_codes: [1]
+		exit_code_map: {"2": 1, "0": 0}`)
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+	impl := inv.Commands[0].Implementations[0]
+	if !slices.Equal(impl.SuccessExitCodes, []types.ExitCode{1}) || !maps.Equal(impl.ExitCodeMap, ExitCodeMap{2: 1, 0: 0}) {
+		t.Fatalf("exit code policy = %v / %v, want [1] / map[0:0 2:1]", impl.SuccessExitCodes, impl.ExitCodeMap)
+	}
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr error
+	}{
+		{name: "success and mapped", policy: `success_exit_codes: [1, 2]
+		exit_code_map: {"2": 1}`, wantErr: ErrExitCodeSuccessAndMapped},
+		{name: "key out of range", policy: `exit_code_map: {"256": 1}`, wantErr: types.ErrInvalidExitCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := parse(tt.policy); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseBytes() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	for _, policy := range []string{`success_exit_codes: [0]`, `exit_code_map: {"x": 1}`, `exit_code_map: {"1": 256}`} {
+		if _, err := parse(policy); err == nil {
+			t.Errorf("ParseBytes(%s) succeeded, want a schema error", policy)
+		}
+	}
+}
+
+func TestValidateExitCodePolicy(t *testing.T) {
+	t.Parallel()
+
+	errs := validateExitCodePolicy([]types.ExitCode{0, 1, 300}, ExitCodeMap{1: 0, 2: -1})
+	wants := []error{ErrSuccessExitCodeZero, ErrExitCodeSuccessAndMapped, types.ErrInvalidExitCode, types.ErrInvalidExitCode}
+	if len(errs) != len(wants) {
+		t.Fatalf("validateExitCodePolicy() = %v, want %d errors", errs, len(wants))
+	}
+	for i, want := range wants {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("error %d = %v, want %v", i, errs[i], want)
+		}
+	}
+}
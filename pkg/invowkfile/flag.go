@@ -20,6 +20,14 @@ const (
 	FlagTypeInt FlagType = "int"
 	// FlagTypeFloat is for floating-point flags
 	FlagTypeFloat FlagType = "float"
+	// FlagTypeDuration is for positive Go duration flags (e.g., "30s", "1h30m")
+	FlagTypeDuration FlagType = "duration"
+	// FlagTypePath is for filesystem path flags, resolved relative to the invowkfile
+	FlagTypePath FlagType = "path"
+	// FlagTypeList is for repeatable flags that collect every occurrence
+	FlagTypeList FlagType = "list"
+	// FlagTypeJSON is for JSON document flags, optionally checked against a schema
+	FlagTypeJSON FlagType = "json"
 )
 
 var (
@@ -96,7 +104,7 @@ type (
 		// DefaultValue is the default value for the flag (optional)
 		DefaultValue string `json:"default_value,omitempty"`
 		// Type specifies the data type of the flag (optional, defaults to "string")
-		// Supported types: "string", "bool", "int", "float"
+		// Supported types: "string", "bool", "int", "float", "duration", "path", "list", "json"
 		Type FlagType `json:"type,omitempty"`
 		// Required indicates whether this flag must be provided (optional, defaults to false)
 		Required bool `json:"required,omitempty"`
@@ -104,12 +112,29 @@ type (
 		Short FlagShorthand `json:"short,omitempty"`
 		// Validation is a regex pattern to validate the flag value (optional)
 		Validation RegexPattern `json:"validation,omitempty"`
+		// Choices lists the allowed values for the flag (optional).
+		// Values outside the list are rejected, and shell completion offers the list.
+		Choices []string `json:"choices,omitempty"`
+		// MustExist requires a "path" value to exist before the command runs (optional)
+		MustExist bool `json:"must_exist,omitempty"`
+		// Kind restricts a "path" value to a file or a directory (optional)
+		Kind PathKind `json:"kind,omitempty"`
+		// ListFormat selects how a "list" value is injected: "lines" (default) or "json"
+		ListFormat ListFormat `json:"list_format,omitempty"`
+		// Schema is a CUE constraint that a "json" value must satisfy (optional)
+		Schema ValueSchema `json:"schema,omitempty"`
+		// Prompt asks for the value interactively when the required flag is
+		// missing and invowk runs in a terminal (optional)
+		Prompt *PromptConfig `json:"prompt,omitempty"`
+		// Complete computes the shell completion candidates of the flag with a
+		// script (optional)
+		Complete *CompletionConfig `json:"complete,omitempty"`
 	}
 )
 
 // Error implements the error interface for InvalidFlagTypeError.
 func (e *InvalidFlagTypeError) Error() string {
-	return fmt.Sprintf("invalid flag type %q (valid: string, bool, int, float)", e.Value)
+	return fmt.Sprintf("invalid flag type %q (valid: string, bool, int, float, duration, path, list, json)", e.Value)
 }
 
 // Unwrap returns the sentinel error for errors.Is() compatibility.
@@ -125,7 +150,8 @@ func (ft FlagType) String() string { return string(ft) }
 // Note: the zero value ("") is valid — it is treated as "string" by GetType().
 func (ft FlagType) Validate() error {
 	switch ft {
-	case FlagTypeString, FlagTypeBool, FlagTypeInt, FlagTypeFloat, "":
+	case FlagTypeString, FlagTypeBool, FlagTypeInt, FlagTypeFloat,
+		FlagTypeDuration, FlagTypePath, FlagTypeList, FlagTypeJSON, "":
 		return nil
 	default:
 		return &InvalidFlagTypeError{Value: ft}
@@ -189,7 +215,8 @@ func (s FlagShorthand) String() string { return string(s) }
 // or an error collecting all field-level validation failures.
 // Delegates to Name.Validate() (nonzero), Description.Validate() (non-empty),
 // Type.Validate() (zero-valid), Short.Validate() (zero-valid),
-// Validation.Validate() (zero-valid), and default-value compatibility.
+// Validation.Validate() (zero-valid), default-value compatibility, choices,
+// and the type-specific options (must_exist, kind, list_format, schema).
 func (f Flag) Validate() error {
 	var errs []error
 	if err := f.Name.Validate(); err != nil {
@@ -208,6 +235,10 @@ func (f Flag) Validate() error {
 		errs = append(errs, err)
 	}
 	errs = append(errs, f.defaultValueValidationErrors()...)
+	errs = append(errs, f.choicesValidationErrors()...)
+	errs = append(errs, f.typeOptionsValidationErrors()...)
+	errs = append(errs, promptValidationErrors(f.Prompt, f.Required, f.GetType(), f.Choices, f.Validation)...)
+	errs = append(errs, completionValidationErrors(f.Complete, f.GetType(), f.Choices)...)
 	if len(errs) > 0 {
 		return &InvalidFlagError{FieldErrors: errs}
 	}
@@ -230,18 +261,57 @@ func (f *Flag) GetType() FlagType {
 	return f.Type
 }
 
-// ValidateFlagValue validates a flag value at runtime against type and validation regex.
+// ValidateFlagValue validates a flag value at runtime against type, validation regex,
+// choices, and (for "json" flags) the schema. List values are JSON arrays of
+// strings; choices and the validation regex apply to every item.
 // Returns nil if the value is valid, or an error describing the issue.
 func (f *Flag) ValidateFlagValue(value string) error {
-	if err := validateValueType(value, f.GetType()); err != nil {
+	flagType := f.GetType()
+	if err := validateValueType(value, flagType); err != nil {
 		return fmt.Errorf("flag '%s' value '%s' is invalid: %s", f.Name, value, err.Error())
 	}
-	if err := validateValueWithRegex("flag '"+f.Name.String()+"'", value, string(f.Validation)); err != nil {
+	if err := validateValueElements("flag '"+f.Name.String()+"'", value, flagType, f.Choices, f.Validation); err != nil {
 		return err
 	}
+	if flagType == FlagTypeJSON {
+		if err := f.Schema.Check(value); err != nil {
+			return fmt.Errorf("flag '%s' value is invalid: %w", f.Name, err)
+		}
+	}
 	return nil
 }
 
+// ValidatePathValue checks a "path" flag value against must_exist and kind,
+// resolving relative values against baseDir (the invowkfile directory).
+// Returns nil for other flag types.
+func (f *Flag) ValidatePathValue(baseDir FilesystemPath, value string) error {
+	if f.GetType() != FlagTypePath || value == "" {
+		return nil
+	}
+	return checkPathValue("flag '"+f.Name.String()+"'", ResolveInputPath(baseDir, value), f.MustExist, f.Kind)
+}
+
+// EnvValue returns the INVOWK_FLAG_* value for a validated flag value: "path"
+// values are resolved against baseDir and "list" values are rendered in the
+// flag's ListFormat. Other values are returned unchanged.
+func (f *Flag) EnvValue(baseDir FilesystemPath, value string) string {
+	switch f.GetType() {
+	case FlagTypePath:
+		return string(ResolveInputPath(baseDir, value))
+	case FlagTypeList:
+		if value == "" {
+			return FormatListValue(nil, f.ListFormat)
+		}
+		values, err := ParseListValue(value)
+		if err != nil {
+			return value
+		}
+		return FormatListValue(values, f.ListFormat)
+	default:
+		return value
+	}
+}
+
 func (f Flag) defaultValueValidationErrors() []error {
 	if f.DefaultValue == "" {
 		return nil
@@ -251,11 +321,29 @@ func (f Flag) defaultValueValidationErrors() []error {
 		errs = append(errs, errors.New("cannot be both required and have a default_value"))
 	}
 	flagType := f.GetType()
-	if err := validateValueType(f.DefaultValue, flagType); err != nil {
-		errs = append(errs, fmt.Errorf("default_value %q is not compatible with type %q: %w", f.DefaultValue, flagType, err))
+	typeErr := validateValueType(f.DefaultValue, flagType)
+	if typeErr != nil {
+		errs = append(errs, fmt.Errorf("default_value %q is not compatible with type %q: %w", f.DefaultValue, flagType, typeErr))
 	}
-	if f.Validation.Validate() == nil && !matchesValidation(f.DefaultValue, string(f.Validation)) {
-		errs = append(errs, fmt.Errorf("default_value %q does not match validation pattern %q", f.DefaultValue, f.Validation))
+	if f.Validation.Validate() == nil {
+		for _, element := range valueElements(f.DefaultValue, flagType) {
+			if !matchesValidation(element, string(f.Validation)) {
+				errs = append(errs, fmt.Errorf("default_value %q does not match validation pattern %q", element, f.Validation))
+			}
+		}
+	}
+	if flagType == FlagTypeJSON && typeErr == nil && f.Schema.Validate() == nil {
+		if err := f.Schema.Check(f.DefaultValue); err != nil {
+			errs = append(errs, fmt.Errorf("default_value %q is invalid: %w", f.DefaultValue, err))
+		}
 	}
 	return errs
 }
+
+func (f Flag) choicesValidationErrors() []error {
+	return choicesValidationErrors(f.Choices, f.GetType(), f.Validation, f.DefaultValue)
+}
+
+func (f Flag) typeOptionsValidationErrors() []error {
+	return typeOptionsValidationErrors(f.GetType(), f.MustExist, f.Kind, f.ListFormat, f.Schema)
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+var (
+	// ErrInvalidFlagGroups is the sentinel error wrapped by InvalidFlagGroupsError.
+	ErrInvalidFlagGroups = errors.New("invalid flag groups")
+
+	// ErrFlagGroupTooSmall is returned when a flag group lists fewer than two flags.
+	ErrFlagGroupTooSmall = errors.New("flag group must list at least two flags")
+
+	// ErrEmptyFlagGroups is returned when a flag_groups block declares no group.
+	ErrEmptyFlagGroups = errors.New("flag_groups must declare at least one group")
+)
+
+type (
+	// FlagGroup is a set of flag names that a flag group constraint applies to.
+	FlagGroup []FlagName
+
+	// InvalidFlagGroupsError is returned when a FlagGroups has invalid fields.
+	// It wraps ErrInvalidFlagGroups for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidFlagGroupsError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// FlagGroups declares constraints between the flags of a command. The
+	// constraints are enforced by the CLI when the command is invoked; only
+	// flags set on the command line count, defaults do not.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	FlagGroups struct {
+		// MutuallyExclusive lists groups of flags of which at most one may be set.
+		MutuallyExclusive []FlagGroup `json:"mutually_exclusive,omitempty"`
+		// RequiredTogether lists groups of flags that must be set together:
+		// setting any flag of a group requires setting all of them.
+		RequiredTogether []FlagGroup `json:"required_together,omitempty"`
+		// OneRequired lists groups of flags of which at least one must be set.
+		OneRequired []FlagGroup `json:"one_required,omitempty"`
+	}
+)
+
+// Validate returns nil if the FlagGroup lists at least two distinct, valid
+// flag names.
+func (g FlagGroup) Validate() error {
+	var errs []error
+	if len(g) < 2 {
+		errs = append(errs, ErrFlagGroupTooSmall)
+	}
+	seen := make(map[FlagName]bool, len(g))
+	for _, name := range g {
+		appendFieldError(&errs, name.Validate())
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("flag group lists '%s' more than once", name))
+		}
+		seen[name] = true
+	}
+	return errors.Join(errs...)
+}
+
+// Strings returns the flag names of the group as plain strings, in order.
+func (g FlagGroup) Strings() []string {
+	names := make([]string, len(g))
+	for i, name := range g {
+		names[i] = string(name)
+	}
+	return names
+}
+
+// Validate returns nil if the FlagGroups declares at least one valid group,
+// or an error collecting all field-level validation failures.
+func (fg FlagGroups) Validate() error {
+	var errs []error
+	if fg.IsEmpty() {
+		errs = append(errs, ErrEmptyFlagGroups)
+	}
+	for _, group := range fg.MutuallyExclusive {
+		appendFieldError(&errs, group.Validate())
+	}
+	for _, group := range fg.RequiredTogether {
+		appendFieldError(&errs, group.Validate())
+	}
+	for _, group := range fg.OneRequired {
+		appendFieldError(&errs, group.Validate())
+	}
+	if len(errs) > 0 {
+		return &InvalidFlagGroupsError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidFlagGroupsError.
+func (e *InvalidFlagGroupsError) Error() string {
+	return types.FormatFieldErrors("flag groups", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidFlagGroups and field errors for errors.Is() compatibility.
+func (e *InvalidFlagGroupsError) Unwrap() error {
+	return errors.Join(ErrInvalidFlagGroups, errors.Join(e.FieldErrors...))
+}
+
+// IsEmpty returns true if the FlagGroups declares no group.
+func (fg *FlagGroups) IsEmpty() bool {
+	return len(fg.MutuallyExclusive) == 0 && len(fg.RequiredTogether) == 0 && len(fg.OneRequired) == 0
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFlagGroupsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		groups  FlagGroups
+		wantErr string
+	}{
+		{name: "all kinds", groups: FlagGroups{
+			MutuallyExclusive: []FlagGroup{{"tag", "latest"}},
+			RequiredTogether:  []FlagGroup{{"user", "password-file"}},
+			OneRequired:       []FlagGroup{{"tag", "latest", "digest"}},
+		}},
+		{name: "empty", groups: FlagGroups{}, wantErr: ErrEmptyFlagGroups.Error()},
+		{name: "single flag", groups: FlagGroups{OneRequired: []FlagGroup{{"tag"}}}, wantErr: ErrFlagGroupTooSmall.Error()},
+		{name: "duplicate flag", groups: FlagGroups{MutuallyExclusive: []FlagGroup{{"tag", "tag"}}}, wantErr: "flag group lists 'tag' more than once"},
+		{name: "invalid flag name", groups: FlagGroups{RequiredTogether: []FlagGroup{{"user", "-password"}}}, wantErr: "invalid flag name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.groups.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidFlagGroups) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidFlagGroups", err)
+			}
+			var groupsErr *InvalidFlagGroupsError
+			if !errors.As(err, &groupsErr) || !fieldErrorsContain(groupsErr.FieldErrors, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want a field error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStructureValidatorValidateFlagGroups(t *testing.T) {
+	t.Parallel()
+
+	const suffix = " in invowkfile at flags.cue"
+	tests := []struct {
+		name        string
+		groups      FlagGroups
+		wantMessage string
+	}{
+		{name: "undeclared flag", groups: FlagGroups{RequiredTogether: []FlagGroup{{"user", "password-file"}}}, wantMessage: "flag group lists undeclared flag 'password-file'" + suffix},
+		{name: "required exclusive flags", groups: FlagGroups{MutuallyExclusive: []FlagGroup{{"tag", "user", "latest"}}}, wantMessage: "mutually exclusive flags --tag, --user are all required" + suffix},
+		{name: "too small", groups: FlagGroups{OneRequired: []FlagGroup{{"latest"}}}, wantMessage: ErrFlagGroupTooSmall.Error() + suffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := structureFlagMutationCommand()
+			cmd.Flags = []Flag{
+				{Name: "tag", Description: "Image tag", Required: true},
+				{Name: "latest", Description: "Use the latest image", Type: FlagTypeBool},
+				{Name: "user", Description: "Registry user", Required: true},
+			}
+			cmd.FlagGroups = &tt.groups
+
+			errs := NewStructureValidator().validateFlagGroups(structureFlagMutationContext(), &cmd)
+			if len(errs) != 1 {
+				t.Fatalf("validateFlagGroups() returned %d errors, want 1: %v", len(errs), errs)
+			}
+			assertStructureFlagError(t, errs[0], "command 'deploy' flag_groups", []string{tt.wantMessage}, true)
+		})
+	}
+}
@@ -18,7 +18,7 @@ func TestFlagValueTypeMutationContracts(t *testing.T) {
 	if !errors.Is(typeErr, ErrInvalidFlagType) {
 		t.Fatalf("InvalidFlagTypeError does not wrap ErrInvalidFlagType: %v", typeErr)
 	}
-	if got := typeErr.Error(); got != `invalid flag type "bogus" (valid: string, bool, int, float)` {
+	if got := typeErr.Error(); got != `invalid flag type "bogus" (valid: string, bool, int, float, duration, path, list, json)` {
 		t.Fatalf("InvalidFlagTypeError.Error() = %q, want invalid type diagnostic", got)
 	}
 
@@ -4,6 +4,7 @@ package invowkfile
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -119,6 +120,181 @@ func assertFlagValidateDefaultValue(t *testing.T, flag Flag, wantErr string) {
 	}
 }
 
+func TestFlag_Validate_Choices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flag    Flag
+		wantErr string
+	}{
+		{
+			name: "valid choices with default",
+			flag: Flag{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}, DefaultValue: "dev"},
+		},
+		{
+			name:    "default not in choices",
+			flag:    Flag{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}, DefaultValue: "qa"},
+			wantErr: "is not one of the choices",
+		},
+		{
+			name:    "duplicate choice",
+			flag:    Flag{Name: "env", Description: "Target environment", Choices: []string{"dev", "dev"}},
+			wantErr: "duplicate choice",
+		},
+		{
+			name:    "choice incompatible with type",
+			flag:    Flag{Name: "replicas", Description: "Replica count", Type: FlagTypeInt, Choices: []string{"1", "many"}},
+			wantErr: "is not compatible with type",
+		},
+		{
+			name:    "choice does not match validation",
+			flag:    Flag{Name: "env", Description: "Target environment", Validation: "^[a-z]+$", Choices: []string{"dev", "PROD"}},
+			wantErr: "does not match validation pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertFlagValidateDefaultValue(t, tt.flag, tt.wantErr)
+		})
+	}
+}
+
+func TestFlag_ValidateFlagValue_Choices(t *testing.T) {
+	t.Parallel()
+
+	flag := Flag{Name: "env", Description: "Target environment", Choices: []string{"dev", "prod"}}
+	if err := flag.ValidateFlagValue("prod"); err != nil {
+		t.Fatalf("ValidateFlagValue(prod) returned error: %v", err)
+	}
+	err := flag.ValidateFlagValue("qa")
+	if err == nil || !strings.Contains(err.Error(), "is not one of the allowed choices: dev, prod") {
+		t.Fatalf("ValidateFlagValue(qa) error = %v, want choices error", err)
+	}
+}
+
+func TestFlag_Validate_TypeOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flag    Flag
+		wantErr string
+	}{
+		{
+			name: "duration default",
+			flag: Flag{Name: "wait", Description: "Wait time", Type: FlagTypeDuration, DefaultValue: "1m30s"},
+		},
+		{
+			name:    "invalid duration default",
+			flag:    Flag{Name: "wait", Description: "Wait time", Type: FlagTypeDuration, DefaultValue: "soon"},
+			wantErr: "must be a valid positive duration",
+		},
+		{
+			name: "path with kind",
+			flag: Flag{Name: "out", Description: "Output dir", Type: FlagTypePath, MustExist: true, Kind: PathKindDir},
+		},
+		{
+			name:    "kind without path type",
+			flag:    Flag{Name: "out", Description: "Output dir", Kind: PathKindDir},
+			wantErr: `must_exist and kind require type "path"`,
+		},
+		{
+			name:    "invalid kind",
+			flag:    Flag{Name: "out", Description: "Output dir", Type: FlagTypePath, Kind: "socket"},
+			wantErr: "invalid path kind",
+		},
+		{
+			name: "list default with choices",
+			flag: Flag{Name: "tag", Description: "Tags", Type: FlagTypeList, ListFormat: ListFormatJSON, Choices: []string{"a", "b"}, DefaultValue: `["a","b"]`},
+		},
+		{
+			name:    "list default not an array",
+			flag:    Flag{Name: "tag", Description: "Tags", Type: FlagTypeList, DefaultValue: "a"},
+			wantErr: "must be a JSON array of strings",
+		},
+		{
+			name:    "list item not in choices",
+			flag:    Flag{Name: "tag", Description: "Tags", Type: FlagTypeList, Choices: []string{"a"}, DefaultValue: `["a","c"]`},
+			wantErr: "is not one of the choices",
+		},
+		{
+			name:    "list_format without list type",
+			flag:    Flag{Name: "tag", Description: "Tags", ListFormat: ListFormatJSON},
+			wantErr: `list_format requires type "list"`,
+		},
+		{
+			name: "json default satisfies schema",
+			flag: Flag{Name: "cfg", Description: "Config", Type: FlagTypeJSON, Schema: "{replicas: int & >0}", DefaultValue: `{"replicas": 2}`},
+		},
+		{
+			name:    "json default violates schema",
+			flag:    Flag{Name: "cfg", Description: "Config", Type: FlagTypeJSON, Schema: "{replicas: int & >0}", DefaultValue: `{"replicas": 0}`},
+			wantErr: "does not satisfy schema",
+		},
+		{
+			name:    "invalid schema",
+			flag:    Flag{Name: "cfg", Description: "Config", Type: FlagTypeJSON, Schema: "{replicas: "},
+			wantErr: "invalid value schema",
+		},
+		{
+			name:    "schema without json type",
+			flag:    Flag{Name: "cfg", Description: "Config", Schema: "string"},
+			wantErr: `schema requires type "json"`,
+		},
+		{
+			name:    "choices on json flag",
+			flag:    Flag{Name: "cfg", Description: "Config", Type: FlagTypeJSON, Choices: []string{"{}"}},
+			wantErr: "choices are not supported for json values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assertFlagValidateDefaultValue(t, tt.flag, tt.wantErr)
+		})
+	}
+}
+
+func TestFlag_ValidateFlagValue_TypedValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flag    Flag
+		value   string
+		wantErr string
+	}{
+		{name: "duration", flag: Flag{Name: "wait", Type: FlagTypeDuration}, value: "5m"},
+		{name: "invalid duration", flag: Flag{Name: "wait", Type: FlagTypeDuration}, value: "-5m", wantErr: "must be a valid positive duration"},
+		{name: "path", flag: Flag{Name: "out", Type: FlagTypePath}, value: "dist/app"},
+		{name: "list items match validation", flag: Flag{Name: "tag", Type: FlagTypeList, Validation: "^v[0-9]+$"}, value: `["v1","v2"]`},
+		{name: "list item fails validation", flag: Flag{Name: "tag", Type: FlagTypeList, Validation: "^v[0-9]+$"}, value: `["v1","latest"]`, wantErr: "does not match required pattern"},
+		{name: "invalid json", flag: Flag{Name: "cfg", Type: FlagTypeJSON}, value: "{", wantErr: "must be valid JSON"},
+		{name: "json violates schema", flag: Flag{Name: "cfg", Type: FlagTypeJSON, Schema: "{name: string}"}, value: `{"name": 1}`, wantErr: "does not satisfy schema"},
+		{name: "json closed by schema", flag: Flag{Name: "cfg", Type: FlagTypeJSON, Schema: "close({name: string})"}, value: `{"name": "a", "extra": true}`, wantErr: "does not satisfy schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.flag.ValidateFlagValue(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateFlagValue(%q) returned error: %v", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateFlagValue(%q) error = %v, want containing %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestFlag_Validate_MissingDescription(t *testing.T) {
 	t.Parallel()
 	f := Flag{Name: "verbose"}
@@ -3,10 +3,14 @@
 package invowkfile
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"slices"
 	"strings"
+
+	"github.com/invowk/invowk/pkg/types"
 )
 
 const (
@@ -39,9 +43,21 @@ func GenerateCUE(inv *Invowkfile) string {
 	// Root-level depends_on
 	generateDependsOn(&sb, inv.DependsOn, "\t")
 
-	// Commands
+	// Root-level hooks
+	generateHooks(&sb, inv.Hooks, "")
+
+	// Templates (commands are emitted expanded; templates are kept for export)
+	generateTemplates(&sb, inv.Templates)
+
+	// Imports
+	generateImports(&sb, inv.Imports)
+
+	// Commands (imported commands stay in their fragments)
 	sb.WriteString("\ncmds: [\n")
 	for i := range inv.Commands {
+		if inv.Commands[i].ImportedFrom != "" {
+			continue
+		}
 		generateCommand(&sb, &inv.Commands[i])
 	}
 	sb.WriteString("]\n")
@@ -49,10 +65,43 @@ func GenerateCUE(inv *Invowkfile) string {
 	return sb.String()
 }
 
+// generateTemplates generates the top-level templates map. Template bodies
+// are written as indented JSON, which is valid CUE.
+func generateTemplates(sb *strings.Builder, templates map[TemplateName]Template) {
+	if len(templates) == 0 {
+		return
+	}
+	sb.WriteString("templates: {\n")
+	for _, name := range slices.Sorted(maps.Keys(templates)) {
+		var body bytes.Buffer
+		if err := json.Indent(&body, templates[name], "\t", "\t"); err != nil {
+			body.Reset()
+			body.WriteString("{}")
+		}
+		fmt.Fprintf(sb, "\t%q: %s\n", name, body.String())
+	}
+	sb.WriteString("}\n")
+}
+
+// generateImports generates the top-level imports list.
+func generateImports(sb *strings.Builder, imports []ImportPattern) {
+	if len(imports) == 0 {
+		return
+	}
+	sb.WriteString("imports: [")
+	for i, pattern := range imports {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%q", pattern)
+	}
+	sb.WriteString("]\n")
+}
+
 // generateEnvBlock generates a CUE env: {...} block at the given indentation.
-// No-op when env is nil or has no files/vars.
+// No-op when env is nil or has no files/vars/secrets.
 func generateEnvBlock(sb *strings.Builder, env *EnvConfig, indent string) {
-	if env == nil || (len(env.Files) == 0 && len(env.Vars) == 0) {
+	if env == nil || (len(env.Files) == 0 && len(env.Vars) == 0 && len(env.Secrets) == 0) {
 		return
 	}
 	sb.WriteString(indent + "env: {\n")
@@ -73,9 +122,37 @@ func generateEnvBlock(sb *strings.Builder, env *EnvConfig, indent string) {
 		}
 		sb.WriteString(indent + "\t}\n")
 	}
+	if len(env.Secrets) > 0 {
+		sb.WriteString(indent + "\tsecrets: {\n")
+		for _, k := range slices.Sorted(maps.Keys(env.Secrets)) {
+			fmt.Fprintf(sb, "%s\t\t%s: ", indent, k)
+			generateSecretSource(sb, env.Secrets[k])
+			sb.WriteString("\n")
+		}
+		sb.WriteString(indent + "\t}\n")
+	}
 	sb.WriteString(indent + "}\n")
 }
 
+// generateSecretSource generates an inline {provider: ...} secret source.
+func generateSecretSource(sb *strings.Builder, secret SecretSource) {
+	fmt.Fprintf(sb, "{provider: %q", secret.Provider)
+	generateInlineStringList(sb, "command", secret.Command)
+	if secret.Path != "" {
+		fmt.Fprintf(sb, ", path: %q", secret.Path)
+	}
+	if secret.Var != "" {
+		fmt.Fprintf(sb, ", var: %q", secret.Var)
+	}
+	if secret.Service != "" {
+		fmt.Fprintf(sb, ", service: %q", secret.Service)
+	}
+	if secret.Account != "" {
+		fmt.Fprintf(sb, ", account: %q", secret.Account)
+	}
+	sb.WriteString("}")
+}
+
 // generateDependsOn generates CUE for a DependsOn block at any nesting level.
 // The indent parameter controls the indentation depth for the block's fields.
 func generateDependsOn(sb *strings.Builder, deps *DependsOn, indent string) {
@@ -102,13 +179,42 @@ func generateCommand(sb *strings.Builder, cmd *Command) {
 	if cmd.Category != "" {
 		fmt.Fprintf(sb, "\t\tcategory: %q\n", cmd.Category)
 	}
+	if cmd.Visibility != "" {
+		fmt.Fprintf(sb, "\t\tvisibility: %q\n", cmd.Visibility)
+	}
+	if len(cmd.Aliases) > 0 {
+		sb.WriteString("\t\taliases: [")
+		for i, alias := range cmd.Aliases {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%q", alias)
+		}
+		sb.WriteString("]\n")
+	}
+	generateDeprecation(sb, cmd.Deprecated)
+	generateConfirm(sb, cmd.Confirm)
+	generateLock(sb, cmd.Lock)
+	generateMatrix(sb, cmd.Matrix)
+	generateOutputs(sb, cmd.Outputs)
 
 	// Generate implementations list
-	sb.WriteString("\t\timplementations: [\n")
-	for i := range cmd.Implementations {
-		generateImplementation(sb, &cmd.Implementations[i])
+	if len(cmd.Implementations) > 0 {
+		sb.WriteString("\t\timplementations: [\n")
+		for i := range cmd.Implementations {
+			generateImplementation(sb, &cmd.Implementations[i])
+		}
+		sb.WriteString(cueCloseList)
+	}
+
+	// Generate steps list
+	if len(cmd.Steps) > 0 {
+		sb.WriteString("\t\tsteps: [\n")
+		for i := range cmd.Steps {
+			generateCommandStep(sb, &cmd.Steps[i])
+		}
+		sb.WriteString(cueCloseList)
 	}
-	sb.WriteString(cueCloseList)
 
 	// Command-level env
 	generateEnvBlock(sb, cmd.Env, "\t\t")
@@ -141,10 +247,15 @@ func generateCommand(sb *strings.Builder, cmd *Command) {
 			if flag.Validation != "" {
 				fmt.Fprintf(sb, cueValidationField, flag.Validation)
 			}
+			generateChoices(sb, flag.Choices)
+			generateTypeOptions(sb, flag.MustExist, flag.Kind, flag.ListFormat, flag.Schema)
+			generatePrompt(sb, flag.Prompt)
+			generateCompletion(sb, flag.Complete)
 			sb.WriteString("},\n")
 		}
 		sb.WriteString(cueCloseList)
 	}
+	generateFlagGroups(sb, cmd.FlagGroups)
 
 	// Generate watch config
 	if cmd.Watch != nil && len(cmd.Watch.Patterns) > 0 {
@@ -176,6 +287,13 @@ func generateCommand(sb *strings.Builder, cmd *Command) {
 		sb.WriteString("\t\t}\n")
 	}
 
+	// Incremental execution patterns
+	generateGlobList(sb, "sources", cmd.Sources, "\t\t")
+	generateGlobList(sb, "generates", cmd.Generates, "\t\t")
+
+	// Command-level hooks
+	generateHooks(sb, cmd.Hooks, "\t\t")
+
 	// Generate args list
 	if len(cmd.Args) > 0 {
 		sb.WriteString("\t\targs: [\n")
@@ -194,6 +312,10 @@ func generateCommand(sb *strings.Builder, cmd *Command) {
 			if arg.Validation != "" {
 				fmt.Fprintf(sb, cueValidationField, arg.Validation)
 			}
+			generateChoices(sb, arg.Choices)
+			generateTypeOptions(sb, arg.MustExist, arg.Kind, "", arg.Schema)
+			generatePrompt(sb, arg.Prompt)
+			generateCompletion(sb, arg.Complete)
 			if arg.Variadic {
 				sb.WriteString(", variadic: true")
 			}
@@ -205,6 +327,413 @@ func generateCommand(sb *strings.Builder, cmd *Command) {
 	sb.WriteString("\t},\n")
 }
 
+// generateGlobList generates a single-line CUE list field of glob patterns.
+// Nothing is written for an empty list.
+func generateGlobList(sb *strings.Builder, field string, patterns []GlobPattern, indent string) {
+	if len(patterns) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "%s%s: [", indent, field)
+	for i, p := range patterns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%q", p)
+	}
+	sb.WriteString("]\n")
+}
+
+// generateChoices generates an inline ", choices: [...]" field for a flag or
+// argument. Nothing is written for an empty list.
+func generateChoices(sb *strings.Builder, choices []string) {
+	if len(choices) == 0 {
+		return
+	}
+	sb.WriteString(", choices: [")
+	for i, choice := range choices {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%q", choice)
+	}
+	sb.WriteString("]")
+}
+
+// generateTypeOptions generates the inline type-specific fields of a flag or
+// argument (must_exist, kind, list_format, schema). Unset fields are skipped.
+func generateTypeOptions(sb *strings.Builder, mustExist bool, kind PathKind, format ListFormat, schema ValueSchema) {
+	if mustExist {
+		sb.WriteString(", must_exist: true")
+	}
+	if kind != "" {
+		fmt.Fprintf(sb, ", kind: %q", kind)
+	}
+	if format != "" {
+		fmt.Fprintf(sb, ", list_format: %q", format)
+	}
+	if schema != "" {
+		fmt.Fprintf(sb, ", schema: %q", schema)
+	}
+}
+
+// generatePrompt generates an inline ", prompt: {...}" field for a flag or
+// argument. Nothing is written for a nil prompt.
+func generatePrompt(sb *strings.Builder, prompt *PromptConfig) {
+	if prompt == nil {
+		return
+	}
+	fmt.Fprintf(sb, ", prompt: {type: %q, message: %q", prompt.Type, prompt.Message)
+	generateInlineStringList(sb, "options", prompt.Options)
+	if prompt.Placeholder != "" {
+		fmt.Fprintf(sb, ", placeholder: %q", prompt.Placeholder)
+	}
+	if prompt.CharLimit > 0 {
+		fmt.Fprintf(sb, ", char_limit: %d", prompt.CharLimit)
+	}
+	generateInlineStringList(sb, "extensions", prompt.Extensions)
+	sb.WriteString("}")
+}
+
+// generateCompletion generates an inline ", complete: {...}" field for a flag
+// or argument. Nothing is written for a nil complete block.
+func generateCompletion(sb *strings.Builder, complete *CompletionConfig) {
+	if complete == nil {
+		return
+	}
+	if complete.Script.IsFile() {
+		fmt.Fprintf(sb, ", complete: {script: {%s}", formatScriptFileFields(*complete.Script.File, complete.Script.Interpreter))
+	} else {
+		fmt.Fprintf(sb, ", complete: {script: {%s}", formatScriptContentFields(complete.Script.Content, complete.Script.Interpreter))
+	}
+	if complete.Runtime != "" {
+		fmt.Fprintf(sb, ", runtime: %q", complete.Runtime)
+	}
+	if complete.Timeout != "" {
+		fmt.Fprintf(sb, ", timeout: %q", complete.Timeout)
+	}
+	sb.WriteString("}")
+}
+
+// generateInlineStringList generates an inline ", field: [...]" list of quoted
+// strings. Nothing is written for an empty list.
+func generateInlineStringList(sb *strings.Builder, field string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, ", %s: [", field)
+	for i, value := range values {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%q", value)
+	}
+	sb.WriteString("]")
+}
+
+// generateRetryPolicy generates CUE for a single-line retry: {...} block.
+// Nothing is written for a nil policy.
+func generateRetryPolicy(sb *strings.Builder, retry *RetryPolicy, indent string) {
+	if retry == nil {
+		return
+	}
+	fmt.Fprintf(sb, "%sretry: {attempts: %d", indent, retry.Attempts)
+	if retry.Backoff != "" {
+		fmt.Fprintf(sb, ", backoff: %q", retry.Backoff)
+	}
+	if retry.MaxBackoff != "" {
+		fmt.Fprintf(sb, ", max_backoff: %q", retry.MaxBackoff)
+	}
+	if len(retry.OnExitCodes) > 0 {
+		sb.WriteString(", on_exit_codes: [")
+		for i, code := range retry.OnExitCodes {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%d", code)
+		}
+		sb.WriteString("]")
+	}
+	sb.WriteString("}\n")
+}
+
+// generateExitCodePolicy generates CUE for the success_exit_codes and
+// exit_code_map of an implementation, each on a single line.
+func generateExitCodePolicy(sb *strings.Builder, successCodes []types.ExitCode, exitCodeMap ExitCodeMap, indent string) {
+	if len(successCodes) > 0 {
+		fmt.Fprintf(sb, "%ssuccess_exit_codes: [", indent)
+		for i, code := range successCodes {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%d", code)
+		}
+		sb.WriteString("]\n")
+	}
+	if len(exitCodeMap) > 0 {
+		fmt.Fprintf(sb, "%sexit_code_map: {", indent)
+		for i, from := range slices.Sorted(maps.Keys(exitCodeMap)) {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "\"%d\": %d", from, exitCodeMap[from])
+		}
+		sb.WriteString("}\n")
+	}
+}
+
+// generateDeprecation generates CUE for a command's deprecated: {...} block.
+// Nothing is written for a nil deprecation.
+func generateDeprecation(sb *strings.Builder, dep *Deprecation) {
+	if dep == nil {
+		return
+	}
+	fmt.Fprintf(sb, "\t\tdeprecated: {message: %q", dep.Message)
+	if dep.Replacement != "" {
+		fmt.Fprintf(sb, ", replacement: %q", dep.Replacement)
+	}
+	if dep.Forward {
+		sb.WriteString(", forward: true")
+	}
+	sb.WriteString("}\n")
+}
+
+// generateConfirm generates CUE for a command's confirm: {...} block.
+func generateConfirm(sb *strings.Builder, confirm *ConfirmConfig) {
+	if confirm == nil {
+		return
+	}
+	fmt.Fprintf(sb, "\t\tconfirm: {message: %q", confirm.Message)
+	if confirm.Default {
+		sb.WriteString(", default: true")
+	}
+	sb.WriteString("}\n")
+}
+
+// generateLock generates CUE for a command's lock: {...} block.
+func generateLock(sb *strings.Builder, lock *CommandLock) {
+	if lock == nil {
+		return
+	}
+	var fields []string
+	if lock.Name != "" {
+		fields = append(fields, fmt.Sprintf("name: %q", lock.Name))
+	}
+	if lock.Scope != "" {
+		fields = append(fields, fmt.Sprintf("scope: %q", lock.Scope))
+	}
+	if lock.Wait {
+		fields = append(fields, "wait: true")
+	}
+	if lock.Timeout != "" {
+		fields = append(fields, fmt.Sprintf("timeout: %q", lock.Timeout))
+	}
+	fmt.Fprintf(sb, "\t\tlock: {%s}\n", strings.Join(fields, ", "))
+}
+
+// generateMatrix generates CUE for a command's matrix: {...} block.
+func generateMatrix(sb *strings.Builder, matrix *CommandMatrix) {
+	if matrix == nil {
+		return
+	}
+	sb.WriteString("\t\tmatrix: {\n")
+	if len(matrix.Vars) > 0 {
+		sb.WriteString("\t\t\tvars: {\n")
+		for _, name := range slices.Sorted(maps.Keys(matrix.Vars)) {
+			values := make([]string, len(matrix.Vars[name]))
+			for i, value := range matrix.Vars[name] {
+				values[i] = fmt.Sprintf("%q", value)
+			}
+			fmt.Fprintf(sb, "\t\t\t\t%s: [%s]\n", name, strings.Join(values, ", "))
+		}
+		sb.WriteString("\t\t\t}\n")
+	}
+	generateMatrixCombinations(sb, "include", matrix.Include)
+	generateMatrixCombinations(sb, "exclude", matrix.Exclude)
+	if matrix.Parallel {
+		sb.WriteString("\t\t\tparallel: true\n")
+	}
+	sb.WriteString("\t\t}\n")
+}
+
+// generateOutputs generates CUE for a command's outputs: [...] list.
+func generateOutputs(sb *strings.Builder, outputs []CommandOutput) {
+	if len(outputs) == 0 {
+		return
+	}
+	sb.WriteString("\t\toutputs: [\n")
+	for _, output := range outputs {
+		if output.Description != "" {
+			fmt.Fprintf(sb, "\t\t\t{name: %q, description: %q},\n", output.Name, output.Description)
+			continue
+		}
+		fmt.Fprintf(sb, "\t\t\t{name: %q},\n", output.Name)
+	}
+	sb.WriteString("\t\t]\n")
+}
+
+// generateMatrixCombinations generates CUE for a matrix include or exclude list.
+func generateMatrixCombinations(sb *strings.Builder, field string, combinations []MatrixCombination) {
+	if len(combinations) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\t\t\t%s: [\n", field)
+	for _, combination := range combinations {
+		fields := make([]string, 0, len(combination))
+		for _, name := range combination.names() {
+			fields = append(fields, fmt.Sprintf("%s: %q", name, combination[name]))
+		}
+		fmt.Fprintf(sb, "\t\t\t\t{%s},\n", strings.Join(fields, ", "))
+	}
+	sb.WriteString("\t\t\t]\n")
+}
+
+// generateFlagGroups generates CUE for a command's flag_groups: {...} block.
+// Nothing is written for nil or empty flag groups.
+func generateFlagGroups(sb *strings.Builder, groups *FlagGroups) {
+	if groups == nil || groups.IsEmpty() {
+		return
+	}
+	sb.WriteString("\t\tflag_groups: {\n")
+	for _, kind := range []struct {
+		field  string
+		groups []FlagGroup
+	}{
+		{"mutually_exclusive", groups.MutuallyExclusive},
+		{"required_together", groups.RequiredTogether},
+		{"one_required", groups.OneRequired},
+	} {
+		if len(kind.groups) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "\t\t\t%s: [", kind.field)
+		for i, group := range kind.groups {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("[")
+			for j, name := range group {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(sb, "%q", name)
+			}
+			sb.WriteString("]")
+		}
+		sb.WriteString("]\n")
+	}
+	sb.WriteString("\t\t}\n")
+}
+
+// generateWhen generates CUE for a when: {...} block at the given indentation.
+// Nothing is written for a nil or empty condition.
+func generateWhen(sb *strings.Builder, when *When, indent string) {
+	if when == nil || when.IsEmpty() {
+		return
+	}
+	sb.WriteString(indent + "when: {\n")
+	generateInlineStringMap(sb, indent+"\tenv", when.Env)
+	generateInlineStringMap(sb, indent+"\tflags", when.Flags)
+	generateInlineStringMap(sb, indent+"\targs", when.Args)
+	if len(when.Files) > 0 {
+		sb.WriteString(indent + "\tfiles: [")
+		for i, path := range when.Files {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%q", path)
+		}
+		sb.WriteString("]\n")
+	}
+	if when.Arch != "" {
+		fmt.Fprintf(sb, "%s\tarch: %q\n", indent, when.Arch)
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+// generateInlineStringMap generates a single-line `field: {"k": "v", ...}`
+// map with sorted keys. Nothing is written for an empty map.
+func generateInlineStringMap[K ~string](sb *strings.Builder, field string, values map[K]string) {
+	if len(values) == 0 {
+		return
+	}
+	sb.WriteString(field + ": {")
+	for i, key := range slices.Sorted(maps.Keys(values)) {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%q: %q", key, values[key])
+	}
+	sb.WriteString("}\n")
+}
+
+// generateHooks generates CUE for a hooks: {...} block at the given indentation.
+// Nothing is written when no phase declares a hook.
+func generateHooks(sb *strings.Builder, hooks *Hooks, indent string) {
+	if hooks.IsEmpty() {
+		return
+	}
+	sb.WriteString(indent + "hooks: {\n")
+	for _, phase := range []HookPhase{HookPhaseBefore, HookPhaseAfter, HookPhaseFinally} {
+		phaseHooks := hooks.Phase(phase)
+		if len(phaseHooks) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "%s\t%s: [\n", indent, phase)
+		for i := range phaseHooks {
+			sb.WriteString("\t\t\t{\n")
+			generateImplementationScript(sb, phaseHooks[i].Script)
+			if phaseHooks[i].Runtime != "" {
+				fmt.Fprintf(sb, "\t\t\t\truntime: %q\n", phaseHooks[i].Runtime)
+			}
+			sb.WriteString("\t\t\t},\n")
+		}
+		sb.WriteString(indent + "\t]\n")
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+// generateCommandStep generates CUE for a single sequential command step.
+func generateCommandStep(sb *strings.Builder, step *CommandStep) {
+	sb.WriteString("\t\t\t{\n")
+	if step.Name != "" {
+		fmt.Fprintf(sb, "\t\t\t\tname: %q\n", step.Name)
+	}
+	if step.IsCommand() {
+		fmt.Fprintf(sb, "\t\t\t\tcmd: %q\n", step.Cmd)
+		if len(step.Flags) > 0 {
+			sb.WriteString("\t\t\t\tflags: {")
+			names := slices.Sorted(maps.Keys(step.Flags))
+			for i, name := range names {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(sb, "%q: %q", name, step.Flags[name])
+			}
+			sb.WriteString("}\n")
+		}
+		if len(step.Args) > 0 {
+			sb.WriteString("\t\t\t\targs: [")
+			for i, arg := range step.Args {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(sb, "%q", arg)
+			}
+			sb.WriteString("]\n")
+		}
+	}
+	if step.Script != nil {
+		generateImplementationScript(sb, *step.Script)
+	}
+	if step.Runtime != "" {
+		fmt.Fprintf(sb, "\t\t\t\truntime: %q\n", step.Runtime)
+	}
+	if step.ContinueOnError {
+		sb.WriteString("\t\t\t\tcontinue_on_error: true\n")
+	}
+	sb.WriteString("\t\t\t},\n")
+}
+
 // generateImplementation generates CUE for a single implementation
 func generateImplementation(sb *strings.Builder, impl *Implementation) {
 	sb.WriteString("\t\t\t{\n")
@@ -242,16 +771,39 @@ func generateImplementation(sb *strings.Builder, impl *Implementation) {
 		fmt.Fprintf(sb, "\t\t\t\ttimeout: %q\n", impl.Timeout)
 	}
 
+	// Implementation-level retry policy
+	generateRetryPolicy(sb, impl.Retry, "\t\t\t\t")
+
+	// Implementation-level exit code policy
+	generateExitCodePolicy(sb, impl.SuccessExitCodes, impl.ExitCodeMap, "\t\t\t\t")
+
+	// Implementation selection conditions
+	generateWhen(sb, impl.When, "\t\t\t\t")
+
+	// Implementation-level incremental patterns
+	generateGlobList(sb, "sources", impl.Sources, "\t\t\t\t")
+	generateGlobList(sb, "generates", impl.Generates, "\t\t\t\t")
+
 	sb.WriteString("\t\t\t},\n")
 }
 
 func generatePlatformConfig(sb *strings.Builder, platform PlatformConfig, indent string) {
-	if platform.Virtual == nil || !platform.Virtual.HasConfig() {
+	if len(platform.Arch) == 0 && (platform.Virtual == nil || !platform.Virtual.HasConfig()) {
 		fmt.Fprintf(sb, "%s{name: %q},\n", indent, platform.Name)
 		return
 	}
 	sb.WriteString(indent + "{\n")
 	fmt.Fprintf(sb, "%s\tname: %q\n", indent, platform.Name)
+	if len(platform.Arch) > 0 {
+		sb.WriteString(indent + "\tarch: [")
+		for i, arch := range platform.Arch {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%q", arch)
+		}
+		sb.WriteString("]\n")
+	}
 	generatePlatformVirtualConfig(sb, platform.Virtual, indent+"\t")
 	sb.WriteString(indent + "},\n")
 }
@@ -391,7 +943,7 @@ func generateRuntimeConfigFields(sb *strings.Builder, r *RuntimeConfig, indent s
 		}
 		writeList("env_inherit_deny", items)
 	}
-	if r.Name == RuntimeVirtualSh || r.Name == RuntimeVirtualLua {
+	if r.Name.IsVirtual() {
 		if len(r.AllowedBinaries) > 0 {
 			items := make([]string, len(r.AllowedBinaries))
 			for i, binary := range r.AllowedBinaries {
@@ -411,6 +963,31 @@ func generateRuntimeConfigFields(sb *strings.Builder, r *RuntimeConfig, indent s
 			writeField("memory_limit", fmt.Sprintf("%q", r.MemoryLimit))
 		}
 	}
+	if r.Name == RuntimeVirtualStarlark && r.MaxSteps != 0 {
+		writeField("max_steps", r.MaxSteps.String())
+	}
+	if r.Name == RuntimeRemoteSSH {
+		writeField("host", fmt.Sprintf("%q", r.Host))
+		if r.Port != 0 {
+			writeField("port", r.Port.String())
+		}
+		if r.User != "" {
+			writeField("user", fmt.Sprintf("%q", r.User))
+		}
+		if r.IdentityFile != "" {
+			writeField("identity_file", fmt.Sprintf("%q", r.IdentityFile))
+		}
+		if r.WorkDir != "" {
+			writeField("workdir", fmt.Sprintf("%q", r.WorkDir))
+		}
+	}
+	if r.Name == RuntimeNative && r.Sandbox != nil {
+		sandbox := "{}"
+		if r.Sandbox.Network != "" {
+			sandbox = fmt.Sprintf("{network: %q}", r.Sandbox.Network)
+		}
+		writeField("sandbox", sandbox)
+	}
 	if r.Name != RuntimeContainer {
 		return
 	}
@@ -492,7 +1069,11 @@ func generateDependsOnContent(sb *strings.Builder, deps *DependsOn, indent strin
 				}
 				fmt.Fprintf(sb, "%q", alt)
 			}
-			sb.WriteString("]},\n")
+			sb.WriteString("]")
+			if dep.Run {
+				sb.WriteString(", run: true")
+			}
+			sb.WriteString("},\n")
 		}
 		sb.WriteString(indent + "]\n")
 	}
@@ -3,8 +3,13 @@
 package invowkfile
 
 import (
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/invowk/invowk/pkg/types"
 )
 
 func TestGenerateCUE_Category(t *testing.T) {
@@ -99,6 +104,524 @@ func TestGenerateCUE_Timeout(t *testing.T) {
 	}
 }
 
+func TestGenerateCUE_IncrementalPatternsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{
+			{
+				Name:      "build",
+				Sources:   []GlobPattern{"go.mod", "**/*.go"},
+				Generates: []GlobPattern{"bin/app"},
+				Implementations: []Implementation{
+					{
+						Script:    ImplementationScript{Content: "go build -o bin/app"},
+						Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+						Platforms: AllPlatformConfigs(),
+						Sources:   []GlobPattern{"scripts/*.sh"},
+					},
+				},
+			},
+		},
+	}
+
+	got := GenerateCUE(inv)
+	if !strings.Contains(got, `sources: ["go.mod", "**/*.go"]`) || !strings.Contains(got, `generates: ["bin/app"]`) {
+		t.Fatalf("expected incremental patterns in generated CUE, got:\n%s", got)
+	}
+
+	roundtrip, err := ParseBytes([]byte(got), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	cmd := &roundtrip.Commands[0]
+	sources, generates := cmd.IncrementalPatterns(&cmd.Implementations[0])
+	if len(sources) != 3 || sources[2] != "scripts/*.sh" {
+		t.Errorf("IncrementalPatterns() sources = %v, want command then implementation patterns", sources)
+	}
+	if len(generates) != 1 || generates[0] != "bin/app" {
+		t.Errorf("IncrementalPatterns() generates = %v, want [bin/app]", generates)
+	}
+}
+
+func TestGenerateCUE_HooksRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Hooks: &Hooks{
+			Before: []Hook{{Script: ImplementationScript{Content: "echo root-before"}}},
+		},
+		Commands: []Command{
+			{
+				Name: "deploy",
+				Hooks: &Hooks{
+					Before:  []Hook{{Script: ImplementationScript{Content: "echo cmd-before"}, Runtime: RuntimeVirtualSh}},
+					Finally: []Hook{{Script: ImplementationScript{Content: "rm -rf tmp\necho cleaned"}}},
+				},
+				Implementations: []Implementation{
+					{
+						Script:    ImplementationScript{Content: "echo deploying"},
+						Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+						Platforms: AllPlatformConfigs(),
+					},
+				},
+			},
+		},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	merged := MergeHooks(roundtrip.Hooks, roundtrip.Commands[0].Hooks)
+	if merged == nil || len(merged.Before) != 2 || len(merged.After) != 0 || len(merged.Finally) != 1 {
+		t.Fatalf("MergeHooks() = %#v, want 2 before and 1 finally hook", merged)
+	}
+	if merged.Before[0].Script.Content != "echo root-before" || merged.Before[1].Runtime != RuntimeVirtualSh {
+		t.Errorf("before hooks = %#v, want root hook first and command hook runtime preserved", merged.Before)
+	}
+	if merged.Finally[0].Script.Content != "rm -rf tmp\necho cleaned" {
+		t.Errorf("finally script = %q, want multi-line content preserved", merged.Finally[0].Script.Content)
+	}
+}
+
+func TestGenerateCUE_RetryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	retry := &RetryPolicy{Attempts: 4, Backoff: "250ms", MaxBackoff: "5s", OnExitCodes: []types.ExitCode{75, 124}}
+	inv := &Invowkfile{
+		Commands: []Command{
+			{
+				Name: "fetch",
+				Implementations: []Implementation{
+					{
+						Script:    ImplementationScript{Content: "curl -fsSL https://example.com"},
+						Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+						Platforms: AllPlatformConfigs(),
+						Retry:     retry,
+					},
+				},
+			},
+		},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Implementations[0].Retry; !reflect.DeepEqual(got, retry) {
+		t.Errorf("roundtrip Retry = %#v, want %#v", got, retry)
+	}
+}
+
+func TestGenerateCUE_AliasesAndDeprecationRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	impl := Implementation{
+		Script:    ImplementationScript{Content: "make deploy"},
+		Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+		Platforms: AllPlatformConfigs(),
+	}
+	dep := &Deprecation{Message: "renamed to 'ship'", Replacement: "ship", Forward: true}
+	inv := &Invowkfile{
+		Commands: []Command{
+			{Name: "ship", Aliases: []CommandName{"s", "ship it"}, Implementations: []Implementation{impl}},
+			{Name: "deploy", Deprecated: dep, Implementations: []Implementation{impl}},
+		},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Aliases; !slices.Equal(got, inv.Commands[0].Aliases) {
+		t.Errorf("roundtrip Aliases = %q, want %q", got, inv.Commands[0].Aliases)
+	}
+	if got := roundtrip.Commands[1].Deprecated; !reflect.DeepEqual(got, dep) {
+		t.Errorf("roundtrip Deprecated = %#v, want %#v", got, dep)
+	}
+}
+
+func TestGenerateCUE_FlagGroupsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	groups := &FlagGroups{
+		MutuallyExclusive: []FlagGroup{{"tag", "latest"}},
+		OneRequired:       []FlagGroup{{"tag", "latest"}, {"user", "token"}},
+	}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "push",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "docker push"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: []Flag{
+				{Name: "tag", Description: "Image tag"},
+				{Name: "latest", Description: "Push the latest tag", Type: FlagTypeBool},
+				{Name: "user", Description: "Registry user"},
+				{Name: "token", Description: "Registry token"},
+			},
+			FlagGroups: groups,
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].FlagGroups; !reflect.DeepEqual(got, groups) {
+		t.Errorf("roundtrip FlagGroups = %#v, want %#v", got, groups)
+	}
+}
+
+func TestGenerateCUE_CompletionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	flagComplete := &CompletionConfig{
+		Script:  ImplementationScript{Content: "printf 'eu\\tEurope\\nus\\tAmericas\\n'"},
+		Runtime: RuntimeVirtualSh,
+		Timeout: "500ms",
+	}
+	argComplete := &CompletionConfig{Script: ImplementationScript{Content: "ls envs"}}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "make deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: []Flag{{Name: "region", Description: "Target region", Complete: flagComplete}},
+			Args:  []Argument{{Name: "env", Description: "Target environment", Required: true, Complete: argComplete}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	cmd := roundtrip.Commands[0]
+	if got := cmd.Flags[0].Complete; !reflect.DeepEqual(got, flagComplete) {
+		t.Errorf("roundtrip flag Complete = %#v, want %#v", got, flagComplete)
+	}
+	if got := cmd.Args[0].Complete; !reflect.DeepEqual(got, argComplete) {
+		t.Errorf("roundtrip arg Complete = %#v, want %#v", got, argComplete)
+	}
+}
+
+func TestGenerateCUE_ConfirmRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	confirm := &ConfirmConfig{Message: "Wipe the {{flag.env}} database?", Default: true}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name:    "db reset",
+			Confirm: confirm,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "make reset"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: []Flag{{Name: "env", Description: "Environment", DefaultValue: "dev"}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Confirm; !reflect.DeepEqual(got, confirm) {
+		t.Errorf("roundtrip Confirm = %#v, want %#v", got, confirm)
+	}
+}
+
+func TestGenerateCUE_LockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	lock := &CommandLock{Name: "local-db", Scope: LockScopeUser, Wait: true, Timeout: "5m"}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "db migrate",
+			Lock: lock,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "make migrate"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Lock; !reflect.DeepEqual(got, lock) {
+		t.Errorf("roundtrip Lock = %#v, want %#v", got, lock)
+	}
+}
+
+func TestGenerateCUE_MatrixRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	matrix := &CommandMatrix{
+		Vars:     map[MatrixVarName][]string{"go": {"1.22", "1.23"}, "db": {"postgres", "mysql"}},
+		Include:  []MatrixCombination{{"go": "1.24", "db": "sqlite"}},
+		Exclude:  []MatrixCombination{{"go": "1.22", "db": "mysql"}},
+		Parallel: true,
+	}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name:   "test",
+			Matrix: matrix,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "go test ./..."},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Matrix; !reflect.DeepEqual(got, matrix) {
+		t.Errorf("roundtrip Matrix = %#v, want %#v", got, matrix)
+	}
+}
+
+func TestGenerateCUE_OutputsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	outputs := []CommandOutput{{Name: "version", Description: "Built version"}, {Name: "digest"}}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name:    "build",
+			Outputs: outputs,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "echo version=1.0 >> \"$INVOWK_OUTPUT_FILE\""},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Outputs; !reflect.DeepEqual(got, outputs) {
+		t.Errorf("roundtrip Outputs = %#v, want %#v", got, outputs)
+	}
+}
+
+func TestGenerateCUE_ExitCodePolicyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "lint",
+			Implementations: []Implementation{{
+				Script:           ImplementationScript{Content: "golangci-lint run"},
+				Runtimes:         []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms:        AllPlatformConfigs(),
+				SuccessExitCodes: []types.ExitCode{1, 3},
+				ExitCodeMap:      ExitCodeMap{2: 10, 4: 0},
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	got, want := roundtrip.Commands[0].Implementations[0], inv.Commands[0].Implementations[0]
+	if !reflect.DeepEqual(got.SuccessExitCodes, want.SuccessExitCodes) || !reflect.DeepEqual(got.ExitCodeMap, want.ExitCodeMap) {
+		t.Errorf("roundtrip exit code policy = %v / %v, want %v / %v", got.SuccessExitCodes, got.ExitCodeMap, want.SuccessExitCodes, want.ExitCodeMap)
+	}
+}
+
+func TestGenerateCUE_RemoteSSHRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Implementations: []Implementation{{
+				Script: ImplementationScript{Content: "./deploy.sh"},
+				Runtimes: []RuntimeConfig{{
+					Name:         RuntimeRemoteSSH,
+					Host:         "build-01.example.com",
+					Port:         2222,
+					User:         "deploy",
+					IdentityFile: "~/.ssh/deploy_ed25519",
+					WorkDir:      "/srv/app",
+				}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	got, want := roundtrip.Commands[0].Implementations[0].Runtimes[0], inv.Commands[0].Implementations[0].Runtimes[0]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("roundtrip remote-ssh runtime = %+v, want %+v", got, want)
+	}
+}
+
+func TestGenerateCUE_NativeSandboxRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, sandbox := range []*RuntimeSandboxConfig{{}, {Network: SandboxNetworkHost}} {
+		inv := &Invowkfile{
+			Commands: []Command{{
+				Name: "lint",
+				Implementations: []Implementation{{
+					Script:    ImplementationScript{Content: "make lint"},
+					Runtimes:  []RuntimeConfig{{Name: RuntimeNative, Sandbox: sandbox}},
+					Platforms: AllPlatformConfigs(),
+				}},
+			}},
+		}
+
+		roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+		if err != nil {
+			t.Fatalf("roundtrip ParseBytes() error = %v", err)
+		}
+		got := roundtrip.Commands[0].Implementations[0].Runtimes[0].Sandbox
+		if got == nil || *got != *sandbox {
+			t.Errorf("roundtrip sandbox = %+v, want %+v", got, sandbox)
+		}
+	}
+}
+
+func TestGenerateCUE_VirtualStarlarkRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "report",
+			Implementations: []Implementation{{
+				Script: ImplementationScript{Content: `print(invowk.path("report.txt"))`},
+				Runtimes: []RuntimeConfig{{
+					Name:             RuntimeVirtualStarlark,
+					AllowedBinaries:  []AllowedBinary{"git"},
+					BinaryLookupMode: BinaryLookupModeStrict,
+					MaxSteps:         100000,
+				}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	got, want := roundtrip.Commands[0].Implementations[0].Runtimes[0], inv.Commands[0].Implementations[0].Runtimes[0]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("roundtrip virtual-starlark runtime = %+v, want %+v", got, want)
+	}
+}
+
+func TestGenerateCUE_VisibilityRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name:       "setup",
+			Visibility: VisibilityPrivate,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "make setup"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Visibility; got != VisibilityPrivate {
+		t.Errorf("roundtrip Visibility = %q, want %q", got, VisibilityPrivate)
+	}
+}
+
+func TestGenerateCUE_PlatformArchRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "fetch",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "make fetch"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: []PlatformConfig{{Name: PlatformLinux, Arch: []Architecture{ArchAMD64, ArchARM64}}, {Name: PlatformMac}},
+			}},
+		}},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	platforms := roundtrip.Commands[0].Implementations[0].Platforms
+	if !slices.Equal(platforms[0].Arch, []Architecture{ArchAMD64, ArchARM64}) || platforms[1].Arch != nil {
+		t.Errorf("roundtrip platforms = %+v, want arch [amd64 arm64] on linux only", platforms)
+	}
+}
+
+func TestGenerateCUE_WhenRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	when := &When{
+		Env:   map[EnvVarName]string{"CI": "true"},
+		Flags: map[FlagName]string{"target": "prod"},
+		Args:  map[ArgumentName]string{"service": "api"},
+		Files: []FilesystemPath{"go.mod"},
+		Arch:  ArchARM64,
+	}
+	inv := &Invowkfile{
+		Commands: []Command{
+			{
+				Name:  "deploy",
+				Flags: []Flag{{Name: "target", Description: "Deploy target", DefaultValue: "dev"}},
+				Args:  []Argument{{Name: "service", Description: "Service to deploy"}},
+				Implementations: []Implementation{
+					{
+						Script:    ImplementationScript{Content: "make deploy-ci"},
+						Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+						Platforms: AllPlatformConfigs(),
+						When:      when,
+					},
+					{
+						Script:    ImplementationScript{Content: "make deploy"},
+						Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+						Platforms: AllPlatformConfigs(),
+					},
+				},
+			},
+		},
+	}
+
+	roundtrip, err := ParseBytes([]byte(GenerateCUE(inv)), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := roundtrip.Commands[0].Implementations[0].When; !reflect.DeepEqual(got, when) {
+		t.Errorf("roundtrip When = %#v, want %#v", got, when)
+	}
+	if got := roundtrip.Commands[0].Implementations[1].When; got != nil {
+		t.Errorf("roundtrip fallback When = %#v, want nil", got)
+	}
+}
+
 func TestGenerateCUE_WatchConfigMinimal(t *testing.T) {
 	t.Parallel()
 
@@ -190,6 +713,157 @@ func TestGenerateCUE_FlagEnhancedFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestGenerateCUE_ChoicesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "echo deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: []Flag{{
+				Name:         "region",
+				Description:  "Target region",
+				Choices:      []string{"eu", "us"},
+				DefaultValue: "eu",
+			}},
+			Args: []Argument{{
+				Name:        "env",
+				Description: "Target environment",
+				Choices:     []string{"dev", "prod"},
+			}},
+		}},
+	}
+
+	got := GenerateCUE(inv)
+	if !strings.Contains(got, `choices: ["eu", "us"]`) || !strings.Contains(got, `choices: ["dev", "prod"]`) {
+		t.Fatalf("generated CUE missing choices:\n%s", got)
+	}
+
+	parsed, err := ParseBytes([]byte(got), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v\n%s", err, got)
+	}
+	if gotChoices := parsed.Commands[0].Flags[0].Choices; !slices.Equal(gotChoices, []string{"eu", "us"}) {
+		t.Fatalf("roundtrip flag choices = %v, want [eu us]", gotChoices)
+	}
+	if gotChoices := parsed.Commands[0].Args[0].Choices; !slices.Equal(gotChoices, []string{"dev", "prod"}) {
+		t.Fatalf("roundtrip arg choices = %v, want [dev prod]", gotChoices)
+	}
+}
+
+func TestGenerateCUE_TypeOptionsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	wantFlags := []Flag{
+		{Name: "out", Description: "Output dir", Type: FlagTypePath, MustExist: true, Kind: PathKindDir},
+		{Name: "tag", Description: "Tags", Type: FlagTypeList, ListFormat: ListFormatJSON, DefaultValue: `["a","b"]`},
+		{Name: "cfg", Description: "Config", Type: FlagTypeJSON, Schema: `{name: string, "replicas": int & >0}`},
+		{Name: "wait", Description: "Wait time", Type: FlagTypeDuration, DefaultValue: "30s"},
+	}
+	wantArgs := []Argument{
+		{Name: "src", Description: "Source file", Type: ArgumentTypePath, Kind: PathKindFile},
+	}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "echo deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: wantFlags,
+			Args:  wantArgs,
+		}},
+	}
+
+	got := GenerateCUE(inv)
+	parsed, err := ParseBytes([]byte(got), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v\n%s", err, got)
+	}
+	if !reflect.DeepEqual(parsed.Commands[0].Flags, wantFlags) {
+		t.Fatalf("roundtrip flags = %#v, want %#v\n%s", parsed.Commands[0].Flags, wantFlags, got)
+	}
+	if !reflect.DeepEqual(parsed.Commands[0].Args, wantArgs) {
+		t.Fatalf("roundtrip args = %#v, want %#v\n%s", parsed.Commands[0].Args, wantArgs, got)
+	}
+}
+
+func TestGenerateCUE_PromptRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	wantFlags := []Flag{
+		{Name: "force", Description: "Force deploy", Type: FlagTypeBool, Required: true, Prompt: &PromptConfig{Type: PromptTypeConfirm, Message: "Force deploy?"}},
+		{Name: "note", Description: "Release note", Required: true, Prompt: &PromptConfig{Type: PromptTypeInput, Message: "Note?", Placeholder: "what changed", CharLimit: 80}},
+	}
+	wantArgs := []Argument{
+		{Name: "env", Description: "Target", Required: true, Choices: []string{"prod", "staging"}, Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Deploy where?", Options: []string{"staging"}}},
+		{Name: "manifest", Description: "Manifest", Required: true, Type: ArgumentTypePath, Prompt: &PromptConfig{Type: PromptTypeFile, Message: "Manifest?", Extensions: []string{".yaml", ".yml"}}},
+	}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "echo deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+				Platforms: AllPlatformConfigs(),
+			}},
+			Flags: wantFlags,
+			Args:  wantArgs,
+		}},
+	}
+
+	got := GenerateCUE(inv)
+	parsed, err := ParseBytes([]byte(got), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v\n%s", err, got)
+	}
+	if !reflect.DeepEqual(parsed.Commands[0].Flags, wantFlags) {
+		t.Fatalf("roundtrip flags = %#v, want %#v\n%s", parsed.Commands[0].Flags, wantFlags, got)
+	}
+	if !reflect.DeepEqual(parsed.Commands[0].Args, wantArgs) {
+		t.Fatalf("roundtrip args = %#v, want %#v\n%s", parsed.Commands[0].Args, wantArgs, got)
+	}
+}
+
+func TestGenerateCUE_SecretsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	wantEnv := &EnvConfig{
+		Vars: map[EnvVarName]string{"REGION": "eu"},
+		Secrets: map[EnvVarName]SecretSource{
+			"API_TOKEN": {Provider: SecretProviderCommand, Command: []string{"pass", "show", "deploy/token"}},
+			"DB_PASS":   {Provider: SecretProviderFile, Path: ".secrets/db"},
+			"GH_TOKEN":  {Provider: SecretProviderEnv, Var: "GITHUB_TOKEN"},
+			"SIGN_KEY":  {Provider: SecretProviderKeyring, Service: "invowk", Account: "release"},
+		},
+	}
+	inv := &Invowkfile{
+		Commands: []Command{{
+			Name: "deploy",
+			Env:  wantEnv,
+			Implementations: []Implementation{{
+				Script:    ImplementationScript{Content: "echo deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}},
+				Platforms: AllPlatformConfigs(),
+			}},
+		}},
+	}
+
+	got := GenerateCUE(inv)
+	parsed, err := ParseBytes([]byte(got), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v\n%s", err, got)
+	}
+	if !reflect.DeepEqual(parsed.Commands[0].Env, wantEnv) {
+		t.Fatalf("roundtrip env = %#v, want %#v\n%s", parsed.Commands[0].Env, wantEnv, got)
+	}
+}
+
 func TestGenerateCUE_RuntimeBaseFieldsRoundTrip(t *testing.T) {
 	t.Parallel()
 
@@ -289,3 +963,30 @@ func TestGenerateCUE_RuntimePersistentFieldsRoundTrip(t *testing.T) {
 		t.Fatalf("roundtrip persistent config = %+v", persistent)
 	}
 }
+
+func TestGenerateCUE_TemplatesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inv, err := ParseBytes([]byte(templatesInvowkfile), "invowkfile.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+
+	generated := GenerateCUE(inv)
+	if strings.Contains(generated, "extends:") {
+		t.Errorf("GenerateCUE() emitted extends, want fully expanded commands:\n%s", generated)
+	}
+	roundtrip, err := ParseBytes([]byte(generated), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v\n%s", err, generated)
+	}
+	if got := roundtrip.Commands[0].Implementations[0].Runtimes; !reflect.DeepEqual(got, inv.Commands[0].Implementations[0].Runtimes) {
+		t.Errorf("roundtrip Runtimes = %#v, want %#v", got, inv.Commands[0].Implementations[0].Runtimes)
+	}
+	if got := roundtrip.Commands[1].Description; got != "Run tests" {
+		t.Errorf("roundtrip Description = %q, want %q", got, "Run tests")
+	}
+	if got := slices.Sorted(maps.Keys(roundtrip.Templates)); !slices.Equal(got, []TemplateName{"container", "go-service"}) {
+		t.Errorf("roundtrip Templates = %v, want the exported templates kept", got)
+	}
+}
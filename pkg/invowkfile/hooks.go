@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// HookPhaseBefore runs before the command's main script. A failing before
+	// hook skips the main script and the after hooks.
+	HookPhaseBefore HookPhase = "before"
+	// HookPhaseAfter runs after the main script succeeds.
+	HookPhaseAfter HookPhase = "after"
+	// HookPhaseFinally always runs last: on success, on failure, on timeout,
+	// and after cancellation (SIGINT).
+	HookPhaseFinally HookPhase = "finally"
+)
+
+var (
+	// ErrInvalidHookPhase is the sentinel error wrapped by InvalidHookPhaseError.
+	ErrInvalidHookPhase = errors.New("invalid hook phase")
+
+	// ErrInvalidHook is the sentinel error wrapped by InvalidHookError.
+	ErrInvalidHook = errors.New("invalid hook")
+
+	// ErrHookContainerRuntime is returned when a hook selects the container or
+	// remote-ssh runtime, which need an image or host a hook cannot declare.
+	ErrHookContainerRuntime = errors.New("hooks do not support the container or remote-ssh runtimes; use native, virtual-sh, virtual-lua, or virtual-starlark")
+)
+
+type (
+	// HookPhase identifies when a lifecycle hook runs relative to the
+	// command's main script: "before", "after", or "finally".
+	HookPhase string
+
+	// InvalidHookPhaseError is returned when a HookPhase is not a known phase.
+	InvalidHookPhaseError struct {
+		Value HookPhase
+	}
+
+	// InvalidHookError is returned when a Hook has invalid fields.
+	// It wraps ErrInvalidHook for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidHookError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// Hook is one lifecycle script run around a command's main script. Hooks
+	// run in the context of the command: they see its flags, args, env, and
+	// working directory.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	Hook struct {
+		// Script is the inline (or module-contained) hook script.
+		Script ImplementationScript `json:"script"`
+		// Runtime selects the runtime that executes the hook (default: native).
+		Runtime RuntimeMode `json:"runtime,omitempty"`
+	}
+
+	//goplint:validate-all
+	//
+	// Hooks groups the lifecycle scripts declared at one level (invowkfile
+	// root or command). Each phase runs its hooks in declaration order.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	Hooks struct {
+		// Before hooks run before the main script; the first failure stops the run.
+		Before []Hook `json:"before,omitempty"`
+		// After hooks run after the main script succeeds.
+		After []Hook `json:"after,omitempty"`
+		// Finally hooks always run last, even when an earlier phase failed,
+		// timed out, or was interrupted. Every finally hook runs.
+		Finally []Hook `json:"finally,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidHookPhaseError) Error() string {
+	return fmt.Sprintf("invalid hook phase %q (must be before, after, or finally)", e.Value)
+}
+
+// Unwrap returns ErrInvalidHookPhase so callers can use errors.Is for programmatic detection.
+func (e *InvalidHookPhaseError) Unwrap() error { return ErrInvalidHookPhase }
+
+// Validate returns nil if the HookPhase is before, after, or finally.
+//
+//goplint:nonzero
+func (p HookPhase) Validate() error {
+	switch p {
+	case HookPhaseBefore, HookPhaseAfter, HookPhaseFinally:
+		return nil
+	default:
+		return &InvalidHookPhaseError{Value: p}
+	}
+}
+
+// String returns the string representation of the HookPhase.
+func (p HookPhase) String() string { return string(p) }
+
+// Validate returns nil if the Hook has a valid script and a hook-capable
+// runtime, or an error collecting all field-level validation failures.
+func (h Hook) Validate() error {
+	var errs []error
+	appendFieldError(&errs, h.Script.Validate())
+	appendOptionalValidation(&errs, h.Runtime, h.Runtime != "")
+	if h.Runtime.RequiresConfig() {
+		errs = append(errs, ErrHookContainerRuntime)
+	}
+	if len(errs) > 0 {
+		return &InvalidHookError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidHookError.
+func (e *InvalidHookError) Error() string {
+	return types.FormatFieldErrors("hook", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidHook and field errors for errors.Is() compatibility.
+func (e *InvalidHookError) Unwrap() error {
+	return errors.Join(ErrInvalidHook, errors.Join(e.FieldErrors...))
+}
+
+// EffectiveRuntime returns the runtime that executes the hook.
+// Hooks default to the native runtime when no runtime is declared.
+func (h *Hook) EffectiveRuntime() RuntimeMode {
+	if h.Runtime != "" {
+		return h.Runtime
+	}
+	return RuntimeNative
+}
+
+// ScriptImplementation returns the implementation used to run the hook. The
+// synthesized implementation supports every platform and only the hook's
+// effective runtime.
+func (h *Hook) ScriptImplementation() *Implementation {
+	return &Implementation{
+		Script:    h.Script,
+		Runtimes:  []RuntimeConfig{{Name: h.EffectiveRuntime()}},
+		Platforms: AllPlatformConfigs(),
+	}
+}
+
+// Validate returns nil if every hook in every phase is valid, or an error
+// collecting all hook validation failures.
+func (h Hooks) Validate() error {
+	var errs []error
+	appendEachValidation(&errs, h.Before)
+	appendEachValidation(&errs, h.After)
+	appendEachValidation(&errs, h.Finally)
+	return errors.Join(errs...)
+}
+
+// Phase returns the hooks declared for phase, or nil for an unknown phase.
+func (h *Hooks) Phase(phase HookPhase) []Hook {
+	if h == nil {
+		return nil
+	}
+	switch phase {
+	case HookPhaseBefore:
+		return h.Before
+	case HookPhaseAfter:
+		return h.After
+	case HookPhaseFinally:
+		return h.Finally
+	default:
+		return nil
+	}
+}
+
+// IsEmpty returns true when no phase declares a hook.
+func (h *Hooks) IsEmpty() bool {
+	return h == nil || (len(h.Before) == 0 && len(h.After) == 0 && len(h.Finally) == 0)
+}
+
+// MergeHooks merges root-level and command-level hooks.
+// Hooks are combined phase by phase in order: root -> command, so root hooks
+// run before command hooks within every phase.
+// Returns nil when neither level declares a hook.
+func MergeHooks(rootHooks, cmdHooks *Hooks) *Hooks {
+	merged := &Hooks{}
+	for _, level := range []*Hooks{rootHooks, cmdHooks} {
+		if level == nil {
+			continue
+		}
+		merged.Before = append(merged.Before, level.Before...)
+		merged.After = append(merged.After, level.After...)
+		merged.Finally = append(merged.Finally, level.Finally...)
+	}
+	if merged.IsEmpty() {
+		return nil
+	}
+	return merged
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -94,6 +95,8 @@ type (
 	// Implementation represents an implementation with platform and runtime constraints
 	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
 	Implementation struct {
+		// Extends names the template merged into this implementation while parsing (optional).
+		Extends TemplateRef `json:"extends,omitempty"`
 		// Script selects inline shell content or a script file reference.
 		Script ImplementationScript `json:"script"`
 		// Runtimes specifies which runtimes can execute this implementation (required, at least one)
@@ -122,6 +125,23 @@ type (
 		// Must be a valid Go duration string (e.g., "30s", "5m", "1h30m").
 		// When exceeded, the command is cancelled and returns a timeout error.
 		Timeout DurationString `json:"timeout,omitempty"`
+		// Retry re-runs the implementation when it exits with a non-zero code (optional).
+		// Every runtime honors the policy; INVOWK_ATTEMPT holds the 1-based attempt number.
+		Retry *RetryPolicy `json:"retry,omitempty"`
+		// SuccessExitCodes lists non-zero exit codes of the script that count as
+		// success and are reported as 0 (optional).
+		SuccessExitCodes []types.ExitCode `json:"success_exit_codes,omitempty"`
+		// ExitCodeMap translates exit codes of the script into the exit codes
+		// reported to invowk and its callers (optional).
+		ExitCodeMap ExitCodeMap `json:"exit_code_map,omitempty"`
+		// Sources lists glob patterns for input files, appended to the command's sources (optional).
+		Sources []GlobPattern `json:"sources,omitempty"`
+		// Generates lists glob patterns for output files, appended to the command's generates (optional).
+		Generates []GlobPattern `json:"generates,omitempty"`
+		// When restricts this implementation to executions where every
+		// condition holds (optional). Failing implementations are skipped
+		// during selection.
+		When *When `json:"when,omitempty"`
 	}
 
 	// PlatformRuntimeKey represents a unique combination of platform and runtime
@@ -247,11 +267,13 @@ func (e *InvalidImplementationMatchError) Unwrap() error { return ErrInvalidImpl
 // Validate returns nil if the Implementation has valid fields,
 // or an error collecting all field-level validation failures.
 // Delegates to Script.Validate() (zero-valid), RuntimeConfig.Validate() for each runtime,
-// PlatformConfig.Validate() for each platform, and validates optional fields when non-empty/non-nil.
+// PlatformConfig.Validate() for each platform, validates optional fields (including
+// Extends) when non-empty/non-nil, and checks the exit code policy.
 //
 //goplint:ignore -- helper-based Sonar refactor keeps optional-field validation local and field-order stable.
 func (s Implementation) Validate() error {
 	var errs []error
+	appendOptionalValidation(&errs, s.Extends, s.Extends != "")
 	appendFieldError(&errs, s.Script.Validate())
 	appendEachValidation(&errs, s.Runtimes)
 	appendEachValidation(&errs, s.Platforms)
@@ -259,6 +281,11 @@ func (s Implementation) Validate() error {
 	appendOptionalValidation(&errs, s.WorkDir, s.WorkDir != "")
 	appendOptionalValidation(&errs, s.DependsOn, s.DependsOn != nil)
 	appendFieldError(&errs, s.Timeout.Validate())
+	appendOptionalValidation(&errs, s.Retry, s.Retry != nil)
+	errs = append(errs, validateExitCodePolicy(s.SuccessExitCodes, s.ExitCodeMap)...)
+	appendEachValidation(&errs, s.Sources)
+	appendEachValidation(&errs, s.Generates)
+	appendOptionalValidation(&errs, s.When, s.When != nil)
 	if len(errs) > 0 {
 		return &InvalidImplementationError{FieldErrors: errs}
 	}
@@ -285,20 +312,49 @@ func (s *Implementation) MatchesPlatform(platform Platform) bool {
 	return false
 }
 
-// GetPlatformConfig returns the PlatformConfig for the given platform, or nil if not found.
-func (s *Implementation) GetPlatformConfig(platform Platform) *PlatformConfig {
+// MatchesPlatformArch returns true if the implementation can run on the given
+// platform and CPU architecture.
+func (s *Implementation) MatchesPlatformArch(platform Platform, arch Architecture) bool {
+	return s.GetPlatformConfig(platform, arch) != nil
+}
+
+// GetPlatformConfig returns the first PlatformConfig for the given platform
+// that applies to arch, or nil if not found.
+func (s *Implementation) GetPlatformConfig(platform Platform, arch Architecture) *PlatformConfig {
 	for i := range s.Platforms {
-		if s.Platforms[i].Name == platform {
+		if s.Platforms[i].Name == platform && s.Platforms[i].MatchesArch(arch) {
 			return &s.Platforms[i]
 		}
 	}
 	return nil
 }
 
+// ArchesForPlatform returns the CPU architectures the implementation is
+// restricted to on the given platform, or nil when any platform entry for it
+// applies to every architecture.
+func (s *Implementation) ArchesForPlatform(platform Platform) []Architecture {
+	var arches []Architecture
+	for i := range s.Platforms {
+		if s.Platforms[i].Name != platform {
+			continue
+		}
+		if len(s.Platforms[i].Arch) == 0 {
+			return nil
+		}
+		for _, arch := range s.Platforms[i].Arch {
+			if !slices.Contains(arches, arch) {
+				arches = append(arches, arch)
+			}
+		}
+	}
+	return arches
+}
+
 // VirtualFilesystemForPlatform returns the effective virtual filesystem config
-// for the given platform. Missing config means restricted access with no named paths.
-func (s *Implementation) VirtualFilesystemForPlatform(platform Platform) VirtualFilesystemConfig {
-	platformCfg := s.GetPlatformConfig(platform)
+// for the given platform and CPU architecture. Missing config means restricted
+// access with no named paths.
+func (s *Implementation) VirtualFilesystemForPlatform(platform Platform, arch Architecture) VirtualFilesystemConfig {
+	platformCfg := s.GetPlatformConfig(platform, arch)
 	if platformCfg == nil {
 		return VirtualFilesystemConfig{}
 	}
@@ -0,0 +1,440 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	slashpath "path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/cueutil"
+)
+
+const (
+	// ImportCollisionCommand marks a fragment command whose name is already taken.
+	ImportCollisionCommand ImportCollisionKind = "command"
+	// ImportCollisionEnvVar marks a fragment env var that is already defined.
+	ImportCollisionEnvVar ImportCollisionKind = "env var"
+)
+
+var (
+	// ErrInvalidImportPattern is the sentinel error wrapped by InvalidImportPatternError.
+	ErrInvalidImportPattern = errors.New("invalid import pattern")
+
+	// ErrImportNotFound is returned when an import without glob characters
+	// names a file that does not exist.
+	ErrImportNotFound = errors.New("imported file not found")
+
+	// ErrImportsInModule is returned when a module invowkfile declares imports.
+	ErrImportsInModule = errors.New("imports are not supported in module invowkfiles")
+)
+
+type (
+	// ImportPattern is a path or filepath.Match glob of invowkfile fragments,
+	// relative to the directory of the importing invowkfile
+	// (e.g. "ci/*.invowk.cue").
+	ImportPattern string
+
+	// InvalidImportPatternError is returned when an ImportPattern is malformed.
+	InvalidImportPatternError struct {
+		Value  ImportPattern
+		Reason string
+	}
+
+	// ImportCollisionKind identifies what an ImportCollision is about.
+	ImportCollisionKind string
+
+	//goplint:validate-all
+	//
+	// ImportCollision records a definition of an imported fragment that was
+	// ignored because an earlier definition has the same name. The importing
+	// invowkfile's own definitions win over its fragments, and fragments
+	// imported earlier win over later ones.
+	ImportCollision struct {
+		// Kind is what collided: a command or an env var.
+		Kind ImportCollisionKind
+		// Name is the name of the command or env var.
+		Name string //goplint:ignore -- command or env var name, validated by its own type before merging.
+		// Fragment is the path of the fragment whose definition was ignored.
+		Fragment FilesystemPath
+		// DefinedIn is the path of the file whose definition was kept.
+		DefinedIn FilesystemPath
+	}
+
+	// invowkfileFragment is the decoded form of #InvowkfileFragment.
+	//
+	//goplint:ignore -- decoded fragment DTO; its fields are validated with the importing invowkfile.
+	invowkfileFragment struct {
+		Env       *EnvConfig `json:"env,omitempty"`
+		DependsOn *DependsOn `json:"depends_on,omitempty"`
+		Commands  []Command  `json:"cmds,omitempty"`
+
+		// inherited holds the paths of the fields its commands took from
+		// templates; they are relative to the template's invowkfile.
+		inherited map[string]bool
+	}
+
+	// fragmentRebaser rewrites the relative paths declared by a fragment,
+	// leaving the fields inherited from templates alone.
+	fragmentRebaser struct {
+		prefix    string
+		inherited map[string]bool
+	}
+
+	// fragmentMerger merges imported fragments into an invowkfile, recording
+	// the definitions it ignores because their name is already taken.
+	fragmentMerger struct {
+		inv      *Invowkfile
+		commands map[CommandName]FilesystemPath
+		envVars  map[EnvVarName]FilesystemPath
+	}
+)
+
+// Validate returns nil if the ImportPattern is a valid relative path or glob
+// that stays within the importing invowkfile's directory.
+func (p ImportPattern) Validate() error {
+	s := string(p)
+	switch {
+	case strings.TrimSpace(s) == "":
+		return &InvalidImportPatternError{Value: p, Reason: "must not be empty"}
+	case utf8.RuneCountInString(s) > MaxPathLength:
+		return &InvalidImportPatternError{Value: p, Reason: fmt.Sprintf("must be at most %d characters", MaxPathLength)}
+	case isAbsolutePath(s):
+		return &InvalidImportPatternError{Value: p, Reason: "must be relative to the invowkfile directory"}
+	case containsParentPathSegment(strings.ReplaceAll(s, "\\", "/")):
+		return &InvalidImportPatternError{Value: p, Reason: "must not contain '..' segments"}
+	}
+	if _, err := filepath.Match(s, ""); err != nil {
+		return &InvalidImportPatternError{Value: p, Reason: err.Error()}
+	}
+	return nil
+}
+
+// String returns the string representation of the ImportPattern.
+func (p ImportPattern) String() string { return string(p) }
+
+// IsGlob reports whether the pattern contains glob characters.
+func (p ImportPattern) IsGlob() bool { return strings.ContainsAny(string(p), "*?[") }
+
+// Error implements the error interface.
+func (e *InvalidImportPatternError) Error() string {
+	return fmt.Sprintf("invalid import %q: %s", e.Value, e.Reason)
+}
+
+// Unwrap returns ErrInvalidImportPattern for errors.Is() compatibility.
+func (e *InvalidImportPatternError) Unwrap() error { return ErrInvalidImportPattern }
+
+// String returns the string representation of the ImportCollisionKind.
+func (k ImportCollisionKind) String() string { return string(k) }
+
+// Validate returns nil if the collision kind is known.
+func (k ImportCollisionKind) Validate() error {
+	switch k {
+	case ImportCollisionCommand, ImportCollisionEnvVar:
+		return nil
+	default:
+		return fmt.Errorf("invalid import collision kind %q", k)
+	}
+}
+
+// Validate returns nil if the ImportCollision's typed fields are valid.
+func (c ImportCollision) Validate() error {
+	return errors.Join(c.Kind.Validate(), c.Fragment.Validate(), c.DefinedIn.Validate())
+}
+
+// String describes the collision, e.g. "command 'lint' of ci/lint.invowk.cue
+// is ignored: already defined in invowkfile.cue".
+func (c ImportCollision) String() string {
+	return fmt.Sprintf("%s '%s' of %s is ignored: already defined in %s", c.Kind, c.Name, c.Fragment, c.DefinedIn)
+}
+
+// importFragments loads the fragments matched by the invowkfile's imports
+// and merges their commands, env, and depends_on into it. Imports are loaded
+// in order, and the files matched by a glob in lexical order; files matched
+// again, and the invowkfile itself, are skipped. Commands of a fragment can
+// extend the templates of the invowkfile.
+func (inv *Invowkfile) importFragments(templates *templateExpander) error {
+	rootDir := filepath.Dir(string(inv.FilePath))
+	merger := newFragmentMerger(inv)
+	seen := map[string]bool{filepath.Clean(string(inv.FilePath)): true}
+	for _, pattern := range inv.Imports {
+		paths, err := pattern.resolve(rootDir)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			fragment, err := parseFragment(path, templates)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(rootDir, filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("import %q: %w", pattern, err)
+			}
+			fragmentPath := FilesystemPath(path) //goplint:ignore -- matched by filepath.Glob and read successfully.
+			fragment.rebase(filepath.ToSlash(rel))
+			merger.merge(fragment, fragmentPath)
+		}
+	}
+	return nil
+}
+
+// resolve returns the regular files the pattern matches in dir.
+func (p ImportPattern) resolve(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(string(p))))
+	if err != nil {
+		return nil, &InvalidImportPatternError{Value: p, Reason: err.Error()}
+	}
+	files := matches[:0]
+	for _, match := range matches {
+		if info, statErr := os.Stat(match); statErr == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 && !p.IsGlob() {
+		return nil, fmt.Errorf("import %q: %w", p, ErrImportNotFound)
+	}
+	return files, nil
+}
+
+// parseFragment reads an imported fragment and decodes it against
+// #InvowkfileFragment.
+func parseFragment(path string, templates *templateExpander) (*invowkfileFragment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read imported invowkfile fragment at %s: %w", path, err)
+	}
+	result, err := cueutil.ParseAndDecode[invowkfileFragment](
+		invowkfileSchemaBytes,
+		data,
+		"#InvowkfileFragment",
+		cueutil.WithFilename(path),
+		cueutil.WithASTTransform(schemaTransform(templates)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	fragment := result.Value
+	fragment.inherited = templates.inherited
+	return fragment, nil
+}
+
+// rebase rewrites the relative paths declared by the fragment so that they
+// resolve against the importing invowkfile's directory the way they resolved
+// against the fragment's own. prefix is the fragment's directory relative to
+// the invowkfile's, with forward slashes. Fields inherited from templates
+// already resolve against the invowkfile's directory and are kept. Sources,
+// generates, and watch patterns resolve against the working directory, which
+// defaults to the fragment's directory for imported commands (see
+// GetEffectiveWorkDir).
+func (f *invowkfileFragment) rebase(prefix string) {
+	if prefix == "." {
+		return
+	}
+	r := fragmentRebaser{prefix: prefix, inherited: f.inherited}
+	r.env(f.Env, "env")
+	r.dependsOn(f.DependsOn, "depends_on")
+	for i := range f.Commands {
+		cmd := &f.Commands[i]
+		path := fmt.Sprintf("cmds[%d]", i)
+		cmd.WorkDir = rebaseField(r, cmd.WorkDir, path+".workdir")
+		r.env(cmd.Env, path+".env")
+		r.dependsOn(cmd.DependsOn, path+".depends_on")
+		for j := range cmd.Implementations {
+			r.implementation(&cmd.Implementations[j], fmt.Sprintf("%s.implementations[%d]", path, j))
+		}
+	}
+}
+
+// implementation rebases the workdir, env, dependency, when, runtime, and
+// platform paths of impl.
+//
+//goplint:ignore -- path is a display-only field path.
+func (r fragmentRebaser) implementation(impl *Implementation, path string) {
+	impl.WorkDir = rebaseField(r, impl.WorkDir, path+".workdir")
+	r.env(impl.Env, path+".env")
+	r.dependsOn(impl.DependsOn, path+".depends_on")
+	if impl.When != nil {
+		for i := range impl.When.Files {
+			impl.When.Files[i] = rebaseField(r, impl.When.Files[i], fmt.Sprintf("%s.when.files[%d]", path, i))
+		}
+	}
+	for i := range impl.Runtimes {
+		rt := &impl.Runtimes[i]
+		rtPath := fmt.Sprintf("%s.runtimes[%d]", path, i)
+		rt.Containerfile = rebaseField(r, rt.Containerfile, rtPath+".containerfile")
+		// "~" paths are expanded on the local host, not resolved.
+		if !strings.HasPrefix(string(rt.IdentityFile), "~") {
+			rt.IdentityFile = rebaseField(r, rt.IdentityFile, rtPath+".identity_file")
+		}
+	}
+	for i := range impl.Platforms {
+		virtual := impl.Platforms[i].Virtual
+		if virtual == nil || virtual.Filesystem == nil {
+			continue
+		}
+		pathsPath := fmt.Sprintf("%s.platforms[%d].virtual.filesystem.paths", path, i)
+		for name, value := range virtual.Filesystem.Paths {
+			// "@" paths are relative to a runtime anchor, not to the invowkfile.
+			if !strings.HasPrefix(string(value), "@") {
+				virtual.Filesystem.Paths[name] = rebaseField(r, value, pathsPath+"."+string(name))
+			}
+		}
+	}
+}
+
+// env rebases the dotenv files and file secrets of env.
+//
+//goplint:ignore -- path is a display-only field path.
+func (r fragmentRebaser) env(env *EnvConfig, path string) {
+	if env == nil {
+		return
+	}
+	for i := range env.Files {
+		env.Files[i] = rebaseField(r, env.Files[i], fmt.Sprintf("%s.files[%d]", path, i))
+	}
+	for name, secret := range env.Secrets {
+		secret.Path = rebaseField(r, secret.Path, path+".secrets."+string(name)+".path")
+		env.Secrets[name] = secret
+	}
+}
+
+// dependsOn rebases the host filepath alternatives of deps.
+//
+//goplint:ignore -- path is a display-only field path.
+func (r fragmentRebaser) dependsOn(deps *DependsOn, path string) {
+	if deps == nil {
+		return
+	}
+	for i := range deps.Filepaths {
+		for j := range deps.Filepaths[i].Alternatives {
+			altPath := fmt.Sprintf("%s.filepaths[%d].alternatives[%d]", path, i, j)
+			deps.Filepaths[i].Alternatives[j] = rebaseField(r, deps.Filepaths[i].Alternatives[j], altPath)
+		}
+	}
+}
+
+// isInherited reports whether the field at path, or a field containing it,
+// was taken from a template.
+//
+//goplint:ignore -- path is a display-only field path.
+func (r fragmentRebaser) isInherited(path string) bool {
+	for i := range len(path) {
+		if (path[i] == '.' || path[i] == '[') && r.inherited[path[:i]] {
+			return true
+		}
+	}
+	return r.inherited[path]
+}
+
+// rebaseField rebases value unless the field at path was inherited from a
+// template.
+//
+//goplint:ignore -- path is a display-only field path.
+func rebaseField[T ~string](r fragmentRebaser, value T, path string) T {
+	if r.isInherited(path) {
+		return value
+	}
+	return rebasePath(value, r.prefix)
+}
+
+// rebasePath prefixes a relative path; empty and absolute paths are kept.
+func rebasePath[T ~string](path T, prefix string) T {
+	if path == "" || isAbsolutePath(string(path)) {
+		return path
+	}
+	return T(slashpath.Join(prefix, string(path)))
+}
+
+func newFragmentMerger(inv *Invowkfile) *fragmentMerger {
+	m := &fragmentMerger{
+		inv:      inv,
+		commands: make(map[CommandName]FilesystemPath, len(inv.Commands)),
+		envVars:  make(map[EnvVarName]FilesystemPath),
+	}
+	for i := range inv.Commands {
+		m.commands[inv.Commands[i].Name] = inv.FilePath
+	}
+	if inv.Env != nil {
+		for name := range inv.Env.Vars {
+			m.envVars[name] = inv.FilePath
+		}
+		for name := range inv.Env.Secrets {
+			m.envVars[name] = inv.FilePath
+		}
+	}
+	return m
+}
+
+// merge appends the fragment's commands, dotenv files, and dependencies to
+// the invowkfile and adds its env vars and secrets, skipping the commands
+// and env vars whose name is already taken.
+func (m *fragmentMerger) merge(fragment *invowkfileFragment, path FilesystemPath) {
+	if fragment.Env != nil {
+		if m.inv.Env == nil {
+			m.inv.Env = &EnvConfig{}
+		}
+		env := m.inv.Env
+		env.Files = append(env.Files, fragment.Env.Files...)
+		for _, name := range slices.Sorted(maps.Keys(fragment.Env.Vars)) {
+			if m.claimEnvVar(name, path) {
+				if env.Vars == nil {
+					env.Vars = make(map[EnvVarName]string)
+				}
+				env.Vars[name] = fragment.Env.Vars[name]
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(fragment.Env.Secrets)) {
+			if m.claimEnvVar(name, path) {
+				if env.Secrets == nil {
+					env.Secrets = make(map[EnvVarName]SecretSource)
+				}
+				env.Secrets[name] = fragment.Env.Secrets[name]
+			}
+		}
+	}
+
+	m.inv.DependsOn = MergeDependsOnAll(m.inv.DependsOn, fragment.DependsOn, nil)
+
+	for i := range fragment.Commands {
+		cmd := fragment.Commands[i]
+		if definedIn, taken := m.commands[cmd.Name]; taken {
+			m.collide(ImportCollisionCommand, string(cmd.Name), path, definedIn)
+			continue
+		}
+		m.commands[cmd.Name] = path
+		cmd.ImportedFrom = path
+		m.inv.Commands = append(m.inv.Commands, cmd)
+	}
+}
+
+// claimEnvVar reports whether name is still free and reserves it for path.
+func (m *fragmentMerger) claimEnvVar(name EnvVarName, path FilesystemPath) bool {
+	if definedIn, taken := m.envVars[name]; taken {
+		m.collide(ImportCollisionEnvVar, string(name), path, definedIn)
+		return false
+	}
+	m.envVars[name] = path
+	return true
+}
+
+//goplint:ignore -- name is a command or env var name validated by its own type.
+func (m *fragmentMerger) collide(kind ImportCollisionKind, name string, fragment, definedIn FilesystemPath) {
+	m.inv.ImportCollisions = append(m.inv.ImportCollisions, ImportCollision{
+		Kind:      kind,
+		Name:      name,
+		Fragment:  fragment,
+		DefinedIn: definedIn,
+	})
+}
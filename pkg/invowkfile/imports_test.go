@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	importsRootInvowkfile = `
+imports: ["ci/*.invowk.cue", "ci/lint.invowk.cue"]
+env: vars: {MODE: "dev"}
+templates: {
+	sh: {
+		runtimes: [{name: "virtual-sh"}]
+		platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]
+	}
+}
+cmds: [{
+	name: "build"
+	implementations: [{extends: "sh", script: {content: "echo build"}}]
+}]
+`
+
+	importsLintFragment = `
+env: {
+	files: [".env.ci?"]
+	vars: {MODE: "ci", LINTER: "golangci-lint"}
+}
+depends_on: filepaths: [{alternatives: [".golangci.yml"]}]
+cmds: [{
+	name: "lint"
+	workdir: "."
+	implementations: [{extends: "sh", script: {content: "echo lint"}, env: files: ["lint.env?"]}]
+}, {
+	name: "build"
+	implementations: [{extends: "sh", script: {content: "echo other build"}}]
+}]
+`
+
+	importsTestFragment = `
+cmds: [{
+	name: "test"
+	implementations: [{extends: "sh", script: {content: "echo test"}}]
+}]
+`
+)
+
+func TestImportPatternValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern ImportPattern
+		valid   bool
+	}{
+		{"ci/*.invowk.cue", true},
+		{"ci/lint.invowk.cue", true},
+		{"./shared.cue", true},
+		{"", false},
+		{"   ", false},
+		{"/etc/invowk.cue", false},
+		{"../shared/*.cue", false},
+		{"ci/../../x.cue", false},
+		{"ci/[.cue", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.pattern), func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.pattern.Validate()
+			if tt.valid && err != nil {
+				t.Fatalf("Validate() error = %v, want nil", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidImportPattern) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidImportPattern", err)
+			}
+		})
+	}
+}
+
+func TestParseImports(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := writeImportFile(t, dir, "invowkfile.cue", importsRootInvowkfile)
+	lintPath := writeImportFile(t, dir, "ci/lint.invowk.cue", importsLintFragment)
+	testPath := writeImportFile(t, dir, "ci/test.invowk.cue", importsTestFragment)
+
+	inv, err := Parse(FilesystemPath(root))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	names := make([]CommandName, 0, len(inv.Commands))
+	for i := range inv.Commands {
+		names = append(names, inv.Commands[i].Name)
+	}
+	if want := []CommandName{"build", "lint", "test"}; !slices.Equal(names, want) {
+		t.Fatalf("commands = %v, want %v (root first, fragments in lexical order, lint imported once)", names, want)
+	}
+	build, lint, test := inv.GetCommand("build"), inv.GetCommand("lint"), inv.GetCommand("test")
+	if build.ImportedFrom != "" || string(lint.ImportedFrom) != lintPath || string(test.ImportedFrom) != testPath {
+		t.Errorf("ImportedFrom = %q/%q/%q, want empty for the root command and the fragment paths", build.ImportedFrom, lint.ImportedFrom, test.ImportedFrom)
+	}
+	if got := build.Implementations[0].Script.Content; got != "echo build" {
+		t.Errorf("build script = %q, want the root command's", got)
+	}
+
+	// Fragment commands extend the root templates, and their relative paths
+	// stay anchored at the fragment's directory.
+	if len(lint.Implementations[0].Runtimes) != 1 || lint.Implementations[0].Runtimes[0].Name != RuntimeVirtualSh {
+		t.Errorf("lint runtimes = %+v, want the sh template's", lint.Implementations[0].Runtimes)
+	}
+	if lint.WorkDir != "ci" {
+		t.Errorf("lint workdir = %q, want %q", lint.WorkDir, "ci")
+	}
+	if got := lint.Implementations[0].Env.Files; !slices.Equal(got, []DotenvFilePath{"ci/lint.env?"}) {
+		t.Errorf("lint env files = %q, want [ci/lint.env?]", got)
+	}
+	if got := inv.GetEffectiveWorkDir(lint, nil, ""); got != FilesystemPath(filepath.Join(dir, "ci")) {
+		t.Errorf("lint effective workdir = %q, want the fragment directory", got)
+	}
+
+	// Fragment env and depends_on are merged into the root, which keeps the
+	// env vars it already defines.
+	if got := inv.Env.Vars; got["MODE"] != "dev" || got["LINTER"] != "golangci-lint" {
+		t.Errorf("env vars = %v, want the root MODE and the fragment LINTER", got)
+	}
+	if !slices.Equal(inv.Env.Files, []DotenvFilePath{"ci/.env.ci?"}) {
+		t.Errorf("env files = %q, want [ci/.env.ci?]", inv.Env.Files)
+	}
+	if inv.DependsOn == nil || len(inv.DependsOn.Filepaths) != 1 || inv.DependsOn.Filepaths[0].Alternatives[0] != "ci/.golangci.yml" {
+		t.Errorf("depends_on = %+v, want the fragment filepath rebased to ci/.golangci.yml", inv.DependsOn)
+	}
+
+	want := []ImportCollision{
+		{Kind: ImportCollisionEnvVar, Name: "MODE", Fragment: FilesystemPath(lintPath), DefinedIn: FilesystemPath(root)},
+		{Kind: ImportCollisionCommand, Name: "build", Fragment: FilesystemPath(lintPath), DefinedIn: FilesystemPath(root)},
+	}
+	if !slices.Equal(inv.ImportCollisions, want) {
+		t.Errorf("ImportCollisions = %+v, want %+v", inv.ImportCollisions, want)
+	}
+}
+
+func TestParseImportsRebasesImplementationPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := writeImportFile(t, dir, "invowkfile.cue", `imports: ["ci/*.invowk.cue"]`)
+	writeImportFile(t, dir, "ci/deploy.invowk.cue", `
+cmds: [{
+	name: "deploy"
+	implementations: [{
+		script: {content: "echo remote"}
+		when: files: ["deploy.ready"]
+		runtimes: [{name: "remote-ssh", host: "build-box", identity_file: "keys/deploy_ed25519"}]
+		platforms: [{name: "linux"}]
+	}, {
+		script: {content: "echo home key"}
+		runtimes: [{name: "remote-ssh", host: "build-box", identity_file: "~/.ssh/id_ed25519"}]
+		platforms: [{name: "linux"}]
+	}, {
+		script: {content: "echo local"}
+		runtimes: [{name: "virtual-sh"}]
+		platforms: [{name: "linux", virtual: filesystem: paths: {CACHE: "cache", WORK: "@work/out"}}]
+	}]
+}]
+`)
+
+	inv, err := Parse(FilesystemPath(root))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	deploy := inv.GetCommand("deploy")
+	remote, homeKey, local := deploy.Implementations[0], deploy.Implementations[1], deploy.Implementations[2]
+	if !slices.Equal(remote.When.Files, []FilesystemPath{"ci/deploy.ready"}) {
+		t.Errorf("when.files = %q, want [ci/deploy.ready]", remote.When.Files)
+	}
+	if got := remote.Runtimes[0].IdentityFile; got != "ci/keys/deploy_ed25519" {
+		t.Errorf("identity_file = %q, want ci/keys/deploy_ed25519", got)
+	}
+	if got := homeKey.Runtimes[0].IdentityFile; got != "~/.ssh/id_ed25519" {
+		t.Errorf("home identity_file = %q, want it unchanged", got)
+	}
+	paths := local.Platforms[0].VirtualFilesystem().Paths
+	if paths["CACHE"] != "ci/cache" || paths["WORK"] != "@work/out" {
+		t.Errorf("virtual filesystem paths = %v, want CACHE rebased and the anchored WORK unchanged", paths)
+	}
+
+	// Without a workdir, imported commands run in the fragment's directory.
+	if got := inv.GetEffectiveWorkDir(deploy, &remote, ""); got != FilesystemPath(filepath.Join(dir, "ci")) {
+		t.Errorf("deploy effective workdir = %q, want the fragment directory", got)
+	}
+}
+
+func TestParseImportsKeepsTemplatePathsRootRelative(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := writeImportFile(t, dir, "invowkfile.cue", `
+imports: ["ci/*.invowk.cue"]
+templates: {
+	tool: {
+		workdir: "build"
+		env: files: ["shared.env?"]
+	}
+	image: {
+		runtimes: [{name: "container", containerfile: "Containerfile"}]
+		platforms: [{name: "linux"}]
+	}
+}
+`)
+	writeImportFile(t, dir, "ci/package.invowk.cue", `
+cmds: [{
+	name: "package"
+	extends: "tool"
+	implementations: [{
+		extends: "image"
+		script: {content: "echo package"}
+		env: files: ["package.env?"]
+		runtimes: [{name: "container", containerfile: "package.Containerfile"}]
+		platforms: [{name: "macos"}]
+	}, {
+		extends: "image"
+		script: {content: "echo image"}
+	}]
+}]
+`)
+
+	inv, err := Parse(FilesystemPath(root))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pkg := inv.GetCommand("package")
+	if pkg.WorkDir != "build" {
+		t.Errorf("workdir = %q, want the template's root-relative build", pkg.WorkDir)
+	}
+	if !slices.Equal(pkg.Env.Files, []DotenvFilePath{"shared.env?"}) {
+		t.Errorf("env.files = %q, want the template's root-relative [shared.env?]", pkg.Env.Files)
+	}
+	declared, inherited := pkg.Implementations[0], pkg.Implementations[1]
+	if !slices.Equal(declared.Env.Files, []DotenvFilePath{"ci/package.env?"}) {
+		t.Errorf("implementation env.files = %q, want the fragment's [ci/package.env?]", declared.Env.Files)
+	}
+	if got := declared.Runtimes[0].Containerfile; got != "ci/package.Containerfile" {
+		t.Errorf("declared containerfile = %q, want ci/package.Containerfile", got)
+	}
+	if got := inherited.Runtimes[0].Containerfile; got != "Containerfile" {
+		t.Errorf("inherited containerfile = %q, want the template's root-relative Containerfile", got)
+	}
+	if got := inv.GetEffectiveWorkDir(pkg, &declared, ""); got != FilesystemPath(filepath.Join(dir, "build")) {
+		t.Errorf("package effective workdir = %q, want the root build directory", got)
+	}
+}
+
+func TestParseImportsErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		root := writeImportFile(t, dir, "invowkfile.cue", `imports: ["ci/missing.invowk.cue"]
+cmds: [{name: "a", implementations: [{script: {content: "echo a"}, runtimes: [{name: "virtual-sh"}], platforms: [{name: "linux"}]}]}]`)
+		if _, err := Parse(FilesystemPath(root)); !errors.Is(err, ErrImportNotFound) {
+			t.Fatalf("Parse() error = %v, want ErrImportNotFound", err)
+		}
+	})
+
+	t.Run("glob without matches", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		root := writeImportFile(t, dir, "invowkfile.cue", `imports: ["ci/*.invowk.cue"]`)
+		_, err := Parse(FilesystemPath(root))
+		if err == nil || !strings.Contains(err.Error(), "has no commands defined") {
+			t.Fatalf("Parse() error = %v, want no commands defined", err)
+		}
+	})
+
+	t.Run("fragment outside the schema", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		root := writeImportFile(t, dir, "invowkfile.cue", `imports: ["ci/*.invowk.cue"]`)
+		fragment := writeImportFile(t, dir, "ci/a.invowk.cue", `default_shell: "/bin/bash"`)
+		_, err := Parse(FilesystemPath(root))
+		if err == nil || !strings.Contains(err.Error(), fragment) {
+			t.Fatalf("Parse() error = %v, want a schema error in %s", err, fragment)
+		}
+	})
+
+	t.Run("invalid fragment command", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		root := writeImportFile(t, dir, "invowkfile.cue", `imports: ["ci/*.invowk.cue"]`)
+		fragment := writeImportFile(t, dir, "ci/a.invowk.cue", `cmds: [{
+	name: "a"
+	flags: [{name: "ivk-x", description: "reserved"}]
+	implementations: [{script: {content: "echo a"}, runtimes: [{name: "virtual-sh"}], platforms: [{name: "linux"}]}]
+}]`)
+		_, err := Parse(FilesystemPath(root))
+		if err == nil || !strings.Contains(err.Error(), "in invowkfile at "+fragment) {
+			t.Fatalf("Parse() error = %v, want a validation error pointing at %s", err, fragment)
+		}
+	})
+
+	t.Run("module", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		data := []byte(`imports: ["ci/*.invowk.cue"]`)
+		_, err := parseBytes(data, filepath.Join(dir, "invowkfile.cue"), FilesystemPath(dir), nil, parseOptions{})
+		if !errors.Is(err, ErrImportsInModule) {
+			t.Fatalf("parseBytes() error = %v, want ErrImportsInModule", err)
+		}
+	})
+}
+
+func writeImportFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+
+	"github.com/invowk/invowk/pkg/fspath"
+)
+
+const (
+	// PathKindFile requires a path value to name a regular file.
+	PathKindFile PathKind = "file"
+	// PathKindDir requires a path value to name a directory.
+	PathKindDir PathKind = "dir"
+
+	// ListFormatLines injects list values newline-separated (the default).
+	ListFormatLines ListFormat = "lines"
+	// ListFormatJSON injects list values as a JSON array of strings.
+	ListFormatJSON ListFormat = "json"
+
+	// MaxValueSchemaLength is the maximum length, in runes, of a ValueSchema.
+	MaxValueSchemaLength = 4096
+)
+
+var (
+	// ErrInvalidPathKind is the sentinel error wrapped by InvalidPathKindError.
+	ErrInvalidPathKind = errors.New("invalid path kind")
+
+	// ErrInvalidListFormat is the sentinel error wrapped by InvalidListFormatError.
+	ErrInvalidListFormat = errors.New("invalid list format")
+
+	// ErrInvalidValueSchema is the sentinel error wrapped by InvalidValueSchemaError.
+	ErrInvalidValueSchema = errors.New("invalid value schema")
+)
+
+type (
+	// PathKind restricts what a "path" flag or argument value must point to.
+	// The zero value ("") accepts both files and directories.
+	//
+	//goplint:enum-cue=#PathKind
+	PathKind string
+
+	// InvalidPathKindError is returned when a PathKind is not "file" or "dir".
+	InvalidPathKindError struct {
+		Value PathKind
+	}
+
+	// ListFormat selects how a "list" flag is injected into INVOWK_FLAG_*.
+	// The zero value ("") is treated as ListFormatLines.
+	//
+	//goplint:enum-cue=#ListFormat
+	ListFormat string
+
+	// InvalidListFormatError is returned when a ListFormat is not "lines" or "json".
+	InvalidListFormatError struct {
+		Value ListFormat
+	}
+
+	// ValueSchema is a CUE constraint that "json" flag and argument values
+	// must satisfy (e.g., `{name: string, replicas: int & >0}`).
+	// The zero value ("") accepts any valid JSON document.
+	ValueSchema string
+
+	// InvalidValueSchemaError is returned when a ValueSchema is not a valid
+	// CUE expression or exceeds MaxValueSchemaLength.
+	InvalidValueSchemaError struct {
+		Value  ValueSchema
+		Reason string
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidPathKindError) Error() string {
+	return fmt.Sprintf("invalid path kind %q (must be file or dir)", e.Value)
+}
+
+// Unwrap returns ErrInvalidPathKind so callers can use errors.Is for programmatic detection.
+func (e *InvalidPathKindError) Unwrap() error { return ErrInvalidPathKind }
+
+// Validate returns nil if the PathKind is "file", "dir", or empty.
+func (k PathKind) Validate() error {
+	switch k {
+	case PathKindFile, PathKindDir, "":
+		return nil
+	default:
+		return &InvalidPathKindError{Value: k}
+	}
+}
+
+// String returns the string representation of the PathKind.
+func (k PathKind) String() string { return string(k) }
+
+// Error implements the error interface.
+func (e *InvalidListFormatError) Error() string {
+	return fmt.Sprintf("invalid list format %q (must be lines or json)", e.Value)
+}
+
+// Unwrap returns ErrInvalidListFormat so callers can use errors.Is for programmatic detection.
+func (e *InvalidListFormatError) Unwrap() error { return ErrInvalidListFormat }
+
+// Validate returns nil if the ListFormat is "lines", "json", or empty.
+func (f ListFormat) Validate() error {
+	switch f {
+	case ListFormatLines, ListFormatJSON, "":
+		return nil
+	default:
+		return &InvalidListFormatError{Value: f}
+	}
+}
+
+// String returns the string representation of the ListFormat.
+func (f ListFormat) String() string { return string(f) }
+
+// Error implements the error interface.
+func (e *InvalidValueSchemaError) Error() string {
+	return fmt.Sprintf("invalid value schema %q: %s", e.Value, e.Reason)
+}
+
+// Unwrap returns ErrInvalidValueSchema so callers can use errors.Is for programmatic detection.
+func (e *InvalidValueSchemaError) Unwrap() error { return ErrInvalidValueSchema }
+
+// Validate returns nil if the ValueSchema is empty or compiles as a CUE expression.
+func (s ValueSchema) Validate() error {
+	if s == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(string(s)) > MaxValueSchemaLength {
+		return &InvalidValueSchemaError{Value: s, Reason: fmt.Sprintf("must be at most %d runes", MaxValueSchemaLength)}
+	}
+	if _, err := s.compile(cuecontext.New()); err != nil {
+		return &InvalidValueSchemaError{Value: s, Reason: err.Error()}
+	}
+	return nil
+}
+
+// String returns the string representation of the ValueSchema.
+func (s ValueSchema) String() string { return string(s) }
+
+// Check returns nil if value is a JSON document that satisfies the schema.
+// An empty schema only requires value to be valid JSON.
+func (s ValueSchema) Check(value string) error {
+	if !json.Valid([]byte(value)) {
+		return errors.New("must be valid JSON")
+	}
+	if s == "" {
+		return nil
+	}
+	ctx := cuecontext.New()
+	schema, err := s.compile(ctx)
+	if err != nil {
+		return err
+	}
+	data := ctx.CompileString(value)
+	if err := data.Err(); err != nil {
+		return fmt.Errorf("must be valid JSON: %w", err)
+	}
+	if err := schema.Unify(data).Validate(cue.Concrete(true)); err != nil {
+		return fmt.Errorf("does not satisfy schema: %w", err)
+	}
+	return nil
+}
+
+func (s ValueSchema) compile(ctx *cue.Context) (cue.Value, error) {
+	value := ctx.CompileString(string(s))
+	if err := value.Err(); err != nil {
+		return cue.Value{}, err
+	}
+	return value, nil
+}
+
+// ParseListValue decodes a "list" flag value: a JSON array of strings.
+func ParseListValue(value string) ([]string, error) {
+	var values []string
+	if err := json.Unmarshal([]byte(value), &values); err != nil || values == nil {
+		return nil, errors.New("must be a JSON array of strings")
+	}
+	return values, nil
+}
+
+// EncodeListValue encodes list values into the JSON array form accepted by
+// ParseListValue. Returns "" for an empty list.
+func EncodeListValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(values) // []string always marshals
+	return string(encoded)
+}
+
+// FormatListValue renders list values the way format injects them into
+// INVOWK_FLAG_*: one value per line, or a JSON array of strings.
+func FormatListValue(values []string, format ListFormat) string {
+	if format == ListFormatJSON {
+		if len(values) == 0 {
+			return "[]"
+		}
+		return EncodeListValue(values)
+	}
+	return strings.Join(values, "\n")
+}
+
+// SplitListEnvValue decodes an INVOWK_FLAG_* value injected with format.
+// It is the inverse of FormatListValue for values without newlines.
+func SplitListEnvValue(value string, format ListFormat) []string {
+	if format == ListFormatJSON {
+		values, err := ParseListValue(value)
+		if err != nil {
+			return nil
+		}
+		return values
+	}
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, "\n")
+}
+
+// ResolveInputPath resolves a "path" flag or argument value. Relative values
+// (forward slashes) are resolved against baseDir, the invowkfile directory.
+func ResolveInputPath(baseDir FilesystemPath, value string) FilesystemPath {
+	if value == "" || fspath.IsAbs(FilesystemPath(value)) {
+		return FilesystemPath(value) //goplint:ignore -- raw CLI value, validated by checkPathValue
+	}
+	return fspath.JoinStr(baseDir, filepath.FromSlash(value))
+}
+
+// checkPathValue checks a resolved path against the must_exist and kind
+// options. A missing path is accepted unless mustExist is set.
+func checkPathValue(name string, path FilesystemPath, mustExist bool, kind PathKind) error {
+	if !mustExist && kind == "" {
+		return nil
+	}
+	info, err := os.Stat(string(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !mustExist {
+			return nil
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s path '%s' does not exist", name, path)
+		}
+		return fmt.Errorf("%s path '%s': %w", name, path, err)
+	}
+	switch {
+	case kind == PathKindFile && !info.Mode().IsRegular():
+		return fmt.Errorf("%s path '%s' is not a file", name, path)
+	case kind == PathKindDir && !info.IsDir():
+		return fmt.Errorf("%s path '%s' is not a directory", name, path)
+	}
+	return nil
+}
+
+// valueElements returns the values that choices and validation patterns apply
+// to: the decoded items of a list value, or the value itself.
+func valueElements(value string, typeName FlagType) []string {
+	if typeName != FlagTypeList {
+		return []string{value}
+	}
+	values, err := ParseListValue(value)
+	if err != nil {
+		return nil
+	}
+	return values
+}
+
+// typeOptionsValidationErrors checks that type-specific options are only set
+// for the type they apply to, and that they are valid.
+// Shared by flags and arguments.
+func typeOptionsValidationErrors(typeName FlagType, mustExist bool, kind PathKind, format ListFormat, schema ValueSchema) []error {
+	var errs []error
+	if err := kind.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := format.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := schema.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if (mustExist || kind != "") && typeName != FlagTypePath {
+		errs = append(errs, errors.New(`must_exist and kind require type "path"`))
+	}
+	if format != "" && typeName != FlagTypeList {
+		errs = append(errs, errors.New(`list_format requires type "list"`))
+	}
+	if schema != "" && typeName != FlagTypeJSON {
+		errs = append(errs, errors.New(`schema requires type "json"`))
+	}
+	return errs
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInputTypeEnums_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range []PathKind{"", PathKindFile, PathKindDir} {
+		if err := kind.Validate(); err != nil {
+			t.Errorf("PathKind(%q).Validate() = %v, want nil", kind, err)
+		}
+	}
+	if err := PathKind("socket").Validate(); !errors.Is(err, ErrInvalidPathKind) {
+		t.Errorf("PathKind(socket).Validate() = %v, want ErrInvalidPathKind", err)
+	}
+	for _, format := range []ListFormat{"", ListFormatLines, ListFormatJSON} {
+		if err := format.Validate(); err != nil {
+			t.Errorf("ListFormat(%q).Validate() = %v, want nil", format, err)
+		}
+	}
+	if err := ListFormat("csv").Validate(); !errors.Is(err, ErrInvalidListFormat) {
+		t.Errorf("ListFormat(csv).Validate() = %v, want ErrInvalidListFormat", err)
+	}
+}
+
+func TestValueSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, schema := range []ValueSchema{"", "string", "{name: string, replicas: int & >0}", "[...string]"} {
+		if err := schema.Validate(); err != nil {
+			t.Errorf("ValueSchema(%q).Validate() = %v, want nil", schema, err)
+		}
+	}
+	for _, schema := range []ValueSchema{"{name: ", ValueSchema(strings.Repeat("a", MaxValueSchemaLength+1))} {
+		if err := schema.Validate(); !errors.Is(err, ErrInvalidValueSchema) {
+			t.Errorf("ValueSchema(%.20q).Validate() = %v, want ErrInvalidValueSchema", schema, err)
+		}
+	}
+}
+
+func TestValueSchema_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		schema  ValueSchema
+		value   string
+		wantErr string
+	}{
+		{name: "any json without schema", value: `[1, "two", null]`},
+		{name: "invalid json", value: `{"a":`, wantErr: "must be valid JSON"},
+		{name: "object satisfies schema", schema: "{replicas: int & >0}", value: `{"replicas": 3}`},
+		{name: "constraint violated", schema: "{replicas: int & >0}", value: `{"replicas": 0}`, wantErr: "does not satisfy schema"},
+		{name: "required field missing", schema: "{name!: string}", value: `{}`, wantErr: "does not satisfy schema"},
+		{name: "list schema", schema: "[...string]", value: `["a", "b"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.schema.Check(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Check(%q) = %v, want nil", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Check(%q) = %v, want containing %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListValueEncoding(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"v1", "latest tag"}
+	encoded := EncodeListValue(values)
+	decoded, err := ParseListValue(encoded)
+	if err != nil || !slices.Equal(decoded, values) {
+		t.Fatalf("ParseListValue(EncodeListValue()) = %v, %v; want %v", decoded, err, values)
+	}
+	if EncodeListValue(nil) != "" {
+		t.Errorf("EncodeListValue(nil) = %q, want empty", EncodeListValue(nil))
+	}
+	for _, invalid := range []string{"", "a", "null", `[1]`, `{"a":"b"}`} {
+		if _, err := ParseListValue(invalid); err == nil {
+			t.Errorf("ParseListValue(%q) error = nil, want error", invalid)
+		}
+	}
+
+	if got := FormatListValue(values, ListFormatLines); got != "v1\nlatest tag" {
+		t.Errorf("FormatListValue(lines) = %q", got)
+	}
+	if got := FormatListValue(values, ListFormatJSON); got != `["v1","latest tag"]` {
+		t.Errorf("FormatListValue(json) = %q", got)
+	}
+	if got := FormatListValue(nil, ListFormatJSON); got != "[]" {
+		t.Errorf("FormatListValue(nil, json) = %q, want []", got)
+	}
+	for _, format := range []ListFormat{"", ListFormatJSON} {
+		if got := SplitListEnvValue(FormatListValue(values, format), format); !slices.Equal(got, values) {
+			t.Errorf("SplitListEnvValue(FormatListValue(%q)) = %q, want %q", format, got, values)
+		}
+	}
+}
+
+func TestFlag_PathValues(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	baseDir := FilesystemPath(dir)
+
+	if got := ResolveInputPath(baseDir, "conf/app.yaml"); got != FilesystemPath(filepath.Join(dir, "conf", "app.yaml")) {
+		t.Errorf("ResolveInputPath(relative) = %q", got)
+	}
+	abs := filepath.Join(dir, "app.yaml")
+	if got := ResolveInputPath(baseDir, abs); got != FilesystemPath(abs) {
+		t.Errorf("ResolveInputPath(absolute) = %q, want %q", got, abs)
+	}
+
+	tests := []struct {
+		name    string
+		flag    Flag
+		value   string
+		wantErr string
+	}{
+		{name: "missing path without must_exist", flag: Flag{Name: "out", Type: FlagTypePath, Kind: PathKindDir}, value: "missing"},
+		{name: "missing path with must_exist", flag: Flag{Name: "out", Type: FlagTypePath, MustExist: true}, value: "missing", wantErr: "does not exist"},
+		{name: "file", flag: Flag{Name: "cfg", Type: FlagTypePath, MustExist: true, Kind: PathKindFile}, value: "app.yaml"},
+		{name: "dir is not a file", flag: Flag{Name: "cfg", Type: FlagTypePath, Kind: PathKindFile}, value: ".", wantErr: "is not a file"},
+		{name: "file is not a dir", flag: Flag{Name: "out", Type: FlagTypePath, Kind: PathKindDir}, value: "app.yaml", wantErr: "is not a directory"},
+		{name: "non-path flag ignored", flag: Flag{Name: "name", MustExist: true}, value: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.flag.ValidatePathValue(baseDir, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidatePathValue(%q) = %v, want nil", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidatePathValue(%q) = %v, want containing %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlag_EnvValue(t *testing.T) {
+	t.Parallel()
+
+	baseDir := FilesystemPath(t.TempDir())
+	tests := []struct {
+		name  string
+		flag  Flag
+		value string
+		want  string
+	}{
+		{name: "string unchanged", flag: Flag{Name: "name"}, value: "x", want: "x"},
+		{name: "path resolved", flag: Flag{Name: "out", Type: FlagTypePath}, value: "dist", want: filepath.Join(string(baseDir), "dist")},
+		{name: "list as lines", flag: Flag{Name: "tag", Type: FlagTypeList}, value: `["a","b"]`, want: "a\nb"},
+		{name: "list as json", flag: Flag{Name: "tag", Type: FlagTypeList, ListFormat: ListFormatJSON}, value: `["a","b"]`, want: `["a","b"]`},
+		{name: "empty list as json", flag: Flag{Name: "tag", Type: FlagTypeList, ListFormat: ListFormatJSON}, want: "[]"},
+		{name: "empty list as lines", flag: Flag{Name: "tag", Type: FlagTypeList}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.flag.EnvValue(baseDir, tt.value); got != tt.want {
+				t.Fatalf("EnvValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -71,6 +71,17 @@ type (
 		// This is useful for defining shared prerequisites like required tools or capabilities
 		// that apply to all commands in this invowkfile.
 		DependsOn *DependsOn `json:"depends_on,omitempty"`
+		// Hooks declares lifecycle scripts that run around every command (optional).
+		// Root-level hooks run before command-level hooks within each phase.
+		Hooks *Hooks `json:"hooks,omitempty"`
+		// Templates declares reusable command and implementation fragments (optional).
+		// They are already merged into the commands and implementations that
+		// extend them; modules export them to their consumers.
+		Templates map[TemplateName]Template `json:"templates,omitempty"`
+		// Imports lists paths or globs of invowkfile fragments, relative to this
+		// invowkfile's directory (optional). Their commands, env, and depends_on
+		// are already merged into this invowkfile.
+		Imports []ImportPattern `json:"imports,omitempty"`
 		// Commands defines the available commands (invowkfile field: 'cmds')
 		Commands []Command `json:"cmds"`
 
@@ -83,6 +94,9 @@ type (
 		// It intentionally uses a local DTO to avoid coupling Invowkfile's core
 		// command model to pkg/invowkmod internals.
 		Metadata *ModuleMetadata `json:"-"`
+		// ImportCollisions records the fragment commands and env vars that were
+		// ignored while merging imports because their name was taken (not in CUE).
+		ImportCollisions []ImportCollision `json:"-"`
 	}
 )
 
@@ -107,8 +121,9 @@ func CurrentPlatform() Platform {
 // *Invowkfile already has a Validate(opts ...ValidateOption) ValidationErrors method
 // in validation.go that runs the full composite validation pipeline.
 // Delegates to DefaultShell (zero-valid), WorkDir (zero-valid), Env (non-nil),
-// DependsOn (non-nil), Metadata (non-nil), each Command, FilePath
-// (non-empty), and ModulePath (non-empty).
+// DependsOn (non-nil), Hooks (non-nil), each Templates key, each Imports
+// pattern, Metadata (non-nil), each Command, each ImportCollisions entry,
+// FilePath (non-empty), and ModulePath (non-empty).
 func (inv Invowkfile) ValidateFields() error {
 	var errs []error
 	inv.appendBaseValidationErrors(&errs)
@@ -164,7 +179,8 @@ func (inv *Invowkfile) GetScriptBasePath() FilesystemPath {
 //  2. Implementation-level workdir (impl.WorkDir)
 //  3. Command-level workdir (cmd.WorkDir)
 //  4. Root-level workdir (inv.WorkDir)
-//  5. Default: invowkfile directory
+//  5. Default: invowkfile directory, or the fragment directory for commands
+//     imported from a fragment
 //
 // All workdir paths in CUE should use forward slashes for cross-platform compatibility.
 // Relative paths are resolved against the invowkfile location.
@@ -216,7 +232,10 @@ func (inv *Invowkfile) GetEffectiveWorkDir(cmd *Command, impl *Implementation, c
 		return resolve(string(inv.WorkDir))
 	}
 
-	// Priority 5: Default (invowkfile directory)
+	// Priority 5: Default (invowkfile or fragment directory)
+	if cmd != nil && cmd.ImportedFrom != "" {
+		return fspath.Dir(cmd.ImportedFrom)
+	}
 	return invowkfileDir
 }
 
@@ -284,6 +303,21 @@ func (inv Invowkfile) appendBaseValidationErrors(errs *[]error) {
 			*errs = append(*errs, err)
 		}
 	}
+	if inv.Hooks != nil {
+		if err := inv.Hooks.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+	for name := range inv.Templates {
+		if err := name.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+	for _, pattern := range inv.Imports {
+		if err := pattern.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
 	if inv.Metadata != nil {
 		if err := inv.Metadata.Validate(); err != nil {
 			*errs = append(*errs, err)
@@ -297,6 +331,11 @@ func (inv Invowkfile) appendCommandValidationErrors(errs *[]error) {
 			*errs = append(*errs, err)
 		}
 	}
+	for _, collision := range inv.ImportCollisions {
+		if err := collision.Validate(); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
 }
 
 func (inv Invowkfile) appendPathValidationErrors(errs *[]error) {
@@ -433,6 +433,63 @@ cmds: [
 	}
 }
 
+func TestParseDependsOn_RunPrerequisites(t *testing.T) {
+	t.Parallel()
+
+	cueContent := `
+cmds: [
+	{
+		name: "deploy"
+		implementations: [
+			{
+				script: {content: "echo deploy"}
+
+				runtimes: [{name: "native"}]
+				platforms: [{name: "linux"}, {name: "macos"}]
+			}
+		]
+		depends_on: {
+			cmds: [
+				{alternatives: ["build"], run: true},
+				{alternatives: ["lint"]},
+				{alternatives: ["test unit", "test"], run: true},
+			]
+		}
+	}
+]
+`
+
+	inv, err := ParseBytes([]byte(cueContent), "invowkfile.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+
+	prereqs := inv.Commands[0].DependsOn.Prerequisites()
+	if len(prereqs) != 2 {
+		t.Fatalf("Prerequisites() = %#v, want 2 entries", prereqs)
+	}
+	if prereqs[0].Alternatives[0] != "build" || prereqs[1].Alternatives[0] != "test unit" {
+		t.Errorf("Prerequisites() = %#v, want build then test unit", prereqs)
+	}
+
+	generated := GenerateCUE(inv)
+	if !strings.Contains(generated, `{alternatives: ["build"], run: true}`) {
+		t.Errorf("GenerateCUE() should emit run: true, got:\n%s", generated)
+	}
+	roundtrip, err := ParseBytes([]byte(generated), "roundtrip.cue")
+	if err != nil {
+		t.Fatalf("roundtrip ParseBytes() error = %v", err)
+	}
+	if got := len(roundtrip.Commands[0].DependsOn.Prerequisites()); got != 2 {
+		t.Errorf("roundtrip Prerequisites() = %d entries, want 2", got)
+	}
+
+	var nilDeps *DependsOn
+	if nilDeps.Prerequisites() != nil {
+		t.Error("Prerequisites() on nil DependsOn should return nil")
+	}
+}
+
 func TestParseDependsOn_WithCustomChecks(t *testing.T) {
 	t.Parallel()
 
@@ -8,7 +8,7 @@
 import "strings"
 
 // RuntimeType defines the available execution runtime types
-#RuntimeType: "native" | "virtual-sh" | "virtual-lua" | "container"
+#RuntimeType: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark" | "container" | "remote-ssh"
 
 // BinaryLookupMode defines how virtual runtimes resolve allowed host binaries.
 #BinaryLookupMode: "host" | "strict"
@@ -27,11 +27,34 @@ import "strings"
 
 #VirtualFilesystemPath: #NonWhitespaceString & strings.MaxRunes(4096)
 
+// SandboxNetwork controls network access for sandboxed native scripts.
+#SandboxNetwork: *"none" | "host"
+
 // FlagType defines the valid types for command flags
-#FlagType: "string" | "bool" | "int" | "float"
+#FlagType: "string" | "bool" | "int" | "float" | "duration" | "path" | "list" | "json"
 
 // ArgumentType defines the valid types for command arguments
-#ArgumentType: "string" | "int" | "float"
+#ArgumentType: "string" | "int" | "float" | "duration" | "path" | "json"
+
+// CommandVisibility controls who can see and run a command
+#CommandVisibility: "public" | "private" | "hidden"
+
+// LockScope selects which runs share a command lock
+#LockScope: "project" | "user"
+
+// PathKind restricts what a "path" flag or argument must point to
+#PathKind: "file" | "dir"
+
+// PromptType selects the TUI component of an argument or flag prompt
+#PromptType: "input" | "choose" | "confirm" | "write" | "filter" | "file"
+
+// ListFormat selects how a "list" flag is injected into INVOWK_FLAG_<NAME>
+#ListFormat: "lines" | "json"
+
+// ValueSchema is a CUE constraint that a "json" flag or argument value must satisfy
+// (e.g., "{name: string, replicas: int & >0}").
+// [GO-ONLY] The constraint must compile as a CUE expression; enforced after decode.
+#ValueSchema: #NonWhitespaceString & strings.MaxRunes(4096)
 
 // DurationString constrains a Go-style duration string (e.g., "30s", "5m", "1h30m").
 // Shared by #Implementation.timeout and #WatchConfig.debounce.
@@ -56,10 +79,48 @@ import "strings"
 	// vars contains environment variables as key-value pairs (optional)
 	// These override values loaded from files.
 	vars?: [string & =~"^[A-Za-z_][A-Za-z0-9_]*$"]: string & strings.MaxRunes(32768)
+
+	// secrets maps environment variable names to secret sources (optional)
+	// Values are resolved at execution time and override vars at the same level.
+	// Resolved values are masked in command output, dry-run plans, and audit prompts.
+	// [GO-ONLY] A name cannot appear in both vars and secrets; enforced after decode.
+	secrets?: [string & =~"^[A-Za-z_][A-Za-z0-9_]*$"]: #SecretSource
+})
+
+// SecretProviderType selects where a secret value is read from
+#SecretProviderType: "command" | "file" | "env" | "keyring"
+
+// SecretSource resolves the value of a secret environment variable at execution time.
+// [GO-ONLY] Each provider requires its own field (command, path, var, or service)
+// and rejects the fields of other providers; enforced after decode.
+#SecretSource: close({
+	// provider selects the secret source (required)
+	// - "command": stdout of an external command, e.g. ["pass", "show", "deploy/token"]
+	// - "file": contents of a file (relative to the invowkfile directory or absolute)
+	// - "env": a host environment variable
+	// - "keyring": the OS keyring (secret-tool on Linux, security on macOS)
+	provider: #SecretProviderType
+
+	// command is the argv of the command printing the secret ("command" provider)
+	// One trailing newline is trimmed from its output.
+	command?: [#NonWhitespaceString, ...(string & !="")]
+
+	// path is the file holding the secret ("file" provider)
+	// One trailing newline is trimmed from the file contents.
+	path?: string & !="" & strings.MaxRunes(4096)
+
+	// var is the host environment variable holding the secret ("env" provider)
+	var?: string & =~"^[A-Za-z_][A-Za-z0-9_]*$"
+
+	// service is the keyring service name ("keyring" provider)
+	service?: #NonWhitespaceString & strings.MaxRunes(256)
+
+	// account is the keyring account name ("keyring" provider, optional)
+	account?: #NonWhitespaceString & strings.MaxRunes(256)
 })
 
 // RuntimeConfig represents a runtime configuration with type-specific options
-#RuntimeConfig: #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigContainer
+#RuntimeConfig: #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigVirtualStarlark | #RuntimeConfigContainer | #RuntimeConfigRemoteSSH
 
 #RuntimeConfigBase: {
 	// name specifies the runtime type (required)
@@ -80,6 +141,17 @@ import "strings"
 #RuntimeConfigNative: close({
 	#RuntimeConfigBase
 	name: "native"
+
+	// sandbox runs the script in new user, mount, and PID namespaces with a
+	// read-only root filesystem (Linux only). Writes are allowed only beneath
+	// the working directory, temp directories, Invowk's config/data/cache/state
+	// directories, and the platform's virtual.filesystem.paths, enforced with
+	// Landlock. virtual.filesystem.access: "full" lifts the write restriction.
+	sandbox?: close({
+		// network controls network access: "none" (default) gives the script
+		// its own network namespace with only loopback; "host" shares the host network.
+		network?: #SandboxNetwork
+	})
 })
 
 #RuntimeConfigVirtualBase: {
@@ -110,6 +182,15 @@ import "strings"
 	memory_limit?: string & =~"^[0-9]+([KkMmGg][Bb]?)?$" & strings.MaxRunes(32)
 })
 
+#RuntimeConfigVirtualStarlark: close({
+	#RuntimeConfigVirtualBase
+	name: "virtual-starlark"
+
+	// max_steps caps the Starlark execution steps, including load()ed files.
+	// Zero or omission means unlimited.
+	max_steps?: int & >=0
+})
+
 #RuntimeConfigContainer: #RuntimeConfigContainerWithImage | #RuntimeConfigContainerWithContainerfile
 
 #RuntimeConfigContainerBase: {
@@ -174,6 +255,30 @@ import "strings"
 	image?: _|_
 })
 
+// RuntimeConfigRemoteSSH runs the script on another host through the system ssh client.
+// The script is uploaded to a private temporary directory on the host, which is
+// removed when the script exits. Host environment inheritance defaults to "none".
+#RuntimeConfigRemoteSSH: close({
+	#RuntimeConfigBase
+	name: "remote-ssh"
+
+	// host is the host name, address, or ssh_config alias to connect to (required)
+	host: string & =~"^[A-Za-z0-9_][A-Za-z0-9_.:%-]*$" & strings.MaxRunes(253)
+
+	// port is the SSH port (optional, default: the ssh client default, usually 22)
+	port?: int & >=1 & <=65535
+
+	// user is the remote login name (optional, default: the ssh client default)
+	user?: string & =~"^[A-Za-z0-9_][A-Za-z0-9_.-]*$" & strings.MaxRunes(256)
+
+	// identity_file is the private key used to authenticate (optional).
+	// Relative paths resolve against the invowkfile directory.
+	identity_file?: #NonWhitespaceString & strings.MaxRunes(4096)
+
+	// workdir is the remote working directory (optional, default: the remote login directory)
+	workdir?: #NonWhitespaceString & strings.MaxRunes(4096)
+})
+
 // VirtualFilesystemConfig configures virtual-runtime filesystem access for a platform.
 #VirtualFilesystemConfig: close({
 	// access controls whether VM-managed filesystem operations are limited to
@@ -194,6 +299,11 @@ import "strings"
 	// name specifies the platform type (required)
 	name: #PlatformType
 
+	// arch restricts the platform to these CPU architectures (optional).
+	// Omit to match every architecture. The container runtime passes the
+	// matched architecture to the engine as --platform linux/<arch>.
+	arch?: [...#Architecture] & [_, ...]
+
 	// virtual contains platform-specific settings for virtual-* runtimes.
 	virtual?: #PlatformVirtualConfig
 })
@@ -247,8 +357,45 @@ import "strings"
 // CustomCheckScript selects the executable script source for a custom dependency check.
 #CustomCheckScript: #ScriptSource
 
+// TemplateName identifies a template in the top-level templates map.
+#TemplateName: string & =~"^[a-zA-Z][a-zA-Z0-9_-]*$" & strings.MaxRunes(256)
+
+// TemplateRef names the template a command or implementation extends: a
+// template of the same invowkfile ("container"), or a template exported by a
+// module as "@module template" ("@io.example.tools container").
+#TemplateRef: string & =~"^(@[a-zA-Z][a-zA-Z0-9.]* )?[a-zA-Z][a-zA-Z0-9_-]*$" & strings.MaxRunes(514)
+
+// Template is a reusable fragment of a command or implementation.
+// Templates are deep-merged into the commands and implementations extending
+// them before the schema is applied, so they are only checked where used.
+// [GO-ONLY] Templates cannot extend other templates; enforced while parsing.
+#Template: {...}
+
+// ImportPattern is a path or glob of invowkfile fragments, relative to the
+// directory of the importing invowkfile (e.g. "ci/*.invowk.cue").
+// [GO-ONLY] Patterns must be valid filepath.Match globs and cannot contain
+// '..' segments; enforced by ImportPattern.Validate.
+#ImportPattern: #NonWhitespaceString & !~"^/" & strings.MaxRunes(4096)
+
+// InvowkfileFragment is a file loaded through the imports of an invowkfile.
+// Its commands, env, and depends_on are merged with those of the invowkfile;
+// its commands can extend the invowkfile's templates.
+// [GO-ONLY] Commands and env vars whose name is already defined are ignored
+// and reported; relative paths resolve against the fragment's directory.
+#InvowkfileFragment: close({
+	env?:        #EnvConfig
+	depends_on?: #DependsOn
+	cmds?: [...#Command]
+})
+
 // Implementation represents an implementation with platform and runtime constraints
 #Implementation: close({
+	// extends deep-merges a template into this implementation (optional)
+	// Fields declared here win; nested structs are merged field by field, and
+	// lists and scalars are replaced whole.
+	// [GO-ONLY] The template must exist; resolved while parsing.
+	extends?: #TemplateRef
+
 	// script selects the executable script source (required).
 	// Use content for inline shell commands and file for script-file references.
 	script: #ImplementationScript
@@ -286,6 +433,79 @@ import "strings"
 	// Must be a valid Go duration string (e.g., "30s", "5m", "1h30m")
 	// When exceeded, the command is cancelled and returns a timeout error.
 	timeout?: #DurationString
+
+	// retry re-runs the implementation when it exits with a non-zero code (optional)
+	// Every runtime honors the policy. INVOWK_ATTEMPT holds the 1-based attempt number.
+	retry?: #RetryPolicy
+
+	// success_exit_codes lists non-zero exit codes of the script that count as success (optional)
+	// They are reported as exit code 0, e.g. [1] for tools like grep or diff.
+	success_exit_codes?: [...int & >=1 & <=255] & [_, ...]
+
+	// exit_code_map translates exit codes of the script into the reported exit codes (optional)
+	// Keys are the script's exit codes, e.g. {"2": 1, "3": 10}. Runtime errors and
+	// container engine failures are never translated; retry.on_exit_codes matches the code before translation.
+	// [GO-ONLY] Keys must be at most 255 and must not be listed in success_exit_codes;
+	// enforced after decode.
+	exit_code_map?: [=~"^(0|[1-9][0-9]{0,2})$"]: int & >=0 & <=255
+
+	// sources lists glob patterns for the input files of this implementation (optional)
+	// Appended to command-level sources. See #Command.sources.
+	sources?: [...#GlobPattern] & [_, ...]
+
+	// generates lists glob patterns for the output files of this implementation (optional)
+	// Appended to command-level generates. See #Command.generates.
+	// [GO-ONLY] Requires sources on the command or implementation; enforced after decode.
+	generates?: [...#GlobPattern] & [_, ...]
+
+	// when restricts this implementation to executions where every condition holds (optional)
+	// Implementations are tried in order; the first one matching the platform, runtime, and
+	// when conditions is selected. Implementations with when may share a platform+runtime
+	// combination with later implementations (e.g., container in CI, native otherwise).
+	when?: #When
+})
+
+// When lists conditions that must all hold for an implementation to be selected.
+// [GO-ONLY] At least one condition is required, and flags/args must name declared
+// flags and arguments of the command; enforced after decode.
+#When: close({
+	// env requires host environment variables to be set to exactly these values
+	env?: [string & =~"^[A-Za-z_][A-Za-z0-9_]*$"]: string & strings.MaxRunes(32768)
+
+	// flags requires command flags to have these values (defaults included)
+	flags?: [string & =~"^[a-zA-Z][a-zA-Z0-9_-]*$"]: string & strings.MaxRunes(4096)
+
+	// args requires positional arguments to have these values
+	// Variadic arguments are compared against their space-joined values.
+	args?: [string & =~"^[a-zA-Z][a-zA-Z0-9_-]*$"]: string & strings.MaxRunes(4096)
+
+	// files requires every path to exist, relative to the invowkfile directory (or module root)
+	files?: [...#NonWhitespaceString & strings.MaxRunes(4096)] & [_, ...]
+
+	// arch requires the host CPU architecture (GOARCH spelling)
+	arch?: #Architecture
+})
+
+// Architecture is a CPU architecture, spelled like Go's GOARCH.
+#Architecture: "amd64" | "arm64" | "386" | "arm" | "riscv64" | "ppc64le" | "s390x"
+
+// RetryPolicy re-runs a failed implementation with exponential backoff.
+// Runtime errors, timeouts, and cancellation are never retried.
+#RetryPolicy: close({
+	// attempts is the total number of runs, including the first one (required, 1-100)
+	attempts: int & >=1 & <=100
+
+	// backoff is the delay before the first retry (optional, default: "1s")
+	// The delay doubles after every retry.
+	backoff?: #DurationString
+
+	// max_backoff caps the delay between retries (optional, default: no cap)
+	// [GO-ONLY] Must not be shorter than backoff; enforced after decode.
+	max_backoff?: #DurationString
+
+	// on_exit_codes restricts retries to these exit codes (optional)
+	// When omitted, every non-zero exit code is retried.
+	on_exit_codes?: [...int & >=1 & <=255] & [_, ...]
 })
 
 // ToolDependency represents a tool/binary that must be available in PATH
@@ -361,6 +581,13 @@ import "strings"
 	// If any of the provided commands is discoverable, the dependency is satisfied (early return).
 	// This allows specifying alternative commands (e.g., ["build-debug", "@tools lint"]).
 	alternatives: [...#CommandDependencyRef] & [_, ...]
+
+	// run executes the first discoverable alternative as a prerequisite before the
+	// declaring command runs (optional, default: false). Shared prerequisites are
+	// deduplicated and independent prerequisites run in parallel, bounded by the
+	// execution.max_parallel config setting. A failing prerequisite fails the command.
+	// [GO-ONLY] run: true is rejected in runtime-level (container) depends_on.
+	run?: bool
 })
 
 // CapabilityName defines the supported system capability types
@@ -427,6 +654,62 @@ import "strings"
 	env_vars?: [...#EnvVarDependency]
 })
 
+// PromptConfig asks for a missing required argument or flag interactively.
+// Prompts only run when stdin and stdout are terminals; otherwise the usual
+// missing-argument/flag error is reported. The answer is injected through
+// INVOWK_ARG_<NAME>/INVOWK_FLAG_<NAME> and validated like a CLI value.
+#PromptConfig: close({
+	// type selects the TUI component (required)
+	// - "input": single line of text
+	// - "choose": pick one of options (or the argument/flag choices)
+	// - "confirm": yes/no question (bool flags only)
+	// - "write": multi-line text
+	// - "filter": fuzzy-filter options (or the argument/flag choices)
+	// - "file": file picker
+	// [GO-ONLY] "confirm" is required for bool flags and rejected otherwise; enforced after decode.
+	type: #PromptType
+
+	// message is the question shown to the user (required)
+	message: string & =~"^\\s*\\S.*$" & strings.MaxRunes(1024)
+
+	// options lists the values offered by "choose" and "filter" prompts (optional)
+	// When omitted, the argument/flag choices are offered.
+	// [GO-ONLY] Only allowed for "choose" and "filter"; options must be valid values
+	// (one of the choices, if declared); enforced after decode.
+	options?: [...string & !="" & strings.MaxRunes(256)] & [_, ...]
+
+	// placeholder is hint text for "input" and "write" prompts (optional)
+	// [GO-ONLY] Only allowed for "input" and "write"; enforced after decode.
+	placeholder?: string & strings.MaxRunes(256)
+
+	// char_limit limits the answer length of "input" prompts (optional)
+	// [GO-ONLY] Only allowed for "input"; enforced after decode.
+	char_limit?: int & >0
+
+	// extensions filters the files offered by "file" prompts (optional, e.g., [".go", ".ts"])
+	// [GO-ONLY] Only allowed for "file"; enforced after decode.
+	extensions?: [...string & =~"^\\.[a-zA-Z0-9]+$"] & [_, ...]
+})
+
+// CompletionConfig computes the shell completion candidates of an argument or
+// flag with a script. Every non-empty stdout line is one candidate, optionally
+// followed by a tab and a description ("staging\tStaging cluster").
+// The script runs in the context of the command: it sees the INVOWK_FLAG_* and
+// INVOWK_ARG_* variables of the values typed so far, and the command env.
+// Results are cached for the rest of the shell session.
+#CompletionConfig: close({
+	// script is the completion script (same shape as an implementation script)
+	script: #ImplementationScript
+
+	// runtime selects the runtime that runs the script (optional, default: "native")
+	// Use "virtual-sh" for a script that completes on every platform.
+	runtime?: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark"
+
+	// timeout limits how long the script may run (optional, default: "2s")
+	// A script that times out or fails offers no completions.
+	timeout?: #DurationString
+})
+
 // Argument represents a positional command-line argument for a command
 #Argument: close({
 	// name is the argument identifier (required, POSIX-compliant)
@@ -450,10 +733,14 @@ import "strings"
 	default_value?: string & !="" & strings.MaxRunes(4096)
 
 	// type specifies the data type of the argument (optional, defaults to "string")
-	// Supported types: "string", "int", "float"
+	// Supported types: "string", "int", "float", "duration", "path", "json"
 	// - "string": any string value (default)
 	// - "int": must be a valid integer
 	// - "float": must be a valid floating-point number
+	// - "duration": must be a positive Go duration (e.g., "30s", "1h30m")
+	// - "path": a filesystem path; relative paths are resolved against the invowkfile
+	//   directory and INVOWK_ARG_<NAME> holds the resolved path
+	// - "json": must be a valid JSON document (checked against schema, if declared)
 	// Note: "bool" is not supported for positional arguments (use flags instead)
 	type?: #ArgumentType
 
@@ -462,6 +749,33 @@ import "strings"
 	// If default_value is specified, it must also match this pattern
 	validation?: string & !="" & strings.MaxRunes(1000)
 
+	// choices lists the allowed values for the argument (optional, at least one)
+	// Values outside the list are rejected; shell completion offers the list.
+	// For variadic arguments every value must be one of the choices.
+	// [GO-ONLY] Choices must be unique and compatible with type and validation,
+	// and default_value must be one of them; enforced after decode.
+	choices?: [...string & !="" & strings.MaxRunes(4096)] & [_, ...]
+
+	// must_exist requires a "path" argument to exist before the command runs (optional)
+	// [GO-ONLY] Only allowed for type "path"; enforced after decode.
+	must_exist?: bool
+
+	// kind requires a "path" argument to be a file or a directory when it exists (optional)
+	// [GO-ONLY] Only allowed for type "path"; enforced after decode.
+	kind?: #PathKind
+
+	// schema is a CUE constraint that a "json" argument must satisfy (optional)
+	// [GO-ONLY] Only allowed for type "json"; enforced after decode.
+	schema?: #ValueSchema
+
+	// prompt asks for the argument interactively when it is missing (optional)
+	// [GO-ONLY] Only allowed together with required: true; enforced after decode.
+	prompt?: #PromptConfig
+
+	// complete computes the shell completion candidates with a script (optional)
+	// [GO-ONLY] Not allowed together with choices; enforced after decode.
+	complete?: #CompletionConfig
+
 	// variadic indicates this argument accepts multiple values (optional, defaults to false)
 	// Only the last argument in the args list can be variadic
 	// Variadic arguments are passed as space-separated values in INVOWK_ARG_<NAME>
@@ -486,11 +800,17 @@ import "strings"
 	default_value?: string & !="" & strings.MaxRunes(4096)
 
 	// type specifies the data type of the flag (optional, defaults to "string")
-	// Supported types: "string", "bool", "int", "float"
+	// Supported types: "string", "bool", "int", "float", "duration", "path", "list", "json"
 	// - "string": any string value (default)
 	// - "bool": must be "true" or "false"
 	// - "int": must be a valid integer
 	// - "float": must be a valid floating-point number
+	// - "duration": must be a positive Go duration (e.g., "30s", "1h30m")
+	// - "path": a filesystem path; relative paths are resolved against the invowkfile
+	//   directory and INVOWK_FLAG_<NAME> holds the resolved path
+	// - "list": repeatable flag collecting every occurrence; default_value is a
+	//   JSON array of strings (e.g., "[\"a\", \"b\"]")
+	// - "json": must be a valid JSON document (checked against schema, if declared)
 	type?: #FlagType
 
 	// required indicates whether this flag must be provided (optional, defaults to false)
@@ -507,14 +827,54 @@ import "strings"
 	// The flag value must match this pattern
 	// If default_value is specified, it must also match this pattern
 	validation?: string & !="" & strings.MaxRunes(1000)
+
+	// choices lists the allowed values for the flag (optional, at least one)
+	// Values outside the list are rejected; shell completion offers the list.
+	// For list flags every item must be one of the choices.
+	// [GO-ONLY] Not supported for bool or json flags. Choices must be unique and compatible
+	// with type and validation, and default_value must be one of them; enforced after decode.
+	choices?: [...string & !="" & strings.MaxRunes(4096)] & [_, ...]
+
+	// must_exist requires a "path" flag to exist before the command runs (optional)
+	// [GO-ONLY] Only allowed for type "path"; enforced after decode.
+	must_exist?: bool
+
+	// kind requires a "path" flag to be a file or a directory when it exists (optional)
+	// [GO-ONLY] Only allowed for type "path"; enforced after decode.
+	kind?: #PathKind
+
+	// list_format selects how a "list" flag is injected (optional, default: "lines")
+	// - "lines": one value per line
+	// - "json": a JSON array of strings
+	// [GO-ONLY] Only allowed for type "list"; enforced after decode.
+	list_format?: #ListFormat
+
+	// schema is a CUE constraint that a "json" flag must satisfy (optional)
+	// [GO-ONLY] Only allowed for type "json"; enforced after decode.
+	schema?: #ValueSchema
+
+	// prompt asks for the flag interactively when it is missing (optional)
+	// A prompted flag is not required by the CLI parser; the missing-flag error
+	// is reported after prompting is skipped or unavailable.
+	// [GO-ONLY] Only allowed together with required: true; enforced after decode.
+	prompt?: #PromptConfig
+
+	// complete computes the shell completion candidates with a script (optional)
+	// [GO-ONLY] Not allowed together with choices or for type "bool"; enforced after decode.
+	complete?: #CompletionConfig
 })
 
+// GlobPattern is a file-matching glob pattern relative to the effective working directory.
+// Patterns support ** for recursive matching (e.g., "src/**/*.go", "*.ts").
+// Shared by #WatchConfig and the incremental sources/generates lists.
+#GlobPattern: string & !="" & strings.MaxRunes(4096)
+
 // WatchConfig defines file-watching behavior for automatic command re-execution
 #WatchConfig: close({
 	// patterns lists glob patterns for files to watch (required, at least one)
 	// Patterns support ** for recursive matching (e.g., "src/**/*.go", "*.ts")
 	// Paths are relative to the effective working directory of the command
-	patterns: [...string & !="" & strings.MaxRunes(4096)] & [_, ...]
+	patterns: [...#GlobPattern] & [_, ...]
 
 	// debounce specifies the delay before re-executing after a change (optional)
 	// Must be a valid Go duration string (e.g., "500ms", "1s", "2s")
@@ -527,7 +887,184 @@ import "strings"
 
 	// ignore lists glob patterns for files/directories to exclude from watching (optional)
 	// Common ignores (.git, node_modules) are applied by default
-	ignore?: [...string & !="" & strings.MaxRunes(4096)]
+	ignore?: [...#GlobPattern]
+})
+
+// CommandStepBase contains fields shared by all command step variants.
+#CommandStepBase: {
+	// name is an optional display label used in progress and dry-run output (optional)
+	name?: #NonWhitespaceString & strings.MaxRunes(256)
+
+	// runtime selects the runtime for this step (optional)
+	// For cmd steps it overrides the invoked command's runtime selection.
+	// For script steps it selects the runtime that runs the script (default: "native").
+	runtime?: #RuntimeType
+
+	// continue_on_error keeps executing later steps when this step fails (optional, default: false)
+	// The failure is reported but does not fail the parent command.
+	continue_on_error?: bool
+}
+
+// CommandStep is one entry of a sequential multi-step command.
+// Exactly one of cmd or script is required.
+#CommandStep: #CommandStepCmd | #CommandStepScript
+
+#CommandStepCmd: close({
+	#CommandStepBase
+
+	// cmd references the command to invoke.
+	// Bare refs resolve in the declaring command's source; "@source command" refs
+	// select an explicit source (same syntax as depends_on.cmds).
+	cmd: #CommandDependencyRef
+
+	// flags binds flag values on the invoked command (optional)
+	// Flags not bound here fall back to the invoked command's defaults.
+	flags?: [string & =~"^[a-zA-Z][a-zA-Z0-9_-]*$" & strings.MaxRunes(256)]: string & strings.MaxRunes(4096)
+
+	// args are positional arguments passed to the invoked command (optional)
+	args?: [...string & strings.MaxRunes(4096)]
+})
+
+#CommandStepScript: close({
+	#CommandStepBase
+
+	// script is an inline script run in the context of the declaring command.
+	// The script sees the declaring command's INVOWK_FLAG_* and INVOWK_ARG_* variables.
+	script: #ImplementationScript
+
+	// runtime is restricted to runtimes that need no extra configuration.
+	// Use a cmd step that references a container implementation instead.
+	runtime?: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark"
+})
+
+// Hook is one lifecycle script run around a command's main script.
+// Hooks run in the context of the command: they see its INVOWK_FLAG_*,
+// INVOWK_ARG_* and env variables, and its working directory.
+#Hook: close({
+	// script is the hook script (same shape as an implementation script)
+	script: #ImplementationScript
+
+	// runtime selects the runtime that runs the hook (optional, default: "native")
+	// Restricted to runtimes that need no extra configuration.
+	runtime?: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark"
+})
+
+// Hooks groups lifecycle scripts by phase. Each phase runs its hooks in
+// declaration order; root-level hooks run before command-level hooks.
+#Hooks: close({
+	// before hooks run before the main script (optional)
+	// The first failing before hook skips the main script and the after hooks.
+	before?: [...#Hook] & [_, ...]
+
+	// after hooks run after the main script succeeds (optional)
+	after?: [...#Hook] & [_, ...]
+
+	// finally hooks always run last (optional): on success, on a non-zero exit,
+	// on timeout, and after an interrupt (SIGINT). Every finally hook runs.
+	finally?: [...#Hook] & [_, ...]
+})
+
+// FlagGroup lists the flags a flag group constraint applies to.
+// [GO-ONLY] Flags must be declared by the command and listed once; enforced after decode.
+#FlagGroup: [...string & =~"^[a-zA-Z][a-zA-Z0-9_-]*$" & strings.MaxRunes(256)] & [_, _, ...]
+
+// FlagGroups declares constraints between the flags of a command.
+// Only flags set on the command line count; defaults do not.
+#FlagGroups: close({
+	// mutually_exclusive lists groups of which at most one flag may be set (optional)
+	mutually_exclusive?: [...#FlagGroup] & [_, ...]
+
+	// required_together lists groups whose flags must all be set once any is set (optional)
+	required_together?: [...#FlagGroup] & [_, ...]
+
+	// one_required lists groups of which at least one flag must be set (optional)
+	one_required?: [...#FlagGroup] & [_, ...]
+})
+
+// Deprecation marks a command as deprecated.
+#Deprecation: close({
+	// message explains why the command is deprecated (required)
+	message: string & =~"^\\s*\\S.*$" & strings.MaxRunes(10240)
+
+	// replacement names the command that supersedes this one (optional)
+	// [GO-ONLY] Must name another command in the same invowkfile; enforced after decode.
+	replacement?: string & =~"^[a-zA-Z][a-zA-Z0-9_ -]*$" & strings.MaxRunes(256)
+
+	// forward runs the replacement instead of the deprecated command (optional, default: false)
+	// Positional args and the flags the replacement declares are passed along.
+	// [GO-ONLY] Requires replacement; enforced after decode.
+	forward?: bool
+})
+
+// ConfirmConfig asks for an interactive yes before a command runs.
+#ConfirmConfig: close({
+	// message is the question shown before the command runs (required)
+	// {{flag.<name>}} and {{arg.<name>}} placeholders are replaced with the
+	// effective flag and argument values.
+	// [GO-ONLY] Placeholders must name flags and args the command declares; enforced after decode.
+	message: string & =~"^\\s*\\S.*$" & strings.MaxRunes(1024)
+
+	// default preselects the answer (optional, default: false)
+	default?: bool
+})
+
+// CommandLock keeps overlapping runs of a command from executing at the same time.
+#CommandLock: close({
+	// name identifies the lock (optional, default: the command name)
+	// Commands that declare the same lock name in the same scope exclude each other.
+	// [GO-ONLY] Control characters are rejected after decode.
+	name?: string & =~"^\\s*\\S.*$" & strings.MaxRunes(256)
+
+	// scope selects which runs share the lock (optional, default: "project")
+	// "project" locks runs of the same invowkfile directory; "user" locks all
+	// runs of the current user.
+	scope?: #LockScope
+
+	// wait makes a run wait for the lock instead of failing (optional, default: false)
+	wait?: bool
+
+	// timeout limits how long a run waits for the lock (optional, default: no limit)
+	// [GO-ONLY] Requires wait: true; enforced after decode.
+	timeout?: #DurationString
+})
+
+// MatrixCombination sets one value per matrix variable.
+#MatrixCombination: close({[=~"^[a-zA-Z][a-zA-Z0-9_]*$"]: string & strings.MaxRunes(4096)})
+
+// CommandMatrix runs a command once per combination of variable values.
+// [GO-ONLY] At least one of vars or include is required, include and exclude
+// entries must set at least one variable, and exclude entries may only name
+// variables declared in vars; enforced after decode.
+#CommandMatrix: close({
+	// vars maps each variable to the values it takes (optional)
+	// Every combination of values runs once; scripts read the value of a variable
+	// from INVOWK_MATRIX_<NAME>, with the name upper-cased.
+	vars?: [=~"^[a-zA-Z][a-zA-Z0-9_]*$"]: [...string & strings.MaxRunes(4096)] & [_, ...]
+
+	// include lists extra combinations to run (optional)
+	include?: [...#MatrixCombination]
+
+	// exclude lists partial combinations to skip (optional)
+	// A combination is skipped when it has every value of an entry.
+	exclude?: [...#MatrixCombination]
+
+	// parallel runs the combinations at the same time (optional, default: false)
+	// At most execution.max_parallel combinations run at once, and every output
+	// line is prefixed with its combination.
+	parallel?: bool
+})
+
+// CommandOutput declares a named value the command's script writes to the
+// file named by INVOWK_OUTPUT_FILE, one name=value line per output (or
+// name<<DELIMITER ... DELIMITER for multi-line values).
+#CommandOutput: close({
+	// name identifies the output (required)
+	// Commands running after this one in the same invocation read the value from
+	// INVOWK_DEP_<CMD>_<NAME>, with the names upper-cased.
+	name: =~"^[a-zA-Z][a-zA-Z0-9_]*$"
+
+	// description explains the value (optional)
+	description?: string & =~"^\\s*\\S.*$" & strings.MaxRunes(10240)
 })
 
 // Command represents a single executable command
@@ -536,6 +1073,43 @@ import "strings"
 	// Can include spaces for subcommand-like behavior (e.g., "test unit")
 	name: string & =~"^[a-zA-Z][a-zA-Z0-9_ -]*$" & strings.MaxRunes(256)
 
+	// extends deep-merges a template into this command (optional)
+	// Fields declared here win; nested structs are merged field by field, and
+	// lists and scalars are replaced whole.
+	// [GO-ONLY] The template must exist; resolved while parsing.
+	extends?: #TemplateRef
+
+	// aliases lists alternative names the command can be invoked by (optional)
+	// Aliases follow the same rules as name (e.g., "b" or "ci build").
+	// [GO-ONLY] Aliases must not collide with command names or other aliases in
+	// the same invowkfile; enforced after decode.
+	aliases?: [...string & =~"^[a-zA-Z][a-zA-Z0-9_ -]*$" & strings.MaxRunes(256)] & [_, ...]
+
+	// deprecated marks the command as deprecated (optional)
+	// Invoking a deprecated command prints a warning, and optionally forwards to
+	// the replacement command.
+	deprecated?: #Deprecation
+
+	// confirm asks for an interactive yes before the command runs (optional)
+	// The question is asked before any dependency check or execution. --ivk-yes
+	// answers it in automation; without a terminal the command fails instead.
+	confirm?: #ConfirmConfig
+
+	// lock keeps overlapping runs of the command from executing at the same time (optional)
+	// The lock is taken after the confirmation question and held until the
+	// command, its prerequisites, and its hooks have finished.
+	lock?: #CommandLock
+
+	// matrix runs the command once per combination of variable values (optional)
+	// Prerequisites run once before the first combination; each combination runs
+	// with its own hooks, and a pass/fail summary follows the last one.
+	matrix?: #CommandMatrix
+
+	// outputs declares the named values the script writes to INVOWK_OUTPUT_FILE (optional)
+	// [GO-ONLY] Output names must be unique, and commands with steps or a matrix
+	// cannot declare outputs; enforced after decode.
+	outputs?: [...#CommandOutput] & [_, ...]
+
 	// description provides help text for the command (optional)
 	// When declared, description must be non-empty (cannot be "" or whitespace-only)
 	description?: string & =~"^\\s*\\S.*$" & strings.MaxRunes(10240)
@@ -545,11 +1119,26 @@ import "strings"
 	// Examples: "build", "test", "deploy", "utilities"
 	category?: string & =~"^\\s*\\S.*$" & strings.MaxRunes(256)
 
-	// implementations defines the executable implementations with platform/runtime constraints (required, at least one)
+	// visibility controls who can see and run this command (optional, default: "public")
+	// "private" commands are omitted from listings and can only be reached from
+	// commands of the same module: through depends_on.cmds, cmd steps, or nested
+	// invowk invocations from their scripts. "hidden" commands are runnable by
+	// anyone but omitted from listings, help, and completion.
+	visibility?: #CommandVisibility
+
+	// implementations defines the executable implementations with platform/runtime constraints
 	// Each implementation specifies which platforms and runtimes it supports
 	// The first implementation for a given platform determines the default runtime for that platform
 	// There cannot be duplicate combinations of platform+runtime across implementations
-	implementations: [...#Implementation] & [_, ...]
+	// [GO-ONLY] Exactly one of implementations or steps is required; enforced after decode.
+	implementations?: [...#Implementation] & [_, ...]
+
+	// steps defines a sequential multi-step pipeline (optional)
+	// Steps run in declaration order and stop at the first failure unless the
+	// failing step sets continue_on_error. Each step invokes another command or
+	// runs an inline script.
+	// [GO-ONLY] Exactly one of implementations or steps is required; enforced after decode.
+	steps?: [...#CommandStep] & [_, ...]
 
 	// env contains environment configuration for this command (optional)
 	// Environment from files is loaded first, then vars override.
@@ -572,6 +1161,12 @@ import "strings"
 	// Reserved built-in flags: help (h), version.
 	flags?: [...#Flag]
 
+	// flag_groups declares constraints between the flags above (optional)
+	// Violations are reported before the command runs.
+	// [GO-ONLY] At least one group is required, and two required flags cannot be
+	// mutually exclusive; enforced after decode.
+	flag_groups?: #FlagGroups
+
 	// args specifies positional arguments for this command (optional)
 	// Arguments are passed to the script as environment variables:
 	//   - INVOWK_ARG_<NAME>: the argument value
@@ -587,6 +1182,23 @@ import "strings"
 	// and control exactly which files are watched. When --ivk-watch is passed without
 	// any watch config, the CLI falls back to watching all files (**/*) in the working directory.
 	watch?: #WatchConfig
+
+	// sources lists glob patterns for the input files of this command (optional)
+	// When declared, the command runs incrementally: it is skipped while the
+	// content of every matched source (and generated output) is unchanged since
+	// its last successful run with the same runtime and environment.
+	// Paths are relative to the effective working directory. --ivk-force always runs.
+	// [GO-ONLY] Not allowed on commands that declare steps; enforced after decode.
+	sources?: [...#GlobPattern] & [_, ...]
+
+	// generates lists glob patterns for the output files of this command (optional)
+	// A command whose outputs are missing or were modified since its last run is stale.
+	// [GO-ONLY] Requires sources on the command or implementation; enforced after decode.
+	generates?: [...#GlobPattern] & [_, ...]
+
+	// hooks declares lifecycle scripts that run around this command (optional)
+	// Command-level hooks run after root-level hooks within each phase.
+	hooks?: #Hooks
 })
 
 // Invowkfile is the root schema for command definitions (invowkfile.cue)
@@ -616,8 +1228,27 @@ import "strings"
 	// To validate dependencies inside the runtime environment, use depends_on inside the runtime block.
 	depends_on?: #DependsOn
 
-	// cmds defines the available commands (required, at least one)
-	cmds: [...#Command] & [_, ...]
+	// hooks declares lifecycle scripts that run around every command (optional)
+	// Root-level hooks run before command-level hooks within each phase.
+	hooks?: #Hooks
+
+	// templates declares reusable command and implementation fragments (optional)
+	// Commands and implementations use them with extends. Templates of a module
+	// are exported: invowkfiles next to the module, or modules vendoring it,
+	// extend them with "@module template".
+	templates?: [#TemplateName]: #Template
+
+	// imports loads invowkfile fragments (optional)
+	// Their commands, env, and depends_on are merged with this invowkfile's,
+	// which lets a large invowkfile be split into several files.
+	imports?: [...#ImportPattern] & [_, ...]
+
+	// cmds defines the available commands (required, at least one unless
+	// imports provides them)
+	cmds: [...#Command]
+	if imports == _|_ {
+		cmds: [_, ...]
+	}
 })
 
 // Example usage with the cue command-line tool:
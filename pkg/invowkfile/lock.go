@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// LockScopeProject shares the lock between runs of the same invowkfile
+	// directory (the default).
+	LockScopeProject LockScope = "project"
+	// LockScopeUser shares the lock between all runs of the current user,
+	// whichever invowkfile declares the command.
+	LockScopeUser LockScope = "user"
+
+	// lockNameMaxRunes is the maximum length of a lock name, matching the CUE
+	// constraint strings.MaxRunes(256) on #CommandLock.name.
+	lockNameMaxRunes = 256
+)
+
+var (
+	// ErrInvalidLockScope is returned when a LockScope value is not one of the defined scopes.
+	ErrInvalidLockScope = errors.New("invalid lock scope")
+
+	// ErrInvalidLockName is the sentinel error wrapped by InvalidLockNameError.
+	ErrInvalidLockName = errors.New("invalid lock name")
+
+	// ErrLockTimeoutWithoutWait is returned when a lock declares a timeout
+	// without wait; a run that does not wait has nothing to time out.
+	ErrLockTimeoutWithoutWait = errors.New("lock timeout requires wait: true")
+
+	// ErrInvalidCommandLock is the sentinel error wrapped by InvalidCommandLockError.
+	ErrInvalidCommandLock = errors.New("invalid command lock")
+)
+
+type (
+	// LockScope selects which runs share a command lock.
+	// The zero value ("") means LockScopeProject.
+	//
+	//goplint:enum-cue=#LockScope
+	LockScope string
+
+	// InvalidLockScopeError is returned when a LockScope value is not recognized.
+	// It wraps ErrInvalidLockScope for errors.Is() compatibility.
+	InvalidLockScopeError struct {
+		Value LockScope
+	}
+
+	// LockName identifies a command lock. Commands that declare the same lock
+	// name in the same scope never run at the same time.
+	LockName string
+
+	// InvalidLockNameError is returned when a LockName is whitespace-only,
+	// too long, or contains control characters.
+	InvalidLockNameError struct {
+		Value LockName
+	}
+
+	// InvalidCommandLockError is returned when a CommandLock has invalid
+	// fields. It wraps ErrInvalidCommandLock for errors.Is() compatibility
+	// and collects field-level validation errors.
+	InvalidCommandLockError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// CommandLock keeps overlapping runs of a command from executing at the
+	// same time, across terminals and processes. A run that finds the lock
+	// held either waits for it or fails right away, naming the process that
+	// holds it.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	CommandLock struct {
+		// Name identifies the lock (default: the command name). Commands that
+		// share a name exclude each other.
+		Name LockName `json:"name,omitempty"`
+		// Scope selects which runs share the lock (default: project).
+		Scope LockScope `json:"scope,omitempty"`
+		// Wait makes a run wait for the lock instead of failing (default: false).
+		Wait bool `json:"wait,omitempty"`
+		// Timeout limits how long a run waits for the lock (default: no limit).
+		// Requires Wait.
+		Timeout DurationString `json:"timeout,omitempty"`
+	}
+)
+
+// Error implements the error interface for InvalidLockScopeError.
+func (e *InvalidLockScopeError) Error() string {
+	return fmt.Sprintf("invalid lock scope %q (valid: project, user)", e.Value)
+}
+
+// Unwrap returns the sentinel error for errors.Is() compatibility.
+func (e *InvalidLockScopeError) Unwrap() error { return ErrInvalidLockScope }
+
+// String returns the string representation of the LockScope.
+func (s LockScope) String() string { return string(s) }
+
+// Validate returns nil if the LockScope is one of the defined scopes,
+// or a validation error if it is not.
+//
+//goplint:nonzero
+func (s LockScope) Validate() error {
+	switch s {
+	case LockScopeProject, LockScopeUser:
+		return nil
+	default:
+		return &InvalidLockScopeError{Value: s}
+	}
+}
+
+// Error implements the error interface for InvalidLockNameError.
+func (e *InvalidLockNameError) Error() string {
+	return fmt.Sprintf("invalid lock name %q (must be non-empty, printable, max %d chars)", e.Value, lockNameMaxRunes)
+}
+
+// Unwrap returns ErrInvalidLockName so callers can use errors.Is for programmatic detection.
+func (e *InvalidLockNameError) Unwrap() error { return ErrInvalidLockName }
+
+// String returns the string representation of the LockName.
+func (n LockName) String() string { return string(n) }
+
+// Validate returns nil if the LockName has visible characters, no control
+// characters, and at most 256 runes, or a validation error if it does not.
+//
+//goplint:nonzero
+func (n LockName) Validate() error {
+	s := string(n)
+	if strings.TrimSpace(s) == "" || utf8.RuneCountInString(s) > lockNameMaxRunes || strings.ContainsFunc(s, unicode.IsControl) {
+		return &InvalidLockNameError{Value: n}
+	}
+	return nil
+}
+
+// Validate returns nil if the CommandLock has a valid name, scope, and
+// timeout, or an error collecting all field-level validation failures.
+func (l CommandLock) Validate() error {
+	var errs []error
+	appendOptionalValidation(&errs, l.Name, l.Name != "")
+	appendOptionalValidation(&errs, l.Scope, l.Scope != "")
+	appendFieldError(&errs, l.Timeout.Validate())
+	if l.Timeout != "" && !l.Wait {
+		errs = append(errs, ErrLockTimeoutWithoutWait)
+	}
+	if len(errs) > 0 {
+		return &InvalidCommandLockError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidCommandLockError.
+func (e *InvalidCommandLockError) Error() string {
+	return types.FormatFieldErrors("command lock", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidCommandLock and field errors for errors.Is() compatibility.
+func (e *InvalidCommandLockError) Unwrap() error {
+	return errors.Join(ErrInvalidCommandLock, errors.Join(e.FieldErrors...))
+}
+
+// EffectiveName returns the lock name: the declared name, or the name of the
+// command that declares the lock when none is declared.
+func (l *CommandLock) EffectiveName(cmdName CommandName) LockName {
+	if l.Name != "" {
+		return l.Name
+	}
+	return LockName(cmdName)
+}
+
+// EffectiveScope returns the lock scope, defaulting to LockScopeProject.
+func (l *CommandLock) EffectiveScope() LockScope {
+	if l.Scope != "" {
+		return l.Scope
+	}
+	return LockScopeProject
+}
+
+// EffectiveTimeout returns how long a run waits for the lock. Zero means
+// no limit.
+func (l *CommandLock) EffectiveTimeout() (time.Duration, error) {
+	return parseDuration("timeout", l.Timeout)
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandLockValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		lock    CommandLock
+		wantErr error
+	}{
+		{name: "defaults", lock: CommandLock{}},
+		{name: "all fields", lock: CommandLock{Name: "local db", Scope: LockScopeUser, Wait: true, Timeout: "5m"}},
+		{name: "whitespace name", lock: CommandLock{Name: "  "}, wantErr: ErrInvalidLockName},
+		{name: "control character in name", lock: CommandLock{Name: "db\tmigrate"}, wantErr: ErrInvalidLockName},
+		{name: "too long name", lock: CommandLock{Name: LockName(strings.Repeat("x", lockNameMaxRunes+1))}, wantErr: ErrInvalidLockName},
+		{name: "unknown scope", lock: CommandLock{Scope: "machine"}, wantErr: ErrInvalidLockScope},
+		{name: "invalid timeout", lock: CommandLock{Wait: true, Timeout: "soon"}, wantErr: ErrInvalidDurationString},
+		{name: "timeout without wait", lock: CommandLock{Timeout: "1m"}, wantErr: ErrLockTimeoutWithoutWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.lock.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidCommandLock) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandLockEffectiveValues(t *testing.T) {
+	t.Parallel()
+
+	lock := &CommandLock{}
+	if got := lock.EffectiveName("db migrate"); got != "db migrate" {
+		t.Errorf("EffectiveName() = %q, want the command name", got)
+	}
+	if got := lock.EffectiveScope(); got != LockScopeProject {
+		t.Errorf("EffectiveScope() = %q, want %q", got, LockScopeProject)
+	}
+	if got, err := lock.EffectiveTimeout(); err != nil || got != 0 {
+		t.Errorf("EffectiveTimeout() = %v, %v, want 0 (no limit)", got, err)
+	}
+
+	lock = &CommandLock{Name: "local-db", Scope: LockScopeUser, Wait: true, Timeout: "90s"}
+	if got := lock.EffectiveName("db migrate"); got != "local-db" {
+		t.Errorf("EffectiveName() = %q, want %q", got, "local-db")
+	}
+	if got := lock.EffectiveScope(); got != LockScopeUser {
+		t.Errorf("EffectiveScope() = %q, want %q", got, LockScopeUser)
+	}
+	if got, err := lock.EffectiveTimeout(); err != nil || got != 90*time.Second {
+		t.Errorf("EffectiveTimeout() = %v, %v, want 90s", got, err)
+	}
+}
+
+func TestParseCommandLock(t *testing.T) {
+	t.Parallel()
+
+	source := `cmds: [{
+	name: "db migrate"
+	lock: {scope: "user", timeout: "1m"}
+	implementations: [{script: {content: "make migrate"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]
+}]`
+	if _, err := ParseBytes([]byte(source), "invowkfile.cue"); !errors.Is(err, ErrLockTimeoutWithoutWait) {
+		t.Fatalf("ParseBytes() error = %v, want ErrLockTimeoutWithoutWait", err)
+	}
+
+	source = strings.Replace(source, `scope: "user"`, `scope: "machine"`, 1)
+	if _, err := ParseBytes([]byte(source), "invowkfile.cue"); err == nil {
+		t.Fatal("ParseBytes() error = nil, want schema error for unknown scope")
+	}
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+// MatrixEnvVarPrefix prefixes the environment variables that carry the values
+// of a matrix combination to the command scripts, e.g. INVOWK_MATRIX_GO.
+const MatrixEnvVarPrefix = "INVOWK_MATRIX_"
+
+var (
+	// ErrInvalidMatrixVarName is the sentinel error wrapped by InvalidMatrixVarNameError.
+	ErrInvalidMatrixVarName = errors.New("invalid matrix variable name")
+
+	// ErrEmptyMatrix is returned when a matrix declares neither vars nor
+	// include entries, so it expands into no combination.
+	ErrEmptyMatrix = errors.New("matrix declares no combinations: vars or include is required")
+
+	// ErrEmptyMatrixValues is returned when a matrix variable lists no values.
+	ErrEmptyMatrixValues = errors.New("matrix variable lists no values")
+
+	// ErrEmptyMatrixEntry is returned when an include or exclude entry sets no variable.
+	ErrEmptyMatrixEntry = errors.New("matrix include/exclude entry sets no variable")
+
+	// ErrUnknownMatrixExcludeVar is returned when an exclude entry names a
+	// variable that vars does not declare; such an entry excludes nothing.
+	ErrUnknownMatrixExcludeVar = errors.New("matrix exclude entry names an undeclared variable")
+
+	// ErrInvalidCommandMatrix is the sentinel error wrapped by InvalidCommandMatrixError.
+	ErrInvalidCommandMatrix = errors.New("invalid command matrix")
+
+	matrixVarNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+)
+
+type (
+	// MatrixVarName names a matrix variable. Format: a letter followed by
+	// letters, digits, or underscores. Scripts read the value of the variable
+	// from INVOWK_MATRIX_<NAME>, with the name upper-cased.
+	MatrixVarName string
+
+	// InvalidMatrixVarNameError is returned when a MatrixVarName does not
+	// match the variable name format.
+	InvalidMatrixVarNameError struct {
+		Value MatrixVarName
+	}
+
+	// MatrixCombination is one set of matrix variable values: a run of a
+	// matrix command, or an include/exclude entry.
+	MatrixCombination map[MatrixVarName]string
+
+	// InvalidCommandMatrixError is returned when a CommandMatrix has invalid
+	// fields. It wraps ErrInvalidCommandMatrix for errors.Is() compatibility
+	// and collects field-level validation errors.
+	InvalidCommandMatrixError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// CommandMatrix runs a command once per combination of variable values:
+	// the cartesian product of Vars, minus the combinations matched by an
+	// Exclude entry, plus the Include entries.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	CommandMatrix struct {
+		// Vars maps each variable to the values it takes (optional).
+		Vars map[MatrixVarName][]string `json:"vars,omitempty"`
+		// Include lists extra combinations to run (optional).
+		Include []MatrixCombination `json:"include,omitempty"`
+		// Exclude lists partial combinations to skip: a combination is skipped
+		// when it has every value of an entry (optional).
+		Exclude []MatrixCombination `json:"exclude,omitempty"`
+		// Parallel runs the combinations at the same time, bounded by
+		// execution.max_parallel, with each output line prefixed by its
+		// combination (default: false).
+		Parallel bool `json:"parallel,omitempty"`
+	}
+)
+
+// Error implements the error interface for InvalidMatrixVarNameError.
+func (e *InvalidMatrixVarNameError) Error() string {
+	return fmt.Sprintf("invalid matrix variable name %q (must start with a letter, followed by letters, digits, or underscores)", e.Value)
+}
+
+// Unwrap returns ErrInvalidMatrixVarName so callers can use errors.Is for programmatic detection.
+func (e *InvalidMatrixVarNameError) Unwrap() error { return ErrInvalidMatrixVarName }
+
+// String returns the string representation of the MatrixVarName.
+func (n MatrixVarName) String() string { return string(n) }
+
+// Validate returns nil if the MatrixVarName matches the variable name format,
+// or a validation error if it does not.
+//
+//goplint:nonzero
+func (n MatrixVarName) Validate() error {
+	if !matrixVarNamePattern.MatchString(string(n)) {
+		return &InvalidMatrixVarNameError{Value: n}
+	}
+	return nil
+}
+
+// EnvVar returns the environment variable that carries the variable's value.
+// Example: "go" -> "INVOWK_MATRIX_GO"
+func (n MatrixVarName) EnvVar() string {
+	return MatrixEnvVarPrefix + strings.ToUpper(string(n))
+}
+
+// Validate returns nil if the combination sets at least one variable and
+// every variable name is valid.
+func (c MatrixCombination) Validate() error {
+	if len(c) == 0 {
+		return ErrEmptyMatrixEntry
+	}
+	var errs []error
+	for _, name := range c.names() {
+		appendFieldError(&errs, name.Validate())
+	}
+	return errors.Join(errs...)
+}
+
+// Label names the combination for output prefixes and summaries, e.g.
+// "db=postgres, go=1.22". Variables are listed in name order.
+func (c MatrixCombination) Label() string {
+	parts := make([]string, 0, len(c))
+	for _, name := range c.names() {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, c[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Matches reports whether c has every value set by entry.
+func (c MatrixCombination) Matches(entry MatrixCombination) bool {
+	for name, value := range entry {
+		if got, ok := c[name]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c MatrixCombination) names() []MatrixVarName {
+	return slices.Sorted(maps.Keys(c))
+}
+
+// Validate returns nil if the CommandMatrix declares at least one
+// combination and has valid variables and entries, or an error collecting
+// all field-level validation failures.
+func (m CommandMatrix) Validate() error {
+	var errs []error
+	if len(m.Vars) == 0 && len(m.Include) == 0 {
+		errs = append(errs, ErrEmptyMatrix)
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Vars)) {
+		appendFieldError(&errs, name.Validate())
+		if len(m.Vars[name]) == 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrEmptyMatrixValues, name))
+		}
+	}
+	appendEachValidation(&errs, m.Include)
+	appendEachValidation(&errs, m.Exclude)
+	for _, entry := range m.Exclude {
+		for _, name := range entry.names() {
+			if _, declared := m.Vars[name]; !declared {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownMatrixExcludeVar, name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &InvalidCommandMatrixError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidCommandMatrixError.
+func (e *InvalidCommandMatrixError) Error() string {
+	return types.FormatFieldErrors("command matrix", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidCommandMatrix and field errors for errors.Is() compatibility.
+func (e *InvalidCommandMatrixError) Unwrap() error {
+	return errors.Join(ErrInvalidCommandMatrix, errors.Join(e.FieldErrors...))
+}
+
+// Combinations expands the matrix into the combinations to run, in a stable
+// order: the cartesian product of Vars (variables in name order, values in
+// declaration order, the last variable varying fastest) without the
+// combinations matched by an Exclude entry, followed by the Include entries
+// that the product does not already contain.
+func (m *CommandMatrix) Combinations() []MatrixCombination {
+	var combinations []MatrixCombination
+	if len(m.Vars) > 0 {
+		combinations = []MatrixCombination{{}}
+		for _, name := range slices.Sorted(maps.Keys(m.Vars)) {
+			next := make([]MatrixCombination, 0, len(combinations)*len(m.Vars[name]))
+			for _, combination := range combinations {
+				for _, value := range m.Vars[name] {
+					extended := maps.Clone(combination)
+					extended[name] = value
+					next = append(next, extended)
+				}
+			}
+			combinations = next
+		}
+		combinations = slices.DeleteFunc(combinations, func(combination MatrixCombination) bool {
+			return slices.ContainsFunc(m.Exclude, combination.Matches)
+		})
+	}
+	for _, entry := range m.Include {
+		if !slices.ContainsFunc(combinations, func(combination MatrixCombination) bool { return maps.Equal(combination, entry) }) {
+			combinations = append(combinations, maps.Clone(entry))
+		}
+	}
+	return combinations
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommandMatrixValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		matrix  CommandMatrix
+		wantErr error
+	}{
+		{name: "vars", matrix: CommandMatrix{Vars: map[MatrixVarName][]string{"go": {"1.22"}}}},
+		{name: "include only", matrix: CommandMatrix{Include: []MatrixCombination{{"os": "linux"}}}},
+		{name: "empty", matrix: CommandMatrix{Parallel: true}, wantErr: ErrEmptyMatrix},
+		{name: "invalid name", matrix: CommandMatrix{Vars: map[MatrixVarName][]string{"go-version": {"1.22"}}}, wantErr: ErrInvalidMatrixVarName},
+		{name: "no values", matrix: CommandMatrix{Vars: map[MatrixVarName][]string{"go": {}}}, wantErr: ErrEmptyMatrixValues},
+		{
+			name:    "empty include entry",
+			matrix:  CommandMatrix{Vars: map[MatrixVarName][]string{"go": {"1.22"}}, Include: []MatrixCombination{{}}},
+			wantErr: ErrEmptyMatrixEntry,
+		},
+		{
+			name:    "invalid include name",
+			matrix:  CommandMatrix{Include: []MatrixCombination{{"1os": "linux"}}},
+			wantErr: ErrInvalidMatrixVarName,
+		},
+		{
+			name:    "undeclared exclude variable",
+			matrix:  CommandMatrix{Vars: map[MatrixVarName][]string{"go": {"1.22"}}, Exclude: []MatrixCombination{{"db": "mysql"}}},
+			wantErr: ErrUnknownMatrixExcludeVar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.matrix.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidCommandMatrix) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandMatrixCombinations(t *testing.T) {
+	t.Parallel()
+
+	matrix := &CommandMatrix{
+		Vars: map[MatrixVarName][]string{"go": {"1.22", "1.23"}, "db": {"postgres", "mysql"}},
+		Exclude: []MatrixCombination{
+			{"go": "1.22", "db": "mysql"},
+		},
+		Include: []MatrixCombination{
+			{"go": "1.23", "db": "postgres"}, // Already in the product.
+			{"go": "1.24", "db": "sqlite"},
+		},
+	}
+
+	got := make([]string, 0, 4)
+	for _, combination := range matrix.Combinations() {
+		got = append(got, combination.Label())
+	}
+	want := []string{
+		"db=postgres, go=1.22",
+		"db=postgres, go=1.23",
+		"db=mysql, go=1.23",
+		"db=sqlite, go=1.24",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Combinations() = %q, want %q", got, want)
+	}
+
+	includeOnly := &CommandMatrix{Include: []MatrixCombination{{"os": "linux"}, {"os": "macos"}}}
+	if got := includeOnly.Combinations(); len(got) != 2 || got[1]["os"] != "macos" {
+		t.Errorf("Combinations() of include-only matrix = %v, want the include entries", got)
+	}
+}
+
+func TestMatrixVarNameEnvVar(t *testing.T) {
+	t.Parallel()
+
+	if got := MatrixVarName("go_version").EnvVar(); got != "INVOWK_MATRIX_GO_VERSION" {
+		t.Errorf("EnvVar() = %q, want %q", got, "INVOWK_MATRIX_GO_VERSION")
+	}
+}
+
+func TestParseCommandMatrix(t *testing.T) {
+	t.Parallel()
+
+	source := `cmds: [{
+	name: "test"
+	matrix: {vars: {go: ["1.22"]}, exclude: [{db: "mysql"}]}
+	implementations: [{script: {content: "go test ./..."}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]
+}]`
+	if _, err := ParseBytes([]byte(source), "invowkfile.cue"); !errors.Is(err, ErrUnknownMatrixExcludeVar) {
+		t.Fatalf("ParseBytes() error = %v, want ErrUnknownMatrixExcludeVar", err)
+	}
+
+	source = strings.Replace(source, `go: ["1.22"]`, `go: []`, 1)
+	if _, err := ParseBytes([]byte(source), "invowkfile.cue"); err == nil {
+		t.Fatal("ParseBytes() error = nil, want schema error for a variable without values")
+	}
+}
@@ -129,7 +129,7 @@ func TestParseBytesRejectsInvalidModulePath(t *testing.T) {
 		}},
 	}))
 
-	_, err := parseBytes(data, "invowkfile.cue", FilesystemPath(" \t "), nil)
+	_, err := parseBytes(data, "invowkfile.cue", FilesystemPath(" \t "), nil, parseOptions{})
 	if !errors.Is(err, ErrInvalidFilesystemPath) {
 		t.Fatalf("parseBytes() error = %v, want ErrInvalidFilesystemPath", err)
 	}
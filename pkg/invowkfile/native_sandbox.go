@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// SandboxNetworkNone runs sandboxed scripts in a new network namespace with only loopback.
+	SandboxNetworkNone SandboxNetwork = "none"
+	// SandboxNetworkHost lets sandboxed scripts use the host network.
+	SandboxNetworkHost SandboxNetwork = "host"
+)
+
+var (
+	// ErrInvalidSandboxNetwork is the sentinel error wrapped by InvalidSandboxNetworkError.
+	ErrInvalidSandboxNetwork = errors.New("invalid sandbox network")
+	// ErrInvalidRuntimeSandboxConfig is the sentinel error wrapped by InvalidRuntimeSandboxConfigError.
+	ErrInvalidRuntimeSandboxConfig = errors.New("invalid runtime sandbox config")
+)
+
+type (
+	// SandboxNetwork controls network access for sandboxed native scripts.
+	//
+	//goplint:enum-cue=#SandboxNetwork
+	SandboxNetwork string
+
+	// InvalidSandboxNetworkError is returned when SandboxNetwork is not recognized.
+	InvalidSandboxNetworkError struct {
+		Value SandboxNetwork
+	}
+
+	// InvalidRuntimeSandboxConfigError is returned when a RuntimeSandboxConfig has invalid fields.
+	// It wraps ErrInvalidRuntimeSandboxConfig for errors.Is() compatibility.
+	InvalidRuntimeSandboxConfigError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// RuntimeSandboxConfig isolates a native runtime script with Linux namespaces
+	// and Landlock. Writable paths come from the platform virtual.filesystem config.
+	RuntimeSandboxConfig struct {
+		// Network controls network access. The zero value means none.
+		Network SandboxNetwork `json:"network,omitempty"`
+	}
+)
+
+// Error implements the error interface for InvalidSandboxNetworkError.
+func (e *InvalidSandboxNetworkError) Error() string {
+	return fmt.Sprintf("invalid sandbox network %q (valid: none, host)", e.Value)
+}
+
+// Unwrap returns ErrInvalidSandboxNetwork for errors.Is() compatibility.
+func (e *InvalidSandboxNetworkError) Unwrap() error { return ErrInvalidSandboxNetwork }
+
+// String returns the string representation of the SandboxNetwork.
+func (n SandboxNetwork) String() string { return string(n.Effective()) }
+
+// Effective returns the configured network mode, defaulting the zero value to none.
+func (n SandboxNetwork) Effective() SandboxNetwork {
+	if n == "" {
+		return SandboxNetworkNone
+	}
+	return n
+}
+
+// Validate returns nil if the SandboxNetwork is recognized. The zero value
+// is valid and means none.
+func (n SandboxNetwork) Validate() error {
+	switch n {
+	case "", SandboxNetworkNone, SandboxNetworkHost:
+		return nil
+	default:
+		return &InvalidSandboxNetworkError{Value: n}
+	}
+}
+
+// Validate returns nil if the RuntimeSandboxConfig has valid fields.
+func (c RuntimeSandboxConfig) Validate() error {
+	var errs []error
+	appendOptionalValidation(&errs, c.Network, c.Network != "")
+	if len(errs) > 0 {
+		return &InvalidRuntimeSandboxConfigError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidRuntimeSandboxConfigError.
+func (e *InvalidRuntimeSandboxConfigError) Error() string {
+	return types.FormatFieldErrors("runtime sandbox config", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidRuntimeSandboxConfig for errors.Is() compatibility.
+func (e *InvalidRuntimeSandboxConfigError) Unwrap() error {
+	return errors.Join(ErrInvalidRuntimeSandboxConfig, errors.Join(e.FieldErrors...))
+}
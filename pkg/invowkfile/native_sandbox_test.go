@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSandboxNetwork_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		network SandboxNetwork
+		want    bool
+	}{
+		{"", true},
+		{SandboxNetworkNone, true},
+		{SandboxNetworkHost, true},
+		{"bridge", false},
+		{"NONE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.network), func(t *testing.T) {
+			t.Parallel()
+			err := tt.network.Validate()
+			if (err == nil) != tt.want {
+				t.Fatalf("SandboxNetwork(%q).Validate() error = %v, want valid=%v", tt.network, err, tt.want)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSandboxNetwork) {
+				t.Errorf("error should wrap ErrInvalidSandboxNetwork, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRuntimeSandboxConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	if err := (RuntimeSandboxConfig{}).Validate(); err != nil {
+		t.Errorf("zero config Validate() error = %v, want nil", err)
+	}
+	if got := (RuntimeSandboxConfig{}).Network.String(); got != "none" {
+		t.Errorf("default network = %q, want none", got)
+	}
+	err := RuntimeSandboxConfig{Network: "bridge"}.Validate()
+	if !errors.Is(err, ErrInvalidRuntimeSandboxConfig) || !errors.Is(err, ErrInvalidSandboxNetwork) {
+		t.Errorf("Validate() error = %v, want ErrInvalidRuntimeSandboxConfig wrapping ErrInvalidSandboxNetwork", err)
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DependencyOutputEnvVarPrefix prefixes the environment variables that carry
+// the outputs of commands that ran earlier in the same invocation, e.g.
+// INVOWK_DEP_BUILD_VERSION for the "version" output of "build".
+const DependencyOutputEnvVarPrefix = "INVOWK_DEP_"
+
+var (
+	// ErrInvalidOutputName is the sentinel error wrapped by InvalidOutputNameError.
+	ErrInvalidOutputName = errors.New("invalid output name")
+
+	// ErrDuplicateOutputName is returned when a command declares the same output twice.
+	ErrDuplicateOutputName = errors.New("duplicate output name")
+
+	// ErrStepCommandOutputs is returned when a command with steps declares
+	// outputs; only commands that run a script write an output file.
+	ErrStepCommandOutputs = errors.New("commands with steps must not declare outputs")
+
+	// ErrMatrixCommandOutputs is returned when a matrix command declares
+	// outputs, which its combinations would overwrite.
+	ErrMatrixCommandOutputs = errors.New("matrix commands must not declare outputs")
+
+	// ErrUndeclaredOutput is returned when a script writes an output its
+	// command does not declare.
+	ErrUndeclaredOutput = errors.New("output is not declared by the command")
+
+	outputNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+)
+
+type (
+	// OutputName names a value a command writes to its output file. Format: a
+	// letter followed by letters, digits, or underscores.
+	OutputName string
+
+	// InvalidOutputNameError is returned when an OutputName does not match the
+	// output name format.
+	InvalidOutputNameError struct {
+		Value OutputName
+	}
+
+	//goplint:validate-all
+	//
+	// CommandOutput declares a named value the command's script writes to the
+	// file named by INVOWK_OUTPUT_FILE.
+	CommandOutput struct {
+		// Name identifies the output (required).
+		Name OutputName `json:"name"`
+		// Description explains the value (optional).
+		Description DescriptionText `json:"description,omitempty"`
+	}
+
+	// CommandOutputs holds the outputs written by one run of a command.
+	CommandOutputs map[OutputName]string
+)
+
+// Error implements the error interface for InvalidOutputNameError.
+func (e *InvalidOutputNameError) Error() string {
+	return fmt.Sprintf("invalid output name %q (must start with a letter, followed by letters, digits, or underscores)", e.Value)
+}
+
+// Unwrap returns ErrInvalidOutputName so callers can use errors.Is for programmatic detection.
+func (e *InvalidOutputNameError) Unwrap() error { return ErrInvalidOutputName }
+
+// String returns the string representation of the OutputName.
+func (n OutputName) String() string { return string(n) }
+
+// Validate returns nil if the OutputName matches the output name format,
+// or a validation error if it does not.
+//
+//goplint:nonzero
+func (n OutputName) Validate() error {
+	if !outputNamePattern.MatchString(string(n)) {
+		return &InvalidOutputNameError{Value: n}
+	}
+	return nil
+}
+
+// DependencyEnvVar returns the environment variable that carries the output
+// of producer to the commands running after it.
+// Example: ("build api", "version") -> "INVOWK_DEP_BUILD_API_VERSION"
+func (n OutputName) DependencyEnvVar(producer CommandName) string {
+	cmd := strings.NewReplacer(" ", "_", "-", "_").Replace(string(producer))
+	return DependencyOutputEnvVarPrefix + strings.ToUpper(cmd) + "_" + strings.ToUpper(string(n))
+}
+
+// Validate returns nil if the CommandOutput has a valid name and description.
+func (o CommandOutput) Validate() error {
+	var errs []error
+	appendFieldError(&errs, o.Name.Validate())
+	appendOptionalValidation(&errs, o.Description, o.Description != "")
+	return errors.Join(errs...)
+}
+
+// Validate returns nil if every output name is valid.
+func (o CommandOutputs) Validate() error {
+	var errs []error
+	for name := range o {
+		appendFieldError(&errs, name.Validate())
+	}
+	return errors.Join(errs...)
+}
+
+// DependencyEnv returns the outputs as the INVOWK_DEP_<CMD>_<NAME> environment
+// variables seen by the commands running after producer.
+func (o CommandOutputs) DependencyEnv(producer CommandName) map[string]string {
+	env := make(map[string]string, len(o))
+	for name, value := range o {
+		env[name.DependencyEnvVar(producer)] = value
+	}
+	return env
+}
+
+// DeclaresOutput reports whether the command declares an output named name.
+func (c *Command) DeclaresOutput(name OutputName) bool {
+	for i := range c.Outputs {
+		if c.Outputs[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOutputNameDependencyEnvVar(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		producer CommandName
+		name     OutputName
+		want     string
+	}{
+		{"build", "version", "INVOWK_DEP_BUILD_VERSION"},
+		{"build api", "image_digest", "INVOWK_DEP_BUILD_API_IMAGE_DIGEST"},
+		{"release-notes", "Path", "INVOWK_DEP_RELEASE_NOTES_PATH"},
+	}
+	for _, tt := range tests {
+		if got := tt.name.DependencyEnvVar(tt.producer); got != tt.want {
+			t.Errorf("OutputName(%q).DependencyEnvVar(%q) = %q, want %q", tt.name, tt.producer, got, tt.want)
+		}
+	}
+}
+
+func TestOutputNameValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []OutputName{"version", "image_digest", "V2"} {
+		if err := name.Validate(); err != nil {
+			t.Errorf("OutputName(%q).Validate() error = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []OutputName{"", "2fast", "image-digest", "has space"} {
+		if err := name.Validate(); !errors.Is(err, ErrInvalidOutputName) {
+			t.Errorf("OutputName(%q).Validate() error = %v, want ErrInvalidOutputName", name, err)
+		}
+	}
+}
+
+func TestParseCommandOutputs(t *testing.T) {
+	t.Parallel()
+
+	const impl = `implementations: [{script: {content: "echo"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]`
+	tests := []struct {
+		name    string
+		command string
+		wantErr error
+	}{
+		{name: "duplicate", command: `outputs: [{name: "version"}, {name: "version"}]
+	` + impl, wantErr: ErrDuplicateOutputName},
+		{name: "matrix", command: `outputs: [{name: "version"}]
+	matrix: {vars: {go: ["1.22"]}}
+	` + impl, wantErr: ErrMatrixCommandOutputs},
+		{name: "steps", command: `outputs: [{name: "version"}]
+	steps: [{script: {content: "echo"}}]`, wantErr: ErrStepCommandOutputs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := "cmds: [{\n\tname: \"build\"\n\t" + tt.command + "\n}]"
+			if _, err := ParseBytes([]byte(source), "invowkfile.cue"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseBytes() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
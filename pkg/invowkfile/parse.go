@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"cuelang.org/go/cue/ast"
+
 	"github.com/invowk/invowk/pkg/cueutil"
 	"github.com/invowk/invowk/pkg/invowkmod"
 )
@@ -37,34 +39,35 @@ func SchemaCUE() string {
 }
 
 // Parse reads and parses an invowkfile from the given path.
-func Parse(path FilesystemPath) (*Invowkfile, error) {
+func Parse(path FilesystemPath, opts ...ParseOption) (*Invowkfile, error) {
 	pathStr := string(path)
 	data, err := os.ReadFile(pathStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read invowkfile at %s: %w", path, err)
 	}
 
-	return ParseBytes(data, pathStr)
+	return ParseBytes(data, pathStr, opts...)
 }
 
 // ParseBytes parses invowkfile content from bytes.
-// Uses cueutil.ParseAndDecodeString for the 3-step CUE parsing flow:
+// Uses cueutil.ParseAndDecode for the 3-step CUE parsing flow:
 // compile schema → compile user data → validate and decode.
-func ParseBytes(data []byte, path string) (*Invowkfile, error) {
-	return parseBytes(data, path, "", nil)
+// Templates are expanded into the commands and implementations extending
+// them before the user data is unified with the schema. Fragments named by
+// imports are read relative to the directory of path and merged afterwards.
+func ParseBytes(data []byte, path string, opts ...ParseOption) (*Invowkfile, error) {
+	return parseBytes(data, path, "", nil, newParseOptions(opts))
 }
 
 //goplint:ignore -- internal CUE parser boundary reuses public ParseBytes raw bytes and filename.
-func parseBytes(data []byte, path string, modulePath FilesystemPath, metadata *ModuleMetadata) (*Invowkfile, error) {
-	if errs := runtimeSchemaPreflightValidationErrors(data, path); len(errs) > 0 {
-		return nil, errs
-	}
-
+func parseBytes(data []byte, path string, modulePath FilesystemPath, metadata *ModuleMetadata, options parseOptions) (*Invowkfile, error) {
+	templates := newTemplateExpander(templateDependencyDir(path, modulePath), options.templateModules)
 	result, err := cueutil.ParseAndDecode[Invowkfile](
 		invowkfileSchemaBytes,
 		data,
 		"#Invowkfile",
 		cueutil.WithFilename(path),
+		cueutil.WithASTTransform(schemaTransform(templates)),
 	)
 	if err != nil {
 		return nil, err
@@ -84,6 +87,15 @@ func parseBytes(data []byte, path string, modulePath FilesystemPath, metadata *M
 		inv.Metadata = metadata
 	}
 
+	if len(inv.Imports) > 0 {
+		if modulePath != "" {
+			return nil, fmt.Errorf("module at %s: %w", modulePath, ErrImportsInModule)
+		}
+		if err := inv.importFragments(templates); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate and collect all errors
 	if errs := inv.Validate(); len(errs) > 0 {
 		// Return ValidationErrors which implements error interface
@@ -93,6 +105,21 @@ func parseBytes(data []byte, path string, modulePath FilesystemPath, metadata *M
 	return inv, nil
 }
 
+// schemaTransform returns the AST transform applied to invowkfiles and their
+// imported fragments before schema validation: templates are expanded, then
+// runtime configs are checked for schema preflight errors.
+func schemaTransform(templates *templateExpander) func(*ast.File) error {
+	return func(file *ast.File) error {
+		if errs := templates.expand(file); len(errs) > 0 {
+			return errs
+		}
+		if errs := runtimeSchemaPreflightFileErrors(file); len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+}
+
 // ParseInvowkmod reads and parses module metadata from invowkmod.cue at the given path.
 // This is a wrapper for invowkmod.ParseInvowkmod.
 func ParseInvowkmod(path FilesystemPath) (*Invowkmod, error) {
@@ -136,7 +163,7 @@ func ParseModule(modulePath FilesystemPath) (*Module, error) {
 
 // ParseLoadedModuleInvowkfile parses a command-bearing invowkfile.cue for an
 // already loaded module and attaches the module's validated metadata and path.
-func ParseLoadedModuleInvowkfile(module *Module) (*Invowkfile, error) {
+func ParseLoadedModuleInvowkfile(module *Module, opts ...ParseOption) (*Invowkfile, error) {
 	if module == nil {
 		return nil, errors.New("module is nil")
 	}
@@ -153,7 +180,7 @@ func ParseLoadedModuleInvowkfile(module *Module) (*Invowkfile, error) {
 		return nil, fmt.Errorf("module metadata at %s: %w", module.Path, err)
 	}
 
-	return parseBytes(data, string(module.InvowkfilePath()), module.Path, metadata)
+	return parseBytes(data, string(module.InvowkfilePath()), module.Path, metadata, newParseOptions(opts))
 }
 
 // ParseEnvInheritMode parses a string into an EnvInheritMode.
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import "github.com/invowk/invowk/pkg/invowkmod"
+
+type (
+	// parseOptions holds configuration for parsing.
+	parseOptions struct {
+		templateModules map[invowkmod.ModuleID]FilesystemPath
+	}
+
+	// ParseOption configures parsing behavior.
+	ParseOption func(*parseOptions)
+)
+
+// WithTemplateModules sets the module directories that module-qualified
+// template references ("@<module-id> <template>") resolve against, keyed by
+// module ID. Discovery passes the modules the parsed file may reach: every
+// module it found for a root invowkfile, and only the global modules and
+// declared, locked dependencies for a module invowkfile. The vendored
+// dependencies of a module, or the modules next to a root invowkfile, are
+// still looked up first.
+func WithTemplateModules(modules map[invowkmod.ModuleID]FilesystemPath) ParseOption {
+	return func(o *parseOptions) {
+		o.templateModules = modules
+	}
+}
+
+// newParseOptions applies opts to the default parse options.
+func newParseOptions(opts []ParseOption) parseOptions {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// PromptTypeInput asks for a single line of text.
+	PromptTypeInput PromptType = "input"
+	// PromptTypeChoose asks the user to pick one of the prompt options.
+	PromptTypeChoose PromptType = "choose"
+	// PromptTypeConfirm asks a yes/no question (bool flags only).
+	PromptTypeConfirm PromptType = "confirm"
+	// PromptTypeWrite asks for multi-line text.
+	PromptTypeWrite PromptType = "write"
+	// PromptTypeFilter asks the user to fuzzy-filter the prompt options.
+	PromptTypeFilter PromptType = "filter"
+	// PromptTypeFile asks the user to pick a file.
+	PromptTypeFile PromptType = "file"
+
+	// MaxPromptMessageLength is the maximum length, in runes, of a prompt message.
+	MaxPromptMessageLength = 1024
+	// MaxPromptOptionLength is the maximum length, in runes, of a prompt option
+	// or placeholder.
+	MaxPromptOptionLength = 256
+)
+
+var (
+	// ErrInvalidPromptType is the sentinel error wrapped by InvalidPromptTypeError.
+	ErrInvalidPromptType = errors.New("invalid prompt type")
+
+	// ErrInvalidPromptConfig is the sentinel error wrapped by InvalidPromptConfigError.
+	ErrInvalidPromptConfig = errors.New("invalid prompt config")
+
+	promptExtensionPattern = regexp.MustCompile(`^\.[a-zA-Z0-9]+$`)
+)
+
+type (
+	// PromptType selects the TUI component used to ask for a missing value.
+	//
+	//goplint:enum-cue=#PromptType
+	PromptType string
+
+	// InvalidPromptTypeError is returned when a PromptType is not a known component.
+	InvalidPromptTypeError struct {
+		Value PromptType
+	}
+
+	// InvalidPromptConfigError is returned when a PromptConfig has invalid fields.
+	// It wraps ErrInvalidPromptConfig for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidPromptConfigError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// PromptConfig declares the interactive prompt shown when a required
+	// argument or flag is missing and invowk runs in a terminal. The answer is
+	// injected through the usual INVOWK_ARG_*/INVOWK_FLAG_* variables and
+	// validated like a value passed on the command line.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	PromptConfig struct {
+		// Type selects the TUI component.
+		Type PromptType `json:"type"`
+		// Message is the question shown to the user.
+		Message string `json:"message"`
+		// Options lists the values offered by "choose" and "filter" prompts.
+		// When empty, the argument or flag choices are offered instead.
+		Options []string `json:"options,omitempty"`
+		// Placeholder is hint text for "input" and "write" prompts (optional).
+		Placeholder string `json:"placeholder,omitempty"`
+		// CharLimit limits the answer length of "input" prompts (optional).
+		CharLimit int `json:"char_limit,omitempty"`
+		// Extensions filters the files offered by "file" prompts (optional, e.g., ".go").
+		Extensions []string `json:"extensions,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidPromptTypeError) Error() string {
+	return fmt.Sprintf("invalid prompt type %q (must be input, choose, confirm, write, filter, or file)", e.Value)
+}
+
+// Unwrap returns ErrInvalidPromptType so callers can use errors.Is for programmatic detection.
+func (e *InvalidPromptTypeError) Unwrap() error { return ErrInvalidPromptType }
+
+// Validate returns nil if the PromptType is a known TUI component.
+//
+//goplint:nonzero
+func (t PromptType) Validate() error {
+	switch t {
+	case PromptTypeInput, PromptTypeChoose, PromptTypeConfirm, PromptTypeWrite, PromptTypeFilter, PromptTypeFile:
+		return nil
+	default:
+		return &InvalidPromptTypeError{Value: t}
+	}
+}
+
+// String returns the string representation of the PromptType.
+func (t PromptType) String() string { return string(t) }
+
+// Validate returns nil if the PromptConfig has valid fields, or an error
+// collecting all field-level validation failures. Compatibility with the
+// owning argument or flag is checked by promptValidationErrors.
+func (p PromptConfig) Validate() error {
+	var errs []error
+	appendFieldError(&errs, p.Type.Validate())
+	if strings.TrimSpace(p.Message) == "" {
+		errs = append(errs, errors.New("prompt message must not be empty"))
+	} else if utf8.RuneCountInString(p.Message) > MaxPromptMessageLength {
+		errs = append(errs, fmt.Errorf("prompt message exceeds maximum length of %d runes", MaxPromptMessageLength))
+	}
+	for _, option := range p.Options {
+		if option == "" || utf8.RuneCountInString(option) > MaxPromptOptionLength {
+			errs = append(errs, fmt.Errorf("prompt option %q must be 1-%d runes", option, MaxPromptOptionLength))
+		}
+	}
+	if utf8.RuneCountInString(p.Placeholder) > MaxPromptOptionLength {
+		errs = append(errs, fmt.Errorf("prompt placeholder exceeds maximum length of %d runes", MaxPromptOptionLength))
+	}
+	if p.CharLimit < 0 {
+		errs = append(errs, fmt.Errorf("prompt char_limit %d must be positive", p.CharLimit))
+	}
+	for _, ext := range p.Extensions {
+		if !promptExtensionPattern.MatchString(ext) {
+			errs = append(errs, fmt.Errorf("prompt extension %q must look like \".ext\"", ext))
+		}
+	}
+	errs = append(errs, p.optionFieldErrors()...)
+	if len(errs) > 0 {
+		return &InvalidPromptConfigError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidPromptConfigError.
+func (e *InvalidPromptConfigError) Error() string {
+	return types.FormatFieldErrors("prompt", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidPromptConfig and field errors for errors.Is() compatibility.
+func (e *InvalidPromptConfigError) Unwrap() error {
+	return errors.Join(ErrInvalidPromptConfig, errors.Join(e.FieldErrors...))
+}
+
+// EffectiveOptions returns the values offered by "choose" and "filter"
+// prompts: the prompt options, or choices when no options are declared.
+//
+//goplint:ignore -- prompt options are free-form display values.
+func (p *PromptConfig) EffectiveOptions(choices []string) []string {
+	if len(p.Options) > 0 {
+		return p.Options
+	}
+	return choices
+}
+
+// optionFieldErrors rejects component options set on a prompt type that
+// ignores them.
+func (p PromptConfig) optionFieldErrors() []error {
+	var errs []error
+	if len(p.Options) > 0 && p.Type != PromptTypeChoose && p.Type != PromptTypeFilter {
+		errs = append(errs, errors.New(`prompt options require type "choose" or "filter"`))
+	}
+	if p.Placeholder != "" && p.Type != PromptTypeInput && p.Type != PromptTypeWrite {
+		errs = append(errs, errors.New(`prompt placeholder requires type "input" or "write"`))
+	}
+	if p.CharLimit != 0 && p.Type != PromptTypeInput {
+		errs = append(errs, errors.New(`prompt char_limit requires type "input"`))
+	}
+	if len(p.Extensions) > 0 && p.Type != PromptTypeFile {
+		errs = append(errs, errors.New(`prompt extensions require type "file"`))
+	}
+	return errs
+}
+
+// promptValidationErrors checks that a prompt fits the argument or flag it
+// belongs to: only required values are prompted for, confirm prompts answer
+// bool flags only, bool flags can only be confirmed, option prompts need
+// options (or choices), and every option must be a valid value.
+// Shared by flags and arguments.
+func promptValidationErrors(prompt *PromptConfig, required bool, typeName FlagType, choices []string, pattern RegexPattern) []error {
+	if prompt == nil {
+		return nil
+	}
+	var errs []error
+	if err := prompt.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if !required {
+		errs = append(errs, errors.New("prompt requires required: true (only missing required values are prompted for)"))
+	}
+	if (prompt.Type == PromptTypeConfirm) != (typeName == FlagTypeBool) {
+		errs = append(errs, fmt.Errorf(`prompt type %q does not fit type %q (confirm prompts are for bool flags only)`, prompt.Type, typeName))
+	}
+	if prompt.Type != PromptTypeChoose && prompt.Type != PromptTypeFilter {
+		return errs
+	}
+	options := prompt.EffectiveOptions(choices)
+	if len(options) == 0 {
+		errs = append(errs, fmt.Errorf("prompt type %q requires options or choices", prompt.Type))
+	}
+	for _, option := range prompt.Options {
+		if len(choices) > 0 && !slices.Contains(choices, option) {
+			errs = append(errs, fmt.Errorf("prompt option %q is not one of the choices", option))
+			continue
+		}
+		if err := validateValueType(option, promptAnswerType(typeName)); err != nil {
+			errs = append(errs, fmt.Errorf("prompt option %q is not compatible with type %q: %w", option, typeName, err))
+			continue
+		}
+		if !matchesValidation(option, string(pattern)) {
+			errs = append(errs, fmt.Errorf("prompt option %q does not match validation pattern %q", option, pattern))
+		}
+	}
+	return errs
+}
+
+// promptAnswerType returns the type a single prompt answer must satisfy:
+// list flags collect one string item per answer.
+func promptAnswerType(typeName FlagType) FlagType {
+	if typeName == FlagTypeList {
+		return FlagTypeString
+	}
+	return typeName
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPromptConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		prompt  PromptConfig
+		wantErr string
+	}{
+		{name: "input", prompt: PromptConfig{Type: PromptTypeInput, Message: "Name?", Placeholder: "alice", CharLimit: 20}},
+		{name: "choose with options", prompt: PromptConfig{Type: PromptTypeChoose, Message: "Where?", Options: []string{"eu", "us"}}},
+		{name: "file with extensions", prompt: PromptConfig{Type: PromptTypeFile, Message: "Which?", Extensions: []string{".yaml", ".json"}}},
+		{name: "unknown type", prompt: PromptConfig{Type: "select", Message: "Where?"}, wantErr: "invalid prompt type"},
+		{name: "blank message", prompt: PromptConfig{Type: PromptTypeInput, Message: "  "}, wantErr: "message must not be empty"},
+		{name: "message too long", prompt: PromptConfig{Type: PromptTypeInput, Message: strings.Repeat("x", MaxPromptMessageLength+1)}, wantErr: "exceeds maximum length"},
+		{name: "empty option", prompt: PromptConfig{Type: PromptTypeChoose, Message: "Where?", Options: []string{""}}, wantErr: "prompt option"},
+		{name: "negative char limit", prompt: PromptConfig{Type: PromptTypeInput, Message: "Name?", CharLimit: -1}, wantErr: "char_limit"},
+		{name: "malformed extension", prompt: PromptConfig{Type: PromptTypeFile, Message: "Which?", Extensions: []string{"yaml"}}, wantErr: "extension"},
+		{name: "options on input", prompt: PromptConfig{Type: PromptTypeInput, Message: "Name?", Options: []string{"a"}}, wantErr: `options require type "choose" or "filter"`},
+		{name: "placeholder on confirm", prompt: PromptConfig{Type: PromptTypeConfirm, Message: "Sure?", Placeholder: "y"}, wantErr: "placeholder requires"},
+		{name: "char limit on write", prompt: PromptConfig{Type: PromptTypeWrite, Message: "Notes?", CharLimit: 10}, wantErr: `char_limit requires type "input"`},
+		{name: "extensions on input", prompt: PromptConfig{Type: PromptTypeInput, Message: "Name?", Extensions: []string{".go"}}, wantErr: `extensions require type "file"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.prompt.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidPromptConfig) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidPromptConfig", err)
+			}
+			var promptErr *InvalidPromptConfigError
+			if !errors.As(err, &promptErr) || !fieldErrorsContain(promptErr.FieldErrors, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want a field error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlagAndArgumentValidatePrompt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flag    *Flag
+		arg     *Argument
+		wantErr string
+	}{
+		{
+			name: "confirm on required bool flag",
+			flag: &Flag{Name: "force", Description: "Force", Type: FlagTypeBool, Required: true, Prompt: &PromptConfig{Type: PromptTypeConfirm, Message: "Force?"}},
+		},
+		{
+			name: "choose falls back to choices",
+			arg:  &Argument{Name: "env", Description: "Env", Required: true, Choices: []string{"dev", "prod"}, Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Env?"}},
+		},
+		{
+			name: "filter options on list flag",
+			flag: &Flag{Name: "tag", Description: "Tags", Type: FlagTypeList, Required: true, Prompt: &PromptConfig{Type: PromptTypeFilter, Message: "Tag?", Options: []string{"a", "b"}}},
+		},
+		{
+			name:    "optional flag",
+			flag:    &Flag{Name: "note", Description: "Note", Prompt: &PromptConfig{Type: PromptTypeInput, Message: "Note?"}},
+			wantErr: "prompt requires required: true",
+		},
+		{
+			name:    "confirm on string flag",
+			flag:    &Flag{Name: "note", Description: "Note", Required: true, Prompt: &PromptConfig{Type: PromptTypeConfirm, Message: "Note?"}},
+			wantErr: "confirm prompts are for bool flags only",
+		},
+		{
+			name:    "input on bool flag",
+			flag:    &Flag{Name: "force", Description: "Force", Type: FlagTypeBool, Required: true, Prompt: &PromptConfig{Type: PromptTypeInput, Message: "Force?"}},
+			wantErr: "confirm prompts are for bool flags only",
+		},
+		{
+			name:    "confirm on argument",
+			arg:     &Argument{Name: "env", Description: "Env", Required: true, Prompt: &PromptConfig{Type: PromptTypeConfirm, Message: "Env?"}},
+			wantErr: "confirm prompts are for bool flags only",
+		},
+		{
+			name:    "choose without options or choices",
+			arg:     &Argument{Name: "env", Description: "Env", Required: true, Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Env?"}},
+			wantErr: "requires options or choices",
+		},
+		{
+			name:    "option outside choices",
+			arg:     &Argument{Name: "env", Description: "Env", Required: true, Choices: []string{"dev"}, Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Env?", Options: []string{"prod"}}},
+			wantErr: "is not one of the choices",
+		},
+		{
+			name:    "option incompatible with type",
+			flag:    &Flag{Name: "count", Description: "Count", Type: FlagTypeInt, Required: true, Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Count?", Options: []string{"many"}}},
+			wantErr: "is not compatible with type",
+		},
+		{
+			name:    "option not matching validation",
+			flag:    &Flag{Name: "region", Description: "Region", Required: true, Validation: "^[a-z]+$", Prompt: &PromptConfig{Type: PromptTypeChoose, Message: "Region?", Options: []string{"EU"}}},
+			wantErr: "does not match validation pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.flag != nil {
+				assertFlagValidateDefaultValue(t, *tt.flag, tt.wantErr)
+				return
+			}
+			assertArgumentValidateDefaultValue(t, *tt.arg, tt.wantErr)
+		})
+	}
+}
+
+func TestPromptConfigEffectiveOptions(t *testing.T) {
+	t.Parallel()
+
+	choices := []string{"dev", "prod"}
+	withOptions := &PromptConfig{Type: PromptTypeChoose, Message: "Env?", Options: []string{"prod"}}
+	if got := withOptions.EffectiveOptions(choices); !slices.Equal(got, []string{"prod"}) {
+		t.Fatalf("EffectiveOptions() = %v, want [prod]", got)
+	}
+	withoutOptions := &PromptConfig{Type: PromptTypeChoose, Message: "Env?"}
+	if got := withoutOptions.EffectiveOptions(choices); !slices.Equal(got, choices) {
+		t.Fatalf("EffectiveOptions() = %v, want %v", got, choices)
+	}
+}
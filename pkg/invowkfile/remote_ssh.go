@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// ErrInvalidRemoteHost is the sentinel error wrapped by InvalidRemoteHostError.
+	ErrInvalidRemoteHost = errors.New("invalid remote host")
+	// ErrInvalidRemoteUser is the sentinel error wrapped by InvalidRemoteUserError.
+	ErrInvalidRemoteUser = errors.New("invalid remote user")
+	// ErrInvalidRemotePort is the sentinel error wrapped by InvalidRemotePortError.
+	ErrInvalidRemotePort = errors.New("invalid remote port")
+
+	// remoteHostRegex accepts host names, IPv4/IPv6 addresses, and ssh_config
+	// aliases. A leading '-' is rejected so a host can never be read as an
+	// ssh client option.
+	remoteHostRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.:%-]*$`)
+
+	// remoteUserRegex accepts portable login names.
+	remoteUserRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+)
+
+type (
+	// RemoteHost is the host name, address, or ssh_config alias the
+	// remote-ssh runtime connects to.
+	RemoteHost string
+
+	// RemoteUser is the login name used by the remote-ssh runtime.
+	// The zero value uses the ssh client default.
+	RemoteUser string
+
+	// RemotePort is the SSH port used by the remote-ssh runtime.
+	// The zero value uses the ssh client default.
+	RemotePort int
+
+	// InvalidRemoteHostError is returned when a RemoteHost value is malformed.
+	InvalidRemoteHostError struct {
+		Value RemoteHost
+	}
+
+	// InvalidRemoteUserError is returned when a RemoteUser value is malformed.
+	InvalidRemoteUserError struct {
+		Value RemoteUser
+	}
+
+	// InvalidRemotePortError is returned when a RemotePort is out of range.
+	InvalidRemotePortError struct {
+		Value RemotePort
+	}
+)
+
+// Error implements the error interface for InvalidRemoteHostError.
+func (e *InvalidRemoteHostError) Error() string {
+	return fmt.Sprintf("invalid remote host %q: must be a host name, address, or ssh_config alias of at most 253 characters", e.Value)
+}
+
+// Unwrap returns ErrInvalidRemoteHost for errors.Is() compatibility.
+func (e *InvalidRemoteHostError) Unwrap() error { return ErrInvalidRemoteHost }
+
+// String returns the string representation of the RemoteHost.
+func (h RemoteHost) String() string { return string(h) }
+
+// Validate returns nil if the RemoteHost is a well-formed host.
+//
+//goplint:nonzero
+func (h RemoteHost) Validate() error {
+	if len(h) > 253 || !remoteHostRegex.MatchString(string(h)) {
+		return &InvalidRemoteHostError{Value: h}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidRemoteUserError.
+func (e *InvalidRemoteUserError) Error() string {
+	return fmt.Sprintf("invalid remote user %q: must be a login name of at most 256 characters", e.Value)
+}
+
+// Unwrap returns ErrInvalidRemoteUser for errors.Is() compatibility.
+func (e *InvalidRemoteUserError) Unwrap() error { return ErrInvalidRemoteUser }
+
+// String returns the string representation of the RemoteUser.
+func (u RemoteUser) String() string { return string(u) }
+
+// Validate returns nil if the RemoteUser is empty or a well-formed login name.
+func (u RemoteUser) Validate() error {
+	if u == "" {
+		return nil
+	}
+	if len(u) > 256 || !remoteUserRegex.MatchString(string(u)) {
+		return &InvalidRemoteUserError{Value: u}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidRemotePortError.
+func (e *InvalidRemotePortError) Error() string {
+	return fmt.Sprintf("invalid remote port %d: must be between 1 and 65535", e.Value)
+}
+
+// Unwrap returns ErrInvalidRemotePort for errors.Is() compatibility.
+func (e *InvalidRemotePortError) Unwrap() error { return ErrInvalidRemotePort }
+
+// String returns the decimal representation of the RemotePort.
+func (p RemotePort) String() string { return strconv.Itoa(int(p)) }
+
+// Validate returns nil if the RemotePort is zero (ssh client default) or a
+// valid TCP port.
+func (p RemotePort) Validate() error {
+	if p < 0 || p > 65535 {
+		return &InvalidRemotePortError{Value: p}
+	}
+	return nil
+}
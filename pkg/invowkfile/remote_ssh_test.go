@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRemoteHost_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		host RemoteHost
+		want bool
+	}{
+		{"host name", "build-01.example.com", true},
+		{"ssh_config alias", "prod_db", true},
+		{"ipv4", "10.0.0.5", true},
+		{"ipv6", "fe80::1%eth0", true},
+		{"empty", "", false},
+		{"leading dash", "-oProxyCommand=sh", false},
+		{"user in host", "deploy@host", false},
+		{"whitespace", "build 01", false},
+		{"too long", RemoteHost(strings.Repeat("a", 254)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.host.Validate()
+			if (err == nil) != tt.want {
+				t.Fatalf("RemoteHost(%q).Validate() error = %v, want valid=%v", tt.host, err, tt.want)
+			}
+			if err != nil {
+				var hostErr *InvalidRemoteHostError
+				if !errors.Is(err, ErrInvalidRemoteHost) || !errors.As(err, &hostErr) {
+					t.Errorf("error should be *InvalidRemoteHostError wrapping ErrInvalidRemoteHost, got %T: %v", err, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoteUser_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		user RemoteUser
+		want bool
+	}{
+		{"empty uses client default", "", true},
+		{"login name", "deploy", true},
+		{"dotted name", "svc.build-bot", true},
+		{"leading dash", "-l", false},
+		{"at sign", "a@b", false},
+		{"whitespace", "de ploy", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.user.Validate()
+			if (err == nil) != tt.want {
+				t.Fatalf("RemoteUser(%q).Validate() error = %v, want valid=%v", tt.user, err, tt.want)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRemoteUser) {
+				t.Errorf("error should wrap ErrInvalidRemoteUser, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRemotePort_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		port RemotePort
+		want bool
+	}{
+		{0, true},
+		{22, true},
+		{65535, true},
+		{-1, false},
+		{65536, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.port.String(), func(t *testing.T) {
+			t.Parallel()
+			err := tt.port.Validate()
+			if (err == nil) != tt.want {
+				t.Fatalf("RemotePort(%d).Validate() error = %v, want valid=%v", tt.port, err, tt.want)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRemotePort) {
+				t.Errorf("error should wrap ErrInvalidRemotePort, got: %v", err)
+			}
+		})
+	}
+}
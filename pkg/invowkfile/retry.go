@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// MaxRetryAttempts is the upper bound for RetryPolicy.Attempts.
+	MaxRetryAttempts RetryAttempts = 100
+
+	// DefaultRetryBackoff is the delay before the first retry when a retry
+	// policy does not declare a backoff.
+	DefaultRetryBackoff = time.Second
+)
+
+var (
+	// ErrInvalidRetryAttempts is the sentinel error wrapped by InvalidRetryAttemptsError.
+	ErrInvalidRetryAttempts = errors.New("invalid retry attempts")
+
+	// ErrInvalidRetryPolicy is the sentinel error wrapped by InvalidRetryPolicyError.
+	ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+
+	// ErrRetryOnSuccessExitCode is returned when on_exit_codes lists exit code 0,
+	// which never triggers a retry.
+	ErrRetryOnSuccessExitCode = errors.New("on_exit_codes must not contain 0 (successful runs are never retried)")
+
+	// ErrRetryMaxBackoffBelowBackoff is returned when max_backoff is shorter
+	// than backoff.
+	ErrRetryMaxBackoffBelowBackoff = errors.New("max_backoff must not be shorter than backoff")
+)
+
+type (
+	// RetryAttempts is the total number of times an implementation may run,
+	// including the first attempt. Valid values are 1 through MaxRetryAttempts.
+	RetryAttempts int
+
+	// InvalidRetryAttemptsError is returned when a RetryAttempts value is out of range.
+	InvalidRetryAttemptsError struct {
+		Value RetryAttempts
+	}
+
+	// InvalidRetryPolicyError is returned when a RetryPolicy has invalid fields.
+	// It wraps ErrInvalidRetryPolicy for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidRetryPolicyError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// RetryPolicy re-runs a failed implementation with exponential backoff.
+	// Only failures that exit with a non-zero code are retried; runtime errors,
+	// timeouts, and cancellation stop the run immediately.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	RetryPolicy struct {
+		// Attempts is the total number of runs, including the first one.
+		Attempts RetryAttempts `json:"attempts"`
+		// Backoff is the delay before the first retry (default: 1s). The delay
+		// doubles after every retry.
+		Backoff DurationString `json:"backoff,omitempty"`
+		// MaxBackoff caps the delay between retries (optional, default: no cap).
+		MaxBackoff DurationString `json:"max_backoff,omitempty"`
+		// OnExitCodes restricts retries to these exit codes (optional).
+		// When empty, every non-zero exit code is retried.
+		OnExitCodes []types.ExitCode `json:"on_exit_codes,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidRetryAttemptsError) Error() string {
+	return fmt.Sprintf("invalid retry attempts %d (must be between 1 and %d)", e.Value, MaxRetryAttempts)
+}
+
+// Unwrap returns ErrInvalidRetryAttempts so callers can use errors.Is for programmatic detection.
+func (e *InvalidRetryAttemptsError) Unwrap() error { return ErrInvalidRetryAttempts }
+
+// Validate returns nil if the RetryAttempts is between 1 and MaxRetryAttempts.
+//
+//goplint:nonzero
+func (a RetryAttempts) Validate() error {
+	if a < 1 || a > MaxRetryAttempts {
+		return &InvalidRetryAttemptsError{Value: a}
+	}
+	return nil
+}
+
+// String returns the decimal string representation of the RetryAttempts.
+func (a RetryAttempts) String() string { return strconv.Itoa(int(a)) }
+
+// Validate returns nil if the RetryPolicy has valid fields,
+// or an error collecting all field-level validation failures.
+func (p RetryPolicy) Validate() error {
+	var errs []error
+	appendFieldError(&errs, p.Attempts.Validate())
+	appendFieldError(&errs, p.Backoff.Validate())
+	appendFieldError(&errs, p.MaxBackoff.Validate())
+	for _, code := range p.OnExitCodes {
+		if err := code.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if code.IsSuccess() {
+			errs = append(errs, ErrRetryOnSuccessExitCode)
+		}
+	}
+	if len(errs) == 0 && p.MaxBackoff != "" {
+		backoff, _ := p.ParseBackoff()
+		maxBackoff, _ := parseDuration("max_backoff", p.MaxBackoff)
+		if maxBackoff < backoff {
+			errs = append(errs, ErrRetryMaxBackoffBelowBackoff)
+		}
+	}
+	if len(errs) > 0 {
+		return &InvalidRetryPolicyError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidRetryPolicyError.
+func (e *InvalidRetryPolicyError) Error() string {
+	return types.FormatFieldErrors("retry policy", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidRetryPolicy and field errors for errors.Is() compatibility.
+func (e *InvalidRetryPolicyError) Unwrap() error {
+	return errors.Join(ErrInvalidRetryPolicy, errors.Join(e.FieldErrors...))
+}
+
+// ParseBackoff parses the Backoff field into a time.Duration.
+// Returns DefaultRetryBackoff when Backoff is empty.
+func (p *RetryPolicy) ParseBackoff() (time.Duration, error) {
+	backoff, err := parseDuration("backoff", p.Backoff)
+	if err != nil {
+		return 0, err
+	}
+	if backoff == 0 {
+		return DefaultRetryBackoff, nil
+	}
+	return backoff, nil
+}
+
+// Delay returns the delay before the given retry (1 for the first retry):
+// the backoff doubled once per earlier retry, capped at MaxBackoff.
+func (p *RetryPolicy) Delay(retry int) (time.Duration, error) {
+	delay, err := p.ParseBackoff()
+	if err != nil {
+		return 0, err
+	}
+	maxBackoff, err := parseDuration("max_backoff", p.MaxBackoff)
+	if err != nil {
+		return 0, err
+	}
+	if maxBackoff == 0 {
+		maxBackoff = math.MaxInt64
+	}
+	for range retry - 1 {
+		if delay >= maxBackoff/2 {
+			return maxBackoff, nil
+		}
+		delay *= 2
+	}
+	return min(delay, maxBackoff), nil
+}
+
+// ShouldRetry reports whether a run that exited with code should be retried
+// according to OnExitCodes. Successful runs are never retried.
+func (p *RetryPolicy) ShouldRetry(code types.ExitCode) bool {
+	if code.IsSuccess() {
+		return false
+	}
+	return len(p.OnExitCodes) == 0 || slices.Contains(p.OnExitCodes, code)
+}
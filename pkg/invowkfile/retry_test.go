@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr error
+	}{
+		{name: "attempts only", policy: RetryPolicy{Attempts: 3}},
+		{name: "full policy", policy: RetryPolicy{Attempts: 5, Backoff: "500ms", MaxBackoff: "10s", OnExitCodes: []types.ExitCode{75, 124}}},
+		{name: "max backoff equal to backoff", policy: RetryPolicy{Attempts: 2, Backoff: "2s", MaxBackoff: "2s"}},
+		{name: "max backoff below default backoff", policy: RetryPolicy{Attempts: 2, MaxBackoff: "500ms"}, wantErr: ErrRetryMaxBackoffBelowBackoff},
+		{name: "zero attempts", policy: RetryPolicy{}, wantErr: ErrInvalidRetryAttempts},
+		{name: "too many attempts", policy: RetryPolicy{Attempts: MaxRetryAttempts + 1}, wantErr: ErrInvalidRetryAttempts},
+		{name: "invalid backoff", policy: RetryPolicy{Attempts: 2, Backoff: "0s"}, wantErr: ErrInvalidDurationString},
+		{name: "max backoff below backoff", policy: RetryPolicy{Attempts: 2, Backoff: "5s", MaxBackoff: "1s"}, wantErr: ErrRetryMaxBackoffBelowBackoff},
+		{name: "success exit code", policy: RetryPolicy{Attempts: 2, OnExitCodes: []types.ExitCode{0}}, wantErr: ErrRetryOnSuccessExitCode},
+		{name: "out of range exit code", policy: RetryPolicy{Attempts: 2, OnExitCodes: []types.ExitCode{256}}, wantErr: types.ErrInvalidExitCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.policy.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidRetryPolicy) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v wrapped in %v", err, tt.wantErr, ErrInvalidRetryPolicy)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{name: "default backoff", policy: RetryPolicy{Attempts: 3}, retry: 1, want: time.Second},
+		{name: "doubles per retry", policy: RetryPolicy{Attempts: 5, Backoff: "100ms"}, retry: 4, want: 800 * time.Millisecond},
+		{name: "capped by max backoff", policy: RetryPolicy{Attempts: 5, Backoff: "1s", MaxBackoff: "3s"}, retry: 3, want: 3 * time.Second},
+		{name: "large retry capped", policy: RetryPolicy{Attempts: MaxRetryAttempts, Backoff: "1h", MaxBackoff: "24h"}, retry: 99, want: 24 * time.Hour},
+		{name: "large retry saturates without cap", policy: RetryPolicy{Attempts: MaxRetryAttempts, Backoff: "1h"}, retry: 99, want: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.policy.Delay(tt.retry)
+			if err != nil {
+				t.Fatalf("Delay(%d) error = %v", tt.retry, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Delay(%d) = %v, want %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	anyFailure := RetryPolicy{Attempts: 3}
+	if anyFailure.ShouldRetry(0) {
+		t.Error("ShouldRetry(0) = true, want successful runs never retried")
+	}
+	if !anyFailure.ShouldRetry(1) {
+		t.Error("ShouldRetry(1) = false, want any non-zero code retried without on_exit_codes")
+	}
+
+	listed := RetryPolicy{Attempts: 3, OnExitCodes: []types.ExitCode{75}}
+	if !listed.ShouldRetry(75) || listed.ShouldRetry(1) {
+		t.Error("ShouldRetry() should only retry exit codes listed in on_exit_codes")
+	}
+}
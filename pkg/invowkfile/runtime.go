@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/invowk/invowk/pkg/containerargs"
@@ -20,8 +21,12 @@ const (
 	RuntimeVirtualSh RuntimeMode = types.RuntimeVirtualSh
 	// RuntimeVirtualLua executes commands using an embedded Lua interpreter
 	RuntimeVirtualLua RuntimeMode = types.RuntimeVirtualLua
+	// RuntimeVirtualStarlark executes commands using an embedded Starlark interpreter
+	RuntimeVirtualStarlark RuntimeMode = types.RuntimeVirtualStarlark
 	// RuntimeContainer executes commands inside a disposable container
 	RuntimeContainer RuntimeMode = types.RuntimeContainer
+	// RuntimeRemoteSSH executes commands on a remote host over SSH
+	RuntimeRemoteSSH RuntimeMode = types.RuntimeRemoteSSH
 
 	// BinaryLookupModeHost resolves allowed host binaries using the command environment PATH.
 	BinaryLookupModeHost BinaryLookupMode = "host"
@@ -104,6 +109,11 @@ var (
 		"lua": true, "lua5.4": true, "lua5.5": true, "luajit": true,
 	}
 
+	// starlarkInterpreters maps Starlark interpreter base names to true.
+	starlarkInterpreters = map[string]bool{
+		"starlark": true,
+	}
+
 	// interpreterExtensions maps interpreter base names to typical file extensions.
 	// Used when creating temporary script files to ensure proper syntax highlighting
 	// and interpreter behavior.
@@ -113,6 +123,7 @@ var (
 		"bash": ".sh", "sh": ".sh", "zsh": ".zsh",
 		"fish": ".fish", "pwsh": ".ps1", "powershell": ".ps1",
 		"php": ".php", "lua": ".lua", "Rscript": ".R",
+		"starlark": ".star",
 	}
 )
 
@@ -220,6 +231,9 @@ type (
 		Value LuaCPULimit
 	}
 
+	// StarlarkMaxSteps is an optional Starlark execution step limit. Zero means unlimited.
+	StarlarkMaxSteps uint64
+
 	// MemoryLimit is an optional byte-size string for Lua memory quotas.
 	MemoryLimit string
 
@@ -260,6 +274,8 @@ type (
 		CPULimit LuaCPULimit `json:"cpu_limit,omitempty"`
 		// MemoryLimit sets the optional virtual-lua memory quota.
 		MemoryLimit MemoryLimit `json:"memory_limit,omitempty"`
+		// MaxSteps sets the optional virtual-starlark execution step limit. Zero means unlimited.
+		MaxSteps StarlarkMaxSteps `json:"max_steps,omitempty"`
 		// DependsOn specifies dependencies validated inside the container environment.
 		// Only valid when Name is RuntimeContainer. For native/virtual runtimes, CUE schema
 		// rejects this field; Go structural validation provides defense-in-depth.
@@ -280,6 +296,20 @@ type (
 		Ports []PortMappingSpec `json:"ports,omitempty"`
 		// Persistent configures persistent container targeting (container only)
 		Persistent *RuntimePersistentConfig `json:"persistent,omitempty"`
+		// Host is the host the script runs on (remote-ssh only, required)
+		Host RemoteHost `json:"host,omitempty"`
+		// Port is the SSH port of Host (remote-ssh only). Zero uses the ssh client default.
+		Port RemotePort `json:"port,omitempty"`
+		// User is the remote login name (remote-ssh only). Empty uses the ssh client default.
+		User RemoteUser `json:"user,omitempty"`
+		// IdentityFile is the private key used to authenticate (remote-ssh only).
+		// Relative paths resolve against the invowkfile directory.
+		IdentityFile FilesystemPath `json:"identity_file,omitempty"`
+		// WorkDir is the remote working directory (remote-ssh only).
+		// Empty runs the script in the remote login directory.
+		WorkDir WorkDir `json:"workdir,omitempty"`
+		// Sandbox isolates the script with Linux namespaces and Landlock (native only).
+		Sandbox *RuntimeSandboxConfig `json:"sandbox,omitempty"`
 	}
 
 	//goplint:validate-all
@@ -288,6 +318,9 @@ type (
 	PlatformConfig struct {
 		// Name specifies the platform type (required)
 		Name PlatformType `json:"name"`
+		// Arch restricts the platform to these CPU architectures.
+		// Empty means every architecture.
+		Arch []Architecture `json:"arch,omitempty"`
 		// Virtual contains platform-specific settings for virtual runtimes.
 		Virtual *PlatformVirtualConfig `json:"virtual,omitempty"`
 	}
@@ -549,6 +582,12 @@ func (l LuaCPULimit) String() string { return fmt.Sprintf("%d", l) }
 // Validate returns nil. LuaCPULimit is unsigned, so all decoded values are valid.
 func (l LuaCPULimit) Validate() error { return nil }
 
+// String returns the string representation of the StarlarkMaxSteps.
+func (s StarlarkMaxSteps) String() string { return fmt.Sprintf("%d", s) }
+
+// Validate returns nil. StarlarkMaxSteps is unsigned, so all decoded values are valid.
+func (s StarlarkMaxSteps) Validate() error { return nil }
+
 // Error implements the error interface for InvalidMemoryLimitError.
 func (e *InvalidMemoryLimitError) Error() string {
 	return fmt.Sprintf("invalid memory limit %q: must be a byte count with optional K, M, or G suffix", e.Value)
@@ -574,12 +613,14 @@ func (m MemoryLimit) Validate() error {
 
 // Validate returns nil if the PlatformConfig has valid fields,
 // or an error collecting all field-level validation failures.
-// Delegates to Name.Validate() (nonzero).
+// Delegates to Name.Validate() (nonzero) and Architecture.Validate() for
+// each declared architecture.
 func (p PlatformConfig) Validate() error {
 	var errs []error
 	if err := p.Name.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	appendEachValidation(&errs, p.Arch)
 	appendOptionalValidation(&errs, p.Virtual, p.Virtual != nil)
 	if len(errs) > 0 {
 		return &InvalidPlatformConfigError{FieldErrors: errs}
@@ -587,6 +628,12 @@ func (p PlatformConfig) Validate() error {
 	return nil
 }
 
+// MatchesArch returns true if the platform applies to the given CPU
+// architecture: it declares no arch restriction or lists arch.
+func (p PlatformConfig) MatchesArch(arch Architecture) bool {
+	return len(p.Arch) == 0 || slices.Contains(p.Arch, arch)
+}
+
 // VirtualFilesystem returns this platform's virtual filesystem config.
 // Missing nested config means restricted access with no named paths.
 func (p PlatformConfig) VirtualFilesystem() VirtualFilesystemConfig {
@@ -620,12 +667,19 @@ func (rc RuntimeConfig) Validate() error {
 	appendOptionalValidation(&errs, rc.BinaryLookupMode, rc.BinaryLookupMode != "")
 	appendOptionalValidation(&errs, rc.CPULimit, rc.CPULimit != 0)
 	appendOptionalValidation(&errs, rc.MemoryLimit, rc.MemoryLimit != "")
+	appendOptionalValidation(&errs, rc.MaxSteps, rc.MaxSteps != 0)
 	appendOptionalValidation(&errs, rc.DependsOn, rc.DependsOn != nil)
 	appendOptionalValidation(&errs, rc.Containerfile, rc.Containerfile != "")
 	appendOptionalValidation(&errs, rc.Image, rc.Image != "")
 	appendEachValidation(&errs, rc.Volumes)
 	appendEachValidation(&errs, rc.Ports)
 	appendOptionalValidation(&errs, rc.Persistent, rc.Persistent != nil)
+	appendOptionalValidation(&errs, rc.Host, rc.Host != "")
+	appendOptionalValidation(&errs, rc.Port, rc.Port != 0)
+	appendOptionalValidation(&errs, rc.User, rc.User != "")
+	appendOptionalValidation(&errs, rc.IdentityFile, rc.IdentityFile != "")
+	appendOptionalValidation(&errs, rc.WorkDir, rc.WorkDir != "")
+	appendOptionalValidation(&errs, rc.Sandbox, rc.Sandbox != nil)
 	appendRuntimeConfigInvariantErrors(&errs, rc)
 	if len(errs) > 0 {
 		return &InvalidRuntimeConfigError{FieldErrors: errs}
@@ -638,6 +692,10 @@ func appendRuntimeConfigInvariantErrors(errs *[]error, rc RuntimeConfig) {
 		*errs = append(*errs, errors.New(`env_inherit_allow requires env_inherit_mode: "allow"`))
 	}
 
+	appendRemoteSSHRuntimeFieldErrors(errs, rc)
+	if rc.Sandbox != nil && rc.Name != RuntimeNative {
+		*errs = append(*errs, errors.New("sandbox is only valid for native runtime"))
+	}
 	if rc.Name != RuntimeContainer {
 		appendVirtualRuntimeFieldErrors(errs, rc)
 		appendNonContainerRuntimeFieldErrors(errs, rc)
@@ -656,8 +714,7 @@ func appendRuntimeConfigInvariantErrors(errs *[]error, rc RuntimeConfig) {
 }
 
 func appendVirtualRuntimeFieldErrors(errs *[]error, rc RuntimeConfig) {
-	isVirtual := rc.Name == RuntimeVirtualSh || rc.Name == RuntimeVirtualLua
-	if !isVirtual {
+	if !rc.Name.IsVirtual() {
 		if len(rc.AllowedBinaries) > 0 {
 			*errs = append(*errs, errors.New("allowed_binaries is only valid for virtual runtimes"))
 		}
@@ -673,6 +730,9 @@ func appendVirtualRuntimeFieldErrors(errs *[]error, rc RuntimeConfig) {
 			*errs = append(*errs, errors.New("memory_limit is only valid for virtual-lua runtime"))
 		}
 	}
+	if rc.Name != RuntimeVirtualStarlark && rc.MaxSteps != 0 {
+		*errs = append(*errs, errors.New("max_steps is only valid for virtual-starlark runtime"))
+	}
 }
 
 func appendNonContainerRuntimeFieldErrors(errs *[]error, rc RuntimeConfig) {
@@ -699,6 +759,30 @@ func appendNonContainerRuntimeFieldErrors(errs *[]error, rc RuntimeConfig) {
 	}
 }
 
+func appendRemoteSSHRuntimeFieldErrors(errs *[]error, rc RuntimeConfig) {
+	if rc.Name == RuntimeRemoteSSH {
+		if rc.Host == "" {
+			*errs = append(*errs, errors.New("remote-ssh runtime requires host"))
+		}
+		return
+	}
+	if rc.Host != "" {
+		*errs = append(*errs, errors.New("host is only valid for remote-ssh runtime"))
+	}
+	if rc.Port != 0 {
+		*errs = append(*errs, errors.New("port is only valid for remote-ssh runtime"))
+	}
+	if rc.User != "" {
+		*errs = append(*errs, errors.New("user is only valid for remote-ssh runtime"))
+	}
+	if rc.IdentityFile != "" {
+		*errs = append(*errs, errors.New("identity_file is only valid for remote-ssh runtime"))
+	}
+	if rc.WorkDir != "" {
+		*errs = append(*errs, errors.New("workdir is only valid for remote-ssh runtime"))
+	}
+}
+
 // Error implements the error interface for InvalidRuntimeConfigError.
 func (e *InvalidRuntimeConfigError) Error() string {
 	return types.FormatFieldErrors("runtime config", e.FieldErrors)
@@ -846,6 +930,13 @@ func IsLuaInterpreter(interpreter string) bool {
 	return luaInterpreters[base]
 }
 
+// IsStarlarkInterpreter returns true if the interpreter is a Starlark interpreter.
+func IsStarlarkInterpreter(interpreter string) bool {
+	base := filepath.Base(interpreter)
+	base = strings.TrimSuffix(base, ".exe")
+	return starlarkInterpreters[base]
+}
+
 // GetExtensionForInterpreter returns the typical file extension for an interpreter.
 // Returns empty string if the interpreter is not recognized.
 func GetExtensionForInterpreter(interpreter string) string {
@@ -356,6 +356,12 @@ func TestInterpreterMutationBoundaries(t *testing.T) {
 	if IsLuaInterpreter("lua5.3") {
 		t.Fatal("IsLuaInterpreter(lua5.3) = true, want false")
 	}
+	if !IsStarlarkInterpreter("/usr/local/bin/starlark") {
+		t.Fatal("IsStarlarkInterpreter(/usr/local/bin/starlark) = false, want true")
+	}
+	if IsStarlarkInterpreter("python3") {
+		t.Fatal("IsStarlarkInterpreter(python3) = true, want false")
+	}
 }
 
 func assertRuntimeConfigFieldErrors(t *testing.T, err error, want []string) {
@@ -35,6 +35,22 @@ var (
 		"cpu_limit":    {},
 		"memory_limit": {},
 	}
+
+	nonStarlarkRuntimeFields = map[string]struct{}{
+		"max_steps": {},
+	}
+
+	nonNativeRuntimeFields = map[string]struct{}{
+		"sandbox": {},
+	}
+
+	nonRemoteSSHRuntimeFields = map[string]struct{}{
+		"host":          {},
+		"identity_file": {},
+		"port":          {},
+		"user":          {},
+		"workdir":       {},
+	}
 )
 
 //goplint:ignore -- CUE parser boundary consumes raw bytes and filename display text.
@@ -43,6 +59,13 @@ func runtimeSchemaPreflightValidationErrors(data []byte, path string) Validation
 	if err != nil {
 		return runtimePreflightParseFallback()
 	}
+	return runtimeSchemaPreflightFileErrors(file)
+}
+
+// runtimeSchemaPreflightFileErrors reports runtime fields that are invalid for
+// the declared runtime before schema unification turns them into opaque
+// disjunction errors.
+func runtimeSchemaPreflightFileErrors(file *ast.File) ValidationErrors {
 	cmds := fieldList(fieldStruct(file.Decls), "cmds")
 	var errs ValidationErrors
 	for cmdIdx, cmd := range cmds {
@@ -73,15 +96,19 @@ func validateRuntimePreflight(runtime *ast.StructLit, path string) ValidationErr
 		return validateVirtualRuntimePreflight(runtime, path, RuntimeVirtualSh)
 	case string(RuntimeVirtualLua):
 		return validateVirtualRuntimePreflight(runtime, path, RuntimeVirtualLua)
+	case string(RuntimeVirtualStarlark):
+		return validateVirtualRuntimePreflight(runtime, path, RuntimeVirtualStarlark)
 	case string(RuntimeContainer):
 		return validateContainerRuntimePreflight(runtime, path)
+	case string(RuntimeRemoteSSH):
+		return validateRemoteSSHRuntimePreflight(runtime, path)
 	default:
 		return nil
 	}
 }
 
 //goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
-func validateNonContainerRuntimePreflight(runtime *ast.StructLit, path string, _ RuntimeMode) ValidationErrors {
+func validateNonContainerRuntimePreflight(runtime *ast.StructLit, path string, mode RuntimeMode) ValidationErrors {
 	var errs ValidationErrors
 	for field := range nonContainerRuntimeFields {
 		if hasField(runtime, field) {
@@ -91,6 +118,57 @@ func validateNonContainerRuntimePreflight(runtime *ast.StructLit, path string, _
 			))
 		}
 	}
+	if mode != RuntimeRemoteSSH {
+		errs = append(errs, validateNonRemoteSSHRuntimePreflight(runtime, path)...)
+	}
+	if mode != RuntimeNative {
+		errs = append(errs, validateNonNativeRuntimePreflight(runtime, path)...)
+	}
+	if mode != RuntimeVirtualStarlark {
+		errs = append(errs, validateNonStarlarkRuntimePreflight(runtime, path)...)
+	}
+	return errs
+}
+
+//goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
+func validateNonStarlarkRuntimePreflight(runtime *ast.StructLit, path string) ValidationErrors {
+	var errs ValidationErrors
+	for field := range nonStarlarkRuntimeFields {
+		if hasField(runtime, field) {
+			errs = append(errs, runtimePreflightError(
+				path+"."+field,
+				field+" is only valid for virtual-starlark runtime",
+			))
+		}
+	}
+	return errs
+}
+
+//goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
+func validateNonNativeRuntimePreflight(runtime *ast.StructLit, path string) ValidationErrors {
+	var errs ValidationErrors
+	for field := range nonNativeRuntimeFields {
+		if hasField(runtime, field) {
+			errs = append(errs, runtimePreflightError(
+				path+"."+field,
+				field+" is only valid for native runtime",
+			))
+		}
+	}
+	return errs
+}
+
+//goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
+func validateNonRemoteSSHRuntimePreflight(runtime *ast.StructLit, path string) ValidationErrors {
+	var errs ValidationErrors
+	for field := range nonRemoteSSHRuntimeFields {
+		if hasField(runtime, field) {
+			errs = append(errs, runtimePreflightError(
+				path+"."+field,
+				field+" is only valid for remote-ssh runtime",
+			))
+		}
+	}
 	return errs
 }
 
@@ -134,7 +212,9 @@ func validateVirtualRuntimePreflight(runtime *ast.StructLit, path string, mode R
 
 //goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
 func validateContainerRuntimePreflight(runtime *ast.StructLit, path string) ValidationErrors {
-	var errs ValidationErrors
+	errs := validateNonRemoteSSHRuntimePreflight(runtime, path)
+	errs = append(errs, validateNonNativeRuntimePreflight(runtime, path)...)
+	errs = append(errs, validateNonStarlarkRuntimePreflight(runtime, path)...)
 	for field := range nonVirtualRuntimeFields {
 		if hasField(runtime, field) {
 			errs = append(errs, runtimePreflightError(
@@ -169,6 +249,31 @@ func validateContainerRuntimePreflight(runtime *ast.StructLit, path string) Vali
 	}
 }
 
+//goplint:ignore -- AST preflight helper builds display-only validation paths from parsed CUE syntax.
+func validateRemoteSSHRuntimePreflight(runtime *ast.StructLit, path string) ValidationErrors {
+	errs := validateNonContainerRuntimePreflight(runtime, path, RuntimeRemoteSSH)
+	for field := range nonVirtualRuntimeFields {
+		if hasField(runtime, field) {
+			errs = append(errs, runtimePreflightError(
+				path+"."+field,
+				field+" is only valid for virtual runtimes",
+			))
+		}
+	}
+	for field := range nonLuaRuntimeFields {
+		if hasField(runtime, field) {
+			errs = append(errs, runtimePreflightError(
+				path+"."+field,
+				field+luaRuntimeOnlyMsg,
+			))
+		}
+	}
+	if !hasField(runtime, "host") {
+		errs = append(errs, runtimePreflightError(path, "remote-ssh runtime requires host"))
+	}
+	return errs
+}
+
 func runtimePreflightParseFallback() ValidationErrors {
 	return nil
 }
@@ -37,6 +37,30 @@ func TestParseBytesRuntimePreflightDiagnostics(t *testing.T) {
 			wantField:   "cmds[0].implementations[0].runtimes[0].image",
 			wantMessage: "image and containerfile are mutually exclusive",
 		},
+		{
+			name:        "native rejects remote-ssh field",
+			runtime:     `{name: "native", host: "build-01"}`,
+			wantField:   "cmds[0].implementations[0].runtimes[0].host",
+			wantMessage: "host is only valid for remote-ssh runtime",
+		},
+		{
+			name:        "virtual-lua rejects starlark field",
+			runtime:     `{name: "virtual-lua", max_steps: 1000}`,
+			wantField:   "cmds[0].implementations[0].runtimes[0].max_steps",
+			wantMessage: "max_steps is only valid for virtual-starlark runtime",
+		},
+		{
+			name:        "remote-ssh requires host",
+			runtime:     `{name: "remote-ssh", user: "deploy"}`,
+			wantField:   "cmds[0].implementations[0].runtimes[0]",
+			wantMessage: "remote-ssh runtime requires host",
+		},
+		{
+			name:        "container rejects native sandbox",
+			runtime:     `{name: "container", image: "debian:stable-slim", sandbox: {}}`,
+			wantField:   "cmds[0].implementations[0].runtimes[0].sandbox",
+			wantMessage: "sandbox is only valid for native runtime",
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,6 +55,32 @@ func TestPlatformConfig_Validate_InvalidName(t *testing.T) {
 	}
 }
 
+func TestPlatformConfig_Validate_InvalidArch(t *testing.T) {
+	t.Parallel()
+	pc := PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchARM64, "mips"}}
+	var pcErr *InvalidPlatformConfigError
+	if !errors.As(pc.Validate(), &pcErr) {
+		t.Fatal("PlatformConfig with invalid arch should fail with *InvalidPlatformConfigError")
+	}
+	if len(pcErr.FieldErrors) != 1 || !errors.Is(pcErr.FieldErrors[0], ErrInvalidArchitecture) {
+		t.Errorf("FieldErrors = %v, want one ErrInvalidArchitecture", pcErr.FieldErrors)
+	}
+}
+
+func TestPlatformConfig_MatchesArch(t *testing.T) {
+	t.Parallel()
+	if !(PlatformConfig{Name: PlatformLinux}).MatchesArch(ArchRISCV64) {
+		t.Error("PlatformConfig without arch should match every architecture")
+	}
+	restricted := PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchAMD64, ArchARM64}}
+	if !restricted.MatchesArch(ArchARM64) {
+		t.Error("MatchesArch(arm64) = false, want true")
+	}
+	if restricted.MatchesArch(Arch386) || restricted.MatchesArch("") {
+		t.Error("MatchesArch() should reject architectures outside the list")
+	}
+}
+
 func TestInvalidPlatformConfigError_ErrorMessage(t *testing.T) {
 	t.Parallel()
 	e := &InvalidPlatformConfigError{FieldErrors: []error{errors.New("x")}}
@@ -151,6 +177,56 @@ func TestRuntimeConfig_Validate_RuntimeSpecificInvariants(t *testing.T) {
 			},
 			wantErr: "containerfile and image are mutually exclusive",
 		},
+		{
+			name: "native rejects remote-ssh fields",
+			config: RuntimeConfig{
+				Name:    RuntimeNative,
+				Host:    "build-01",
+				WorkDir: "/srv/app",
+			},
+			wantErr: "host is only valid for remote-ssh runtime",
+		},
+		{
+			name: "virtual-lua rejects max_steps",
+			config: RuntimeConfig{
+				Name:     RuntimeVirtualLua,
+				MaxSteps: 1000,
+			},
+			wantErr: "max_steps is only valid for virtual-starlark runtime",
+		},
+		{
+			name: "virtual-starlark rejects lua limits",
+			config: RuntimeConfig{
+				Name:     RuntimeVirtualStarlark,
+				CPULimit: 1000,
+			},
+			wantErr: "cpu_limit is only valid for virtual-lua runtime",
+		},
+		{
+			name: "remote-ssh requires host",
+			config: RuntimeConfig{
+				Name: RuntimeRemoteSSH,
+				User: "deploy",
+			},
+			wantErr: "remote-ssh runtime requires host",
+		},
+		{
+			name: "remote-ssh rejects container fields",
+			config: RuntimeConfig{
+				Name:  RuntimeRemoteSSH,
+				Host:  "build-01",
+				Image: "debian:stable-slim",
+			},
+			wantErr: "image is only valid for container runtime",
+		},
+		{
+			name: "virtual-sh rejects sandbox",
+			config: RuntimeConfig{
+				Name:    RuntimeVirtualSh,
+				Sandbox: &RuntimeSandboxConfig{},
+			},
+			wantErr: "sandbox is only valid for native runtime",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// SecretProviderCommand reads the secret from the stdout of an external
+	// command (e.g., `pass show deploy/token`).
+	SecretProviderCommand SecretProviderType = "command"
+	// SecretProviderFile reads the secret from a file.
+	SecretProviderFile SecretProviderType = "file"
+	// SecretProviderEnv reads the secret from a host environment variable.
+	SecretProviderEnv SecretProviderType = "env"
+	// SecretProviderKeyring reads the secret from the OS keyring through the
+	// platform helper binary (secret-tool on Linux, security on macOS).
+	SecretProviderKeyring SecretProviderType = "keyring"
+
+	// MaxSecretKeyringFieldLength is the maximum length, in runes, of a
+	// keyring service or account name.
+	MaxSecretKeyringFieldLength = 256
+)
+
+var (
+	// ErrInvalidSecretProvider is the sentinel error wrapped by InvalidSecretProviderError.
+	ErrInvalidSecretProvider = errors.New("invalid secret provider")
+
+	// ErrInvalidSecretSource is the sentinel error wrapped by InvalidSecretSourceError.
+	ErrInvalidSecretSource = errors.New("invalid secret source")
+)
+
+type (
+	// SecretProviderType selects where a secret env var value is read from.
+	//
+	//goplint:enum-cue=#SecretProviderType
+	SecretProviderType string
+
+	// InvalidSecretProviderError is returned when a SecretProviderType is not a
+	// known provider.
+	InvalidSecretProviderError struct {
+		Value SecretProviderType
+	}
+
+	// InvalidSecretSourceError is returned when a SecretSource has invalid fields.
+	// It wraps ErrInvalidSecretSource for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidSecretSourceError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// SecretSource declares how the value of a secret env var is resolved at
+	// execution time. Secret values are never stored in the invowkfile, and
+	// resolved values are masked in command output.
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	SecretSource struct {
+		// Provider selects where the value is read from.
+		Provider SecretProviderType `json:"provider"`
+		// Command is the argv of the external command printing the secret
+		// ("command" provider). One trailing newline is trimmed.
+		Command []string `json:"command,omitempty"`
+		// Path is the file holding the secret ("file" provider). Relative paths
+		// are resolved against the invowkfile directory (or module root).
+		// One trailing newline is trimmed.
+		Path FilesystemPath `json:"path,omitempty"`
+		// Var is the host environment variable holding the secret ("env" provider).
+		Var EnvVarName `json:"var,omitempty"`
+		// Service is the keyring service name ("keyring" provider).
+		Service string `json:"service,omitempty"`
+		// Account is the keyring account name ("keyring" provider).
+		Account string `json:"account,omitempty"`
+	}
+)
+
+// Error implements the error interface.
+func (e *InvalidSecretProviderError) Error() string {
+	return fmt.Sprintf("invalid secret provider %q (must be command, file, env, or keyring)", e.Value)
+}
+
+// Unwrap returns ErrInvalidSecretProvider so callers can use errors.Is for programmatic detection.
+func (e *InvalidSecretProviderError) Unwrap() error { return ErrInvalidSecretProvider }
+
+// Validate returns nil if the SecretProviderType is a known provider.
+//
+//goplint:nonzero
+func (p SecretProviderType) Validate() error {
+	switch p {
+	case SecretProviderCommand, SecretProviderFile, SecretProviderEnv, SecretProviderKeyring:
+		return nil
+	default:
+		return &InvalidSecretProviderError{Value: p}
+	}
+}
+
+// String returns the string representation of the SecretProviderType.
+func (p SecretProviderType) String() string { return string(p) }
+
+// Validate returns nil if the SecretSource has a known provider and exactly
+// the fields that provider uses, or an error collecting all field-level
+// validation failures.
+func (s SecretSource) Validate() error {
+	var errs []error
+	appendFieldError(&errs, s.Provider.Validate())
+	errs = append(errs, s.providerFieldErrors()...)
+	if len(errs) > 0 {
+		return &InvalidSecretSourceError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidSecretSourceError.
+func (e *InvalidSecretSourceError) Error() string {
+	return types.FormatFieldErrors("secret source", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidSecretSource and field errors for errors.Is() compatibility.
+func (e *InvalidSecretSourceError) Unwrap() error {
+	return errors.Join(ErrInvalidSecretSource, errors.Join(e.FieldErrors...))
+}
+
+// providerFieldErrors checks that the fields required by the provider are set
+// and that fields used by other providers are not.
+func (s SecretSource) providerFieldErrors() []error {
+	var errs []error
+	uses := func(provider SecretProviderType, field string, set bool) {
+		switch {
+		case s.Provider == provider && !set:
+			errs = append(errs, fmt.Errorf("secret provider %q requires %s", provider, field))
+		case s.Provider != provider && set:
+			errs = append(errs, fmt.Errorf("secret %s requires provider %q", field, provider))
+		}
+	}
+	uses(SecretProviderCommand, "command", len(s.Command) > 0)
+	uses(SecretProviderFile, "path", s.Path != "")
+	uses(SecretProviderEnv, "var", s.Var != "")
+	uses(SecretProviderKeyring, "service", s.Service != "")
+	if s.Account != "" && s.Provider != SecretProviderKeyring {
+		errs = append(errs, fmt.Errorf("secret account requires provider %q", SecretProviderKeyring))
+	}
+
+	for _, arg := range s.Command {
+		if arg == "" {
+			errs = append(errs, errors.New("secret command arguments must not be empty"))
+			break
+		}
+	}
+	if len(s.Command) > 0 && strings.TrimSpace(s.Command[0]) == "" {
+		errs = append(errs, errors.New("secret command program must not be blank"))
+	}
+	if s.Path != "" {
+		if strings.ContainsRune(string(s.Path), '\x00') {
+			errs = append(errs, errors.New("secret path contains null byte"))
+		} else if len(s.Path) > MaxPathLength {
+			errs = append(errs, fmt.Errorf("secret path too long (%d chars, max %d)", len(s.Path), MaxPathLength))
+		}
+	}
+	if s.Var != "" {
+		appendFieldError(&errs, s.Var.Validate())
+	}
+	if utf8.RuneCountInString(s.Service) > MaxSecretKeyringFieldLength || utf8.RuneCountInString(s.Account) > MaxSecretKeyringFieldLength {
+		errs = append(errs, fmt.Errorf("secret keyring service and account must be at most %d runes", MaxSecretKeyringFieldLength))
+	}
+	return errs
+}
+
+// SecretNames returns the sorted names of the secrets declared at the root,
+// command, and implementation levels. Any of the configs may be nil.
+func SecretNames(envs ...*EnvConfig) []EnvVarName {
+	names := make(map[EnvVarName]struct{})
+	for _, env := range envs {
+		for name := range env.GetSecrets() {
+			names[name] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
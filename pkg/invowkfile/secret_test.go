@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSecretSourceValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  SecretSource
+		wantErr string
+	}{
+		{name: "command", source: SecretSource{Provider: SecretProviderCommand, Command: []string{"pass", "show", "token"}}},
+		{name: "file", source: SecretSource{Provider: SecretProviderFile, Path: ".secrets/token"}},
+		{name: "env", source: SecretSource{Provider: SecretProviderEnv, Var: "GITHUB_TOKEN"}},
+		{name: "keyring without account", source: SecretSource{Provider: SecretProviderKeyring, Service: "invowk"}},
+		{name: "unknown provider", source: SecretSource{Provider: "vault"}, wantErr: "invalid secret provider"},
+		{name: "command missing", source: SecretSource{Provider: SecretProviderCommand}, wantErr: `provider "command" requires command`},
+		{name: "blank program", source: SecretSource{Provider: SecretProviderCommand, Command: []string{" ", "x"}}, wantErr: "program must not be blank"},
+		{name: "empty argument", source: SecretSource{Provider: SecretProviderCommand, Command: []string{"pass", ""}}, wantErr: "arguments must not be empty"},
+		{name: "path on env provider", source: SecretSource{Provider: SecretProviderEnv, Var: "TOKEN", Path: "token"}, wantErr: `secret path requires provider "file"`},
+		{name: "invalid var", source: SecretSource{Provider: SecretProviderEnv, Var: "1TOKEN"}, wantErr: "invalid environment variable name"},
+		{name: "account on file provider", source: SecretSource{Provider: SecretProviderFile, Path: "token", Account: "me"}, wantErr: `secret account requires provider "keyring"`},
+		{name: "keyring missing service", source: SecretSource{Provider: SecretProviderKeyring, Account: "me"}, wantErr: `provider "keyring" requires service`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.source.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidSecretSource) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidSecretSource", err)
+			}
+			var sourceErr *InvalidSecretSourceError
+			if !errors.As(err, &sourceErr) || !fieldErrorsContain(sourceErr.FieldErrors, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want a field error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnvConfigValidateSecrets(t *testing.T) {
+	t.Parallel()
+
+	env := EnvConfig{
+		Vars: map[EnvVarName]string{"TOKEN": "plain"},
+		Secrets: map[EnvVarName]SecretSource{
+			"TOKEN": {Provider: SecretProviderEnv, Var: "GITHUB_TOKEN"},
+			"KEY":   {Provider: SecretProviderFile},
+		},
+	}
+	err := env.Validate()
+	var envErr *InvalidEnvConfigError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("Validate() error = %v, want InvalidEnvConfigError", err)
+	}
+	if !fieldErrorsContain(envErr.FieldErrors, "TOKEN is declared in both vars and secrets") {
+		t.Errorf("Validate() field errors = %v, want vars/secrets conflict", envErr.FieldErrors)
+	}
+	if !fieldErrorsContain(envErr.FieldErrors, "secret KEY") {
+		t.Errorf("Validate() field errors = %v, want invalid source for KEY", envErr.FieldErrors)
+	}
+}
+
+func TestSecretNames(t *testing.T) {
+	t.Parallel()
+
+	root := &EnvConfig{Secrets: map[EnvVarName]SecretSource{"B": {Provider: SecretProviderEnv, Var: "B"}}}
+	cmd := &EnvConfig{Secrets: map[EnvVarName]SecretSource{"A": {Provider: SecretProviderEnv, Var: "A"}, "B": {Provider: SecretProviderEnv, Var: "C"}}}
+	if got := SecretNames(root, nil, cmd); !slices.Equal(got, []EnvVarName{"A", "B"}) {
+		t.Fatalf("SecretNames() = %v, want [A B]", got)
+	}
+}
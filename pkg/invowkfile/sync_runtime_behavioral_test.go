@@ -12,7 +12,7 @@ import (
 // =============================================================================
 
 // TestBehavioralSync_RuntimeMode verifies Go RuntimeMode.Validate() agrees with
-// CUE #RuntimeType disjunction ("native" | "virtual-sh" | "virtual-lua" | "container").
+// CUE #RuntimeType disjunction ("native" | "virtual-sh" | "virtual-lua" | "virtual-starlark" | "container" | "remote-ssh").
 func TestBehavioralSync_RuntimeMode(t *testing.T) {
 	t.Parallel()
 	schema, ctx := getCUESchema(t)
@@ -23,8 +23,12 @@ func TestBehavioralSync_RuntimeMode(t *testing.T) {
 			{"native", true, true, ""},
 			{"virtual-sh", true, true, ""},
 			{"virtual-lua", true, true, ""},
+			{"virtual-starlark", true, true, ""},
+			{"starlark", false, false, ""},
 			{"virtual", false, false, ""},
 			{"container", true, true, ""},
+			{"remote-ssh", true, true, ""},
+			{"ssh", false, false, ""},
 			{"invalid", false, false, ""},
 			{"NATIVE", false, false, ""},
 			{"", false, false, ""},
@@ -75,6 +79,24 @@ func TestBehavioralSync_EnvInheritMode(t *testing.T) {
 	)
 }
 
+// TestBehavioralSync_SandboxNetwork verifies Go SandboxNetwork.Validate() agrees with
+// CUE #SandboxNetwork disjunction ("none" | "host").
+func TestBehavioralSync_SandboxNetwork(t *testing.T) {
+	t.Parallel()
+	schema, ctx := getCUESchema(t)
+
+	runBehavioralSync(t, schema, ctx, "#SandboxNetwork",
+		func(s string) error { return SandboxNetwork(s).Validate() },
+		[]behavioralSyncCase{
+			{"none", true, true, ""},
+			{"host", true, true, ""},
+			{"bridge", false, false, ""},
+			{"HOST", false, false, ""},
+			{"", true, false, "Go zero value means the none default; CUE omits the field instead"},
+		},
+	)
+}
+
 // TestBehavioralSync_ContainerImage verifies Go ContainerImage.Validate() agrees with
 // CUE #RuntimeConfigContainerWithImage.image constraint (non-empty + length).
 // Note: ContainerImage("") is valid in Go (no image = use containerfile),
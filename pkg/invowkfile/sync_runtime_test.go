@@ -18,7 +18,7 @@ import (
 
 // TestRuntimeConfigSchemaSync verifies RuntimeConfig Go struct matches CUE runtime definitions.
 //
-// Note: The CUE schema uses a union type (#RuntimeConfig = #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigContainer)
+// Note: The CUE schema uses a union type (#RuntimeConfig = #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigVirtualStarlark | #RuntimeConfigContainer | #RuntimeConfigRemoteSSH)
 // while Go uses a single RuntimeConfig struct with all fields. We need to extract the union of all fields
 // from the runtime variants, including the container source variants. This requires custom merge logic, so it
 // remains a separate test.
@@ -36,7 +36,7 @@ func runtimeConfigCUEFields(t *testing.T, schema cue.Value) map[string]bool {
 	t.Helper()
 
 	allFields := extractRuntimeConfigFields(t, schema, "#RuntimeConfigNative")
-	for _, definition := range []string{"#RuntimeConfigVirtualSh", "#RuntimeConfigVirtualLua"} {
+	for _, definition := range []string{"#RuntimeConfigVirtualSh", "#RuntimeConfigVirtualLua", "#RuntimeConfigVirtualStarlark", "#RuntimeConfigRemoteSSH"} {
 		mergeRuntimeConfigFields(allFields, extractRuntimeConfigFields(t, schema, definition), false)
 	}
 	for _, definition := range []string{"#RuntimeConfigContainerWithImage", "#RuntimeConfigContainerWithContainerfile"} {
@@ -516,6 +516,41 @@ cmds: [{
 	}
 }
 
+func TestNativeSandboxConfigConstraints(t *testing.T) {
+	t.Parallel()
+
+	withRuntime := func(runtime string) string {
+		return `
+cmds: [{
+	name: "test"
+	implementations: [{
+		script: {content: "echo hello"}
+		runtimes: [` + runtime + `]
+		platforms: [{name: "linux"}]
+	}]
+}]`
+	}
+
+	for _, runtime := range []string{
+		`{name: "native", sandbox: {}}`,
+		`{name: "native", sandbox: {network: "none"}}`,
+		`{name: "native", sandbox: {network: "host"}}`,
+	} {
+		if err := validateCUE(t, withRuntime(runtime)); err != nil {
+			t.Errorf("%s should pass, got error: %v", runtime, err)
+		}
+	}
+	for _, runtime := range []string{
+		`{name: "native", sandbox: {network: "bridge"}}`,
+		`{name: "native", sandbox: {writable: ["/tmp"]}}`,
+		`{name: "virtual-sh", sandbox: {}}`,
+	} {
+		if validateCUE(t, withRuntime(runtime)) == nil {
+			t.Errorf("%s should fail validation", runtime)
+		}
+	}
+}
+
 // TestCustomCheckNameLengthConstraint verifies #CustomCheck.name has a 256 rune limit.
 func TestCustomCheckNameLengthConstraint(t *testing.T) {
 	t.Parallel()
@@ -85,7 +85,14 @@ func TestSchemaSync(t *testing.T) {
 		goType reflect.Type
 	}{
 		{"#Invowkfile", reflect.TypeFor[Invowkfile]()},
+		{"#InvowkfileFragment", reflect.TypeFor[invowkfileFragment]()},
 		{"#Command", reflect.TypeFor[Command]()},
+		{"#Deprecation", reflect.TypeFor[Deprecation]()},
+		{"#ConfirmConfig", reflect.TypeFor[ConfirmConfig]()},
+		{"#CommandLock", reflect.TypeFor[CommandLock]()},
+		{"#CommandMatrix", reflect.TypeFor[CommandMatrix]()},
+		{"#CommandOutput", reflect.TypeFor[CommandOutput]()},
+		{"#FlagGroups", reflect.TypeFor[FlagGroups]()},
 		{"#Implementation", reflect.TypeFor[Implementation]()},
 		{"#DependsOn", reflect.TypeFor[DependsOn]()},
 		{"#Flag", reflect.TypeFor[Flag]()},
@@ -102,6 +109,13 @@ func TestSchemaSync(t *testing.T) {
 		{"#EnvVarDependency", reflect.TypeFor[EnvVarDependency]()},
 		{"#CustomCheck", reflect.TypeFor[CustomCheck]()},
 		{"#WatchConfig", reflect.TypeFor[WatchConfig]()},
+		{"#Hooks", reflect.TypeFor[Hooks]()},
+		{"#Hook", reflect.TypeFor[Hook]()},
+		{"#RetryPolicy", reflect.TypeFor[RetryPolicy]()},
+		{"#PromptConfig", reflect.TypeFor[PromptConfig]()},
+		{"#CompletionConfig", reflect.TypeFor[CompletionConfig]()},
+		{"#SecretSource", reflect.TypeFor[SecretSource]()},
+		{"#When", reflect.TypeFor[When]()},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+
+	"github.com/invowk/invowk/pkg/cueutil"
+	"github.com/invowk/invowk/pkg/invowkmod"
+)
+
+const (
+	templateValidatorName ValidatorName = "templates"
+
+	templatesField = "templates"
+	extendsField   = "extends"
+)
+
+var (
+	// templateNameRegex matches the CUE constraint for #TemplateName.
+	templateNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+	// ErrInvalidTemplateName is the sentinel error wrapped by InvalidTemplateNameError.
+	ErrInvalidTemplateName = errors.New("invalid template name")
+
+	// ErrInvalidTemplateRef is the sentinel error wrapped by InvalidTemplateRefError.
+	ErrInvalidTemplateRef = errors.New("invalid template reference")
+)
+
+type (
+	// TemplateName identifies a template in an invowkfile's templates map.
+	TemplateName string
+
+	// InvalidTemplateNameError is returned when a TemplateName does not match
+	// the template name grammar.
+	InvalidTemplateNameError struct {
+		Value TemplateName
+	}
+
+	// TemplateRef names the template a command or implementation extends:
+	// either a template of the same invowkfile ("container") or a template
+	// exported by a module ("@io.example.tools container").
+	TemplateRef string
+
+	// InvalidTemplateRefError is returned when a TemplateRef is malformed.
+	InvalidTemplateRefError struct {
+		Value  TemplateRef
+		Reason string
+	}
+
+	// Template is a reusable command or implementation fragment, kept as the
+	// JSON it decoded from. Templates are merged into the commands and
+	// implementations extending them while the invowkfile is parsed, so the
+	// parsed commands never need to consult them.
+	Template = json.RawMessage
+
+	// templateExpander merges templates into the commands and implementations
+	// of a parsed invowkfile AST.
+	templateExpander struct {
+		local map[TemplateName]*ast.StructLit
+		// dependencyDir holds the <module-id>.invowkmod directories whose
+		// templates can be extended with module-qualified references.
+		dependencyDir string
+		// moduleDirs maps the IDs of the other discovered modules the file
+		// may reach to their module directories.
+		moduleDirs map[invowkmod.ModuleID]FilesystemPath
+		modules    map[invowkmod.ModuleID]map[TemplateName]*ast.StructLit
+		// inherited holds the paths of the fields the last expanded file took
+		// from templates, e.g. "cmds[0].implementations[1].workdir".
+		inherited map[string]bool
+		errs      ValidationErrors
+	}
+)
+
+// Validate returns nil if the TemplateName matches the template name grammar.
+func (n TemplateName) Validate() error {
+	if utf8.RuneCountInString(string(n)) > MaxNameLength || !templateNameRegex.MatchString(string(n)) {
+		return &InvalidTemplateNameError{Value: n}
+	}
+	return nil
+}
+
+// String returns the string representation of the TemplateName.
+func (n TemplateName) String() string { return string(n) }
+
+// Error implements the error interface.
+func (e *InvalidTemplateNameError) Error() string {
+	return fmt.Sprintf("invalid template name %q (must start with a letter and contain only letters, digits, underscores, or hyphens)", e.Value)
+}
+
+// Unwrap returns ErrInvalidTemplateName for errors.Is() compatibility.
+func (e *InvalidTemplateNameError) Unwrap() error { return ErrInvalidTemplateName }
+
+// Validate returns nil if the TemplateRef is a template name, optionally
+// qualified with "@<module-id> ".
+func (r TemplateRef) Validate() error {
+	if r.IsQualified() {
+		module, name, ok := strings.Cut(strings.TrimPrefix(string(r), "@"), " ")
+		if !ok {
+			return &InvalidTemplateRefError{Value: r, Reason: "qualified references must use @module template"}
+		}
+		if err := invowkmod.ModuleID(module).Validate(); err != nil {
+			return &InvalidTemplateRefError{Value: r, Reason: err.Error()}
+		}
+		if err := TemplateName(name).Validate(); err != nil {
+			return &InvalidTemplateRefError{Value: r, Reason: err.Error()}
+		}
+		return nil
+	}
+	if err := TemplateName(r).Validate(); err != nil {
+		return &InvalidTemplateRefError{Value: r, Reason: err.Error()}
+	}
+	return nil
+}
+
+// String returns the string representation of the TemplateRef.
+func (r TemplateRef) String() string { return string(r) }
+
+// IsQualified reports whether the reference names a module-exported template.
+func (r TemplateRef) IsQualified() bool { return strings.HasPrefix(string(r), "@") }
+
+// Module returns the module ID of a qualified reference, or "" for a local one.
+func (r TemplateRef) Module() invowkmod.ModuleID {
+	if !r.IsQualified() {
+		return ""
+	}
+	module, _, _ := strings.Cut(strings.TrimPrefix(string(r), "@"), " ")
+	return invowkmod.ModuleID(module) //goplint:ignore -- validated by TemplateRef.Validate before use.
+}
+
+// Name returns the referenced template name.
+func (r TemplateRef) Name() TemplateName {
+	if !r.IsQualified() {
+		return TemplateName(r) //goplint:ignore -- validated by TemplateRef.Validate before use.
+	}
+	_, name, _ := strings.Cut(string(r), " ")
+	return TemplateName(name) //goplint:ignore -- validated by TemplateRef.Validate before use.
+}
+
+// Error implements the error interface.
+func (e *InvalidTemplateRefError) Error() string {
+	return fmt.Sprintf("invalid template reference %q: %s", e.Value, e.Reason)
+}
+
+// Unwrap returns ErrInvalidTemplateRef for errors.Is() compatibility.
+func (e *InvalidTemplateRefError) Unwrap() error { return ErrInvalidTemplateRef }
+
+// expandTemplates deep-merges the templates named by extends fields into
+// their commands and implementations. Fields declared by the command or
+// implementation win; nested structs are merged field by field, while lists
+// and scalars are taken whole. Commands are expanded before their
+// implementations, so implementations inherited from a command template can
+// extend implementation templates. Merged nodes keep the template's source
+// positions, so schema errors point at the template that introduced them.
+//
+//goplint:ignore -- CUE AST boundary receives the display path of the dependency directory.
+func expandTemplates(file *ast.File, dependencyDir string) ValidationErrors {
+	return newTemplateExpander(dependencyDir, nil).expand(file)
+}
+
+// newTemplateExpander creates an expander resolving module-qualified
+// references against the modules in dependencyDir, then against moduleDirs.
+//
+//goplint:ignore -- CUE AST boundary receives the display path of the dependency directory.
+func newTemplateExpander(dependencyDir string, moduleDirs map[invowkmod.ModuleID]FilesystemPath) *templateExpander {
+	return &templateExpander{dependencyDir: dependencyDir, moduleDirs: moduleDirs}
+}
+
+// expand merges templates into the commands and implementations of file, as
+// described by expandTemplates. The templates declared by every file the
+// expander has seen stay available, so the fragments imported by an
+// invowkfile can extend the invowkfile's templates.
+func (e *templateExpander) expand(file *ast.File) ValidationErrors {
+	root := fieldStruct(file.Decls)
+	e.errs, e.inherited = nil, nil
+	if declared := e.collect(root, templatesField); len(declared) > 0 {
+		if e.local == nil {
+			e.local = make(map[TemplateName]*ast.StructLit, len(declared))
+		}
+		maps.Copy(e.local, declared)
+	}
+	if len(e.local) == 0 && !declaresExtends(root) {
+		return nil
+	}
+
+	for cmdIdx, cmd := range fieldList(root, "cmds") {
+		cmdPath := fmt.Sprintf("cmds[%d]", cmdIdx)
+		e.extend(cmd, cmdPath)
+		for implIdx, impl := range fieldList(cmd, "implementations") {
+			e.extend(impl, fmt.Sprintf("%s.implementations[%d]", cmdPath, implIdx))
+		}
+	}
+	if len(e.errs) > 0 {
+		return e.errs
+	}
+
+	// Cloned identifiers must be resolved in their new scope.
+	astutil.Resolve(file, func(token.Pos, string, ...any) {})
+	return nil
+}
+
+// templateDependencyDir returns the directory holding the modules an
+// invowkfile can extend templates from: the vendored dependencies of a module,
+// or the modules next to a root invowkfile.
+func templateDependencyDir(path string, modulePath FilesystemPath) string {
+	if modulePath != "" {
+		return string(invowkmod.GetVendoredModulesDir(modulePath))
+	}
+	return filepath.Dir(path)
+}
+
+// collect returns the templates declared by the struct field name of parent,
+// recording errors for malformed templates.
+//
+//goplint:ignore -- CUE AST helper accepts literal field names and display paths.
+func (e *templateExpander) collect(parent *ast.StructLit, name string) map[TemplateName]*ast.StructLit {
+	expr, ok := fieldExpr(parent, name)
+	if !ok {
+		return nil
+	}
+	list, ok := expr.(*ast.StructLit)
+	if !ok {
+		return nil
+	}
+	templates := make(map[TemplateName]*ast.StructLit, len(list.Elts))
+	for _, decl := range list.Elts {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		label, _, err := ast.LabelName(field.Label)
+		if err != nil {
+			continue
+		}
+		path := name + "." + label
+		body, ok := field.Value.(*ast.StructLit)
+		if !ok {
+			e.errs = append(e.errs, templateError(path, "template must be a struct"))
+			continue
+		}
+		if hasField(body, extendsField) {
+			e.errs = append(e.errs, templateError(path+"."+extendsField, "templates cannot extend other templates"))
+			continue
+		}
+		templates[TemplateName(label)] = body //goplint:ignore -- template names are validated by the schema after expansion.
+	}
+	return templates
+}
+
+// extend merges the template target extends, if any, into target.
+//
+//goplint:ignore -- CUE AST helper builds display-only validation paths.
+func (e *templateExpander) extend(target *ast.StructLit, path string) {
+	raw, ok := fieldString(target, extendsField)
+	if !ok {
+		return
+	}
+	extendsPath := path + "." + extendsField
+	ref := TemplateRef(raw)
+	if err := ref.Validate(); err != nil {
+		e.errs = append(e.errs, templateError(extendsPath, err.Error()))
+		return
+	}
+	template, err := e.lookup(ref)
+	if err != nil {
+		e.errs = append(e.errs, templateError(extendsPath, err.Error()))
+		return
+	}
+	e.mergeTemplate(target, template, path)
+}
+
+// lookup resolves a validated template reference.
+func (e *templateExpander) lookup(ref TemplateRef) (*ast.StructLit, error) {
+	if !ref.IsQualified() {
+		template, ok := e.local[ref.Name()]
+		if !ok {
+			return nil, fmt.Errorf("template '%s' is not declared in templates", ref.Name())
+		}
+		return template, nil
+	}
+
+	module := ref.Module()
+	templates, loaded := e.modules[module]
+	if !loaded {
+		var err error
+		templates, err = e.loadModule(module)
+		if err != nil {
+			return nil, err
+		}
+		if e.modules == nil {
+			e.modules = make(map[invowkmod.ModuleID]map[TemplateName]*ast.StructLit)
+		}
+		e.modules[module] = templates
+	}
+	template, ok := templates[ref.Name()]
+	if !ok {
+		return nil, fmt.Errorf("module '%s' does not export template '%s'", module, ref.Name())
+	}
+	return template, nil
+}
+
+// loadModule parses the invowkfile of a dependency module and returns its
+// templates. The module is looked up in the dependency directory first, then
+// among the modules resolved by discovery.
+func (e *templateExpander) loadModule(module invowkmod.ModuleID) (map[TemplateName]*ast.StructLit, error) {
+	path := filepath.Join(e.dependencyDir, module.String()+invowkmod.ModuleSuffix, InvowkfileName+".cue")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		moduleDir, ok := e.moduleDirs[module]
+		if !ok {
+			return nil, fmt.Errorf("module '%s' is not available to provide templates (looked in %s and the discovered modules)", module, e.dependencyDir)
+		}
+		path = filepath.Join(string(moduleDir), InvowkfileName+".cue")
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("module '%s' templates: %w", module, err)
+		}
+	}
+	if err := cueutil.CheckFileSize(data, cueutil.DefaultMaxFileSize, path); err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(path, data, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("module '%s' templates: %w", module, cueutil.FormatError(err, path))
+	}
+	dependency := &templateExpander{}
+	templates := dependency.collect(fieldStruct(file.Decls), templatesField)
+	if len(dependency.errs) > 0 {
+		return nil, fmt.Errorf("module '%s' templates: %s", module, dependency.errs.Error())
+	}
+	return templates, nil
+}
+
+// templateError builds a parse-time validation error for a template field.
+//
+//goplint:ignore -- validation error fields are display-only diagnostics assembled from parser-owned state.
+func templateError(field, message string) ValidationError {
+	return ValidationError{
+		Validator: templateValidatorName,
+		Field:     field,
+		Message:   message,
+	}
+}
+
+// declaresExtends reports whether any command or implementation extends a template.
+func declaresExtends(root *ast.StructLit) bool {
+	for _, cmd := range fieldList(root, "cmds") {
+		if hasField(cmd, extendsField) {
+			return true
+		}
+		for _, impl := range fieldList(cmd, "implementations") {
+			if hasField(impl, extendsField) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeTemplate adds the fields of template missing from target, merging
+// fields that are structs on both sides recursively. The paths of the added
+// fields, below the target's path, are recorded as inherited.
+//
+//goplint:ignore -- CUE AST helper builds display-only field paths.
+func (e *templateExpander) mergeTemplate(target, template *ast.StructLit, path string) {
+	for _, decl := range template.Elts {
+		field, ok := decl.(*ast.Field)
+		if !ok {
+			continue
+		}
+		label, _, err := ast.LabelName(field.Label)
+		if err != nil {
+			continue
+		}
+		fieldPath := path + "." + label
+		existing, ok := fieldExpr(target, label)
+		if !ok {
+			target.Elts = append(target.Elts, cloneNode(field))
+			if e.inherited == nil {
+				e.inherited = make(map[string]bool)
+			}
+			e.inherited[fieldPath] = true
+			continue
+		}
+		targetStruct, targetIsStruct := existing.(*ast.StructLit)
+		templateStruct, templateIsStruct := field.Value.(*ast.StructLit)
+		if targetIsStruct && templateIsStruct {
+			e.mergeTemplate(targetStruct, templateStruct, fieldPath)
+		}
+	}
+}
+
+// cloneNode deep-copies the syntax a template contributes, so a template
+// extended several times never shares nodes between commands. Identifiers
+// lose their resolution and are resolved again after expansion.
+func cloneNode[T ast.Node](node T) T {
+	var cloned ast.Node
+	switch n := ast.Node(node).(type) {
+	case *ast.Field:
+		c := *n
+		c.Value = cloneNode(n.Value)
+		cloned = &c
+	case *ast.StructLit:
+		c := *n
+		c.Elts = make([]ast.Decl, len(n.Elts))
+		for i, elt := range n.Elts {
+			c.Elts[i] = cloneNode(elt)
+		}
+		cloned = &c
+	case *ast.ListLit:
+		c := *n
+		c.Elts = make([]ast.Expr, len(n.Elts))
+		for i, elt := range n.Elts {
+			c.Elts[i] = cloneNode(elt)
+		}
+		cloned = &c
+	case *ast.BasicLit:
+		c := *n
+		cloned = &c
+	case *ast.Ident:
+		c := *n
+		c.Node, c.Scope = nil, nil
+		cloned = &c
+	case *ast.UnaryExpr:
+		c := *n
+		c.X = cloneNode(n.X)
+		cloned = &c
+	case *ast.BinaryExpr:
+		c := *n
+		c.X, c.Y = cloneNode(n.X), cloneNode(n.Y)
+		cloned = &c
+	case *ast.ParenExpr:
+		c := *n
+		c.X = cloneNode(n.X)
+		cloned = &c
+	case *ast.Interpolation:
+		c := *n
+		c.Elts = make([]ast.Expr, len(n.Elts))
+		for i, elt := range n.Elts {
+			c.Elts[i] = cloneNode(elt)
+		}
+		cloned = &c
+	default:
+		// Other syntax (comprehensions, calls) is shared; templates are data.
+		return node
+	}
+	return cloned.(T) //nolint:forcetypeassert // every case clones a node of the input's dynamic type
+}
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/invowk/invowk/pkg/invowkmod"
+)
+
+const templatesInvowkfile = `
+templates: {
+	container: {
+		runtimes: [{name: "container", image: "debian:stable-slim", volumes: ["./cache:/cache"], env_inherit_mode: "allow", env_inherit_allow: ["CI"]}]
+		platforms: [{name: "linux"}]
+		env: vars: {MODE: "ci", LEVEL: "info"}
+	}
+	"go-service": {
+		description: "Go service command"
+		category: "build"
+		implementations: [{extends: "container", script: {content: "go build ./..."}}]
+	}
+}
+
+cmds: [
+	{
+		name: "build"
+		extends: "go-service"
+	},
+	{
+		name: "test"
+		extends: "go-service"
+		description: "Run tests"
+		implementations: [{
+			extends: "container"
+			script: {content: "go test ./..."}
+			platforms: [{name: "linux"}, {name: "macos"}]
+			env: vars: {LEVEL: "debug"}
+		}]
+	},
+]
+`
+
+func TestParseBytes_ExpandsTemplates(t *testing.T) {
+	t.Parallel()
+
+	inv, err := ParseBytes([]byte(templatesInvowkfile), "invowkfile.cue")
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+
+	build := inv.GetCommand("build")
+	if build.Description != "Go service command" || build.Category != "build" || build.Extends != "go-service" {
+		t.Errorf("build = %q/%q/%q, want the template description, category, and extends", build.Description, build.Category, build.Extends)
+	}
+	if len(build.Implementations) != 1 {
+		t.Fatalf("build implementations = %d, want 1 from the template", len(build.Implementations))
+	}
+	impl := build.Implementations[0]
+	if impl.Script.Content != "go build ./..." || impl.Extends != "container" {
+		t.Errorf("build implementation = %q extends %q, want the template script extending container", impl.Script.Content, impl.Extends)
+	}
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Image != "debian:stable-slim" || len(impl.Runtimes[0].Volumes) != 1 {
+		t.Errorf("build runtimes = %+v, want the container template runtime", impl.Runtimes)
+	}
+
+	test := inv.GetCommand("test")
+	if test.Description != "Run tests" || test.Category != "build" {
+		t.Errorf("test = %q/%q, want its own description and the template category", test.Description, test.Category)
+	}
+	testImpl := test.Implementations[0]
+	if testImpl.Script.Content != "go test ./..." || len(testImpl.Platforms) != 2 {
+		t.Errorf("test implementation = %q on %v, want its own script and platforms", testImpl.Script.Content, testImpl.Platforms)
+	}
+	if got := testImpl.Env.Vars; got["LEVEL"] != "debug" || got["MODE"] != "ci" {
+		t.Errorf("test env vars = %v, want nested structs merged with the implementation winning", got)
+	}
+	if len(inv.Templates) != 2 {
+		t.Errorf("Templates = %d, want 2 kept for export", len(inv.Templates))
+	}
+}
+
+func TestParseBytes_TemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cue     string
+		wantErr string
+	}{
+		{
+			name:    "unknown template",
+			cue:     `cmds: [{name: "build", extends: "missing"}]`,
+			wantErr: "cmds[0].extends: template 'missing' is not declared in templates",
+		},
+		{
+			name: "template extends template",
+			cue: `
+templates: {base: {description: "x"}, derived: {extends: "base"}}
+cmds: [{name: "build", extends: "derived"}]`,
+			wantErr: "templates.derived.extends: templates cannot extend other templates",
+		},
+		{
+			name:    "invalid reference",
+			cue:     `cmds: [{name: "build", extends: "@io.example tools base"}]`,
+			wantErr: `invalid template reference "@io.example tools base"`,
+		},
+		{
+			name:    "missing module",
+			cue:     `cmds: [{name: "build", extends: "@io.example.missing base"}]`,
+			wantErr: "module 'io.example.missing' is not available to provide templates",
+		},
+		{
+			name: "template field invalid for commands",
+			cue: `
+templates: {container: {runtimes: [{name: "native"}]}}
+cmds: [{name: "build", extends: "container", implementations: [{script: {content: "make"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]`,
+			wantErr: "runtimes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseBytes([]byte(tt.cue), filepath.Join(t.TempDir(), "invowkfile.cue"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseBytes() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_ExtendsModuleTemplates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	moduleDir := filepath.Join(dir, "io.example.tools.invowkmod")
+	if err := os.MkdirAll(moduleDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	moduleInvowkfile := `
+templates: container: {
+	runtimes: [{name: "container", image: "golang:1.26"}]
+	platforms: [{name: "linux"}]
+}
+cmds: [{name: "noop", implementations: [{script: {content: "true"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]
+`
+	if err := os.WriteFile(filepath.Join(moduleDir, "invowkfile.cue"), []byte(moduleInvowkfile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rootInvowkfile := `cmds: [{name: "build", implementations: [{extends: "@io.example.tools container", script: {content: "go build"}}]}]`
+	rootPath := filepath.Join(dir, "invowkfile.cue")
+	if err := os.WriteFile(rootPath, []byte(rootInvowkfile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := Parse(FilesystemPath(rootPath))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	impl := inv.Commands[0].Implementations[0]
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Image != "golang:1.26" {
+		t.Errorf("runtimes = %+v, want the module template runtime", impl.Runtimes)
+	}
+
+	_, err = ParseBytes([]byte(strings.Replace(rootInvowkfile, "container", "other", 1)), rootPath)
+	if err == nil || !strings.Contains(err.Error(), "module 'io.example.tools' does not export template 'other'") {
+		t.Errorf("ParseBytes() error = %v, want a missing exported template error", err)
+	}
+}
+
+func TestParse_ExtendsTemplatesFromResolvedModules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// The module is not next to the invowkfile, as for an included module.
+	moduleDir := filepath.Join(dir, "shared", "io.example.tools.invowkmod")
+	if err := os.MkdirAll(moduleDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	moduleInvowkfile := `
+templates: container: {
+	runtimes: [{name: "container", image: "golang:1.26"}]
+	platforms: [{name: "linux"}]
+}
+cmds: [{name: "noop", implementations: [{script: {content: "true"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]}]
+`
+	if err := os.WriteFile(filepath.Join(moduleDir, "invowkfile.cue"), []byte(moduleInvowkfile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	projectDir := filepath.Join(dir, "project")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	rootInvowkfile := `cmds: [{name: "build", implementations: [{extends: "@io.example.tools container", script: {content: "go build"}}]}]`
+	rootPath := filepath.Join(projectDir, "invowkfile.cue")
+	if err := os.WriteFile(rootPath, []byte(rootInvowkfile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Parse(FilesystemPath(rootPath)); err == nil || !strings.Contains(err.Error(), "is not available to provide templates") {
+		t.Fatalf("Parse() error = %v, want the module to be unavailable without resolved modules", err)
+	}
+
+	modules := map[invowkmod.ModuleID]FilesystemPath{"io.example.tools": FilesystemPath(moduleDir)}
+	inv, err := Parse(FilesystemPath(rootPath), WithTemplateModules(modules))
+	if err != nil {
+		t.Fatalf("Parse(WithTemplateModules) error = %v", err)
+	}
+	impl := inv.Commands[0].Implementations[0]
+	if len(impl.Runtimes) != 1 || impl.Runtimes[0].Image != "golang:1.26" {
+		t.Errorf("runtimes = %+v, want the resolved module template runtime", impl.Runtimes)
+	}
+}
+
+func TestTemplateRefValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref        TemplateRef
+		wantErr    bool
+		wantModule string
+		wantName   TemplateName
+	}{
+		{ref: "container", wantName: "container"},
+		{ref: "go-service_2", wantName: "go-service_2"},
+		{ref: "@io.example.tools container", wantModule: "io.example.tools", wantName: "container"},
+		{ref: "", wantErr: true},
+		{ref: "1container", wantErr: true},
+		{ref: "two words", wantErr: true},
+		{ref: "@io.example.tools", wantErr: true},
+		{ref: "@io..example container", wantErr: true},
+		{ref: "@io.example bad.name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.ref), func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.ref.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTemplateRef) {
+					t.Fatalf("Validate() error = %v, want ErrInvalidTemplateRef", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if string(tt.ref.Module()) != tt.wantModule || tt.ref.Name() != tt.wantName {
+				t.Errorf("Module(), Name() = %q, %q; want %q, %q", tt.ref.Module(), tt.ref.Name(), tt.wantModule, tt.wantName)
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@
 package invowkfile
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // parseFloat64BitSize is derived without a raw numeric literal because
@@ -17,6 +20,7 @@ const parseFloat64BitSize = len("0123456789abcdef0123456789abcdef0123456789abcde
 // Shared by both flag and argument validation to avoid duplicating type-check logic.
 // Argument callers cast via FlagType(arg.GetType()) since ArgumentType values are
 // a subset of FlagType values (both are string-based named types).
+// List values are JSON arrays of strings (see ParseListValue).
 func validateValueType(value string, typeName FlagType) error {
 	switch typeName {
 	case FlagTypeBool:
@@ -43,8 +47,22 @@ func validateValueType(value string, typeName FlagType) error {
 			return errors.New("must be a valid floating-point number")
 		}
 		return nil
-	case FlagTypeString:
-		// Any string is valid
+	case FlagTypeDuration:
+		if value == "" || DurationString(value).Validate() != nil {
+			return errors.New("must be a valid positive duration (e.g., 30s, 5m, 1h30m)")
+		}
+		return nil
+	case FlagTypeList:
+		_, err := ParseListValue(value)
+		return err
+	case FlagTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return errors.New("must be valid JSON")
+		}
+		return nil
+	case FlagTypeString, FlagTypePath:
+		// Any string is valid; path existence is checked against the
+		// invowkfile directory before execution.
 		return nil
 	default:
 		// Defense-in-depth: CUE schema enforces valid types at parse time.
@@ -66,3 +84,65 @@ func validateValueWithRegex(name, value, pattern string) error {
 	}
 	return nil
 }
+
+// validateValueInChoices validates a value against an optional list of choices.
+// Returns nil if no choices are declared or the value is one of them.
+// name is used for error messages.
+func validateValueInChoices(name, value string, choices []string) error {
+	if len(choices) == 0 || slices.Contains(choices, value) {
+		return nil
+	}
+	return fmt.Errorf("%s value '%s' is not one of the allowed choices: %s", name, value, strings.Join(choices, ", "))
+}
+
+// validateValueElements validates a value against choices and the validation
+// pattern. For list values every item is checked. name is used for error messages.
+func validateValueElements(name, value string, typeName FlagType, choices []string, pattern RegexPattern) error {
+	for _, element := range valueElements(value, typeName) {
+		if err := validateValueInChoices(name, element, choices); err != nil {
+			return err
+		}
+		if err := validateValueWithRegex(name, element, string(pattern)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// choicesValidationErrors checks declared choices against the value type, the
+// validation pattern, and the default value. Shared by flags and arguments.
+// Choices must be unique, type-compatible, and match the validation pattern;
+// a default value must be one of the choices. List choices constrain each item.
+func choicesValidationErrors(choices []string, typeName FlagType, pattern RegexPattern, defaultValue string) []error {
+	if len(choices) == 0 {
+		return nil
+	}
+	if typeName == FlagTypeBool || typeName == FlagTypeJSON {
+		return []error{fmt.Errorf("choices are not supported for %s values", typeName)}
+	}
+	elementType := typeName
+	if typeName == FlagTypeList {
+		elementType = FlagTypeString
+	}
+	var errs []error
+	for i, choice := range choices {
+		if slices.Contains(choices[:i], choice) {
+			errs = append(errs, fmt.Errorf("duplicate choice %q", choice))
+			continue
+		}
+		if err := validateValueType(choice, elementType); err != nil {
+			errs = append(errs, fmt.Errorf("choice %q is not compatible with type %q: %w", choice, typeName, err))
+		}
+		if pattern != "" && pattern.Validate() == nil && !matchesValidation(choice, string(pattern)) {
+			errs = append(errs, fmt.Errorf("choice %q does not match validation pattern %q", choice, pattern))
+		}
+	}
+	if defaultValue != "" {
+		for _, element := range valueElements(defaultValue, typeName) {
+			if !slices.Contains(choices, element) {
+				errs = append(errs, fmt.Errorf("default_value %q is not one of the choices: %s", element, strings.Join(choices, ", ")))
+			}
+		}
+	}
+	return errs
+}
@@ -80,8 +80,8 @@ func TestValidateValueTypeMutationBoundaries(t *testing.T) {
 		{
 			name:    "unknown type rejects programmatic misuse",
 			value:   "anything",
-			kind:    "duration",
-			wantErr: `unknown flag type "duration"`,
+			kind:    "uuid",
+			wantErr: `unknown flag type "uuid"`,
 		},
 	}
 
@@ -39,3 +39,23 @@ func TestValidateEnvFilePath_Matrix(t *testing.T) {
 		ValidRelative:      pathmatrix.PassAny(nil),
 	})
 }
+
+// TestResolveInputPath_Matrix pins how "path" flag and argument values are
+// resolved: absolute inputs (including Unix-absolute on Windows) pass through
+// and everything else is joined to the invowkfile directory.
+func TestResolveInputPath_Matrix(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	pathmatrix.Resolver(t, baseDir, func(input string) (string, error) {
+		return string(ResolveInputPath(FilesystemPath(baseDir), input)), nil
+	}, pathmatrix.Expectations{
+		UnixAbsolute:       pathmatrix.Pass(pathmatrix.InputUnixAbsolute),
+		WindowsDriveAbs:    pathmatrix.PassHostNativeAbs(pathmatrix.InputWindowsDriveAbs),
+		WindowsRooted:      pathmatrix.PassHostNativeAbs(pathmatrix.InputWindowsRooted),
+		UNC:                pathmatrix.PassHostNativeAbs(pathmatrix.InputUNC),
+		SlashTraversal:     pathmatrix.PassRelative(pathmatrix.InputSlashTraversal),
+		BackslashTraversal: pathmatrix.PassRelative(pathmatrix.InputBackslashTraversal),
+		ValidRelative:      pathmatrix.PassAny(nil),
+	})
+}
@@ -10,6 +10,7 @@ const structureValidatorName = "structure"
 //
 // Validation methods are organized across focused files by concern:
 //   - validation_structure_command.go: command, implementation, and runtime config validation
+//   - validation_structure_steps.go: sequential command step validation
 //   - validation_structure_flags.go: flag name, type, short alias, and reserved name validation
 //   - validation_structure_args.go: argument name, type, ordering, and variadic validation
 //   - validation_structure_deps.go: dependency (tools, commands, filepaths, env vars), custom checks, and env config
@@ -45,10 +46,24 @@ func (v *StructureValidator) Validate(ctx *ValidationContext, inv *Invowkfile) [
 	// Validate root-level depends_on (all dependency types including custom checks)
 	errors = append(errors, v.validateDependsOn(ctx, inv, inv.DependsOn, NewFieldPath().Root())...)
 
-	// Validate each command
+	// Validate root-level lifecycle hooks
+	errors = append(errors, v.validateHooks(ctx, inv, inv.Hooks, NewFieldPath().Root())...)
+
+	// Validate each command; imported commands report their fragment's path
 	for i := range inv.Commands {
-		errors = append(errors, v.validateCommand(ctx, inv, &inv.Commands[i])...)
+		errors = append(errors, v.validateCommand(ctx.forCommand(&inv.Commands[i]), inv, &inv.Commands[i])...)
 	}
 
 	return errors
 }
+
+// forCommand returns the validation context of cmd: errors of a command
+// imported from a fragment point at the fragment instead of the invowkfile.
+func (c *ValidationContext) forCommand(cmd *Command) *ValidationContext {
+	if cmd.ImportedFrom == "" {
+		return c
+	}
+	cmdCtx := *c
+	cmdCtx.FilePath = cmd.ImportedFrom
+	return &cmdCtx
+}
@@ -100,11 +100,11 @@ func (v *StructureValidator) validateArg(ctx *ValidationContext, cmd *Command, a
 	seenNames[string(arg.Name)] = true
 
 	// Validate type is valid (if specified) - note: bool is not allowed for args
-	if arg.Type != "" && arg.Type != ArgumentTypeString && arg.Type != ArgumentTypeInt && arg.Type != ArgumentTypeFloat {
+	if arg.Type.Validate() != nil {
 		errors = append(errors, ValidationError{
 			Validator: v.Name(),
 			Field:     path.String(),
-			Message:   "has invalid type '" + string(arg.Type) + "' (must be 'string', 'int', or 'float') in invowkfile at " + string(ctx.FilePath),
+			Message:   "has invalid type '" + string(arg.Type) + "' (must be 'string', 'int', 'float', 'duration', 'path', or 'json') in invowkfile at " + string(ctx.FilePath),
 		})
 	}
 
@@ -146,5 +146,35 @@ func (v *StructureValidator) validateArg(ctx *ValidationContext, cmd *Command, a
 		})
 	}
 
+	// [GO-ONLY] Choice/type/default compatibility needs type-aware checks; CUE
+	// only enforces non-empty choice strings.
+	for _, err := range arg.choicesValidationErrors() {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+		})
+	}
+
+	// [GO-ONLY] must_exist/kind/schema are only meaningful for their type, and
+	// schemas must compile as CUE; CUE only enforces the field shapes.
+	for _, err := range arg.typeOptionsValidationErrors() {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+		})
+	}
+
+	// [GO-ONLY] Prompts must fit the argument: required only, no confirm
+	// prompts, and options that are valid argument values.
+	for _, err := range promptValidationErrors(arg.Prompt, arg.Required, FlagType(arg.GetType()), arg.Choices, arg.Validation) {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+		})
+	}
+
 	return errors, isOptional, isVariadic
 }
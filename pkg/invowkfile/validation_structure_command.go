@@ -4,7 +4,11 @@ package invowkfile
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
+	"strconv"
 )
 
 // validateCommand validates a single command and collects all errors.
@@ -39,6 +43,13 @@ func (v *StructureValidator) validateCommand(ctx *ValidationContext, inv *Invowk
 		})
 	}
 
+	validationErrors = append(validationErrors, v.validateAliases(ctx, inv, cmd, path)...)
+	validationErrors = append(validationErrors, v.validateDeprecation(ctx, inv, cmd, path)...)
+	validationErrors = append(validationErrors, v.validateConfirm(ctx, cmd, path)...)
+	validationErrors = append(validationErrors, v.validateLock(ctx, cmd, path)...)
+	validationErrors = append(validationErrors, v.validateMatrix(ctx, cmd, path)...)
+	validationErrors = append(validationErrors, v.validateOutputs(ctx, cmd, path)...)
+
 	// Validate command-level depends_on (all dependency types including custom checks)
 	validationErrors = append(validationErrors, v.validateDependsOn(ctx, inv, cmd.DependsOn, path.Copy())...)
 
@@ -53,13 +64,40 @@ func (v *StructureValidator) validateCommand(ctx *ValidationContext, inv *Invowk
 		}
 	}
 
-	if len(cmd.Implementations) == 0 {
+	validationErrors = append(validationErrors, v.validateIncrementalPatterns(ctx, path, cmd.Sources, cmd.Generates)...)
+
+	// Validate command-level lifecycle hooks
+	validationErrors = append(validationErrors, v.validateHooks(ctx, inv, cmd.Hooks, path)...)
+
+	// [GO-ONLY] CUE models implementations and steps as independent optional lists;
+	// the exactly-one invariant is enforced here.
+	switch {
+	case len(cmd.Implementations) > 0 && cmd.HasSteps():
 		validationErrors = append(validationErrors, ValidationError{
 			Validator: v.Name(),
 			Field:     path.String(),
-			Message:   "must have at least one implementation in invowkfile at " + string(ctx.FilePath),
+			Message:   "must not define both implementations and steps in invowkfile at " + string(ctx.FilePath),
 		})
-	} else {
+	case cmd.HasSteps():
+		// [GO-ONLY] Step commands have no implementation to fingerprint.
+		if len(cmd.Sources) > 0 || len(cmd.Generates) > 0 {
+			validationErrors = append(validationErrors, ValidationError{
+				Validator: v.Name(),
+				Field:     path.String(),
+				Message:   ErrStepCommandIncremental.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+				Cause:     ErrStepCommandIncremental,
+			})
+		}
+		for i := range cmd.Steps {
+			validationErrors = append(validationErrors, v.validateCommandStep(ctx, inv, cmd, i)...)
+		}
+	case len(cmd.Implementations) == 0:
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   "must have at least one implementation or step in invowkfile at " + string(ctx.FilePath),
+		})
+	default:
 		// Validate each implementation
 		for i := range cmd.Implementations {
 			validationErrors = append(validationErrors, v.validateImplementation(ctx, inv, cmd, i)...)
@@ -81,6 +119,9 @@ func (v *StructureValidator) validateCommand(ctx *ValidationContext, inv *Invowk
 	// Validate args
 	validationErrors = append(validationErrors, v.validateArgs(ctx, cmd)...)
 
+	// Validate flag and arg completion scripts
+	validationErrors = append(validationErrors, v.validateCompletions(ctx, inv, cmd)...)
+
 	return validationErrors
 }
 
@@ -130,6 +171,294 @@ func (v *StructureValidator) validateImplementation(ctx *ValidationContext, inv
 		})
 	}
 
+	// [GO-ONLY] Duration semantics and the max_backoff >= backoff relation
+	// need time.ParseDuration; CUE only enforces shapes and ranges.
+	if impl.Retry != nil {
+		if invalid, ok := errors.AsType[*InvalidRetryPolicyError](impl.Retry.Validate()); ok {
+			for _, fieldErr := range invalid.FieldErrors {
+				validationErrors = append(validationErrors, ValidationError{
+					Validator: v.Name(),
+					Field:     path.Copy().Field("retry").String(),
+					Message:   fieldErr.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+					Cause:     fieldErr,
+				})
+			}
+		}
+	}
+
+	// [GO-ONLY] CUE cannot bound the numeric value of exit_code_map keys or
+	// relate them to success_exit_codes.
+	for _, err := range validateExitCodePolicy(impl.SuccessExitCodes, impl.ExitCodeMap) {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("exit_code_map").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+
+	validationErrors = append(validationErrors, v.validateIncrementalPatterns(ctx, path, impl.Sources, impl.Generates)...)
+	validationErrors = append(validationErrors, v.validateWhen(ctx, cmd, impl.When, path)...)
+
+	// [GO-ONLY] generates needs sources from the command or this implementation.
+	if sources, generates := cmd.IncrementalPatterns(impl); len(generates) > 0 && len(sources) == 0 {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   ErrGeneratesWithoutSources.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     ErrGeneratesWithoutSources,
+		})
+	}
+
+	return validationErrors
+}
+
+// validateWhen validates the when conditions of an implementation.
+// [GO-ONLY] CUE cannot require a non-empty struct or cross-reference the
+// command's declared flags and arguments.
+func (v *StructureValidator) validateWhen(ctx *ValidationContext, cmd *Command, when *When, path *FieldPath) []ValidationError {
+	if when == nil {
+		return nil
+	}
+	var errs []error
+	if invalid, ok := errors.AsType[*InvalidWhenError](when.Validate()); ok {
+		errs = append(errs, invalid.FieldErrors...)
+	}
+	for _, name := range slices.Sorted(maps.Keys(when.Flags)) {
+		if !slices.ContainsFunc(cmd.Flags, func(f Flag) bool { return f.Name == name }) {
+			errs = append(errs, fmt.Errorf("when references undeclared flag '%s'", name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(when.Args)) {
+		if !slices.ContainsFunc(cmd.Args, func(a Argument) bool { return a.Name == name }) {
+			errs = append(errs, fmt.Errorf("when references undeclared argument '%s'", name))
+		}
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("when").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateAliases validates the aliases of a command.
+// [GO-ONLY] CUE cannot check that aliases stay unique across the commands of
+// an invowkfile. An alias shared by two commands is reported on the later one.
+func (v *StructureValidator) validateAliases(ctx *ValidationContext, inv *Invowkfile, cmd *Command, path *FieldPath) []ValidationError {
+	var errs []error
+	for i, alias := range cmd.Aliases {
+		if err := alias.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch {
+		case alias == cmd.Name:
+			errs = append(errs, fmt.Errorf("alias '%s' repeats the command name", alias))
+		case slices.Contains(cmd.Aliases[:i], alias):
+			errs = append(errs, fmt.Errorf("has duplicate alias '%s'", alias))
+		case slices.ContainsFunc(inv.Commands, func(other Command) bool { return other.Name == alias }):
+			errs = append(errs, fmt.Errorf("alias '%s' conflicts with the command of the same name", alias))
+		default:
+			for j := range inv.Commands {
+				other := &inv.Commands[j]
+				if other == cmd {
+					break
+				}
+				if slices.Contains(other.Aliases, alias) {
+					errs = append(errs, fmt.Errorf("alias '%s' is already an alias of command '%s'", alias, other.Name))
+					break
+				}
+			}
+		}
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("aliases").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateDeprecation validates the deprecation of a command.
+// [GO-ONLY] CUE cannot require a replacement for forwarding or check that the
+// replacement names another command of the invowkfile.
+func (v *StructureValidator) validateDeprecation(ctx *ValidationContext, inv *Invowkfile, cmd *Command, path *FieldPath) []ValidationError {
+	dep := cmd.Deprecated
+	if dep == nil {
+		return nil
+	}
+	var errs []error
+	if invalid, ok := errors.AsType[*InvalidDeprecationError](dep.Validate()); ok {
+		errs = append(errs, invalid.FieldErrors...)
+	}
+	if dep.Replacement != "" {
+		switch {
+		case dep.Replacement == cmd.Name || slices.Contains(cmd.Aliases, dep.Replacement):
+			errs = append(errs, fmt.Errorf("replacement '%s' must name another command", dep.Replacement))
+		case !slices.ContainsFunc(inv.Commands, func(other Command) bool { return other.Name == dep.Replacement }):
+			errs = append(errs, fmt.Errorf("replacement '%s' is not a command of this invowkfile", dep.Replacement))
+		}
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("deprecated").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateConfirm validates the confirm block of a command.
+// [GO-ONLY] CUE cannot check that message placeholders name flags and
+// arguments the command declares.
+func (v *StructureValidator) validateConfirm(ctx *ValidationContext, cmd *Command, path *FieldPath) []ValidationError {
+	confirm := cmd.Confirm
+	if confirm == nil {
+		return nil
+	}
+	var errs []error
+	if invalid, ok := errors.AsType[*InvalidConfirmConfigError](confirm.Validate()); ok {
+		errs = append(errs, invalid.FieldErrors...)
+	}
+	flags, args := confirm.Placeholders()
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(flags))) {
+		if !slices.ContainsFunc(cmd.Flags, func(flag Flag) bool { return flag.Name == name }) {
+			errs = append(errs, fmt.Errorf("confirm message references undeclared flag '%s'", name))
+		}
+	}
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(args))) {
+		if !slices.ContainsFunc(cmd.Args, func(arg Argument) bool { return arg.Name == name }) {
+			errs = append(errs, fmt.Errorf("confirm message references undeclared argument '%s'", name))
+		}
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("confirm").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateLock validates the lock of a command.
+// [GO-ONLY] CUE cannot reject control characters in the lock name or require
+// wait for a timeout.
+func (v *StructureValidator) validateLock(ctx *ValidationContext, cmd *Command, path *FieldPath) []ValidationError {
+	if cmd.Lock == nil {
+		return nil
+	}
+	invalid, ok := errors.AsType[*InvalidCommandLockError](cmd.Lock.Validate())
+	if !ok {
+		return nil
+	}
+	validationErrors := make([]ValidationError, 0, len(invalid.FieldErrors))
+	for _, err := range invalid.FieldErrors {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("lock").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateMatrix validates the matrix of a command.
+// [GO-ONLY] CUE cannot require vars or include, reject empty include and
+// exclude entries, or check exclude entries against the declared vars.
+func (v *StructureValidator) validateMatrix(ctx *ValidationContext, cmd *Command, path *FieldPath) []ValidationError {
+	if cmd.Matrix == nil {
+		return nil
+	}
+	invalid, ok := errors.AsType[*InvalidCommandMatrixError](cmd.Matrix.Validate())
+	if !ok {
+		return nil
+	}
+	validationErrors := make([]ValidationError, 0, len(invalid.FieldErrors))
+	for _, err := range invalid.FieldErrors {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("matrix").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateOutputs validates the outputs of a command.
+// [GO-ONLY] CUE cannot reject duplicate output names or outputs on commands
+// with steps or a matrix.
+func (v *StructureValidator) validateOutputs(ctx *ValidationContext, cmd *Command, path *FieldPath) []ValidationError {
+	if len(cmd.Outputs) == 0 {
+		return nil
+	}
+	var errs []error
+	if cmd.HasSteps() {
+		errs = append(errs, ErrStepCommandOutputs)
+	}
+	if cmd.Matrix != nil {
+		errs = append(errs, ErrMatrixCommandOutputs)
+	}
+	seen := make(map[OutputName]bool, len(cmd.Outputs))
+	for _, output := range cmd.Outputs {
+		if seen[output.Name] {
+			errs = append(errs, fmt.Errorf("%w '%s'", ErrDuplicateOutputName, output.Name))
+		}
+		seen[output.Name] = true
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.Copy().Field("outputs").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
+// validateIncrementalPatterns validates the sources and generates glob patterns
+// declared at path.
+// [GO-ONLY] Glob syntax requires doublestar; CUE only enforces non-empty strings.
+func (v *StructureValidator) validateIncrementalPatterns(ctx *ValidationContext, path *FieldPath, sources, generates []GlobPattern) []ValidationError {
+	validationErrors := v.validateGlobPatterns(ctx, path, "sources", sources)
+	return append(validationErrors, v.validateGlobPatterns(ctx, path, "generates", generates)...)
+}
+
+func (v *StructureValidator) validateGlobPatterns(ctx *ValidationContext, path *FieldPath, field string, patterns []GlobPattern) []ValidationError {
+	var validationErrors []ValidationError
+	for i, pattern := range patterns {
+		if err := pattern.Validate(); err != nil {
+			validationErrors = append(validationErrors, ValidationError{
+				Validator: v.Name(),
+				Field:     path.Copy().Field(field + "[" + strconv.Itoa(i) + "]").String(),
+				Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+				Cause:     err,
+			})
+		}
+	}
 	return validationErrors
 }
 
@@ -211,6 +540,20 @@ func (v *StructureValidator) validateRuntimeConfig(ctx *ValidationContext, inv *
 			}
 		}
 		validationErrors = append(validationErrors, v.validateDependsOn(ctx, inv, rt.DependsOn, path.Copy())...)
+		if rt.DependsOn != nil {
+			for i, dep := range rt.DependsOn.Commands {
+				if !dep.Run {
+					continue
+				}
+				validationErrors = append(validationErrors, ValidationError{
+					Validator: v.Name(),
+					Field:     path.Copy().DependsOn().Field("cmds[" + strconv.Itoa(i+1) + "]").String(),
+					Message:   ErrRuntimeRunDependency.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+					Severity:  SeverityError,
+					Cause:     ErrRuntimeRunDependency,
+				})
+			}
+		}
 	}
 
 	return validationErrors
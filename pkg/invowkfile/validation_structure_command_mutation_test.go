@@ -45,7 +45,7 @@ func TestStructureCommandMutationDiagnostics(t *testing.T) {
 				inv.Commands[0].Implementations = nil
 			},
 			wantField:   "command 'deploy'",
-			wantMessage: "must have at least one implementation in invowkfile at /workspace/invowkfile.cue",
+			wantMessage: "must have at least one implementation or step in invowkfile at /workspace/invowkfile.cue",
 		},
 		{
 			name: "duplicate platform runtime",
@@ -99,6 +99,31 @@ func TestStructureCommandMutationDiagnostics(t *testing.T) {
 			wantField:   "command 'deploy' implementation #1 timeout",
 			wantMessage: `invalid duration string "0s": must be a positive duration`,
 		},
+		{
+			name: "implementation retry",
+			mutate: func(_ *testing.T, inv *Invowkfile) {
+				inv.Commands[0].Implementations[0].Retry = &RetryPolicy{Attempts: 3, Backoff: "5s", MaxBackoff: "1s"}
+			},
+			wantField:   "command 'deploy' implementation #1 retry",
+			wantMessage: ErrRetryMaxBackoffBelowBackoff.Error() + " in invowkfile at /workspace/invowkfile.cue",
+		},
+		{
+			name: "empty implementation when",
+			mutate: func(_ *testing.T, inv *Invowkfile) {
+				inv.Commands[0].Implementations[0].When = &When{}
+			},
+			wantField:   "command 'deploy' implementation #1 when",
+			wantMessage: ErrEmptyWhen.Error() + " in invowkfile at /workspace/invowkfile.cue",
+			wantCause:   ErrEmptyWhen,
+		},
+		{
+			name: "when undeclared flag",
+			mutate: func(_ *testing.T, inv *Invowkfile) {
+				inv.Commands[0].Implementations[0].When = &When{Flags: map[FlagName]string{"target": "prod"}}
+			},
+			wantField:   "command 'deploy' implementation #1 when",
+			wantMessage: "when references undeclared flag 'target' in invowkfile at /workspace/invowkfile.cue",
+		},
 		{
 			name: "missing script source",
 			mutate: func(_ *testing.T, inv *Invowkfile) {
@@ -175,6 +200,21 @@ func TestStructureCommandMutationDiagnostics(t *testing.T) {
 	}
 }
 
+func TestStructureCommandMutationConditionalDuplicate(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	conditional := inv.Commands[0].Implementations[0]
+	conditional.When = &When{Env: map[EnvVarName]string{"CI": "true"}}
+	inv.Commands[0].Implementations = append([]Implementation{conditional}, inv.Commands[0].Implementations...)
+
+	for _, err := range inv.Validate() {
+		if strings.Contains(err.Message, "duplicate platform+runtime") {
+			t.Fatalf("Validate() reported %q, want conditional implementations to share combinations", err.Message)
+		}
+	}
+}
+
 func TestStructureCommandMutationDependencyDelegation(t *testing.T) {
 	t.Parallel()
 
@@ -254,6 +294,159 @@ func TestStructureCommandMutationWatchConfigExpansion(t *testing.T) {
 	)
 }
 
+func TestStructureCommandMutationRuntimeRunDependency(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	inv.Commands[0].Implementations[0].Runtimes[0] = RuntimeConfig{
+		Name:  RuntimeContainer,
+		Image: "debian:stable-slim",
+		DependsOn: &DependsOn{Commands: []CommandDependency{
+			{Alternatives: []CommandDependencyRef{"lint"}},
+			{Alternatives: []CommandDependencyRef{"build"}, Run: true},
+		}},
+	}
+
+	got := requireValidationStructureCommandIssue(
+		t,
+		inv.Validate(),
+		"command 'deploy' implementation #1 runtime #1 depends_on cmds[2]",
+		ErrRuntimeRunDependency.Error()+" in invowkfile at /workspace/invowkfile.cue",
+	)
+	if !errors.Is(got.Cause, ErrRuntimeRunDependency) {
+		t.Fatalf("validation cause = %v, want ErrRuntimeRunDependency", got.Cause)
+	}
+}
+
+func TestStructureCommandMutationGeneratesWithoutSources(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	inv.Commands[0].Generates = []GlobPattern{"dist/**"}
+
+	got := requireValidationStructureCommandIssue(
+		t,
+		inv.Validate(),
+		"command 'deploy' implementation #1",
+		ErrGeneratesWithoutSources.Error()+" in invowkfile at /workspace/invowkfile.cue",
+	)
+	if !errors.Is(got.Cause, ErrGeneratesWithoutSources) {
+		t.Fatalf("validation cause = %v, want ErrGeneratesWithoutSources", got.Cause)
+	}
+
+	inv.Commands[0].Implementations[0].Sources = []GlobPattern{"src/**"}
+	if errs := inv.Validate(); len(errs) != 0 {
+		t.Fatalf("Validate() with implementation sources = %v, want no errors", errs)
+	}
+}
+
+func TestStructureCommandMutationIncrementalPatterns(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	inv.Commands[0].Sources = []GlobPattern{"src/**", "src/[a-"}
+
+	got := requireValidationStructureCommandIssue(
+		t,
+		inv.Validate(),
+		"command 'deploy' sources[1]",
+		`invalid glob pattern "src/[a-": invalid syntax: syntax error in pattern in invowkfile at /workspace/invowkfile.cue`,
+	)
+	if !errors.Is(got.Cause, ErrInvalidGlobPattern) {
+		t.Fatalf("validation cause = %v, want ErrInvalidGlobPattern", got.Cause)
+	}
+
+	steps := validationStructureCommandMutationInvowkfile()
+	steps.Commands[0].Implementations = nil
+	steps.Commands[0].Steps = []CommandStep{{Script: &ImplementationScript{Content: "echo step"}}}
+	steps.Commands[0].Sources = []GlobPattern{"src/**"}
+	requireValidationStructureCommandIssue(
+		t,
+		steps.Validate(),
+		"command 'deploy'",
+		ErrStepCommandIncremental.Error()+" in invowkfile at /workspace/invowkfile.cue",
+	)
+}
+
+func TestStructureCommandMutationHooks(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	inv.Commands[0].Hooks = &Hooks{
+		Finally: []Hook{
+			{Script: ImplementationScript{Content: "echo cleanup"}},
+			{Script: ImplementationScript{Content: "echo cleanup"}, Runtime: RuntimeContainer},
+		},
+	}
+	got := requireValidationStructureCommandIssue(
+		t,
+		inv.Validate(),
+		"command 'deploy' hooks.finally[2]",
+		ErrHookContainerRuntime.Error(),
+	)
+	if !errors.Is(got.Cause, ErrHookContainerRuntime) {
+		t.Fatalf("validation cause = %v, want ErrHookContainerRuntime", got.Cause)
+	}
+
+	scriptFile := ScriptFilePath("scripts/setup.sh")
+	root := validationStructureCommandMutationInvowkfile()
+	root.Hooks = &Hooks{Before: []Hook{{Script: ImplementationScript{File: &scriptFile}}}}
+	requireValidationStructureCommandIssue(
+		t,
+		root.Validate(),
+		"root hooks.before[1] script file",
+		"script file requires module invowkfile in invowkfile at /workspace/invowkfile.cue",
+	)
+}
+
+func TestStructureCommandMutationAliases(t *testing.T) {
+	t.Parallel()
+
+	inv := validationStructureCommandMutationInvowkfile()
+	ship := inv.Commands[0]
+	ship.Name = "ship"
+	ship.Aliases = []CommandName{"s"}
+	inv.Commands = append(inv.Commands, ship)
+	inv.Commands[0].Aliases = []CommandName{"deploy", "d", "d", "ship"}
+	inv.Commands[1].Aliases = []CommandName{"s", "d"}
+	errs := inv.Validate()
+
+	const suffix = " in invowkfile at /workspace/invowkfile.cue"
+	requireValidationStructureCommandIssue(t, errs, "command 'deploy' aliases", "alias 'deploy' repeats the command name"+suffix)
+	requireValidationStructureCommandIssue(t, errs, "command 'deploy' aliases", "has duplicate alias 'd'"+suffix)
+	requireValidationStructureCommandIssue(t, errs, "command 'deploy' aliases", "alias 'ship' conflicts with the command of the same name"+suffix)
+	requireValidationStructureCommandIssue(t, errs, "command 'ship' aliases", "alias 'd' is already an alias of command 'deploy'"+suffix)
+	if got := len(errs); got != 4 {
+		t.Fatalf("Validate() returned %d issues, want 4:\n%s", got, errs.Error())
+	}
+}
+
+func TestStructureCommandMutationDeprecation(t *testing.T) {
+	t.Parallel()
+
+	const suffix = " in invowkfile at /workspace/invowkfile.cue"
+	tests := []struct {
+		name        string
+		deprecation Deprecation
+		wantMessage string
+	}{
+		{name: "forward without replacement", deprecation: Deprecation{Message: "gone", Forward: true}, wantMessage: ErrForwardWithoutReplacement.Error() + suffix},
+		{name: "self replacement", deprecation: Deprecation{Message: "gone", Replacement: "deploy"}, wantMessage: "replacement 'deploy' must name another command" + suffix},
+		{name: "unknown replacement", deprecation: Deprecation{Message: "gone", Replacement: "ship"}, wantMessage: "replacement 'ship' is not a command of this invowkfile" + suffix},
+		{name: "empty message", deprecation: Deprecation{Message: " "}, wantMessage: `invalid description text: non-empty value must not be whitespace-only and must be at most 10240 runes (got " ")` + suffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			inv := validationStructureCommandMutationInvowkfile()
+			inv.Commands[0].Deprecated = &tt.deprecation
+			requireValidationStructureCommandIssue(t, inv.Validate(), "command 'deploy' deprecated", tt.wantMessage)
+		})
+	}
+}
+
 func validationStructureCommandMutationInvowkfile() *Invowkfile {
 	return &Invowkfile{
 		FilePath: validationStructureCommandMutationFile,
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+// validateCompletions validates the complete blocks of a command's flags and
+// args and collects all errors.
+// [GO-ONLY] CUE cannot check that complete is not combined with choices or a
+// bool flag, nor resolve module-contained script files.
+func (v *StructureValidator) validateCompletions(ctx *ValidationContext, inv *Invowkfile, cmd *Command) []ValidationError {
+	var validationErrors []ValidationError
+	for i := range cmd.Flags {
+		flag := &cmd.Flags[i]
+		if flag.Complete == nil || flag.Name == "" {
+			continue
+		}
+		path := NewFieldPath().Command(cmd.Name).Flag(flag.Name).Field("complete")
+		validationErrors = append(validationErrors, v.validateCompletion(ctx, inv, flag.Complete, flag.GetType(), flag.Choices, path)...)
+	}
+	for i := range cmd.Args {
+		arg := &cmd.Args[i]
+		if arg.Complete == nil || arg.Name == "" {
+			continue
+		}
+		path := NewFieldPath().Command(cmd.Name).Arg(arg.Name).Field("complete")
+		validationErrors = append(validationErrors, v.validateCompletion(ctx, inv, arg.Complete, FlagType(arg.GetType()), arg.Choices, path)...)
+	}
+	return validationErrors
+}
+
+func (v *StructureValidator) validateCompletion(ctx *ValidationContext, inv *Invowkfile, complete *CompletionConfig, typeName FlagType, choices []string, path *FieldPath) []ValidationError {
+	var validationErrors []ValidationError
+	for _, err := range completionValidationErrors(complete, typeName, choices) {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Severity:  SeverityError,
+			Cause:     err,
+		})
+	}
+	if complete.Validate() != nil {
+		return validationErrors
+	}
+	return append(validationErrors, v.validateImplementationScript(ctx, inv, complete.ScriptImplementation(), path)...)
+}
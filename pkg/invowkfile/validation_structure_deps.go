@@ -4,6 +4,9 @@ package invowkfile
 
 import (
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -305,5 +308,30 @@ func (v *StructureValidator) validateEnvConfig(ctx *ValidationContext, env *EnvC
 		}
 	}
 
+	// [GO-ONLY] Secret sources: provider-specific fields and vars/secrets
+	// name conflicts are not expressible in the CUE schema.
+	for _, key := range slices.Sorted(maps.Keys(env.Secrets)) {
+		var secretErrs []error
+		appendFieldError(&secretErrs, key.Validate())
+		if err := env.Secrets[key].Validate(); err != nil {
+			if sourceErr, ok := errors.AsType[*InvalidSecretSourceError](err); ok {
+				secretErrs = append(secretErrs, sourceErr.FieldErrors...)
+			} else {
+				secretErrs = append(secretErrs, err)
+			}
+		}
+		if _, ok := env.Vars[key]; ok {
+			secretErrs = append(secretErrs, fmt.Errorf("%s is declared in both vars and secrets", key))
+		}
+		for _, err := range secretErrs {
+			errs = append(errs, ValidationError{
+				Validator: v.Name(),
+				Field:     basePath.Copy().EnvSecret(string(key)).String(),
+				Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+				Severity:  SeverityError,
+			})
+		}
+	}
+
 	return errs
 }
@@ -231,6 +231,29 @@ func TestStructureDependsOnMutationDiagnostics(t *testing.T) {
 			wantField:   "command 'deploy' env.vars['TOKEN']",
 			wantMessage: "value too long (32769 chars, max 32768) in invowkfile at /workspace/invowkfile.cue",
 		},
+		{
+			name: "command env secret provider fields",
+			mutate: func(_ *testing.T, inv *Invowkfile) {
+				inv.Commands[0].Env = &EnvConfig{
+					Secrets: map[EnvVarName]SecretSource{
+						"TOKEN": {Provider: SecretProviderFile},
+					},
+				}
+			},
+			wantField:   "command 'deploy' env.secrets['TOKEN']",
+			wantMessage: `secret provider "file" requires path in invowkfile at /workspace/invowkfile.cue`,
+		},
+		{
+			name: "root env secret shadows var",
+			mutate: func(_ *testing.T, inv *Invowkfile) {
+				inv.Env = &EnvConfig{
+					Vars:    map[EnvVarName]string{"TOKEN": "plain"},
+					Secrets: map[EnvVarName]SecretSource{"TOKEN": {Provider: SecretProviderEnv, Var: "GITHUB_TOKEN"}},
+				}
+			},
+			wantField:   "root env.secrets['TOKEN']",
+			wantMessage: "TOKEN is declared in both vars and secrets in invowkfile at /workspace/invowkfile.cue",
+		},
 	}
 
 	for _, tt := range tests {
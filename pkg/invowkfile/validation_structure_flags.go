@@ -3,7 +3,10 @@
 package invowkfile
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -79,9 +82,59 @@ func (v *StructureValidator) validateFlags(ctx *ValidationContext, cmd *Command)
 		errors = append(errors, v.validateFlag(ctx, cmd, flag, i, seenNames, seenShorts)...)
 	}
 
+	errors = append(errors, v.validateFlagGroups(ctx, cmd)...)
+
 	return errors
 }
 
+// validateFlagGroups validates the flag groups of a command.
+// [GO-ONLY] CUE cannot check that groups only list flags the command declares,
+// or that a mutually exclusive group does not contain two required flags.
+func (v *StructureValidator) validateFlagGroups(ctx *ValidationContext, cmd *Command) []ValidationError {
+	groups := cmd.FlagGroups
+	if groups == nil {
+		return nil
+	}
+	var errs []error
+	if invalid, ok := errors.AsType[*InvalidFlagGroupsError](groups.Validate()); ok {
+		errs = append(errs, invalid.FieldErrors...)
+	}
+
+	reported := make(map[FlagName]bool)
+	for _, group := range slices.Concat(groups.MutuallyExclusive, groups.RequiredTogether, groups.OneRequired) {
+		for _, name := range group {
+			if reported[name] || slices.ContainsFunc(cmd.Flags, func(flag Flag) bool { return flag.Name == name }) {
+				continue
+			}
+			reported[name] = true
+			errs = append(errs, fmt.Errorf("flag group lists undeclared flag '%s'", name))
+		}
+	}
+	for _, group := range groups.MutuallyExclusive {
+		var required []string
+		for _, flag := range cmd.Flags {
+			if flag.Required && slices.Contains(group, flag.Name) {
+				required = append(required, "--"+string(flag.Name))
+			}
+		}
+		if len(required) > 1 {
+			errs = append(errs, fmt.Errorf("mutually exclusive flags %s are all required", strings.Join(required, ", ")))
+		}
+	}
+
+	validationErrors := make([]ValidationError, 0, len(errs))
+	for _, err := range errs {
+		validationErrors = append(validationErrors, ValidationError{
+			Validator: v.Name(),
+			Field:     NewFieldPath().Command(cmd.Name).Field("flag_groups").String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Severity:  SeverityError,
+			Cause:     err,
+		})
+	}
+	return validationErrors
+}
+
 // validateFlag validates a single flag and collects all errors.
 func (v *StructureValidator) validateFlag(ctx *ValidationContext, cmd *Command, flag *Flag, idx int, seenNames, seenShorts map[string]bool) []ValidationError {
 	var errors []ValidationError
@@ -192,11 +245,11 @@ func (v *StructureValidator) validateFlag(ctx *ValidationContext, cmd *Command,
 	}
 
 	// Validate type is valid (if specified)
-	if flag.Type != "" && flag.Type != FlagTypeString && flag.Type != FlagTypeBool && flag.Type != FlagTypeInt && flag.Type != FlagTypeFloat {
+	if flag.Type.Validate() != nil {
 		errors = append(errors, ValidationError{
 			Validator: v.Name(),
 			Field:     path.String(),
-			Message:   "has invalid type '" + string(flag.Type) + "' (must be 'string', 'bool', 'int', or 'float') in invowkfile at " + string(ctx.FilePath),
+			Message:   "has invalid type '" + string(flag.Type) + "' (must be 'string', 'bool', 'int', 'float', 'duration', 'path', 'list', or 'json') in invowkfile at " + string(ctx.FilePath),
 			Severity:  SeverityError,
 		})
 	}
@@ -259,5 +312,38 @@ func (v *StructureValidator) validateFlag(ctx *ValidationContext, cmd *Command,
 		})
 	}
 
+	// [GO-ONLY] Choice/type/default compatibility needs type-aware checks; CUE
+	// only enforces non-empty choice strings.
+	for _, err := range flag.choicesValidationErrors() {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Severity:  SeverityError,
+		})
+	}
+
+	// [GO-ONLY] must_exist/kind/list_format/schema are only meaningful for their
+	// type, and schemas must compile as CUE; CUE only enforces the field shapes.
+	for _, err := range flag.typeOptionsValidationErrors() {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Severity:  SeverityError,
+		})
+	}
+
+	// [GO-ONLY] Prompts must fit the flag: required only, confirm for bool
+	// flags, and options that are valid flag values.
+	for _, err := range promptValidationErrors(flag.Prompt, flag.Required, flag.GetType(), flag.Choices, flag.Validation) {
+		errors = append(errors, ValidationError{
+			Validator: v.Name(),
+			Field:     path.String(),
+			Message:   err.Error() + invowkfileAtSuffix + string(ctx.FilePath),
+			Severity:  SeverityError,
+		})
+	}
+
 	return errors
 }
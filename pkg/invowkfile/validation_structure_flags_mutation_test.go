@@ -162,6 +162,34 @@ func TestStructureValidatorValidateFlagDiagnosticsContract(t *testing.T) {
 				"flags.cue",
 			},
 		},
+		{
+			name: "default outside choices reports choices",
+			flag: Flag{
+				Name:         "env",
+				Description:  "Target environment",
+				Choices:      []string{"dev", "prod"},
+				DefaultValue: "qa",
+			},
+			wantField: "command 'deploy' flag 'env'",
+			wantMessage: []string{
+				"default_value",
+				"is not one of the choices: dev, prod",
+				"flags.cue",
+			},
+		},
+		{
+			name: "type option without matching type",
+			flag: Flag{
+				Name:        "out",
+				Description: "Output directory",
+				Kind:        PathKindDir,
+			},
+			wantField: "command 'deploy' flag 'out'",
+			wantMessage: []string{
+				`must_exist and kind require type "path"`,
+				"flags.cue",
+			},
+		},
 	}
 
 	for _, tt := range tests {
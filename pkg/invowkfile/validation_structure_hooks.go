@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"strconv"
+)
+
+// validateHooks validates the lifecycle hooks declared at one level (root or
+// command) and collects all errors.
+func (v *StructureValidator) validateHooks(ctx *ValidationContext, inv *Invowkfile, hooks *Hooks, basePath *FieldPath) []ValidationError {
+	if hooks == nil {
+		return nil
+	}
+
+	var validationErrors []ValidationError
+	for _, phase := range []HookPhase{HookPhaseBefore, HookPhaseAfter, HookPhaseFinally} {
+		phaseHooks := hooks.Phase(phase)
+		for i := range phaseHooks {
+			hook := &phaseHooks[i]
+			path := basePath.Copy().Field("hooks." + string(phase) + "[" + strconv.Itoa(i+1) + "]")
+			if err := hook.Validate(); err != nil {
+				validationErrors = append(validationErrors, hookValidationErrors(v.Name(), path.String(), err)...)
+				continue
+			}
+			validationErrors = append(validationErrors, v.validateImplementationScript(ctx, inv, hook.ScriptImplementation(), path)...)
+		}
+	}
+	return validationErrors
+}
+
+//goplint:ignore -- validation field paths are rendered diagnostic strings.
+func hookValidationErrors(validatorName ValidatorName, field string, err error) []ValidationError {
+	if invalid, ok := errors.AsType[*InvalidHookError](err); ok {
+		result := make([]ValidationError, 0, len(invalid.FieldErrors))
+		for _, fieldErr := range invalid.FieldErrors {
+			result = append(result, ValidationError{
+				Validator: validatorName,
+				Field:     field,
+				Message:   fieldErr.Error(),
+				Cause:     fieldErr,
+			})
+		}
+		return result
+	}
+	return []ValidationError{{
+		Validator: validatorName,
+		Field:     field,
+		Message:   err.Error(),
+		Cause:     err,
+	}}
+}
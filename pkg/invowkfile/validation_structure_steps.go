@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import "errors"
+
+// validateCommandStep validates a single sequential command step and collects all errors.
+func (v *StructureValidator) validateCommandStep(ctx *ValidationContext, inv *Invowkfile, cmd *Command, stepIdx int) []ValidationError {
+	step := &cmd.Steps[stepIdx]
+	path := NewFieldPath().Command(cmd.Name).Step(stepIdx)
+
+	if err := step.Validate(); err != nil {
+		return commandStepValidationErrors(v.Name(), path.String(), err)
+	}
+
+	var validationErrors []ValidationError
+	if step.IsCommand() {
+		// [GO-ONLY] Direct self-reference is rejected statically; indirect cycles
+		// through other commands are detected when the steps execute.
+		if parts, err := step.Cmd.Parse(); err == nil && !parts.Qualified && parts.Command == cmd.Name {
+			validationErrors = append(validationErrors, ValidationError{
+				Validator: v.Name(),
+				Field:     path.Copy().Field("cmd").String(),
+				Message:   "step must not invoke its own command '" + string(cmd.Name) + "' in invowkfile at " + string(ctx.FilePath),
+			})
+		}
+		return validationErrors
+	}
+
+	impl := step.ScriptImplementation()
+	validationErrors = append(validationErrors, v.validateImplementationScript(ctx, inv, impl, path)...)
+	return validationErrors
+}
+
+//goplint:ignore -- validation field paths are rendered diagnostic strings.
+func commandStepValidationErrors(validatorName ValidatorName, field string, err error) []ValidationError {
+	if invalid, ok := errors.AsType[*InvalidCommandStepError](err); ok {
+		result := make([]ValidationError, 0, len(invalid.FieldErrors))
+		for _, fieldErr := range invalid.FieldErrors {
+			result = append(result, ValidationError{
+				Validator: validatorName,
+				Field:     field,
+				Message:   fieldErr.Error(),
+				Cause:     fieldErr,
+			})
+		}
+		return result
+	}
+	return []ValidationError{{
+		Validator: validatorName,
+		Field:     field,
+		Message:   err.Error(),
+		Cause:     err,
+	}}
+}
@@ -406,6 +406,12 @@ func (p *FieldPath) Implementation(index int) *FieldPath {
 	return p
 }
 
+// Step adds a command step context to the path (1-indexed for user display).
+func (p *FieldPath) Step(index int) *FieldPath {
+	p.parts = append(p.parts, "step #"+strconv.Itoa(index+1))
+	return p
+}
+
 // Runtime adds a runtime context to the path (1-indexed for user display).
 func (p *FieldPath) Runtime(index int) *FieldPath {
 	p.parts = append(p.parts, "runtime #"+strconv.Itoa(index+1))
@@ -466,6 +472,12 @@ func (p *FieldPath) EnvVar(key string) *FieldPath {
 	return p
 }
 
+// EnvSecret adds an env.secrets context to the path.
+func (p *FieldPath) EnvSecret(key string) *FieldPath {
+	p.parts = append(p.parts, "env.secrets['"+key+"']")
+	return p
+}
+
 // DependsOn adds a depends_on context to the path.
 func (p *FieldPath) DependsOn() *FieldPath {
 	p.parts = append(p.parts, "depends_on")
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// VisibilityPublic lists the command and lets anyone run it (the default).
+	VisibilityPublic CommandVisibility = "public"
+	// VisibilityPrivate hides the command and only lets commands of the same
+	// module reach it, through depends_on.cmds, cmd steps, or nested invowk
+	// invocations from their scripts.
+	VisibilityPrivate CommandVisibility = "private"
+	// VisibilityHidden keeps the command runnable but omits it from listings,
+	// help, and completion.
+	VisibilityHidden CommandVisibility = "hidden"
+)
+
+// ErrInvalidCommandVisibility is returned when a CommandVisibility value is not one of the defined visibilities.
+var ErrInvalidCommandVisibility = errors.New("invalid command visibility")
+
+type (
+	// CommandVisibility controls who can see and run a command.
+	// The zero value ("") means VisibilityPublic.
+	//
+	//goplint:enum-cue=#CommandVisibility
+	CommandVisibility string
+
+	// InvalidCommandVisibilityError is returned when a CommandVisibility value is not recognized.
+	// It wraps ErrInvalidCommandVisibility for errors.Is() compatibility.
+	InvalidCommandVisibilityError struct {
+		Value CommandVisibility
+	}
+)
+
+// Error implements the error interface for InvalidCommandVisibilityError.
+func (e *InvalidCommandVisibilityError) Error() string {
+	return fmt.Sprintf("invalid visibility %q (valid: public, private, hidden)", e.Value)
+}
+
+// Unwrap returns the sentinel error for errors.Is() compatibility.
+func (e *InvalidCommandVisibilityError) Unwrap() error {
+	return ErrInvalidCommandVisibility
+}
+
+// String returns the string representation of the CommandVisibility.
+func (v CommandVisibility) String() string { return string(v) }
+
+// Validate returns nil if the CommandVisibility is one of the defined visibilities,
+// or a validation error if it is not.
+//
+//goplint:nonzero
+func (v CommandVisibility) Validate() error {
+	switch v {
+	case VisibilityPublic, VisibilityPrivate, VisibilityHidden:
+		return nil
+	default:
+		return &InvalidCommandVisibilityError{Value: v}
+	}
+}
+
+// IsPrivate reports whether only commands of the same module may run the command.
+func (v CommandVisibility) IsPrivate() bool { return v == VisibilityPrivate }
+
+// IsListed reports whether the command appears in listings, help, and completion.
+func (v CommandVisibility) IsListed() bool { return v == "" || v == VisibilityPublic }
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommandVisibilityValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		visibility CommandVisibility
+		wantErr    bool
+		wantListed bool
+	}{
+		{visibility: "", wantErr: true, wantListed: true},
+		{visibility: VisibilityPublic, wantListed: true},
+		{visibility: VisibilityPrivate},
+		{visibility: VisibilityHidden},
+		{visibility: "internal", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.visibility), func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.visibility.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCommandVisibility) {
+				t.Errorf("Validate() error = %v, want ErrInvalidCommandVisibility", err)
+			}
+			if got := tt.visibility.IsListed(); got != tt.wantListed {
+				t.Errorf("IsListed() = %v, want %v", got, tt.wantListed)
+			}
+			if got := tt.visibility.IsPrivate(); got != (tt.visibility == VisibilityPrivate) {
+				t.Errorf("IsPrivate() = %v", got)
+			}
+		})
+	}
+}
+
+func TestParseCommandVisibility(t *testing.T) {
+	t.Parallel()
+
+	source := `cmds: [{
+	name: "setup"
+	visibility: "internal"
+	implementations: [{script: {content: "make setup"}, runtimes: [{name: "native"}], platforms: [{name: "linux"}]}]
+}]`
+	if _, err := ParseBytes([]byte(source), "invowkfile.cue"); err == nil {
+		t.Fatal("ParseBytes() error = nil, want schema error for unknown visibility")
+	}
+}
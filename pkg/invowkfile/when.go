@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/invowk/invowk/pkg/platform"
+	"github.com/invowk/invowk/pkg/types"
+)
+
+const (
+	// ArchAMD64 is the 64-bit x86 architecture.
+	ArchAMD64 Architecture = "amd64"
+	// ArchARM64 is the 64-bit ARM architecture.
+	ArchARM64 Architecture = "arm64"
+	// Arch386 is the 32-bit x86 architecture.
+	Arch386 Architecture = "386"
+	// ArchARM is the 32-bit ARM architecture.
+	ArchARM Architecture = "arm"
+	// ArchRISCV64 is the 64-bit RISC-V architecture.
+	ArchRISCV64 Architecture = "riscv64"
+	// ArchPPC64LE is the little-endian 64-bit POWER architecture.
+	ArchPPC64LE Architecture = "ppc64le"
+	// ArchS390X is the IBM Z architecture.
+	ArchS390X Architecture = "s390x"
+)
+
+var (
+	// ErrInvalidArchitecture is the sentinel error wrapped by InvalidArchitectureError.
+	ErrInvalidArchitecture = errors.New("invalid architecture")
+
+	// ErrInvalidWhen is the sentinel error wrapped by InvalidWhenError.
+	ErrInvalidWhen = errors.New("invalid when condition")
+
+	// ErrEmptyWhen is returned when a when block declares no condition.
+	ErrEmptyWhen = errors.New("when must declare at least one condition")
+)
+
+type (
+	// Architecture is a CPU architecture, spelled like Go's GOARCH.
+	//
+	//goplint:enum-cue=#Architecture
+	Architecture string
+
+	// InvalidArchitectureError is returned when an Architecture is not a known value.
+	InvalidArchitectureError struct {
+		Value Architecture
+	}
+
+	// InvalidWhenError is returned when a When has invalid fields.
+	// It wraps ErrInvalidWhen for errors.Is() compatibility and collects
+	// field-level validation errors.
+	InvalidWhenError struct {
+		FieldErrors []error
+	}
+
+	//goplint:validate-all
+	//
+	// When restricts an implementation to executions where every declared
+	// condition holds. Implementations whose conditions fail are skipped during
+	// implementation selection, so one command can pick different
+	// implementations for the same platform and runtime (e.g., in CI).
+	//nolint:recvcheck // DDD Validate() (value) + existing methods (pointer)
+	When struct {
+		// Env requires host environment variables to be set to exactly these values.
+		Env map[EnvVarName]string `json:"env,omitempty"`
+		// Flags requires command flags to have these values (defaults included).
+		Flags map[FlagName]string `json:"flags,omitempty"`
+		// Args requires positional arguments to have these values. Variadic
+		// arguments are compared against their space-joined values.
+		Args map[ArgumentName]string `json:"args,omitempty"`
+		// Files requires every path to exist. Relative paths are resolved
+		// against the invowkfile directory (or module root).
+		Files []FilesystemPath `json:"files,omitempty"`
+		// Arch requires the host CPU architecture.
+		Arch Architecture `json:"arch,omitempty"`
+	}
+
+	//goplint:mutable
+	//
+	// WhenContext holds the execution facts When conditions are evaluated
+	// against. A zero WhenContext has no env vars, flags, args, files, or
+	// architecture, so only implementations without conditions match it.
+	WhenContext struct {
+		// LookupEnv looks up host environment variables. Nil means none are set.
+		LookupEnv func(string) (string, bool)
+		// Flags holds the effective flag values, including defaults.
+		Flags map[FlagName]string
+		// Args holds the effective positional argument values by name.
+		Args map[ArgumentName]string
+		// BaseDir resolves relative Files paths.
+		BaseDir FilesystemPath
+		// Arch is the host CPU architecture.
+		Arch Architecture
+	}
+)
+
+// CurrentArchitecture returns the CPU architecture invowk is running on.
+func CurrentArchitecture() Architecture {
+	return Architecture(platform.Arch())
+}
+
+// Error implements the error interface.
+func (e *InvalidArchitectureError) Error() string {
+	return fmt.Sprintf("invalid architecture %q (must be amd64, arm64, 386, arm, riscv64, ppc64le, or s390x)", e.Value)
+}
+
+// Unwrap returns ErrInvalidArchitecture so callers can use errors.Is for programmatic detection.
+func (e *InvalidArchitectureError) Unwrap() error { return ErrInvalidArchitecture }
+
+// Validate returns nil if the Architecture is a known value.
+//
+//goplint:nonzero
+func (a Architecture) Validate() error {
+	switch a {
+	case ArchAMD64, ArchARM64, Arch386, ArchARM, ArchRISCV64, ArchPPC64LE, ArchS390X:
+		return nil
+	default:
+		return &InvalidArchitectureError{Value: a}
+	}
+}
+
+// String returns the string representation of the Architecture.
+func (a Architecture) String() string { return string(a) }
+
+// Validate returns nil if the When declares at least one valid condition,
+// or an error collecting all field-level validation failures.
+func (w When) Validate() error {
+	var errs []error
+	if w.IsEmpty() {
+		errs = append(errs, ErrEmptyWhen)
+	}
+	for name := range w.Env {
+		appendFieldError(&errs, name.Validate())
+	}
+	for name := range w.Flags {
+		appendFieldError(&errs, name.Validate())
+	}
+	for name := range w.Args {
+		appendFieldError(&errs, name.Validate())
+	}
+	for _, path := range w.Files {
+		appendFieldError(&errs, path.Validate())
+	}
+	if w.Arch != "" {
+		appendFieldError(&errs, w.Arch.Validate())
+	}
+	if len(errs) > 0 {
+		return &InvalidWhenError{FieldErrors: errs}
+	}
+	return nil
+}
+
+// Error implements the error interface for InvalidWhenError.
+func (e *InvalidWhenError) Error() string {
+	return types.FormatFieldErrors("when condition", e.FieldErrors)
+}
+
+// Unwrap returns ErrInvalidWhen and field errors for errors.Is() compatibility.
+func (e *InvalidWhenError) Unwrap() error {
+	return errors.Join(ErrInvalidWhen, errors.Join(e.FieldErrors...))
+}
+
+// IsEmpty returns true if the When declares no condition.
+func (w *When) IsEmpty() bool {
+	return len(w.Env) == 0 && len(w.Flags) == 0 && len(w.Args) == 0 && len(w.Files) == 0 && w.Arch == ""
+}
+
+// Match reports whether every condition holds in wc. The description names
+// the matched conditions, or the first failed one when ok is false. A nil
+// When always matches with an empty description.
+func (w *When) Match(wc WhenContext) (ok bool, description string) {
+	if w == nil {
+		return true, ""
+	}
+	var matched []string
+	if w.Arch != "" {
+		if wc.Arch != w.Arch {
+			return false, fmt.Sprintf("arch is %s, want %s", orUnknown(string(wc.Arch)), w.Arch)
+		}
+		matched = append(matched, "arch="+string(w.Arch))
+	}
+	for _, name := range slices.Sorted(maps.Keys(w.Env)) {
+		var value string
+		var set bool
+		if wc.LookupEnv != nil {
+			value, set = wc.LookupEnv(string(name))
+		}
+		if want := w.Env[name]; !set || value != want {
+			return false, fmt.Sprintf("env %s is %s, want %q", name, quotedOrUnset(value, set), want)
+		}
+		matched = append(matched, fmt.Sprintf("env %s=%q", name, value))
+	}
+	for _, name := range slices.Sorted(maps.Keys(w.Flags)) {
+		value, set := wc.Flags[name]
+		if want := w.Flags[name]; !set || value != want {
+			return false, fmt.Sprintf("flag --%s is %s, want %q", name, quotedOrUnset(value, set), want)
+		}
+		matched = append(matched, fmt.Sprintf("flag --%s=%q", name, value))
+	}
+	for _, name := range slices.Sorted(maps.Keys(w.Args)) {
+		value, set := wc.Args[name]
+		if want := w.Args[name]; !set || value != want {
+			return false, fmt.Sprintf("arg %s is %s, want %q", name, quotedOrUnset(value, set), want)
+		}
+		matched = append(matched, fmt.Sprintf("arg %s=%q", name, value))
+	}
+	for _, path := range w.Files {
+		if _, err := os.Stat(string(ResolveInputPath(wc.BaseDir, string(path)))); err != nil {
+			return false, fmt.Sprintf("file %s does not exist", path)
+		}
+		matched = append(matched, fmt.Sprintf("file %s exists", path))
+	}
+	return true, strings.Join(matched, ", ")
+}
+
+func quotedOrUnset(value string, set bool) string {
+	if !set {
+		return "unset"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func joinArchitectures(arches []Architecture) string {
+	strs := make([]string, len(arches))
+	for i, arch := range arches {
+		strs[i] = string(arch)
+	}
+	return strings.Join(strs, ", ")
+}
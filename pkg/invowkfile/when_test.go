@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package invowkfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWhenValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		when    When
+		wantErr string
+	}{
+		{name: "env", when: When{Env: map[EnvVarName]string{"CI": "true"}}},
+		{name: "all conditions", when: When{
+			Env:   map[EnvVarName]string{"CI": ""},
+			Flags: map[FlagName]string{"target": "prod"},
+			Args:  map[ArgumentName]string{"service": "api"},
+			Files: []FilesystemPath{"go.mod"},
+			Arch:  ArchAMD64,
+		}},
+		{name: "empty", when: When{}, wantErr: ErrEmptyWhen.Error()},
+		{name: "invalid env name", when: When{Env: map[EnvVarName]string{"1CI": "true"}}, wantErr: "invalid environment variable name"},
+		{name: "invalid flag name", when: When{Flags: map[FlagName]string{"-target": "prod"}}, wantErr: "invalid flag name"},
+		{name: "invalid arch", when: When{Arch: "sparc"}, wantErr: `invalid architecture "sparc"`},
+		{name: "empty file", when: When{Files: []FilesystemPath{""}}, wantErr: "must be non-empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.when.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidWhen) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidWhen", err)
+			}
+			var whenErr *InvalidWhenError
+			if !errors.As(err, &whenErr) || !fieldErrorsContain(whenErr.FieldErrors, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want a field error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWhenMatch(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(baseDir, "go.mod"), []byte("module x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wc := WhenContext{
+		LookupEnv: func(name string) (string, bool) {
+			value, ok := map[string]string{"CI": "true", "EMPTY": ""}[name]
+			return value, ok
+		},
+		Flags:   map[FlagName]string{"target": "prod"},
+		Args:    map[ArgumentName]string{"service": "api"},
+		BaseDir: FilesystemPath(baseDir),
+		Arch:    ArchARM64,
+	}
+
+	tests := []struct {
+		name     string
+		when     *When
+		wantOK   bool
+		wantDesc string
+	}{
+		{name: "nil", when: nil, wantOK: true},
+		{name: "env", when: &When{Env: map[EnvVarName]string{"CI": "true"}}, wantOK: true, wantDesc: `env CI="true"`},
+		{name: "empty env value", when: &When{Env: map[EnvVarName]string{"EMPTY": ""}}, wantOK: true, wantDesc: `env EMPTY=""`},
+		{name: "env mismatch", when: &When{Env: map[EnvVarName]string{"CI": "false"}}, wantDesc: `env CI is "true", want "false"`},
+		{name: "env unset", when: &When{Env: map[EnvVarName]string{"GITHUB_ACTIONS": "true"}}, wantDesc: `env GITHUB_ACTIONS is unset, want "true"`},
+		{name: "flag", when: &When{Flags: map[FlagName]string{"target": "prod"}}, wantOK: true, wantDesc: `flag --target="prod"`},
+		{name: "flag mismatch", when: &When{Flags: map[FlagName]string{"target": "dev"}}, wantDesc: `flag --target is "prod", want "dev"`},
+		{name: "arg", when: &When{Args: map[ArgumentName]string{"service": "api"}}, wantOK: true, wantDesc: `arg service="api"`},
+		{name: "arg unset", when: &When{Args: map[ArgumentName]string{"region": "eu"}}, wantDesc: `arg region is unset, want "eu"`},
+		{name: "file", when: &When{Files: []FilesystemPath{"go.mod"}}, wantOK: true, wantDesc: "file go.mod exists"},
+		{name: "missing file", when: &When{Files: []FilesystemPath{"go.mod", "Cargo.toml"}}, wantDesc: "file Cargo.toml does not exist"},
+		{name: "arch", when: &When{Arch: ArchARM64}, wantOK: true, wantDesc: "arch=arm64"},
+		{name: "arch mismatch", when: &When{Arch: ArchAMD64}, wantDesc: "arch is arm64, want amd64"},
+		{
+			name:     "all conditions",
+			when:     &When{Arch: ArchARM64, Env: map[EnvVarName]string{"CI": "true"}, Flags: map[FlagName]string{"target": "prod"}},
+			wantOK:   true,
+			wantDesc: `arch=arm64, env CI="true", flag --target="prod"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, desc := tt.when.Match(wc)
+			if ok != tt.wantOK || desc != tt.wantDesc {
+				t.Fatalf("Match() = %v, %q; want %v, %q", ok, desc, tt.wantOK, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestWhenMatch_ZeroContext(t *testing.T) {
+	t.Parallel()
+
+	when := &When{Env: map[EnvVarName]string{"CI": "true"}}
+	if ok, desc := when.Match(WhenContext{}); ok || desc != `env CI is unset, want "true"` {
+		t.Fatalf("Match() = %v, %q; want the condition to fail on a zero context", ok, desc)
+	}
+	if ok, desc := (&When{Arch: ArchAMD64}).Match(WhenContext{}); ok || desc != "arch is unknown, want amd64" {
+		t.Fatalf("Match() = %v, %q; want unknown arch", ok, desc)
+	}
+}
+
+func TestCommandSelectImplementation(t *testing.T) {
+	t.Parallel()
+
+	cmd := &Command{
+		Name: "deploy",
+		Implementations: []Implementation{
+			{
+				Script:    ImplementationScript{Content: "make deploy-ci"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeContainer, Image: "debian:stable-slim"}},
+				Platforms: []PlatformConfig{{Name: PlatformLinux}},
+				When:      &When{Env: map[EnvVarName]string{"CI": "true"}},
+			},
+			{
+				Script:    ImplementationScript{Content: "make deploy"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}, {Name: RuntimeContainer, Image: "debian:stable-slim"}},
+				Platforms: []PlatformConfig{{Name: PlatformLinux}},
+			},
+		},
+	}
+	inCI := WhenContext{LookupEnv: func(name string) (string, bool) { return "true", name == "CI" }}
+
+	impl, explanation := cmd.SelectImplementation(PlatformLinux, RuntimeContainer, inCI)
+	if impl != &cmd.Implementations[0] || explanation != `implementation #1 selected: env CI="true"` {
+		t.Errorf("SelectImplementation(CI) = %p, %q; want implementation #1", impl, explanation)
+	}
+
+	impl, explanation = cmd.SelectImplementation(PlatformLinux, RuntimeContainer, WhenContext{})
+	wantExplanation := `implementation #1 skipped: env CI is unset, want "true"; implementation #2 selected: no when conditions`
+	if impl != &cmd.Implementations[1] || explanation != wantExplanation {
+		t.Errorf("SelectImplementation(local) = %p, %q; want implementation #2 with %q", impl, explanation, wantExplanation)
+	}
+
+	// The conditional implementation does not offer native, so no explanation is needed.
+	impl, explanation = cmd.SelectImplementation(PlatformLinux, RuntimeNative, inCI)
+	if impl != &cmd.Implementations[1] || explanation != "" {
+		t.Errorf("SelectImplementation(native) = %p, %q; want implementation #2 without explanation", impl, explanation)
+	}
+
+	if impl, _ := cmd.SelectImplementation(PlatformMac, RuntimeNative, inCI); impl != nil {
+		t.Errorf("SelectImplementation(macos) = %p, want nil", impl)
+	}
+
+	if got := cmd.GetDefaultRuntimeForPlatformWhen(PlatformLinux, inCI); got != RuntimeContainer {
+		t.Errorf("GetDefaultRuntimeForPlatformWhen(CI) = %s, want container", got)
+	}
+	if got := cmd.GetDefaultRuntimeForPlatformWhen(PlatformLinux, WhenContext{}); got != RuntimeNative {
+		t.Errorf("GetDefaultRuntimeForPlatformWhen(local) = %s, want native", got)
+	}
+}
+
+func TestCommandSelectImplementation_Arch(t *testing.T) {
+	t.Parallel()
+
+	cmd := &Command{
+		Name: "fetch",
+		Implementations: []Implementation{
+			{
+				Script:    ImplementationScript{Content: "curl -LO https://example.com/tool-arm64"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+				Platforms: []PlatformConfig{{Name: PlatformLinux, Arch: []Architecture{ArchARM64}}, {Name: PlatformMac}},
+			},
+			{
+				Script:    ImplementationScript{Content: "curl -LO https://example.com/tool-amd64"},
+				Runtimes:  []RuntimeConfig{{Name: RuntimeVirtualSh}, {Name: RuntimeNative}},
+				Platforms: []PlatformConfig{{Name: PlatformLinux, Arch: []Architecture{ArchAMD64, Arch386}}},
+			},
+		},
+	}
+
+	impl, explanation := cmd.SelectImplementation(PlatformLinux, RuntimeNative, WhenContext{Arch: ArchARM64})
+	if impl != &cmd.Implementations[0] || explanation != "implementation #1 selected: no when conditions" {
+		t.Errorf("SelectImplementation(arm64) = %p, %q; want implementation #1", impl, explanation)
+	}
+
+	impl, explanation = cmd.SelectImplementation(PlatformLinux, RuntimeNative, WhenContext{Arch: ArchAMD64})
+	wantExplanation := "implementation #1 skipped: arch is amd64, want one of arm64; implementation #2 selected: no when conditions"
+	if impl != &cmd.Implementations[1] || explanation != wantExplanation {
+		t.Errorf("SelectImplementation(amd64) = %p, %q; want implementation #2 with %q", impl, explanation, wantExplanation)
+	}
+
+	impl, explanation = cmd.SelectImplementation(PlatformLinux, RuntimeNative, WhenContext{Arch: ArchRISCV64})
+	if impl != nil || !strings.Contains(explanation, "implementation #2 skipped: arch is riscv64, want one of amd64, 386") {
+		t.Errorf("SelectImplementation(riscv64) = %p, %q; want nil with arch explanation", impl, explanation)
+	}
+
+	// The macos entry has no arch restriction.
+	if impl, _ := cmd.SelectImplementation(PlatformMac, RuntimeNative, WhenContext{Arch: ArchAMD64}); impl != &cmd.Implementations[0] {
+		t.Errorf("SelectImplementation(macos) = %p, want implementation #1", impl)
+	}
+
+	if got := cmd.GetDefaultRuntimeForPlatformWhen(PlatformLinux, WhenContext{Arch: ArchAMD64}); got != RuntimeVirtualSh {
+		t.Errorf("GetDefaultRuntimeForPlatformWhen(amd64) = %s, want virtual-sh", got)
+	}
+}
+
+func TestCommandValidateImplementations_Arch(t *testing.T) {
+	t.Parallel()
+
+	impl := func(platforms ...PlatformConfig) Implementation {
+		return Implementation{
+			Script:    ImplementationScript{Content: "make"},
+			Runtimes:  []RuntimeConfig{{Name: RuntimeNative}},
+			Platforms: platforms,
+		}
+	}
+	tests := []struct {
+		name    string
+		impls   []Implementation
+		wantErr string
+	}{
+		{
+			name: "disjoint arches",
+			impls: []Implementation{
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchAMD64}}),
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchARM64}}),
+			},
+		},
+		{
+			name: "arch-specific before fallback",
+			impls: []Implementation{
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchARM64}}),
+				impl(PlatformConfig{Name: PlatformLinux}),
+			},
+		},
+		{
+			name: "fallback shadows arch-specific",
+			impls: []Implementation{
+				impl(PlatformConfig{Name: PlatformLinux}),
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchARM64}}),
+			},
+			wantErr: "platform=linux, runtime=native (implementations #1 and #2)",
+		},
+		{
+			name: "overlapping arches",
+			impls: []Implementation{
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchAMD64, ArchARM64}}),
+				impl(PlatformConfig{Name: PlatformLinux, Arch: []Architecture{ArchARM64}}),
+			},
+			wantErr: "platform=linux, arch=arm64, runtime=native (implementations #1 and #2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := &Command{Name: "build", Implementations: tt.impls}
+			err := cmd.ValidateImplementations()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateImplementations() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateImplementations() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@ const (
 	// CommandScopeDenyInaccessible means the target module is not local,
 	// global, or a direct dependency.
 	CommandScopeDenyInaccessible CommandScopeDenyReason = "inaccessible"
+	// CommandScopeDenyPrivate means the target command is private to another
+	// module.
+	CommandScopeDenyPrivate CommandScopeDenyReason = "private"
 )
 
 var (
@@ -30,6 +33,9 @@ type (
 	//  2. Commands from globally installed user command modules (~/.invowk/cmds/)
 	//  3. Commands from first-level requirements resolved in invowkmod.lock.cue
 	//
+	// Private commands (visibility: "private") are only visible from their own
+	// module, whatever the other rules allow.
+	//
 	// CommandScope holds the commands visible to a module, populated post-construction
 	// from discovered command sources. Global command sources are added via
 	// AddGlobalSource(), and direct dependency sources are added via
@@ -67,6 +73,8 @@ type (
 		Reference CommandReference
 		SourceID  ModuleSourceID
 		ModuleID  ModuleID
+		// Private marks a target that only its own module may call.
+		Private bool
 	}
 
 	// CommandScopeDenyReason identifies why a command reference is outside scope.
@@ -123,7 +131,7 @@ func (t CommandTarget) Validate() error {
 // Validate returns nil if the denial reason is recognized.
 func (r CommandScopeDenyReason) Validate() error {
 	switch r {
-	case CommandScopeDenyInaccessible:
+	case CommandScopeDenyInaccessible, CommandScopeDenyPrivate:
 		return nil
 	default:
 		return ErrInvalidCommandScopeDenyReason
@@ -188,6 +196,16 @@ func (s *CommandScope) CanCallTarget(target CommandTarget) CommandScopeDecision
 		return decision
 	}
 
+	// Private targets are only visible from their own module.
+	if target.Private {
+		if s.targetIsSameModule(target) {
+			decision.Allowed = true
+		} else {
+			decision.Reason = CommandScopeDenyPrivate
+		}
+		return decision
+	}
+
 	// If discovery did not attach module identity, the target is a local/root command.
 	if target.SourceID == "" && target.ModuleID == "" {
 		decision.Allowed = true
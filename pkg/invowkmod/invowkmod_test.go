@@ -85,6 +85,37 @@ func TestCommandScope_CanCallTargetUsesDiscoveryIdentity(t *testing.T) {
 			}
 		}},
 
+		{name: "denies private command of direct dependency and global source", run: func(t *testing.T) {
+			t.Parallel()
+
+			for _, target := range []CommandTarget{
+				{Reference: "allowed-tools test", SourceID: "allowed-tools", ModuleID: "io.example.tools", Private: true},
+				{Reference: "global-tools lint", SourceID: "global-tools", ModuleID: "io.example.global", Private: true},
+			} {
+				decision := scope.CanCallTarget(target)
+				if decision.Allowed {
+					t.Fatalf("CanCallTarget() allowed private target of another module: %+v", decision)
+				}
+				if decision.Reason != CommandScopeDenyPrivate {
+					t.Fatalf("Reason = %q, want %q", decision.Reason, CommandScopeDenyPrivate)
+				}
+			}
+		}},
+
+		{name: "allows private command of the same module", run: func(t *testing.T) {
+			t.Parallel()
+
+			decision := scope.CanCallTarget(CommandTarget{
+				Reference: "io.example.caller helper",
+				SourceID:  "io.example.caller",
+				ModuleID:  "io.example.caller",
+				Private:   true,
+			})
+			if !decision.Allowed {
+				t.Fatalf("CanCallTarget() denied private target of the same module: %+v", decision)
+			}
+		}},
+
 		{name: "allows same module only when source identity also matches", run: func(t *testing.T) {
 			t.Parallel()
 
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: MPL-2.0
+
+package platform
+
+import "runtime"
+
+// Arch returns the CPU architecture invowk is running on, spelled like
+// runtime.GOARCH (e.g., "amd64", "arm64").
+func Arch() string {
+	return runtime.GOARCH
+}
@@ -6,6 +6,8 @@
 //
 //   - OS name constants ([Windows], [Darwin], [Linux]) for runtime.GOOS comparisons
 //
+//   - The host CPU architecture ([Arch]), resolved from runtime.GOARCH
+//
 //   - Windows reserved filenames that cannot be used as command names
 //     or module directory names (see [IsWindowsReservedName])
 //
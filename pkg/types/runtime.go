@@ -14,8 +14,12 @@ const (
 	RuntimeVirtualSh RuntimeMode = "virtual-sh"
 	// RuntimeVirtualLua executes commands using the embedded Lua interpreter.
 	RuntimeVirtualLua RuntimeMode = "virtual-lua"
+	// RuntimeVirtualStarlark executes commands using the embedded Starlark interpreter.
+	RuntimeVirtualStarlark RuntimeMode = "virtual-starlark"
 	// RuntimeContainer executes commands inside a container.
 	RuntimeContainer RuntimeMode = "container"
+	// RuntimeRemoteSSH executes commands on a remote host over SSH.
+	RuntimeRemoteSSH RuntimeMode = "remote-ssh"
 )
 
 // ErrInvalidRuntimeMode is the sentinel error wrapped by InvalidRuntimeModeError.
@@ -33,7 +37,7 @@ type (
 
 // Error implements the error interface.
 func (e *InvalidRuntimeModeError) Error() string {
-	return fmt.Sprintf("invalid runtime mode %q (must be one of: native, virtual-sh, virtual-lua, container)", e.Value)
+	return fmt.Sprintf("invalid runtime mode %q (must be one of: native, virtual-sh, virtual-lua, virtual-starlark, container, remote-ssh)", e.Value)
 }
 
 // Unwrap returns ErrInvalidRuntimeMode so callers can use errors.Is for programmatic detection.
@@ -49,9 +53,22 @@ func (m RuntimeMode) String() string { return string(m) }
 //goplint:nonzero
 func (m RuntimeMode) Validate() error {
 	switch m {
-	case RuntimeNative, RuntimeVirtualSh, RuntimeVirtualLua, RuntimeContainer:
+	case RuntimeNative, RuntimeVirtualSh, RuntimeVirtualLua, RuntimeVirtualStarlark, RuntimeContainer, RuntimeRemoteSSH:
 		return nil
 	default:
 		return &InvalidRuntimeModeError{Value: m}
 	}
 }
+
+// IsVirtual reports whether the runtime runs scripts in an embedded
+// interpreter governed by the virtual filesystem and allowed-binaries policy.
+func (m RuntimeMode) IsVirtual() bool {
+	return m == RuntimeVirtualSh || m == RuntimeVirtualLua || m == RuntimeVirtualStarlark
+}
+
+// RequiresConfig reports whether the runtime needs settings only a runtime
+// config can declare (a container image, a remote host), so it cannot be
+// selected by name alone in hooks, completion scripts, or script steps.
+func (m RuntimeMode) RequiresConfig() bool {
+	return m == RuntimeContainer || m == RuntimeRemoteSSH
+}
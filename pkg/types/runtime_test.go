@@ -18,7 +18,9 @@ func TestRuntimeModeValidate(t *testing.T) {
 		{name: "native", value: RuntimeNative, wantValid: true},
 		{name: "virtual-sh", value: RuntimeVirtualSh, wantValid: true},
 		{name: "virtual-lua", value: RuntimeVirtualLua, wantValid: true},
+		{name: "virtual-starlark", value: RuntimeVirtualStarlark, wantValid: true},
 		{name: "container", value: RuntimeContainer, wantValid: true},
+		{name: "remote-ssh", value: RuntimeRemoteSSH, wantValid: true},
 		{name: "empty", value: "", wantValid: false},
 		{name: "unknown", value: "magical", wantValid: false},
 	}
@@ -51,3 +53,33 @@ func TestRuntimeModeString(t *testing.T) {
 		t.Errorf("RuntimeVirtualLua.String() = %q, want virtual-lua", got)
 	}
 }
+
+func TestRuntimeModeIsVirtual(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []RuntimeMode{RuntimeVirtualSh, RuntimeVirtualLua, RuntimeVirtualStarlark} {
+		if !mode.IsVirtual() {
+			t.Errorf("RuntimeMode(%q).IsVirtual() = false, want true", mode)
+		}
+	}
+	for _, mode := range []RuntimeMode{RuntimeNative, RuntimeContainer, RuntimeRemoteSSH} {
+		if mode.IsVirtual() {
+			t.Errorf("RuntimeMode(%q).IsVirtual() = true, want false", mode)
+		}
+	}
+}
+
+func TestRuntimeModeRequiresConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []RuntimeMode{RuntimeNative, RuntimeVirtualSh, RuntimeVirtualLua, RuntimeVirtualStarlark} {
+		if mode.RequiresConfig() {
+			t.Errorf("RuntimeMode(%q).RequiresConfig() = true, want false", mode)
+		}
+	}
+	for _, mode := range []RuntimeMode{RuntimeContainer, RuntimeRemoteSSH} {
+		if !mode.RequiresConfig() {
+			t.Errorf("RuntimeMode(%q).RequiresConfig() = false, want true", mode)
+		}
+	}
+}
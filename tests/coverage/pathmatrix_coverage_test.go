@@ -34,6 +34,7 @@ var (
 		"pkg/invowkfile.ContainerfilePath.Validate":                 {testFile: "pkg/invowkfile/containerfile_path_test.go", testFunc: "TestContainerfilePath_Validate"},
 		"pkg/invowkfile.Implementation.GetScriptFilePathWithModule": {testFile: "pkg/invowkfile/implementation_get_script_file_path_test.go", testFunc: "TestGetScriptFilePathWithModule_Matrix"},
 		"pkg/invowkfile.Invowkfile.GetEffectiveWorkDir":             {testFile: "pkg/invowkfile/invowkfile_workdir_matrix_test.go", testFunc: "TestGetEffectiveWorkDir_Matrix"},
+		"pkg/invowkfile.ResolveInputPath":                           {testFile: "pkg/invowkfile/validation_pathmatrix_test.go", testFunc: "TestResolveInputPath_Matrix"},
 		"pkg/invowkfile.ScriptFilePath.ResolveFromModule":           {testFile: "pkg/invowkfile/script_file_path_test.go", testFunc: "TestScriptFilePath_ResolveFromModule_Matrix"},
 		"pkg/invowkfile.ScriptFilePath.Validate":                    {testFile: "pkg/invowkfile/script_file_path_test.go", testFunc: "TestScriptFilePath_Validate_Matrix"},
 		"pkg/invowkfile.ValidateContainerfilePath":                  {testFile: "pkg/invowkfile/validation_pathmatrix_test.go", testFunc: "TestValidateContainerfilePath_Matrix"},
@@ -59,6 +60,14 @@ var (
 			testFile: "internal/app/deps/filepaths_test.go",
 			reason:   "thin default-probe wrapper; the path dialect contract is exercised through ValidateFilepathAlternativesWithProbe",
 		},
+		"internal/app/deps.ValidateArgumentPaths": {
+			testFile: "internal/app/deps/input_test.go",
+			reason:   "thin collection wrapper around Argument.ValidatePathValue; resolution is covered by the ResolveInputPath matrix",
+		},
+		"internal/app/deps.ValidateFlagPaths": {
+			testFile: "internal/app/deps/input_test.go",
+			reason:   "thin collection wrapper around Flag.ValidatePathValue; resolution is covered by the ResolveInputPath matrix",
+		},
 		"internal/app/deps.resolveHostFilepathAlternative": {
 			testFile: "internal/app/deps/filepaths_test.go",
 			reason:   "private resolver is exercised by the registered ValidateFilepathAlternativesWithProbe matrix",
@@ -179,6 +188,14 @@ var (
 			testFile: "pkg/invowkfile/dotenv_path_test.go",
 			reason:   "DotenvFilePath.Validate is a scalar nonblank check; ValidateEnvFilePath owns portable path security",
 		},
+		"pkg/invowkfile.Argument.ValidatePathValue": {
+			testFile: "pkg/invowkfile/argument_validate_test.go",
+			reason:   "checks existence and kind of a path resolved by the registered ResolveInputPath matrix",
+		},
+		"pkg/invowkfile.Flag.ValidatePathValue": {
+			testFile: "pkg/invowkfile/input_types_test.go",
+			reason:   "checks existence and kind of a path resolved by the registered ResolveInputPath matrix",
+		},
 		"pkg/invowkfile.Implementation.GetScriptFilePath": {
 			testFile: "pkg/invowkfile/invowkfile_parsing_test.go",
 			reason:   "non-module compatibility wrapper; module-aware resolution is covered by GetScriptFilePathWithModule",
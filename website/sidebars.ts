@@ -39,7 +39,9 @@ const sidebars: SidebarsConfig = {
         'runtime-modes/native',
         'runtime-modes/virtual',
         'runtime-modes/virtual-lua',
+        'runtime-modes/virtual-starlark',
         'runtime-modes/container',
+        'runtime-modes/remote-ssh',
       ],
     },
     {
@@ -132,6 +132,11 @@ container: {
         inherit_includes: true
         cache_dir: ""
     }
+}
+
+// Execution scheduling
+execution: {
+    max_parallel: 0
 }`,
   },
 
@@ -401,6 +406,14 @@ container: {
         inherit_includes: true
         cache_dir: ""
     }
+}
+
+// Execution scheduling
+// --------------------
+// Max prerequisite commands (depends_on.cmds with run: true) running at once.
+// 0 uses the number of available CPUs.
+execution: {
+    max_parallel: 0
 }`,
   },
 
@@ -538,6 +551,11 @@ container: {
         inherit_includes: true
         cache_dir: ""
     }
+}
+
+// Execution scheduling
+execution: {
+    max_parallel: 0
 }`,
   },
 
@@ -778,6 +796,14 @@ import "strings"
 }`,
   },
 
+  'reference/config/execution': {
+    language: 'cue',
+    code: `execution: {
+    // Run at most 4 prerequisite commands at the same time
+    max_parallel: 4
+}`,
+  },
+
   'reference/config/container': {
     language: 'cue',
     code: `container: {
@@ -1021,6 +1047,14 @@ container: {
         inherit_includes: true
         cache_dir: ""
     }
+}
+
+// Execution scheduling
+// --------------------
+// Max prerequisite commands (depends_on.cmds with run: true) running at once.
+// 0 uses the number of available CPUs.
+execution: {
+    max_parallel: 0
 }`,
   },
 
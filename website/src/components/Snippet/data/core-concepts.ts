@@ -176,6 +176,77 @@ cmds: [
 ]`,
   },
 
+  'core-concepts/templates': {
+    language: 'cue',
+    code: `templates: {
+    "go-container": {
+        runtimes: [{name: "container", image: "golang:1.26"}]
+        platforms: [{name: "linux"}]
+        timeout: "10m"
+    }
+    "go-service": {
+        category: "Go"
+        workdir: "./service"
+        flags: [{name: "race", description: "Enable the race detector", type: "bool", default_value: "false"}]
+    }
+}
+
+cmds: [
+    {
+        name: "build"
+        extends: "go-service"
+        implementations: [
+            {extends: "go-container", script: {content: "go build ./..."}}
+        ]
+    },
+    {
+        name: "test"
+        extends: "go-service"
+        workdir: "./service/internal"  // Overrides the template value
+        implementations: [
+            {extends: "go-container", timeout: "20m", script: {content: "go test ./..."}}
+        ]
+    }
+]`,
+  },
+
+  'core-concepts/module-templates': {
+    language: 'cue',
+    code: `// Uses the "go-container" template exported by the io.example.tools module
+implementations: [
+    {extends: "@io.example.tools go-container", script: {content: "go vet ./..."}}
+]`,
+  },
+
+  'core-concepts/imports': {
+    language: 'cue',
+    code: `// invowkfile.cue
+imports: ["ci/*.invowk.cue"]
+
+templates: {
+    sh: {
+        runtimes: [{name: "virtual-sh"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }
+}
+
+cmds: [
+    {name: "build", implementations: [{extends: "sh", script: {content: "go build ./..."}}]}
+]
+
+// ci/lint.invowk.cue
+env: vars: {GOLANGCI_LINT_CACHE: ".cache/lint"}
+depends_on: filepaths: [{alternatives: [".golangci.yml"]}]  // ci/.golangci.yml
+
+cmds: [
+    {
+        name: "lint"
+        workdir: "."  // The ci/ directory
+        implementations: [{extends: "sh", script: {content: "golangci-lint run ../..."}}]
+    }
+]`,
+  },
+
   // =============================================================================
   // COMMANDS AND NAMESPACES
   // =============================================================================
@@ -266,6 +337,181 @@ From tools.invowkmod:
 ]`,
   },
 
+  'commands-namespaces/aliases-deprecation': {
+    language: 'cue',
+    code: `cmds: [
+    {
+        name: "test unit"
+        // Also runnable as "invowk cmd tu" and "invowk cmd ut"
+        aliases: ["tu", "ut"]
+        implementations: [...]
+    },
+    {
+        name: "deploy"
+        implementations: [...]
+    },
+    {
+        // Renamed to "deploy": warn, then run "deploy" with the same
+        // arguments and the flags "deploy" declares
+        name: "ship"
+        deprecated: {
+            message: "ship was renamed to deploy and will be removed in v3"
+            replacement: "deploy"
+            forward: true
+        }
+        implementations: [...]
+    },
+]`,
+  },
+
+  'commands-namespaces/deprecation-warning': {
+    language: 'text',
+    code: `$ invowk cmd ship
+! Deprecated command!
+
+ship was renamed to deploy and will be removed in v3
+Replacement: deploy
+Running 'deploy' instead.`,
+  },
+
+  'commands-namespaces/confirm': {
+    language: 'cue',
+    code: `cmds: [
+    {
+        name: "db reset"
+        flags: [
+            {name: "env", description: "Target environment", default_value: "staging"},
+        ]
+        confirm: {
+            message: "Drop and recreate the {{flag.env}} database?"
+        }
+        implementations: [...]
+    },
+]`,
+  },
+
+  'commands-namespaces/confirm-yes': {
+    language: 'bash',
+    code: `# Asks: Drop and recreate the prod database?
+invowk cmd db reset --env prod
+
+# In CI, answer yes up front
+invowk cmd db reset --env prod --ivk-yes`,
+  },
+
+  'commands-namespaces/lock': {
+    language: 'cue',
+    code: `cmds: [
+    {
+        name: "db migrate"
+        lock: {
+            name: "local-db"
+            wait: true
+            timeout: "5m"
+        }
+        implementations: [...]
+    },
+    {
+        // Fails right away while "db migrate" runs
+        name: "db seed"
+        lock: {name: "local-db"}
+        implementations: [...]
+    },
+]`,
+  },
+
+  'commands-namespaces/lock-held': {
+    language: 'text',
+    code: `$ invowk cmd db seed
+✗ Command locked!
+
+Command 'db seed' did not run: lock 'local-db' is held by PID 48213 (started 2026-03-04 10:15:02).`,
+  },
+
+  'commands-namespaces/matrix': {
+    language: 'cue',
+    code: `cmds: [{
+    name: "test"
+    matrix: {
+        vars: {
+            go: ["1.22", "1.23"]
+            db: ["postgres", "mysql"]
+        }
+        exclude: [{go: "1.22", db: "mysql"}]
+        include: [{go: "1.24", db: "sqlite"}]
+        parallel: true
+    }
+    implementations: [{
+        script: {content: "DB=$INVOWK_MATRIX_DB go$INVOWK_MATRIX_GO test ./..."}
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
+}]`,
+  },
+
+  'commands-namespaces/matrix-summary': {
+    language: 'text',
+    code: `$ invowk cmd test
+...
+✗ 1 of 4 combinations of 'test' failed
+
+  Combination            Result       Duration
+  db=postgres, go=1.22   pass         12.4s
+  db=postgres, go=1.23   pass         12.9s
+  db=mysql, go=1.23      fail (exit 1)  14.1s
+  db=sqlite, go=1.24     pass         9.8s`,
+  },
+
+  'commands-namespaces/outputs': {
+    language: 'cue',
+    code: `cmds: [
+    {
+        name: "build"
+        outputs: [{name: "version", description: "Version of the built artifact"}]
+        implementations: [{
+            script: {content: """
+                VERSION=$(git describe --tags)
+                go build -ldflags "-X main.version=$VERSION" ./...
+                echo "version=$VERSION" >> "$INVOWK_OUTPUT_FILE"
+                """}
+            runtimes: [{name: "native"}]
+            platforms: [{name: "linux"}, {name: "macos"}]
+        }]
+    },
+    {
+        name: "deploy"
+        depends_on: cmds: [{alternatives: ["build"], run: true}]
+        implementations: [{
+            script: {content: "./deploy.sh \\"$INVOWK_DEP_BUILD_VERSION\\""}
+            runtimes: [{name: "native"}]
+            platforms: [{name: "linux"}, {name: "macos"}]
+        }]
+    },
+]`,
+  },
+
+  'commands-namespaces/visibility': {
+    language: 'cue',
+    code: `cmds: [
+    {
+        name: "release"
+        steps: [{cmd: "build assets"}, {cmd: "publish"}]
+    },
+    {
+        // Runs only as a step or dependency of this module's commands
+        name: "build assets"
+        visibility: "private"
+        implementations: [...]
+    },
+    {
+        // Runnable as "invowk cmd publish", but not listed
+        name: "publish"
+        visibility: "hidden"
+        implementations: [...]
+    },
+]`,
+  },
+
   // =============================================================================
   // IMPLEMENTATIONS
   // =============================================================================
@@ -344,6 +590,25 @@ platforms: [
 }`,
   },
 
+  'implementations/platform-arch': {
+    language: 'cue',
+    code: `{
+    name: "fetch tool"
+    implementations: [
+        {
+            script: {content: "curl -fsSLO https://example.com/tool-linux-arm64"}
+            runtimes: [{name: "native"}]
+            platforms: [{name: "linux", arch: ["arm64"]}]
+        },
+        {
+            script: {content: "curl -fsSLO https://example.com/tool-linux-amd64"}
+            runtimes: [{name: "native"}]
+            platforms: [{name: "linux", arch: ["amd64"]}]
+        }
+    ]
+}`,
+  },
+
   'implementations/runtime-specific': {
     language: 'cue',
     code: `{
@@ -428,6 +693,24 @@ platforms: [
 }`,
   },
 
+  'implementations/exit-codes': {
+    language: 'cue',
+    code: `{
+    name: "find todos"
+    implementations: [{
+        script: {content: "grep -rn TODO src/"}
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+
+        // grep exits 1 when nothing matches, which is fine here
+        success_exit_codes: [1]
+
+        // grep exits 2 on errors; report them as 70
+        exit_code_map: {"2": 70}
+    }]
+}`,
+  },
+
   'implementations/selection-example': {
     language: 'cue',
     code: `{
@@ -450,6 +733,55 @@ platforms: [
 }`,
   },
 
+  'implementations/when-conditions': {
+    language: 'cue',
+    code: `{
+    name: "deploy"
+    flags: [
+        {name: "target", description: "Deploy target", default_value: "staging"},
+    ]
+    implementations: [
+        {
+            // CI runners deploy from a pinned container image
+            script: {content: "make deploy-ci"}
+            runtimes: [{name: "container", image: "debian:stable-slim"}]
+            platforms: [{name: "linux"}]
+            when: {env: {CI: "true"}}
+        },
+        {
+            // Production deploys from Apple Silicon laptops need the cross toolchain
+            script: {content: "make deploy-cross TARGET=$INVOWK_FLAG_TARGET"}
+            runtimes: [{name: "native"}]
+            platforms: [{name: "macos"}]
+            when: {flags: {target: "production"}, arch: "arm64", files: ["toolchains/cross.mk"]}
+        },
+        {
+            // Fallback: no conditions
+            script: {content: "make deploy TARGET=$INVOWK_FLAG_TARGET"}
+            runtimes: [{name: "native"}, {name: "container", image: "debian:stable-slim"}]
+            platforms: [{name: "linux"}, {name: "macos"}]
+        },
+    ]
+}`,
+  },
+
+  'implementations/when-dry-run': {
+    language: 'text',
+    code: `$ invowk cmd deploy --ivk-dry-run
+Dry Run
+
+  Command:  deploy
+  Source:   invowkfile
+  Runtime:  native
+  Platform: linux
+  Selected: implementation #1 skipped: env CI is unset, want "true"; implementation #3 selected: no when conditions
+...
+
+$ CI=true invowk cmd deploy --ivk-verbose
+-> 'deploy' runs with container: implementation #1 selected: env CI="true"
+...`,
+  },
+
   'implementations/list-output': {
     language: 'text',
     code: `Available Commands
@@ -668,7 +1000,10 @@ invowk cmd database-cli --ivk-interactive`,
     workdir?:       string    // Optional - default working directory
     env?:           #EnvConfig      // Optional - global environment
     depends_on?:    #DependsOn      // Optional - global dependencies
-    cmds:           [...#Command]   // Required - at least one command
+    hooks?:         #Hooks          // Optional - lifecycle hooks for all commands
+    templates?:     [string]: {...} // Optional - reusable command/implementation fields
+    imports?:       [...string]     // Optional - invowkfile fragments to merge in
+    cmds:           [...#Command]   // Required - at least one command, unless imported
 }`,
   },
 
@@ -688,6 +1023,7 @@ workdir: "/opt/app"`,
     language: 'cue',
     code: `#Command: {
     name:            string               // Required
+    extends?:        string               // Optional - template to inherit from
     description?:    string               // Optional
     category?:       string               // Optional - groups in listing
     implementations: [...#Implementation] // Required - at least one
@@ -695,8 +1031,12 @@ workdir: "/opt/app"`,
     workdir?:        string               // Optional
     depends_on?:     #DependsOn           // Optional
     flags?:          [...#Flag]           // Optional
+    flag_groups?:    #FlagGroups          // Optional - flag constraints
     args?:           [...#Argument]       // Optional
     watch?:          #WatchConfig         // Optional - file-watching
+    sources?:        [...#GlobPattern]    // Optional - incremental inputs
+    generates?:      [...#GlobPattern]    // Optional - incremental outputs
+    hooks?:          #Hooks               // Optional - lifecycle hooks
 }`,
   },
 
@@ -715,6 +1055,7 @@ name: "deploy-prod"`,
   'reference/invowkfile/implementation-structure': {
     language: 'cue',
     code: `#Implementation: {
+    extends?:    string       // Optional - template to inherit from
     script:      #ImplementationScript       // Required - content or file
     runtimes:    [...#RuntimeConfig] & [_, ...]  // Required - runtime configurations
     platforms:   [...#PlatformConfig] & [_, ...]  // Required - at least one platform
@@ -722,6 +1063,10 @@ name: "deploy-prod"`,
     workdir?:    string       // Optional
     depends_on?: #DependsOn   // Optional
     timeout?:    #DurationString  // Optional - max execution time
+    retry?:      #RetryPolicy     // Optional - re-run on failure
+    when?:       #When            // Optional - selection conditions
+    sources?:    [...#GlobPattern]  // Optional - extra incremental inputs
+    generates?:  [...#GlobPattern]  // Optional - extra incremental outputs
 }`,
   },
 
@@ -786,10 +1131,11 @@ platforms: [
     env_inherit_deny?:  [...string]
 }
 
-// Native runtime: no additional fields
+// Native runtime
 #RuntimeConfigNative: close({
     #RuntimeConfigBase
-    name: "native"
+    name:     "native"
+    sandbox?: close({network?: "none" | "host"})  // Linux only
 })
 
 // Virtual-sh runtime
@@ -810,6 +1156,15 @@ platforms: [
     memory_limit?: string
 })
 
+// Virtual-starlark runtime
+#RuntimeConfigVirtualStarlark: close({
+    #RuntimeConfigBase
+    name: "virtual-starlark"
+    allowed_binaries?: [...string]
+    binary_lookup_mode?: "host" | "strict"
+    max_steps?: int & >=0
+})
+
 // Container runtime: exactly one source + extras
 #RuntimeConfigContainerBase: {
     #RuntimeConfigBase
@@ -834,8 +1189,19 @@ platforms: [
 
 #RuntimeConfigContainer: #RuntimeConfigContainerWithImage | #RuntimeConfigContainerWithContainerfile
 
+// Remote-ssh runtime: runs the script on another host over SSH
+#RuntimeConfigRemoteSSH: close({
+    #RuntimeConfigBase
+    name:           "remote-ssh"
+    host:           string
+    port?:          int & >=1 & <=65535
+    user?:          string
+    identity_file?: string
+    workdir?:       string
+})
+
 // Discriminated union of all runtime types
-#RuntimeConfig: #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigContainer`,
+#RuntimeConfig: #RuntimeConfigNative | #RuntimeConfigVirtualSh | #RuntimeConfigVirtualLua | #RuntimeConfigContainer | #RuntimeConfigRemoteSSH`,
   },
 
   'reference/invowkfile/env-inherit-example': {
@@ -913,10 +1279,23 @@ containerfile: "./docker/Dockerfile.build"`,
 ]`,
   },
 
+  'reference/invowkfile/remote-ssh-example': {
+    language: 'cue',
+    code: `runtimes: [{
+    name:          "remote-ssh"
+    host:          "build-01.example.com"
+    port:          2222                      // Optional: ssh client default
+    user:          "deploy"                  // Optional: ssh client default
+    identity_file: "~/.ssh/deploy_ed25519"   // Optional: ssh agent / ssh_config
+    workdir:       "/srv/app"                // Optional: remote login directory
+}]`,
+  },
+
   'reference/invowkfile/platform-config-structure': {
     language: 'cue',
     code: `#PlatformConfig: {
     name: "linux" | "macos" | "windows"
+    arch?: [...("amd64" | "arm64" | "386" | "arm" | "riscv64" | "ppc64le" | "s390x")]
     virtual?: {
         filesystem?: {
             access?: "restricted" | "full"
@@ -929,8 +1308,9 @@ containerfile: "./docker/Dockerfile.build"`,
   'reference/invowkfile/env-config-structure': {
     language: 'cue',
     code: `#EnvConfig: {
-    files?: [...string]         // Dotenv files to load
-    vars?:  [string]: string    // Environment variables
+    files?:   [...string]              // Dotenv files to load
+    vars?:    [string]: string         // Environment variables
+    secrets?: [string]: #SecretSource  // Secrets resolved at execution time
 }`,
   },
 
@@ -955,6 +1335,16 @@ containerfile: "./docker/Dockerfile.build"`,
 }`,
   },
 
+  'reference/invowkfile/env-secrets-example': {
+    language: 'cue',
+    code: `env: {
+    secrets: {
+        API_TOKEN: {provider: "command", command: ["pass", "show", "api/token"]}
+        DB_PASSWORD: {provider: "file", path: ".secrets/db"}
+    }
+}`,
+  },
+
   'reference/invowkfile/depends-on-structure': {
     language: 'cue',
     code: `#DependsOn: {
@@ -992,6 +1382,7 @@ containerfile: "./docker/Dockerfile.build"`,
 
 #CommandDependency: {
     alternatives: [...#CommandDependencyRef] & [_, ...]
+    run?:         bool  // Execute the first discoverable alternative first
 }`,
   },
 
@@ -1056,10 +1447,17 @@ containerfile: "./docker/Dockerfile.build"`,
     name:          string    // POSIX-compliant name
     description:   string    // Help text
     default_value?: string   // Default value
-    type?:         "string" | "bool" | "int" | "float"
+    type?:         "string" | "bool" | "int" | "float" | "duration" | "path" | "list" | "json"
     required?:     bool
     short?:        string    // Single character alias
     validation?:   string    // Regex pattern
+    choices?:      [...string] // Allowed values
+    must_exist?:   bool      // path: must exist
+    kind?:         "file" | "dir" // path: required kind
+    list_format?:  "lines" | "json" // list: INVOWK_FLAG_* encoding
+    schema?:       string    // json: CUE constraint
+    prompt?:       #PromptConfig // Ask when the required flag is missing
+    complete?:     #CompletionConfig // Dynamic shell completion
 }`,
   },
 
@@ -1088,12 +1486,39 @@ containerfile: "./docker/Dockerfile.build"`,
     description:   string    // Help text
     required?:     bool      // Must be provided
     default_value?: string   // Default if not provided
-    type?:         "string" | "int" | "float"
+    type?:         "string" | "int" | "float" | "duration" | "path" | "json"
     validation?:   string    // Regex pattern
+    choices?:      [...string] // Allowed values
+    must_exist?:   bool      // path: must exist
+    kind?:         "file" | "dir" // path: required kind
+    schema?:       string    // json: CUE constraint
+    prompt?:       #PromptConfig // Ask when the required argument is missing
+    complete?:     #CompletionConfig // Dynamic shell completion
     variadic?:     bool      // Accepts multiple values (last arg only)
 }`,
   },
 
+  'reference/invowkfile/prompt-config-structure': {
+    language: 'cue',
+    code: `#PromptConfig: {
+    type:         "input" | "choose" | "confirm" | "write" | "filter" | "file"
+    message:      string      // Question shown to the user
+    options?:     [...string] // choose/filter: offered values (default: choices)
+    placeholder?: string      // input/write: hint text
+    char_limit?:  int         // input: maximum answer length
+    extensions?:  [...string] // file: allowed extensions (e.g., ".yaml")
+}`,
+  },
+
+  'reference/invowkfile/completion-config-structure': {
+    language: 'cue',
+    code: `#CompletionConfig: {
+    script:   #ImplementationScript // Prints one candidate per line
+    runtime?: "native" | "virtual-sh" | "virtual-lua" | "virtual-starlark" // Default: native
+    timeout?: #DurationString       // Default: "2s"
+}`,
+  },
+
   'reference/invowkfile/argument-example': {
     language: 'cue',
     code: `args: [
@@ -1190,6 +1615,25 @@ cmds: [
 ]`,
   },
 
+  'reference/invowkfile/retry-example': {
+    language: 'cue',
+    code: `{
+    name: "fetch-deps"
+    implementations: [{
+        script: {content: "echo \\"attempt $INVOWK_ATTEMPT\\" && go mod download"}
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+        timeout: "10m"
+        retry: {
+            attempts: 4
+            backoff: "2s"       // 2s, 4s, 8s between attempts
+            max_backoff: "30s"
+            on_exit_codes: [1]  // only retry exit code 1
+        }
+    }]
+}`,
+  },
+
   'reference/invowkfile/timeout-example': {
     language: 'cue',
     code: `{
@@ -1238,4 +1682,41 @@ cmds: [
     }]
 }`,
   },
+
+  'reference/invowkfile/incremental-example': {
+    language: 'cue',
+    code: `{
+    name: "build"
+    description: "Build the binary (skipped when nothing changed)"
+    sources: ["go.mod", "go.sum", "**/*.go"]
+    generates: ["bin/app"]
+    implementations: [{
+        script: {content: "go build -o bin/app ./cmd/app"}
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
+}`,
+  },
+
+  'reference/invowkfile/hooks-example': {
+    language: 'cue',
+    code: `// Root-level hooks run around every command in this invowkfile
+hooks: {
+    finally: [{script: {content: "echo done: $INVOWK_CMD_NAME"}}]
+}
+
+cmds: [{
+    name: "integration-test"
+    hooks: {
+        before:  [{script: {content: "docker compose up -d"}}]
+        after:   [{script: {content: "echo 'all tests passed'"}, runtime: "virtual-sh"}]
+        finally: [{script: {content: "docker compose down"}}]
+    }
+    implementations: [{
+        script: {content: "go test -tags=integration ./..."}
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
+}]`,
+  },
 } satisfies Record<string, Snippet>;
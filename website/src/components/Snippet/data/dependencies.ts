@@ -81,8 +81,20 @@ export const dependenciesSnippets = {
   },
 
   'dependencies/commands-workflow': {
-    language: 'bash',
-    code: `invowk cmd build && invowk cmd deploy`,
+    language: 'cue',
+    code: `{
+    name: "deploy"
+    depends_on: {
+        cmds: [
+            // Executed before deploy; shared prerequisites run once
+            {alternatives: ["build"], run: true},
+            {alternatives: ["test"], run: true},
+            // Discoverability check only
+            {alternatives: ["@tools notify"]},
+        ]
+    }
+    implementations: [...]
+}`,
   },
 
   'dependencies/capabilities-basic': {
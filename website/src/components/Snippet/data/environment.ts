@@ -900,4 +900,54 @@ invowk cmd build --ivk-env-var DEBUG=true --ivk-env-var LOG_LEVEL=debug`,
   --ivk-env-inherit-allow LANG 
   --ivk-env-inherit-deny AWS_SECRET_ACCESS_KEY`,
   },
+
+  'environment/secrets-basic': {
+    language: 'cue',
+    code: `cmds: [{
+    name: "deploy"
+    env: {
+        vars: {
+            REGISTRY: "ghcr.io/acme"
+        }
+        secrets: {
+            REGISTRY_TOKEN: {provider: "command", command: ["pass", "show", "acme/registry"]}
+        }
+    }
+    implementations: [{
+        script: """
+            echo "$REGISTRY_TOKEN" | docker login "$REGISTRY" --password-stdin
+            """
+        runtimes: [{name: "native"}]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
+}]`,
+  },
+
+  'environment/secrets-providers': {
+    language: 'cue',
+    code: `env: {
+    secrets: {
+        // Stdout of an external command
+        NPM_TOKEN: {provider: "command", command: ["op", "read", "op://dev/npm/token"]}
+        // File contents, relative to the invowkfile directory
+        DB_PASSWORD: {provider: "file", path: ".secrets/db-password"}
+        // Host environment variable (e.g., injected by CI)
+        GITHUB_TOKEN: {provider: "env", var: "CI_GITHUB_TOKEN"}
+        // OS keyring (secret-tool on Linux, security on macOS)
+        SIGNING_KEY: {provider: "keyring", service: "acme-signing", account: "release"}
+    }
+}`,
+  },
+
+  'environment/secrets-masked-output': {
+    language: 'bash',
+    code: `$ invowk cmd deploy
+Login Succeeded
+Using registry token ******** for ghcr.io/acme
+
+$ invowk cmd deploy --ivk-dry-run
+...
+  Secrets (resolved at execution time):
+    REGISTRY_TOKEN (from command)`,
+  },
 } satisfies Record<string, Snippet>;
@@ -252,6 +252,76 @@ invowk cmd run --count=-1  # Negative allowed`,
 invowk cmd run --threshold=1.5e-3  # Scientific notation`,
   },
 
+  'flags-args/flags-type-duration': {
+    language: 'cue',
+    code: `{name: "wait", description: "How long to wait", type: "duration", default_value: "30s"}`,
+  },
+
+  'flags-args/flags-type-duration-usage': {
+    language: 'bash',
+    code: `invowk cmd run --wait=5m
+invowk cmd run --wait=1h30m
+invowk cmd run --wait=soon  # Error: must be a valid positive duration`,
+  },
+
+  'flags-args/flags-type-path': {
+    language: 'cue',
+    code: `{
+    name: "config"
+    description: "Config file"
+    type: "path"
+    must_exist: true  // The path must exist
+    kind: "file"      // "file" or "dir"
+}`,
+  },
+
+  'flags-args/flags-type-path-usage': {
+    language: 'bash',
+    code: `# Relative paths are resolved against the invowkfile directory
+invowk cmd deploy --config=conf/prod.yaml
+# INVOWK_FLAG_CONFIG=/path/to/project/conf/prod.yaml`,
+  },
+
+  'flags-args/flags-type-list': {
+    language: 'cue',
+    code: `{
+    name: "tag"
+    description: "Image tags"
+    type: "list"
+    default_value: "[\\"latest\\"]"  // JSON array of strings
+    list_format: "lines"           // "lines" (default) or "json"
+}`,
+  },
+
+  'flags-args/flags-type-list-usage': {
+    language: 'bash',
+    code: `invowk cmd build --tag=v1.2.0 --tag=stable
+
+# lines: one value per line
+while IFS= read -r tag; do
+    docker tag app "app:$tag"
+done <<< "$INVOWK_FLAG_TAG"
+
+# json: INVOWK_FLAG_TAG='["v1.2.0","stable"]'`,
+  },
+
+  'flags-args/flags-type-json': {
+    language: 'cue',
+    code: `{
+    name: "settings"
+    description: "Deployment settings"
+    type: "json"
+    // CUE constraint the JSON value must satisfy (optional)
+    schema: "{replicas: int & >0, region!: string}"
+}`,
+  },
+
+  'flags-args/flags-type-json-usage': {
+    language: 'bash',
+    code: `invowk cmd deploy --settings='{"replicas": 3, "region": "eu-west"}'
+invowk cmd deploy --settings='{"replicas": 0}'  # Error: does not satisfy schema`,
+  },
+
   'flags-args/flags-required': {
     language: 'cue',
     code: `{
@@ -338,6 +408,79 @@ invowk cmd deploy --env=production
 # Error: flag 'env' value 'production' does not match required pattern '^(dev|staging|prod)$'`,
   },
 
+  'flags-args/flags-choices': {
+    language: 'cue',
+    code: `flags: [
+    {
+        name: "region"
+        description: "Target region"
+        choices: ["eu-west", "us-east", "ap-south"]
+        default_value: "eu-west"
+    }
+]`,
+  },
+
+  'flags-args/flags-prompt': {
+    language: 'cue',
+    code: `flags: [
+    {
+        name: "force"
+        description: "Skip safety checks"
+        type: "bool"
+        required: true
+        prompt: {type: "confirm", message: "Skip safety checks?"}
+    },
+    {
+        name: "region"
+        description: "Target region"
+        required: true
+        choices: ["eu-west", "us-east"]
+        prompt: {type: "choose", message: "Deploy to which region?"}
+    }
+]`,
+  },
+
+  'flags-args/flags-complete': {
+    language: 'cue',
+    code: `flags: [
+    {
+        name: "namespace"
+        description: "Kubernetes namespace"
+        complete: {
+            script: {content: "kubectl get namespaces -o name | cut -d/ -f2"}
+            timeout: "3s"
+        }
+    },
+    {
+        name: "branch"
+        description: "Branch to deploy"
+        complete: {
+            script: {content: "git for-each-ref --format='%(refname:short)' refs/heads"}
+            runtime: "virtual-sh"
+        }
+    }
+]`,
+  },
+
+  'flags-args/flags-groups': {
+    language: 'cue',
+    code: `{
+    name: "push"
+    flags: [
+        {name: "tag", description: "Image tag to push"},
+        {name: "latest", description: "Push the latest tag", type: "bool"},
+        {name: "user", description: "Registry user"},
+        {name: "password-file", description: "File holding the registry password", type: "path"},
+    ]
+    flag_groups: {
+        mutually_exclusive: [["tag", "latest"]]
+        required_together: [["user", "password-file"]]
+        one_required: [["tag", "latest"]]
+    }
+    implementations: [...]
+}`,
+  },
+
   'flags-args/flags-accessing': {
     language: 'cue',
     code: `{
@@ -517,6 +660,21 @@ invowk cmd deploy --env=production
     code: `invowk cmd scale 0.5`,
   },
 
+  'flags-args/args-type-path': {
+    language: 'cue',
+    code: `args: [
+    {name: "source", description: "Directory to archive", type: "path", must_exist: true, kind: "dir"},
+    {name: "timeout", description: "Upload timeout", type: "duration", default_value: "5m"},
+    {name: "metadata", description: "Archive metadata", type: "json", schema: "{[string]: string}", default_value: "{}"},
+]`,
+  },
+
+  'flags-args/args-type-path-usage': {
+    language: 'bash',
+    code: `invowk cmd archive ./build 10m '{"owner": "platform"}'
+# INVOWK_ARG_SOURCE is resolved against the invowkfile directory`,
+  },
+
   'flags-args/args-required': {
     language: 'cue',
     code: `args: [
@@ -631,6 +789,60 @@ invowk cmd deploy production
 # Error: argument 'environment' value 'production' does not match pattern '^(dev|staging|prod)$'`,
   },
 
+  'flags-args/args-choices': {
+    language: 'cue',
+    code: `args: [
+    {
+        name: "environment"
+        description: "Target environment"
+        required: true
+        choices: ["dev", "staging", "prod"]
+    },
+    {
+        name: "services"
+        description: "Services to restart"
+        variadic: true
+        choices: ["api", "web", "worker"]
+    }
+]`,
+  },
+
+  'flags-args/args-prompt': {
+    language: 'cue',
+    code: `args: [
+    {
+        name: "environment"
+        description: "Target environment"
+        required: true
+        choices: ["dev", "staging", "prod"]
+        prompt: {type: "filter", message: "Deploy where?"}
+    },
+    {
+        name: "manifest"
+        description: "Deployment manifest"
+        required: true
+        type: "path"
+        kind: "file"
+        prompt: {type: "file", message: "Pick a manifest", extensions: [".yaml", ".yml"]}
+    }
+]`,
+  },
+
+  'flags-args/args-complete': {
+    language: 'cue',
+    code: `{
+    name: "logs"
+    flags: [{name: "namespace", description: "Kubernetes namespace", default_value: "default"}]
+    args: [{
+        name: "pod"
+        description: "Pod to follow"
+        required: true
+        complete: {script: {content: "kubectl get pods -n $INVOWK_FLAG_NAMESPACE -o name | cut -d/ -f2"}}
+    }]
+    implementations: [...]
+}`,
+  },
+
   'flags-args/args-accessing': {
     language: 'cue',
     code: `{
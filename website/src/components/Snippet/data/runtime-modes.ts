@@ -231,6 +231,30 @@ cmds: [
 }`,
   },
 
+  'runtime-modes/native-sandbox': {
+    language: 'cue',
+    code: `{
+    name: "test"
+    implementations: [{
+        script: {content: "./scripts/test.sh > \\"$INVOWK_PATH_REPORTS/test.log\\""}
+        runtimes: [{
+            name: "native"
+            sandbox: {network: "none"}
+        }]
+        platforms: [{
+            name: "linux"
+            virtual: {
+                filesystem: {
+                    paths: {
+                        REPORTS: "@cache/reports"
+                    }
+                }
+            }
+        }]
+    }]
+}`,
+  },
+
   'runtime-modes/virtual-basic': {
     language: 'cue',
     code: `{
@@ -552,6 +576,59 @@ virtual: {
 }`,
   },
 
+  'runtime-modes/virtual-starlark-basic': {
+    language: 'cue',
+    code: `{
+    name: "hello-starlark"
+    implementations: [{
+        script: {content: """
+            print("Hello from virtual-starlark")
+            print("workdir: " + invowk.path("@work"))
+            """}
+        runtimes: [{name: "virtual-starlark"}]
+        platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]
+    }]
+}`,
+  },
+
+  'runtime-modes/virtual-starlark-bridge': {
+    language: 'cue',
+    code: `script: {content: """
+    out, err, code = invowk.capture.basename("src/main.go")
+    if code != 0:
+        fail(err)
+    print("file: " + out.strip())
+
+    for tag in invowk.env.get("INVOWK_FLAG_TAG", ()):
+        invowk.cmd("mkdir", "-p", "dist/" + tag)
+    """}`,
+  },
+
+  'runtime-modes/virtual-starlark-load': {
+    language: 'cue',
+    code: `// helpers/versions.star (next to the invowkfile):
+//
+//   def bump(version):
+//       major, minor, patch = version.split(".")
+//       return "%s.%s.%d" % (major, minor, int(patch) + 1)
+
+{
+    name: "next-version"
+    outputs: [{name: "version"}]
+    implementations: [{
+        script: {content: """
+            load("helpers/versions.star", "bump")
+            invowk.output("version", bump(invowk.args[0]))
+            """}
+        runtimes: [{
+            name: "virtual-starlark"
+            max_steps: 100000
+        }]
+        platforms: [{name: "linux"}, {name: "macos"}, {name: "windows"}]
+    }]
+}`,
+  },
+
   'runtime-modes/container-basic': {
     language: 'cue',
     code: `{
@@ -972,6 +1049,44 @@ container_engine: "podman"  // or "docker"`,
             image: "debian:stable-slim"
         }]
     }]
+}`,
+  },
+
+  'runtime-modes/remote-ssh-basic': {
+    language: 'cue',
+    code: `{
+    name: "deploy"
+    implementations: [{
+        script: {content: """
+            git pull --ff-only
+            ./restart.sh "$@"
+            """}
+        runtimes: [{
+            name:    "remote-ssh"
+            host:    "app-01.example.com"
+            user:    "deploy"
+            workdir: "/srv/app"
+        }]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
+}`,
+  },
+
+  'runtime-modes/remote-ssh-env': {
+    language: 'cue',
+    code: `{
+    name: "migrate"
+    env: {vars: {RAILS_ENV: "production"}}
+    implementations: [{
+        script: {content: "bin/rails db:migrate"}
+        runtimes: [{
+            name:              "remote-ssh"
+            host:              "db-admin"            // alias from ~/.ssh/config
+            env_inherit_mode:  "allow"
+            env_inherit_allow: ["DATABASE_URL"]      // forwarded from the local shell
+        }]
+        platforms: [{name: "linux"}, {name: "macos"}]
+    }]
 }`,
   },
 } satisfies Record<string, Snippet>;